@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const alertSeverityWarning = "warning"
+
+// GetAlertRules generates the prometheus alerting rules of the namespace as yaml, for the certificates expiring,
+// the quotas near their limits and the nodes offline. The thresholds are taken from the config,
+// the current quota limits and the offline threshold of the namespace
+func (api *API) GetAlertRules(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	limits, err := api.Quota.GetQuota(ns)
+	if err != nil {
+		return nil, err
+	}
+	rules := generateAlertRules(api.alertRules, ns, limits, api.Offline.Threshold(ns))
+	data, err := yaml.Marshal(rules)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-rules.yaml", ns))
+	return data, nil
+}
+
+// generateAlertRules builds the rules of the namespace, a rule is generated for each quota with a limit
+func generateAlertRules(r config.AlertRules, ns string, limits map[string]int, offline time.Duration) *models.AlertRuleGroups {
+	forDur := promDuration(r.For)
+	labels := map[string]string{"severity": alertSeverityWarning, "namespace": ns}
+	var rules []models.AlertRule
+	if r.CertExpiringWithin > 0 {
+		rules = append(rules, models.AlertRule{
+			Alert:  "BaetylCertificateExpiring",
+			Expr:   fmt.Sprintf(`%s{namespace="%s"} - time() < %d`, r.CertExpiryMetric, ns, int64(r.CertExpiringWithin.Seconds())),
+			For:    forDur,
+			Labels: labels,
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("The certificate {{ $labels.name }} of the namespace %s expires within %s", ns, promDuration(r.CertExpiringWithin)),
+			},
+		})
+	}
+	if r.QuotaPercent > 0 {
+		var names []string
+		for name, limit := range limits {
+			if limit > 0 {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			limit := limits[name]
+			// the least usage reaching the percent of the limit
+			threshold := (limit*r.QuotaPercent + 99) / 100
+			rules = append(rules, models.AlertRule{
+				Alert:  "BaetylQuotaNearLimit",
+				Expr:   fmt.Sprintf(`%s{namespace="%s",quota="%s"} >= %d`, r.QuotaUsedMetric, ns, name, threshold),
+				For:    forDur,
+				Labels: labels,
+				Annotations: map[string]string{
+					"summary": fmt.Sprintf("The quota %s of the namespace %s is used up to %d%% of the limit %d", name, ns, r.QuotaPercent, limit),
+				},
+			})
+		}
+	}
+	if offline > 0 {
+		rules = append(rules, models.AlertRule{
+			Alert:  "BaetylNodeOffline",
+			Expr:   fmt.Sprintf(`time() - %s{namespace="%s"} > %d`, r.NodeReportMetric, ns, int64(offline.Seconds())),
+			For:    forDur,
+			Labels: labels,
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("The node {{ $labels.name }} of the namespace %s has not reported for %s", ns, promDuration(offline)),
+			},
+		})
+	}
+	return &models.AlertRuleGroups{Groups: []models.AlertRuleGroup{{Name: "baetyl-" + ns, Rules: rules}}}
+}
+
+// promDuration formats the duration in the largest unit of prometheus dividing it, such as 30d and 90s
+func promDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	for _, u := range []struct {
+		unit string
+		dur  time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}, {"s", time.Second}} {
+		if d%u.dur == 0 {
+			return fmt.Sprintf("%d%s", d/u.dur, u.unit)
+		}
+	}
+	return fmt.Sprintf("%dms", d/time.Millisecond)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestGetAlertRules(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sQuota := ms.NewMockQuotaService(mockCtl)
+	sOffline := ms.NewMockNodeOfflineService(mockCtl)
+	cfg := config.AlertRules{
+		CertExpiringWithin: 720 * time.Hour,
+		QuotaPercent:       90,
+		For:                5 * time.Minute,
+		CertExpiryMetric:   "cert_expiry",
+		QuotaUsedMetric:    "quota_used",
+		NodeReportMetric:   "node_report",
+	}
+	api := &API{Quota: sQuota, Offline: sOffline, alertRules: cfg}
+
+	router := gin.New()
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	router.GET("/v1/alerts/rules", mockIM, common.WrapperRaw(api.GetAlertRules, true))
+
+	sQuota.EXPECT().GetQuota("default").Return(map[string]int{"maxNodeCount": 10, "maxAppCount": 15, "maxConfigCount": 0}, nil).Times(1)
+	sOffline.EXPECT().Threshold("default").Return(90 * time.Second).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/alerts/rules", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "attachment; filename=default-rules.yaml", w.Header().Get("Content-Disposition"))
+
+	var res models.AlertRuleGroups
+	assert.NoError(t, yaml.Unmarshal(w.Body.Bytes(), &res))
+	assert.Len(t, res.Groups, 1)
+	assert.Equal(t, "baetyl-default", res.Groups[0].Name)
+	rules := res.Groups[0].Rules
+	assert.Len(t, rules, 4)
+	assert.Equal(t, "BaetylCertificateExpiring", rules[0].Alert)
+	assert.Equal(t, `cert_expiry{namespace="default"} - time() < 2592000`, rules[0].Expr)
+	assert.Equal(t, "5m", rules[0].For)
+	assert.Equal(t, map[string]string{"severity": "warning", "namespace": "default"}, rules[0].Labels)
+	assert.Contains(t, rules[0].Annotations["summary"], "expires within 30d")
+	assert.Equal(t, `quota_used{namespace="default",quota="maxAppCount"} >= 14`, rules[1].Expr)
+	assert.Equal(t, `quota_used{namespace="default",quota="maxNodeCount"} >= 9`, rules[2].Expr)
+	assert.Equal(t, "BaetylNodeOffline", rules[3].Alert)
+	assert.Equal(t, `time() - node_report{namespace="default"} > 90`, rules[3].Expr)
+
+	// the rules disabled are not generated
+	cfg.CertExpiringWithin, cfg.QuotaPercent = 0, 0
+	groups := generateAlertRules(cfg, "default", map[string]int{"maxNodeCount": 10}, 0)
+	assert.Empty(t, groups.Groups[0].Rules)
+}
+
+func TestPromDuration(t *testing.T) {
+	assert.Equal(t, "", promDuration(0))
+	assert.Equal(t, "30d", promDuration(720*time.Hour))
+	assert.Equal(t, "25h", promDuration(25*time.Hour))
+	assert.Equal(t, "90s", promDuration(90*time.Second))
+	assert.Equal(t, "1500ms", promDuration(1500*time.Millisecond))
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/baetyl/baetyl-go/v2/log"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
@@ -11,28 +13,73 @@ import (
 
 // API baetyl api server
 type API struct {
-	Hooks    map[string]interface{}
-	NS       service.NamespaceService
-	Node     service.NodeService
-	Index    service.IndexService
-	Func     service.FunctionService
-	Obj      service.ObjectService
-	PKI      service.PKIService
-	Auth     service.AuthService
-	Prop     service.PropertyService
-	Module   service.ModuleService
-	Init     service.InitService
-	License  service.LicenseService
-	Quota    service.QuotaService
-	Template service.TemplateService
-	Task     service.TaskService
-	Locker   service.LockerService
-	SysApp   service.SystemAppService
-	Sign     service.SignService
-	Wrapper  service.WrapperService
-	Facade   facade.Facade
+	Hooks     map[string]interface{}
+	NS        service.NamespaceService
+	Node      service.NodeService
+	NodeGroup service.NodeGroupService
+	AppTpl    service.AppTemplateService
+	Index     service.IndexService
+	Func      service.FunctionService
+	Obj       service.ObjectService
+	PKI       service.PKIService
+	Auth      service.AuthService
+	Prop      service.PropertyService
+	Module    service.ModuleService
+	Init      service.InitService
+	License   service.LicenseService
+	Quota     service.QuotaService
+	Template  service.TemplateService
+	Task      service.TaskService
+	Locker    service.LockerService
+	SysApp    service.SystemAppService
+	Sign      service.SignService
+	Wrapper   service.WrapperService
+	Facade    facade.Facade
+	Audit     service.AuditService
+	Offline   service.NodeOfflineService
+	Webhook   service.WebhookService
+	NodeCmd   service.NodeCommandService
+	AppSched  service.AppScheduleService
+	Registry  service.RegistryService
+	Job       service.JobService
+	// NodeLog streams the logs of the apps on the nodes, it is nil if the plugin nodeLog is not set
+	NodeLog service.NodeLogService
 	*service.AppCombinedService
 	log *log.Logger
+	// certRotationOverlap how long the replaced certificate keeps valid after rotation
+	certRotationOverlap time.Duration
+	// certReapInterval how often the deprecated certificates are deleted once their overlap ends
+	certReapInterval  time.Duration
+	nodeStatsWatchers *nodeStatsWatchers
+	nodeLogStreams    *namespaceLimiter
+	nodeLogMaxTail    int
+	// appTrashRetention how long the deleted apps are kept in the trash
+	appTrashRetention    time.Duration
+	appTrashReapInterval time.Duration
+	// appScheduleInterval how often the due deploys of the apps are checked
+	appScheduleInterval time.Duration
+	// registryRefresh the retry of the transient failures of refreshing the registry passwords
+	registryRefresh config.Retry
+	// registryAllowPrivate whether the registries on the private networks are verified and inspected
+	registryAllowPrivate bool
+	// appCapacityCheck how to handle the apps exceeding the capacity of their target nodes
+	appCapacityCheck string
+	// appSelectorConfirmThreshold the apps matching more nodes than it need to be confirmed
+	appSelectorConfirmThreshold int
+	// appPortConflictCheck whether the host ports of the apps are checked against the other apps on the same nodes
+	appPortConflictCheck bool
+	// objectURLMaxExpiration the max expiry of the signed urls of the objects
+	objectURLMaxExpiration time.Duration
+	// csrPolicy the limits of the certificate signing requests of the clients
+	csrPolicy config.CSRPolicy
+	// alertRules the thresholds of the alerting rules generated for the namespaces
+	alertRules config.AlertRules
+	// passwordPolicy the policy of the passwords of the registries and the secrets
+	passwordPolicy config.PasswordPolicy
+	// licenseMode how to handle the requests exceeding the license limits
+	licenseMode string
+	// nodeMaintenanceExcludeQuota the quota of the nodes under maintenance is released
+	nodeMaintenanceExcludeQuota bool
 }
 
 // NewAPI new api
@@ -49,6 +96,45 @@ func NewAPI(config *config.CloudConfig) (*API, error) {
 	if err != nil {
 		return nil, err
 	}
+	nodeOfflineService, err := service.NewNodeOfflineService(config)
+	if err != nil {
+		return nil, err
+	}
+	webhookService, err := service.NewWebhookService(config)
+	if err != nil {
+		return nil, err
+	}
+	nodeCommandService, err := service.NewNodeCommandService(config)
+	if err != nil {
+		return nil, err
+	}
+	appScheduleService, err := service.NewAppScheduleService(config)
+	if err != nil {
+		return nil, err
+	}
+	registryService, err := service.NewRegistryService(config)
+	if err != nil {
+		return nil, err
+	}
+	jobService, err := service.NewJobService(config)
+	if err != nil {
+		return nil, err
+	}
+	var nodeLogService service.NodeLogService
+	if config.Plugin.NodeLog != "" {
+		nodeLogService, err = service.NewNodeLogService(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	nodeGroupService, err := service.NewNodeGroupService(config)
+	if err != nil {
+		return nil, err
+	}
+	appTemplateService, err := service.NewAppTemplateService(config)
+	if err != nil {
+		return nil, err
+	}
 	indexService, err := service.NewIndexService(config)
 	if err != nil {
 		return nil, err
@@ -122,27 +208,61 @@ func NewAPI(config *config.CloudConfig) (*API, error) {
 	if err != nil {
 		return nil, err
 	}
+	auditService, err := service.NewAuditService(config)
+	if err != nil {
+		return nil, err
+	}
 	return &API{
-		NS:                 namespaceService,
-		Node:               nodeService,
-		Index:              indexService,
-		Obj:                objectService,
-		Func:               functionService,
-		PKI:                pkiService,
-		Auth:               authService,
-		Sign:               signService,
-		Prop:               propertyService,
-		Module:             moduleService,
-		Init:               initService,
-		License:            licenseService,
-		Quota:              quotaService,
-		Template:           templateService,
-		Task:               taskService,
-		Locker:             lockerService,
-		SysApp:             sysApp,
-		Wrapper:            wrapper,
-		AppCombinedService: acs,
-		Facade:             appFacade,
-		log:                log.L().With(log.Any("api", "admin")),
+		NS:                  namespaceService,
+		Node:                nodeService,
+		Offline:             nodeOfflineService,
+		Webhook:             webhookService,
+		NodeCmd:             nodeCommandService,
+		AppSched:            appScheduleService,
+		Registry:            registryService,
+		Job:                 jobService,
+		NodeLog:             nodeLogService,
+		NodeGroup:           nodeGroupService,
+		AppTpl:              appTemplateService,
+		Audit:               auditService,
+		Index:               indexService,
+		Obj:                 objectService,
+		Func:                functionService,
+		PKI:                 pkiService,
+		Auth:                authService,
+		Sign:                signService,
+		Prop:                propertyService,
+		Module:              moduleService,
+		Init:                initService,
+		License:             licenseService,
+		Quota:               quotaService,
+		Template:            templateService,
+		Task:                taskService,
+		Locker:              lockerService,
+		SysApp:              sysApp,
+		Wrapper:             wrapper,
+		AppCombinedService:  acs,
+		Facade:              appFacade,
+		log:                 log.L().With(log.Any("api", "admin")),
+		certRotationOverlap: config.Certificate.RotationOverlap,
+		certReapInterval:    config.Certificate.ReapInterval,
+		csrPolicy:           config.Certificate.CSR,
+		alertRules:          config.AlertRules,
+		passwordPolicy:      config.PasswordPolicy,
+		nodeStatsWatchers: newNodeStatsWatchers(config.NodeWatch.MaxWatchers,
+			config.NodeWatch.Interval, config.NodeWatch.Heartbeat),
+		nodeLogStreams:              newNamespaceLimiter(config.NodeLog.MaxStreams),
+		nodeLogMaxTail:              config.NodeLog.MaxTail,
+		appTrashRetention:           config.AppTrash.Retention,
+		appTrashReapInterval:        config.AppTrash.ReapInterval,
+		appScheduleInterval:         config.AppSchedule.Interval,
+		registryRefresh:             config.RegistryRefresh,
+		registryAllowPrivate:        config.Registry.AllowPrivateNetwork,
+		appCapacityCheck:            config.AppCapacity.Check,
+		objectURLMaxExpiration:      config.Object.MaxURLExpiration,
+		appSelectorConfirmThreshold: config.AppSelector.ConfirmThreshold,
+		appPortConflictCheck:        config.AppPortConflict.Check,
+		licenseMode:                 config.License.Mode,
+		nodeMaintenanceExcludeQuota: config.NodeMaintenance.ExcludeQuota,
 	}, nil
 }
@@ -27,6 +27,15 @@ func TestNewAdminAPI(t *testing.T) {
 	c.Plugin.Record = common.RandString(9)
 	c.Plugin.Callback = common.RandString(9)
 	c.Plugin.AppHistory = common.RandString(9)
+	c.Plugin.NodeGroup = common.RandString(9)
+	c.Plugin.AppTpl = common.RandString(9)
+	c.Plugin.AuditSink = common.RandString(9)
+	c.Plugin.Webhook = common.RandString(9)
+	c.Plugin.NodeCmd = common.RandString(9)
+	c.Plugin.AppSched = common.RandString(9)
+	c.Plugin.RegRefresh = common.RandString(9)
+	c.Plugin.Job = common.RandString(9)
+	c.Plugin.EventSink = common.RandString(9)
 	c.Plugin.Objects = []string{common.RandString(9), common.RandString(9)}
 	c.Plugin.Functions = []string{common.RandString(9), common.RandString(9)}
 	c.Plugin.Property = common.RandString(9)
@@ -77,6 +86,48 @@ func TestNewAdminAPI(t *testing.T) {
 	plugin.RegisterFactory(c.Plugin.Index, func() (plugin.Plugin, error) {
 		return mockIndex, nil
 	})
+
+	mockAppHis := mockPlugin.NewMockAppHistory(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppHistory, func() (plugin.Plugin, error) {
+		return mockAppHis, nil
+	})
+	mockNodeGroup := mockPlugin.NewMockNodeGroup(mockCtl)
+	plugin.RegisterFactory(c.Plugin.NodeGroup, func() (plugin.Plugin, error) {
+		return mockNodeGroup, nil
+	})
+	mockAppTemplate := mockPlugin.NewMockAppTemplate(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppTpl, func() (plugin.Plugin, error) {
+		return mockAppTemplate, nil
+	})
+
+	mockWebhook := mockPlugin.NewMockWebhook(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Webhook, func() (plugin.Plugin, error) {
+		return mockWebhook, nil
+	})
+	mockNodeCmd := mockPlugin.NewMockNodeCommand(mockCtl)
+	plugin.RegisterFactory(c.Plugin.NodeCmd, func() (plugin.Plugin, error) {
+		return mockNodeCmd, nil
+	})
+	mockAppSched := mockPlugin.NewMockAppSchedule(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppSched, func() (plugin.Plugin, error) {
+		return mockAppSched, nil
+	})
+	mockRegRefresh := mockPlugin.NewMockRegistryRefresh(mockCtl)
+	plugin.RegisterFactory(c.Plugin.RegRefresh, func() (plugin.Plugin, error) {
+		return mockRegRefresh, nil
+	})
+	mockJob := mockPlugin.NewMockJob(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Job, func() (plugin.Plugin, error) {
+		return mockJob, nil
+	})
+	mockAuditSink := mockPlugin.NewMockAuditSink(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AuditSink, func() (plugin.Plugin, error) {
+		return mockAuditSink, nil
+	})
+	mockEventSink := mockPlugin.NewMockEventSink(mockCtl)
+	plugin.RegisterFactory(c.Plugin.EventSink, func() (plugin.Plugin, error) {
+		return mockEventSink, nil
+	})
 	mockProperty := mockPlugin.NewMockProperty(mockCtl)
 	plugin.RegisterFactory(c.Plugin.Property, func() (plugin.Plugin, error) {
 		return mockProperty, nil
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	AppCapacityCheckOff   = "off"
+	AppCapacityCheckWarn  = "warn"
+	AppCapacityCheckError = "error"
+)
+
+var capacityResources = []string{string(coreV1.ResourceCPU), string(coreV1.ResourceMemory)}
+
+// checkAppCapacity checks whether the cpu and memory requested by the app fit the capacity reported by each of its target nodes.
+// The nodes whose capacity is unknown are skipped, and the whole check is skipped with ?skipCapacityCheck=true.
+// The problems are returned as warnings, or as an error if the check is configured as error
+func (api *API) checkAppCapacity(c *common.Context, ns string, app *specV1.Application) ([]string, error) {
+	if api.appCapacityCheck != AppCapacityCheckWarn && api.appCapacityCheck != AppCapacityCheckError {
+		return nil, nil
+	}
+	if skip, _ := strconv.ParseBool(c.Query("skipCapacityCheck")); skip || app.Selector == "" {
+		return nil, nil
+	}
+	requested, err := appRequestedResources(app)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if len(requested) == 0 {
+		return nil, nil
+	}
+	nodes, err := api.Node.List(ns, &models.ListOptions{LabelSelector: app.Selector})
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		capacity := nodeCapacity(node)
+		var exceeded []string
+		for _, name := range capacityResources {
+			req, ok := requested[name]
+			if !ok {
+				continue
+			}
+			if cp, ok := capacity[name]; ok && req.Cmp(cp) > 0 {
+				exceeded = append(exceeded, fmt.Sprintf("%s: %s > %s", name, req.String(), cp.String()))
+			}
+		}
+		if len(exceeded) > 0 {
+			warnings = append(warnings, fmt.Sprintf("the resources requested by the application exceed the capacity of the node (%s) (%s)",
+				node.Name, strings.Join(exceeded, ", ")))
+		}
+	}
+	if len(warnings) > 0 && api.appCapacityCheck == AppCapacityCheckError {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", strings.Join(warnings, "; ")))
+	}
+	return warnings, nil
+}
+
+// appRequestedResources sums the cpu and memory requested by the services of all replicas,
+// the limit of a service is taken as its request if no request is set
+func appRequestedResources(app *specV1.Application) (map[string]resource.Quantity, error) {
+	res := map[string]resource.Quantity{}
+	replica := app.Replica
+	if replica < 1 || app.Workload == specV1.WorkloadDaemonSet {
+		replica = 1
+	}
+	for _, svc := range app.Services {
+		if svc.Resources == nil {
+			continue
+		}
+		for _, name := range capacityResources {
+			v, ok := svc.Resources.Requests[name]
+			if !ok {
+				if v, ok = svc.Resources.Limits[name]; !ok {
+					continue
+				}
+			}
+			q, err := resource.ParseQuantity(v)
+			if err != nil {
+				return nil, fmt.Errorf("the %s of the service (%s) is invalid: %s", name, svc.Name, err.Error())
+			}
+			total := res[name]
+			for j := 0; j < replica; j++ {
+				total.Add(q)
+			}
+			res[name] = total
+		}
+	}
+	return res, nil
+}
+
+// nodeCapacity returns the largest cpu and memory capacity reported by the edge nodes of the node,
+// the resources are missing if the capacity is unknown
+func nodeCapacity(node *specV1.Node) map[string]resource.Quantity {
+	res := map[string]resource.Quantity{}
+	if node.Report == nil {
+		return res
+	}
+	view, err := node.View(0)
+	if err != nil || view.Report == nil {
+		return res
+	}
+	for _, stats := range view.Report.NodeStats {
+		if stats == nil {
+			continue
+		}
+		for _, name := range capacityResources {
+			q, err := resource.ParseQuantity(stats.Capacity[name])
+			if err != nil || q.IsZero() {
+				continue
+			}
+			if cp, ok := res[name]; !ok || q.Cmp(cp) > 0 {
+				res[name] = q
+			}
+		}
+	}
+	return res
+}
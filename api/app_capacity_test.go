@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAppRequestedResources(t *testing.T) {
+	app := &specV1.Application{
+		Replica: 2,
+		Services: []specV1.Service{
+			{Name: "s0", Resources: &specV1.Resources{Limits: map[string]string{"cpu": "500m", "memory": "256Mi"}}},
+			{Name: "s1", Resources: &specV1.Resources{
+				Requests: map[string]string{"cpu": "250m"},
+				Limits:   map[string]string{"cpu": "1"},
+			}},
+			{Name: "s2"},
+		},
+	}
+	res, err := appRequestedResources(app)
+	assert.NoError(t, err)
+	cpu, mem := res["cpu"], res["memory"]
+	assert.Equal(t, "1500m", cpu.String())
+	assert.Equal(t, "512Mi", mem.String())
+
+	app.Workload = specV1.WorkloadDaemonSet
+	res, err = appRequestedResources(app)
+	assert.NoError(t, err)
+	cpu = res["cpu"]
+	assert.Equal(t, "750m", cpu.String())
+
+	app.Services[0].Resources.Limits["memory"] = "abc"
+	_, err = appRequestedResources(app)
+	assert.Error(t, err)
+}
+
+func TestCheckAppCapacity(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api := &API{Node: sNode, appCapacityCheck: AppCapacityCheckWarn}
+
+	newContext := func(query string) *common.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", "/v1/apps"+query, nil)
+		return common.NewContext(c)
+	}
+	app := &specV1.Application{
+		Selector: "a=b",
+		Services: []specV1.Service{
+			{Name: "s0", Resources: &specV1.Resources{Limits: map[string]string{"cpu": "2", "memory": "1Gi"}}},
+		},
+	}
+	nodes := &models.NodeList{Items: []specV1.Node{
+		{Name: "small", Report: specV1.Report{"nodestats": map[string]interface{}{
+			"h0": map[string]interface{}{"capacity": map[string]interface{}{"cpu": "1", "memory": "4Gi"}},
+		}}},
+		{Name: "large", Report: specV1.Report{"nodestats": map[string]interface{}{
+			"h0": map[string]interface{}{"capacity": map[string]interface{}{"cpu": "4", "memory": "8Gi"}},
+		}}},
+		{Name: "unknown"},
+	}}
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=b"}).Return(nodes, nil).Times(2)
+
+	c := newContext("")
+	c.SetNamespace("default")
+	warnings, err := api.checkAppCapacity(c, "default", app)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"the resources requested by the application exceed the capacity of the node (small) (cpu: 2 > 1)"}, warnings)
+
+	api.appCapacityCheck = AppCapacityCheckError
+	_, err = api.checkAppCapacity(c, "default", app)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the capacity of the node (small)")
+
+	// skipped
+	warnings, err = api.checkAppCapacity(newContext("?skipCapacityCheck=true"), "default", app)
+	assert.NoError(t, err)
+	assert.Nil(t, warnings)
+	api.appCapacityCheck = AppCapacityCheckOff
+	warnings, err = api.checkAppCapacity(c, "default", app)
+	assert.NoError(t, err)
+	assert.Nil(t, warnings)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"sort"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetAppHealth roll up the health of the instances of the application across the nodes it targets,
+// see models.AppHealth for how the health is taken from the reports of the nodes
+func (api *API) GetAppHealth(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	app, err := api.App.Get(ns, n, "")
+	if err != nil {
+		return nil, err
+	}
+	res := &models.AppHealth{
+		Name:     app.Name,
+		Version:  app.Version,
+		Selector: app.Selector,
+		Items:    []models.AppHealthNode{},
+	}
+	if app.Selector != "" {
+		nodes, err := api.Node.List(ns, &models.ListOptions{LabelSelector: app.Selector})
+		if err != nil {
+			return nil, err
+		}
+		reportTimes, err := api.Node.ListReportTime(ns)
+		if err != nil {
+			return nil, err
+		}
+		for i := range nodes.Items {
+			item := getAppHealthNode(&nodes.Items[i], app, api.Offline.Status(ns, reportTimes[nodes.Items[i].Name]))
+			switch item.Health {
+			case models.AppHealthHealthy:
+				res.Healthy++
+			case models.AppHealthUnhealthy:
+				res.Unhealthy++
+			default:
+				res.Unknown++
+			}
+			res.Items = append(res.Items, item)
+		}
+	}
+	res.Total = len(res.Items)
+	return res, nil
+}
+
+func getAppHealthNode(node *specV1.Node, app *specV1.Application, nodeStatus string) models.AppHealthNode {
+	item := models.AppHealthNode{
+		Name:       node.Name,
+		Health:     models.AppHealthUnknown,
+		NodeStatus: nodeStatus,
+		Instances:  []models.AppHealthInstance{},
+	}
+	var stats *specV1.AppStats
+	for _, s := range node.Report.AppStats(app.System) {
+		if s.Name == app.Name {
+			stats = &s
+			break
+		}
+	}
+	if stats == nil {
+		return item
+	}
+	item.Version = stats.Version
+
+	names := make([]string, 0, len(stats.InstanceStats))
+	for name := range stats.InstanceStats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	healthy := 0
+	for _, name := range names {
+		instance := getAppHealthInstance(name, stats.InstanceStats[name])
+		switch instance.Health {
+		case models.AppHealthHealthy:
+			healthy++
+		case models.AppHealthUnhealthy:
+			item.Health = models.AppHealthUnhealthy
+		}
+		item.Instances = append(item.Instances, instance)
+	}
+	// the health reported by the offline node or of another version is stale
+	if nodeStatus != models.ReadyTypeOnline || item.Version != app.Version {
+		item.Health = models.AppHealthUnknown
+		return item
+	}
+	if healthy > 0 && healthy == len(item.Instances) {
+		item.Health = models.AppHealthHealthy
+	}
+	return item
+}
+
+func getAppHealthInstance(name string, stats specV1.InstanceStats) models.AppHealthInstance {
+	instance := models.AppHealthInstance{
+		Name:    name,
+		Service: stats.ServiceName,
+		Health:  models.AppHealthUnknown,
+		Status:  stats.Status,
+		Cause:   stats.Cause,
+	}
+	switch stats.Status {
+	case specV1.Failed:
+		instance.Health = models.AppHealthUnhealthy
+		return instance
+	case specV1.Running:
+	default:
+		return instance
+	}
+
+	ready, reported := false, false
+	if ext, ok := stats.Extension.(map[string]interface{}); ok {
+		ready, reported = ext[models.AppHealthExtensionReady].(bool)
+	}
+	if !reported {
+		ready = true
+		for _, container := range stats.Containers {
+			if container.State != specV1.ContainerRunning {
+				ready = false
+				if instance.Cause == "" {
+					instance.Cause = container.Reason
+				}
+			}
+		}
+	}
+	if ready {
+		instance.Health = models.AppHealthHealthy
+	} else {
+		instance.Health = models.AppHealthUnhealthy
+	}
+	return instance
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestGetAppHealth(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	sOffline := ms.NewMockNodeOfflineService(mockCtl)
+	api := &API{AppCombinedService: &service.AppCombinedService{App: sApp}, Node: sNode, Offline: sOffline}
+
+	router := gin.New()
+	router.GET("/v1/apps/:name/health", func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }, common.Wrapper(api.GetAppHealth))
+
+	running := []specV1.ContainerInfo{{Name: "c0", State: specV1.ContainerRunning}}
+	newNode := func(name, version string, instances map[string]specV1.InstanceStats) specV1.Node {
+		node := specV1.Node{Name: name, Report: specV1.Report{}}
+		if instances != nil {
+			node.Report.SetAppStats(false, []specV1.AppStats{{AppInfo: specV1.AppInfo{Name: "a0", Version: version}, InstanceStats: instances}})
+		}
+		return node
+	}
+	app := &specV1.Application{Name: "a0", Namespace: "default", Version: "2", Selector: "a=b"}
+	nodes := &models.NodeList{Items: []specV1.Node{
+		newNode("n0", "2", map[string]specV1.InstanceStats{
+			"i1": {ServiceName: "s1", Status: specV1.Running, Containers: running},
+			"i0": {ServiceName: "s0", Status: specV1.Running, Extension: map[string]interface{}{"ready": true}},
+		}),
+		// not ready by the probes although the containers are running
+		newNode("n1", "2", map[string]specV1.InstanceStats{
+			"i0": {Status: specV1.Running, Containers: running, Extension: map[string]interface{}{"ready": false}},
+		}),
+		newNode("n2", "2", map[string]specV1.InstanceStats{
+			"i0": {Status: specV1.Running, Containers: []specV1.ContainerInfo{{Name: "c0", State: specV1.ContainerWaiting, Reason: "CrashLoopBackOff"}}},
+		}),
+		newNode("n3", "2", map[string]specV1.InstanceStats{"i0": {Status: specV1.Pending}}),
+		newNode("n4", "1", map[string]specV1.InstanceStats{"i0": {Status: specV1.Running, Containers: running}}),
+		newNode("n5", "2", map[string]specV1.InstanceStats{"i0": {Status: specV1.Running, Containers: running}}),
+		newNode("n6", "", nil),
+	}}
+	now := time.Now()
+	reportTimes := map[string]time.Time{"n0": now, "n1": now, "n2": now, "n3": now, "n4": now, "n6": now}
+	sApp.EXPECT().Get("default", "a0", "").Return(app, nil).Times(1)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=b"}).Return(nodes, nil).Times(1)
+	sNode.EXPECT().ListReportTime("default").Return(reportTimes, nil).Times(1)
+	sOffline.EXPECT().Status("default", gomock.Any()).DoAndReturn(func(_ string, reportTime time.Time) string {
+		if reportTime.IsZero() {
+			return models.ReadyTypOffline
+		}
+		return models.ReadyTypeOnline
+	}).AnyTimes()
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/a0/health", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := new(models.AppHealth)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 7, res.Total)
+	assert.Equal(t, 1, res.Healthy)
+	assert.Equal(t, 2, res.Unhealthy)
+	assert.Equal(t, 4, res.Unknown)
+
+	assert.Equal(t, models.AppHealthNode{
+		Name:       "n0",
+		Health:     models.AppHealthHealthy,
+		NodeStatus: models.ReadyTypeOnline,
+		Version:    "2",
+		Instances: []models.AppHealthInstance{
+			{Name: "i0", Service: "s0", Health: models.AppHealthHealthy, Status: specV1.Running},
+			{Name: "i1", Service: "s1", Health: models.AppHealthHealthy, Status: specV1.Running},
+		},
+	}, res.Items[0])
+	assert.Equal(t, models.AppHealthUnhealthy, res.Items[1].Health)
+	assert.Equal(t, models.AppHealthUnhealthy, res.Items[2].Health)
+	assert.Equal(t, "CrashLoopBackOff", res.Items[2].Instances[0].Cause)
+	assert.Equal(t, models.AppHealthUnknown, res.Items[3].Health)
+	// another version
+	assert.Equal(t, models.AppHealthUnknown, res.Items[4].Health)
+	assert.Equal(t, models.AppHealthHealthy, res.Items[4].Instances[0].Health)
+	// offline
+	assert.Equal(t, models.AppHealthUnknown, res.Items[5].Health)
+	assert.Equal(t, models.ReadyTypOffline, res.Items[5].NodeStatus)
+	// not reported
+	assert.Equal(t, models.AppHealthUnknown, res.Items[6].Health)
+	assert.Empty(t, res.Items[6].Instances)
+
+	sApp.EXPECT().Get("default", "a1", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/a1/health", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// appLabels the labels of an application patched, which are validated as the labels of the nodes
+type appLabels struct {
+	Labels map[string]string `binding:"omitempty,label"`
+}
+
+// PatchApplicationLabels patches the labels of the application without a full update, the rest of the application
+// is kept as it is. The labels of baetyl and the ones of the system apps can't be patched
+func (api *API) PatchApplicationLabels(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	patch := &models.AppLabelsPatch{}
+	if err := c.LoadBody(patch); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if len(patch.Annotations) > 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the applications have no annotations"))
+	}
+	for k := range patch.Labels {
+		if !common.ValidNonBaetyl(k) {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the label ("+k+") of baetyl can't be patched"))
+		}
+	}
+
+	oldApp, err := api.App.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	// sys app: core、init、function is not visible
+	if common.ValidIsInvisible(oldApp.Labels) {
+		return nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", oldApp.Name))
+	}
+	if CheckIsSysResources(oldApp.Labels) {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "labels can't be modified of sys apps"))
+	}
+	if err = api.Facade.CheckAppCanary(ns, oldApp); err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{}
+	for k, v := range oldApp.Labels {
+		labels[k] = v
+	}
+	for k, v := range patch.Labels {
+		if v == nil {
+			delete(labels, k)
+		} else {
+			labels[k] = *v
+		}
+	}
+	if err = binding.Validator.ValidateStruct(&appLabels{Labels: labels}); err != nil {
+		return nil, common.Error(common.ErrInvalidLabels, common.Field(common.ErrInvalidLabels, "labels"))
+	}
+
+	app := new(specV1.Application)
+	*app = *oldApp
+	app.Labels = labels
+	app, err = api.App.Update(nil, ns, app)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return api.ToApplicationView(app)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestPatchApplicationLabels(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api := &API{AppCombinedService: &service.AppCombinedService{App: sApp}, Facade: fApp, log: log.L()}
+
+	router := gin.New()
+	router.PATCH("/v1/apps/:name/labels", func(c *gin.Context) {
+		common.NewContext(c).SetNamespace("default")
+	}, common.Wrapper(api.PatchApplicationLabels))
+	patch := func(name, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPatch, "/v1/apps/"+name+"/labels", bytes.NewBufferString(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	app := &specV1.Application{
+		Namespace: "default",
+		Name:      "app01",
+		Version:   "5",
+		Selector:  "a=b",
+		Labels:    map[string]string{"team": "a", "env": "dev", common.LabelAppName: "app01"},
+		Services:  []specV1.Service{{Name: "s0", Image: "nginx"}},
+	}
+
+	// the labels set to null are removed, and the rest of the app is kept
+	sApp.EXPECT().Get("default", "app01", "").Return(app, nil).Times(1)
+	fApp.EXPECT().CheckAppCanary("default", app).Return(nil).Times(1)
+	sApp.EXPECT().Update(nil, "default", gomock.Any()).DoAndReturn(func(_ interface{}, _ string, a *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, map[string]string{"team": "b", "tier": "web", common.LabelAppName: "app01"}, a.Labels)
+		assert.Equal(t, "5", a.Version)
+		assert.Equal(t, "a=b", a.Selector)
+		assert.Equal(t, app.Services, a.Services)
+		return a, nil
+	}).Times(1)
+	w := patch("app01", `{"labels":{"team":"b","env":null,"tier":"web"}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	view := &models.ApplicationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, "b", view.Labels["team"])
+	// the app itself is not changed
+	assert.Equal(t, "a", app.Labels["team"])
+
+	// the labels of baetyl and the annotations can't be patched
+	w = patch("app01", `{"labels":{"baetyl-app-name":"x"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the label (baetyl-app-name) of baetyl can't be patched")
+	w = patch("app01", `{"annotations":{"a":"b"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the applications have no annotations")
+
+	// invalid labels
+	sApp.EXPECT().Get("default", "app01", "").Return(app, nil).Times(1)
+	fApp.EXPECT().CheckAppCanary("default", app).Return(nil).Times(1)
+	w = patch("app01", `{"labels":{"team":"a b"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrInvalidLabels)
+
+	// the labels of the sys apps can't be modified
+	sysApp := &specV1.Application{Namespace: "default", Name: "sys01", Labels: map[string]string{common.LabelSystem: "true"}}
+	sApp.EXPECT().Get("default", "sys01", "").Return(sysApp, nil).Times(1)
+	w = patch("sys01", `{"labels":{"team":"a"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "labels can't be modified of sys apps")
+
+	// the app with a canary in progress
+	sApp.EXPECT().Get("default", "app01", "").Return(app, nil).Times(1)
+	fApp.EXPECT().CheckAppCanary("default", app).Return(common.Error(common.ErrRequestParamInvalid,
+		common.Field("error", "the app (app01) has a canary in progress, promote or abort the canary first"))).Times(1)
+	w = patch("app01", `{"labels":{"team":"b"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "canary in progress")
+
+	sApp.EXPECT().Get("default", "app02", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	w = patch("app02", `{"labels":{"team":"b"}}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
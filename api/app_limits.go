@@ -0,0 +1,127 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// applyAppLimits sets the default limits of the namespace to the services of the app which don't set their own,
+// the max limit is taken as the default if no default is set. The apps whose limits exceed the max are rejected.
+// The system apps are not limited
+func (api *API) applyAppLimits(ns string, app *specV1.Application) error {
+	if app.System {
+		return nil
+	}
+	settings, err := api.NS.GetSettings(ns)
+	if err != nil {
+		return err
+	}
+	if len(settings.DefaultLimits) == 0 && len(settings.MaxLimits) == 0 {
+		return nil
+	}
+	defaults := make(map[string]string, len(capacityResources))
+	for _, name := range capacityResources {
+		if v, ok := settings.DefaultLimits[name]; ok {
+			defaults[name] = v
+		} else if v, ok = settings.MaxLimits[name]; ok {
+			defaults[name] = v
+		}
+	}
+	var problems []string
+	for _, services := range [][]specV1.Service{app.InitServices, app.Services} {
+		for i := range services {
+			problems = append(problems, limitService(&services[i], defaults, settings.MaxLimits)...)
+		}
+	}
+	if len(problems) > 0 {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", strings.Join(problems, "; ")))
+	}
+	return nil
+}
+
+func limitService(svc *specV1.Service, defaults, max map[string]string) []string {
+	var problems []string
+	for _, name := range capacityResources {
+		v, ok := "", false
+		if svc.Resources != nil {
+			v, ok = svc.Resources.Limits[name]
+		}
+		if !ok {
+			if v, ok = defaults[name]; !ok {
+				continue
+			}
+			if svc.Resources == nil {
+				svc.Resources = &specV1.Resources{}
+			}
+			if svc.Resources.Limits == nil {
+				svc.Resources.Limits = map[string]string{}
+			}
+			svc.Resources.Limits[name] = v
+			// the request given is not cut to fit the default limit
+			if req, ok := svc.Resources.Requests[name]; ok && compareQuantity(req, v) > 0 {
+				problems = append(problems, fmt.Sprintf("the %s request (%s) of the service (%s) exceeds the default limit (%s) of the namespace",
+					name, req, svc.Name, v))
+			}
+		}
+		m, ok := max[name]
+		if !ok {
+			continue
+		}
+		if _, err := resource.ParseQuantity(v); err != nil {
+			problems = append(problems, fmt.Sprintf("the %s limit (%s) of the service (%s) is invalid", name, v, svc.Name))
+		} else if compareQuantity(v, m) > 0 {
+			problems = append(problems, fmt.Sprintf("the %s limit (%s) of the service (%s) exceeds the max limit (%s) of the namespace",
+				name, v, svc.Name, m))
+		}
+	}
+	return problems
+}
+
+// compareQuantity compares the quantities which are already validated, the invalid one is taken as 0
+func compareQuantity(a, b string) int {
+	qa, _ := resource.ParseQuantity(a)
+	qb, _ := resource.ParseQuantity(b)
+	return qa.Cmp(qb)
+}
+
+// validAppLimits checks the default and max limits of the namespace settings, only cpu and memory are supported
+// and the default should not exceed the max
+func validAppLimits(settings *models.NamespaceSettings) error {
+	var problems []string
+	for _, limits := range []map[string]string{settings.DefaultLimits, settings.MaxLimits} {
+		names := make([]string, 0, len(limits))
+		for name := range limits {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if name != capacityResources[0] && name != capacityResources[1] {
+				problems = append(problems, fmt.Sprintf("the limit (%s) is not supported", name))
+				continue
+			}
+			if q, err := resource.ParseQuantity(limits[name]); err != nil || q.Sign() <= 0 {
+				problems = append(problems, fmt.Sprintf("the %s limit (%s) is invalid", name, limits[name]))
+			}
+		}
+	}
+	if len(problems) == 0 {
+		for _, name := range capacityResources {
+			d, ok1 := settings.DefaultLimits[name]
+			m, ok2 := settings.MaxLimits[name]
+			if ok1 && ok2 && compareQuantity(d, m) > 0 {
+				problems = append(problems, fmt.Sprintf("the default %s limit (%s) exceeds the max (%s)", name, d, m))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", strings.Join(problems, "; ")))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestApplyAppLimits(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	api := &API{NS: sNS}
+
+	settings := &models.NamespaceSettings{
+		DefaultLimits: map[string]string{"cpu": "500m"},
+		MaxLimits:     map[string]string{"cpu": "2", "memory": "1Gi"},
+	}
+	sNS.EXPECT().GetSettings("default").Return(settings, nil).AnyTimes()
+
+	app := &specV1.Application{
+		InitServices: []specV1.Service{{Name: "i0"}},
+		Services: []specV1.Service{
+			{Name: "s0"},
+			{Name: "s1", Resources: &specV1.Resources{Limits: map[string]string{"cpu": "1", "memory": "512Mi"}}},
+		},
+	}
+	assert.NoError(t, api.applyAppLimits("default", app))
+	// the max is taken as the default of memory
+	assert.Equal(t, map[string]string{"cpu": "500m", "memory": "1Gi"}, app.InitServices[0].Resources.Limits)
+	assert.Equal(t, map[string]string{"cpu": "500m", "memory": "1Gi"}, app.Services[0].Resources.Limits)
+	assert.Equal(t, map[string]string{"cpu": "1", "memory": "512Mi"}, app.Services[1].Resources.Limits)
+
+	app = &specV1.Application{Services: []specV1.Service{
+		{Name: "s0", Resources: &specV1.Resources{Limits: map[string]string{"cpu": "4"}}},
+		{Name: "s1", Resources: &specV1.Resources{Requests: map[string]string{"cpu": "1"}}},
+	}}
+	err := api.applyAppLimits("default", app)
+	assert.EqualError(t, err, "非法的请求参数。\nThe request parameter is invalid. (the cpu limit (4) of the service (s0) exceeds the max limit (2) of the namespace; "+
+		"the cpu request (1) of the service (s1) exceeds the default limit (500m) of the namespace)")
+
+	// the system apps are not limited
+	app = &specV1.Application{System: true, Services: []specV1.Service{{Name: "s0"}}}
+	assert.NoError(t, api.applyAppLimits("default", app))
+	assert.Nil(t, app.Services[0].Resources)
+
+	sNS.EXPECT().GetSettings("other").Return(nil, fmt.Errorf("error")).Times(1)
+	assert.Error(t, api.applyAppLimits("other", &specV1.Application{}))
+}
+
+func TestValidAppLimits(t *testing.T) {
+	assert.NoError(t, validAppLimits(&models.NamespaceSettings{}))
+	assert.NoError(t, validAppLimits(&models.NamespaceSettings{
+		DefaultLimits: map[string]string{"cpu": "1", "memory": "256Mi"},
+		MaxLimits:     map[string]string{"cpu": "1"},
+	}))
+	err := validAppLimits(&models.NamespaceSettings{
+		DefaultLimits: map[string]string{"gpu": "1", "memory": "abc"},
+		MaxLimits:     map[string]string{"cpu": "0"},
+	})
+	assert.EqualError(t, err, "非法的请求参数。\nThe request parameter is invalid. (the limit (gpu) is not supported; the memory limit (abc) is invalid; the cpu limit (0) is invalid)")
+	err = validAppLimits(&models.NamespaceSettings{
+		DefaultLimits: map[string]string{"cpu": "2"},
+		MaxLimits:     map[string]string{"cpu": "1"},
+	})
+	assert.EqualError(t, err, "非法的请求参数。\nThe request parameter is invalid. (the default cpu limit (2) exceeds the max (1))")
+}
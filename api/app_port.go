@@ -0,0 +1,88 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// checkAppHostPorts rejects the application binding the host ports which are already bound by the other apps
+// on the nodes matching its selector, since the services fail to start on the devices otherwise.
+// The other apps are found in the desires of the nodes, the system apps included
+func (api *API) checkAppHostPorts(ns string, app *specV1.Application) error {
+	if !api.appPortConflictCheck || app.Selector == "" {
+		return nil
+	}
+	ports := appHostPorts(app)
+	if len(ports) == 0 {
+		return nil
+	}
+	nodes, err := api.Node.List(ns, &models.ListOptions{LabelSelector: app.Selector})
+	if err != nil {
+		return err
+	}
+	checked := map[string]bool{app.Name: true}
+	conflicts := map[string][]string{}
+	for _, node := range nodes.Items {
+		for _, sys := range []bool{false, true} {
+			for _, info := range node.Desire.AppInfos(sys) {
+				if checked[info.Name] {
+					continue
+				}
+				checked[info.Name] = true
+				other, err := api.App.Get(ns, info.Name, "")
+				if err != nil {
+					if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+						continue
+					}
+					return err
+				}
+				for port := range appHostPorts(other) {
+					if ports[port] {
+						conflicts[other.Name] = append(conflicts[other.Name], port)
+					}
+				}
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	var names []string
+	for name := range conflicts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var msgs []string
+	for _, name := range names {
+		sort.Strings(conflicts[name])
+		msgs = append(msgs, fmt.Sprintf("%s: %s", name, strings.Join(conflicts[name], ", ")))
+	}
+	return common.Error(common.ErrHostPortConflict, common.Field("name", app.Name),
+		common.Field("conflicts", strings.Join(msgs, "; ")))
+}
+
+// appHostPorts returns the host ports bound by the services of the application, such as 8080/TCP
+func appHostPorts(app *specV1.Application) map[string]bool {
+	ports := map[string]bool{}
+	for _, svc := range app.Services {
+		for _, p := range svc.Ports {
+			if p.HostPort == 0 {
+				continue
+			}
+			protocol := strings.ToUpper(p.Protocol)
+			if protocol == "" {
+				protocol = string(v1.ProtocolTCP)
+			}
+			ports[fmt.Sprintf("%d/%s", p.HostPort, protocol)] = true
+		}
+	}
+	return ports
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestCheckAppHostPorts(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	sApp := ms.NewMockApplicationService(mockCtl)
+	api := &API{Node: sNode, AppCombinedService: &service.AppCombinedService{App: sApp}, appPortConflictCheck: true}
+
+	newApp := func(name string, ports ...specV1.ContainerPort) *specV1.Application {
+		return &specV1.Application{
+			Name:      name,
+			Namespace: "default",
+			Selector:  "region=bj",
+			Services:  []specV1.Service{{Name: "s0", Ports: ports}},
+		}
+	}
+	app := newApp("app01", specV1.ContainerPort{HostPort: 8080, ContainerPort: 80},
+		specV1.ContainerPort{HostPort: 5353, ContainerPort: 53, Protocol: "UDP"})
+	assert.Equal(t, map[string]bool{"8080/TCP": true, "5353/UDP": true}, appHostPorts(app))
+
+	nodes := &models.NodeList{Items: []specV1.Node{
+		{Name: "node01", Desire: specV1.Desire{
+			specV1.KeyApps:    []specV1.AppInfo{{Name: "app01"}, {Name: "app02"}, {Name: "app03"}},
+			specV1.KeySysApps: []specV1.AppInfo{{Name: "baetyl-core"}},
+		}},
+		{Name: "node02", Desire: specV1.Desire{
+			specV1.KeyApps: []specV1.AppInfo{{Name: "app02"}, {Name: "app04"}},
+		}},
+	}}
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "region=bj"}).Return(nodes, nil).Times(1)
+	sApp.EXPECT().Get("default", "app02", "").Return(newApp("app02",
+		specV1.ContainerPort{HostPort: 8080, ContainerPort: 8080, Protocol: "TCP"}), nil).Times(1)
+	sApp.EXPECT().Get("default", "app03", "").Return(newApp("app03",
+		specV1.ContainerPort{HostPort: 5353, ContainerPort: 53}), nil).Times(1)
+	sApp.EXPECT().Get("default", "baetyl-core", "").Return(newApp("baetyl-core",
+		specV1.ContainerPort{HostPort: 5353, ContainerPort: 53, Protocol: "udp"}), nil).Times(1)
+	sApp.EXPECT().Get("default", "app04", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	err := api.checkAppHostPorts("default", app)
+	assert.Error(t, err)
+	e, ok := err.(interface{ Code() string })
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrHostPortConflict, e.Code())
+	assert.Contains(t, err.Error(), "app02: 8080/TCP; baetyl-core: 5353/UDP")
+
+	// no conflicts
+	sNode.EXPECT().List("default", gomock.Any()).Return(&models.NodeList{Items: []specV1.Node{
+		{Name: "node02", Desire: specV1.Desire{specV1.KeyApps: []specV1.AppInfo{{Name: "app03"}}}},
+	}}, nil).Times(1)
+	sApp.EXPECT().Get("default", "app03", "").Return(newApp("app03",
+		specV1.ContainerPort{HostPort: 5353, ContainerPort: 53}), nil).Times(1)
+	assert.NoError(t, api.checkAppHostPorts("default", app))
+
+	// the apps without host ports or selector are not checked
+	assert.NoError(t, api.checkAppHostPorts("default", newApp("app05", specV1.ContainerPort{ContainerPort: 80})))
+	app.Selector = ""
+	assert.NoError(t, api.checkAppHostPorts("default", app))
+}
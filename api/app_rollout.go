@@ -0,0 +1,79 @@
+package api
+
+import (
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetAppRolloutStatus roll up the rollout of the current version of the application across the nodes it targets.
+// A node has applied the version once it reports the version running, it fails if the application of the version
+// is reported failed, and it is pending otherwise, such as the node is offline or still applies an older version
+func (api *API) GetAppRolloutStatus(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	app, err := api.App.Get(ns, n, "")
+	if err != nil {
+		return nil, err
+	}
+	res := &models.AppRollout{
+		Name:     app.Name,
+		Version:  app.Version,
+		Selector: app.Selector,
+		Items:    []models.AppRolloutNode{},
+	}
+	if app.Selector != "" {
+		nodes, err := api.Node.List(ns, &models.ListOptions{LabelSelector: app.Selector})
+		if err != nil {
+			return nil, err
+		}
+		for i := range nodes.Items {
+			item := getAppRolloutNode(&nodes.Items[i], app)
+			switch item.Status {
+			case models.AppRolloutApplied:
+				res.Applied++
+			case models.AppRolloutFailed:
+				res.Failed++
+			default:
+				res.Pending++
+			}
+			res.Items = append(res.Items, item)
+		}
+	}
+	res.Total = len(res.Items)
+	res.Complete = res.Applied == res.Total
+	return res, nil
+}
+
+func getAppRolloutNode(node *specV1.Node, app *specV1.Application) models.AppRolloutNode {
+	item := models.AppRolloutNode{Name: node.Name, Status: models.AppRolloutPending}
+	for _, info := range node.Desire.AppInfos(app.System) {
+		if info.Name == app.Name {
+			item.DesiredVersion = info.Version
+			break
+		}
+	}
+	for _, info := range node.Report.AppInfos(app.System) {
+		if info.Name == app.Name {
+			item.AppliedVersion = info.Version
+			break
+		}
+	}
+	for _, stats := range node.Report.AppStats(app.System) {
+		if stats.Name == app.Name {
+			item.AppStatus = stats.Status
+			item.Cause = stats.Cause
+			break
+		}
+	}
+	if item.AppliedVersion != app.Version {
+		return item
+	}
+	switch item.AppStatus {
+	case specV1.Running, specV1.Succeeded:
+		item.Status = models.AppRolloutApplied
+	case specV1.Failed:
+		item.Status = models.AppRolloutFailed
+	}
+	return item
+}
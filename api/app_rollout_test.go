@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestGetAppRolloutStatus(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	api := &API{AppCombinedService: &service.AppCombinedService{App: sApp}, Node: sNode}
+
+	router := gin.New()
+	router.GET("/v1/apps/:name/rollout", func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }, common.Wrapper(api.GetAppRolloutStatus))
+
+	newNode := func(name, version string, status specV1.Status, cause string) specV1.Node {
+		node := specV1.Node{Name: name, Desire: specV1.Desire{}, Report: specV1.Report{}}
+		node.Desire.SetAppInfos(false, []specV1.AppInfo{{Name: "a0", Version: "2"}})
+		if version != "" {
+			node.Report.SetAppInfos(false, []specV1.AppInfo{{Name: "a0", Version: version}})
+			node.Report.SetAppStats(false, []specV1.AppStats{{AppInfo: specV1.AppInfo{Name: "a0", Version: version}, Status: status, Cause: cause}})
+		}
+		return node
+	}
+	app := &specV1.Application{Name: "a0", Namespace: "default", Version: "2", Selector: "a=b"}
+	nodes := &models.NodeList{Items: []specV1.Node{
+		newNode("n0", "2", specV1.Running, ""),
+		newNode("n1", "1", specV1.Running, ""),
+		newNode("n2", "2", specV1.Failed, "image not found"),
+		newNode("n3", "2", specV1.Pending, ""),
+		newNode("n4", "", "", ""),
+	}}
+	sApp.EXPECT().Get("default", "a0", "").Return(app, nil).Times(1)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=b"}).Return(nodes, nil).Times(1)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/a0/rollout", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := new(models.AppRollout)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, "2", res.Version)
+	assert.Equal(t, 5, res.Total)
+	assert.Equal(t, 1, res.Applied)
+	assert.Equal(t, 1, res.Failed)
+	assert.Equal(t, 3, res.Pending)
+	assert.False(t, res.Complete)
+	assert.Equal(t, models.AppRolloutNode{
+		Name:           "n0",
+		Status:         models.AppRolloutApplied,
+		DesiredVersion: "2",
+		AppliedVersion: "2",
+		AppStatus:      specV1.Running,
+	}, res.Items[0])
+	assert.Equal(t, models.AppRolloutPending, res.Items[1].Status)
+	assert.Equal(t, "1", res.Items[1].AppliedVersion)
+	assert.Equal(t, models.AppRolloutFailed, res.Items[2].Status)
+	assert.Equal(t, "image not found", res.Items[2].Cause)
+	assert.Equal(t, models.AppRolloutPending, res.Items[3].Status)
+	assert.Equal(t, models.AppRolloutPending, res.Items[4].Status)
+	assert.Equal(t, "", res.Items[4].AppliedVersion)
+
+	// the rollout is complete once all nodes have applied the version
+	nodes = &models.NodeList{Items: []specV1.Node{newNode("n0", "2", specV1.Running, "")}}
+	sApp.EXPECT().Get("default", "a0", "").Return(app, nil).Times(1)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=b"}).Return(nodes, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/a0/rollout", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = new(models.AppRollout)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.True(t, res.Complete)
+	assert.Equal(t, 1, res.Applied)
+
+	sApp.EXPECT().Get("default", "a1", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/a1/rollout", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const appScheduleBatch = 100
+
+// ScheduleApplication schedules an update of the application at a future time, the update is checked as PUT /apps/:name
+// when it is scheduled, and is applied by the scheduler when it is due
+func (api *API) ScheduleApplication(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	schedule := &models.AppSchedule{}
+	if err := c.LoadBody(schedule); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	schedule.Namespace, schedule.Name = ns, name
+
+	appView := schedule.Application
+	appView.Namespace, appView.Name = ns, name
+	if err := api.checkApplicationView(appView); err != nil {
+		return nil, err
+	}
+	if appView.Strategy != nil && appView.Strategy.Type == models.AppStrategyCanary {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "a canary can't be scheduled"))
+	}
+	if err := api.validApplication(ns, appView); err != nil {
+		return nil, err
+	}
+	oldApp, err := api.App.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if err = checkAppScheduleTarget(oldApp, appView); err != nil {
+		return nil, err
+	}
+	app, _, err := api.ToApplication(appView, oldApp)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = api.checkAppSelector(c, ns, app, oldApp); err != nil {
+		return nil, err
+	}
+	if _, err = api.checkAppCapacity(c, ns, app); err != nil {
+		return nil, err
+	}
+	return api.AppSched.Create(schedule)
+}
+
+// GetAppSchedule gets the latest scheduled update of the application and its outcome
+func (api *API) GetAppSchedule(c *common.Context) (interface{}, error) {
+	return api.AppSched.Get(c.GetNamespace(), c.GetNameFromParam())
+}
+
+// CancelAppSchedule cancels the pending update of the application
+func (api *API) CancelAppSchedule(c *common.Context) (interface{}, error) {
+	return api.AppSched.Cancel(c.GetNamespace(), c.GetNameFromParam())
+}
+
+// RunAppScheduler applies the due updates of the applications periodically until stopped,
+// each update is claimed before being applied, so it is applied by one replica only
+func (api *API) RunAppScheduler(stop <-chan struct{}) {
+	if api.appScheduleInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(api.appScheduleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			api.applyAppSchedules()
+		}
+	}
+}
+
+func (api *API) applyAppSchedules() {
+	due, err := api.AppSched.ListDue(appScheduleBatch)
+	if err != nil {
+		api.log.Error("failed to list due app schedules", log.Error(err))
+		return
+	}
+	for i := range due {
+		schedule := &due[i]
+		ok, err := api.AppSched.Claim(schedule.ID)
+		if err != nil {
+			api.log.Error("failed to claim app schedule", log.Any("id", schedule.ID), log.Error(err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		err = api.applyAppSchedule(schedule)
+		if err != nil {
+			api.log.Error("failed to apply app schedule", log.Any("namespace", schedule.Namespace),
+				log.Any("name", schedule.Name), log.Any("id", schedule.ID), log.Error(err))
+		}
+		if err = api.AppSched.Finish(schedule.ID, err); err != nil {
+			api.log.Error("failed to record the outcome of app schedule", log.Any("id", schedule.ID), log.Error(err))
+		}
+	}
+}
+
+func (api *API) applyAppSchedule(schedule *models.AppSchedule) error {
+	ns, appView := schedule.Namespace, schedule.Application
+	ctx := context.Background()
+	lockName := "namespace_" + ns
+	version, err := api.Locker.Lock(ctx, lockName, 0)
+	if err != nil {
+		return err
+	}
+	defer api.Locker.Unlock(ctx, lockName, version)
+
+	oldApp, err := api.App.Get(ns, schedule.Name, "")
+	if err != nil {
+		return err
+	}
+	// the app may have been changed since it was scheduled
+	if err = checkAppScheduleTarget(oldApp, appView); err != nil {
+		return err
+	}
+	if err = api.Facade.CheckAppCanary(ns, oldApp); err != nil {
+		return err
+	}
+	if err = api.validApplication(ns, appView); err != nil {
+		return err
+	}
+
+	appView.Version = oldApp.Version
+	appView.CreationTimestamp = oldApp.CreationTimestamp
+	appView.CronStatus = specV1.CronNotSet
+	appView.CronTime = oldApp.CronTime
+	app, configs, err := api.ToApplication(appView, oldApp)
+	if err != nil {
+		return err
+	}
+	// ota can not modify
+	app.Ota = oldApp.Ota
+	_, err = api.Facade.UpdateApp(ns, oldApp, app, configs)
+	return errors.Trace(err)
+}
+
+// checkAppScheduleTarget checks that the application can be updated to the scheduled one
+func checkAppScheduleTarget(oldApp *specV1.Application, appView *models.ApplicationView) error {
+	// sys app: core、init、function is not visible
+	if common.ValidIsInvisible(oldApp.Labels) {
+		return common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", oldApp.Name))
+	}
+	if CheckIsSysResources(oldApp.Labels) &&
+		(oldApp.Selector != appView.Selector || !reflect.DeepEqual(oldApp.Labels, appView.Labels) || !appView.System) {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "selector，labels or system field can't be modified of sys apps"))
+	}
+	if oldApp.CronStatus == specV1.CronWait {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "the app has a cron job waiting to be deployed"))
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestAppSchedule(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sSched := ms.NewMockAppScheduleService(mockCtl)
+	api := &API{AppSched: sSched, AppCombinedService: &service.AppCombinedService{App: sApp}, log: log.L()}
+
+	router := gin.New()
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	router.GET("/v1/apps/:name/schedule", mockIM, common.Wrapper(api.GetAppSchedule))
+	router.POST("/v1/apps/:name/schedule", mockIM, common.Wrapper(api.ScheduleApplication))
+	router.DELETE("/v1/apps/:name/schedule", mockIM, common.Wrapper(api.CancelAppSchedule))
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	app := &specV1.Application{Namespace: "default", Name: "app01", Version: "10", Selector: "a=b"}
+	at := time.Now().Add(time.Hour)
+	body := &models.AppSchedule{
+		Time: &at,
+		Application: &models.ApplicationView{
+			Name:     "app01",
+			Selector: "a=c",
+			Services: []models.ServiceView{{Service: specV1.Service{Name: "s1", Image: "nginx:1.21"}}},
+		},
+	}
+	sApp.EXPECT().Get("default", "app01", "").Return(app, nil).Times(1)
+	sSched.EXPECT().Create(gomock.Any()).DoAndReturn(func(s *models.AppSchedule) (*models.AppSchedule, error) {
+		assert.Equal(t, "default", s.Namespace)
+		assert.Equal(t, "app01", s.Name)
+		assert.Equal(t, "default", s.Application.Namespace)
+		assert.Equal(t, "a=c", s.Application.Selector)
+		s.ID = 1
+		s.Status = models.AppSchedulePending
+		return s, nil
+	}).Times(1)
+	w := do(http.MethodPost, "/v1/apps/app01/schedule", body)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := new(models.AppSchedule)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, int64(1), res.ID)
+	assert.Equal(t, models.AppSchedulePending, res.Status)
+
+	// the target spec is required
+	w = do(http.MethodPost, "/v1/apps/app01/schedule", &models.AppSchedule{Time: &at})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the app waiting for its cron job can't be scheduled
+	sApp.EXPECT().Get("default", "app01", "").Return(&specV1.Application{Name: "app01", CronStatus: specV1.CronWait}, nil).Times(1)
+	w = do(http.MethodPost, "/v1/apps/app01/schedule", body)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the app has a cron job waiting to be deployed")
+
+	canary := *body.Application
+	canary.Strategy = &models.AppStrategy{Type: models.AppStrategyCanary, Percent: 10}
+	w = do(http.MethodPost, "/v1/apps/app01/schedule", &models.AppSchedule{Time: &at, Application: &canary})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "a canary can't be scheduled")
+
+	// get and cancel
+	sSched.EXPECT().Get("default", "app01").Return(&models.AppSchedule{ID: 1, Status: models.AppSchedulePending}, nil).Times(1)
+	w = do(http.MethodGet, "/v1/apps/app01/schedule", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	sSched.EXPECT().Cancel("default", "app01").Return(&models.AppSchedule{ID: 1, Status: models.AppScheduleCanceled}, nil).Times(1)
+	w = do(http.MethodDelete, "/v1/apps/app01/schedule", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"canceled"`)
+}
+
+func TestApplyAppSchedules(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sSched := ms.NewMockAppScheduleService(mockCtl)
+	sLocker := ms.NewMockLockerService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api := &API{AppSched: sSched, Locker: sLocker, Facade: fApp, AppCombinedService: &service.AppCombinedService{App: sApp}, log: log.L()}
+
+	app := &specV1.Application{Namespace: "default", Name: "app01", Version: "10", Selector: "a=b", Ota: specV1.OtaInfo{Task: &specV1.OtaTask{}}}
+	due := []models.AppSchedule{
+		{ID: 1, Namespace: "default", Name: "app01", Application: &models.ApplicationView{
+			Name:     "app01",
+			Selector: "a=c",
+			Services: []models.ServiceView{{Service: specV1.Service{Name: "s1", Image: "nginx:1.21"}}},
+		}},
+		// claimed by another replica
+		{ID: 2, Namespace: "default", Name: "app02"},
+		{ID: 3, Namespace: "default", Name: "app03", Application: &models.ApplicationView{Name: "app03"}},
+	}
+	sSched.EXPECT().ListDue(appScheduleBatch).Return(due, nil).Times(1)
+	sSched.EXPECT().Claim(int64(1)).Return(true, nil).Times(1)
+	sSched.EXPECT().Claim(int64(2)).Return(false, nil).Times(1)
+	sSched.EXPECT().Claim(int64(3)).Return(true, nil).Times(1)
+	sLocker.EXPECT().Lock(gomock.Any(), "namespace_default", int64(0)).Return("v", nil).Times(2)
+	sLocker.EXPECT().Unlock(gomock.Any(), "namespace_default", "v").Times(2)
+	sApp.EXPECT().Get("default", "app01", "").Return(app, nil).Times(1)
+	fApp.EXPECT().CheckAppCanary("default", app).Return(nil).Times(1)
+	fApp.EXPECT().UpdateApp("default", app, gomock.Any(), nil).DoAndReturn(
+		func(_ string, _, a *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "a=c", a.Selector)
+			assert.Equal(t, "10", a.Version)
+			assert.Equal(t, app.Ota, a.Ota)
+			return a, nil
+		}).Times(1)
+	sSched.EXPECT().Finish(int64(1), nil).Return(nil).Times(1)
+	// the app has been deleted since it was scheduled
+	sApp.EXPECT().Get("default", "app03", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	sSched.EXPECT().Finish(int64(3), gomock.Not(nil)).Return(nil).Times(1)
+	api.applyAppSchedules()
+}
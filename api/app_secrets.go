@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	// AutoMountSecretVolumePrefix the prefix of the volumes of the secrets mounted automatically
+	AutoMountSecretVolumePrefix = "baetyl-auto-secret-"
+	// AutoMountSecretDir the directory the secrets mounted automatically are mounted under, one sub directory each
+	AutoMountSecretDir = "/var/lib/baetyl/secrets"
+)
+
+// isAutoMountSecrets returns whether the secrets labeled for the app are mounted automatically
+func isAutoMountSecrets(labels map[string]string) bool {
+	res, _ := strconv.ParseBool(labels[common.LabelAutoMountSecrets])
+	return res
+}
+
+// listAutoMountSecrets lists the secrets of the namespace of the app labeled for the app, only the config secrets
+// are mounted, and the secrets of the system are mounted to the system apps only
+func (api *API) listAutoMountSecrets(ns, name string, system bool) (*models.SecretList, error) {
+	secrets, err := api.Secret.List(ns, &models.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", common.LabelAppName, name)})
+	if err != nil {
+		return nil, err
+	}
+	res := &models.SecretList{ListOptions: secrets.ListOptions, Items: []specV1.Secret{}}
+	for _, s := range secrets.Items {
+		if s.Namespace != "" && s.Namespace != ns {
+			continue
+		}
+		if t, ok := s.Labels[specV1.SecretLabel]; ok && t != specV1.SecretConfig {
+			continue
+		}
+		if !system && CheckIsSysResources(s.Labels) {
+			continue
+		}
+		res.Items = append(res.Items, s)
+	}
+	res.Total = len(res.Items)
+	return res, nil
+}
+
+// checkAutoSecretVolumes checks the volumes of the app don't use the names reserved for the secrets mounted automatically
+func checkAutoSecretVolumes(app *models.ApplicationView) error {
+	for _, v := range app.Volumes {
+		if strings.HasPrefix(v.Name, AutoMountSecretVolumePrefix) {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the volume name (%s) is reserved for the secrets mounted automatically", v.Name)))
+		}
+	}
+	return nil
+}
+
+// mountAutoSecrets resolves the secrets labeled for the app, which are added as volumes mounted read-only
+// by all services of the app under AutoMountSecretDir. The ones resolved before are dropped first, so that
+// the secrets no longer labeled for the app are unmounted, and the secrets referenced by the app already are skipped.
+// The secrets labeled for the app later are mounted on the next update of the app
+func (api *API) mountAutoSecrets(ns string, appView *models.ApplicationView, app *specV1.Application) error {
+	unmountAutoSecrets(app)
+	if !appView.AutoMountSecrets {
+		delete(app.Labels, common.LabelAutoMountSecrets)
+		return nil
+	}
+	secrets, err := api.listAutoMountSecrets(ns, app.Name, appView.System)
+	if err != nil {
+		return err
+	}
+	referenced := map[string]bool{}
+	for _, v := range app.Volumes {
+		if v.Secret != nil {
+			referenced[v.Secret.Name] = true
+		}
+	}
+	for _, s := range secrets.Items {
+		if referenced[s.Name] {
+			continue
+		}
+		volume := AutoMountSecretVolumePrefix + s.Name
+		app.Volumes = append(app.Volumes, specV1.Volume{
+			Name:         volume,
+			VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: s.Name}},
+		})
+		for i := range app.Services {
+			app.Services[i].VolumeMounts = append(app.Services[i].VolumeMounts, specV1.VolumeMount{
+				Name:      volume,
+				MountPath: AutoMountSecretDir + "/" + s.Name,
+				ReadOnly:  true,
+			})
+		}
+	}
+	if app.Labels == nil {
+		app.Labels = map[string]string{}
+	}
+	app.Labels[common.LabelAutoMountSecrets] = "true"
+	return nil
+}
+
+// unmountAutoSecrets removes the volumes and the mounts of the secrets mounted automatically
+func unmountAutoSecrets(app *specV1.Application) {
+	var volumes []specV1.Volume
+	for _, v := range app.Volumes {
+		if !strings.HasPrefix(v.Name, AutoMountSecretVolumePrefix) {
+			volumes = append(volumes, v)
+		}
+	}
+	app.Volumes = volumes
+	for i := range app.Services {
+		app.Services[i].VolumeMounts = withoutAutoSecretMounts(app.Services[i].VolumeMounts)
+	}
+}
+
+// hideAutoSecrets hides the secrets mounted automatically from the view of the app, which are resolved again on saving
+func hideAutoSecrets(app *specV1.Application, appView *models.ApplicationView) {
+	appView.AutoMountSecrets = isAutoMountSecrets(app.Labels)
+	delete(appView.Labels, common.LabelAutoMountSecrets)
+	var volumes []models.VolumeView
+	for _, v := range appView.Volumes {
+		if !strings.HasPrefix(v.Name, AutoMountSecretVolumePrefix) {
+			volumes = append(volumes, v)
+		}
+	}
+	appView.Volumes = volumes
+	for i := range appView.Services {
+		appView.Services[i].VolumeMounts = withoutAutoSecretMounts(appView.Services[i].VolumeMounts)
+	}
+}
+
+func withoutAutoSecretMounts(mounts []specV1.VolumeMount) []specV1.VolumeMount {
+	var res []specV1.VolumeMount
+	for _, m := range mounts {
+		if !strings.HasPrefix(m.Name, AutoMountSecretVolumePrefix) {
+			res = append(res, m)
+		}
+	}
+	return res
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func autoMountTestSecrets() *models.SecretList {
+	return &models.SecretList{Items: []specV1.Secret{
+		{Name: "s1", Namespace: "default", Labels: map[string]string{common.LabelAppName: "app01", specV1.SecretLabel: specV1.SecretConfig}},
+		{Name: "s2", Namespace: "default", Labels: map[string]string{common.LabelAppName: "app01"}},
+		{Name: "reg", Namespace: "default", Labels: map[string]string{common.LabelAppName: "app01", specV1.SecretLabel: specV1.SecretRegistry}},
+		{Name: "sys", Namespace: "default", Labels: map[string]string{common.LabelAppName: "app01", common.LabelSystem: "true"}},
+		{Name: "other", Namespace: "other", Labels: map[string]string{common.LabelAppName: "app01"}},
+	}}
+}
+
+func TestMountAutoSecrets(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api := &API{AppCombinedService: &service.AppCombinedService{Secret: sSecret}, log: log.L()}
+
+	app := &specV1.Application{
+		Name: "app01",
+		Services: []specV1.Service{
+			{Name: "a", VolumeMounts: []specV1.VolumeMount{{Name: "v1", MountPath: "/s2"}, {Name: AutoMountSecretVolumePrefix + "old", MountPath: "/old"}}},
+			{Name: "b"},
+		},
+		Volumes: []specV1.Volume{
+			{Name: "v1", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s2"}}},
+			{Name: AutoMountSecretVolumePrefix + "old", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "old"}}},
+		},
+	}
+	sSecret.EXPECT().List("default", &models.ListOptions{LabelSelector: common.LabelAppName + "=app01"}).Return(autoMountTestSecrets(), nil).Times(1)
+	err := api.mountAutoSecrets("default", &models.ApplicationView{AutoMountSecrets: true}, app)
+	assert.NoError(t, err)
+	assert.Equal(t, "true", app.Labels[common.LabelAutoMountSecrets])
+	// the secret referenced already, the registries, the secrets of the system and of other namespaces are skipped
+	assert.Equal(t, []specV1.Volume{
+		{Name: "v1", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s2"}}},
+		{Name: AutoMountSecretVolumePrefix + "s1", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s1"}}},
+	}, app.Volumes)
+	mount := specV1.VolumeMount{Name: AutoMountSecretVolumePrefix + "s1", MountPath: AutoMountSecretDir + "/s1", ReadOnly: true}
+	assert.Equal(t, []specV1.VolumeMount{{Name: "v1", MountPath: "/s2"}, mount}, app.Services[0].VolumeMounts)
+	assert.Equal(t, []specV1.VolumeMount{mount}, app.Services[1].VolumeMounts)
+
+	// the view hides the secrets mounted automatically
+	view := &models.ApplicationView{Labels: map[string]string{common.LabelAutoMountSecrets: "true"}}
+	view.Volumes = []models.VolumeView{{Name: "v1"}, {Name: AutoMountSecretVolumePrefix + "s1"}}
+	view.Services = []models.ServiceView{{Service: app.Services[0]}}
+	hideAutoSecrets(app, view)
+	assert.True(t, view.AutoMountSecrets)
+	assert.Empty(t, view.Labels)
+	assert.Equal(t, []models.VolumeView{{Name: "v1"}}, view.Volumes)
+	assert.Equal(t, []specV1.VolumeMount{{Name: "v1", MountPath: "/s2"}}, view.Services[0].VolumeMounts)
+
+	// the secrets are unmounted if disabled
+	err = api.mountAutoSecrets("default", &models.ApplicationView{}, app)
+	assert.NoError(t, err)
+	assert.Empty(t, app.Labels)
+	assert.Len(t, app.Volumes, 1)
+	assert.Equal(t, []specV1.VolumeMount{{Name: "v1", MountPath: "/s2"}}, app.Services[0].VolumeMounts)
+	assert.Empty(t, app.Services[1].VolumeMounts)
+
+	err = checkAutoSecretVolumes(&models.ApplicationView{Volumes: []models.VolumeView{{Name: AutoMountSecretVolumePrefix + "x"}}})
+	assert.Error(t, err)
+}
+
+func TestGetAutoMountedSecrets(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api := &API{AppCombinedService: &service.AppCombinedService{App: sApp, Secret: sSecret}, log: log.L()}
+
+	router := gin.New()
+	router.GET("/v1/apps/:name/secrets", func(c *gin.Context) {
+		common.NewContext(c).SetNamespace("default")
+	}, common.Wrapper(api.GetSysAppSecrets))
+
+	app := &specV1.Application{Name: "app01", Namespace: "default", Labels: map[string]string{common.LabelAutoMountSecrets: "true"}}
+	sApp.EXPECT().Get("default", "app01", "").Return(app, nil).Times(1)
+	sSecret.EXPECT().List("default", gomock.Any()).Return(autoMountTestSecrets(), nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/app01/secrets", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.SecretViewList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, "s1", res.Items[0].Name)
+	assert.Equal(t, "s2", res.Items[1].Name)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// appTargetsSampleSize how many names of the matching nodes are returned by the preview
+const appTargetsSampleSize = 10
+
+// PreviewAppTargets count the nodes matching the selector of an application to be created, nothing is created
+func (api *API) PreviewAppTargets(c *common.Context) (interface{}, error) {
+	preview := new(models.AppTargetsPreview)
+	if err := c.LoadBody(preview); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	nodes, err := api.Node.List(c.GetNamespace(), &models.ListOptions{LabelSelector: preview.Selector})
+	if err != nil {
+		return nil, err
+	}
+	res := &models.AppTargets{Selector: preview.Selector, Total: len(nodes.Items), Sample: []string{}}
+	for _, node := range nodes.Items {
+		if len(res.Sample) == appTargetsSampleSize {
+			break
+		}
+		res.Sample = append(res.Sample, node.Name)
+	}
+	return res, nil
+}
+
+// GetAppNodes list the nodes currently matching the selector of the application.
+// The selector is evaluated against the labels of the nodes, which are rematched whenever the labels
+// of a node or the selector of an application change, so the list is what the application is deployed to
+func (api *API) GetAppNodes(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	app, err := api.App.Get(ns, n, "")
+	if err != nil {
+		return nil, err
+	}
+	res := &models.AppNodeList{Selector: app.Selector, Items: []models.AppNode{}}
+	if app.Selector == "" {
+		return res, nil
+	}
+	nodes, err := api.Node.List(ns, &models.ListOptions{LabelSelector: app.Selector})
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes.Items {
+		item := models.AppNode{Name: node.Name, Labels: node.Labels}
+		for _, info := range node.Report.AppInfos(app.System) {
+			if info.Name == app.Name {
+				item.ReportedVersion = info.Version
+				break
+			}
+		}
+		item.Synced = item.ReportedVersion == app.Version
+		res.Items = append(res.Items, item)
+	}
+	res.Total = len(res.Items)
+	return res, nil
+}
+
+// checkAppSelector guards against deploying the application to too many nodes by a broad selector by mistake.
+// The selector matching more nodes than the threshold is rejected unless ?confirm=true is given,
+// it is returned as a warning instead in dry run, and an unchanged selector is not checked again
+func (api *API) checkAppSelector(c *common.Context, ns string, app, oldApp *specV1.Application) ([]string, error) {
+	if api.appSelectorConfirmThreshold <= 0 || app.Selector == "" {
+		return nil, nil
+	}
+	if oldApp != nil && oldApp.Selector == app.Selector {
+		return nil, nil
+	}
+	if confirm, _ := strconv.ParseBool(c.Query("confirm")); confirm {
+		return nil, nil
+	}
+	nodes, err := api.Node.List(ns, &models.ListOptions{LabelSelector: app.Selector})
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes.Items) <= api.appSelectorConfirmThreshold {
+		return nil, nil
+	}
+	msg := fmt.Sprintf("the selector (%s) matches %d nodes, more than %d, set confirm=true to deploy the application to all of them",
+		app.Selector, len(nodes.Items), api.appSelectorConfirmThreshold)
+	if isDryRun(c) {
+		return []string{msg}, nil
+	}
+	return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", msg))
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestGetAppNodes(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	api := &API{AppCombinedService: &service.AppCombinedService{App: sApp}, Node: sNode}
+
+	router := gin.New()
+	router.GET("/v1/apps/:name/nodes", func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }, common.Wrapper(api.GetAppNodes))
+
+	app := &specV1.Application{Name: "a0", Namespace: "default", Version: "2", Selector: "a=b"}
+	nodes := &models.NodeList{Items: []specV1.Node{
+		{Name: "n0", Labels: map[string]string{"a": "b"}, Report: specV1.Report{
+			"apps": []interface{}{map[string]interface{}{"name": "a0", "version": "2"}},
+		}},
+		{Name: "n1", Labels: map[string]string{"a": "b"}, Report: specV1.Report{
+			"apps": []interface{}{map[string]interface{}{"name": "a0", "version": "1"}},
+		}},
+		{Name: "n2", Labels: map[string]string{"a": "b"}},
+	}}
+	sApp.EXPECT().Get("default", "a0", "").Return(app, nil).Times(1)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=b"}).Return(nodes, nil).Times(1)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/a0/nodes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"selector":"a=b","total":3,"items":[
+		{"name":"n0","labels":{"a":"b"},"reportedVersion":"2","synced":true},
+		{"name":"n1","labels":{"a":"b"},"reportedVersion":"1","synced":false},
+		{"name":"n2","labels":{"a":"b"},"synced":false}]}`, w.Body.String())
+
+	// the app without selector is deployed to no nodes
+	sApp.EXPECT().Get("default", "a0", "").Return(&specV1.Application{Name: "a0"}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/a0/nodes", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"selector":"","total":0,"items":[]}`, w.Body.String())
+}
+
+func TestCheckAppSelector(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api := &API{Node: sNode, appSelectorConfirmThreshold: 2}
+
+	newContext := func(query string) *common.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", "/v1/apps"+query, nil)
+		return common.NewContext(c)
+	}
+	app := &specV1.Application{Name: "a0", Selector: "a=b"}
+	nodes := &models.NodeList{Items: []specV1.Node{{Name: "n0"}, {Name: "n1"}, {Name: "n2"}}}
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=b"}).Return(nodes, nil).Times(2)
+
+	_, err := api.checkAppSelector(newContext(""), "default", app, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the selector (a=b) matches 3 nodes, more than 2, set confirm=true")
+
+	warnings, err := api.checkAppSelector(newContext("?dryRun=true"), "default", app, nil)
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 1)
+
+	// confirmed, unchanged or not limited
+	warnings, err = api.checkAppSelector(newContext("?confirm=true"), "default", app, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, warnings)
+	warnings, err = api.checkAppSelector(newContext(""), "default", app, &specV1.Application{Selector: "a=b"})
+	assert.NoError(t, err)
+	assert.Nil(t, warnings)
+	api.appSelectorConfirmThreshold = 0
+	warnings, err = api.checkAppSelector(newContext(""), "default", app, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, warnings)
+
+	api.appSelectorConfirmThreshold = 3
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=b"}).Return(nodes, nil).Times(1)
+	warnings, err = api.checkAppSelector(newContext(""), "default", app, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, warnings)
+}
+
+func TestPreviewAppTargets(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api := &API{Node: sNode}
+
+	router := gin.New()
+	router.POST("/v1/apps/preview-targets", func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }, common.Wrapper(api.PreviewAppTargets))
+	do := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/v1/apps/preview-targets", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	nodes := &models.NodeList{}
+	for i := 0; i < 12; i++ {
+		nodes.Items = append(nodes.Items, specV1.Node{Name: fmt.Sprintf("n%02d", i)})
+	}
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=b"}).Return(nodes, nil).Times(1)
+	w := do(`{"selector":"a=b"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"selector":"a=b","total":12,"sample":["n00","n01","n02","n03","n04","n05","n06","n07","n08","n09"]}`, w.Body.String())
+
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=c"}).Return(&models.NodeList{}, nil).Times(1)
+	w = do(`{"selector":"a=c"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"selector":"a=c","total":0,"sample":[]}`, w.Body.String())
+
+	// the selector is required
+	w = do(`{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/gin-gonic/gin/binding"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetAppTemplate get an app template
+func (api *API) GetAppTemplate(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	tpl, err := api.AppTpl.Get(ns, n)
+	if err = checkResourceFound(tpl, err, common.AppTemplate, n); err != nil {
+		return nil, err
+	}
+	return tpl, nil
+}
+
+// ListAppTemplate list app templates
+func (api *API) ListAppTemplate(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	params, err := api.ParseListOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	return api.AppTpl.List(ns, params)
+}
+
+// CreateAppTemplate create an app template
+func (api *API) CreateAppTemplate(c *common.Context) (interface{}, error) {
+	tpl, err := api.parseAndCheckAppTemplate(c)
+	if err != nil {
+		return nil, err
+	}
+	ns := c.GetNamespace()
+	tpl.Namespace = ns
+
+	old, err := api.AppTpl.Get(ns, tpl.Name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return nil, err
+		}
+	}
+	if old != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
+	}
+	return api.AppTpl.Create(tpl)
+}
+
+// UpdateAppTemplate update the parameters, template, labels and description of an app template,
+// the applications instantiated before are not changed
+func (api *API) UpdateAppTemplate(c *common.Context) (interface{}, error) {
+	tpl, err := api.parseAndCheckAppTemplate(c)
+	if err != nil {
+		return nil, err
+	}
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	old, err := api.AppTpl.Get(ns, n)
+	if err != nil {
+		return nil, err
+	}
+	old.Parameters = tpl.Parameters
+	old.Template = tpl.Template
+	old.Labels = tpl.Labels
+	old.Description = tpl.Description
+	old.UpdateTimestamp = time.Now()
+	return api.AppTpl.Update(old)
+}
+
+// DeleteAppTemplate delete an app template, the applications instantiated are kept
+func (api *API) DeleteAppTemplate(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	_, err := api.AppTpl.Get(ns, n)
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return nil, api.AppTpl.Delete(ns, n)
+}
+
+// InstantiateAppTemplate render the template with the values of the parameters and create the application
+func (api *API) InstantiateAppTemplate(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.AppTemplateInstance{}
+	if err := c.LoadBody(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	tpl, err := api.AppTpl.Get(ns, n)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := api.AppTpl.Render(tpl, params.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	appView, err := parseRenderedApplication(data)
+	if err != nil {
+		return nil, err
+	}
+	if params.Name != "" {
+		appView.Name = params.Name
+	}
+	if !common.ValidNonBaetyl(appView.Name) {
+		return nil, common.Error(common.ErrInvalidName, common.Field("nonBaetyl", "Name"))
+	}
+	if err = api.checkApplicationView(appView); err != nil {
+		return nil, err
+	}
+	return api.createApplication(c, appView)
+}
+
+func (api *API) parseAndCheckAppTemplate(c *common.Context) (*models.AppTemplate, error) {
+	tpl := new(models.AppTemplate)
+	if err := c.LoadBody(tpl); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if name := c.GetNameFromParam(); name != "" {
+		tpl.Name = name
+	}
+	if tpl.Name == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+	}
+	return tpl, nil
+}
+
+// parseRenderedApplication parses the rendered template in yaml or json as the body of creating an application
+func parseRenderedApplication(data []byte) (*models.ApplicationView, error) {
+	invalid := func(err error) error {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the rendered application is invalid: %s", err.Error())))
+	}
+	data, err := yaml.ToJSON(data)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	app := new(models.ApplicationView)
+	if err = json.Unmarshal(data, app); err != nil {
+		return nil, invalid(err)
+	}
+	if err = binding.Validator.ValidateStruct(app); err != nil {
+		return nil, invalid(err)
+	}
+	if err = utils.SetDefaults(app); err != nil {
+		return nil, invalid(err)
+	}
+	return app, nil
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initAppTemplateAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L()}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	sNS.EXPECT().GetSettings(gomock.Any()).Return(&models.NamespaceSettings{}, nil).AnyTimes()
+	api.NS = sNS
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		templates := v1.Group("/apptemplates")
+		templates.GET("/:name", mockIM, common.Wrapper(api.GetAppTemplate))
+		templates.PUT("/:name", mockIM, common.Wrapper(api.UpdateAppTemplate))
+		templates.DELETE("/:name", mockIM, common.Wrapper(api.DeleteAppTemplate))
+		templates.POST("", mockIM, common.Wrapper(api.CreateAppTemplate))
+		templates.GET("", mockIM, common.Wrapper(api.ListAppTemplate))
+		templates.POST("/:name/instantiate", mockIM, common.Wrapper(api.InstantiateAppTemplate))
+	}
+	return api, router, mockCtl
+}
+
+func TestCreateAndUpdateAppTemplate(t *testing.T) {
+	api, router, mockCtl := initAppTemplateAPI(t)
+	defer mockCtl.Finish()
+	sTpl := ms.NewMockAppTemplateService(mockCtl)
+	api.AppTpl = sTpl
+
+	tpl := &models.AppTemplate{
+		Name:       "tpl01",
+		Parameters: []models.AppTemplateParameter{{Name: "image", Required: true}},
+		Template:   "name: app\nservices:\n- name: s\n  image: '{{ .image }}'",
+	}
+	sTpl.EXPECT().Get("default", "tpl01").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	sTpl.EXPECT().Create(gomock.Any()).DoAndReturn(func(template *models.AppTemplate) (*models.AppTemplate, error) {
+		assert.Equal(t, "default", template.Namespace)
+		assert.Equal(t, tpl.Template, template.Template)
+		return template, nil
+	}).Times(1)
+	body, _ := json.Marshal(tpl)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/apptemplates", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// name in use
+	sTpl.EXPECT().Get("default", "tpl01").Return(tpl, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apptemplates", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// template is required
+	body, _ = json.Marshal(&models.AppTemplate{Name: "tpl02"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apptemplates", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// update
+	sTpl.EXPECT().Get("default", "tpl01").Return(&models.AppTemplate{Name: "tpl01", Namespace: "default", Template: "old"}, nil).Times(1)
+	sTpl.EXPECT().Update(gomock.Any()).DoAndReturn(func(template *models.AppTemplate) (*models.AppTemplate, error) {
+		assert.Equal(t, tpl.Template, template.Template)
+		assert.Equal(t, tpl.Parameters, template.Parameters)
+		return template, nil
+	}).Times(1)
+	body, _ = json.Marshal(tpl)
+	req, _ = http.NewRequest(http.MethodPut, "/v1/apptemplates/tpl01", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetListAndDeleteAppTemplate(t *testing.T) {
+	api, router, mockCtl := initAppTemplateAPI(t)
+	defer mockCtl.Finish()
+	sTpl := ms.NewMockAppTemplateService(mockCtl)
+	api.AppTpl = sTpl
+
+	tpl := &models.AppTemplate{Name: "tpl01", Namespace: "default", Template: "name: app"}
+	sTpl.EXPECT().Get("default", "tpl01").Return(tpl, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apptemplates/tpl01", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sTpl.EXPECT().List("default", gomock.Any()).Return(&models.AppTemplateList{Total: 1, Items: []models.AppTemplate{*tpl}}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apptemplates", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sTpl.EXPECT().Get("default", "tpl01").Return(tpl, nil).Times(1)
+	sTpl.EXPECT().Delete("default", "tpl01").Return(nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apptemplates/tpl01", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// not found is ignored
+	sTpl.EXPECT().Get("default", "tpl02").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apptemplates/tpl02", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestInstantiateAppTemplate(t *testing.T) {
+	api, router, mockCtl := initAppTemplateAPI(t)
+	defer mockCtl.Finish()
+	sTpl := ms.NewMockAppTemplateService(mockCtl)
+	api.AppTpl = sTpl
+	sApp := ms.NewMockApplicationService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: ms.NewMockConfigService(mockCtl),
+		Secret: ms.NewMockSecretService(mockCtl),
+	}
+	fApp := mf.NewMockFacade(mockCtl)
+	api.Facade = fApp
+
+	tpl := &models.AppTemplate{Name: "tpl01", Namespace: "default"}
+	rendered := []byte(`
+name: web
+services:
+- name: web
+  image: nginx:1.25
+  ports:
+  - containerPort: 80
+`)
+	instantiate := func(params *models.AppTemplateInstance) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(params)
+		req, _ := http.NewRequest(http.MethodPost, "/v1/apptemplates/tpl01/instantiate", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	values := map[string]string{"version": "1.25"}
+
+	// 200 the name is overridden
+	sTpl.EXPECT().Get("default", "tpl01").Return(tpl, nil).Times(1)
+	sTpl.EXPECT().Render(tpl, values).Return(rendered, nil).Times(1)
+	sApp.EXPECT().Get("default", "web-01", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	fApp.EXPECT().CreateApp("default", nil, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _, app *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "web-01", app.Name)
+			assert.Equal(t, "nginx:1.25", app.Services[0].Image)
+			assert.Equal(t, int32(80), app.Services[0].Ports[0].ContainerPort)
+			assert.Equal(t, specV1.AppTypeContainer, app.Type)
+			return app, nil
+		}).Times(1)
+	w := instantiate(&models.AppTemplateInstance{Name: "web-01", Parameters: values})
+	assert.Equal(t, http.StatusOK, w.Code)
+	view := &models.ApplicationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, "web-01", view.Name)
+
+	// 400 the required parameters are missing
+	sTpl.EXPECT().Get("default", "tpl01").Return(tpl, nil).Times(1)
+	sTpl.EXPECT().Render(tpl, gomock.Any()).Return(nil, common.Error(common.ErrRequestParamInvalid,
+		common.Field("error", "the required parameters (version) are missing"))).Times(1)
+	w = instantiate(&models.AppTemplateInstance{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the required parameters (version) are missing")
+
+	// 400 the rendered application is invalid
+	sTpl.EXPECT().Get("default", "tpl01").Return(tpl, nil).Times(1)
+	sTpl.EXPECT().Render(tpl, gomock.Any()).Return([]byte("name: web\nservices: ["), nil).Times(1)
+	w = instantiate(&models.AppTemplateInstance{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the rendered application is invalid")
+
+	sTpl.EXPECT().Get("default", "tpl01").Return(tpl, nil).Times(1)
+	sTpl.EXPECT().Render(tpl, gomock.Any()).Return([]byte("name: web\ntype: unknown"), nil).Times(1)
+	w = instantiate(&models.AppTemplateInstance{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "type is invalid")
+
+	sTpl.EXPECT().Get("default", "tpl01").Return(tpl, nil).Times(1)
+	sTpl.EXPECT().Render(tpl, gomock.Any()).Return(rendered, nil).Times(1)
+	w = instantiate(&models.AppTemplateInstance{Name: "baetyl-web"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 404 the template is not found
+	sTpl.EXPECT().Get("default", "tpl01").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	w = instantiate(&models.AppTemplateInstance{})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,10 +14,13 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/baetyl/baetyl-go/v2/utils"
 	"github.com/jinzhu/copier"
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 const (
@@ -42,7 +46,7 @@ type DeleteApplicationOta = func(c *common.Context, app *specV1.Application) err
 func (api *API) GetApplication(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
 	app, err := api.Facade.GetApp(ns, n, "")
-	if err != nil {
+	if err = checkResourceFound(app, err, common.APP, n); err != nil {
 		return nil, err
 	}
 
@@ -50,16 +54,23 @@ func (api *API) GetApplication(c *common.Context) (interface{}, error) {
 	if common.ValidIsInvisible(app.Labels) {
 		return nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", app.Name))
 	}
+	setResourceETag(c, app.Version)
 	return api.ToApplicationView(app)
 }
 
-// ListApplication list application
+// ListApplication list application, sortBy is one of name and createTime, and order is asc or desc
 func (api *API) ListApplication(c *common.Context) (interface{}, error) {
 	ns := c.GetNamespace()
 	params, err := api.ParseListOptionsAppendSystemLabel(c)
 	if err != nil {
 		return nil, err
 	}
+	if err = params.CheckCursorPaging(); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err = params.CheckSort(models.SortByName, models.SortByCreateTime); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
 	apps, err := api.App.List(ns, params)
 	if err != nil {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
@@ -74,10 +85,14 @@ func (api *API) CreateApplication(c *common.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	return api.createApplication(c, appView)
+}
+
+func (api *API) createApplication(c *common.Context, appView *models.ApplicationView) (interface{}, error) {
 	ns, name := c.GetNamespace(), appView.Name
 	appView.Namespace = ns
 
-	err = api.validApplication(ns, appView)
+	err := api.validApplication(ns, appView)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +121,41 @@ func (api *API) CreateApplication(c *common.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = api.mountAutoSecrets(ns, appView, app); err != nil {
+		return nil, err
+	}
+	if err = api.applyAppLimits(ns, app); err != nil {
+		return nil, err
+	}
+	if err = api.checkAppHostPorts(ns, app); err != nil {
+		return nil, err
+	}
+	warnings, err := api.checkAppSelector(c, ns, app, nil)
+	if err != nil {
+		return nil, err
+	}
+	capacityWarnings, err := api.checkAppCapacity(c, ns, app)
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, capacityWarnings...)
+
+	if isDryRun(c) {
+		pending := configNames(configs)
+		if baseApp != nil {
+			app.Services = append(baseApp.Services, app.Services...)
+			app.Volumes = append(baseApp.Volumes, app.Volumes...)
+			// the configs of a base app in another namespace are copied along with the app
+			if baseApp.Namespace != ns {
+				for _, v := range baseApp.Volumes {
+					if v.Config != nil {
+						pending = append(pending, v.Config.Name)
+					}
+				}
+			}
+		}
+		return api.dryRunApplication(ns, nil, app, configs, pending, warnings)
+	}
 
 	if f, exist := api.Hooks[HookCreateApplicationOta]; exist {
 		if hk, ok := f.(CreateApplicationOta); ok {
@@ -122,7 +172,7 @@ func (api *API) CreateApplication(c *common.Context) (interface{}, error) {
 		return nil, err
 	}
 
-	return api.ToApplicationView(app)
+	return api.toApplicationViewWithWarnings(app, warnings)
 }
 
 // UpdateApplication update the application
@@ -149,6 +199,23 @@ func (api *API) UpdateApplication(c *common.Context) (interface{}, error) {
 		return nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", oldApp.Name))
 	}
 
+	if err = checkResourceVersion(c, common.APP, oldApp.Name, oldApp.Version); err != nil {
+		return nil, err
+	}
+
+	if err = api.Facade.CheckAppCanary(ns, oldApp); err != nil {
+		return nil, err
+	}
+	canary := appView.Strategy != nil && appView.Strategy.Type == models.AppStrategyCanary
+	if canary {
+		if err = api.CheckFeature(ns, models.FeatureCanary); err != nil {
+			return nil, err
+		}
+		if err = validAppCanary(oldApp, appView); err != nil {
+			return nil, err
+		}
+	}
+
 	// labels and Selector can't be modified of sys apps
 	if CheckIsSysResources(oldApp.Labels) &&
 		(oldApp.Selector != appView.Selector || !reflect.DeepEqual(oldApp.Labels, appView.Labels) || !appView.System) {
@@ -169,9 +236,35 @@ func (api *API) UpdateApplication(c *common.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	// the app given its own selector is no longer deployed to the node group
+	if app.Selector != oldApp.Selector {
+		delete(app.Labels, common.LabelNodeGroup)
+	}
+	if err = api.mountAutoSecrets(ns, appView, app); err != nil {
+		return nil, err
+	}
+	if err = api.applyAppLimits(ns, app); err != nil {
+		return nil, err
+	}
 
 	// ota can not modify
 	app.Ota = oldApp.Ota
+	if err = api.checkAppHostPorts(ns, app); err != nil {
+		return nil, err
+	}
+	warnings, err := api.checkAppSelector(c, ns, app, oldApp)
+	if err != nil {
+		return nil, err
+	}
+	capacityWarnings, err := api.checkAppCapacity(c, ns, app)
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, capacityWarnings...)
+
+	if isDryRun(c) {
+		return api.dryRunApplication(ns, oldApp, app, configs, configNames(configs), warnings)
+	}
 
 	if f, exist := api.Hooks[HookUpdateApplicationOta]; exist {
 		if hk, ok := f.(UpdateApplicationOta); ok {
@@ -182,6 +275,84 @@ func (api *API) UpdateApplication(c *common.Context) (interface{}, error) {
 		}
 	}
 
+	if canary {
+		var appCanary *models.AppCanary
+		app, appCanary, err = api.Facade.UpdateAppCanary(ns, oldApp, app, appView.Strategy.Percent)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		view, err := api.toApplicationViewWithWarnings(app, warnings)
+		if err != nil {
+			return nil, err
+		}
+		view.Canary = appCanary
+		return view, nil
+	}
+
+	app, err = api.Facade.UpdateApp(ns, oldApp, app, configs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return api.toApplicationViewWithWarnings(app, warnings)
+}
+
+func (api *API) toApplicationViewWithWarnings(app *specV1.Application, warnings []string) (*models.ApplicationView, error) {
+	view, err := api.ToApplicationView(app)
+	if err != nil {
+		return nil, err
+	}
+	view.Warnings = warnings
+	return view, nil
+}
+
+// RollbackApplication roll back the application to a historical version, which is saved as a new version
+func (api *API) RollbackApplication(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.AppRollback{}
+	if err := c.LoadBody(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+
+	oldApp, err := api.App.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// sys app: core、init、function is not visible
+	if common.ValidIsInvisible(oldApp.Labels) {
+		return nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", oldApp.Name))
+	}
+
+	if oldApp.CronStatus == specV1.CronWait {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "failed to roll back, the app has a cron job waiting to be deployed"))
+	}
+
+	if err = api.Facade.CheckAppCanary(ns, oldApp); err != nil {
+		return nil, err
+	}
+
+	if params.Version == oldApp.Version {
+		return api.ToApplicationView(oldApp)
+	}
+
+	app, err := api.App.GetHistory(ns, name, params.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := api.checkAppDependencies(ns, app, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	app.Version = oldApp.Version
+	app.CreationTimestamp = oldApp.CreationTimestamp
+	app.CronStatus = specV1.CronNotSet
+	app.CronTime = oldApp.CronTime
+	// ota can not modify
+	app.Ota = oldApp.Ota
+
 	app, err = api.Facade.UpdateApp(ns, oldApp, app, configs)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -190,16 +361,174 @@ func (api *API) UpdateApplication(c *common.Context) (interface{}, error) {
 	return api.ToApplicationView(app)
 }
 
-// DeleteApplication delete the application
+// checkAppDependencies checks that the configs, secrets and registries referenced by the app still exist,
+// sets their latest versions except the pinned versions of the configs, and returns the generated configs of function services which must be kept by the app.
+// The pending configs and secrets are going to be created along with the app, so they are not required to exist.
+func (api *API) checkAppDependencies(ns string, app *specV1.Application, pendingConfigs, pendingSecrets []string) ([]specV1.Configuration, error) {
+	pending := map[string]bool{}
+	for _, name := range pendingConfigs {
+		pending["config/"+name] = true
+	}
+	for _, name := range pendingSecrets {
+		pending["secret/"+name] = true
+	}
+	mounted := map[string]bool{}
+	for _, services := range [][]specV1.Service{app.InitServices, app.Services} {
+		for _, s := range services {
+			for _, vm := range s.VolumeMounts {
+				mounted[vm.Name] = true
+			}
+		}
+	}
+
+	var configs []specV1.Configuration
+	var missing []string
+	for _, v := range app.Volumes {
+		if v.Config != nil {
+			if pending["config/"+v.Config.Name] {
+				continue
+			}
+			// the pinned version of the config is kept as given if it is still available
+			if v.Config.Version != "" && service.IsConfigPinned(app, v.Config.Name) {
+				cfg, err := api.Config.GetVersion(ns, v.Config.Name, v.Config.Version)
+				if err != nil {
+					if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+						missing = append(missing, fmt.Sprintf("config(%s@%s)", v.Config.Name, v.Config.Version))
+						continue
+					}
+					return nil, err
+				}
+				if strings.HasPrefix(cfg.Name, FunctionConfigPrefix) || strings.HasPrefix(cfg.Name, FunctionProgramConfigPrefix) {
+					configs = append(configs, *cfg)
+				}
+				continue
+			}
+			cfg, err := api.Config.Get(nil, ns, v.Config.Name, "")
+			if err != nil {
+				if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+					missing = append(missing, fmt.Sprintf("config(%s)", v.Config.Name))
+					continue
+				}
+				return nil, err
+			}
+			v.Config.Version = cfg.Version
+			if strings.HasPrefix(cfg.Name, FunctionConfigPrefix) || strings.HasPrefix(cfg.Name, FunctionProgramConfigPrefix) {
+				configs = append(configs, *cfg)
+			}
+		}
+		if v.Secret != nil {
+			if pending["secret/"+v.Secret.Name] {
+				continue
+			}
+			secret, err := api.Secret.Get(ns, v.Secret.Name, "")
+			if err != nil {
+				if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+					// registries are added as secret volumes named after the registry and never mounted
+					kind := "secret"
+					if v.Name == v.Secret.Name && !mounted[v.Name] {
+						kind = "registry"
+					}
+					missing = append(missing, fmt.Sprintf("%s(%s)", kind, v.Secret.Name))
+					continue
+				}
+				return nil, err
+			}
+			v.Secret.Version = secret.Version
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, common.Error(common.ErrAppDependencyMissing,
+			common.Field("name", app.Name),
+			common.Field("version", app.Version),
+			common.Field("missing", strings.Join(missing, ", ")))
+	}
+	return configs, nil
+}
+
+// dryRunApplication validates the referenced resources of the rendered app and
+// returns it with a diff against the current version, nothing is persisted
+func (api *API) dryRunApplication(ns string, oldApp, app *specV1.Application, configs []specV1.Configuration, pending, warnings []string) (*models.ApplicationDryRun, error) {
+	app.Namespace = ns
+	if _, err := api.checkAppDependencies(ns, app, pending, nil); err != nil {
+		return nil, err
+	}
+	diff, err := diffApplication(oldApp, app)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ApplicationDryRun{
+		Application: app,
+		Configs:     configs,
+		Diff:        diff,
+		Warnings:    warnings,
+	}, nil
+}
+
+// diffApplication returns the unified diff between two versions of the app, a nil app is treated as empty
+func diffApplication(from, to *specV1.Application) (string, error) {
+	fromName, fromText, err := appDiffText(from)
+	if err != nil {
+		return "", err
+	}
+	toName, toText, err := appDiffText(to)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromText),
+		B:        difflib.SplitLines(toText),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+}
+
+func appDiffText(app *specV1.Application) (string, string, error) {
+	if app == nil {
+		return "/dev/null", "", nil
+	}
+	res := *app
+	// the update time is refreshed on every save, it is not a change of the spec
+	res.UpdateTime = time.Time{}
+	data, err := yaml.Marshal(&res)
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	return fmt.Sprintf("%s@%s", res.Name, res.Version), string(data), nil
+}
+
+func configNames(configs []specV1.Configuration) []string {
+	names := make([]string, 0, len(configs))
+	for _, cfg := range configs {
+		names = append(names, cfg.Name)
+	}
+	return names
+}
+
+func isDryRun(c *common.Context) bool {
+	res, _ := strconv.ParseBool(c.Query("dryRun"))
+	return res
+}
+
+// DeleteApplication delete the application, the configs and secrets referenced only by the application
+// are deleted along if cascade is set, and the deleted and shared ones are returned
 func (api *API) DeleteApplication(c *common.Context) (interface{}, error) {
 	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	cascade, err := api.parseCascade(c)
+	if err != nil {
+		return nil, err
+	}
 	app, err := api.App.Get(ns, name, "")
 	if err != nil {
 		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
-			return nil, nil
+			return nil, checkIfMatch(c, common.APP, name, "")
 		}
 		return nil, err
 	}
+	if err = checkIfMatch(c, common.APP, name, app.Version); err != nil {
+		return nil, err
+	}
 
 	if canDelete, err := api.IsAppCanDelete(ns, name); err != nil {
 		return nil, err
@@ -216,8 +545,11 @@ func (api *API) DeleteApplication(c *common.Context) (interface{}, error) {
 		}
 	}
 
-	err = api.Facade.DeleteApp(ns, name, app)
-	return nil, err
+	err = api.deleteApp(ns, app)
+	if err != nil || !cascade {
+		return nil, err
+	}
+	return api.deleteAppReferences(ns, app), nil
 }
 
 func (api *API) GetSysAppConfigs(c *common.Context) (interface{}, error) {
@@ -242,8 +574,20 @@ func (api *API) GetSysAppConfigs(c *common.Context) (interface{}, error) {
 	return list, err
 }
 
+// GetSysAppSecrets lists the secrets labeled for the app, which are the ones mounted automatically
+// if the app mounts the secrets by convention
 func (api *API) GetSysAppSecrets(c *common.Context) (interface{}, error) {
-	res, err := api.getNodeSysAppSecretLikedResources(c)
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	app, err := api.App.Get(ns, n, "")
+	if err != nil {
+		return nil, err
+	}
+	var res *models.SecretList
+	if isAutoMountSecrets(app.Labels) {
+		res, err = api.listAutoMountSecrets(ns, n, CheckIsSysResources(app.Labels))
+	} else {
+		res, err = api.Secret.List(ns, &models.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", BaetylAppNameKey, n)})
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -278,38 +622,46 @@ func (api *API) ParseApplication(c *common.Context) (*models.ApplicationView, er
 	if name := c.GetNameFromParam(); name != "" {
 		app.Name = name
 	}
+	if err = api.checkApplicationView(app); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// checkApplicationView checks the name, type, services and workload of the application
+func (api *API) checkApplicationView(app *models.ApplicationView) error {
 	if app.Name == "" {
-		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
 	}
 	if len(app.Name) > AppNameMaxLength {
-		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is too long"))
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is too long"))
 	}
 	if app.Type == specV1.AppTypeContainer {
 		app.ImageTrim()
 		for _, v := range app.Services {
 			if v.FunctionConfig != nil || v.Functions != nil {
-				return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "add function info in container app"))
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error", "add function info in container app"))
 			}
 			if app.Mode == context.RunModeKube && v.Image == "" {
-				return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "image is required in kube mode"))
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error", "image is required in kube mode"))
 			}
 			if app.Mode == context.RunModeNative && v.ProgramConfig == "" {
-				return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "program config is required in native mode"))
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error", "program config is required in native mode"))
 			}
 		}
 	} else if app.Type == specV1.AppTypeFunction {
 		for _, v := range app.Services {
 			if v.FunctionConfig == nil {
-				return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "function config can't be empty in function app"))
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error", "function config can't be empty in function app"))
 			}
 		}
 		if len(app.Registries) != 0 {
-			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "registries should be empty in function app"))
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", "registries should be empty in function app"))
 		}
 	} else if app.Type == specV1.AppTypeHelm || app.Type == specV1.AppTypeYaml {
-		return app, nil
+		return nil
 	} else {
-		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "type is invalid"))
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "type is invalid"))
 	}
 
 	// multi-container compatibility
@@ -319,10 +671,10 @@ func (api *API) ParseApplication(c *common.Context) (*models.ApplicationView, er
 		app.Workload != specV1.WorkloadDaemonSet &&
 		app.Workload != specV1.WorkloadStatefulSet &&
 		app.Workload != specV1.WorkloadJob {
-		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error",
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error",
 			"failed to parse service type, service type should be deployment / daemonset / statefulset / job"))
 	}
-	return app, nil
+	return checkAutoSecretVolumes(app)
 }
 
 func (api *API) getBaseAppIfSet(c *common.Context) (*specV1.Application, error) {
@@ -342,6 +694,7 @@ func (api *API) ParseListOptions(c *common.Context) (*models.ListOptions, error)
 	if err := c.Bind(params); err != nil {
 		return nil, err
 	}
+	_, params.CursorPaging = c.GetQuery("cursor")
 	return params, nil
 }
 
@@ -375,6 +728,8 @@ func (api *API) ToApplicationView(app *specV1.Application) (*models.ApplicationV
 	appView := &models.ApplicationView{}
 	copier.Copy(appView, app)
 
+	hideAutoSecrets(app, appView)
+	translateVolumesToEnvSources(app, appView)
 	err := api.translateSecretsToSecretLikedResources(appView)
 	if err != nil {
 		return nil, err
@@ -457,6 +812,7 @@ func (api *API) ToApplication(appView *models.ApplicationView, oldApp *specV1.Ap
 	copier.Copy(app, appView)
 
 	translateSecretLikedModelsToSecrets(appView, app)
+	translateEnvSourcesToVolumes(appView, app)
 	translateNativeApp(appView, app)
 
 	if app.Type != specV1.AppTypeFunction {
@@ -635,6 +991,150 @@ func translateSecretLikedModelsToSecrets(appView *models.ApplicationView, app *s
 	}
 }
 
+// translateEnvSourcesToVolumes adds the referenced secrets and configs as volumes, and keeps the references of the
+// environment variables as the mounts of the volumes, the variables are kept with the empty values. The volumes are
+// synchronized like the mounted ones, so that the updates of the secrets and configs bump the version of the app,
+// and the references are resolved into the values when the app is synced to the nodes
+func translateEnvSourcesToVolumes(appView *models.ApplicationView, app *specV1.Application) {
+	var volumes []specV1.Volume
+	for _, v := range app.Volumes {
+		if !models.IsEnvSourceVolume(v.Name) {
+			volumes = append(volumes, v)
+		}
+	}
+	app.Volumes = volumes
+	added := map[string]bool{}
+	translate := func(views []models.ServiceView, services []specV1.Service) {
+		for i := range views {
+			services[i].VolumeMounts = withoutEnvSourceMounts(services[i].VolumeMounts)
+			if len(views[i].Env) == 0 {
+				services[i].Env = views[i].Service.Env
+				continue
+			}
+			services[i].Env = make([]specV1.Environment, 0, len(views[i].Env))
+			for _, env := range views[i].Env {
+				services[i].Env = append(services[i].Env, specV1.Environment{Name: env.Name, Value: env.Value})
+				if env.ValueFrom == nil {
+					continue
+				}
+				services[i].Env[len(services[i].Env)-1].Value = ""
+				name := env.ValueFrom.VolumeName()
+				services[i].VolumeMounts = append(services[i].VolumeMounts, specV1.VolumeMount{
+					Name:      name,
+					MountPath: env.Name,
+					SubPath:   env.ValueFrom.Key,
+					ReadOnly:  true,
+				})
+				if added[name] {
+					continue
+				}
+				added[name] = true
+				volume := specV1.Volume{Name: name}
+				if env.ValueFrom.Secret != "" {
+					volume.Secret = &specV1.ObjectReference{Name: env.ValueFrom.Secret}
+				} else {
+					volume.Config = &specV1.ObjectReference{Name: env.ValueFrom.Config}
+				}
+				app.Volumes = append(app.Volumes, volume)
+			}
+		}
+	}
+	translate(appView.InitServices, app.InitServices)
+	translate(appView.Services, app.Services)
+}
+
+// translateVolumesToEnvSources reads the references of the environment variables from the mounts of the volumes
+// added for them, and hides the volumes and the mounts
+func translateVolumesToEnvSources(app *specV1.Application, appView *models.ApplicationView) {
+	sources := map[string]*models.EnvVarSource{}
+	for _, v := range app.Volumes {
+		if !models.IsEnvSourceVolume(v.Name) {
+			continue
+		}
+		if v.Secret != nil {
+			sources[v.Name] = &models.EnvVarSource{Secret: v.Secret.Name}
+		} else if v.Config != nil {
+			sources[v.Name] = &models.EnvVarSource{Config: v.Config.Name}
+		}
+	}
+	translate := func(services []specV1.Service, views []models.ServiceView) {
+		for i := range services {
+			refs := map[string]*models.EnvVarSource{}
+			for _, vm := range services[i].VolumeMounts {
+				if source, ok := sources[vm.Name]; ok {
+					refs[vm.MountPath] = &models.EnvVarSource{Secret: source.Secret, Config: source.Config, Key: vm.SubPath}
+				}
+			}
+			views[i].Service.Env = nil
+			views[i].Env = nil
+			for _, env := range services[i].Env {
+				view := models.EnvironmentView{Name: env.Name, Value: env.Value}
+				if ref, ok := refs[env.Name]; ok {
+					view.Value, view.ValueFrom = "", ref
+				}
+				views[i].Env = append(views[i].Env, view)
+			}
+			views[i].VolumeMounts = withoutEnvSourceMounts(views[i].VolumeMounts)
+		}
+	}
+	translate(app.InitServices, appView.InitServices)
+	translate(app.Services, appView.Services)
+
+	volumes := make([]models.VolumeView, 0, len(appView.Volumes))
+	for _, v := range appView.Volumes {
+		if models.IsEnvSourceVolume(v.Name) {
+			continue
+		}
+		volumes = append(volumes, v)
+	}
+	appView.Volumes = volumes
+}
+
+func withoutEnvSourceMounts(mounts []specV1.VolumeMount) []specV1.VolumeMount {
+	var res []specV1.VolumeMount
+	for _, vm := range mounts {
+		if !models.IsEnvSourceVolume(vm.Name) {
+			res = append(res, vm)
+		}
+	}
+	return res
+}
+
+// validEnvSources checks the secrets and configs referenced by the environment variables contain the keys
+func (api *API) validEnvSources(namespace string, services []models.ServiceView) error {
+	for _, service := range services {
+		for _, env := range service.Env {
+			source := env.ValueFrom
+			if source == nil {
+				continue
+			}
+			if (source.Secret == "") == (source.Config == "") {
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error",
+					fmt.Sprintf("exactly one of secret and config should be referenced by the environment variable (%s) of the service (%s)", env.Name, service.Name)))
+			}
+			var exist bool
+			if source.Secret != "" {
+				secret, err := api.Secret.Get(namespace, source.Secret, "")
+				if err != nil {
+					return err
+				}
+				_, exist = secret.Data[source.Key]
+			} else {
+				config, err := api.Config.Get(nil, namespace, source.Config, "")
+				if err != nil {
+					return err
+				}
+				_, exist = config.Data[source.Key]
+			}
+			if !exist {
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error",
+					fmt.Sprintf("the key (%s) referenced by the environment variable (%s) of the service (%s) does not exist", source.Key, env.Name, service.Name)))
+			}
+		}
+	}
+	return nil
+}
+
 func (api *API) translateSecretsToSecretLikedResources(appView *models.ApplicationView) error {
 	appView.Registries = make([]models.RegistryView, 0)
 	volumes := make([]models.VolumeView, 0)
@@ -709,6 +1209,13 @@ func (api *API) validApplication(namespace string, app *models.ApplicationView)
 		}
 	}
 
+	if err := api.validEnvSources(namespace, app.InitServices); err != nil {
+		return err
+	}
+	if err := api.validEnvSources(namespace, app.Services); err != nil {
+		return err
+	}
+
 	tcpPorts := make(map[int32]bool)
 	updPorts := make(map[int32]bool)
 	for _, service := range app.Services {
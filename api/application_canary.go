@@ -0,0 +1,115 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// PromoteApplication advances the canary of the application to more nodes, or to all nodes to finish it
+func (api *API) PromoteApplication(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.AppPromote{}
+	if err := c.LoadBody(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+
+	app, canary, err := api.getAppWithCanary(ns, name)
+	if err != nil {
+		return nil, err
+	}
+	if params.Percent != 0 && params.Percent < 100 && params.Percent <= canary.Percent {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the percent should be greater than the current percent of the canary"))
+	}
+
+	canary, err = api.Facade.PromoteAppCanary(ns, app, canary, params.Percent)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	view, err := api.ToApplicationView(app)
+	if err != nil {
+		return nil, err
+	}
+	view.Canary = canary
+	return view, nil
+}
+
+// AbortApplication aborts the canary of the application, all nodes are rolled back to the stable version
+func (api *API) AbortApplication(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	oldApp, canary, err := api.getAppWithCanary(ns, name)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := api.App.GetHistory(ns, name, canary.StableVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	app.Version = oldApp.Version
+	app.CreationTimestamp = oldApp.CreationTimestamp
+	app.CronStatus = specV1.CronNotSet
+	app.CronTime = oldApp.CronTime
+	// ota can not modify
+	app.Ota = oldApp.Ota
+
+	app, err = api.Facade.AbortAppCanary(ns, app)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return api.ToApplicationView(app)
+}
+
+// getAppWithCanary returns the application and its canary in progress
+func (api *API) getAppWithCanary(ns, name string) (*specV1.Application, *models.AppCanary, error) {
+	app, err := api.App.Get(ns, name, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	// sys app: core、init、function is not visible
+	if common.ValidIsInvisible(app.Labels) {
+		return nil, nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", app.Name))
+	}
+	canary, err := api.getAppCanary(ns, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if canary == nil || canary.Version != app.Version {
+		return nil, nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the app has no canary in progress"))
+	}
+	return app, canary, nil
+}
+
+// getAppCanary returns the canary in progress of the application, or nil if there is none
+func (api *API) getAppCanary(ns, name string) (*models.AppCanary, error) {
+	canary, err := api.App.GetCanary(ns, name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return canary, nil
+}
+
+// validAppCanary checks that the update of the application can be rolled out as a canary
+func validAppCanary(oldApp *specV1.Application, appView *models.ApplicationView) error {
+	if appView.Strategy.Percent == 0 {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "the percent of the canary is required"))
+	}
+	if appView.Type == specV1.AppTypeFunction {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "the canary of function apps is not supported"))
+	}
+	if appView.CronStatus == specV1.CronWait {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "the canary of apps with a cron job is not supported"))
+	}
+	if appView.Selector != oldApp.Selector {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "the selector can't be modified by a canary"))
+	}
+	return nil
+}
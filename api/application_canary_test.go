@@ -0,0 +1,212 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestUpdateApplicationCanary(t *testing.T) {
+	api, router, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	fApp := mf.NewMockFacade(mockCtl)
+	api.Facade = fApp
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	api.NS = sNS
+	sNS.EXPECT().GetSettings(gomock.Any()).Return(&models.NamespaceSettings{}, nil).AnyTimes()
+
+	curApp := getMockContainerApp()
+	curApp.Version = "2"
+	canaryEnabled := true
+	sNS.EXPECT().IsFeatureEnabled(curApp.Namespace, models.FeatureCanary).DoAndReturn(func(string, string) (bool, error) {
+		return canaryEnabled, nil
+	}).AnyTimes()
+	sConfig.EXPECT().Get(nil, gomock.Any(), gomock.Any(), "").Return(&specV1.Configuration{Name: "agent-conf"}, nil).AnyTimes()
+	sSecret.EXPECT().Get(gomock.Any(), "secret01", gomock.Any()).Return(&specV1.Secret{Name: "secret01"}, nil).AnyTimes()
+	sSecret.EXPECT().Get(gomock.Any(), "registry01", gomock.Any()).Return(&specV1.Secret{Name: "registry01",
+		Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry}}, nil).AnyTimes()
+	sApp.EXPECT().Get(curApp.Namespace, "abc", "").Return(curApp, nil).AnyTimes()
+
+	update := func(app *specV1.Application, strategy *models.AppStrategy) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(struct {
+			*specV1.Application
+			Strategy *models.AppStrategy `json:"strategy,omitempty"`
+		}{app, strategy})
+		req, _ := http.NewRequest(http.MethodPut, "/v1/apps/abc", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	newApp := getMockContainerApp()
+	newApp.Services[0].Image = "image:v3"
+
+	// 200 rolled out to 10% of the nodes
+	fApp.EXPECT().CheckAppCanary(curApp.Namespace, curApp).Return(nil).Times(1)
+	fApp.EXPECT().UpdateAppCanary(curApp.Namespace, curApp, gomock.Any(), 10).DoAndReturn(
+		func(ns string, _, app *specV1.Application, percent int) (*specV1.Application, *models.AppCanary, error) {
+			assert.Equal(t, "image:v3", app.Services[0].Image)
+			res := *app
+			res.Version = "3"
+			return &res, &models.AppCanary{Name: "abc", Namespace: ns, Version: "3", StableVersion: "2", Percent: percent, Nodes: []string{"n1"}}, nil
+		}).Times(1)
+	w := update(newApp, &models.AppStrategy{Type: models.AppStrategyCanary, Percent: 10})
+	assert.Equal(t, http.StatusOK, w.Code)
+	view := &models.ApplicationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, "3", view.Version)
+	assert.Equal(t, "2", view.Canary.StableVersion)
+	assert.Equal(t, []string{"n1"}, view.Canary.Nodes)
+
+	// 403 the canary feature is disabled in the namespace
+	canaryEnabled = false
+	fApp.EXPECT().CheckAppCanary(curApp.Namespace, curApp).Return(nil).Times(1)
+	w = update(newApp, &models.AppStrategy{Type: models.AppStrategyCanary, Percent: 10})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "The feature (canary) is not enabled in the namespace.")
+	canaryEnabled = true
+
+	// 400 invalid percent
+	w = update(newApp, &models.AppStrategy{Type: models.AppStrategyCanary, Percent: 100})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 400 percent is required
+	fApp.EXPECT().CheckAppCanary(curApp.Namespace, curApp).Return(nil).Times(1)
+	w = update(newApp, &models.AppStrategy{Type: models.AppStrategyCanary})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the percent of the canary is required")
+
+	// 400 selector can't be modified
+	selApp := getMockContainerApp()
+	selApp.Selector = "a=b"
+	fApp.EXPECT().CheckAppCanary(curApp.Namespace, curApp).Return(nil).Times(1)
+	w = update(selApp, &models.AppStrategy{Type: models.AppStrategyCanary, Percent: 10})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the selector can't be modified by a canary")
+
+	// 400 a canary is in progress
+	fApp.EXPECT().CheckAppCanary(curApp.Namespace, curApp).Return(common.Error(common.ErrRequestParamInvalid,
+		common.Field("error", "the app (abc) has a canary in progress, promote or abort the canary first"))).Times(2)
+	w = update(newApp, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "promote or abort the canary first")
+	body, _ := json.Marshal(&models.AppRollback{Version: "1"})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 200 no canary in progress
+	fApp.EXPECT().CheckAppCanary(curApp.Namespace, curApp).Return(nil).Times(1)
+	fApp.EXPECT().UpdateApp(curApp.Namespace, curApp, gomock.Any(), gomock.Any()).Return(curApp, nil).Times(1)
+	w = update(newApp, &models.AppStrategy{Type: models.AppStrategyRolling})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPromoteAndAbortApplication(t *testing.T) {
+	api, router, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	fApp := mf.NewMockFacade(mockCtl)
+	api.Facade = fApp
+
+	curApp := getMockContainerApp()
+	curApp.Version = "3"
+	curApp.Services[0].Image = "image:v3"
+	stable := getMockContainerApp()
+	stable.Version = "2"
+	stable.Services[0].Image = "image:v2"
+	canary := &models.AppCanary{Name: "abc", Namespace: curApp.Namespace, Version: "3", StableVersion: "2", Percent: 10, Nodes: []string{"n1"}}
+	sSecret.EXPECT().Get(gomock.Any(), "secret01", gomock.Any()).Return(&specV1.Secret{Name: "secret01"}, nil).AnyTimes()
+	sSecret.EXPECT().Get(gomock.Any(), "registry01", gomock.Any()).Return(&specV1.Secret{Name: "registry01",
+		Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry}}, nil).AnyTimes()
+	sApp.EXPECT().Get(curApp.Namespace, "abc", "").Return(curApp, nil).AnyTimes()
+
+	post := func(action string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, "/v1/apps/abc/"+action, bytes.NewReader(data))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// 400 no canary in progress
+	sApp.EXPECT().GetCanary(curApp.Namespace, "abc").Return(nil, common.Error(common.ErrResourceNotFound)).Times(2)
+	w := post("promote", &models.AppPromote{Percent: 50})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the app has no canary in progress")
+	w = post("abort", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 400 the percent is not advanced
+	sApp.EXPECT().GetCanary(curApp.Namespace, "abc").Return(canary, nil).Times(1)
+	w = post("promote", &models.AppPromote{Percent: 10})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 200 advanced to 50%
+	sApp.EXPECT().GetCanary(curApp.Namespace, "abc").Return(canary, nil).Times(1)
+	fApp.EXPECT().PromoteAppCanary(curApp.Namespace, curApp, canary, 50).Return(
+		&models.AppCanary{Name: "abc", Version: "3", StableVersion: "2", Percent: 50, Nodes: []string{"n1", "n2"}}, nil).Times(1)
+	w = post("promote", &models.AppPromote{Percent: 50})
+	assert.Equal(t, http.StatusOK, w.Code)
+	view := &models.ApplicationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, 50, view.Canary.Percent)
+	assert.Equal(t, []string{"n1", "n2"}, view.Canary.Nodes)
+
+	// 200 fully promoted
+	sApp.EXPECT().GetCanary(curApp.Namespace, "abc").Return(canary, nil).Times(1)
+	fApp.EXPECT().PromoteAppCanary(curApp.Namespace, curApp, canary, 0).Return(nil, nil).Times(1)
+	w = post("promote", &models.AppPromote{})
+	assert.Equal(t, http.StatusOK, w.Code)
+	view = &models.ApplicationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Nil(t, view.Canary)
+
+	// 200 aborted, rolled back to the stable version as a new version
+	sApp.EXPECT().GetCanary(curApp.Namespace, "abc").Return(canary, nil).Times(1)
+	sApp.EXPECT().GetHistory(curApp.Namespace, "abc", "2").Return(stable, nil).Times(1)
+	fApp.EXPECT().AbortAppCanary(curApp.Namespace, gomock.Any()).DoAndReturn(
+		func(_ string, app *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, "3", app.Version)
+			assert.Equal(t, "image:v2", app.Services[0].Image)
+			res := *app
+			res.Version = "4"
+			return &res, nil
+		}).Times(1)
+	w = post("abort", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	view = &models.ApplicationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, "4", view.Version)
+	assert.Equal(t, "image:v2", view.Services[0].Image)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/facade"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// parseCascade returns whether the configs and secrets referenced only by the app are deleted along,
+// which is not supported if the deleted apps are kept in the trash to be restored
+func (api *API) parseCascade(c *common.Context) (bool, error) {
+	str := c.Query("cascade")
+	if str == "" {
+		return false, nil
+	}
+	cascade, err := strconv.ParseBool(str)
+	if err != nil {
+		return false, common.Error(common.ErrRequestParamInvalid, common.Field("cascade", str))
+	}
+	if cascade && api.appTrashRetention > 0 {
+		return false, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "cascade is not supported since the deleted apps are kept in the trash"))
+	}
+	return cascade, nil
+}
+
+// appReference the way to check and delete a config or secret referenced by the deleted app
+type appReference struct {
+	typ       common.Resource
+	names     map[string]bool
+	getLabels func(ns, name string) (map[string]string, error)
+	listApps  func(ns, name string) ([]string, error)
+	delete    func(ns, name string) error
+	deleted   *[]string
+	shared    *[]string
+}
+
+// deleteAppReferences deletes the configs and secrets of the volumes of the deleted app which are not referenced by other apps.
+// The system ones are kept unless the app is a system one, and the generated configs of functions are cleaned with the app
+func (api *API) deleteAppReferences(ns string, app *specV1.Application) *models.ApplicationCascadeDeletion {
+	res := &models.ApplicationCascadeDeletion{
+		DeletedConfigs: []string{},
+		DeletedSecrets: []string{},
+		SharedConfigs:  []string{},
+		SharedSecrets:  []string{},
+	}
+	configs, secrets := map[string]bool{}, map[string]bool{}
+	for _, v := range app.Volumes {
+		if v.Config != nil && !strings.HasPrefix(v.Config.Name, facade.FunctionConfigPrefix) &&
+			!strings.HasPrefix(v.Config.Name, facade.FunctionProgramConfigPrefix) {
+			configs[v.Config.Name] = true
+		}
+		if v.Secret != nil {
+			secrets[v.Secret.Name] = true
+		}
+	}
+	refs := []*appReference{
+		{
+			typ:   common.Config,
+			names: configs,
+			getLabels: func(ns, name string) (map[string]string, error) {
+				cfg, err := api.Config.Get(nil, ns, name, "")
+				if err != nil {
+					return nil, err
+				}
+				return cfg.Labels, nil
+			},
+			listApps: api.Index.ListAppIndexByConfig,
+			delete:   api.Facade.DeleteConfig,
+			deleted:  &res.DeletedConfigs,
+			shared:   &res.SharedConfigs,
+		},
+		{
+			typ:   common.Secret,
+			names: secrets,
+			getLabels: func(ns, name string) (map[string]string, error) {
+				secret, err := api.Secret.Get(ns, name, "")
+				if err != nil {
+					return nil, err
+				}
+				return secret.Labels, nil
+			},
+			listApps: api.Index.ListAppIndexBySecret,
+			delete:   api.Facade.DeleteSecret,
+			deleted:  &res.DeletedSecrets,
+			shared:   &res.SharedSecrets,
+		},
+	}
+	isSys := CheckIsSysResources(app.Labels)
+	for _, ref := range refs {
+		for _, name := range sortedKeys(ref.names) {
+			err := api.deleteAppReference(ns, name, isSys, ref)
+			if err != nil {
+				log.L().Error("failed to delete the resource referenced by the app", log.Any("namespace", ns),
+					log.Any("app", app.Name), log.Any("type", ref.typ), log.Any("name", name), log.Error(err))
+				res.Failed = append(res.Failed, string(ref.typ)+"/"+name)
+			}
+		}
+	}
+	return res
+}
+
+func (api *API) deleteAppReference(ns, name string, isSys bool, ref *appReference) error {
+	labels, err := ref.getLabels(ns, name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil
+		}
+		return err
+	}
+	apps, err := ref.listApps(ns, name)
+	if err != nil {
+		return err
+	}
+	if len(apps) > 0 || (!isSys && CheckIsSysResources(labels)) {
+		*ref.shared = append(*ref.shared, name)
+		return nil
+	}
+	if err = ref.delete(ns, name); err != nil {
+		return err
+	}
+	*ref.deleted = append(*ref.deleted, name)
+	return nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/facade"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestDeleteApplicationCascade(t *testing.T) {
+	api, router, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret}
+	sIndex := ms.NewMockIndexService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api.Facade, api.Index = fApp, sIndex
+
+	ns := "baetyl-cloud"
+	app := &specV1.Application{
+		Namespace: ns,
+		Name:      "abc",
+		Volumes: []specV1.Volume{
+			{Name: "v1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c1"}}},
+			{Name: "v2", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c2"}}},
+			{Name: "v3", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c3"}}},
+			{Name: "v4", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: facade.FunctionProgramConfigPrefix + "-abc"}}},
+			{Name: "v5", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s1"}}},
+			{Name: "v6", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s2"}}},
+			{Name: "v7", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s3"}}},
+			{Name: "v8", VolumeSource: specV1.VolumeSource{HostPath: &specV1.HostPathVolumeSource{Path: "/var"}}},
+		},
+	}
+
+	sApp.EXPECT().Get(ns, "abc", "").Return(app, nil)
+	fApp.EXPECT().DeleteApp(ns, "abc", app).Return(nil)
+	// c1 is referenced only by the app, c2 is shared and c3 is not found
+	sConfig.EXPECT().Get(nil, ns, "c1", "").Return(&specV1.Configuration{Name: "c1"}, nil)
+	sIndex.EXPECT().ListAppIndexByConfig(ns, "c1").Return([]string{}, nil)
+	fApp.EXPECT().DeleteConfig(ns, "c1").Return(nil)
+	sConfig.EXPECT().Get(nil, ns, "c2", "").Return(&specV1.Configuration{Name: "c2"}, nil)
+	sIndex.EXPECT().ListAppIndexByConfig(ns, "c2").Return([]string{"other"}, nil)
+	sConfig.EXPECT().Get(nil, ns, "c3", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	// s1 is referenced only by the app, s2 is a system one and s3 is failed to delete
+	sSecret.EXPECT().Get(ns, "s1", "").Return(&specV1.Secret{Name: "s1"}, nil)
+	sIndex.EXPECT().ListAppIndexBySecret(ns, "s1").Return(nil, nil)
+	fApp.EXPECT().DeleteSecret(ns, "s1").Return(nil)
+	sSecret.EXPECT().Get(ns, "s2", "").Return(&specV1.Secret{Name: "s2", Labels: map[string]string{common.LabelSystem: "true"}}, nil)
+	sIndex.EXPECT().ListAppIndexBySecret(ns, "s2").Return(nil, nil)
+	sSecret.EXPECT().Get(ns, "s3", "").Return(&specV1.Secret{Name: "s3"}, nil)
+	sIndex.EXPECT().ListAppIndexBySecret(ns, "s3").Return(nil, nil)
+	fApp.EXPECT().DeleteSecret(ns, "s3").Return(fmt.Errorf("error"))
+
+	req, _ := http.NewRequest(http.MethodDelete, "/v1/apps/abc?cascade=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.ApplicationCascadeDeletion{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, &models.ApplicationCascadeDeletion{
+		DeletedConfigs: []string{"c1"},
+		DeletedSecrets: []string{"s1"},
+		SharedConfigs:  []string{"c2"},
+		SharedSecrets:  []string{"s2"},
+		Failed:         []string{"secret/s3"},
+	}, res)
+
+	// the references are kept if the app is failed to delete
+	sApp.EXPECT().Get(ns, "abc", "").Return(app, nil)
+	fApp.EXPECT().DeleteApp(ns, "abc", app).Return(fmt.Errorf("error"))
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apps/abc?cascade=true", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apps/abc?cascade=yes", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// cascade is not supported with the trash
+	api.appTrashRetention = time.Hour
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apps/abc?cascade=true", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
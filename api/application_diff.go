@@ -0,0 +1,242 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// DiffApplication compares two historical versions of the application
+func (api *API) DiffApplication(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.AppDiffParams{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+
+	from, err := api.App.GetHistory(ns, name, params.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.App.GetHistory(ns, name, params.To)
+	if err != nil {
+		return nil, err
+	}
+	// sys app: core、init、function is not visible
+	if common.ValidIsInvisible(to.Labels) {
+		return nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", name))
+	}
+
+	diff, err := diffApplication(from, to)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := appJSONPatch(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ApplicationDiff{
+		Name:     name,
+		From:     params.From,
+		To:       params.To,
+		Services: diffAppServices(from, to),
+		Configs:  diffAppConfigs(from, to),
+		Patch:    patch,
+		Diff:     diff,
+	}, nil
+}
+
+func diffAppServices(from, to *specV1.Application) []models.ServiceDiff {
+	fromSvcs, toSvcs := appServices(from), appServices(to)
+	var res []models.ServiceDiff
+	for _, name := range unionKeys(fromSvcs, toSvcs) {
+		f, fok := fromSvcs[name]
+		t, tok := toSvcs[name]
+		switch {
+		case !fok:
+			res = append(res, models.ServiceDiff{Name: name, Change: models.DiffAdded})
+		case !tok:
+			res = append(res, models.ServiceDiff{Name: name, Change: models.DiffRemoved})
+		default:
+			sd := models.ServiceDiff{
+				Name:         name,
+				Change:       models.DiffModified,
+				VolumeMounts: diffFields(volumeMounts(f), volumeMounts(t)),
+				Env:          diffFields(envs(f), envs(t)),
+				Resources:    diffFields(resources(f), resources(t)),
+			}
+			if f.Image != t.Image {
+				sd.Image = &models.FieldChange{Name: "image", Change: models.DiffModified, From: f.Image, To: t.Image}
+			}
+			if sd.Image != nil || len(sd.VolumeMounts) > 0 || len(sd.Env) > 0 || len(sd.Resources) > 0 {
+				res = append(res, sd)
+			}
+		}
+	}
+	return res
+}
+
+// diffAppConfigs compares the volumes referencing configs and secrets
+func diffAppConfigs(from, to *specV1.Application) []models.FieldChange {
+	refs := func(app *specV1.Application) map[string]interface{} {
+		res := map[string]interface{}{}
+		for _, v := range app.Volumes {
+			if v.Config != nil || v.Secret != nil {
+				res[v.Name] = v.VolumeSource
+			}
+		}
+		return res
+	}
+	return diffFields(refs(from), refs(to))
+}
+
+func appServices(app *specV1.Application) map[string]specV1.Service {
+	res := map[string]specV1.Service{}
+	for _, services := range [][]specV1.Service{app.InitServices, app.Services} {
+		for _, s := range services {
+			res[s.Name] = s
+		}
+	}
+	return res
+}
+
+func volumeMounts(s specV1.Service) map[string]interface{} {
+	res := map[string]interface{}{}
+	for _, vm := range s.VolumeMounts {
+		res[vm.Name] = vm
+	}
+	return res
+}
+
+func envs(s specV1.Service) map[string]interface{} {
+	res := map[string]interface{}{}
+	for _, env := range s.Env {
+		res[env.Name] = env.Value
+	}
+	return res
+}
+
+func resources(s specV1.Service) map[string]interface{} {
+	res := map[string]interface{}{}
+	if s.Resources == nil {
+		return res
+	}
+	for k, v := range s.Resources.Limits {
+		res["limits."+k] = v
+	}
+	for k, v := range s.Resources.Requests {
+		res["requests."+k] = v
+	}
+	return res
+}
+
+func diffFields(from, to map[string]interface{}) []models.FieldChange {
+	var res []models.FieldChange
+	for _, name := range unionKeys(from, to) {
+		f, fok := from[name]
+		t, tok := to[name]
+		switch {
+		case !fok:
+			res = append(res, models.FieldChange{Name: name, Change: models.DiffAdded, To: t})
+		case !tok:
+			res = append(res, models.FieldChange{Name: name, Change: models.DiffRemoved, From: f})
+		case !reflect.DeepEqual(f, t):
+			res = append(res, models.FieldChange{Name: name, Change: models.DiffModified, From: f, To: t})
+		}
+	}
+	return res
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appJSONPatch returns the json patch turning the from app into the to app
+func appJSONPatch(from, to *specV1.Application) ([]models.JSONPatchOp, error) {
+	f, err := appJSONValue(from)
+	if err != nil {
+		return nil, err
+	}
+	t, err := appJSONValue(to)
+	if err != nil {
+		return nil, err
+	}
+	patch := []models.JSONPatchOp{}
+	return jsonPatch(patch, "", f, t), nil
+}
+
+func appJSONValue(app *specV1.Application) (interface{}, error) {
+	res := *app
+	// the update time is refreshed on every save, it is not a change of the spec
+	res.UpdateTime = time.Time{}
+	data, err := json.Marshal(&res)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var v interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return v, nil
+}
+
+// jsonPatch appends the operations turning the from value into the to value, arrays of different lengths are replaced as a whole
+func jsonPatch(patch []models.JSONPatchOp, path string, from, to interface{}) []models.JSONPatchOp {
+	switch f := from.(type) {
+	case map[string]interface{}:
+		t, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, k := range unionKeys(f, t) {
+			p := path + "/" + escapeJSONPointer(k)
+			fv, fok := f[k]
+			tv, tok := t[k]
+			switch {
+			case !fok:
+				patch = append(patch, models.JSONPatchOp{Op: "add", Path: p, Value: tv})
+			case !tok:
+				patch = append(patch, models.JSONPatchOp{Op: "remove", Path: p})
+			default:
+				patch = jsonPatch(patch, p, fv, tv)
+			}
+		}
+		return patch
+	case []interface{}:
+		t, ok := to.([]interface{})
+		if !ok || len(f) != len(t) {
+			break
+		}
+		for i := range f {
+			patch = jsonPatch(patch, fmt.Sprintf("%s/%d", path, i), f[i], t[i])
+		}
+		return patch
+	}
+	if !reflect.DeepEqual(from, to) {
+		patch = append(patch, models.JSONPatchOp{Op: "replace", Path: path, Value: to})
+	}
+	return patch
+}
+
+func escapeJSONPointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initAppDiffAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L().With(log.Any("test", "api"))}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	v1 := router.Group("v1")
+	{
+		apps := v1.Group("/apps")
+		apps.GET("/:name/diff", mockIM, common.Wrapper(api.DiffApplication))
+	}
+	return api, router, mockCtl
+}
+
+func TestDiffApplication(t *testing.T) {
+	api, router, mockCtl := initAppDiffAPI(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp}
+
+	from := &specV1.Application{
+		Name:      "app01",
+		Namespace: "default",
+		Version:   "3",
+		Services: []specV1.Service{
+			{
+				Name:         "svc01",
+				Image:        "nginx:1.0",
+				VolumeMounts: []specV1.VolumeMount{{Name: "cfg", MountPath: "/etc/a"}},
+				Env:          []specV1.Environment{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+				Resources:    &specV1.Resources{Limits: map[string]string{"cpu": "1"}},
+			},
+			{Name: "svc02", Image: "redis"},
+		},
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg", Version: "1"}}},
+		},
+	}
+	to := &specV1.Application{
+		Name:      "app01",
+		Namespace: "default",
+		Version:   "5",
+		Services: []specV1.Service{
+			{
+				Name:         "svc01",
+				Image:        "nginx:2.0",
+				VolumeMounts: []specV1.VolumeMount{{Name: "cfg", MountPath: "/etc/b"}},
+				Env:          []specV1.Environment{{Name: "A", Value: "1"}, {Name: "C", Value: "3"}},
+				Resources:    &specV1.Resources{Limits: map[string]string{"cpu": "2", "memory": "1Gi"}},
+			},
+			{Name: "svc03", Image: "mysql"},
+		},
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg", Version: "2"}}},
+		},
+	}
+
+	// missing params
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/app01/diff?from=3", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// missing version
+	sApp.EXPECT().GetHistory("default", "app01", "3").Return(from, nil).Times(1)
+	sApp.EXPECT().GetHistory("default", "app01", "4").Return(nil, common.Error(common.ErrResourceNotFound,
+		common.Field("type", "app version"), common.Field("name", "app01@4"))).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/app01/diff?from=3&to=4", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "app01@4")
+
+	sApp.EXPECT().GetHistory("default", "app01", "3").Return(from, nil).Times(1)
+	sApp.EXPECT().GetHistory("default", "app01", "5").Return(to, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/app01/diff?from=3&to=5", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	res := &models.ApplicationDiff{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, "3", res.From)
+	assert.Equal(t, "5", res.To)
+	assert.Len(t, res.Services, 3)
+
+	svc01 := res.Services[0]
+	assert.Equal(t, "svc01", svc01.Name)
+	assert.Equal(t, models.DiffModified, svc01.Change)
+	assert.Equal(t, "nginx:1.0", svc01.Image.From)
+	assert.Equal(t, "nginx:2.0", svc01.Image.To)
+	assert.Len(t, svc01.VolumeMounts, 1)
+	assert.Equal(t, []models.FieldChange{
+		{Name: "B", Change: models.DiffRemoved, From: "2"},
+		{Name: "C", Change: models.DiffAdded, To: "3"},
+	}, svc01.Env)
+	assert.Equal(t, []models.FieldChange{
+		{Name: "limits.cpu", Change: models.DiffModified, From: "1", To: "2"},
+		{Name: "limits.memory", Change: models.DiffAdded, To: "1Gi"},
+	}, svc01.Resources)
+	assert.Equal(t, models.ServiceDiff{Name: "svc02", Change: models.DiffRemoved}, res.Services[1])
+	assert.Equal(t, models.ServiceDiff{Name: "svc03", Change: models.DiffAdded}, res.Services[2])
+
+	assert.Len(t, res.Configs, 1)
+	assert.Equal(t, "cfg", res.Configs[0].Name)
+	assert.Equal(t, models.DiffModified, res.Configs[0].Change)
+
+	assert.Contains(t, res.Patch, models.JSONPatchOp{Op: "replace", Path: "/version", Value: "5"})
+	assert.Contains(t, res.Patch, models.JSONPatchOp{Op: "replace", Path: "/services/0/image", Value: "nginx:2.0"})
+	assert.Contains(t, res.Patch, models.JSONPatchOp{Op: "replace", Path: "/volumes/0/config/version", Value: "2"})
+	assert.Contains(t, res.Patch, models.JSONPatchOp{Op: "add", Path: "/services/0/resources/limits/memory", Value: "1Gi"})
+	assert.True(t, strings.HasPrefix(res.Diff, "--- app01@3\n+++ app01@5\n"))
+	assert.Contains(t, res.Diff, "-  image: nginx:1.0\n+  image: nginx:2.0\n")
+}
+
+func TestJSONPatch(t *testing.T) {
+	from := map[string]interface{}{"a": 1.0, "b/c": "x", "d": []interface{}{1.0, 2.0}, "e": "y"}
+	to := map[string]interface{}{"a": 2.0, "b/c": "x", "d": []interface{}{1.0}, "f": "z"}
+	assert.Equal(t, []models.JSONPatchOp{
+		{Op: "replace", Path: "/a", Value: 2.0},
+		{Op: "replace", Path: "/d", Value: []interface{}{1.0}},
+		{Op: "remove", Path: "/e"},
+		{Op: "add", Path: "/f", Value: "z"},
+	}, jsonPatch(nil, "", from, to))
+	assert.Equal(t, "a~0b~1c", escapeJSONPointer("a~b/c"))
+	assert.Len(t, jsonPatch(nil, "", from, from), 0)
+}
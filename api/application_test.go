@@ -162,12 +162,18 @@ func initApplicationAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 	api := &API{log: log.L().With(log.Any("test", "api"))}
 	router := gin.Default()
 	mockCtl := gomock.NewController(t)
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	sNS.EXPECT().GetSettings(gomock.Any()).Return(&models.NamespaceSettings{}, nil).AnyTimes()
+	api.NS = sNS
 	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "baetyl-cloud") }
 	v1 := router.Group("v1")
 	{
 		configs := v1.Group("/apps")
 		configs.GET("/:name", mockIM, common.Wrapper(api.GetApplication))
 		configs.PUT("/:name", mockIM, common.Wrapper(api.UpdateApplication))
+		configs.POST("/:name/rollback", mockIM, common.Wrapper(api.RollbackApplication))
+		configs.POST("/:name/promote", mockIM, common.Wrapper(api.PromoteApplication))
+		configs.POST("/:name/abort", mockIM, common.Wrapper(api.AbortApplication))
 		configs.DELETE("/:name", mockIM, common.Wrapper(api.DeleteApplication))
 		configs.POST("", mockIM, common.Wrapper(api.CreateApplication))
 		configs.GET("", mockIM, common.Wrapper(api.ListApplication))
@@ -1062,6 +1068,7 @@ func TestUpdateContainerApplication(t *testing.T) {
 	sNode := ms.NewMockNodeService(mockCtl)
 	fApp := mf.NewMockFacade(mockCtl)
 	api.Facade = fApp
+	fApp.EXPECT().CheckAppCanary(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	api.Index = sIndex
 	api.Node = sNode
 
@@ -1127,6 +1134,7 @@ func TestUpdateNativeNotFunctionApplication(t *testing.T) {
 	sIndex := ms.NewMockIndexService(mockCtl)
 	fApp := mf.NewMockFacade(mockCtl)
 	api.Facade = fApp
+	fApp.EXPECT().CheckAppCanary(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	api.Index = sIndex
 	api.Node = sNode
 
@@ -1571,6 +1579,9 @@ func TestUpdateSysApplication(t *testing.T) {
 	defer mockCtl.Finish()
 
 	sApp := ms.NewMockApplicationService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api.Facade = fApp
+	fApp.EXPECT().CheckAppCanary(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	sConfig := ms.NewMockConfigService(mockCtl)
 	sSecret := ms.NewMockSecretService(mockCtl)
 	api.AppCombinedService = &service.AppCombinedService{
@@ -1881,6 +1892,7 @@ func TestUpdateFunctionApplication(t *testing.T) {
 	sFunc := ms.NewMockFunctionService(mockCtl)
 	fApp := mf.NewMockFacade(mockCtl)
 	api.Facade = fApp
+	fApp.EXPECT().CheckAppCanary(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	api.Index = sIndex
 	api.Node = sNode
 	api.Func = sFunc
@@ -2359,6 +2371,30 @@ func TestDeleteApplication(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 
+	// 412: the app was modified or deleted since the version read
+	app.Version = "5"
+	sApp.EXPECT().Get(gomock.Any(), "abc", gomock.Any()).Return(app, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apps/abc", nil)
+	req.Header.Set(common.HeaderIfMatch, "4")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	sApp.EXPECT().Get(gomock.Any(), "abc", gomock.Any()).Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apps/abc", nil)
+	req.Header.Set(common.HeaderIfMatch, "5")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	sApp.EXPECT().Get(gomock.Any(), "abc", gomock.Any()).Return(app, nil).Times(1)
+	fApp.EXPECT().DeleteApp(app.Namespace, app.Name, gomock.Any()).Return(nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apps/abc", nil)
+	req.Header.Set(common.HeaderIfMatch, "5")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
 	app.Name = "baetyl-core-test"
 	sApp.EXPECT().Get(gomock.Any(), app.Name, gomock.Any()).Return(app, nil).AnyTimes()
 	sIndex.EXPECT().ListNodesByApp(gomock.Any(), app.Name).Return(nil, fmt.Errorf("error"))
@@ -2374,6 +2410,159 @@ func TestDeleteApplication(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestRollbackApplication(t *testing.T) {
+	api, router, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	fApp := mf.NewMockFacade(mockCtl)
+	api.Facade = fApp
+	fApp.EXPECT().CheckAppCanary(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	curApp := getMockContainerApp()
+	curApp.Version = "2"
+	curApp.Services[0].Image = "image:v2"
+	hisApp := getMockContainerApp()
+	hisApp.Version = "1"
+	hisApp.Services[0].Image = "image:v1"
+	hisApp.Volumes = append(hisApp.Volumes, specV1.Volume{
+		Name:         "registry01",
+		VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "registry01"}},
+	})
+
+	// 400 without version
+	req, _ := http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback", bytes.NewReader([]byte(`{}`)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ := json.Marshal(&models.AppRollback{Version: "1"})
+
+	// 404 app not found
+	sApp.EXPECT().Get(curApp.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 404 version not found
+	sApp.EXPECT().Get(curApp.Namespace, "abc", "").Return(curApp, nil).AnyTimes()
+	sApp.EXPECT().GetHistory(curApp.Namespace, "abc", "1").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 400 dependencies missing
+	sApp.EXPECT().GetHistory(curApp.Namespace, "abc", "1").Return(hisApp, nil).Times(1)
+	sConfig.EXPECT().Get(nil, curApp.Namespace, "agent-conf", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	sSecret.EXPECT().Get(curApp.Namespace, "secret01", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	sSecret.EXPECT().Get(curApp.Namespace, "registry01", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrAppDependencyMissing)
+	assert.Contains(t, w.Body.String(), "config(agent-conf), secret(secret01), registry(registry01)")
+
+	// 200 rolled back as a new version
+	sApp.EXPECT().GetHistory(curApp.Namespace, "abc", "1").Return(hisApp, nil).Times(1)
+	sConfig.EXPECT().Get(nil, curApp.Namespace, "agent-conf", "").Return(&specV1.Configuration{Name: "agent-conf"}, nil).Times(1)
+	sSecret.EXPECT().Get(curApp.Namespace, "secret01", "").Return(&specV1.Secret{Name: "secret01"}, nil).AnyTimes()
+	sSecret.EXPECT().Get(curApp.Namespace, "registry01", "").Return(&specV1.Secret{Name: "registry01",
+		Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry}}, nil).AnyTimes()
+	fApp.EXPECT().UpdateApp(curApp.Namespace, curApp, gomock.Any(), gomock.Len(0)).DoAndReturn(
+		func(_ string, _, app *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "2", app.Version)
+			assert.Equal(t, "image:v1", app.Services[0].Image)
+			res := *app
+			res.Version = "3"
+			return &res, nil
+		}).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	view := &models.ApplicationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, "3", view.Version)
+	assert.Equal(t, "image:v1", view.Services[0].Image)
+	assert.Len(t, view.Registries, 1)
+
+	// 200 already at the target version
+	body, _ = json.Marshal(&models.AppRollback{Version: "2"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestApplicationDryRun(t *testing.T) {
+	api, router, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	// nothing is persisted, any call of the facade but the canary check fails the test
+	fApp := mf.NewMockFacade(mockCtl)
+	fApp.EXPECT().CheckAppCanary(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	api.Facade = fApp
+
+	config := &specV1.Configuration{Name: "agent-conf", Version: "12"}
+	secret := &specV1.Secret{Name: "secret01", Version: "34"}
+	sConfig.EXPECT().Get(nil, "baetyl-cloud", config.Name, "").Return(config, nil).AnyTimes()
+	sSecret.EXPECT().Get("baetyl-cloud", secret.Name, "").Return(secret, nil).AnyTimes()
+
+	mApp := getMockContainerApp()
+	mApp.Selector = "a=b"
+	body, _ := json.Marshal(mApp)
+
+	// create
+	sApp.EXPECT().Get("baetyl-cloud", "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/apps?dryRun=true", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.ApplicationDryRun{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, "abc", res.Application.Name)
+	assert.Equal(t, "12", res.Application.Volumes[0].Config.Version)
+	assert.Equal(t, "34", res.Application.Volumes[1].Secret.Version)
+	assert.Contains(t, res.Diff, "--- /dev/null\n+++ abc@\n")
+	assert.Contains(t, res.Diff, "+selector: a=b\n")
+
+	// update
+	oldApp := getMockContainerApp()
+	oldApp.Version = "5"
+	oldApp.Selector = "a=b"
+	oldApp.Services[0].Image = "image:v1"
+	oldApp.Labels = map[string]string{common.LabelAppMode: context.RunModeKube}
+	sApp.EXPECT().Get("baetyl-cloud", "abc", "").Return(oldApp, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/abc?dryRun=true", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = &models.ApplicationDryRun{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, "5", res.Application.Version)
+	assert.Contains(t, res.Diff, "--- abc@5\n+++ abc@5\n")
+	assert.Contains(t, res.Diff, "-  image: image:v1\n+  image: hub.baidubce.com/baetyl/baetyl-agent:1.0.0\n")
+	assert.NotContains(t, res.Diff, "+selector")
+}
+
 func TestCreateKubeFunctionApplication(t *testing.T) {
 	api, router, mockCtl := initApplicationAPI(t)
 	defer mockCtl.Finish()
@@ -3445,6 +3634,93 @@ func TestAPI_ToApplication(t *testing.T) {
 	fmt.Println(cfg)
 }
 
+func TestApplicationEnvSources(t *testing.T) {
+	api, _, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		Config: sConfig,
+		Secret: sSecret,
+	}
+
+	appView := &models.ApplicationView{
+		Name:      "a0",
+		Namespace: "default",
+		Services: []models.ServiceView{
+			{
+				Service: specV1.Service{Name: "s0"},
+				Env: []models.EnvironmentView{
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "PASSWORD", ValueFrom: &models.EnvVarSource{Secret: "sec0", Key: "password"}},
+					{Name: "HOST", ValueFrom: &models.EnvVarSource{Config: "cfg0", Key: "host"}},
+					{Name: "USERNAME", ValueFrom: &models.EnvVarSource{Secret: "sec0", Key: "username"}},
+				},
+			},
+		},
+		Volumes: []models.VolumeView{
+			{Name: "cfg", Config: &specV1.ObjectReference{Name: "cfg0"}},
+		},
+	}
+	sec := &specV1.Secret{Name: "sec0", Data: map[string][]byte{"password": []byte("p"), "username": []byte("u")}}
+	cfg := &specV1.Configuration{Name: "cfg0", Data: map[string]string{"host": "localhost"}}
+
+	// validate
+	sSecret.EXPECT().Get("default", "sec0", "").Return(sec, nil).Times(2)
+	sConfig.EXPECT().Get(nil, "default", "cfg0", "").Return(cfg, nil).Times(2)
+	assert.NoError(t, api.validEnvSources("default", appView.Services))
+
+	sSecret.EXPECT().Get("default", "sec0", "").Return(&specV1.Secret{Name: "sec0", Data: map[string][]byte{"password": []byte("p")}}, nil).Times(2)
+	err := api.validEnvSources("default", appView.Services)
+	assert.Contains(t, err.Error(), "The request parameter is invalid. (the key (username) referenced by the environment variable (USERNAME) of the service (s0) does not exist)")
+
+	sSecret.EXPECT().Get("default", "sec0", "").Return(nil, common.Error(common.ErrResourceNotFound, common.Field("type", "secret"), common.Field("name", "sec0"))).Times(1)
+	err = api.validEnvSources("default", appView.Services)
+	assert.Error(t, err)
+
+	invalid := []models.ServiceView{{
+		Service: specV1.Service{Name: "s0"},
+		Env:     []models.EnvironmentView{{Name: "E", ValueFrom: &models.EnvVarSource{Secret: "sec0", Config: "cfg0", Key: "k"}}},
+	}}
+	err = api.validEnvSources("default", invalid)
+	assert.Contains(t, err.Error(), "The request parameter is invalid. (exactly one of secret and config should be referenced by the environment variable (E) of the service (s0))")
+
+	// the references are kept as the mounts of the volumes synchronized, not in the values
+	app, _, err := api.ToApplication(appView, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []specV1.Environment{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "PASSWORD"},
+		{Name: "HOST"},
+		{Name: "USERNAME"},
+	}, app.Services[0].Env)
+	assert.Equal(t, []specV1.VolumeMount{
+		{Name: "baetyl-env-secret-sec0", MountPath: "PASSWORD", SubPath: "password", ReadOnly: true},
+		{Name: "baetyl-env-config-cfg0", MountPath: "HOST", SubPath: "host", ReadOnly: true},
+		{Name: "baetyl-env-secret-sec0", MountPath: "USERNAME", SubPath: "username", ReadOnly: true},
+	}, app.Services[0].VolumeMounts)
+	assert.Equal(t, []specV1.Volume{
+		{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg0"}}},
+		{Name: "baetyl-env-secret-sec0", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "sec0"}}},
+		{Name: "baetyl-env-config-cfg0", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg0"}}},
+	}, app.Volumes)
+
+	view, err := api.ToApplicationView(app)
+	assert.NoError(t, err)
+	assert.Equal(t, appView.Services[0].Env, view.Services[0].Env)
+	assert.Empty(t, view.Services[0].VolumeMounts)
+	assert.Equal(t, appView.Volumes, view.Volumes)
+
+	// the plain values shaped like the references are kept as they are
+	plain := &specV1.Application{Name: "a1", Services: []specV1.Service{{
+		Name: "s0",
+		Env:  []specV1.Environment{{Name: "E", Value: "${secret:sec0/password}"}},
+	}}}
+	view, err = api.ToApplicationView(plain)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.EnvironmentView{{Name: "E", Value: "${secret:sec0/password}"}}, view.Services[0].Env)
+}
+
 func TestIsValidPort(t *testing.T) {
 	svc := &models.ServiceView{
 		Service: specV1.Service{
@@ -3477,3 +3753,36 @@ func TestIsValidPort(t *testing.T) {
 	_, err = isValidPort(svc, tcpPorts, udpPorts)
 	assert.NotNil(t, err)
 }
+
+func TestCheckAppDependenciesPinned(t *testing.T) {
+	api, _, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+	sConfig := ms.NewMockConfigService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{Config: sConfig}
+
+	app := &specV1.Application{
+		Name:   "abc",
+		Labels: map[string]string{common.LabelPinnedConfigs: "c1_c3"},
+		Volumes: []specV1.Volume{
+			{Name: "v1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c1", Version: "1"}}},
+			{Name: "v2", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c2", Version: "1"}}},
+			{Name: "v3", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c3"}}},
+		},
+	}
+	// the pinned version is kept, the others follow the latest versions
+	sConfig.EXPECT().GetVersion("default", "c1", "1").Return(&specV1.Configuration{Name: "c1", Version: "1"}, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "c2", "").Return(&specV1.Configuration{Name: "c2", Version: "2"}, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "c3", "").Return(&specV1.Configuration{Name: "c3", Version: "3"}, nil).Times(1)
+	_, err := api.checkAppDependencies("default", app, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", app.Volumes[0].Config.Version)
+	assert.Equal(t, "2", app.Volumes[1].Config.Version)
+	assert.Equal(t, "3", app.Volumes[2].Config.Version)
+
+	// the pinned version is no longer kept
+	app.Volumes = app.Volumes[:1]
+	sConfig.EXPECT().GetVersion("default", "c1", "1").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	_, err = api.checkAppDependencies("default", app, nil, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "config(c1@1)")
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// the number of expired apps purged at a time by the reaper
+const appTrashReapBatch = 100
+
+// deleteApp deletes the app into the trash, or at once if the trash is disabled
+func (api *API) deleteApp(ns string, app *specV1.Application) error {
+	if api.appTrashRetention > 0 {
+		return api.Facade.TrashApp(ns, app, time.Now().Add(api.appTrashRetention))
+	}
+	return api.Facade.DeleteApp(ns, app.Name, app)
+}
+
+// ListAppTrash lists the deleted apps of the namespace which can be restored
+func (api *API) ListAppTrash(c *common.Context) (interface{}, error) {
+	return api.App.ListTrash(c.GetNamespace())
+}
+
+// RestoreApplication recreates the deleted app, the configs and secrets it references are required to exist
+func (api *API) RestoreApplication(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	trash, err := api.App.GetTrash(ns, name)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = api.App.Get(ns, name, "")
+	if err == nil {
+		return nil, common.Error(common.ErrResourceConflict,
+			common.Field("type", common.APP), common.Field("name", name))
+	}
+	if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+		return nil, err
+	}
+
+	app := trash.Application
+	app.Namespace = ns
+	configs, err := api.checkAppDependencies(ns, app, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	app, err = api.Facade.RestoreApp(ns, app, configs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return api.ToApplicationView(app)
+}
+
+// PurgeApplication deletes the app from the trash for good
+func (api *API) PurgeApplication(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	trash, err := api.App.GetTrash(ns, name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return nil, api.Facade.PurgeApp(ns, trash)
+}
+
+// RunAppTrashReaper purges the apps past retention periodically until stopped
+func (api *API) RunAppTrashReaper(stop <-chan struct{}) {
+	if api.appTrashReapInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(api.appTrashReapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			api.reapAppTrash()
+		}
+	}
+}
+
+func (api *API) reapAppTrash() {
+	for {
+		expired, err := api.App.ListExpiredTrash(time.Now(), appTrashReapBatch)
+		if err != nil {
+			api.log.Error("failed to list expired apps in trash", log.Error(err))
+			return
+		}
+		for i := range expired {
+			trash := &expired[i]
+			if err = api.Facade.PurgeApp(trash.Namespace, trash); err != nil {
+				// retried next time
+				api.log.Error("failed to purge app from trash", log.Any("namespace", trash.Namespace),
+					log.Any("name", trash.Name), log.Error(err))
+				return
+			}
+		}
+		if len(expired) < appTrashReapBatch {
+			return
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initAppTrashAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L().With(log.Any("test", "api"))}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	v1 := router.Group("v1")
+	{
+		apps := v1.Group("/apps")
+		apps.GET("/trash", mockIM, common.Wrapper(api.ListAppTrash))
+		apps.DELETE("/trash/:name", mockIM, common.Wrapper(api.PurgeApplication))
+		apps.POST("/:name/restore", mockIM, common.Wrapper(api.RestoreApplication))
+	}
+	return api, router, mockCtl
+}
+
+func TestAppTrash(t *testing.T) {
+	api, router, mockCtl := initAppTrashAPI(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret}
+	sFacade := mf.NewMockFacade(mockCtl)
+	api.Facade = sFacade
+
+	app := &specV1.Application{
+		Name:      "app01",
+		Namespace: "default",
+		Version:   "3",
+		Type:      specV1.AppTypeContainer,
+		Services:  []specV1.Service{{Name: "svc", Image: "nginx"}},
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg", Version: "1"}}},
+		},
+	}
+	trash := &models.AppTrash{Name: "app01", Namespace: "default", Version: "3", Application: app}
+	notFound := common.Error(common.ErrResourceNotFound)
+
+	// deleted at once if the trash is disabled
+	sFacade.EXPECT().DeleteApp("default", "app01", app).Return(nil).Times(1)
+	assert.NoError(t, api.deleteApp("default", app))
+
+	api.appTrashRetention = time.Hour
+	sFacade.EXPECT().TrashApp("default", app, gomock.Any()).DoAndReturn(func(ns string, app *specV1.Application, expire time.Time) error {
+		assert.WithinDuration(t, time.Now().Add(time.Hour), expire, time.Second)
+		return nil
+	}).Times(1)
+	assert.NoError(t, api.deleteApp("default", app))
+
+	// list
+	sApp.EXPECT().ListTrash("default").Return(&models.AppTrashList{Total: 1, Items: []models.AppTrash{*trash}}, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/trash", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	list := &models.AppTrashList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), list))
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "app01", list.Items[0].Name)
+	assert.Nil(t, list.Items[0].Application)
+
+	restore := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/v1/apps/app01/restore", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// not in the trash
+	sApp.EXPECT().GetTrash("default", "app01").Return(nil, notFound).Times(1)
+	assert.Equal(t, http.StatusNotFound, restore().Code)
+
+	// an app of the same name is created after deleted
+	sApp.EXPECT().GetTrash("default", "app01").Return(trash, nil).Times(1)
+	sApp.EXPECT().Get("default", "app01", "").Return(&specV1.Application{Name: "app01"}, nil).Times(1)
+	w = restore()
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrResourceConflict)
+
+	// the referenced config is deleted
+	sApp.EXPECT().GetTrash("default", "app01").Return(trash, nil).Times(1)
+	sApp.EXPECT().Get("default", "app01", "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(nil, notFound).Times(1)
+	w = restore()
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "config(cfg)")
+
+	sApp.EXPECT().GetTrash("default", "app01").Return(trash, nil).Times(1)
+	sApp.EXPECT().Get("default", "app01", "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(&specV1.Configuration{Name: "cfg", Version: "2"}, nil).Times(1)
+	sFacade.EXPECT().RestoreApp("default", app, nil).DoAndReturn(
+		func(ns string, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "2", app.Volumes[0].Config.Version)
+			return app, nil
+		}).Times(1)
+	w = restore()
+	assert.Equal(t, http.StatusOK, w.Code)
+	view := &models.ApplicationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, "app01", view.Name)
+
+	// purge
+	purge := func() int {
+		req, _ := http.NewRequest(http.MethodDelete, "/v1/apps/trash/app01", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	sApp.EXPECT().GetTrash("default", "app01").Return(nil, notFound).Times(1)
+	assert.Equal(t, http.StatusOK, purge())
+	sApp.EXPECT().GetTrash("default", "app01").Return(trash, nil).Times(1)
+	sFacade.EXPECT().PurgeApp("default", trash).Return(nil).Times(1)
+	assert.Equal(t, http.StatusOK, purge())
+}
+
+func TestReapAppTrash(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sFacade := mf.NewMockFacade(mockCtl)
+	api := &API{
+		log:                  log.L().With(log.Any("test", "api")),
+		AppCombinedService:   &service.AppCombinedService{App: sApp},
+		Facade:               sFacade,
+		appTrashReapInterval: time.Millisecond * 10,
+	}
+
+	full := make([]models.AppTrash, appTrashReapBatch)
+	for i := range full {
+		full[i] = models.AppTrash{Name: "a", Namespace: "default"}
+	}
+	rest := []models.AppTrash{{Name: "b", Namespace: "other"}}
+	gomock.InOrder(
+		sApp.EXPECT().ListExpiredTrash(gomock.Any(), appTrashReapBatch).Return(full, nil),
+		sApp.EXPECT().ListExpiredTrash(gomock.Any(), appTrashReapBatch).Return(rest, nil),
+	)
+	sFacade.EXPECT().PurgeApp("default", gomock.Any()).Return(nil).Times(appTrashReapBatch)
+	sFacade.EXPECT().PurgeApp("other", &rest[0]).Return(nil).Times(1)
+	api.reapAppTrash()
+
+	// stops at the first failure
+	sApp.EXPECT().ListExpiredTrash(gomock.Any(), appTrashReapBatch).Return(full, nil).Times(1)
+	sFacade.EXPECT().PurgeApp("default", gomock.Any()).Return(common.Error(common.ErrResourceNotFound)).Times(1)
+	api.reapAppTrash()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	sApp.EXPECT().ListExpiredTrash(gomock.Any(), appTrashReapBatch).Return(nil, nil).MinTimes(1)
+	go func() {
+		api.RunAppTrashReaper(stop)
+		close(done)
+	}()
+	time.Sleep(time.Millisecond * 50)
+	close(stop)
+	<-done
+}
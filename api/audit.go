@@ -0,0 +1,19 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ListAudit list the audit records of the namespace, filtered by resource type and time range
+func (api *API) ListAudit(c *common.Context) (interface{}, error) {
+	params := &models.AuditListOptions{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if params.StartTime < 0 || params.EndTime < 0 ||
+		(params.EndTime > 0 && params.StartTime > params.EndTime) {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "invalid time range"))
+	}
+	return api.Audit.List(c.GetNamespace(), params)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initAuditAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		audits := v1.Group("/audits")
+		audits.GET("", mockIM, common.Wrapper(api.ListAudit))
+	}
+	return api, router, mockCtl
+}
+
+func TestListAudit(t *testing.T) {
+	api, router, mockCtl := initAuditAPI(t)
+	defer mockCtl.Finish()
+	sAudit := ms.NewMockAuditService(mockCtl)
+	api.Audit = sAudit
+
+	list := &models.AuditList{
+		Total: 1,
+		Items: []models.Audit{{Namespace: "default", Resource: "apps", Method: http.MethodPost, Status: http.StatusOK}},
+	}
+	sAudit.EXPECT().List("default", gomock.Any()).DoAndReturn(func(_ string, params *models.AuditListOptions) (*models.AuditList, error) {
+		assert.Equal(t, "apps", params.Resource)
+		assert.Equal(t, int64(100), params.StartTime)
+		assert.Equal(t, int64(200), params.EndTime)
+		return list, nil
+	}).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/audits?resource=apps&startTime=100&endTime=200", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.AuditList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "apps", res.Items[0].Resource)
+
+	// invalid time range
+	req, _ = http.NewRequest(http.MethodGet, "/v1/audits?startTime=200&endTime=100", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/audits?startTime=abc", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -1,23 +1,35 @@
 package api
 
 import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
+const (
+	// DefaultCertExpiringWithin the default duration to look ahead for expiring certificates
+	DefaultCertExpiringWithin = 30 * 24 * time.Hour
+	// DefaultCertRotationOverlap the default duration the replaced certificate keeps valid after rotation
+	DefaultCertRotationOverlap = 72 * time.Hour
+)
+
 // GetCertificate get a Certificate
 func (api *API) GetCertificate(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
 
 	secret, err := api.Secret.Get(ns, n, "")
-	if err != nil {
-		return nil, wrapSecretLikedResourceNotFoundError(n, common.Certificate, err)
+	if err = checkResourceFound(secret, err, common.Certificate, n); err != nil {
+		return nil, err
 	}
 	return hideCertKey(api.ToCertificateView(secret)), nil
 }
@@ -96,7 +108,7 @@ func (api *API) UpdateCertificate(c *common.Context) (interface{}, error) {
 	if err = sd.ParseCertInfo(); err != nil {
 		return nil, err
 	}
-	secret, err = api.Secret.Update(ns, sd.ToSecret())
+	secret, err = api.Secret.Update(nil, ns, sd.ToSecret())
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +131,287 @@ func (api *API) GetAppByCertificate(c *common.Context) (interface{}, error) {
 	return api.listAppBySecret(ns, res.Name)
 }
 
+// ListExpiringCertificates list the certificates expiring within the duration, sorted by the soonest
+func (api *API) ListExpiringCertificates(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	within := DefaultCertExpiringWithin
+	if v := c.Query("within"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "within should be a positive duration, such as 720h"))
+		}
+		within = d
+	}
+
+	params := &models.ListOptions{
+		LabelSelector: fmt.Sprintf("!%s,%s=%s", common.LabelSystem, specV1.SecretLabel, specV1.SecretCertificate),
+	}
+	secrets, err := api.Secret.List(ns, params)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+
+	now := time.Now()
+	deadline := now.Add(within)
+	res := &models.ExpiringCertificateList{
+		Within: within.String(),
+		Items:  []models.ExpiringCertificate{},
+	}
+	for i := range secrets.Items {
+		cert := api.ToCertificateView(&secrets.Items[i])
+		notAfter, err := cert.ParseNotAfter()
+		if err != nil {
+			log.L().Warn("failed to parse certificate", log.Any("name", cert.Name), log.Error(err))
+			continue
+		}
+		if notAfter.After(deadline) {
+			continue
+		}
+		apps, err := api.listAppBySecret(ns, cert.Name)
+		if err != nil {
+			return nil, err
+		}
+		appNames := make([]string, 0, len(apps.Items))
+		for _, app := range apps.Items {
+			appNames = append(appNames, app.Name)
+		}
+		res.Items = append(res.Items, models.ExpiringCertificate{
+			Name:          cert.Name,
+			Namespace:     cert.Namespace,
+			Issuer:        cert.Issuer,
+			SerialNumber:  cert.SerialNumber,
+			NotAfter:      notAfter,
+			DaysRemaining: int(notAfter.Sub(now) / (24 * time.Hour)),
+			Apps:          appNames,
+		})
+	}
+	sort.SliceStable(res.Items, func(i, j int) bool {
+		return res.Items[i].NotAfter.Before(res.Items[j].NotAfter)
+	})
+	res.Total = len(res.Items)
+	return res, nil
+}
+
+// RotateCertificate re-issue the certificate signed by baetyl-cloud with the same subject and SANs,
+// the referencing apps are updated to the new version, and the replaced certificate keeps valid
+// during the overlap window, it is deleted from pki by the first rotation after the window ends
+func (api *API) RotateCertificate(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	overlap := api.certRotationOverlap
+	if overlap == 0 {
+		overlap = DefaultCertRotationOverlap
+	}
+	if v := c.Query("overlap"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "overlap should be a positive duration, such as 72h"))
+		}
+		overlap = d
+	}
+
+	secret, err := api.Secret.Get(ns, n, "")
+	if err != nil {
+		return nil, wrapSecretLikedResourceNotFoundError(n, common.Certificate, err)
+	}
+	cert := api.ToFilteredCertificateView(secret)
+	if cert == nil {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", common.Certificate), common.Field("name", n))
+	}
+
+	old, err := parseCertificate(cert.Data.Certificate)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	ca, err := api.PKI.GetCA()
+	if err != nil {
+		return nil, err
+	}
+	if !isIssuedBy(old, ca) {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the certificate isn't issued by baetyl-cloud"))
+	}
+
+	client := isClientCertificate(old)
+	sign := api.PKI.SignServerCertificate
+	if client {
+		sign = api.PKI.SignClientCertificate
+	}
+	cred, err := sign(old.Subject.CommonName, models.AltNames{
+		DNSNames: old.DNSNames,
+		IPs:      old.IPAddresses,
+		Emails:   old.EmailAddresses,
+		URIs:     old.URIs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deprecated := api.cleanDeprecatedCertificates(cert.DeprecatedCerts, now)
+	if cert.CertId != "" {
+		deprecated = append(deprecated, models.DeprecatedCertificate{
+			CertId:     cert.CertId,
+			Client:     client,
+			ExpireTime: now.Add(overlap),
+		})
+	}
+	cert.CertId = cred.CertId
+	cert.DeprecatedCerts = deprecated
+	cert.Data = models.CertificateDataItem{
+		Key:         string(cred.KeyPEM),
+		Certificate: string(cred.CertPEM),
+	}
+	cert.UpdateTimestamp = now
+	if err = cert.ParseCertInfo(); err != nil {
+		return nil, err
+	}
+	secret, err = api.Facade.UpdateSecret(ns, cert.ToSecret())
+	if err != nil {
+		// the new certificate is never used
+		api.deleteIssuedCertificate(cred.CertId, client)
+		return nil, err
+	}
+	return hideCertKey(api.ToCertificateView(secret)), nil
+}
+
+// cleanDeprecatedCertificates delete the deprecated certificates whose overlap window ends,
+// and return the ones remaining
+func (api *API) cleanDeprecatedCertificates(certs []models.DeprecatedCertificate, now time.Time) []models.DeprecatedCertificate {
+	var res []models.DeprecatedCertificate
+	for _, v := range certs {
+		if v.ExpireTime.After(now) || !api.deleteIssuedCertificate(v.CertId, v.Client) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// RunCertificateReaper deletes the deprecated certificates whose overlap window ends periodically until stopped,
+// so that they are not kept in pki until the next rotation
+func (api *API) RunCertificateReaper(stop <-chan struct{}) {
+	if api.certReapInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(api.certReapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			api.reapDeprecatedCertificates()
+		}
+	}
+}
+
+func (api *API) reapDeprecatedCertificates() {
+	list, err := api.NS.List(&models.ListOptions{})
+	if err != nil {
+		api.log.Error("failed to list namespaces", log.Error(err))
+		return
+	}
+	params := &models.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", specV1.SecretLabel, specV1.SecretCertificate)}
+	now := time.Now()
+	for _, ns := range list.Items {
+		secrets, err := api.Secret.List(ns.Name, params)
+		if err != nil {
+			api.log.Error("failed to list certificates", log.Any("namespace", ns.Name), log.Error(err))
+			continue
+		}
+		for i := range secrets.Items {
+			cert := models.FromSecretToCertificate(&secrets.Items[i], false)
+			if cert == nil || !hasExpiredDeprecatedCertificate(cert.DeprecatedCerts, now) {
+				continue
+			}
+			if err = api.reapCertificate(ns.Name, cert.Name, now); err != nil {
+				// retried next time
+				api.log.Error("failed to reap deprecated certificates", log.Any("namespace", ns.Name),
+					log.Any("name", cert.Name), log.Error(err))
+			}
+		}
+	}
+}
+
+// reapCertificate deletes the deprecated certificates of the certificate whose overlap window ends, the certificate
+// is read again under the lock of the namespace, which is held by the rotation as well
+func (api *API) reapCertificate(ns, name string, now time.Time) error {
+	ctx := context.Background()
+	lockName := "namespace_" + ns
+	version, err := api.Locker.Lock(ctx, lockName, 0)
+	if err != nil {
+		return err
+	}
+	defer api.Locker.Unlock(ctx, lockName, version)
+
+	secret, err := api.Secret.Get(ns, name, "")
+	if err != nil {
+		return err
+	}
+	cert := api.ToFilteredCertificateView(secret)
+	if cert == nil {
+		return nil
+	}
+	remaining := api.cleanDeprecatedCertificates(cert.DeprecatedCerts, now)
+	if len(remaining) == len(cert.DeprecatedCerts) {
+		return nil
+	}
+	cert.DeprecatedCerts = remaining
+	_, err = api.Facade.UpdateSecret(ns, cert.ToSecret())
+	return err
+}
+
+func hasExpiredDeprecatedCertificate(certs []models.DeprecatedCertificate, now time.Time) bool {
+	for _, v := range certs {
+		if !v.ExpireTime.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (api *API) deleteIssuedCertificate(certId string, client bool) bool {
+	var err error
+	if client {
+		err = api.PKI.DeleteClientCertificate(certId)
+	} else {
+		err = api.PKI.DeleteServerCertificate(certId)
+	}
+	if err != nil {
+		log.L().Warn("failed to delete certificate from pki", log.Any("certId", certId), log.Error(err))
+		return false
+	}
+	return true
+}
+
+func parseCertificate(data string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("failed to find certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func isIssuedBy(cert *x509.Certificate, caPEM []byte) bool {
+	ca, err := parseCertificate(string(caPEM))
+	if err != nil {
+		return false
+	}
+	return cert.CheckSignatureFrom(ca) == nil
+}
+
+func isClientCertificate(cert *x509.Certificate) bool {
+	for _, v := range cert.ExtKeyUsage {
+		if v == x509.ExtKeyUsageServerAuth {
+			return false
+		}
+	}
+	for _, v := range cert.ExtKeyUsage {
+		if v == x509.ExtKeyUsageClientAuth {
+			return true
+		}
+	}
+	return false
+}
+
 func parseAndCheckCertificateModelWhenGet(c *common.Context) (*models.Certificate, error) {
 	cert, err := parseAndCheckCertificateModel(c)
 	if err != nil {
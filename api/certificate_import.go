@@ -0,0 +1,106 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ImportCertificates creates the certificates in bulk, each certificate is validated and created on its own,
+// a failure on one certificate does not abort the rest
+func (api *API) ImportCertificates(c *common.Context) (interface{}, error) {
+	params := &models.CertificateImport{}
+	if err := c.LoadBody(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	ns := c.GetNamespace()
+	secrets, err := api.Secret.List(ns, &models.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", specV1.SecretLabel, specV1.SecretCertificate),
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	fingerprints := map[string]string{}
+	for i := range secrets.Items {
+		cert := api.ToCertificateView(&secrets.Items[i])
+		names[cert.Name] = true
+		if cert.FingerPrint != "" {
+			fingerprints[cert.FingerPrint] = cert.Name
+		}
+	}
+
+	res := &models.CertificateImportResultList{Items: make([]models.CertificateImportResult, 0, len(params.Items))}
+	for _, item := range params.Items {
+		result := api.importCertificate(ns, item, params.DuplicatePolicy, names, fingerprints)
+		switch result.Status {
+		case models.CertificateImportCreated:
+			res.Created++
+		case models.CertificateImportSkipped:
+			res.Skipped++
+		default:
+			res.Failed++
+		}
+		res.Items = append(res.Items, result)
+	}
+	res.Total = len(res.Items)
+	return res, nil
+}
+
+// importCertificate validates and creates a certificate, the names and the fingerprints of the certificates
+// created are added for the duplicate detection of the following ones
+func (api *API) importCertificate(ns string, item models.CertificateImportItem, policy string, names map[string]bool, fingerprints map[string]string) models.CertificateImportResult {
+	res := models.CertificateImportResult{Name: item.Name}
+	fail := func(err error) models.CertificateImportResult {
+		res.Status, res.Code, res.Message = models.CertificateImportFailed, common.ErrUnknown, err.Error()
+		if e, ok := err.(errors.Coder); ok {
+			res.Code = e.Code()
+		}
+		return res
+	}
+
+	if err := binding.Validator.ValidateStruct(&item); err != nil {
+		return fail(common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error())))
+	}
+	if !common.ValidNonBaetyl(item.Name) {
+		return fail(common.Error(common.ErrInvalidName, common.Field("nonBaetyl", "Name")))
+	}
+	if item.Data.Key == "" || item.Data.Certificate == "" {
+		return fail(common.Error(common.ErrRequestParamInvalid, common.Field("error", "private key and certificate can't be empty")))
+	}
+	cert := &models.Certificate{
+		Name:        item.Name,
+		Namespace:   ns,
+		Description: item.Description,
+		Data:        item.Data,
+	}
+	if err := cert.ParseCertInfo(); err != nil {
+		return fail(common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error())))
+	}
+	res.FingerPrint = cert.FingerPrint
+	if dup, ok := fingerprints[cert.FingerPrint]; ok {
+		res.Duplicate = dup
+		if policy == models.ConflictPolicySkip {
+			res.Status = models.CertificateImportSkipped
+			return res
+		}
+		return fail(common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the certificate is a duplicate of the certificate (%s)", dup))))
+	}
+	if names[item.Name] {
+		return fail(common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use")))
+	}
+
+	if _, err := api.Facade.CreateSecret(ns, cert.ToSecret()); err != nil {
+		return fail(err)
+	}
+	names[item.Name] = true
+	fingerprints[cert.FingerPrint] = item.Name
+	res.Status = models.CertificateImportCreated
+	return res
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestImportCertificates(t *testing.T) {
+	api, router, mockCtl := initCertificateAPI(t)
+	defer mockCtl.Finish()
+	sSecret := ms.NewMockSecretService(mockCtl)
+	fSecret := mf.NewMockFacade(mockCtl)
+	api.Facade = fSecret
+	api.AppCombinedService = &service.AppCombinedService{Secret: sSecret}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other.ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTpl, caTpl, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDer)
+	assert.NoError(t, err)
+	cert1, key1 := genTestIssuedCertificate(t, ca, caKey, 2, []string{"a.example.com"})
+	cert2, key2 := genTestIssuedCertificate(t, ca, caKey, 3, []string{"b.example.com"})
+	cert3, key3 := genTestIssuedCertificate(t, ca, caKey, 4, []string{"c.example.com"})
+
+	existing := &models.Certificate{Name: "old", Namespace: "default", Data: models.CertificateDataItem{Certificate: cert1, Key: key1}}
+	assert.NoError(t, existing.ParseCertInfo())
+	list := &models.SecretList{Items: []specV1.Secret{*existing.ToSecret()}}
+	do := func(body *models.CertificateImport) (int, *models.CertificateImportResultList) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, "/v1/certificates/import", bytes.NewReader(data))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		res := &models.CertificateImportResultList{}
+		json.Unmarshal(w.Body.Bytes(), res)
+		return w.Code, res
+	}
+	items := []models.CertificateImportItem{
+		{Name: "c1", Data: models.CertificateDataItem{Certificate: cert1, Key: key1}},
+		{Name: "c2", Description: "b", Data: models.CertificateDataItem{Certificate: cert2, Key: key2}},
+		{Name: "c3", Data: models.CertificateDataItem{Certificate: cert2, Key: key2}},
+		{Name: "c4", Data: models.CertificateDataItem{Certificate: cert3, Key: key1}},
+		{Name: "old", Data: models.CertificateDataItem{Certificate: cert3, Key: key3}},
+		{Name: "Bad_Name", Data: models.CertificateDataItem{Certificate: cert3, Key: key3}},
+		{Name: "c5", Data: models.CertificateDataItem{Certificate: cert3}},
+	}
+
+	// the duplicates are skipped by default, including the ones of the earlier items
+	sSecret.EXPECT().List("default", &models.ListOptions{LabelSelector: specV1.SecretLabel + "=" + specV1.SecretCertificate}).Return(list, nil).Times(1)
+	fSecret.EXPECT().CreateSecret("default", gomock.Any()).DoAndReturn(func(_ string, s *specV1.Secret) (*specV1.Secret, error) {
+		assert.Equal(t, "c2", s.Name)
+		assert.Equal(t, specV1.SecretCertificate, s.Labels[specV1.SecretLabel])
+		return s, nil
+	}).Times(1)
+	code, res := do(&models.CertificateImport{Items: items})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 7, res.Total)
+	assert.Equal(t, 1, res.Created)
+	assert.Equal(t, 2, res.Skipped)
+	assert.Equal(t, 4, res.Failed)
+	statuses := []string{}
+	for _, item := range res.Items {
+		statuses = append(statuses, item.Status)
+	}
+	assert.Equal(t, []string{"skipped", "created", "skipped", "failed", "failed", "failed", "failed"}, statuses)
+	assert.Equal(t, "old", res.Items[0].Duplicate)
+	assert.Equal(t, existing.FingerPrint, res.Items[0].FingerPrint)
+	assert.Equal(t, "c2", res.Items[2].Duplicate)
+	assert.Contains(t, res.Items[4].Message, "this name is already in use")
+
+	// the duplicates are failed by the policy
+	sSecret.EXPECT().List("default", gomock.Any()).Return(list, nil).Times(1)
+	code, res = do(&models.CertificateImport{DuplicatePolicy: models.ConflictPolicyFail, Items: items[:1]})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, res.Failed)
+	assert.Equal(t, common.ErrRequestParamInvalid, res.Items[0].Code)
+	assert.Equal(t, "old", res.Items[0].Duplicate)
+
+	code, _ = do(&models.CertificateImport{DuplicatePolicy: "overwrite", Items: items[:1]})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = do(&models.CertificateImport{})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
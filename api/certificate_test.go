@@ -2,19 +2,29 @@ package api
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
 	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
@@ -29,12 +39,16 @@ func initCertificateAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 	v1 := router.Group("v1")
 	{
 		certificate := v1.Group("/certificates")
+		certificate.GET("/expiring", mockIM, common.Wrapper(api.ListExpiringCertificates))
+		certificate.POST("/csr", mockIM, common.Wrapper(api.SignCSR))
+		certificate.POST("/import", mockIM, common.Wrapper(api.ImportCertificates))
 		certificate.GET("/:name", mockIM, common.Wrapper(api.GetCertificate))
 		certificate.PUT("/:name", mockIM, common.Wrapper(api.UpdateCertificate))
 		certificate.DELETE("/:name", mockIM, common.Wrapper(api.DeleteCertificate))
 		certificate.POST("", mockIM, common.Wrapper(api.CreateCertificate))
 		certificate.GET("", mockIM, common.Wrapper(api.ListCertificate))
 		certificate.GET("/:name/apps", mockIM, common.Wrapper(api.GetAppByCertificate))
+		certificate.POST("/:name/rotate", mockIM, common.Wrapper(api.RotateCertificate))
 	}
 	return api, router, mockCtl
 }
@@ -515,7 +529,7 @@ RcKyjhh1
 	}
 
 	mkSecretService.EXPECT().Get(gomock.Any(), name, gomock.Any()).Return(res2, nil).Times(1)
-	mkSecretService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(res1, nil).Times(1)
+	mkSecretService.EXPECT().Update(nil, gomock.Any(), gomock.Any()).Return(res1, nil).Times(1)
 	w := httptest.NewRecorder()
 	body, _ := json.Marshal(cert1)
 	req, _ := http.NewRequest(http.MethodPut, "/v1/certificates/"+name, bytes.NewReader(body))
@@ -532,7 +546,7 @@ RcKyjhh1
 
 	cert1.Data.Key = keyData1
 	mkSecretService.EXPECT().Get(gomock.Any(), name, gomock.Any()).Return(res2, nil).Times(1)
-	mkSecretService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+	mkSecretService.EXPECT().Update(nil, gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(cert1)
 	req, _ = http.NewRequest(http.MethodPut, "/v1/certificates/"+name, bytes.NewReader(body))
@@ -664,6 +678,93 @@ func TestListCertificate(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func genTestCertificate(t *testing.T, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestListExpiringCertificates(t *testing.T) {
+	api, router, mockCtl := initCertificateAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Secret: sSecret,
+	}
+	sIndex := ms.NewMockIndexService(mockCtl)
+	api.Index = sIndex
+
+	now := time.Now()
+	genSecret := func(name string, notAfter time.Time) specV1.Secret {
+		cert := &models.Certificate{
+			Name:      name,
+			Namespace: "default",
+			Data:      models.CertificateDataItem{Certificate: genTestCertificate(t, notAfter)},
+		}
+		return *cert.ToSecret()
+	}
+	broken := genSecret("broken", now)
+	broken.Data["certificate"] = []byte("invalid")
+	list := &models.SecretList{
+		Items: []specV1.Secret{
+			genSecret("later", now.Add(100*24*time.Hour)),
+			genSecret("soon", now.Add(10*24*time.Hour+time.Hour)),
+			genSecret("expired", now.Add(-2*24*time.Hour-time.Hour)),
+			broken,
+		},
+	}
+
+	// 400 invalid duration
+	req, _ := http.NewRequest(http.MethodGet, "/v1/certificates/expiring?within=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 200 default within 30 days
+	sSecret.EXPECT().List("default", &models.ListOptions{
+		LabelSelector: "!" + common.LabelSystem + "," + specV1.SecretLabel + "=" + specV1.SecretCertificate,
+	}).Return(list, nil).Times(2)
+	sIndex.EXPECT().ListAppIndexBySecret("default", "soon").Return([]string{"app1"}, nil).Times(2)
+	sIndex.EXPECT().ListAppIndexBySecret("default", "expired").Return(nil, nil).Times(2)
+	sIndex.EXPECT().ListAppIndexBySecret("default", "later").Return(nil, nil).Times(1)
+	sApp.EXPECT().ListByNames("default", []string{"app1"}).Return([]models.AppItem{{Name: "app1"}}, nil).Times(2)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/certificates/expiring", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.ExpiringCertificateList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, "720h0m0s", res.Within)
+	assert.Equal(t, "expired", res.Items[0].Name)
+	assert.Equal(t, -2, res.Items[0].DaysRemaining)
+	assert.Equal(t, []string{}, res.Items[0].Apps)
+	assert.Equal(t, "soon", res.Items[1].Name)
+	assert.Equal(t, 10, res.Items[1].DaysRemaining)
+	assert.Equal(t, []string{"app1"}, res.Items[1].Apps)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/certificates/expiring?within=2400h", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = &models.ExpiringCertificateList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, "later", res.Items[2].Name)
+}
+
 func TestGetAppByCertificate(t *testing.T) {
 	api, router, mockCtl := initCertificateAPI(t)
 	defer mockCtl.Finish()
@@ -733,3 +834,291 @@ func TestGetAppByCertificate(t *testing.T) {
 	router.ServeHTTP(w4, req4)
 	assert.Equal(t, http.StatusOK, w4.Code)
 }
+
+func genTestIssuedCertificate(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, serial int64, dnsNames []string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, ca, &key.PublicKey, caKey)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func TestRotateCertificate(t *testing.T) {
+	api, router, mockCtl := initCertificateAPI(t)
+	defer mockCtl.Finish()
+
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		Secret: sSecret,
+	}
+	sPKI := ms.NewMockPKIService(mockCtl)
+	api.PKI = sPKI
+	fSecret := mf.NewMockFacade(mockCtl)
+	api.Facade = fSecret
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "baetyl.ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTpl, caTpl, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDer)
+	assert.NoError(t, err)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer})
+
+	oldCert, oldKey := genTestIssuedCertificate(t, ca, caKey, 2, []string{"test.baetyl.io"})
+	newCert, newKey := genTestIssuedCertificate(t, ca, caKey, 3, []string{"test.baetyl.io"})
+	now := time.Now()
+	cert := &models.Certificate{
+		Name:      "abc",
+		Namespace: "default",
+		Data:      models.CertificateDataItem{Key: oldKey, Certificate: oldCert},
+		CertId:    "old-id",
+		DeprecatedCerts: []models.DeprecatedCertificate{
+			{CertId: "gone-id", ExpireTime: now.Add(-time.Hour)},
+			{CertId: "keep-id", ExpireTime: now.Add(time.Hour)},
+		},
+	}
+	assert.NoError(t, cert.ParseCertInfo())
+	secret := cert.ToSecret()
+
+	// 400 invalid overlap
+	req, _ := http.NewRequest(http.MethodPost, "/v1/certificates/abc/rotate?overlap=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 404 not found
+	sSecret.EXPECT().Get("default", "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/certificates/abc/rotate", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 400 not issued by baetyl-cloud
+	foreign := &models.Certificate{
+		Name:      "abc",
+		Namespace: "default",
+		Data:      models.CertificateDataItem{Certificate: genTestCertificate(t, now.Add(time.Hour))},
+	}
+	sSecret.EXPECT().Get("default", "abc", "").Return(foreign.ToSecret(), nil).Times(1)
+	sPKI.EXPECT().GetCA().Return(caPEM, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/certificates/abc/rotate", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "isn't issued by baetyl-cloud")
+
+	// 200 rotated
+	altNames := models.AltNames{DNSNames: []string{"test.baetyl.io"}}
+	cred := &models.PEMCredential{CertPEM: []byte(newCert), KeyPEM: []byte(newKey), CertId: "new-id"}
+	sSecret.EXPECT().Get("default", "abc", "").Return(secret, nil).Times(1)
+	sPKI.EXPECT().GetCA().Return(caPEM, nil).Times(1)
+	sPKI.EXPECT().SignServerCertificate("test", altNames).Return(cred, nil).Times(1)
+	sPKI.EXPECT().DeleteServerCertificate("gone-id").Return(nil).Times(1)
+	var updated *specV1.Secret
+	fSecret.EXPECT().UpdateSecret("default", gomock.Any()).DoAndReturn(func(_ string, s *specV1.Secret) (*specV1.Secret, error) {
+		updated = s
+		return s, nil
+	}).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/certificates/abc/rotate?overlap=2h", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.Certificate{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, newCert, res.Data.Certificate)
+	assert.Empty(t, res.Data.Key)
+	assert.Equal(t, "3", res.SerialNumber)
+
+	rotated := models.FromSecretToCertificate(updated, false)
+	assert.Equal(t, "new-id", rotated.CertId)
+	assert.Equal(t, newKey, rotated.Data.Key)
+	assert.Len(t, rotated.DeprecatedCerts, 2)
+	assert.Equal(t, "keep-id", rotated.DeprecatedCerts[0].CertId)
+	assert.Equal(t, "old-id", rotated.DeprecatedCerts[1].CertId)
+	assert.WithinDuration(t, now.Add(2*time.Hour), rotated.DeprecatedCerts[1].ExpireTime, time.Minute)
+
+	// the new certificate is deleted if failed to update
+	sSecret.EXPECT().Get("default", "abc", "").Return(secret, nil).Times(1)
+	sPKI.EXPECT().GetCA().Return(caPEM, nil).Times(1)
+	sPKI.EXPECT().SignServerCertificate("test", altNames).Return(cred, nil).Times(1)
+	sPKI.EXPECT().DeleteServerCertificate("gone-id").Return(fmt.Errorf("error")).Times(1)
+	fSecret.EXPECT().UpdateSecret("default", gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	sPKI.EXPECT().DeleteServerCertificate("new-id").Return(nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/certificates/abc/rotate", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestReapDeprecatedCertificates(t *testing.T) {
+	api, _, mockCtl := initCertificateAPI(t)
+	defer mockCtl.Finish()
+
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{Secret: sSecret}
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	api.NS = sNS
+	sLocker := ms.NewMockLockerService(mockCtl)
+	api.Locker = sLocker
+	sPKI := ms.NewMockPKIService(mockCtl)
+	api.PKI = sPKI
+	fSecret := mf.NewMockFacade(mockCtl)
+	api.Facade = fSecret
+	api.log = log.L()
+
+	now := time.Now()
+	expired := (&models.Certificate{
+		Name:      "abc",
+		Namespace: "default",
+		CertId:    "cur-id",
+		DeprecatedCerts: []models.DeprecatedCertificate{
+			{CertId: "gone-id", ExpireTime: now.Add(-time.Hour)},
+			{CertId: "keep-id", Client: true, ExpireTime: now.Add(time.Hour)},
+		},
+	}).ToSecret()
+	valid := (&models.Certificate{
+		Name:            "def",
+		Namespace:       "default",
+		DeprecatedCerts: []models.DeprecatedCertificate{{CertId: "keep-id", ExpireTime: now.Add(time.Hour)}},
+	}).ToSecret()
+	selector := fmt.Sprintf("%s=%s", specV1.SecretLabel, specV1.SecretCertificate)
+
+	sNS.EXPECT().List(gomock.Any()).Return(&models.NamespaceList{Items: []models.Namespace{{Name: "default"}}}, nil).Times(1)
+	sSecret.EXPECT().List("default", &models.ListOptions{LabelSelector: selector}).
+		Return(&models.SecretList{Items: []specV1.Secret{*expired, *valid}}, nil).Times(1)
+	sLocker.EXPECT().Lock(gomock.Any(), "namespace_default", int64(0)).Return("v1", nil).Times(1)
+	sSecret.EXPECT().Get("default", "abc", "").Return(expired, nil).Times(1)
+	sPKI.EXPECT().DeleteServerCertificate("gone-id").Return(nil).Times(1)
+	fSecret.EXPECT().UpdateSecret("default", gomock.Any()).DoAndReturn(func(_ string, s *specV1.Secret) (*specV1.Secret, error) {
+		cert := models.FromSecretToCertificate(s, false)
+		assert.Equal(t, "cur-id", cert.CertId)
+		assert.Len(t, cert.DeprecatedCerts, 1)
+		assert.Equal(t, "keep-id", cert.DeprecatedCerts[0].CertId)
+		return s, nil
+	}).Times(1)
+	sLocker.EXPECT().Unlock(gomock.Any(), "namespace_default", "v1").Times(1)
+	api.reapDeprecatedCertificates()
+
+	// the certificate is kept as it is if failed to delete from pki
+	sNS.EXPECT().List(gomock.Any()).Return(&models.NamespaceList{Items: []models.Namespace{{Name: "default"}}}, nil).Times(1)
+	sSecret.EXPECT().List("default", gomock.Any()).Return(&models.SecretList{Items: []specV1.Secret{*expired}}, nil).Times(1)
+	sLocker.EXPECT().Lock(gomock.Any(), "namespace_default", int64(0)).Return("v2", nil).Times(1)
+	sSecret.EXPECT().Get("default", "abc", "").Return(expired, nil).Times(1)
+	sPKI.EXPECT().DeleteServerCertificate("gone-id").Return(fmt.Errorf("error")).Times(1)
+	sLocker.EXPECT().Unlock(gomock.Any(), "namespace_default", "v2").Times(1)
+	api.reapDeprecatedCertificates()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	api.certReapInterval = time.Millisecond * 10
+	sNS.EXPECT().List(gomock.Any()).Return(&models.NamespaceList{}, nil).MinTimes(1)
+	go func() {
+		api.RunCertificateReaper(stop)
+		close(done)
+	}()
+	time.Sleep(time.Millisecond * 50)
+	close(stop)
+	<-done
+}
+
+func genTestCSR(t *testing.T, key interface{}, tpl *x509.CertificateRequest) string {
+	der, err := x509.CreateCertificateRequest(rand.Reader, tpl, key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func TestSignCSR(t *testing.T) {
+	api, router, mockCtl := initCertificateAPI(t)
+	defer mockCtl.Finish()
+	sPKI := ms.NewMockPKIService(mockCtl)
+	api.PKI = sPKI
+	api.log = log.L()
+	api.csrPolicy = config.CSRPolicy{
+		MaxValidity:   30 * 24 * time.Hour,
+		KeyAlgorithms: []string{"RSA", "ECDSA"},
+		MinRSABits:    2048,
+		MinECDSABits:  256,
+	}
+	do := func(req *models.CSRRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		r, _ := http.NewRequest(http.MethodPost, "/v1/certificates/csr", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	csr := genTestCSR(t, key, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "default.client:console"}})
+	notAfter := time.Now().Add(10 * 24 * time.Hour).Truncate(time.Second).UTC()
+	cert, ca := genTestCertificate(t, notAfter), genTestCertificate(t, notAfter.Add(time.Hour))
+
+	sPKI.EXPECT().SignCertificateRequest(gomock.Any(), 10*24*time.Hour).Return(&models.PEMCredential{CertPEM: []byte(cert), CertId: "c0"}, nil).Times(1)
+	sPKI.EXPECT().GetCA().Return([]byte(ca), nil).Times(1)
+	w := do(&models.CSRRequest{CSR: csr, ValidityDays: 10})
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := new(models.CSRCertificate)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, "c0", res.CertId)
+	assert.Equal(t, cert, res.Certificate)
+	assert.Equal(t, cert+ca, res.Chain)
+	assert.Equal(t, notAfter, res.NotAfter.UTC())
+
+	// the max validity is given if not asked for
+	sPKI.EXPECT().SignCertificateRequest(gomock.Any(), 30*24*time.Hour).Return(&models.PEMCredential{CertPEM: []byte(cert), CertId: "c1"}, nil).Times(1)
+	sPKI.EXPECT().GetCA().Return([]byte(ca), nil).Times(1)
+	w = do(&models.CSRRequest{CSR: csr})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	other := genTestCSR(t, key, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "other.client:console"}})
+	// the common name of the node n0, which the certificate would act as on the sync link
+	node := genTestCSR(t, key, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "default.n0"}})
+	empty := genTestCSR(t, key, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "default.client:"}})
+	sans := genTestCSR(t, key, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "default.client:console"}, DNSNames: []string{"cloud.baetyl.io"}})
+	weak := genTestCSR(t, rsaKey, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "default.client:console"}})
+	cases := []struct {
+		req   *models.CSRRequest
+		error string
+	}{
+		{req: &models.CSRRequest{}, error: "required"},
+		{req: &models.CSRRequest{CSR: "invalid"}, error: "failed to find certificate request"},
+		{req: &models.CSRRequest{CSR: csr, ValidityDays: 31}, error: "the validity (31 days) is longer than the max (30 days)"},
+		{req: &models.CSRRequest{CSR: other}, error: "the common name (other.client:console) should be prefixed with the namespace and client:"},
+		{req: &models.CSRRequest{CSR: node}, error: "the common name (default.n0) should be prefixed with the namespace and client:"},
+		{req: &models.CSRRequest{CSR: empty}, error: "the common name (default.client:) should be prefixed"},
+		{req: &models.CSRRequest{CSR: sans}, error: "the subject alternative names are not allowed"},
+		{req: &models.CSRRequest{CSR: weak}, error: "the RSA key of 1024 bits is too short, at least 2048 bits"},
+	}
+	for _, tc := range cases {
+		w = do(tc.req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), tc.error)
+	}
+
+	api.csrPolicy.KeyAlgorithms = []string{"RSA"}
+	w = do(&models.CSRRequest{CSR: csr})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the algorithm (ECDSA) of the public key is not allowed, only RSA")
+}
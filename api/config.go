@@ -14,6 +14,7 @@ import (
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 const (
@@ -30,19 +31,42 @@ const (
 func (api *API) GetConfig(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
 	config, err := api.Config.Get(nil, ns, n, "")
+	if err = checkResourceFound(config, err, common.Config, n); err != nil {
+		return nil, err
+	}
+	setResourceETag(c, config.Version)
+	return api.ToConfigurationView(config)
+}
+
+// GetConfigVersions list the kept versions of a config, the latest first
+func (api *API) GetConfigVersions(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Config.ListVersions(ns, n)
+}
+
+// GetConfigVersion get a kept version of a config
+func (api *API) GetConfigVersion(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	config, err := api.Config.GetVersion(ns, n, c.Param("version"))
 	if err != nil {
 		return nil, err
 	}
 	return api.ToConfigurationView(config)
 }
 
-// ListConfig list config
+// ListConfig list config, sortBy is one of name, createTime and updateTime, and order is asc or desc
 func (api *API) ListConfig(c *common.Context) (interface{}, error) {
 	ns := c.GetNamespace()
 	params, err := api.ParseListOptionsAppendSystemLabel(c)
 	if err != nil {
 		return nil, err
 	}
+	if err = params.CheckCursorPaging(); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err = params.CheckSort(models.SortByName, models.SortByCreateTime, models.SortByUpdateTime); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
 	list, err := api.Config.List(ns, params)
 	if err != nil {
 		log.L().Error("list config error", log.Error(err))
@@ -81,6 +105,10 @@ func (api *API) CreateConfig(c *common.Context) (interface{}, error) {
 			common.Field("error", "this name is already in use"))
 	}
 
+	if err = api.checkConfigSchema(ns, config); err != nil {
+		return nil, err
+	}
+
 	config, err = api.Facade.CreateConfig(ns, config)
 	if err != nil {
 		return nil, err
@@ -101,6 +129,9 @@ func (api *API) UpdateConfig(c *common.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = checkResourceVersion(c, common.Config, res.Name, res.Version); err != nil {
+		return nil, err
+	}
 
 	// labels can't be modified of sys apps
 	if CheckIsSysResources(res.Labels) && !reflect.DeepEqual(res.Labels, config.Labels) {
@@ -111,6 +142,10 @@ func (api *API) UpdateConfig(c *common.Context) (interface{}, error) {
 		return api.ToConfigurationView(res)
 	}
 
+	if err = api.checkConfigSchema(ns, config); err != nil {
+		return nil, err
+	}
+
 	config.Version = res.Version
 	config.UpdateTimestamp = time.Now()
 	config.CreationTimestamp = res.CreationTimestamp
@@ -129,11 +164,14 @@ func (api *API) DeleteConfig(c *common.Context) (interface{}, error) {
 	res, err := api.Config.Get(nil, ns, n, "")
 	if err != nil {
 		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
-			return nil, nil
+			return nil, checkIfMatch(c, common.Config, n, "")
 		}
 		log.L().Error("get config failed", log.Error(err), log.Any("name", n), log.Any("namespace", ns))
 		return nil, err
 	}
+	if err = checkIfMatch(c, common.Config, n, res.Version); err != nil {
+		return nil, err
+	}
 
 	appNames, err := api.Index.ListAppIndexByConfig(ns, res.Name)
 	if err != nil {
@@ -142,9 +180,7 @@ func (api *API) DeleteConfig(c *common.Context) (interface{}, error) {
 	}
 
 	if len(appNames) > 0 {
-		return nil, common.Error(common.ErrResourceHasBeenUsed,
-			common.Field("type", "config"),
-			common.Field("name", n))
+		return nil, api.resourceHasBeenUsedError(ns, "config", n, appNames)
 	}
 
 	//TODO: should remove file(bos/aws) of a function Config
@@ -215,6 +251,17 @@ func (api *API) parseAndCheckConfigView(c *common.Context) (*specV1.Configuratio
 		return nil, err
 	}
 
+	if configView.Template {
+		// the content is rendered for each node, it can't be validated against a schema in advance
+		if configView.Schema != "" {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "a config template can't be validated against a schema"))
+		}
+		if err = service.CheckConfigTemplate(config); err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+	}
+
 	return config, nil
 }
 
@@ -224,6 +271,18 @@ func (api *API) ToConfigurationView(config *specV1.Configuration) (*models.Confi
 	if err != nil {
 		return nil, err
 	}
+	schema, isSchema := config.Labels[common.LabelConfigSchema]
+	_, isTemplate := config.Labels[common.LabelConfigTemplate]
+	if isSchema || isTemplate {
+		configView.Schema = schema
+		configView.Template = isTemplate
+		configView.Labels = map[string]string{}
+		for k, v := range config.Labels {
+			if k != common.LabelConfigSchema && k != common.LabelConfigTemplate {
+				configView.Labels[k] = v
+			}
+		}
+	}
 
 	for k, v := range config.Data {
 		obj := models.ConfigDataItem{
@@ -261,6 +320,19 @@ func (api *API) ToConfiguration(userID string, configView *models.ConfigurationV
 	if err != nil {
 		return nil, err
 	}
+	if configView.Schema != "" || configView.Template {
+		labels := map[string]string{}
+		for k, v := range configView.Labels {
+			labels[k] = v
+		}
+		if configView.Schema != "" {
+			labels[common.LabelConfigSchema] = configView.Schema
+		}
+		if configView.Template {
+			labels[common.LabelConfigTemplate] = "true"
+		}
+		config.Labels = labels
+	}
 
 	config.Data = map[string]string{}
 	for _, v := range configView.Data {
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	openapiErrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/yaml"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ConfigSchemaKey the key of the data item holding the JSON schema in a schema config
+const ConfigSchemaKey = "schema.json"
+
+// ValidateConfig validate the candidate content of a config against its schema without saving it,
+// the schema of the stored config is used if the payload doesn't name one
+func (api *API) ValidateConfig(c *common.Context) (interface{}, error) {
+	config, err := api.parseAndCheckConfigView(c)
+	if err != nil {
+		return nil, err
+	}
+	ns := c.GetNamespace()
+	if _, ok := config.Labels[common.LabelConfigSchema]; !ok {
+		old, err := api.Config.Get(nil, ns, config.Name, "")
+		if err != nil {
+			if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+				return nil, err
+			}
+		}
+		if old != nil {
+			if schema, ok := old.Labels[common.LabelConfigSchema]; ok {
+				if config.Labels == nil {
+					config.Labels = map[string]string{}
+				}
+				config.Labels[common.LabelConfigSchema] = schema
+			}
+		}
+	}
+	return api.validateConfigSchema(ns, config)
+}
+
+// checkConfigSchema rejects the config if its content doesn't match the schema attached
+func (api *API) checkConfigSchema(ns string, config *specV1.Configuration) error {
+	res, err := api.validateConfigSchema(ns, config)
+	if err != nil {
+		return err
+	}
+	if res.Valid {
+		return nil
+	}
+	msgs := make([]string, 0, len(res.Errors))
+	for _, e := range res.Errors {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", e.Field, e.Message))
+	}
+	return common.Error(common.ErrRequestParamInvalid, common.Field("error",
+		fmt.Sprintf("the config doesn't match the schema (%s): %s", res.Schema, strings.Join(msgs, "; "))))
+}
+
+// validateConfigSchema validates the kv items of the config against the schema attached, the items named *.json,
+// *.yaml or *.yml are decoded before validating, the others are validated as strings
+func (api *API) validateConfigSchema(ns string, config *specV1.Configuration) (*models.ConfigValidation, error) {
+	name := config.Labels[common.LabelConfigSchema]
+	res := &models.ConfigValidation{Schema: name, Valid: true}
+	if name == "" {
+		return res, nil
+	}
+	if name == config.Name {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "a config can't be validated against itself"))
+	}
+	schema, err := api.getConfigSchema(ns, name)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]interface{}{}
+	for k, v := range config.Data {
+		if strings.HasPrefix(k, common.ConfigObjectPrefix) {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(k)) {
+		case ".json", ".yaml", ".yml":
+			var value interface{}
+			if err = yaml.Unmarshal([]byte(v), &value); err != nil {
+				res.Errors = append(res.Errors, models.ConfigValidationError{Field: k, Message: err.Error()})
+				continue
+			}
+			doc[k] = value
+		default:
+			doc[k] = v
+		}
+	}
+
+	result := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(doc)
+	for _, e := range result.Errors {
+		item := models.ConfigValidationError{Message: e.Error()}
+		if v, ok := e.(*openapiErrors.Validation); ok {
+			item.Field = strings.TrimPrefix(v.Name, ".")
+		}
+		res.Errors = append(res.Errors, item)
+	}
+	sort.SliceStable(res.Errors, func(i, j int) bool {
+		return res.Errors[i].Field < res.Errors[j].Field
+	})
+	res.Valid = len(res.Errors) == 0
+	return res, nil
+}
+
+func (api *API) getConfigSchema(ns, name string) (*spec.Schema, error) {
+	cfg, err := api.Config.Get(nil, ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	content, ok := cfg.Data[ConfigSchemaKey]
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error",
+			fmt.Sprintf("the config (%s) isn't a schema, the item (%s) is missing", name, ConfigSchemaKey)))
+	}
+	schema := new(spec.Schema)
+	if err = json.Unmarshal([]byte(content), schema); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error",
+			fmt.Sprintf("the schema (%s) is invalid: %s", name, err.Error())))
+	}
+	return schema, nil
+}
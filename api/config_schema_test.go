@@ -0,0 +1,196 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+const testConfigSchema = `{
+  "type": "object",
+  "required": ["conf.json"],
+  "properties": {
+    "mode": {"type": "string", "enum": ["debug", "release"]},
+    "conf.json": {
+      "type": "object",
+      "required": ["port"],
+      "properties": {"port": {"type": "integer", "minimum": 1, "maximum": 65535}}
+    }
+  }
+}`
+
+func newSchemaConfigView(mode, conf string) *models.ConfigurationView {
+	return &models.ConfigurationView{
+		Name:   "abc",
+		Schema: "abc-schema",
+		Data: []models.ConfigDataItem{
+			{Key: "mode", Value: map[string]string{"type": ConfigTypeKV, "value": mode}},
+			{Key: "conf.json", Value: map[string]string{"type": ConfigTypeKV, "value": conf}},
+		},
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	api, router, mockCtl := initConfigAPI(t)
+	defer mockCtl.Finish()
+
+	sConfig := ms.NewMockConfigService(mockCtl)
+	fConfig := mf.NewMockFacade(mockCtl)
+	api.Facade = fConfig
+	api.AppCombinedService = &service.AppCombinedService{Config: sConfig}
+
+	schema := &specV1.Configuration{
+		Name:      "abc-schema",
+		Namespace: "default",
+		Data:      map[string]string{ConfigSchemaKey: testConfigSchema},
+	}
+
+	// create with valid content, the schema is kept as a label
+	sConfig.EXPECT().Get(nil, "default", "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "abc-schema", "").Return(schema, nil).Times(1)
+	fConfig.EXPECT().CreateConfig("default", gomock.Any()).DoAndReturn(func(_ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Equal(t, "abc-schema", cfg.Labels[common.LabelConfigSchema])
+		return cfg, nil
+	}).Times(1)
+	w := httptest.NewRecorder()
+	body, _ := json.Marshal(newSchemaConfigView("debug", `{"port": 8080}`))
+	req, _ := http.NewRequest(http.MethodPost, "/v1/configs", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	view := new(models.ConfigurationView)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, "abc-schema", view.Schema)
+	assert.NotContains(t, view.Labels, common.LabelConfigSchema)
+
+	// create with invalid content
+	sConfig.EXPECT().Get(nil, "default", "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "abc-schema", "").Return(schema, nil).Times(1)
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(newSchemaConfigView("test", `{"port": 70000}`))
+	req, _ = http.NewRequest(http.MethodPost, "/v1/configs", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the config doesn't match the schema (abc-schema)")
+	assert.Contains(t, w.Body.String(), "conf.json.port")
+
+	// update with content failed to be decoded
+	old := &specV1.Configuration{
+		Name:      "abc",
+		Namespace: "default",
+		Labels:    map[string]string{common.LabelConfigSchema: "abc-schema"},
+		Data:      map[string]string{"mode": "debug", "conf.json": `{"port": 8080}`},
+	}
+	sConfig.EXPECT().Get(nil, "default", "abc", "").Return(old, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "abc-schema", "").Return(schema, nil).Times(1)
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(newSchemaConfigView("debug", `{"port":`))
+	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/abc", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "conf.json")
+
+	// validate with the schema of the stored config
+	view = newSchemaConfigView("test", `{"port": 0}`)
+	view.Schema = ""
+	sConfig.EXPECT().Get(nil, "default", "abc", "").Return(old, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "abc-schema", "").Return(schema, nil).Times(1)
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(view)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/configs/abc/validate", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := new(models.ConfigValidation)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.False(t, res.Valid)
+	assert.Equal(t, "abc-schema", res.Schema)
+	assert.Len(t, res.Errors, 2)
+	assert.Equal(t, "conf.json.port", res.Errors[0].Field)
+	assert.Equal(t, "mode", res.Errors[1].Field)
+
+	// validate a new config without schema
+	sConfig.EXPECT().Get(nil, "default", "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(view)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/configs/abc/validate", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = new(models.ConfigValidation)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.True(t, res.Valid)
+
+	// the schema config doesn't hold a schema
+	sConfig.EXPECT().Get(nil, "default", "abc-schema", "").Return(&specV1.Configuration{Name: "abc-schema"}, nil).Times(1)
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(newSchemaConfigView("debug", `{"port": 8080}`))
+	req, _ = http.NewRequest(http.MethodPost, "/v1/configs/abc/validate", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "isn't a schema")
+}
+
+func TestConfigTemplate(t *testing.T) {
+	api, router, mockCtl := initConfigAPI(t)
+	defer mockCtl.Finish()
+
+	sConfig := ms.NewMockConfigService(mockCtl)
+	fConfig := mf.NewMockFacade(mockCtl)
+	api.Facade = fConfig
+	api.AppCombinedService = &service.AppCombinedService{Config: sConfig}
+
+	view := &models.ConfigurationView{
+		Name:     "abc",
+		Labels:   map[string]string{"a": "b"},
+		Template: true,
+		Data: []models.ConfigDataItem{
+			{Key: "conf.yml", Value: map[string]string{"type": ConfigTypeKV, "value": "name: {{ .node.name }}"}},
+		},
+	}
+
+	// the template flag is kept as a label
+	sConfig.EXPECT().Get(nil, "default", "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	fConfig.EXPECT().CreateConfig("default", gomock.Any()).DoAndReturn(func(_ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Equal(t, map[string]string{"a": "b", common.LabelConfigTemplate: "true"}, cfg.Labels)
+		return cfg, nil
+	}).Times(1)
+	w := httptest.NewRecorder()
+	body, _ := json.Marshal(view)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/configs", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := new(models.ConfigurationView)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.True(t, res.Template)
+	assert.Equal(t, map[string]string{"a": "b"}, res.Labels)
+	assert.Equal(t, "name: {{ .node.name }}", res.Data[0].Value["value"])
+
+	// invalid syntax
+	view.Data[0].Value["value"] = "name: {{ .node.name "
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(view)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/configs", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the template of the item (conf.yml) is invalid")
+
+	// a template can't be validated against a schema
+	view.Data[0].Value["value"] = "name: {{ .node.name }}"
+	view.Schema = "abc-schema"
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(view)
+	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/abc", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "a config template can't be validated against a schema")
+}
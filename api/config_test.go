@@ -38,6 +38,9 @@ func initConfigAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		configs := v1.Group("/configs")
 		configs.GET("/:name", mockIM, common.Wrapper(api.GetConfig))
 		configs.GET("/:name/apps", mockIM, common.Wrapper(api.GetAppByConfig))
+		configs.GET("/:name/versions", mockIM, common.Wrapper(api.GetConfigVersions))
+		configs.GET("/:name/versions/:version", mockIM, common.Wrapper(api.GetConfigVersion))
+		configs.POST("/:name/validate", mockIM, common.Wrapper(api.ValidateConfig))
 		configs.PUT("/:name", mockIM, common.Wrapper(api.UpdateConfig))
 		configs.DELETE("/:name", mockIM, common.Wrapper(api.DeleteConfig))
 		configs.POST("", mockIM, common.Wrapper(api.CreateConfig))
@@ -63,16 +66,18 @@ func TestGetConfig(t *testing.T) {
 	mConf := &specV1.Configuration{
 		Namespace: "default",
 		Name:      "abc",
+		Version:   "3",
 	}
 
 	sConfig.EXPECT().Get(nil, mConf.Namespace, mConf.Name, "").Return(mConf, nil)
 	sConfig.EXPECT().Get(nil, mConf.Namespace, "cba", "").Return(nil, fmt.Errorf("error"))
 
-	// 200
+	// 200 tagged by the version
 	req, _ := http.NewRequest(http.MethodGet, "/v1/configs/abc", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `W/"3"`, w.Header().Get(common.HeaderETag))
 
 	// 404
 	req, _ = http.NewRequest(http.MethodGet, "/v1/configs/cba", nil)
@@ -81,6 +86,44 @@ func TestGetConfig(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w2.Code)
 }
 
+func TestGetConfigVersions(t *testing.T) {
+	api, router, mockCtl := initConfigAPI(t)
+	defer mockCtl.Finish()
+	sConfig := ms.NewMockConfigService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{Config: sConfig}
+
+	list := &models.ResourceVersionList{Total: 2, Items: []models.ResourceVersion{
+		{Namespace: "default", Kind: models.ResourceVersionConfig, Name: "abc", Version: "2"},
+		{Namespace: "default", Kind: models.ResourceVersionConfig, Name: "abc", Version: "1"},
+	}}
+	sConfig.EXPECT().ListVersions("default", "abc").Return(list, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/configs/abc/versions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.ResourceVersionList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, "2", res.Items[0].Version)
+
+	mConf := &specV1.Configuration{Namespace: "default", Name: "abc", Version: "1", Data: map[string]string{"a": "1"}}
+	sConfig.EXPECT().GetVersion("default", "abc", "1").Return(mConf, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs/abc/versions/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	view := &models.ConfigurationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, "1", view.Version)
+
+	sConfig.EXPECT().GetVersion("default", "abc", "0").Return(nil, common.Error(common.ErrResourceNotFound,
+		common.Field("type", "config version"), common.Field("name", "abc@0"))).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs/abc/versions/0", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 func TestListConfig(t *testing.T) {
 	api, router, mockCtl := initConfigAPI(t)
 	defer mockCtl.Finish()
@@ -115,6 +158,76 @@ func TestListConfig(t *testing.T) {
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 400 invalid cursor
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs?cursor=invalid", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 200 next cursor
+	sConfig.EXPECT().List("default", &models.ListOptions{
+		LabelSelector: "!" + common.LabelSystem,
+		Limit:         2,
+		Cursor:        models.EncodeCursor(10),
+		CursorPaging:  true,
+	}).DoAndReturn(func(_ string, opt *models.ListOptions) (*models.ConfigurationList, error) {
+		opt.NextCursor = models.EncodeCursor(8)
+		return &models.ConfigurationList{ListOptions: opt}, nil
+	})
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs?limit=2&cursor="+models.EncodeCursor(10), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"nextCursor":"`+models.EncodeCursor(8)+`"`)
+
+	// 200 the first page is fetched by an empty cursor, and a limit alone does not switch to the cursor
+	sConfig.EXPECT().List("default", &models.ListOptions{
+		LabelSelector: "!" + common.LabelSystem,
+		Limit:         2,
+		CursorPaging:  true,
+	}).Return(mClist, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs?limit=2&cursor=", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	sConfig.EXPECT().List("default", &models.ListOptions{
+		LabelSelector: "!" + common.LabelSystem,
+		Limit:         2,
+	}).Return(mClist, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs?limit=2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 200 offset pagination is deprecated
+	sConfig.EXPECT().List("default", gomock.Any()).DoAndReturn(func(_ string, opt *models.ListOptions) (*models.ConfigurationList, error) {
+		return &models.ConfigurationList{ListOptions: opt}, nil
+	})
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs?pageNo=1&pageSize=2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), models.DeprecatedOffsetPaging)
+
+	// 200 sorted by update time
+	sConfig.EXPECT().List("default", &models.ListOptions{
+		LabelSelector: "!" + common.LabelSystem,
+		SortBy:        models.SortByUpdateTime,
+		Order:         models.SortOrderDesc,
+	}).Return(mClist, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs?sortBy=updateTime&order=desc", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 400 invalid sort
+	for _, query := range []string{"sortBy=version", "order=up", "sortBy=name&limit=2&cursor=", "sortBy=name&cursor=" + models.EncodeCursor(10)} {
+		req, _ = http.NewRequest(http.MethodGet, "/v1/configs?"+query, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
 }
 
 func TestCreateConfig(t *testing.T) {
@@ -602,6 +715,23 @@ func TestUpdateConfig(t *testing.T) {
 	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/"+name, bytes.NewReader(body))
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 409: the config was modified since the version read
+	res3.Version = "2"
+	sConfig.EXPECT().Get(nil, ns, name, gomock.Any()).Return(res3, nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/"+name+"?resourceVersion=1", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrVersionConflict")
+	assert.Contains(t, w.Body.String(), "the current version is 2, not 1")
+
+	sConfig.EXPECT().Get(nil, ns, name, gomock.Any()).Return(res3, nil).Times(1)
+	fConfig.EXPECT().UpdateConfig(ns, gomock.Any()).Return(res, nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/"+name+"?resourceVersion=2", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
 }
 
 func TestUpdateSysConfig(t *testing.T) {
@@ -725,10 +855,12 @@ func TestDeleteConfig(t *testing.T) {
 	// 403
 	sConfig.EXPECT().Get(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(mConf, nil)
 	sIndex.EXPECT().ListAppIndexByConfig(gomock.Any(), gomock.Any()).Return(appNames, nil)
+	sApp.EXPECT().ListByNames(mConf.Namespace, appNames).Return([]models.AppItem{{Name: "app01", Version: "12"}}, nil).Times(1)
 	req, _ = http.NewRequest(http.MethodDelete, "/v1/configs/abc", nil)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "has been used by the applications (app01:12)")
 
 	// 200
 	sConfig.EXPECT().Get(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(mConf, nil)
@@ -746,6 +878,42 @@ func TestDeleteConfig(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 
+	// 412: the config was modified or deleted since the version read
+	mConf.Version = "2"
+	sConfig.EXPECT().Get(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(mConf, nil)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/configs/abc", nil)
+	req.Header.Set(common.HeaderIfMatch, "1")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Contains(t, w.Body.String(), "is of the version 2, not 1")
+
+	sConfig.EXPECT().Get(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, common.Error(common.ErrResourceNotFound))
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/configs/abc", nil)
+	req.Header.Set(common.HeaderIfMatch, "2")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Contains(t, w.Body.String(), "does not exist, not 2")
+
+	sConfig.EXPECT().Get(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(mConf, nil)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/configs/abc", nil)
+	req.Header.Set(common.HeaderIfMatch, `W/"1", "3"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	// the header carries the raw version, or the etag of the config got
+	for _, v := range []string{"2", " 2 ", "*", `W/"2"`, `"2"`, `W/"1", W/"2"`} {
+		sConfig.EXPECT().Get(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(mConf, nil)
+		sIndex.EXPECT().ListAppIndexByConfig(gomock.Any(), gomock.Any()).Return(nil, nil)
+		fConfig.EXPECT().DeleteConfig(mConf.Namespace, mConf.Name).Return(nil)
+		req, _ = http.NewRequest(http.MethodDelete, "/v1/configs/abc", nil)
+		req.Header.Set(common.HeaderIfMatch, v)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, v)
+	}
 }
 
 func TestGetAppByConfig(t *testing.T) {
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// DefaultCSRMaxValidity the default max validity of the certificates signed for the csrs
+const DefaultCSRMaxValidity = 365 * 24 * time.Hour
+
+// csrClientPrefix the prefix of the name after the namespace in the common name of the csrs, which is never a valid
+// node name, so the certificates signed can't act as the nodes on the sync link
+const csrClientPrefix = "client:"
+
+// SignCSR sign the certificate signing request of the client with the ca of baetyl-cloud, the private key never
+// leaves the client. The common name of the csr is <namespace>.client:<name>, so the certificate can't act as
+// a node, and the validity, the key and the alternative names are limited by the csr policy
+func (api *API) SignCSR(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	req := new(models.CSRRequest)
+	if err := c.LoadBody(req); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	csr, err := api.parseAndCheckCSR(ns, req.CSR)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	maxValidity := api.csrPolicy.MaxValidity
+	if maxValidity <= 0 {
+		maxValidity = DefaultCSRMaxValidity
+	}
+	validity := maxValidity
+	if req.ValidityDays > 0 {
+		validity = time.Duration(req.ValidityDays) * 24 * time.Hour
+	}
+	if validity > maxValidity {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the validity (%d days) is longer than the max (%d days)", req.ValidityDays, int(maxValidity.Hours()/24))))
+	}
+
+	cred, err := api.PKI.SignCertificateRequest(csr.Raw, validity)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := parseCertificate(string(cred.CertPEM))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ca, err := api.PKI.GetCA()
+	if err != nil {
+		return nil, err
+	}
+	api.log.Info("csr is signed", log.Any(c.GetTrace()), log.Any("namespace", ns),
+		log.Any("commonName", csr.Subject.CommonName), log.Any("certId", cred.CertId))
+	return &models.CSRCertificate{
+		CertId:      cred.CertId,
+		Certificate: string(cred.CertPEM),
+		Chain:       strings.TrimRight(string(cred.CertPEM), "\n") + "\n" + string(ca),
+		NotAfter:    cert.NotAfter,
+	}, nil
+}
+
+// parseAndCheckCSR parse the pem of the csr, verify its signature and check it against the csr policy
+func (api *API) parseAndCheckCSR(ns, data string) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil || (block.Type != "CERTIFICATE REQUEST" && block.Type != "NEW CERTIFICATE REQUEST") {
+		return nil, errors.New("failed to find certificate request")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return nil, errors.Errorf("the signature of the certificate request is invalid: %s", err.Error())
+	}
+	cn := csr.Subject.CommonName
+	if prefix := ns + "." + csrClientPrefix; !strings.HasPrefix(cn, prefix) || len(cn) == len(prefix) {
+		return nil, errors.Errorf("the common name (%s) should be prefixed with the namespace and %s, such as %s.%sconsole",
+			cn, csrClientPrefix, ns, csrClientPrefix)
+	}
+	if !api.csrPolicy.AllowSANs &&
+		(len(csr.DNSNames) > 0 || len(csr.IPAddresses) > 0 || len(csr.EmailAddresses) > 0 || len(csr.URIs) > 0) {
+		return nil, errors.New("the subject alternative names are not allowed")
+	}
+	if err = api.checkCSRKey(csr.PublicKey); err != nil {
+		return nil, err
+	}
+	return csr, nil
+}
+
+// checkCSRKey check the algorithm and the size of the public key
+func (api *API) checkCSRKey(key interface{}) error {
+	var alg string
+	var bits, min int
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		alg, bits, min = "RSA", k.N.BitLen(), api.csrPolicy.MinRSABits
+	case *ecdsa.PublicKey:
+		alg, bits, min = "ECDSA", k.Curve.Params().BitSize, api.csrPolicy.MinECDSABits
+	case ed25519.PublicKey:
+		alg, bits = "Ed25519", ed25519.PublicKeySize*8
+	default:
+		return errors.New("the algorithm of the public key is not supported")
+	}
+	allowed := false
+	for _, a := range api.csrPolicy.KeyAlgorithms {
+		if strings.EqualFold(a, alg) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errors.Errorf("the algorithm (%s) of the public key is not allowed, only %s", alg, strings.Join(api.csrPolicy.KeyAlgorithms, ", "))
+	}
+	if bits < min {
+		return errors.Errorf("the %s key of %d bits is too short, at least %d bits", alg, bits, min)
+	}
+	return nil
+}
@@ -99,6 +99,42 @@ func (api *API) ImportFunction(c *common.Context) (interface{}, error) {
 	}, nil
 }
 
+// InvokeFunction invoke a version of the function with the json payload in the body, returns the result and execution logs
+func (api *API) InvokeFunction(c *common.Context) (interface{}, error) {
+	id, name, version, source := c.GetUser().ID, c.Param("name"), c.Param("version"), c.Param("source")
+	return api.Func.Invoke(id, name, version, source, c.Request.Body)
+}
+
+// UploadFunction upload the zip archive of the code as a version of the function of the source, such as a local source,
+// the archive is the file of the multipart form, with the runtime and the handler of the function
+func (api *API) UploadFunction(c *common.Context) (interface{}, error) {
+	id, name, version, source := c.GetUser().ID, c.Param("name"), c.Param("version"), c.Param("source")
+	function := &models.Function{
+		Name:    name,
+		Version: version,
+		Runtime: c.PostForm("runtime"),
+		Handler: c.PostForm("handler"),
+	}
+	if function.Runtime == "" || function.Handler == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "runtime and handler are required"))
+	}
+	runtimes, err := api.Func.ListRuntimes()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := runtimes[strings.ToLower(function.Runtime)]; !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the runtime (%s) is not supported", function.Runtime)))
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	defer file.Close()
+	return api.Func.Upload(id, source, function, file)
+}
+
 func base64ToHex(s string) (string, error) {
 	b, err := base64.StdEncoding.DecodeString(s)
 	if err != nil {
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -32,6 +34,8 @@ func initFunctionAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		function.GET("/:source/functions", mockIM, common.Wrapper(api.ListFunctions))
 		function.GET("/:source/functions/:name/versions", mockIM, common.Wrapper(api.ListFunctionVersions))
 		function.POST("/:source/functions/:name/versions/:version", mockIM, common.Wrapper(api.ImportFunction))
+		function.POST("/:source/functions/:name/versions/:version/invoke", mockIM, common.Wrapper(api.InvokeFunction))
+		function.POST("/:source/functions/:name/versions/:version/upload", mockIM, common.Wrapper(api.UploadFunction))
 	}
 	return api, router, mockCtl
 }
@@ -252,3 +256,94 @@ func TestImportFunction(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+func TestInvokeFunction(t *testing.T) {
+	api, router, mockCtl := initFunctionAPI(t)
+	defer mockCtl.Finish()
+	sFunc := ms.NewMockFunctionService(mockCtl)
+	api.Func = sFunc
+
+	res := &models.FunctionInvocation{Result: `{"b":1}`, Logs: "START"}
+	sFunc.EXPECT().Invoke("default", "abc", "1", "baiducfc", gomock.Any()).Return(res, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/functions/baiducfc/functions/abc/versions/1/invoke", bytes.NewReader([]byte(`{"a":1}`)))
+	re := httptest.NewRecorder()
+	router.ServeHTTP(re, req)
+	assert.Equal(t, http.StatusOK, re.Code)
+	out := new(models.FunctionInvocation)
+	assert.NoError(t, json.Unmarshal(re.Body.Bytes(), out))
+	assert.Equal(t, res, out)
+
+	sFunc.EXPECT().Invoke("default", "abc", "1", "baiducfc", gomock.Any()).Return(nil,
+		common.Error(common.ErrRequestParamInvalid, common.Field("error", "the payload should be json"))).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/functions/baiducfc/functions/abc/versions/1/invoke", bytes.NewReader([]byte(`{`)))
+	re = httptest.NewRecorder()
+	router.ServeHTTP(re, req)
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+
+	sFunc.EXPECT().Invoke("default", "abc", "1", "baiducfc", gomock.Any()).Return(nil,
+		common.Error(common.ErrGatewayTimeout, common.Field("type", "function"), common.Field("name", "abc"))).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/functions/baiducfc/functions/abc/versions/1/invoke", bytes.NewReader([]byte(`{"a":1}`)))
+	re = httptest.NewRecorder()
+	router.ServeHTTP(re, req)
+	assert.Equal(t, http.StatusGatewayTimeout, re.Code)
+}
+
+func TestUploadFunction(t *testing.T) {
+	api, router, mockCtl := initFunctionAPI(t)
+	defer mockCtl.Finish()
+	sFunc := ms.NewMockFunctionService(mockCtl)
+	api.Func = sFunc
+
+	upload := func(fields map[string]string, archive []byte) *httptest.ResponseRecorder {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		for k, v := range fields {
+			assert.NoError(t, writer.WriteField(k, v))
+		}
+		if archive != nil {
+			part, err := writer.CreateFormFile("file", "code.zip")
+			assert.NoError(t, err)
+			_, err = part.Write(archive)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, writer.Close())
+		req, _ := http.NewRequest(http.MethodPost, "/v1/functions/local/functions/abc/versions/1/upload", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		re := httptest.NewRecorder()
+		router.ServeHTTP(re, req)
+		return re
+	}
+	runtimes := map[string]string{"python3": "python3-image"}
+	fields := map[string]string{"runtime": "python3", "handler": "index.handler"}
+
+	res := &models.Function{Name: "abc", Version: "1", Runtime: "python3", Handler: "index.handler",
+		Code: models.FunctionCode{Size: 4, Sha256: "sha", Location: "file:///abc/1.zip"}}
+	sFunc.EXPECT().ListRuntimes().Return(runtimes, nil).Times(1)
+	sFunc.EXPECT().Upload("default", "local", &models.Function{Name: "abc", Version: "1", Runtime: "python3", Handler: "index.handler"}, gomock.Any()).Return(res, nil).Times(1)
+	re := upload(fields, []byte("code"))
+	assert.Equal(t, http.StatusOK, re.Code)
+	out := new(models.Function)
+	assert.NoError(t, json.Unmarshal(re.Body.Bytes(), out))
+	assert.Equal(t, res, out)
+
+	// the invalid archive is rejected by the service
+	sFunc.EXPECT().ListRuntimes().Return(runtimes, nil).Times(1)
+	sFunc.EXPECT().Upload("default", "local", gomock.Any(), gomock.Any()).Return(nil,
+		common.Error(common.ErrRequestParamInvalid, common.Field("error", "the archive should be a zip"))).Times(1)
+	re = upload(fields, []byte("code"))
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+
+	// no archive
+	sFunc.EXPECT().ListRuntimes().Return(runtimes, nil).Times(1)
+	re = upload(fields, nil)
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+
+	// unsupported runtime
+	sFunc.EXPECT().ListRuntimes().Return(runtimes, nil).Times(1)
+	re = upload(map[string]string{"runtime": "go", "handler": "main"}, []byte("code"))
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+	assert.Contains(t, re.Body.String(), "the runtime (go) is not supported")
+
+	re = upload(map[string]string{"runtime": "python3"}, []byte("code"))
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+}
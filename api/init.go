@@ -42,6 +42,7 @@ func (api *InitAPI) GetResource(c *common.Context) (interface{}, error) {
 		InitApplyYaml string `form:"initApplyYaml,omitempty"`
 		Mode          string `form:"mode,omitempty"`
 		Path          string `form:"path,omitempty"`
+		NodeToken     string `form:"nodeToken,omitempty"`
 	}{}
 	err := c.Bind(query)
 	if err != nil {
@@ -55,13 +56,25 @@ func (api *InitAPI) GetResource(c *common.Context) (interface{}, error) {
 			common.ErrRequestParamInvalid,
 			common.Field("error", err))
 	}
-	return api.Init.GetResource(data[service.InfoNamespace].(string), data[service.InfoName].(string), resourceName, map[string]interface{}{
+	// the node tokens are signed the same way, but they are checked against the revocation by CheckNodeToken only
+	if _, ok := data[service.InfoNodeToken]; ok {
+		log.L().Info("node token is not accepted to get the init resources")
+		return nil, common.Error(
+			common.ErrRequestParamInvalid,
+			common.Field("error", common.Error(common.ErrInvalidToken)))
+	}
+	params := map[string]interface{}{
 		"Token":          query.Token,
 		"KubeNodeName":   query.Node,
 		"InitApplyYaml":  query.InitApplyYaml,
 		"Mode":           query.Mode,
 		"BaetylHostPath": query.Path,
-	})
+		"NodeToken":      query.NodeToken,
+	}
+	if id, ok := data[service.InfoOneTime].(string); ok {
+		params["InitTokenID"] = id
+	}
+	return api.Init.GetResource(data[service.InfoNamespace].(string), data[service.InfoName].(string), resourceName, params)
 }
 
 func CheckAndParseToken(token string, genToken func(map[string]interface{}) (string, error)) (map[string]interface{}, error) {
@@ -122,3 +122,59 @@ func TestInitAPIImpl_CheckAndParseToken(t *testing.T) {
 	assert.Equal(t, info[service.InfoName], res[service.InfoName].(string))
 	assert.Equal(t, info[service.InfoNamespace], res[service.InfoNamespace].(string))
 }
+
+func TestInitAPIImpl_GetResourceOneTime(t *testing.T) {
+	api, router, mockCtl := initInitAPI(t)
+	defer mockCtl.Finish()
+	mInit := ms.NewMockInitService(mockCtl)
+	api.Init = mInit
+	mSign := ms.NewMockSignService(mockCtl)
+	api.Sign = mSign
+
+	info := map[string]interface{}{
+		service.InfoName:      "n0",
+		service.InfoNamespace: "default",
+		service.InfoExpiry:    time.Now().Unix() + 60,
+		service.InfoOneTime:   "id",
+	}
+	data, err := json.Marshal(info)
+	assert.NoError(t, err)
+	token := "0123456789" + hex.EncodeToString(data)
+
+	mSign.EXPECT().GenToken(gomock.Any()).Return(token, nil).Times(1)
+	mInit.EXPECT().GetResource("default", "n0", "baetyl-install.sh", gomock.Any()).DoAndReturn(
+		func(_, _, _ string, params map[string]interface{}) (interface{}, error) {
+			assert.Equal(t, "id", params["InitTokenID"])
+			return []byte("shell"), nil
+		}).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/init/baetyl-install.sh?token="+token, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestInitAPIImpl_GetResourceNodeToken(t *testing.T) {
+	api, router, mockCtl := initInitAPI(t)
+	defer mockCtl.Finish()
+	mInit := ms.NewMockInitService(mockCtl)
+	api.Init = mInit
+	mSign := ms.NewMockSignService(mockCtl)
+	api.Sign = mSign
+
+	// the node token without its prefix
+	info := map[string]interface{}{
+		service.InfoName:      "n0",
+		service.InfoNamespace: "default",
+		service.InfoExpiry:    time.Now().Unix() + 60,
+		service.InfoNodeToken: "id",
+	}
+	data, err := json.Marshal(info)
+	assert.NoError(t, err)
+	token := "0123456789" + hex.EncodeToString(data)
+
+	mSign.EXPECT().GenToken(gomock.Any()).Return(token, nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/init/baetyl-install.sh?token="+token, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// errJobCanceled stops the running job canceled
+var errJobCanceled = errors.New("the job is canceled")
+
+// jobProgress reports the items done of the job, it returns an error once the job is canceled,
+// so that the job stops at its next item
+type jobProgress func(done, total int) error
+
+// jobFunc the work of a job, which is run with a copy of the context of the request starting it
+type jobFunc func(c *common.Context, progress jobProgress) (interface{}, error)
+
+// GetJob gets the status, progress and result of the job
+func (api *API) GetJob(c *common.Context) (interface{}, error) {
+	id, err := parseJobID(c)
+	if err != nil {
+		return nil, err
+	}
+	job, err := api.Job.Get(c.GetNamespace(), id)
+	if err = checkResourceFound(job, err, common.Job, c.Param("id")); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// CancelJob cancels the pending or running job, the running job stops at its next item,
+// and the items done before are kept
+func (api *API) CancelJob(c *common.Context) (interface{}, error) {
+	id, err := parseJobID(c)
+	if err != nil {
+		return nil, err
+	}
+	job, err := api.Job.Cancel(c.GetNamespace(), id)
+	if err != nil {
+		return nil, wrapResourceNotFoundError(err, common.Job, c.Param("id"))
+	}
+	return job, nil
+}
+
+func parseJobID(c *common.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the job id should be an integer"))
+	}
+	return id, nil
+}
+
+// isAsyncRequest returns whether the request asks to run in background by the query async=true
+func isAsyncRequest(c *common.Context) bool {
+	async, _ := strconv.ParseBool(c.Query("async"))
+	return async
+}
+
+// startJob creates a job of the type and runs it in background, the job is responded with 202 at once
+// and polled by its id. The job holds the lock of the namespace while running if lock is true
+func (api *API) startJob(c *common.Context, typ string, lock bool, run jobFunc) (interface{}, error) {
+	job, err := api.Job.Create(c.GetNamespace(), typ)
+	if err != nil {
+		return nil, err
+	}
+	go api.runJob(detachContext(c), *job, lock, run)
+	return &common.Accepted{Body: job}, nil
+}
+
+func (api *API) runJob(c *common.Context, job models.Job, lock bool, run jobFunc) {
+	var res interface{}
+	var err error
+	if lock {
+		ctx := context.Background()
+		lockName := "namespace_" + job.Namespace
+		var version string
+		if version, err = api.Locker.Lock(ctx, lockName, 0); err == nil {
+			defer api.Locker.Unlock(ctx, lockName, version)
+		}
+	}
+	ok, e := api.Job.Start(&job)
+	if e != nil || !ok {
+		// the job is canceled before it is started
+		api.log.Warn("failed to start job", log.Any("namespace", job.Namespace), log.Any("id", job.ID), log.Error(e))
+		return
+	}
+	if err == nil {
+		res, err = runJobFunc(c, run, func(done, total int) error {
+			if total <= 0 {
+				return nil
+			}
+			ok, err := api.Job.Progress(&job, done*100/total)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errJobCanceled
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		api.log.Error("failed to run job", log.Any("namespace", job.Namespace), log.Any("id", job.ID),
+			log.Any("type", job.Type), log.Error(err))
+	}
+	if err = api.Job.Finish(&job, res, err); err != nil {
+		api.log.Error("failed to record the result of job", log.Any("namespace", job.Namespace), log.Any("id", job.ID), log.Error(err))
+	}
+}
+
+func runJobFunc(c *common.Context, run jobFunc, progress jobProgress) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = common.Error(common.ErrUnknown, common.Field("error", r))
+		}
+	}()
+	return run(c, progress)
+}
+
+// detachContext copies the context of the request for the job running after the request is responded,
+// the headers written by the job are dropped
+func detachContext(c *common.Context) *common.Context {
+	cp := c.Copy()
+	cp.Writer = &jobResponseWriter{ResponseWriter: cp.Writer, header: http.Header{}}
+	return common.NewContext(cp)
+}
+
+// jobResponseWriter the writer of the copied context, which is not bound to the connection any more
+type jobResponseWriter struct {
+	gin.ResponseWriter
+	header http.Header
+}
+
+func (w *jobResponseWriter) Header() http.Header {
+	return w.header
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestGetAndCancelJob(t *testing.T) {
+	api, router, mockCtl, _ := initNamespaceBundleAPI(t)
+	defer mockCtl.Finish()
+	mJob := ms.NewMockJobService(mockCtl)
+	api.Job = mJob
+	router.GET("/v1/jobs/:id", func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }, common.Wrapper(api.GetJob))
+	router.POST("/v1/jobs/:id/cancel", func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }, common.Wrapper(api.CancelJob))
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/jobs/abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mJob.EXPECT().Get("default", int64(1)).Return(&models.Job{ID: 1, Status: models.JobRunning, Progress: 50}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/jobs/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	job := &models.Job{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), job))
+	assert.Equal(t, models.JobRunning, job.Status)
+	assert.Equal(t, 50, job.Progress)
+
+	mJob.EXPECT().Get("default", int64(2)).Return(nil, common.Error(common.ErrResourceNotFound,
+		common.Field("type", "job"), common.Field("name", "2"), common.Field("namespace", "default"))).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/jobs/2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mJob.EXPECT().Cancel("default", int64(1)).Return(&models.Job{ID: 1, Status: models.JobCanceled}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/jobs/1/cancel", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestExportNamespaceAsync(t *testing.T) {
+	api, router, mockCtl, mocks := initNamespaceBundleAPI(t)
+	defer mockCtl.Finish()
+	mJob := ms.NewMockJobService(mockCtl)
+	api.Job = mJob
+
+	done := make(chan interface{}, 1)
+	mJob.EXPECT().Create("default", models.JobNamespaceExport).
+		Return(&models.Job{ID: 1, Namespace: "default", Type: models.JobNamespaceExport, Status: models.JobPending}, nil).Times(1)
+	mJob.EXPECT().Start(gomock.Any()).Return(true, nil).Times(1)
+	mJob.EXPECT().Finish(gomock.Any(), gomock.Any(), nil).DoAndReturn(func(_ *models.Job, res interface{}, _ error) error {
+		done <- res
+		return nil
+	}).Times(1)
+	expectNamespaceExport(mocks)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/namespace/export?async=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	job := &models.Job{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), job))
+	assert.Equal(t, int64(1), job.ID)
+	assert.Equal(t, models.JobPending, job.Status)
+
+	res := <-done
+	items, err := parseNamespaceBundle(res.(string))
+	assert.NoError(t, err)
+	assert.Len(t, items, 5)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetLicense returns the status of the license, and the usages of the namespace against the limits
+func (api *API) GetLicense(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	usages, err := api.quotaUsages(ns)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.LicenseStatus{
+		Mode:     api.getLicenseMode(),
+		Valid:    true,
+		Limits:   map[string]models.QuotaUsage{},
+		Overages: []string{},
+	}
+	if err = api.License.CheckLicense(); err != nil {
+		res.Valid, res.Message = false, err.Error()
+	}
+	for k, v := range usages {
+		if v.Limit <= 0 {
+			continue
+		}
+		res.Limits[k] = v
+		if v.Used >= v.Limit {
+			res.Overages = append(res.Overages, (&common.QuotaError{Name: k, Limit: v.Limit, Used: v.Used}).Overage())
+		}
+	}
+	sort.Strings(res.Overages)
+	return res, nil
+}
+
+// TolerateLicense returns whether the request is allowed though it exceeds the license limits, which is
+// flagged by the overage header of the response. It is false if the license is enforced or the error is not
+// of the license limits
+func (api *API) TolerateLicense(c *common.Context, err error) bool {
+	mode := api.getLicenseMode()
+	if mode == common.LicenseModeEnforce {
+		return false
+	}
+	e, ok := err.(errors.Coder)
+	if !ok || (e.Code() != common.ErrLicenseQuota && e.Code() != common.ErrLicenseQuotaAcquire) {
+		return false
+	}
+	overage := e.Code()
+	if qe, ok := err.(*common.QuotaError); ok {
+		overage = qe.Overage()
+	}
+	c.Writer.Header().Add(common.HeaderLicenseOverage, overage)
+	if mode == common.LicenseModeWarn {
+		c.Writer.Header().Add(HeaderWarning, fmt.Sprintf(`299 - "the license limit is exceeded (%s)"`, overage))
+		log.L().Warn("the license limit is exceeded", log.Any(c.GetTrace()), log.Any("namespace", c.GetNamespace()), log.Error(err))
+	} else {
+		log.L().Info("the license limit is exceeded", log.Any(c.GetTrace()), log.Any("namespace", c.GetNamespace()), log.Error(err))
+	}
+	return true
+}
+
+// getLicenseMode returns the mode of the license enforcement, the limits are enforced if it is not set
+func (api *API) getLicenseMode() string {
+	switch api.licenseMode {
+	case common.LicenseModeWarn, common.LicenseModeReport:
+		return api.licenseMode
+	default:
+		return common.LicenseModeEnforce
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAPI_GetLicense(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mQuota := ms.NewMockQuotaService(mockCtl)
+	mLicense := ms.NewMockLicenseService(mockCtl)
+	api := &API{Quota: mQuota, License: mLicense, licenseMode: common.LicenseModeWarn}
+	router := gin.Default()
+	router.GET("/v1/license", func(c *gin.Context) { common.NewContext(c).SetNamespace(namespace) }, common.Wrapper(api.GetLicense))
+
+	mQuota.EXPECT().GetQuota(namespace).Return(map[string]int{"maxNodeCount": 2, "maxAppCount": 20}, nil)
+	mQuota.EXPECT().CollectUsage(namespace, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(map[string]int{"maxNodeCount": 3, "maxAppCount": 2, "maxConfigCount": 3}, nil)
+	mLicense.EXPECT().CheckLicense().Return(fmt.Errorf("license expired"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/license", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.LicenseStatus{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, &models.LicenseStatus{
+		Mode:    common.LicenseModeWarn,
+		Valid:   false,
+		Message: "license expired",
+		Limits: map[string]models.QuotaUsage{
+			"maxNodeCount": {Limit: 2, Used: 3},
+			"maxAppCount":  {Limit: 20, Used: 2},
+		},
+		Overages: []string{"maxNodeCount; limit=2; used=3"},
+	}, res)
+
+	mQuota.EXPECT().GetQuota(namespace).Return(nil, fmt.Errorf("error"))
+	req, _ = http.NewRequest(http.MethodGet, "/v1/license", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestAPI_TolerateLicense(t *testing.T) {
+	quotaErr := &common.QuotaError{Name: "maxNodeCount", Limit: 10, Used: 10, Requested: 1}
+	assert.Equal(t, common.ErrLicenseQuota, quotaErr.Code())
+	assert.Equal(t, "maxNodeCount; limit=10; used=10; requested=1", quotaErr.Overage())
+	assert.Contains(t, quotaErr.Error(), "(maxNodeCount)")
+
+	tests := []struct {
+		mode     string
+		err      error
+		tolerate bool
+		warning  bool
+	}{
+		{mode: "", err: quotaErr},
+		{mode: common.LicenseModeEnforce, err: quotaErr},
+		{mode: "unknown", err: quotaErr},
+		{mode: common.LicenseModeWarn, err: fmt.Errorf("error")},
+		{mode: common.LicenseModeWarn, err: common.Error(common.ErrRequestParamInvalid)},
+		{mode: common.LicenseModeWarn, err: quotaErr, tolerate: true, warning: true},
+		{mode: common.LicenseModeReport, err: quotaErr, tolerate: true},
+		{mode: common.LicenseModeReport, err: common.Error(common.ErrLicenseQuotaAcquire), tolerate: true},
+	}
+	for _, tt := range tests {
+		api := &API{licenseMode: tt.mode}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/v1/nodes", nil)
+		cc := common.NewContext(c)
+		assert.Equal(t, tt.tolerate, api.TolerateLicense(cc, tt.err), tt.mode)
+		assert.Equal(t, tt.tolerate, w.Header().Get(common.HeaderLicenseOverage) != "", tt.mode)
+		assert.Equal(t, tt.warning, w.Header().Get(HeaderWarning) != "", tt.mode)
+	}
+}
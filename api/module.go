@@ -1,6 +1,10 @@
 package api
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/baetyl/baetyl-go/v2/log"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
@@ -8,9 +12,13 @@ import (
 )
 
 func (api *API) GetModules(c *common.Context) (interface{}, error) {
-	res, err := api.Module.GetModules(c.Param("name"))
+	name := c.Param("name")
+	res, err := api.Module.GetModules(name)
 	if err != nil {
-		return nil, err
+		return nil, wrapResourceNotFoundError(err, common.Module, name)
+	}
+	if len(res) == 0 {
+		return nil, resourceNotFoundError(common.Module, name)
 	}
 	return models.ModuleList{
 		Total: len(res),
@@ -40,6 +48,14 @@ func (api *API) CreateModule(c *common.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = api.Module.CheckDependencies(&module); err != nil {
+		return nil, err
+	}
+	user := c.GetUser()
+	module.CreatedBy = user.Name
+	if module.CreatedBy == "" {
+		module.CreatedBy = user.ID
+	}
 	res, err := api.Module.CreateModule(&module)
 	if err != nil {
 		return nil, err
@@ -60,6 +76,9 @@ func (api *API) UpdateModule(c *common.Context) (interface{}, error) {
 	}
 	module.Name = name
 	module.Version = version
+	if err = api.Module.CheckDependencies(module); err != nil {
+		return nil, err
+	}
 	res, err := api.Module.UpdateModuleByVersion(module)
 	if err != nil {
 		return nil, err
@@ -67,8 +86,20 @@ func (api *API) UpdateModule(c *common.Context) (interface{}, error) {
 	return res, nil
 }
 
+// GetModuleDependencies returns the dependency tree of the module version
+func (api *API) GetModuleDependencies(c *common.Context) (interface{}, error) {
+	return api.Module.GetDependencyTree(c.GetNameFromParam(), c.Param("version"))
+}
+
+// DeleteModules deletes the module or a version of it, which is refused if depended on by other modules unless forced
 func (api *API) DeleteModules(c *common.Context) (interface{}, error) {
 	name, version := c.GetNameFromParam(), c.Param("version")
+	force, _ := strconv.ParseBool(c.Query("force"))
+	if !force {
+		if err := api.checkModuleDependents(name, version); err != nil {
+			return nil, err
+		}
+	}
 	var err error
 	if version == "" {
 		err = api.Module.DeleteModules(name)
@@ -107,6 +138,26 @@ func (api *API) ListModules(c *common.Context) (interface{}, error) {
 	}, nil
 }
 
+// checkModuleDependents the versions of the module itself are not counted when all of them are deleted
+func (api *API) checkModuleDependents(name, version string) error {
+	dependents, err := api.Module.ListDependents(name, version)
+	if err != nil {
+		return err
+	}
+	var refs []string
+	for _, m := range dependents {
+		if version == "" && m.Name == name {
+			continue
+		}
+		refs = append(refs, m.Name+":"+m.Version)
+	}
+	if len(refs) > 0 {
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the module is depended on by (%s), delete them first or use force=true", strings.Join(refs, ", "))))
+	}
+	return nil
+}
+
 func (api *API) parseAndCheckModule(module *models.Module, c *common.Context) error {
 	err := c.LoadBody(module)
 	if err != nil {
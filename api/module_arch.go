@@ -0,0 +1,364 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	mediaTypeManifestList  = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeManifest      = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeOCIIndex      = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest   = "application/vnd.oci.image.manifest.v1+json"
+	maxRegistryResponseLen = 4 << 20
+)
+
+type imageRef struct {
+	Host string
+	Repo string
+	// Ref the tag or the digest
+	Ref string
+}
+
+type imagePlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+func (p imagePlatform) String() string {
+	res := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		res += "/" + p.Variant
+	}
+	return res
+}
+
+type imageManifest struct {
+	MediaType string `json:"mediaType"`
+	Manifests []struct {
+		Platform *imagePlatform `json:"platform"`
+	} `json:"manifests"`
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// ValidateModuleArch checks whether the image of the module supports the architectures of the nodes
+// matching the label selector in the query nodes, all nodes are checked if it is empty
+func (api *API) ValidateModuleArch(c *common.Context) (interface{}, error) {
+	ns, name, version := c.GetNamespace(), c.Param("name"), c.Param("version")
+	selector := c.Query("nodes")
+	if _, err := common.ParseLabelSelector(selector); err != nil {
+		return nil, err
+	}
+	module, err := api.Module.GetModuleByVersion(name, version)
+	if err != nil {
+		return nil, err
+	}
+	if module.Image == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the module has no image"))
+	}
+	nodes, err := api.Node.List(ns, &models.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	registries, err := api.Secret.List(ns, &models.ListOptions{LabelSelector: specV1.SecretLabel + "=" + specV1.SecretRegistry})
+	if err != nil {
+		return nil, err
+	}
+	ref := parseImageRef(module.Image)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), RegistryVerifyTimeout)
+	defer cancel()
+	ctx, span := common.StartSpan(ctx, "registry.inspect", trace.WithAttributes(attribute.String("image", module.Image)))
+	platforms, err := fetchImagePlatforms(ctx, newRegistryClient(RegistryVerifyTimeout, api.registryAllowPrivate), ref, matchImageRegistry(ref, registries))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("failed to inspect the image (%s): %s", module.Image, err.Error())))
+	}
+
+	res := &models.ModuleArchValidation{
+		Name:      module.Name,
+		Version:   module.Version,
+		Image:     module.Image,
+		Platforms: make([]string, 0, len(platforms)),
+		Supported: true,
+	}
+	for _, p := range platforms {
+		res.Platforms = append(res.Platforms, p.String())
+	}
+	missing := map[string][]string{}
+	for _, n := range nodes.Items {
+		infos := reportedNodeInfos(n.Report)
+		if len(infos) == 0 {
+			res.Unknown = append(res.Unknown, n.Name)
+			continue
+		}
+		for _, info := range infos {
+			if !imageSupportsArch(platforms, info.Arch, info.Variant) {
+				arch := info.Arch
+				if info.Variant != "" {
+					arch += "/" + info.Variant
+				}
+				missing[arch] = appendUnique(missing[arch], n.Name)
+			}
+		}
+	}
+	for arch, names := range missing {
+		res.Missing = append(res.Missing, models.ModuleArchMissing{Arch: arch, Nodes: names})
+	}
+	sort.Slice(res.Missing, func(i, j int) bool { return res.Missing[i].Arch < res.Missing[j].Arch })
+	res.Supported = len(res.Missing) == 0
+	return res, nil
+}
+
+// parseImageRef splits the image into the registry host, the repository and the tag or digest,
+// the images without a host are from docker hub
+func parseImageRef(image string) imageRef {
+	res := imageRef{Host: "docker.io", Ref: "latest"}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, res.Ref = name[:i], name[i+1:]
+	} else if i = strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, res.Ref = name[:i], name[i+1:]
+	}
+	if i := strings.Index(name, "/"); i >= 0 {
+		if host := name[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			res.Host, name = host, name[i+1:]
+		}
+	}
+	if res.Host == "docker.io" && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	res.Repo = name
+	return res
+}
+
+// matchImageRegistry returns the registry of the namespace with the same host as the image,
+// or an anonymous one of the host if not found
+func matchImageRegistry(ref imageRef, registries *models.SecretList) *models.Registry {
+	if registries != nil {
+		for i := range registries.Items {
+			r := models.FromSecretToRegistry(&registries.Items[i], false)
+			if registryHost(r.Address) == registryHost(ref.Host) {
+				return r
+			}
+		}
+	}
+	return &models.Registry{Address: ref.Host}
+}
+
+func registryHost(address string) string {
+	if u, err := url.Parse(registryBaseURL(address)); err == nil {
+		return u.Host
+	}
+	return address
+}
+
+// fetchImagePlatforms reads the platforms from the manifest list of the image,
+// or from the config of the image if it is a single manifest
+func fetchImagePlatforms(ctx context.Context, cli *http.Client, ref imageRef, r *models.Registry) ([]imagePlatform, error) {
+	base := registryBaseURL(r.Address) + "/v2/" + ref.Repo
+	accept := strings.Join([]string{mediaTypeManifestList, mediaTypeOCIIndex, mediaTypeManifest, mediaTypeOCIManifest}, ", ")
+	auth := ""
+	data, err := registryFetch(ctx, cli, base+"/manifests/"+ref.Ref, accept, r, ref.Repo, &auth)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &imageManifest{}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var res []imagePlatform
+	for _, m := range manifest.Manifests {
+		// the attestations are listed with the unknown platform
+		if m.Platform != nil && m.Platform.Architecture != "unknown" {
+			res = append(res, *m.Platform)
+		}
+	}
+	if len(manifest.Manifests) > 0 {
+		return res, nil
+	}
+	if manifest.Config.Digest == "" {
+		return nil, errors.New("the manifest has no config")
+	}
+	data, err = registryFetch(ctx, cli, base+"/blobs/"+manifest.Config.Digest, "", r, ref.Repo, &auth)
+	if err != nil {
+		return nil, err
+	}
+	platform := imagePlatform{}
+	if err = json.Unmarshal(data, &platform); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(res, platform), nil
+}
+
+// registryFetch gets the body of the url, it authorizes with the credentials of the registry if challenged,
+// the authorization is kept in auth for the following requests
+func registryFetch(ctx context.Context, cli *http.Client, address, accept string, r *models.Registry, repo string, auth *string) ([]byte, error) {
+	do := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if *auth != "" {
+			req.Header.Set("Authorization", *auth)
+		}
+		common.InjectTrace(ctx, req.Header)
+		return cli.Do(req)
+	}
+	resp, err := do()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && *auth == "" {
+		resp.Body.Close()
+		if *auth, err = registryAuthorize(ctx, cli, resp.Header.Get("WWW-Authenticate"), r, repo); err != nil {
+			return nil, err
+		}
+		if resp, err = do(); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected response of registry: " + resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxRegistryResponseLen))
+}
+
+// registryAuthorize returns the authorization header for the challenge, a pull token is requested for the bearer scheme
+// from the realm checked by checkTokenRealm
+func registryAuthorize(ctx context.Context, cli *http.Client, challenge string, r *models.Registry, repo string) (string, error) {
+	scheme, params := parseAuthenticateHeader(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if r.Username == "" {
+			return "", errors.New("the registry requires the credentials")
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(r.Username+":"+r.Password)), nil
+	case "bearer":
+		realm, err := url.Parse(params["realm"])
+		if err != nil || realm.Host == "" {
+			return "", errors.New("invalid token realm of registry")
+		}
+		if err = checkTokenRealm(realm, r.Address); err != nil {
+			return "", err
+		}
+		q := realm.Query()
+		if v, ok := params["service"]; ok {
+			q.Set("service", v)
+		}
+		scope := params["scope"]
+		if scope == "" {
+			scope = "repository:" + repo + ":pull"
+		}
+		q.Set("scope", scope)
+		realm.RawQuery = q.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if r.Username != "" {
+			req.SetBasicAuth(r.Username, r.Password)
+		}
+		resp, err := cli.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", errors.New("failed to get the token of registry: " + resp.Status)
+		}
+		token := struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}{}
+		if err = json.NewDecoder(io.LimitReader(resp.Body, maxRegistryResponseLen)).Decode(&token); err != nil {
+			return "", errors.Trace(err)
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		return "Bearer " + token.Token, nil
+	}
+	return "", errors.New("unsupported authentication scheme of registry: " + scheme)
+}
+
+// reportedNodeInfos returns the infos of the hosts of the node reported
+func reportedNodeInfos(report specV1.Report) map[string]*specV1.NodeInfo {
+	raw, ok := report[common.NodeInfo]
+	if !ok || raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	fields := map[string]json.RawMessage{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	res := map[string]*specV1.NodeInfo{}
+	if _, ok = fields["arch"]; ok {
+		// the info of the single host reported by the old cores
+		info := &specV1.NodeInfo{}
+		if err = json.Unmarshal(data, info); err != nil {
+			return nil
+		}
+		res[info.Hostname] = info
+	} else if err = json.Unmarshal(data, &res); err != nil {
+		return nil
+	}
+	for host, info := range res {
+		if info == nil || info.Arch == "" {
+			delete(res, host)
+		}
+	}
+	return res
+}
+
+// imageSupportsArch the variant is compared only if both the node and the image have one
+func imageSupportsArch(platforms []imagePlatform, arch, variant string) bool {
+	for _, p := range platforms {
+		if p.Architecture != arch {
+			continue
+		}
+		if variant == "" || p.Variant == "" || p.Variant == variant {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
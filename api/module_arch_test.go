@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestParseImageRef(t *testing.T) {
+	cases := map[string]imageRef{
+		"nginx":                             {Host: "docker.io", Repo: "library/nginx", Ref: "latest"},
+		"baetyl/core:v2.4.3":                {Host: "docker.io", Repo: "baetyl/core", Ref: "v2.4.3"},
+		"localhost:5000/core":               {Host: "localhost:5000", Repo: "core", Ref: "latest"},
+		"hub.baidubce.com/baetyl/core:v2":   {Host: "hub.baidubce.com", Repo: "baetyl/core", Ref: "v2"},
+		"hub.baidubce.com/core@sha256:abcd": {Host: "hub.baidubce.com", Repo: "core", Ref: "sha256:abcd"},
+	}
+	for image, expect := range cases {
+		assert.Equal(t, expect, parseImageRef(image), image)
+	}
+}
+
+func TestValidateModuleArch(t *testing.T) {
+	api, router, mockCtl := initModuleAPI(t)
+	defer mockCtl.Finish()
+	sModule := ms.NewMockModuleService(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.Module, api.Node = sModule, sNode
+	api.AppCombinedService = &service.AppCombinedService{Secret: sSecret}
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "pass" || r.URL.Query().Get("scope") != "repository:baetyl/multi:pull" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"token":"t1"}`))
+		case r.URL.Path == "/v2/baetyl/single/manifests/v1":
+			w.Write([]byte(`{"mediaType":"` + mediaTypeManifest + `","config":{"digest":"sha256:abc"}}`))
+		case r.URL.Path == "/v2/baetyl/single/blobs/sha256:abc":
+			w.Write([]byte(`{"os":"linux","architecture":"amd64"}`))
+		case r.Header.Get("Authorization") != "Bearer t1":
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+srv.URL+`/token",service="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/baetyl/multi/manifests/v1":
+			assert.Contains(t, r.Header.Get("Accept"), mediaTypeManifestList)
+			w.Write([]byte(`{"mediaType":"` + mediaTypeManifestList + `","manifests":[
+				{"platform":{"os":"linux","architecture":"amd64"}},
+				{"platform":{"os":"linux","architecture":"arm","variant":"v7"}},
+				{"platform":{"os":"unknown","architecture":"unknown"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	registries := &models.SecretList{Items: []specV1.Secret{{
+		Name:   "reg",
+		Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry},
+		Data:   map[string][]byte{"address": []byte(srv.URL), "username": []byte("user"), "password": []byte("pass")},
+	}}}
+	nodes := &models.NodeList{Items: []specV1.Node{
+		{Name: "n1", Report: specV1.Report{"node": map[string]interface{}{"h1": map[string]interface{}{"arch": "amd64"}}}},
+		{Name: "n2", Report: specV1.Report{"node": map[string]interface{}{
+			"h1": map[string]interface{}{"arch": "arm64"},
+			"h2": map[string]interface{}{"arch": "arm", "variant": "v7"},
+		}}},
+		{Name: "n3", Report: specV1.Report{"node": map[string]interface{}{"hostname": "h1", "arch": "arm64"}}},
+		{Name: "n4"},
+	}}
+	validate := func(name, query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/v1/modules/"+name+"/version/v1/validate-arch"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	sModule.EXPECT().GetModuleByVersion("multi", "v1").Return(&models.Module{Name: "multi", Version: "v1", Image: host + "/baetyl/multi:v1"}, nil)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "arch=arm"}).Return(nodes, nil)
+	sSecret.EXPECT().List("default", &models.ListOptions{LabelSelector: specV1.SecretLabel + "=" + specV1.SecretRegistry}).Return(registries, nil).Times(3)
+	// the loopback addresses are never dialed, even if the registries on the private networks are allowed
+	api.registryAllowPrivate = true
+	w := validate("multi", "?nodes=arch%3Darm")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "is forbidden")
+
+	defer allowInternalRegistries()()
+	sModule.EXPECT().GetModuleByVersion("multi", "v1").Return(&models.Module{Name: "multi", Version: "v1", Image: host + "/baetyl/multi:v1"}, nil)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "arch=arm"}).Return(nodes, nil)
+	w = validate("multi", "?nodes=arch%3Darm")
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.ModuleArchValidation{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, &models.ModuleArchValidation{
+		Name:      "multi",
+		Version:   "v1",
+		Image:     host + "/baetyl/multi:v1",
+		Platforms: []string{"linux/amd64", "linux/arm/v7"},
+		Missing:   []models.ModuleArchMissing{{Arch: "arm64", Nodes: []string{"n2", "n3"}}},
+		Unknown:   []string{"n4"},
+	}, res)
+
+	// the single manifest of the public image
+	sModule.EXPECT().GetModuleByVersion("single", "v1").Return(&models.Module{Name: "single", Version: "v1", Image: host + "/baetyl/single:v1"}, nil)
+	sNode.EXPECT().List("default", &models.ListOptions{}).Return(&models.NodeList{Items: nodes.Items[:1]}, nil)
+	w = validate("single", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = &models.ModuleArchValidation{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.True(t, res.Supported)
+	assert.Equal(t, []string{"linux/amd64"}, res.Platforms)
+
+	// no image
+	sModule.EXPECT().GetModuleByVersion("program", "v1").Return(&models.Module{Name: "program", Version: "v1"}, nil)
+	assert.Equal(t, http.StatusBadRequest, validate("program", "").Code)
+
+	assert.Equal(t, http.StatusBadRequest, validate("multi", "?nodes=a%3D%3D%3Db").Code)
+}
+
+func TestCheckTokenRealm(t *testing.T) {
+	realm := func(s string) *url.URL {
+		u, err := url.Parse(s)
+		assert.NoError(t, err)
+		return u
+	}
+	assert.NoError(t, checkTokenRealm(realm("https://auth.docker.io/token"), "docker.io"))
+	assert.NoError(t, checkTokenRealm(realm("http://harbor.local/service/token"), "http://harbor.local"))
+	assert.Error(t, checkTokenRealm(realm("http://auth.example.com/token"), "harbor.example.com"))
+	assert.Error(t, checkTokenRealm(realm("http://auth.example.com/token"), "https://harbor.example.com"))
+	assert.Error(t, checkTokenRealm(realm("ftp://auth.example.com/token"), "http://harbor.local"))
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetModuleHistory returns the versions of the module in the order of creation, with the changes from the
+// previous version of each. The versions are filtered by the time range of the creation and paged by pageNo and pageSize,
+// the changes are always taken from the previous version of all, which may be out of the page
+func (api *API) GetModuleHistory(c *common.Context) (interface{}, error) {
+	name := c.GetNameFromParam()
+	params := &models.ModuleHistoryOptions{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if params.StartTime < 0 || params.EndTime < 0 ||
+		(params.EndTime > 0 && params.StartTime > params.EndTime) {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "invalid time range"))
+	}
+	if params.PageNo < 0 || params.PageSize < 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "pageNo and pageSize should not be negative"))
+	}
+	modules, err := api.Module.GetModules(name)
+	if err != nil {
+		return nil, wrapResourceNotFoundError(err, common.Module, name)
+	}
+	if len(modules) == 0 {
+		return nil, resourceNotFoundError(common.Module, name)
+	}
+	sort.SliceStable(modules, func(i, j int) bool {
+		return modules[i].CreationTimestamp.Before(modules[j].CreationTimestamp)
+	})
+
+	res := &models.ModuleHistory{Name: name, ModuleHistoryOptions: params, Items: []models.ModuleHistoryItem{}}
+	for i := range modules {
+		m := &modules[i]
+		if !inTimeRange(m.CreationTimestamp, params.StartTime, params.EndTime) {
+			continue
+		}
+		item := models.ModuleHistoryItem{
+			Version:    m.Version,
+			Image:      m.Image,
+			IsLatest:   m.IsLatest,
+			CreatedBy:  m.CreatedBy,
+			CreateTime: m.CreationTimestamp,
+		}
+		if i > 0 {
+			item.Previous = modules[i-1].Version
+			item.Changes = diffModule(&modules[i-1], m)
+		}
+		res.Items = append(res.Items, item)
+	}
+	res.Total = len(res.Items)
+	if params.GetLimitNumber() > 0 {
+		start, end := params.GetLimitOffset(), params.GetLimitOffset()+params.GetLimitNumber()
+		if start > res.Total {
+			start = res.Total
+		}
+		if end > res.Total {
+			end = res.Total
+		}
+		res.Items = res.Items[start:end]
+	}
+	return res, nil
+}
+
+// inTimeRange the range is in unix seconds, and the zero bounds are open
+func inTimeRange(t time.Time, start, end int64) bool {
+	if start > 0 && t.Unix() < start {
+		return false
+	}
+	if end > 0 && t.Unix() > end {
+		return false
+	}
+	return true
+}
+
+// diffModule summarizes the changes of the image, programs and dependencies between the versions
+func diffModule(old, cur *models.Module) []string {
+	var changes []string
+	if old.Image != cur.Image {
+		changes = append(changes, fmt.Sprintf("image: %s -> %s", old.Image, cur.Image))
+	}
+	if old.Type != cur.Type {
+		changes = append(changes, fmt.Sprintf("type: %s -> %s", old.Type, cur.Type))
+	}
+	changes = append(changes, diffStrings("program", old.Programs, cur.Programs, false)...)
+
+	oldDeps := make(map[string]string, len(old.Dependencies))
+	for _, d := range old.Dependencies {
+		oldDeps[d.Name] = d.Version
+	}
+	curDeps := make(map[string]string, len(cur.Dependencies))
+	for _, d := range cur.Dependencies {
+		curDeps[d.Name] = d.Version
+	}
+	return append(changes, diffStrings("dependency", oldDeps, curDeps, true)...)
+}
+
+// diffStrings lists the keys added, removed and changed, along with the values changed if withValues
+func diffStrings(kind string, old, cur map[string]string, withValues bool) []string {
+	keys := make([]string, 0, len(old)+len(cur))
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range cur {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var changes []string
+	for _, k := range keys {
+		o, inOld := old[k]
+		v, inCur := cur[k]
+		switch {
+		case !inOld:
+			changes = append(changes, fmt.Sprintf("%s %s: added", kind, k))
+		case !inCur:
+			changes = append(changes, fmt.Sprintf("%s %s: removed", kind, k))
+		case o == v:
+		case withValues:
+			changes = append(changes, fmt.Sprintf("%s %s: %s -> %s", kind, k, o, v))
+		default:
+			changes = append(changes, fmt.Sprintf("%s %s: changed", kind, k))
+		}
+	}
+	return changes
+}
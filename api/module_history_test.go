@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/stretchr/testify/assert"
+
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestGetModuleHistory(t *testing.T) {
+	api, router, mockCtl := initModuleAPI(t)
+	defer mockCtl.Finish()
+	sModule := ms.NewMockModuleService(mockCtl)
+	api.Module = sModule
+
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// listed in the order of creation desc
+	modules := []models.Module{
+		{
+			Name: "baetyl", Version: "v3", Image: "baetyl:v3", Type: "system", IsLatest: true, CreatedBy: "bob",
+			Programs:          map[string]string{"linux-amd64": "url-amd64-v3"},
+			Dependencies:      []models.ModuleDependency{{Name: "baetyl-init", Version: "v2"}},
+			CreationTimestamp: day.Add(48 * time.Hour),
+		},
+		{
+			Name: "baetyl", Version: "v2", Image: "baetyl:v2", Type: "system", CreatedBy: "alice",
+			Programs:          map[string]string{"linux-amd64": "url-amd64-v2", "linux-arm64": "url-arm64"},
+			Dependencies:      []models.ModuleDependency{{Name: "baetyl-init", Version: "v1"}, {Name: "baetyl-broker", Version: "v1"}},
+			CreationTimestamp: day.Add(24 * time.Hour),
+		},
+		{
+			Name: "baetyl", Version: "v1", Image: "baetyl:v1", Type: "system", CreatedBy: "alice",
+			Programs:          map[string]string{"linux-amd64": "url-amd64-v1"},
+			Dependencies:      []models.ModuleDependency{{Name: "baetyl-init", Version: "v1"}},
+			CreationTimestamp: day,
+		},
+	}
+	get := func(query string) (int, *models.ModuleHistory) {
+		req, _ := http.NewRequest(http.MethodGet, "/v1/modules/baetyl/history"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		res := new(models.ModuleHistory)
+		if w.Code == http.StatusOK {
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+		}
+		return w.Code, res
+	}
+
+	sModule.EXPECT().GetModules("baetyl").Return(modules, nil).Times(1)
+	code, res := get("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, []models.ModuleHistoryItem{
+		{Version: "v1", Image: "baetyl:v1", CreatedBy: "alice", CreateTime: day},
+		{
+			Version: "v2", Image: "baetyl:v2", CreatedBy: "alice", CreateTime: day.Add(24 * time.Hour), Previous: "v1",
+			Changes: []string{
+				"image: baetyl:v1 -> baetyl:v2",
+				"program linux-amd64: changed",
+				"program linux-arm64: added",
+				"dependency baetyl-broker: added",
+			},
+		},
+		{
+			Version: "v3", Image: "baetyl:v3", IsLatest: true, CreatedBy: "bob", CreateTime: day.Add(48 * time.Hour), Previous: "v2",
+			Changes: []string{
+				"image: baetyl:v2 -> baetyl:v3",
+				"program linux-amd64: changed",
+				"program linux-arm64: removed",
+				"dependency baetyl-broker: removed",
+				"dependency baetyl-init: v1 -> v2",
+			},
+		},
+	}, res.Items)
+
+	// the changes of the first version in the range are taken from the previous version out of the range
+	sModule.EXPECT().GetModules("baetyl").Return(modules, nil).Times(1)
+	code, res = get(fmt.Sprintf("?startTime=%d&pageNo=2&pageSize=1", day.Add(time.Hour).Unix()))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, res.Total)
+	assert.Len(t, res.Items, 1)
+	assert.Equal(t, "v3", res.Items[0].Version)
+
+	sModule.EXPECT().GetModules("baetyl").Return(modules, nil).Times(1)
+	code, res = get(fmt.Sprintf("?endTime=%d", day.Add(time.Hour).Unix()))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "v1", res.Items[0].Version)
+
+	code, _ = get("?startTime=10&endTime=5")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	sModule.EXPECT().GetModules("baetyl").Return([]models.Module{}, nil).Times(1)
+	code, _ = get("")
+	assert.Equal(t, http.StatusNotFound, code)
+}
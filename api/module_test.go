@@ -28,7 +28,10 @@ func initModuleAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		module.GET("", mockIM, common.Wrapper(api.ListModules))
 		module.GET("/:name", mockIM, common.Wrapper(api.GetModules))
 		module.GET("/:name/version/:version", mockIM, common.Wrapper(api.GetModuleByVersion))
+		module.GET("/:name/version/:version/deps", mockIM, common.Wrapper(api.GetModuleDependencies))
+		module.POST("/:name/version/:version/validate-arch", mockIM, common.Wrapper(api.ValidateModuleArch))
 		module.GET("/:name/latest", mockIM, common.Wrapper(api.GetLatestModule))
+		module.GET("/:name/history", mockIM, common.Wrapper(api.GetModuleHistory))
 		module.POST("", mockIM, common.Wrapper(api.CreateModule))
 		module.PUT("/:name/version/:version", mockIM, common.Wrapper(api.UpdateModule))
 		module.DELETE("/:name", mockIM, common.Wrapper(api.DeleteModules))
@@ -123,6 +126,7 @@ func TestCreateModule(t *testing.T) {
 		Version: "m1v",
 		Type:    string(common.TypeSystemOptional),
 	}
+	sModule.EXPECT().CheckDependencies(m1).Return(nil).Times(2)
 	sModule.EXPECT().CreateModule(m1).Return(m1, nil).Times(1)
 
 	w := httptest.NewRecorder()
@@ -138,6 +142,26 @@ func TestCreateModule(t *testing.T) {
 	req, _ = http.NewRequest(http.MethodPost, "/v1/modules", bytes.NewReader(body))
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	m2 := &models.Module{
+		Name:         "baetyl-function",
+		Version:      "m2v",
+		Dependencies: []models.ModuleDependency{{Name: "baetyl-broker", Version: "v1"}},
+	}
+	sModule.EXPECT().CheckDependencies(m2).Return(common.Error(common.ErrRequestParamInvalid, common.Field("error", "the dependency does not exist"))).Times(1)
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(m2)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/modules", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the version of a dependency is required
+	m2.Dependencies = []models.ModuleDependency{{Name: "baetyl-broker"}}
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(m2)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/modules", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestUpdateModule(t *testing.T) {
@@ -161,6 +185,7 @@ func TestUpdateModule(t *testing.T) {
 		Type:    string(common.TypeSystemOptional),
 	}
 	sModule.EXPECT().GetModuleByVersion(m1.Name, m1.Version).Return(res, nil).Times(1)
+	sModule.EXPECT().CheckDependencies(m1).Return(nil).Times(1)
 	sModule.EXPECT().UpdateModuleByVersion(m1).Return(m1, nil).Times(1)
 
 	w := httptest.NewRecorder()
@@ -185,6 +210,8 @@ func TestDeleteModules(t *testing.T) {
 	api.Module = sModule
 	api.Init = sInit
 
+	// the versions of the module itself are not counted
+	sModule.EXPECT().ListDependents("baetyl", "").Return([]models.Module{{Name: "baetyl", Version: "v2"}}, nil).Times(1)
 	sModule.EXPECT().DeleteModules("baetyl").Return(nil).Times(1)
 
 	req, _ := http.NewRequest(http.MethodDelete, "/v1/modules/baetyl", nil)
@@ -192,11 +219,59 @@ func TestDeleteModules(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 
+	sModule.EXPECT().ListDependents("baetyl", "v1").Return(nil, nil).Times(1)
 	sModule.EXPECT().DeleteModuleByVersion("baetyl", "v1").Return(nil).Times(1)
 	req, _ = http.NewRequest(http.MethodDelete, "/v1/modules/baetyl/version/v1", nil)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	sModule.EXPECT().ListDependents("baetyl", "v1").Return([]models.Module{
+		{Name: "baetyl", Version: "v2"},
+		{Name: "baetyl-function", Version: "v3"},
+	}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/modules/baetyl/version/v1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the module is depended on by (baetyl:v2, baetyl-function:v3)")
+
+	sModule.EXPECT().DeleteModuleByVersion("baetyl", "v1").Return(nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/modules/baetyl/version/v1?force=true", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sModule.EXPECT().ListDependents("baetyl", "").Return(nil, errors.New("err")).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/modules/baetyl", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestGetModuleDependencies(t *testing.T) {
+	api, router, mockCtl := initModuleAPI(t)
+	defer mockCtl.Finish()
+	sModule := ms.NewMockModuleService(mockCtl)
+	api.Module = sModule
+
+	tree := &models.ModuleDependencyTree{Name: "baetyl", Version: "v1", Dependencies: []models.ModuleDependencyTree{
+		{Name: "baetyl-broker", Version: "v2"},
+	}}
+	sModule.EXPECT().GetDependencyTree("baetyl", "v1").Return(tree, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/modules/baetyl/version/v1/deps", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.ModuleDependencyTree{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, tree, res)
+
+	sModule.EXPECT().GetDependencyTree("baetyl", "v1").Return(nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "a cyclic dependency is found"))).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/modules/baetyl/version/v1/deps", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestListModules(t *testing.T) {
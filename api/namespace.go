@@ -1,6 +1,9 @@
 package api
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/baetyl/baetyl-go/v2/log"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
@@ -42,3 +45,86 @@ func (api *API) DeleteNamespace(c *common.Context) (interface{}, error) {
 
 	return nil, err
 }
+
+// GetNamespaceSettings get the settings of the namespace
+func (api *API) GetNamespaceSettings(c *common.Context) (interface{}, error) {
+	return api.NS.GetSettings(c.GetNamespace())
+}
+
+// GetNamespaceFeatures get the feature flags in effect in the namespace
+func (api *API) GetNamespaceFeatures(c *common.Context) (interface{}, error) {
+	features, err := api.NS.GetFeatures(c.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	return &models.NamespaceFeatures{Features: features}, nil
+}
+
+// CheckFeature returns ErrFeatureDisabled if the feature is not enabled in the namespace
+func (api *API) CheckFeature(namespace, flag string) error {
+	enabled, err := api.NS.IsFeatureEnabled(namespace, flag)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return common.Error(common.ErrFeatureDisabled, common.Field("feature", flag))
+	}
+	return nil
+}
+
+// UpdateNamespaceSettings replace the settings of the namespace, the default node labels are merged into
+// the existing nodes if applyExisting is true, and the labels of the nodes are kept on conflicts with warnings
+func (api *API) UpdateNamespaceSettings(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	settings := new(models.NamespaceSettings)
+	if err := c.LoadBody(settings); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	for k := range settings.NodeLabels {
+		if !common.ValidNonBaetyl(k) {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the label (%s) is reserved by the system", k)))
+		}
+	}
+	if err := validAppLimits(settings); err != nil {
+		return nil, err
+	}
+	applyExisting := false
+	if v := c.Query("applyExisting"); v != "" {
+		var err error
+		if applyExisting, err = strconv.ParseBool(v); err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "applyExisting should be a boolean"))
+		}
+	}
+
+	saved, err := api.NS.UpdateSettings(ns, settings)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.NamespaceSettingsResult{NamespaceSettings: saved}
+	if !applyExisting || len(saved.NodeLabels) == 0 {
+		return res, nil
+	}
+	nodes, err := api.Node.List(ns, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		labels, conflicts := saved.MergeNodeLabels(node.Labels)
+		for _, k := range conflicts {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("the label (%s) of the node (%s) is kept as (%s) rather than the default (%s)",
+				k, node.Name, labels[k], saved.NodeLabels[k]))
+		}
+		if len(labels) == len(node.Labels) {
+			continue
+		}
+		node.Labels = labels
+		if _, err = api.Node.Update(ns, node); err != nil {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("failed to apply the default labels to the node (%s): %s", node.Name, err.Error()))
+			continue
+		}
+		res.Updated = append(res.Updated, node.Name)
+	}
+	return res, nil
+}
@@ -0,0 +1,600 @@
+package api
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+// HeaderBundlePassphrase the header carrying the passphrase to encrypt the secrets of an exported bundle,
+// and to decrypt them on import
+const HeaderBundlePassphrase = "x-baetyl-bundle-passphrase"
+
+const (
+	bundleSaltSize = 16
+	bundleKeySize  = 32
+	// the cost parameters of scrypt recommended for the interactive logins
+	bundleScryptN = 32768
+	bundleScryptR = 8
+	bundleScryptP = 1
+)
+
+// the resources are imported in this order, so that the dependencies exist before the apps
+var bundleKindOrder = map[string]int{
+	models.BundleKindSecret:        0,
+	models.BundleKindCertificate:   0,
+	models.BundleKindRegistry:      0,
+	models.BundleKindConfiguration: 1,
+	models.BundleKindApplication:   2,
+	models.BundleKindNode:          3,
+}
+
+// ExportNamespace exports the apps, configs, secrets, certificates, registries and nodes of the namespace
+// as a multi-document yaml, the system resources are not exported
+func (api *API) ExportNamespace(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	params := &models.NamespaceExportParams{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if params.SecretMode == "" {
+		params.SecretMode = models.BundleSecretRedacted
+	}
+	cipher := &bundleCipher{}
+	if params.SecretMode == models.BundleSecretEncrypted {
+		passphrase := c.GetHeader(HeaderBundlePassphrase)
+		if passphrase == "" {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the header %s is required to encrypt secrets", HeaderBundlePassphrase)))
+		}
+		// a random salt is generated for each export and kept in the encrypted items
+		salt := make([]byte, bundleSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, errors.Trace(err)
+		}
+		key, err := bundleKey(passphrase, salt)
+		if err != nil {
+			return nil, err
+		}
+		cipher.key, cipher.salt = key, base64.StdEncoding.EncodeToString(salt)
+	}
+
+	if isAsyncRequest(c) {
+		// the bundle is the result of the job
+		return api.startJob(c, models.JobNamespaceExport, false, func(_ *common.Context, _ jobProgress) (interface{}, error) {
+			data, err := api.encodeNamespaceBundle(ns, params.SecretMode, cipher)
+			if err != nil {
+				return nil, err
+			}
+			return string(data), nil
+		})
+	}
+	data, err := api.encodeNamespaceBundle(ns, params.SecretMode, cipher)
+	if err != nil {
+		return nil, err
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.yaml", ns))
+	return data, nil
+}
+
+func (api *API) encodeNamespaceBundle(ns, secretMode string, cipher *bundleCipher) ([]byte, error) {
+	items, err := api.exportNamespace(ns, secretMode, cipher)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	for _, item := range items {
+		if err = enc.Encode(item); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if err = enc.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (api *API) exportNamespace(ns, secretMode string, cipher *bundleCipher) ([]models.NamespaceBundleItem, error) {
+	opts := &models.ListOptions{LabelSelector: "!" + common.LabelSystem}
+	var items []models.NamespaceBundleItem
+
+	secrets, err := api.Secret.List(ns, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets.Items {
+		secret := secrets.Items[i]
+		if secret.System {
+			continue
+		}
+		kind := models.BundleKindSecret
+		switch secret.Labels[specV1.SecretLabel] {
+		case specV1.SecretCertificate:
+			kind = models.BundleKindCertificate
+		case specV1.SecretRegistry:
+			kind = models.BundleKindRegistry
+		}
+		item := models.NamespaceBundleItem{Kind: kind, SecretMode: secretMode, Secret: &secret}
+		switch secretMode {
+		case models.BundleSecretRedacted:
+			data := map[string][]byte{}
+			for k := range secret.Data {
+				data[k] = []byte{}
+			}
+			secret.Data = data
+		case models.BundleSecretEncrypted:
+			if secret.Data, err = util.EncryptMap(secret.Data, cipher.key); err != nil {
+				return nil, errors.Trace(err)
+			}
+			item.Salt = cipher.salt
+		}
+		secret.Namespace, secret.Version = "", ""
+		secret.CreationTimestamp, secret.UpdateTimestamp = time.Time{}, time.Time{}
+		items = append(items, item)
+	}
+
+	configs, err := api.Config.List(ns, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range configs.Items {
+		cfg := configs.Items[i]
+		if cfg.System {
+			continue
+		}
+		cfg.Namespace, cfg.Version = "", ""
+		cfg.CreationTimestamp, cfg.UpdateTimestamp = time.Time{}, time.Time{}
+		items = append(items, models.NamespaceBundleItem{Kind: models.BundleKindConfiguration, Configuration: &cfg})
+	}
+
+	apps, err := api.App.List(ns, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range apps.Items {
+		if item.System {
+			continue
+		}
+		app, err := api.App.Get(ns, item.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		app.Namespace, app.Version = "", ""
+		app.CreationTimestamp, app.UpdateTime = time.Time{}, time.Time{}
+		items = append(items, models.NamespaceBundleItem{Kind: models.BundleKindApplication, Application: app})
+	}
+
+	nodes, err := api.Node.List(ns, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes.Items {
+		// only the spec of the node is exported, the system labels are added again on import
+		labels := map[string]string{}
+		for k, v := range n.Labels {
+			switch k {
+			case common.LabelNodeName, common.LabelAccelerator, common.LabelCluster, common.LabelNodeMode:
+			default:
+				labels[k] = v
+			}
+		}
+		items = append(items, models.NamespaceBundleItem{Kind: models.BundleKindNode, Node: &specV1.Node{
+			Name:        n.Name,
+			Accelerator: n.Accelerator,
+			Mode:        n.Mode,
+			NodeMode:    n.NodeMode,
+			Cluster:     n.Cluster,
+			Labels:      labels,
+			Annotations: n.Annotations,
+			SysApps:     n.SysApps,
+			Description: n.Description,
+		}})
+	}
+	return items, nil
+}
+
+// ImportNamespace recreates the resources of an exported bundle in the namespace,
+// the existing resources are skipped, overwritten or fail the import according to the conflict policy.
+// The passphrase to decrypt the secrets is taken from the header as on export.
+// All the resources are validated as by the routes creating them before anything is imported, then the secrets,
+// configs and apps are imported in one transaction, the nodes are imported one by one after them
+func (api *API) ImportNamespace(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	params := &models.NamespaceImport{}
+	if err := c.LoadBody(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	items, err := parseNamespaceBundle(params.Bundle)
+	if err != nil {
+		return nil, err
+	}
+	passphrase := c.GetHeader(HeaderBundlePassphrase)
+
+	// conflicts are checked before importing, nothing is imported if the policy is fail
+	olds := make([]interface{}, len(items))
+	for i, item := range items {
+		if olds[i], err = api.getBundleResource(ns, &item); err != nil {
+			return nil, err
+		}
+		if olds[i] != nil && params.ConflictPolicy == models.ConflictPolicyFail {
+			return nil, common.Error(common.ErrResourceConflict,
+				common.Field("type", item.Kind), common.Field("name", bundleItemName(&item)))
+		}
+		if item.SecretMode == models.BundleSecretEncrypted && passphrase == "" {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the header %s is required to decrypt secrets", HeaderBundlePassphrase)))
+		}
+	}
+	plan, err := api.planNamespaceImport(c, items, olds, params.ConflictPolicy, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAsyncRequest(c) {
+		return api.startJob(c, models.JobNamespaceImport, true, func(cc *common.Context, progress jobProgress) (interface{}, error) {
+			return api.importNamespace(cc, plan, progress)
+		})
+	}
+	return api.importNamespace(c, plan, nil)
+}
+
+// namespaceImportPlan the validated resources of a bundle to import
+type namespaceImportPlan struct {
+	resources []models.NamespaceImportResource
+	nodes     []*specV1.Node
+	oldNodes  []*specV1.Node
+	newNodes  int
+	result    *models.NamespaceImportResult
+}
+
+// planNamespaceImport decrypts the secrets of the bundle and validates the resources to import as the routes
+// creating them do, the quotas of the apps, configs and storage are checked against all the resources to import
+func (api *API) planNamespaceImport(c *common.Context, items []models.NamespaceBundleItem, olds []interface{},
+	policy, passphrase string) (*namespaceImportPlan, error) {
+	ns := c.GetNamespace()
+	plan := &namespaceImportPlan{result: &models.NamespaceImportResult{Created: []string{}, Updated: []string{}, Skipped: []string{}}}
+	keys := map[string][]byte{}
+	configs := map[string]*specV1.Configuration{}
+	var configNames, secretNames []string
+	requested := map[string]int{}
+	for i := range items {
+		item, old := &items[i], olds[i]
+		id := item.Kind + "/" + bundleItemName(item)
+		// the data of redacted secrets is lost, they can't be imported
+		if (old != nil && policy == models.ConflictPolicySkip) || item.SecretMode == models.BundleSecretRedacted {
+			plan.result.Skipped = append(plan.result.Skipped, id)
+			continue
+		}
+		if old == nil {
+			plan.result.Created = append(plan.result.Created, id)
+		} else {
+			plan.result.Updated = append(plan.result.Updated, id)
+		}
+		switch item.Kind {
+		case models.BundleKindApplication:
+			app := item.Application
+			app.Namespace = ns
+			if old != nil {
+				oldApp := old.(*specV1.Application)
+				app.Version = oldApp.Version
+				app.CreationTimestamp = oldApp.CreationTimestamp
+				app.CronStatus = specV1.CronNotSet
+				// ota can not modify
+				app.Ota = oldApp.Ota
+			} else {
+				requested[plugin.QuotaApp]++
+			}
+			// the secrets and configs of the bundle are imported before the apps
+			gen, err := api.checkAppDependencies(ns, app, configNames, secretNames)
+			if err != nil {
+				return nil, err
+			}
+			// the generated configs of the function services imported along with the app are kept by it
+			for _, v := range app.Volumes {
+				if v.Config == nil || configs[v.Config.Name] == nil {
+					continue
+				}
+				if strings.HasPrefix(v.Config.Name, FunctionConfigPrefix) || strings.HasPrefix(v.Config.Name, FunctionProgramConfigPrefix) {
+					gen = append(gen, *configs[v.Config.Name])
+				}
+			}
+			if err = api.applyAppLimits(ns, app); err != nil {
+				return nil, err
+			}
+			if err = api.checkAppHostPorts(ns, app); err != nil {
+				return nil, err
+			}
+			plan.resources = append(plan.resources, models.NamespaceImportResource{Application: app, Configs: gen, Old: old})
+		case models.BundleKindConfiguration:
+			cfg := item.Configuration
+			cfg.Namespace = ns
+			requested[plugin.QuotaStorage] += configStorageSize(cfg)
+			if old != nil {
+				oldCfg := old.(*specV1.Configuration)
+				cfg.Version = oldCfg.Version
+				cfg.CreationTimestamp = oldCfg.CreationTimestamp
+				cfg.UpdateTimestamp = time.Now()
+				requested[plugin.QuotaStorage] -= configStorageSize(oldCfg)
+			} else {
+				requested[plugin.QuotaConfig]++
+			}
+			if err := api.checkConfigSchema(ns, cfg); err != nil {
+				return nil, err
+			}
+			configs[cfg.Name] = cfg
+			configNames = append(configNames, cfg.Name)
+			plan.resources = append(plan.resources, models.NamespaceImportResource{Configuration: cfg, Old: old})
+		case models.BundleKindNode:
+			node := item.Node
+			if err := api.NodeModeParamCheck(node); err != nil {
+				return nil, err
+			}
+			if err := api.CheckNodeOptionalSysApps(node.SysApps, node.NodeMode); err != nil {
+				return nil, err
+			}
+			var oldNode *specV1.Node
+			if old != nil {
+				oldNode = old.(*specV1.Node)
+			} else {
+				plan.newNodes++
+			}
+			plan.nodes = append(plan.nodes, node)
+			plan.oldNodes = append(plan.oldNodes, oldNode)
+		default:
+			secret := item.Secret
+			secret.Namespace = ns
+			if item.SecretMode == models.BundleSecretEncrypted {
+				key, ok := keys[item.Salt]
+				if !ok {
+					salt, err := base64.StdEncoding.DecodeString(item.Salt)
+					if err != nil || len(salt) == 0 {
+						return nil, common.Error(common.ErrRequestParamInvalid,
+							common.Field("error", fmt.Sprintf("the salt of the encrypted secret (%s) is invalid", secret.Name)))
+					}
+					if key, err = bundleKey(passphrase, salt); err != nil {
+						return nil, err
+					}
+					keys[item.Salt] = key
+				}
+				data, err := util.DecryptMap(secret.Data, key)
+				if err != nil {
+					return nil, common.Error(common.ErrRequestParamInvalid,
+						common.Field("error", fmt.Sprintf("failed to decrypt secret (%s), the passphrase may be wrong", secret.Name)))
+				}
+				secret.Data = data
+			}
+			var oldSecret *specV1.Secret
+			requested[plugin.QuotaStorage] += secretStorageSize(secret)
+			if old != nil {
+				oldSecret = old.(*specV1.Secret)
+				secret.Version = oldSecret.Version
+				secret.CreationTimestamp = oldSecret.CreationTimestamp
+				secret.UpdateTimestamp = time.Now()
+				requested[plugin.QuotaStorage] -= secretStorageSize(oldSecret)
+			}
+			if err := api.checkBundleSecret(item.Kind, secret, oldSecret); err != nil {
+				return nil, err
+			}
+			secretNames = append(secretNames, secret.Name)
+			plan.resources = append(plan.resources, models.NamespaceImportResource{Secret: secret, Old: old})
+		}
+	}
+	if err := api.checkImportQuotas(c, requested); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// checkBundleSecret validates the secret, certificate or registry of the bundle as the routes creating them do
+func (api *API) checkBundleSecret(kind string, secret, old *specV1.Secret) error {
+	switch kind {
+	case models.BundleKindCertificate:
+		if err := models.FromSecretToCertificate(secret, false).ParseCertInfo(); err != nil {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("invalid certificate (%s): %s", secret.Name, err.Error())))
+		}
+	case models.BundleKindRegistry:
+		registry := models.FromSecretToRegistry(secret, false)
+		if err := api.ValidateRegistryModel(registry); err != nil {
+			return err
+		}
+		return checkPassword(api.passwordPolicy, "password", registry.Password)
+	default:
+		var oldData map[string]string
+		if old != nil {
+			oldData = models.FromSecretToView(old, false).Data
+		}
+		return checkSecretPasswords(api.passwordPolicy, models.FromSecretToView(secret, false).Data, oldData)
+	}
+	return nil
+}
+
+// checkImportQuotas checks the apps, configs and storage requested by the import stay within the quotas of the namespace
+func (api *API) checkImportQuotas(c *common.Context, requested map[string]int) error {
+	ns := c.GetNamespace()
+	limits, err := api.Quota.GetQuota(ns)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, name := range []string{plugin.QuotaApp, plugin.QuotaConfig, plugin.QuotaStorage} {
+		if limits[name] > 0 && requested[name] > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	usages, err := api.Quota.CollectUsage(ns, api.AppNumberCollector, api.ConfigNumberCollector, api.StorageSizeCollector)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if usages[name]+requested[name] <= limits[name] {
+			continue
+		}
+		err = &common.QuotaError{Name: name, Limit: limits[name], Used: usages[name], Requested: requested[name]}
+		if !api.TolerateLicense(c, err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// importNamespace imports the validated resources, the progress is reported before each step if not nil.
+// The quota of the new nodes is acquired before anything is imported, and the part of the nodes not imported is released
+func (api *API) importNamespace(c *common.Context, plan *namespaceImportPlan, progress jobProgress) (*models.NamespaceImportResult, error) {
+	ns := c.GetNamespace()
+	acquired := 0
+	if plan.newNodes > 0 {
+		if err := api.Quota.AcquireQuota(ns, plugin.QuotaNode, plan.newNodes); err != nil {
+			if !api.TolerateLicense(c, err) {
+				return nil, err
+			}
+		} else {
+			acquired = plan.newNodes
+		}
+	}
+	defer func() {
+		if acquired > 0 {
+			if e := api.ReleaseQuota(ns, plugin.QuotaNode, acquired); e != nil {
+				log.L().Error("ReleaseQuota error", log.Error(e))
+			}
+		}
+	}()
+
+	total := len(plan.nodes) + 1
+	if progress != nil {
+		if err := progress(0, total); err != nil {
+			return nil, err
+		}
+	}
+	if len(plan.resources) > 0 {
+		if err := api.Facade.ImportResources(ns, plan.resources); err != nil {
+			return nil, err
+		}
+	}
+	// the nodes are created along with their system apps, each in its own transaction
+	for i, node := range plan.nodes {
+		if progress != nil {
+			if err := progress(i+1, total); err != nil {
+				return nil, err
+			}
+		}
+		if plan.oldNodes[i] != nil {
+			if _, err := api.updateNode(c, plan.oldNodes[i], node); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err := api.createNode(c, node, false); err != nil {
+			return nil, err
+		}
+		if acquired > 0 {
+			acquired--
+		}
+	}
+	return plan.result, nil
+}
+
+func parseNamespaceBundle(bundle string) ([]models.NamespaceBundleItem, error) {
+	var items []models.NamespaceBundleItem
+	dec := yaml.NewDecoder(bytes.NewBufferString(bundle))
+	for {
+		item := models.NamespaceBundleItem{}
+		err := dec.Decode(&item)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+		if _, ok := bundleKindOrder[item.Kind]; !ok || bundleItemName(&item) == "" {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("invalid bundle document of kind (%s)", item.Kind)))
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return bundleKindOrder[items[i].Kind] < bundleKindOrder[items[j].Kind]
+	})
+	return items, nil
+}
+
+func bundleItemName(item *models.NamespaceBundleItem) string {
+	switch item.Kind {
+	case models.BundleKindApplication:
+		if item.Application != nil {
+			return item.Application.Name
+		}
+	case models.BundleKindConfiguration:
+		if item.Configuration != nil {
+			return item.Configuration.Name
+		}
+	case models.BundleKindNode:
+		if item.Node != nil {
+			return item.Node.Name
+		}
+	default:
+		if item.Secret != nil {
+			return item.Secret.Name
+		}
+	}
+	return ""
+}
+
+// getBundleResource returns the existing resource of the bundle item, or nil if not found
+func (api *API) getBundleResource(ns string, item *models.NamespaceBundleItem) (interface{}, error) {
+	var res interface{}
+	var err error
+	name := bundleItemName(item)
+	switch item.Kind {
+	case models.BundleKindApplication:
+		res, err = api.App.Get(ns, name, "")
+	case models.BundleKindConfiguration:
+		res, err = api.Config.Get(nil, ns, name, "")
+	case models.BundleKindNode:
+		res, err = api.Node.Get(nil, ns, name)
+	default:
+		res, err = api.Secret.Get(ns, name, "")
+	}
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+// bundleCipher the key to encrypt the secrets of an exported bundle and the salt it is derived with
+type bundleCipher struct {
+	key  []byte
+	salt string
+}
+
+// bundleKey derives the key to encrypt the secrets of a bundle from the passphrase by scrypt
+func bundleKey(passphrase string, salt []byte) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, bundleScryptN, bundleScryptR, bundleScryptP, bundleKeySize)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return key, nil
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+type namespaceBundleMocks struct {
+	app    *ms.MockApplicationService
+	config *ms.MockConfigService
+	secret *ms.MockSecretService
+	node   *ms.MockNodeService
+	quota  *ms.MockQuotaService
+	facade *mf.MockFacade
+}
+
+func initNamespaceBundleAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller, *namespaceBundleMocks) {
+	api := &API{log: log.L().With(log.Any("test", "api"))}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mocks := &namespaceBundleMocks{
+		app:    ms.NewMockApplicationService(mockCtl),
+		config: ms.NewMockConfigService(mockCtl),
+		secret: ms.NewMockSecretService(mockCtl),
+		node:   ms.NewMockNodeService(mockCtl),
+		quota:  ms.NewMockQuotaService(mockCtl),
+		facade: mf.NewMockFacade(mockCtl),
+	}
+	api.AppCombinedService = &service.AppCombinedService{App: mocks.app, Config: mocks.config, Secret: mocks.secret}
+	api.Node = mocks.node
+	api.Quota = mocks.quota
+	api.Facade = mocks.facade
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	sNS.EXPECT().GetSettings("default").Return(&models.NamespaceSettings{}, nil).AnyTimes()
+	api.NS = sNS
+
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	v1 := router.Group("v1")
+	{
+		namespace := v1.Group("/namespace")
+		namespace.GET("/export", mockIM, common.WrapperRaw(api.ExportNamespace, true))
+		namespace.POST("/import", mockIM, common.Wrapper(api.ImportNamespace))
+	}
+	return api, router, mockCtl, mocks
+}
+
+func expectNamespaceExport(mocks *namespaceBundleMocks) {
+	opts := &models.ListOptions{LabelSelector: "!" + common.LabelSystem}
+	mocks.secret.EXPECT().List("default", opts).Return(&models.SecretList{Items: []specV1.Secret{
+		{Name: "s1", Namespace: "default", Version: "1", Data: map[string][]byte{"k": []byte("v")},
+			Labels: map[string]string{specV1.SecretLabel: specV1.SecretConfig}},
+		{Name: "r1", Namespace: "default", Version: "2",
+			Data:   map[string][]byte{"address": []byte("hub.example.com"), "username": []byte("u"), "password": []byte("p")},
+			Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry}},
+	}}, nil).Times(1)
+	mocks.config.EXPECT().List("default", opts).Return(&models.ConfigurationList{Items: []specV1.Configuration{
+		{Name: "c1", Namespace: "default", Version: "3", Data: map[string]string{"a": "b"}},
+		{Name: "sys", Namespace: "default", System: true},
+	}}, nil).Times(1)
+	mocks.app.EXPECT().List("default", opts).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "a1"}}}, nil).Times(1)
+	mocks.app.EXPECT().Get("default", "a1", "").Return(&specV1.Application{
+		Name: "a1", Namespace: "default", Version: "4",
+		Services: []specV1.Service{{Name: "svc", Image: "nginx"}},
+	}, nil).Times(1)
+	mocks.node.EXPECT().List("default", &models.ListOptions{}).Return(&models.NodeList{Items: []specV1.Node{
+		{Name: "n1", Namespace: "default", Version: "5", Description: "desc",
+			Labels: map[string]string{"a": "b", common.LabelNodeName: "n1"}},
+	}}, nil).Times(1)
+}
+
+func exportNamespace(t *testing.T, router *gin.Engine, query, passphrase string) (int, []models.NamespaceBundleItem, string) {
+	req, _ := http.NewRequest(http.MethodGet, "/v1/namespace/export"+query, nil)
+	if passphrase != "" {
+		req.Header.Set(HeaderBundlePassphrase, passphrase)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		return w.Code, nil, ""
+	}
+	items, err := parseNamespaceBundle(w.Body.String())
+	assert.NoError(t, err)
+	return w.Code, items, w.Body.String()
+}
+
+func TestExportNamespace(t *testing.T) {
+	_, router, mockCtl, mocks := initNamespaceBundleAPI(t)
+	defer mockCtl.Finish()
+
+	// the passphrase is required to encrypt secrets
+	code, _, _ := exportNamespace(t, router, "?secretMode=encrypted", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _, _ = exportNamespace(t, router, "?secretMode=unknown", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// redacted by default
+	expectNamespaceExport(mocks)
+	code, items, _ := exportNamespace(t, router, "", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, items, 5)
+	assert.Equal(t, models.BundleKindSecret, items[0].Kind)
+	assert.Equal(t, models.BundleSecretRedacted, items[0].SecretMode)
+	assert.Len(t, items[0].Secret.Data["k"], 0)
+	assert.Empty(t, items[0].Secret.Version)
+	assert.Equal(t, models.BundleKindRegistry, items[1].Kind)
+	assert.Equal(t, models.BundleKindConfiguration, items[2].Kind)
+	assert.Equal(t, "c1", items[2].Configuration.Name)
+	assert.Empty(t, items[2].Configuration.Namespace)
+	assert.Equal(t, models.BundleKindApplication, items[3].Kind)
+	assert.Equal(t, "nginx", items[3].Application.Services[0].Image)
+	assert.Equal(t, models.BundleKindNode, items[4].Kind)
+	assert.Equal(t, map[string]string{"a": "b"}, items[4].Node.Labels)
+	assert.Equal(t, "desc", items[4].Node.Description)
+
+	expectNamespaceExport(mocks)
+	_, items, _ = exportNamespace(t, router, "?secretMode=plain", "")
+	assert.Equal(t, []byte("v"), items[0].Secret.Data["k"])
+
+	expectNamespaceExport(mocks)
+	_, items, _ = exportNamespace(t, router, "?secretMode=encrypted", "pass")
+	assert.Equal(t, models.BundleSecretEncrypted, items[0].SecretMode)
+	assert.NotEqual(t, []byte("v"), items[0].Secret.Data["k"])
+	// the key is derived with a random salt kept in the bundle
+	assert.NotEmpty(t, items[0].Salt)
+	assert.Equal(t, items[0].Salt, items[1].Salt)
+	expectNamespaceExport(mocks)
+	_, others, _ := exportNamespace(t, router, "?secretMode=encrypted", "pass")
+	assert.NotEqual(t, items[0].Salt, others[0].Salt)
+	assert.NotEqual(t, items[0].Secret.Data["k"], others[0].Secret.Data["k"])
+}
+
+func importNamespace(router *gin.Engine, params *models.NamespaceImport, passphrase string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(params)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/namespace/import", bytes.NewReader(body))
+	if passphrase != "" {
+		req.Header.Set(HeaderBundlePassphrase, passphrase)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestImportNamespace(t *testing.T) {
+	_, router, mockCtl, mocks := initNamespaceBundleAPI(t)
+	defer mockCtl.Finish()
+
+	expectNamespaceExport(mocks)
+	_, _, bundle := exportNamespace(t, router, "?secretMode=encrypted", "pass")
+
+	// invalid bundle
+	w := importNamespace(router, &models.NamespaceImport{Bundle: "kind: Unknown\n"}, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = importNamespace(router, &models.NamespaceImport{Bundle: bundle, ConflictPolicy: "unknown"}, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	notFound := common.Error(common.ErrResourceNotFound)
+	existing := &specV1.Application{Name: "a1", Namespace: "default", Version: "10"}
+
+	// fail on conflict by default, nothing is imported
+	mocks.secret.EXPECT().Get("default", "s1", "").Return(nil, notFound).Times(1)
+	mocks.secret.EXPECT().Get("default", "r1", "").Return(nil, notFound).Times(1)
+	mocks.config.EXPECT().Get(nil, "default", "c1", "").Return(nil, notFound).Times(1)
+	mocks.app.EXPECT().Get("default", "a1", "").Return(existing, nil).Times(1)
+	w = importNamespace(router, &models.NamespaceImport{Bundle: bundle}, "pass")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrResourceConflict)
+
+	// the passphrase is required in the header to decrypt secrets
+	mocks.secret.EXPECT().Get("default", "s1", "").Return(nil, notFound).Times(1)
+	w = importNamespace(router, &models.NamespaceImport{Bundle: bundle, ConflictPolicy: models.ConflictPolicySkip}, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	expectGets := func(r1 *specV1.Secret, n1 *specV1.Node) {
+		mocks.secret.EXPECT().Get("default", "s1", "").Return(nil, notFound).Times(1)
+		if r1 == nil {
+			mocks.secret.EXPECT().Get("default", "r1", "").Return(nil, notFound).Times(1)
+		} else {
+			mocks.secret.EXPECT().Get("default", "r1", "").Return(r1, nil).Times(1)
+		}
+		mocks.config.EXPECT().Get(nil, "default", "c1", "").Return(nil, notFound).Times(1)
+		mocks.app.EXPECT().Get("default", "a1", "").Return(nil, notFound).Times(1)
+		if n1 == nil {
+			mocks.node.EXPECT().Get(nil, "default", "n1").Return(nil, notFound).Times(1)
+		} else {
+			mocks.node.EXPECT().Get(nil, "default", "n1").Return(n1, nil).Times(1)
+		}
+	}
+
+	// a wrong passphrase fails the import before anything is imported
+	expectGets(&specV1.Secret{Name: "r1"}, &specV1.Node{Name: "n1"})
+	w = importNamespace(router, &models.NamespaceImport{Bundle: bundle, ConflictPolicy: models.ConflictPolicySkip}, "wrong")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "failed to decrypt secret (s1)")
+
+	// the quotas are checked against all the resources to import
+	expectGets(&specV1.Secret{Name: "r1"}, &specV1.Node{Name: "n1"})
+	mocks.quota.EXPECT().GetQuota("default").Return(map[string]int{plugin.QuotaApp: 1}, nil).Times(1)
+	mocks.quota.EXPECT().CollectUsage("default", gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(map[string]int{plugin.QuotaApp: 1}, nil).Times(1)
+	w = importNamespace(router, &models.NamespaceImport{Bundle: bundle, ConflictPolicy: models.ConflictPolicySkip}, "pass")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrLicenseQuota)
+
+	// the quota of the new nodes is acquired before anything is imported
+	expectGets(nil, nil)
+	mocks.quota.EXPECT().GetQuota("default").Return(map[string]int{}, nil).Times(1)
+	mocks.quota.EXPECT().AcquireQuota("default", plugin.QuotaNode, 1).Return(&common.QuotaError{Name: plugin.QuotaNode, Limit: 1, Used: 1}).Times(1)
+	w = importNamespace(router, &models.NamespaceImport{Bundle: bundle, ConflictPolicy: models.ConflictPolicySkip}, "pass")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrLicenseQuota)
+
+	// skip the existing, the others are imported in one transaction
+	expectGets(&specV1.Secret{Name: "r1"}, &specV1.Node{Name: "n1"})
+	mocks.quota.EXPECT().GetQuota("default").Return(map[string]int{}, nil).Times(1)
+	mocks.facade.EXPECT().ImportResources("default", gomock.Any()).DoAndReturn(func(ns string, resources []models.NamespaceImportResource) error {
+		assert.Len(t, resources, 3)
+		secret := resources[0].Secret
+		assert.Equal(t, "s1", secret.Name)
+		assert.Equal(t, "default", secret.Namespace)
+		assert.Equal(t, []byte("v"), secret.Data["k"])
+		assert.Nil(t, resources[0].Old)
+		assert.Equal(t, "c1", resources[1].Configuration.Name)
+		assert.Equal(t, "a1", resources[2].Application.Name)
+		return nil
+	}).Times(1)
+	w = importNamespace(router, &models.NamespaceImport{Bundle: bundle, ConflictPolicy: models.ConflictPolicySkip}, "pass")
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.NamespaceImportResult{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, []string{"Secret/s1", "Configuration/c1", "Application/a1"}, res.Created)
+	assert.Equal(t, []string{"Registry/r1", "Node/n1"}, res.Skipped)
+	assert.Len(t, res.Updated, 0)
+
+	// overwrite, the redacted secrets are skipped
+	expectNamespaceExport(mocks)
+	_, items, _ := exportNamespace(t, router, "", "")
+	bundle = ""
+	for _, item := range items[:4] {
+		data, err := yaml.Marshal(item)
+		assert.NoError(t, err)
+		bundle += "---\n" + string(data)
+	}
+	oldCfg := &specV1.Configuration{Name: "c1", Version: "7"}
+	mocks.secret.EXPECT().Get("default", gomock.Any(), "").Return(nil, notFound).Times(2)
+	mocks.config.EXPECT().Get(nil, "default", "c1", "").Return(oldCfg, nil).Times(1)
+	mocks.app.EXPECT().Get("default", "a1", "").Return(existing, nil).Times(1)
+	mocks.quota.EXPECT().GetQuota("default").Return(map[string]int{}, nil).Times(1)
+	mocks.facade.EXPECT().ImportResources("default", gomock.Any()).DoAndReturn(func(ns string, resources []models.NamespaceImportResource) error {
+		assert.Len(t, resources, 2)
+		assert.Equal(t, "7", resources[0].Configuration.Version)
+		assert.Equal(t, oldCfg, resources[0].Old)
+		assert.Equal(t, "10", resources[1].Application.Version)
+		assert.Equal(t, existing, resources[1].Old)
+		return nil
+	}).Times(1)
+	w = importNamespace(router, &models.NamespaceImport{Bundle: bundle, ConflictPolicy: models.ConflictPolicyOverwrite}, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = &models.NamespaceImportResult{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Len(t, res.Created, 0)
+	assert.Equal(t, []string{"Configuration/c1", "Application/a1"}, res.Updated)
+	assert.Equal(t, []string{"Secret/s1", "Registry/r1"}, res.Skipped)
+
+	// the failure of the transaction fails the import
+	mocks.secret.EXPECT().Get("default", gomock.Any(), "").Return(nil, notFound).Times(2)
+	mocks.config.EXPECT().Get(nil, "default", "c1", "").Return(oldCfg, nil).Times(1)
+	mocks.app.EXPECT().Get("default", "a1", "").Return(existing, nil).Times(1)
+	mocks.quota.EXPECT().GetQuota("default").Return(map[string]int{}, nil).Times(1)
+	mocks.facade.EXPECT().ImportResources("default", gomock.Any()).Return(common.Error(common.ErrRequestParamInvalid, common.Field("error", "the app (a1) has a canary in progress"))).Times(1)
+	w = importNamespace(router, &models.NamespaceImport{Bundle: bundle, ConflictPolicy: models.ConflictPolicyOverwrite}, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "canary in progress")
+}
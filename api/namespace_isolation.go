@@ -0,0 +1,174 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// NamespaceIsolationProbePrefix the prefix of the probe namespace, which owns no resource
+const NamespaceIsolationProbePrefix = "baetyl-isolation-"
+
+// isolationProbe reads a resource type, first returns the name of any resource of the namespace,
+// get returns whether the resource is read, and count returns the number of the resources listed
+type isolationProbe struct {
+	resource common.Resource
+	first    func(ns string) (string, error)
+	get      func(ns, name string) (bool, error)
+	count    func(ns string) (int, error)
+}
+
+// CheckNamespaceIsolation reads a resource of each type of the namespace from a probe namespace owning nothing,
+// and lists the probe namespace, all the reads are expected to be blocked
+func (api *API) CheckNamespaceIsolation(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	res := &models.NamespaceIsolationCheck{
+		Namespace: ns,
+		Probe:     NamespaceIsolationProbePrefix + strings.ToLower(common.RandString(8)),
+		Items:     []models.NamespaceIsolationItem{},
+	}
+	before := service.BlockedNamespaceReads()
+	passed := true
+	for _, p := range api.isolationProbes() {
+		item, err := checkIsolation(p, ns, res.Probe)
+		if err != nil {
+			return nil, err
+		}
+		passed = passed && item.Blocked
+		res.Items = append(res.Items, item)
+	}
+	res.BlockedReads = service.BlockedNamespaceReads()
+	res.Passed = passed && res.BlockedReads == before
+	return res, nil
+}
+
+func checkIsolation(p isolationProbe, ns, probe string) (models.NamespaceIsolationItem, error) {
+	item := models.NamespaceIsolationItem{Resource: string(p.resource), Blocked: true}
+	name, err := p.first(ns)
+	if err != nil {
+		return item, err
+	}
+	if name == "" {
+		item.Skipped = true
+		item.Message = "no resource to read in the namespace"
+		return item, nil
+	}
+	item.Name = name
+	found, err := p.get(probe, name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			item.Message = err.Error()
+		}
+	}
+	if found {
+		item.Blocked = false
+		item.Message = fmt.Sprintf("the %s (%s) is read from the probe namespace", p.resource, name)
+		return item, nil
+	}
+	n, err := p.count(probe)
+	if err != nil {
+		item.Message = err.Error()
+	}
+	if n > 0 {
+		item.Blocked = false
+		item.Message = fmt.Sprintf("%d %s(s) are listed in the probe namespace", n, p.resource)
+	}
+	return item, nil
+}
+
+func (api *API) isolationProbes() []isolationProbe {
+	first := func() *models.ListOptions {
+		return &models.ListOptions{Filter: models.Filter{PageNo: 1, PageSize: 1}}
+	}
+	return []isolationProbe{
+		{
+			resource: common.Node,
+			first: func(ns string) (string, error) {
+				list, err := api.Node.List(ns, first())
+				if err != nil || len(list.Items) == 0 {
+					return "", err
+				}
+				return list.Items[0].Name, nil
+			},
+			get: func(ns, name string) (bool, error) {
+				node, err := api.Node.Get(nil, ns, name)
+				return err == nil && node != nil, err
+			},
+			count: func(ns string) (int, error) {
+				list, err := api.Node.List(ns, &models.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+		},
+		{
+			resource: common.Application,
+			first: func(ns string) (string, error) {
+				list, err := api.App.List(ns, first())
+				if err != nil || len(list.Items) == 0 {
+					return "", err
+				}
+				return list.Items[0].Name, nil
+			},
+			get: func(ns, name string) (bool, error) {
+				app, err := api.App.Get(ns, name, "")
+				return err == nil && app != nil, err
+			},
+			count: func(ns string) (int, error) {
+				list, err := api.App.List(ns, &models.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+		},
+		{
+			resource: common.Config,
+			first: func(ns string) (string, error) {
+				list, err := api.Config.List(ns, first())
+				if err != nil || len(list.Items) == 0 {
+					return "", err
+				}
+				return list.Items[0].Name, nil
+			},
+			get: func(ns, name string) (bool, error) {
+				cfg, err := api.Config.Get(nil, ns, name, "")
+				return err == nil && cfg != nil, err
+			},
+			count: func(ns string) (int, error) {
+				list, err := api.Config.List(ns, &models.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+		},
+		{
+			resource: common.Secret,
+			first: func(ns string) (string, error) {
+				list, err := api.Secret.List(ns, first())
+				if err != nil || len(list.Items) == 0 {
+					return "", err
+				}
+				return list.Items[0].Name, nil
+			},
+			get: func(ns, name string) (bool, error) {
+				secret, err := api.Secret.Get(ns, name, "")
+				return err == nil && secret != nil, err
+			},
+			count: func(ns string) (int, error) {
+				list, err := api.Secret.List(ns, &models.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+		},
+	}
+}
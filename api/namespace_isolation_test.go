@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestCheckNamespaceIsolation(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode, sApp := ms.NewMockNodeService(mockCtl), ms.NewMockApplicationService(mockCtl)
+	sConfig, sSecret := ms.NewMockConfigService(mockCtl), ms.NewMockSecretService(mockCtl)
+	api := &API{Node: sNode, AppCombinedService: &service.AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret}}
+
+	router := gin.New()
+	router.GET("/v1/namespace/isolation-check", func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }, common.Wrapper(api.CheckNamespaceIsolation))
+
+	first := &models.ListOptions{Filter: models.Filter{PageNo: 1, PageSize: 1}}
+	isProbe := probeNamespace{}
+	notFound := common.Error(common.ErrResourceNotFound)
+
+	sNode.EXPECT().List("default", first).Return(&models.NodeList{Items: []specV1.Node{{Name: "n0"}}}, nil).Times(2)
+	sNode.EXPECT().Get(nil, isProbe, "n0").Return(nil, notFound).Times(2)
+	sNode.EXPECT().List(isProbe, &models.ListOptions{}).Return(&models.NodeList{}, nil).Times(2)
+	sApp.EXPECT().List("default", first).Return(&models.ApplicationList{}, nil).Times(2)
+	sConfig.EXPECT().List("default", first).Return(&models.ConfigurationList{Items: []specV1.Configuration{{Name: "c0"}}}, nil).Times(2)
+	sConfig.EXPECT().Get(nil, isProbe, "c0", "").Return(nil, notFound).Times(2)
+	sConfig.EXPECT().List(isProbe, &models.ListOptions{}).Return(&models.ConfigurationList{}, nil).Times(1)
+	sSecret.EXPECT().List("default", first).Return(&models.SecretList{Items: []specV1.Secret{{Name: "s0"}}}, nil).Times(2)
+	sSecret.EXPECT().Get(isProbe, "s0", "").Return(nil, notFound).Times(1)
+	sSecret.EXPECT().List(isProbe, &models.ListOptions{}).Return(&models.SecretList{}, nil).Times(1)
+
+	check := func() *models.NamespaceIsolationCheck {
+		req, _ := http.NewRequest(http.MethodGet, "/v1/namespace/isolation-check", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		res := new(models.NamespaceIsolationCheck)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+		return res
+	}
+
+	res := check()
+	assert.True(t, res.Passed)
+	assert.Equal(t, "default", res.Namespace)
+	assert.True(t, strings.HasPrefix(res.Probe, NamespaceIsolationProbePrefix))
+	assert.Equal(t, []models.NamespaceIsolationItem{
+		{Resource: string(common.Node), Name: "n0", Blocked: true},
+		{Resource: string(common.Application), Blocked: true, Skipped: true, Message: "no resource to read in the namespace"},
+		{Resource: string(common.Config), Name: "c0", Blocked: true},
+		{Resource: string(common.Secret), Name: "s0", Blocked: true},
+	}, res.Items)
+
+	// the config is listed and the secret is read from the probe namespace
+	sConfig.EXPECT().List(isProbe, &models.ListOptions{}).Return(&models.ConfigurationList{Items: []specV1.Configuration{{Name: "c0"}}}, nil).Times(1)
+	sSecret.EXPECT().Get(isProbe, "s0", "").Return(&specV1.Secret{Name: "s0"}, nil).Times(1)
+	res = check()
+	assert.False(t, res.Passed)
+	assert.False(t, res.Items[2].Blocked)
+	assert.Equal(t, "1 config(s) are listed in the probe namespace", res.Items[2].Message)
+	assert.False(t, res.Items[3].Blocked)
+	assert.Equal(t, "the secret (s0) is read from the probe namespace", res.Items[3].Message)
+}
+
+// probeNamespace matches the probe namespace generated by the check
+type probeNamespace struct{}
+
+func (probeNamespace) Matches(x interface{}) bool {
+	ns, ok := x.(string)
+	return ok && strings.HasPrefix(ns, NamespaceIsolationProbePrefix)
+}
+
+func (probeNamespace) String() string {
+	return "is a probe namespace"
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetNamespaceSummary returns the numbers of the resources created by user, the nodes by status,
+// the apps deployed and the usages of the quotas of the namespace in one response
+func (api *API) GetNamespaceSummary(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	opts := &models.ListOptions{LabelSelector: "!" + common.LabelSystem}
+	res := &models.NamespaceSummary{Namespace: ns, Resources: map[string]int{}}
+
+	apps, err := api.App.List(ns, opts)
+	if err != nil {
+		return nil, err
+	}
+	res.Resources[string(common.APP)] = apps.Total
+	configs, err := api.Config.List(ns, opts)
+	if err != nil {
+		return nil, err
+	}
+	res.Resources[string(common.Config)] = configs.Total
+	secrets, err := api.Secret.List(ns, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, typ := range summarySecretTypes {
+		res.Resources[typ] = 0
+	}
+	for _, s := range secrets.Items {
+		if typ, ok := summarySecretTypes[s.Labels[specV1.SecretLabel]]; ok {
+			res.Resources[typ]++
+		}
+	}
+	groups, err := api.NodeGroup.List(ns, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	res.Resources[string(common.NodeGroup)] = len(groups.Items)
+
+	nodes, err := api.Node.List(ns, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	res.Resources[string(common.Node)] = len(nodes.Items)
+	reportTimes, err := api.Node.ListReportTime(ns)
+	if err != nil {
+		return nil, err
+	}
+	deployed := map[string]bool{}
+	for _, node := range nodes.Items {
+		switch api.Offline.Status(ns, reportTimes[node.Name]) {
+		case models.ReadyTypeOnline:
+			res.Nodes.Online++
+		case models.ReadyTypOffline:
+			res.Nodes.Offline++
+		default:
+			res.Nodes.Uninstall++
+		}
+		for _, info := range node.Desire.AppInfos(false) {
+			deployed[info.Name] = true
+		}
+	}
+	res.Nodes.Total = len(nodes.Items)
+	res.DeployedApps = len(deployed)
+
+	if res.Quotas, err = api.quotaUsages(ns); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// summarySecretTypes the resource types of the secrets by their labels
+var summarySecretTypes = map[string]string{
+	specV1.SecretConfig:      string(common.Secret),
+	specV1.SecretRegistry:    string(common.Registry),
+	specV1.SecretCertificate: string(common.Certificate),
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestGetNamespaceSummary(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sGroup := ms.NewMockNodeGroupService(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	sOffline := ms.NewMockNodeOfflineService(mockCtl)
+	sQuota := ms.NewMockQuotaService(mockCtl)
+	api := &API{Node: sNode, NodeGroup: sGroup, Offline: sOffline, Quota: sQuota,
+		AppCombinedService: &service.AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret}}
+
+	router := gin.New()
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	router.GET("/v1/namespace/summary", mockIM, common.Wrapper(api.GetNamespaceSummary))
+	do := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/v1/namespace/summary", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	opts := &models.ListOptions{LabelSelector: "!" + common.LabelSystem}
+	sApp.EXPECT().List("default", opts).Return(&models.ApplicationList{Total: 3}, nil).Times(1)
+	sConfig.EXPECT().List("default", opts).Return(&models.ConfigurationList{Total: 2}, nil).Times(1)
+	sSecret.EXPECT().List("default", opts).Return(&models.SecretList{Items: []specV1.Secret{
+		{Name: "s1", Labels: map[string]string{specV1.SecretLabel: specV1.SecretConfig}},
+		{Name: "r1", Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry}},
+		{Name: "r2", Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry}},
+	}}, nil).Times(1)
+	sGroup.EXPECT().List("default", gomock.Any()).Return(&models.NodeGroupList{Items: []models.NodeGroup{{Name: "g1"}}}, nil).Times(1)
+	sNode.EXPECT().List("default", gomock.Any()).Return(&models.NodeList{Items: []specV1.Node{
+		{Name: "n1", Desire: specV1.Desire{specV1.KeyApps: []specV1.AppInfo{{Name: "app1"}, {Name: "app2"}}}},
+		{Name: "n2", Desire: specV1.Desire{specV1.KeyApps: []specV1.AppInfo{{Name: "app1"}}}},
+		{Name: "n3"},
+	}}, nil).Times(1)
+	recent, stale := time.Now(), time.Now().Add(-time.Hour)
+	sNode.EXPECT().ListReportTime("default").Return(map[string]time.Time{"n1": recent, "n2": stale}, nil).Times(1)
+	sOffline.EXPECT().Status("default", recent).Return(models.ReadyTypeOnline).Times(1)
+	sOffline.EXPECT().Status("default", stale).Return(models.ReadyTypOffline).Times(1)
+	sOffline.EXPECT().Status("default", time.Time{}).Return(models.ReadyTypeUninstall).Times(1)
+	sQuota.EXPECT().GetQuota("default").Return(map[string]int{"maxAppCount": 10}, nil).Times(1)
+	sQuota.EXPECT().CollectUsage("default", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(map[string]int{"maxAppCount": 3, "maxNodeCount": 3}, nil).Times(1)
+
+	w := do()
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.NamespaceSummary
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, models.NamespaceSummary{
+		Namespace:    "default",
+		Resources:    map[string]int{"app": 3, "config": 2, "secret": 1, "registry": 2, "certificate": 0, "nodegroup": 1, "node": 3},
+		Nodes:        models.NodeSummary{Total: 3, Online: 1, Offline: 1, Uninstall: 1},
+		DeployedApps: 2,
+		Quotas: map[string]models.QuotaUsage{
+			"maxAppCount":  {Limit: 10, Used: 3},
+			"maxNodeCount": {Used: 3},
+		},
+	}, res)
+
+	sApp.EXPECT().List("default", opts).Return(nil, errors.New("failed to list apps")).Times(1)
+	w = do()
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
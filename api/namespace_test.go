@@ -1,11 +1,14 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -34,6 +37,9 @@ func initNamespaceAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		testA.POST("", mockIMtestA, common.Wrapper(api.CreateNamespace))
 		testA.GET("", mockIMtestA, common.Wrapper(api.GetNamespace))
 		testA.DELETE("", mockIMtestA, common.Wrapper(api.DeleteNamespace))
+		testA.GET("/settings", mockIMtestA, common.Wrapper(api.GetNamespaceSettings))
+		testA.PUT("/settings", mockIMtestA, common.Wrapper(api.UpdateNamespaceSettings))
+		testA.GET("/features", mockIMtestA, common.Wrapper(api.GetNamespaceFeatures))
 	}
 	v2 := router.Group("testB")
 	{
@@ -138,3 +144,117 @@ func TestAPI_DeleteNamespace(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+func TestNamespaceSettings(t *testing.T) {
+	api, router, mockCtl := initNamespaceAPI(t)
+	defer mockCtl.Finish()
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	api.NS = sNS
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+
+	settings := &models.NamespaceSettings{NodeLabels: map[string]string{"tenant": "acme", "zone": "east"}}
+
+	sNS.EXPECT().GetSettings("testA").Return(settings, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/testA/namespace/settings", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.NamespaceSettings{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, settings, res)
+
+	// saved only
+	body, _ := json.Marshal(settings)
+	sNS.EXPECT().UpdateSettings("testA", settings).Return(settings, nil)
+	req, _ = http.NewRequest(http.MethodPut, "/testA/namespace/settings", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	result := &models.NamespaceSettingsResult{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), result))
+	assert.Equal(t, settings.NodeLabels, result.NodeLabels)
+	assert.Nil(t, result.Updated)
+
+	// applied to the existing nodes
+	nodes := &models.NodeList{Items: []specV1.Node{
+		{Name: "n0", Namespace: "testA", Labels: map[string]string{"tenant": "acme", "zone": "east"}},
+		{Name: "n1", Namespace: "testA", Labels: map[string]string{"zone": "west"}},
+		{Name: "n2", Namespace: "testA"},
+		{Name: "n3", Namespace: "testA"},
+	}}
+	sNS.EXPECT().UpdateSettings("testA", settings).Return(settings, nil)
+	sNode.EXPECT().List("testA", gomock.Any()).Return(nodes, nil)
+	sNode.EXPECT().Update("testA", gomock.Any()).DoAndReturn(func(_ string, n *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "n1", n.Name)
+		assert.Equal(t, map[string]string{"tenant": "acme", "zone": "west"}, n.Labels)
+		return n, nil
+	})
+	sNode.EXPECT().Update("testA", gomock.Any()).DoAndReturn(func(_ string, n *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "n2", n.Name)
+		assert.Equal(t, settings.NodeLabels, n.Labels)
+		return n, nil
+	})
+	sNode.EXPECT().Update("testA", gomock.Any()).Return(nil, fmt.Errorf("error"))
+	req, _ = http.NewRequest(http.MethodPut, "/testA/namespace/settings?applyExisting=true", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	result = &models.NamespaceSettingsResult{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), result))
+	assert.Equal(t, []string{"n1", "n2"}, result.Updated)
+	assert.Equal(t, []string{
+		"the label (zone) of the node (n1) is kept as (west) rather than the default (east)",
+		"failed to apply the default labels to the node (n3): error",
+	}, result.Warnings)
+
+	// reserved label
+	body, _ = json.Marshal(&models.NamespaceSettings{NodeLabels: map[string]string{"baetyl-node": "x"}})
+	req, _ = http.NewRequest(http.MethodPut, "/testA/namespace/settings", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// invalid applyExisting
+	body, _ = json.Marshal(settings)
+	req, _ = http.NewRequest(http.MethodPut, "/testA/namespace/settings?applyExisting=abc", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sNS.EXPECT().UpdateSettings("testA", settings).Return(nil, fmt.Errorf("error"))
+	req, _ = http.NewRequest(http.MethodPut, "/testA/namespace/settings", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestNamespaceFeatures(t *testing.T) {
+	api, router, mockCtl := initNamespaceAPI(t)
+	defer mockCtl.Finish()
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	api.NS = sNS
+
+	features := map[string]bool{models.FeatureFunctions: true, models.FeatureCanary: false}
+	sNS.EXPECT().GetFeatures("testA").Return(features, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/testA/namespace/features", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.NamespaceFeatures
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, features, res.Features)
+
+	sNS.EXPECT().GetFeatures("testA").Return(nil, fmt.Errorf("error"))
+	req, _ = http.NewRequest(http.MethodGet, "/testA/namespace/features", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	sNS.EXPECT().IsFeatureEnabled("testA", models.FeatureCanary).Return(false, nil)
+	err := api.CheckFeature("testA", models.FeatureCanary)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The feature (canary) is not enabled in the namespace.")
+	sNS.EXPECT().IsFeatureEnabled("testA", models.FeatureFunctions).Return(true, nil)
+	assert.NoError(t, api.CheckFeature("testA", models.FeatureFunctions))
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -37,6 +38,9 @@ const (
 	MethodCurl              = "curl"
 	PlatformWindows         = "windows"
 	PlatformAndroid         = "android"
+	PlatformLinuxAMD64      = "linux-amd64"
+	PlatformLinuxARM64      = "linux-arm64"
+	PlatformK8sYaml         = "k8s-yaml"
 	DeprecatedGPUMetrics    = "baetyl-gpu-metrics"
 	DeprecatedDmp           = "baetyl-dmp"
 
@@ -59,6 +63,18 @@ const (
 
 	ByteUnitKB = "KB"
 	ByteUnitMB = "MB"
+
+	NodeTagsMaxCount      = 64
+	NodeTagKeyMaxLength   = 128
+	NodeTagValueMaxLength = 512
+
+	NodeInitTokenMaxTTL = 7 * 24 * time.Hour
+
+	// HeaderWarning the warnings of the successful requests, formatted as 299 - "<message>"
+	HeaderWarning = "Warning"
+
+	NodeDeployHistoryDefaultPageSize = 20
+	NodeDeployHistoryMaxPageSize     = 100
 )
 
 var (
@@ -74,6 +90,13 @@ var (
 		HookDeleteNodeOta,
 		HookDeleteNodeDmp,
 	}
+	// nodeSystemLabels the labels of the nodes maintained by the cloud, which are not relabeled in batch
+	nodeSystemLabels = map[string]bool{
+		common.LabelNodeName:    true,
+		common.LabelAccelerator: true,
+		common.LabelCluster:     true,
+		common.LabelNodeMode:    true,
+	}
 )
 
 type CreateNodeHook = func(*common.Context, *v1.Node) (*v1.Node, error)
@@ -84,11 +107,24 @@ type DeleteNodeHook = func(*common.Context, *v1.Node) error
 func (api *API) GetNode(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
 	node, err := api.Node.Get(nil, ns, n)
-	if err != nil {
+	if err = checkResourceFound(node, err, common.Node, n); err != nil {
 		return nil, err
 	}
 
-	return api.ToNodeView(node)
+	view, err := api.ToNodeView(node)
+	if err != nil {
+		return nil, err
+	}
+	var reportTime time.Time
+	if view.Report != nil && view.Report.Time != nil {
+		reportTime = *view.Report.Time
+	}
+	return &models.NodeDetailView{
+		NodeView:    view,
+		Status:      api.Offline.Status(ns, reportTime),
+		Drain:       nodeDrainView(node, models.GetNodeDrain(node)),
+		Maintenance: models.GetNodeMaintenance(node),
+	}, nil
 }
 
 func (api *API) GetNodes(c *common.Context) (interface{}, error) {
@@ -120,25 +156,56 @@ func (api *API) GetNodes(c *common.Context) (interface{}, error) {
 	return nodeViewList, nil
 }
 
-// GetNodeStats get a node stats
-func (api *API) GetNodeStats(c *common.Context) (interface{}, error) {
-	ns, n := c.GetNamespace(), c.GetNameFromParam()
-
-	node, err := api.Node.Get(nil, ns, n)
-	if err != nil {
-		return nil, err
+// BatchGetNodes get nodes by names, the names are deduplicated and the found nodes are ordered by name,
+// so the same set of names always gets the same response. The names not found are listed rather than skipped
+func (api *API) BatchGetNodes(c *common.Context) (interface{}, error) {
+	nodeNames := &models.NodeBatchNames{}
+	if err := c.LoadBody(nodeNames); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
 	}
+	names := make([]string, 0, len(nodeNames.Names))
+	seen := map[string]bool{}
+	for _, name := range nodeNames.Names {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
 
-	view, err := api.ToNodeView(node)
-	if err != nil {
-		return nil, err
+	ns := c.GetNamespace()
+	res := &models.NodeBatchGetResult{
+		Items:    make([]v1.NodeView, 0, len(names)),
+		NotFound: make([]string, 0),
+	}
+	for _, name := range names {
+		node, err := api.Node.Get(nil, ns, name)
+		if err != nil {
+			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				res.NotFound = append(res.NotFound, name)
+				continue
+			}
+			return nil, err
+		}
+		view, err := api.ToNodeView(node)
+		if err != nil {
+			return nil, err
+		}
+		view.Desire = nil
+		res.Items = append(res.Items, *view)
 	}
+	res.Total = len(res.Items)
+	return res, nil
+}
 
-	view.Desire = nil
-	return view, nil
+// GetNodeStats get a node stats
+func (api *API) GetNodeStats(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.getNodeStatsView(ns, n)
 }
 
-// ListNode list node
+// ListNode list node, sortBy is one of name and createTime, and order is asc or desc.
+// The online nodes are ranked first if neither sortBy nor createSort is set
 func (api *API) ListNode(c *common.Context) (interface{}, error) {
 	ns := c.GetNamespace()
 	params, err := api.ParseListOptions(c)
@@ -148,6 +215,18 @@ func (api *API) ListNode(c *common.Context) (interface{}, error) {
 	if err := params.NodeOptionsCheck(); err != nil {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
 	}
+	if err := params.CheckCursorPaging(); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err := params.CheckSort(models.SortByName, models.SortByCreateTime); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err := checkNodeStatsWindow(params); err != nil {
+		return nil, err
+	}
+	if _, err := common.ParseLabelSelector(params.LabelSelector); err != nil {
+		return nil, err
+	}
 	nodeList, err := api.Node.List(ns, params)
 	if err != nil {
 		return nil, err
@@ -166,11 +245,17 @@ func (api *API) ListNode(c *common.Context) (interface{}, error) {
 			return nil, err
 		}
 		view.Desire = nil
+		if !params.WithTags {
+			view.Annotations = common.SetNodeTags(view.Annotations, nil)
+		}
 
 		nodeViewList.Items = append(nodeViewList.Items, *view)
 	}
 	filterByNodeSelector(&nodeViewList)
 
+	if params.IncludeStats {
+		return api.joinNodeStats(ns, &nodeViewList, params.StatsWindow)
+	}
 	return nodeViewList, nil
 }
 
@@ -211,6 +296,12 @@ func (api *API) CreateNode(c *common.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	return api.createNode(c, n, true)
+}
+
+// createNode creates the node along with its system apps, the quota of the node is acquired unless acquireQuota
+// is false, in which case it is held by the caller
+func (api *API) createNode(c *common.Context, n *v1.Node, acquireQuota bool) (*v1.NodeView, error) {
 	ns := c.GetNamespace()
 	n.Namespace = ns
 
@@ -229,30 +320,39 @@ func (api *API) CreateNode(c *common.Context) (interface{}, error) {
 	}
 
 	if oldNode != nil {
-		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
+		return nil, common.Error(common.ErrResourceConflict, common.Field("type", "node"), common.Field("name", n.Name))
 	}
 
-	err = api.Quota.AcquireQuota(ns, plugin.QuotaNode, NodeNumber)
+	// the default labels of the namespace are merged, the labels given by the node take precedence
+	settings, err := api.NS.GetSettings(ns)
 	if err != nil {
 		return nil, err
 	}
-
-	if n.Attributes == nil {
-		n.Attributes = make(map[string]interface{})
-	}
-	version, err := api.getCoreLatestVersion()
-	if err != nil {
-		return nil, err
+	var conflicts []string
+	n.Labels, conflicts = settings.MergeNodeLabels(n.Labels)
+	for _, k := range conflicts {
+		c.Writer.Header().Add(HeaderWarning, fmt.Sprintf(`299 - "the label (%s) is kept as (%s) rather than the default (%s) of the namespace"`,
+			k, n.Labels[k], settings.NodeLabels[k]))
 	}
-	n.Attributes["BaetylCoreVersion"] = version
-	n.Attributes[UserID] = c.GetUserInfo().User.ID
 
-	n.SysApps = common.UpdateSysAppByAccelerator(n.Accelerator, n.SysApps)
+	acquired := false
+	if acquireQuota {
+		if err = api.Quota.AcquireQuota(ns, plugin.QuotaNode, NodeNumber); err != nil {
+			if !api.TolerateLicense(c, err) {
+				return nil, err
+			}
+		} else {
+			acquired = true
+		}
+	}
 
-	node, err := api.Wrapper.CreateNodeTx(api.Node.Create)(nil, n.Namespace, n)
+	node, err := api.persistNode(c, n)
 	if err != nil {
-		if e := api.ReleaseQuota(ns, plugin.QuotaNode, NodeNumber); e != nil {
-			log.L().Error("ReleaseQuota error", log.Error(e))
+		// the quota acquired is rolled back if the node is not persisted
+		if acquired {
+			if e := api.ReleaseQuota(ns, plugin.QuotaNode, NodeNumber); e != nil {
+				log.L().Error("ReleaseQuota error", log.Error(e))
+			}
 		}
 		return nil, err
 	}
@@ -278,6 +378,30 @@ func (api *API) CreateNode(c *common.Context) (interface{}, error) {
 	return view, nil
 }
 
+func (api *API) persistNode(c *common.Context, n *v1.Node) (*v1.Node, error) {
+	if n.Attributes == nil {
+		n.Attributes = make(map[string]interface{})
+	}
+	version, err := api.getCoreLatestVersion()
+	if err != nil {
+		return nil, err
+	}
+	n.Attributes["BaetylCoreVersion"] = version
+	n.Attributes[UserID] = c.GetUserInfo().User.ID
+
+	n.SysApps = common.UpdateSysAppByAccelerator(n.Accelerator, n.SysApps)
+
+	node, err := api.Wrapper.CreateNodeTx(api.Node.Create)(nil, n.Namespace, n)
+	if err != nil {
+		// the node created by a racing request without the lock is reported as a conflict rather than the error of the storage
+		if old, e := api.Node.Get(nil, n.Namespace, n.Name); e == nil && old != nil {
+			return nil, common.Error(common.ErrResourceConflict, common.Field("type", "node"), common.Field("name", n.Name))
+		}
+		return nil, err
+	}
+	return node, nil
+}
+
 // UpdateNode update the node
 func (api *API) UpdateNode(c *common.Context) (interface{}, error) {
 	node, err := api.ParseAndCheckNode(c)
@@ -289,7 +413,15 @@ func (api *API) UpdateNode(c *common.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = checkResourceVersion(c, common.Node, oldNode.Name, oldNode.Version); err != nil {
+		return nil, err
+	}
+	return api.updateNode(c, oldNode, node)
+}
 
+func (api *API) updateNode(c *common.Context, oldNode, node *v1.Node) (*v1.NodeView, error) {
+	var err error
+	ns := c.GetNamespace()
 	node.Labels = common.AddSystemLabel(node.Labels, map[string]string{
 		common.LabelNodeName:    node.Name,
 		common.LabelAccelerator: node.Accelerator,
@@ -297,6 +429,8 @@ func (api *API) UpdateNode(c *common.Context) (interface{}, error) {
 		common.LabelNodeMode:    oldNode.NodeMode,
 	})
 	node.Version = oldNode.Version
+	// the tags are only changed by UpdateNodeTags
+	node.Annotations = common.SetNodeTags(node.Annotations, common.GetNodeTags(oldNode.Annotations))
 	node.Attributes = oldNode.Attributes
 	if node.Attributes != nil {
 		if _, ok := node.Attributes[UserID]; !ok {
@@ -386,31 +520,82 @@ func (api *API) DeleteNode(c *common.Context) (interface{}, error) {
 	node, err := api.Node.Get(nil, ns, n)
 	if err != nil {
 		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
-			return nil, nil
+			return nil, checkIfMatch(c, common.Node, n, "")
 		}
 		return nil, err
 	}
+	if err = checkIfMatch(c, common.Node, n, node.Version); err != nil {
+		return nil, err
+	}
 
-	for _, item := range HookDeleteList {
-		if f, exist := api.Hooks[item]; exist {
-			if hk, ok := f.(DeleteNodeHook); ok {
-				err = hk(c, node)
-				if err != nil {
-					return nil, err
+	if err = api.deleteNode(c, ns, node); err != nil {
+		return nil, err
+	}
+	if api.nodeHoldsQuota(node) {
+		if e := api.ReleaseQuota(ns, plugin.QuotaNode, NodeNumber); e != nil {
+			log.L().Error("ReleaseQuota error", log.Error(e))
+		}
+	}
+
+	return api.deleteAllSysAppsOfNode(node)
+}
+
+// BatchDeleteNodes delete nodes by names, a failure on one node does not abort the rest
+func (api *API) BatchDeleteNodes(c *common.Context) (interface{}, error) {
+	nodeNames := &models.NodeBatchNames{}
+	if err := c.LoadBody(nodeNames); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	ns := c.GetNamespace()
+	res := &models.NodeDeleteResultList{
+		Items: make([]models.NodeDeleteResult, 0, len(nodeNames.Names)),
+	}
+	for _, name := range nodeNames.Names {
+		item := models.NodeDeleteResult{Name: name}
+		node, err := api.Node.Get(nil, ns, name)
+		if err == nil {
+			err = api.deleteNode(c, ns, node)
+		}
+		if err == nil {
+			// the node deleted is never reported as failed, the quota failed to be released is reported as a warning
+			item.Deleted = true
+			if api.nodeHoldsQuota(node) {
+				if e := api.ReleaseQuota(ns, plugin.QuotaNode, NodeNumber); e != nil {
+					log.L().Error("ReleaseQuota error", log.Any("name", name), log.Error(e))
+					item.Warning = fmt.Sprintf("the node quota failed to be released: %s", e.Error())
+					c.Writer.Header().Add(HeaderWarning, fmt.Sprintf(`299 - "the node quota of (%s) failed to be released"`, name))
 				}
 			}
+			if _, e := api.deleteAllSysAppsOfNode(node); e != nil {
+				log.L().Error("delete sys apps of node error", log.Any("name", name), log.Error(e))
+			}
+		} else {
+			item.Code, item.Message = common.ErrUnknown, err.Error()
+			if e, ok := err.(errors.Coder); ok {
+				item.Code = e.Code()
+			}
+			res.Failed++
 		}
+		res.Items = append(res.Items, item)
 	}
+	res.Total = len(res.Items)
+	return res, nil
+}
 
-	// Delete Node
-	if err = api.Node.Delete(nil, c.GetNamespace(), node); err != nil {
-		return nil, err
+func (api *API) deleteNode(c *common.Context, ns string, node *v1.Node) error {
+	if err := checkNodeDrained(node); err != nil {
+		return err
 	}
-	if e := api.ReleaseQuota(ns, plugin.QuotaNode, NodeNumber); e != nil {
-		log.L().Error("ReleaseQuota error", log.Error(e))
+	for _, item := range HookDeleteList {
+		if f, exist := api.Hooks[item]; exist {
+			if hk, ok := f.(DeleteNodeHook); ok {
+				if err := hk(c, node); err != nil {
+					return err
+				}
+			}
+		}
 	}
-
-	return api.deleteAllSysAppsOfNode(node)
+	return api.Node.Delete(nil, ns, node)
 }
 
 func (api *API) ToNodeView(node *v1.Node) (*v1.NodeView, error) {
@@ -502,7 +687,8 @@ func (api *API) GetFunctionsByNode(c *common.Context) (interface{}, error) {
 	}, err
 }
 
-// GenInitCmdFromNode generate install command
+// GenInitCmdFromNode generate install command, the platform selects the template of the command,
+// a shell command for linux, a powershell command for windows, or the manifest of the init for k8s-yaml
 func (api *API) GenInitCmdFromNode(c *common.Context) (interface{}, error) {
 	ns, name := c.GetNamespace(), c.Param("name")
 	_, err := api.Node.Get(nil, ns, name)
@@ -521,15 +707,51 @@ func (api *API) GenInitCmdFromNode(c *common.Context) (interface{}, error) {
 	if method == MethodWget {
 		template = service.TemplateInitCommandWget
 	}
-	switch c.Query("platform") {
+	params := map[string]interface{}{
+		"mode": mode,
+	}
+	format := models.InitCMDFormatShell
+	switch platform := c.Query("platform"); platform {
+	case "":
+	case PlatformLinuxAMD64, PlatformLinuxARM64:
+		// the template of the platform is optional, the one of the method is used if not set
+		params["fallbackTemplate"] = template
+		params["arch"] = strings.TrimPrefix(platform, "linux-")
+		template = service.TemplateBaetylInitCommand + "-" + platform
 	case PlatformWindows:
 		template = service.TemplateInitCommandWindows
+		format = models.InitCMDFormatPowershell
+	case PlatformK8sYaml:
+		return api.genInitManifestFromNode(c, ns, name, mode)
 	case PlatformAndroid:
 		return api.GenAndroidInitCmdFromNode()
+	default:
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error",
+			fmt.Sprintf("the platform should be one of %s, %s, %s, %s and %s",
+				PlatformLinuxAMD64, PlatformLinuxARM64, PlatformWindows, PlatformK8sYaml, PlatformAndroid)))
+	}
+	params["template"] = template
+	if ttl := c.Query("ttl"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil || d <= 0 || d > NodeInitTokenMaxTTL {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the ttl should be a positive duration no more than %s", NodeInitTokenMaxTTL)))
+		}
+		params["ttl"] = d
 	}
-	params := map[string]interface{}{
-		"mode":     mode,
-		"template": template,
+	if oneTime := c.Query("oneTime"); oneTime != "" {
+		b, err := strconv.ParseBool(oneTime)
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("oneTime", oneTime))
+		}
+		params["oneTime"] = b
+	}
+	withNodeToken := false
+	if nodeToken := c.Query("nodeToken"); nodeToken != "" {
+		withNodeToken, err = strconv.ParseBool(nodeToken)
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("nodeToken", nodeToken))
+		}
 	}
 	if mode == context.RunModeKube {
 		params["InitApplyYaml"] = "baetyl-init-deployment.yml"
@@ -538,12 +760,47 @@ func (api *API) GenInitCmdFromNode(c *common.Context) (interface{}, error) {
 	} else {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("mode", mode))
 	}
+	// the node token issued along replaces the former one of the node
+	if withNodeToken {
+		token, err := api.issueNodeToken(ns, name, NodeTokenDefaultTTL)
+		if err != nil {
+			return nil, err
+		}
+		params["NodeToken"] = token.Token
+	}
 
 	cmd, err := api.Init.GetResource(ns, name, service.TemplateBaetylInitCommand, params)
 	if err != nil {
 		return nil, err
 	}
-	return models.InitCMD{CMD: string(cmd.([]byte))}, nil
+	return models.InitCMD{CMD: string(cmd.([]byte)), Format: format}, nil
+}
+
+// genInitManifestFromNode generates the manifest of the init to be applied by kubectl, which carries the node certificate
+// instead of a token, so the ttl and oneTime are not supported
+func (api *API) genInitManifestFromNode(c *common.Context, ns, name, mode string) (interface{}, error) {
+	if mode != context.RunModeKube {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the platform %s only supports the mode %s", PlatformK8sYaml, context.RunModeKube)))
+	}
+	if c.Query("ttl") != "" || c.Query("oneTime") != "" {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the ttl and oneTime are not supported by the platform %s", PlatformK8sYaml)))
+	}
+	manifest, err := api.Init.GetResource(ns, name, service.TemplateInitManifest, map[string]interface{}{
+		"Mode":         mode,
+		"KubeNodeName": c.Query("node"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return models.InitCMD{CMD: string(manifest.([]byte)), Format: models.InitCMDFormatYaml}, nil
+}
+
+// GetNodeInitStatus get the status of the latest one-time init token of the node
+func (api *API) GetNodeInitStatus(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.Param("name")
+	return api.Init.GetInitTokenStatus(ns, name)
 }
 
 func (api *API) GenAndroidInitCmdFromNode() (interface{}, error) {
@@ -558,9 +815,38 @@ func (api *API) GenAndroidInitCmdFromNode() (interface{}, error) {
 	return models.InitCMD{APK: apk, APKSys: apkSys}, nil
 }
 
-// GetNodeDeployHistory list node // TODO will support later
+// GetNodeDeployHistory list the deploy records of the node by page, the latest first,
+// the versions of the app in each record can be compared by the diff of the application
 func (api *API) GetNodeDeployHistory(c *common.Context) (interface{}, error) {
-	return nil, nil
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if params.PageSize <= 0 {
+		params.PageSize = NodeDeployHistoryDefaultPageSize
+	}
+	if params.PageSize > NodeDeployHistoryMaxPageSize {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the pageSize should be no more than %d", NodeDeployHistoryMaxPageSize)))
+	}
+	if params.PageNo <= 0 {
+		params.PageNo = 1
+	}
+	if _, err := api.Node.Get(nil, ns, n); err != nil {
+		return nil, err
+	}
+	return api.Node.ListDeployHistory(ns, n, params)
+}
+
+// GetNodeDeployRecord get a deploy record of the node with the desired spec after the deploy
+func (api *API) GetNodeDeployRecord(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the id of the deploy record is invalid"))
+	}
+	return api.Node.GetDeployRecord(ns, n, id)
 }
 
 func (api *API) ParseAndCheckNode(c *common.Context) (*v1.Node, error) {
@@ -604,6 +890,8 @@ func (api *API) ParseAndCheckNodeNames(c *common.Context) (*models.NodeNames, er
 	return nodeNames, nil
 }
 
+// NodeNumberCollector counts the nodes of the namespace for the quota, the nodes under maintenance are excluded
+// if NodeMaintenance.ExcludeQuota is set
 func (api *API) NodeNumberCollector(namespace string) (map[string]int, error) {
 	return api.Node.Count(namespace)
 }
@@ -633,7 +921,192 @@ func (api *API) UpdateNodeProperties(c *common.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return api.Node.UpdateNodeProperties(ns, n, props)
+	unlock, err := api.lockNodeProperties(c, ns, n)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return api.Node.UpdateNodeProperties(ns, n, propertyActor(c), props)
+}
+
+// GetNodePropertyHistory lists the changes of the node properties, filtered by the property, the type and the time range
+func (api *API) GetNodePropertyHistory(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.NodePropertyRecordListOptions{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if params.StartTime < 0 || params.EndTime < 0 ||
+		(params.EndTime > 0 && params.StartTime > params.EndTime) {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "invalid time range"))
+	}
+	if _, err := api.Node.Get(nil, ns, n); err != nil {
+		return nil, err
+	}
+	return api.Node.ListPropertyHistory(ns, n, params)
+}
+
+// propertyActor returns the user changing the node properties
+func propertyActor(c *common.Context) string {
+	user := c.GetUser()
+	if user.ID == "" && user.Name == "" {
+		user = c.GetUserInfo().User
+	}
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.ID
+}
+
+// BatchUpdateNodeProperties merges the properties into the desired ones of the nodes matching the selector one by one,
+// the desired properties absent in the batch are kept, each node is locked while updated and the failures do not abort the others
+func (api *API) BatchUpdateNodeProperties(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	batch := new(models.NodePropertiesBatch)
+	if err := c.LoadBody(batch); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err := checkNodeProperties(&batch.Properties); err != nil {
+		return nil, err
+	}
+	nodes, err := api.Node.List(ns, &models.ListOptions{LabelSelector: batch.Selector})
+	if err != nil {
+		return nil, err
+	}
+	res := &models.NodePropertiesResultList{
+		Items: make([]models.NodePropertiesResult, 0, len(nodes.Items)),
+	}
+	for _, node := range nodes.Items {
+		item := models.NodePropertiesResult{Name: node.Name}
+		if err = api.updateNodePropertiesWithLock(c, ns, node.Name, &batch.Properties); err != nil {
+			item.Code, item.Message = common.ErrUnknown, err.Error()
+			if e, ok := err.(errors.Coder); ok {
+				item.Code = e.Code()
+			}
+			res.Failed++
+		} else {
+			item.Updated = true
+		}
+		res.Items = append(res.Items, item)
+	}
+	res.Total = len(res.Items)
+	return res, nil
+}
+
+// BatchUpdateNodeLabels adds and removes the labels of the nodes matching the selector,
+// each node relabeled is updated once so that the apps selecting it are synced once
+func (api *API) BatchUpdateNodeLabels(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	batch := new(models.NodeLabelsBatch)
+	if err := c.LoadBody(batch); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err := checkNodeLabelsBatch(batch); err != nil {
+		return nil, err
+	}
+	nodes, err := api.Node.List(ns, &models.ListOptions{LabelSelector: batch.Selector})
+	if err != nil {
+		return nil, err
+	}
+	res := &models.NodeLabelsResultList{
+		Items: make([]models.NodeLabelsResult, 0, len(nodes.Items)),
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		item := models.NodeLabelsResult{Name: node.Name}
+		if labels, changed := relabelNode(node.Labels, batch); changed {
+			node.Labels = labels
+			if _, err = api.Node.Update(ns, node); err != nil {
+				item.Code, item.Message = common.ErrUnknown, err.Error()
+				if e, ok := err.(errors.Coder); ok {
+					item.Code = e.Code()
+				}
+				res.Failed++
+			} else {
+				item.Updated = true
+			}
+		}
+		res.Items = append(res.Items, item)
+	}
+	res.Total = len(res.Items)
+	return res, nil
+}
+
+func checkNodeLabelsBatch(batch *models.NodeLabelsBatch) error {
+	if len(batch.Add) == 0 && len(batch.Remove) == 0 {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "no labels to add or remove"))
+	}
+	for _, k := range batch.Remove {
+		if _, ok := batch.Add[k]; ok {
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the label (%s) can't be added and removed at once", k)))
+		}
+	}
+	for _, k := range batch.Remove {
+		if nodeSystemLabels[k] {
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the system label (%s) can't be changed", k)))
+		}
+	}
+	for k := range batch.Add {
+		if nodeSystemLabels[k] {
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the system label (%s) can't be changed", k)))
+		}
+	}
+	return nil
+}
+
+// relabelNode returns the labels of the node after the batch is applied, and whether they are changed
+func relabelNode(labels map[string]string, batch *models.NodeLabelsBatch) (map[string]string, bool) {
+	res := make(map[string]string, len(labels)+len(batch.Add))
+	for k, v := range labels {
+		res[k] = v
+	}
+	changed := false
+	for k, v := range batch.Add {
+		if old, ok := res[k]; !ok || old != v {
+			res[k] = v
+			changed = true
+		}
+	}
+	for _, k := range batch.Remove {
+		if _, ok := res[k]; ok {
+			delete(res, k)
+			changed = true
+		}
+	}
+	return res, changed
+}
+
+// lockNodeProperties locks the properties of the node, both the single and the batch updates take the lock
+func (api *API) lockNodeProperties(c *common.Context, ns, name string) (func(), error) {
+	ctx := c.Request.Context()
+	lockName := fmt.Sprintf("node_%s_%s", ns, name)
+	version, err := api.Locker.Lock(ctx, lockName, 0)
+	if err != nil {
+		return nil, err
+	}
+	return func() { api.Locker.Unlock(ctx, lockName, version) }, nil
+}
+
+func (api *API) updateNodePropertiesWithLock(c *common.Context, ns, name string, props *models.NodeProperties) error {
+	unlock, err := api.lockNodeProperties(c, ns, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	current, err := api.Node.GetNodeProperties(ns, name)
+	if err != nil {
+		return err
+	}
+	desire := make(map[string]interface{}, len(current.State.Desire)+len(props.State.Desire))
+	for k, v := range current.State.Desire {
+		desire[k] = v
+	}
+	for k, v := range props.State.Desire {
+		desire[k] = v
+	}
+	merged := &models.NodeProperties{State: models.NodePropertiesState{Desire: desire}}
+	_, err = api.Node.UpdateNodeProperties(ns, name, propertyActor(c), merged)
+	return err
 }
 
 func (api *API) UpdateNodeMode(c *common.Context) (interface{}, error) {
@@ -649,6 +1122,48 @@ func (api *API) UpdateNodeMode(c *common.Context) (interface{}, error) {
 	return nil, nil
 }
 
+// GetNodeTags get the tags of the node
+func (api *API) GetNodeTags(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	node, err := api.Node.Get(nil, ns, n)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NodeTags{Tags: common.GetNodeTags(node.Annotations)}, nil
+}
+
+// UpdateNodeTags replace the tags of the node, which are descriptive and not used to select the node
+func (api *API) UpdateNodeTags(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	tags := &models.NodeTags{}
+	if err := c.LoadBody(tags); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err := checkNodeTags(tags.Tags); err != nil {
+		return nil, err
+	}
+	res, err := api.Node.UpdateNodeTags(ns, n, tags.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NodeTags{Tags: res}, nil
+}
+
+func checkNodeTags(tags map[string]string) error {
+	if len(tags) > NodeTagsMaxCount {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the number of tags should not exceed %d", NodeTagsMaxCount)))
+	}
+	for k, v := range tags {
+		if k == "" || len(k) > NodeTagKeyMaxLength {
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the length of the tag key (%s) should be between 1 and %d", k, NodeTagKeyMaxLength)))
+		}
+		if len(v) > NodeTagValueMaxLength {
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the length of the value of the tag (%s) should not exceed %d", k, NodeTagValueMaxLength)))
+		}
+	}
+	return nil
+}
+
 func (api *API) ParseAndCheckNodeMode(c *common.Context) (*models.NodeMode, error) {
 	nodeMode := &models.NodeMode{}
 	err := c.LoadBody(nodeMode)
@@ -667,12 +1182,19 @@ func (api *API) ParseAndCheckProperties(c *common.Context) (*models.NodeProperti
 	if err != nil {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
 	}
+	if err = checkNodeProperties(props); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+func checkNodeProperties(props *models.NodeProperties) error {
 	for _, v := range props.State.Desire {
 		if _, ok := v.(string); !ok {
-			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("value", "desire value should be string"))
+			return common.Error(common.ErrRequestParamInvalid, common.Field("value", "desire value should be string"))
 		}
 	}
-	return props, nil
+	return nil
 }
 
 func (api *API) UpdateCoreApp(c *common.Context) (interface{}, error) {
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// DispatchNodeCommand queue a command for the node to pick up on its next sync
+func (api *API) DispatchNodeCommand(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	command := new(models.NodeCommand)
+	if err := c.LoadBody(command); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if !models.ValidNodeCommand(command.Type) {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the command type (%s) is not supported", command.Type)))
+	}
+	if _, err := api.Node.Get(nil, ns, n); err != nil {
+		return nil, err
+	}
+	command.ID = 0
+	command.Namespace = ns
+	command.Node = n
+	return api.NodeCmd.Dispatch(command)
+}
+
+// ListNodeCommand list the commands of the node and their status
+func (api *API) ListNodeCommand(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.Node.Get(nil, ns, n); err != nil {
+		return nil, err
+	}
+	return api.NodeCmd.List(ns, n, params)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestNodeCommand(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	sCmd := ms.NewMockNodeCommandService(mockCtl)
+	api := &API{Node: sNode, NodeCmd: sCmd}
+
+	router := gin.New()
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	router.POST("/v1/nodes/:name/commands", mockIM, common.Wrapper(api.DispatchNodeCommand))
+	router.GET("/v1/nodes/:name/commands", mockIM, common.Wrapper(api.ListNodeCommand))
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// dispatch
+	sNode.EXPECT().Get(nil, "default", "node01").Return(&specV1.Node{Name: "node01"}, nil).Times(1)
+	sCmd.EXPECT().Dispatch(gomock.Any()).DoAndReturn(func(c *models.NodeCommand) (*models.NodeCommand, error) {
+		assert.Equal(t, "default", c.Namespace)
+		assert.Equal(t, "node01", c.Node)
+		assert.Equal(t, int64(0), c.ID)
+		c.ID = 1
+		c.Status = models.NodeCommandPending
+		return c, nil
+	}).Times(1)
+	w := do(http.MethodPost, "/v1/nodes/node01/commands", &models.NodeCommand{ID: 5, Type: models.NodeCommandRestartCore})
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := new(models.NodeCommand)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, int64(1), res.ID)
+	assert.Equal(t, models.NodeCommandPending, res.Status)
+
+	// unsupported type
+	w = do(http.MethodPost, "/v1/nodes/node01/commands", &models.NodeCommand{Type: "shutdown"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the command type (shutdown) is not supported")
+
+	// the node doesn't exist in the namespace
+	sNode.EXPECT().Get(nil, "default", "node02").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	w = do(http.MethodPost, "/v1/nodes/node02/commands", &models.NodeCommand{Type: models.NodeCommandReboot})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// rate limited
+	sNode.EXPECT().Get(nil, "default", "node01").Return(&specV1.Node{Name: "node01"}, nil).Times(1)
+	sCmd.EXPECT().Dispatch(gomock.Any()).Return(nil, common.Error(common.ErrTooManyRequests)).Times(1)
+	w = do(http.MethodPost, "/v1/nodes/node01/commands", &models.NodeCommand{Type: models.NodeCommandReboot})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// list
+	sNode.EXPECT().Get(nil, "default", "node01").Return(&specV1.Node{Name: "node01"}, nil).Times(1)
+	sCmd.EXPECT().List("default", "node01", &models.Filter{PageNo: 1, PageSize: 10}).Return(&models.NodeCommandList{
+		Total: 1,
+		Items: []models.NodeCommand{{ID: 1, Type: models.NodeCommandRestartCore, Status: models.NodeCommandSucceeded}},
+	}, nil).Times(1)
+	w = do(http.MethodGet, "/v1/nodes/node01/commands?pageNo=1&pageSize=10", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	list := new(models.NodeCommandList)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), list))
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, models.NodeCommandSucceeded, list.Items[0].Status)
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// DrainNode marks the node unschedulable and removes its non-system apps, so it can be deleted without orphaned workloads.
+// The apps deployed to the node only are migrated to the nodes of the group if given, by setting the selector of the group
+// to them, which is confirmed like the other selector changes. The apps deployed to the other nodes too are kept on them.
+// The migrations are checked before the node is drained, and the ones failed are kept in the drain state.
+// The node is draining until it reports that the apps are stopped, which is skipped with force=true
+func (api *API) DrainNode(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := new(models.NodeDrainParams)
+	if err := c.ShouldBindQuery(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	node, err := api.Node.Get(nil, ns, n)
+	if err != nil {
+		return nil, err
+	}
+	var group *models.NodeGroup
+	if params.Group != "" {
+		if group, err = api.NodeGroup.Get(ns, params.Group); err != nil {
+			return nil, err
+		}
+	}
+
+	drain := models.GetNodeDrain(node)
+	if drain != nil {
+		// the node is drained already, only the force option is taken
+		if params.Force && !drain.Force {
+			drain.Force = true
+			if err = api.setNodeDrain(ns, node, drain); err != nil {
+				return nil, err
+			}
+		}
+		return nodeDrainView(node, drain), nil
+	}
+
+	drain = &models.NodeDrain{
+		State:     models.NodeDrainDraining,
+		Force:     params.Force,
+		StartTime: time.Now().UTC(),
+	}
+	var migrations []*v1.Application
+	if group != nil {
+		drain.Group = group.Name
+		if migrations, drain.Skipped, err = api.planNodeDrainMigrations(c, ns, node, group); err != nil {
+			return nil, err
+		}
+	}
+	// the non-system apps are removed from the desire of the unschedulable node
+	if err = api.setNodeDrain(ns, node, drain); err != nil {
+		return nil, err
+	}
+
+	for _, app := range migrations {
+		if _, err = api.deployAppToNodeGroup(ns, group, app); err != nil {
+			log.L().Error("failed to migrate the app of the drained node", log.Any("namespace", ns),
+				log.Any("node", n), log.Any("app", app.Name), log.Any("group", group.Name), log.Error(err))
+			drain.Failed = append(drain.Failed, models.NodeDrainFailure{App: app.Name, Message: err.Error()})
+			continue
+		}
+		drain.Migrated = append(drain.Migrated, app.Name)
+	}
+	if len(drain.Migrated) > 0 || len(drain.Failed) > 0 {
+		if err = api.setNodeDrain(ns, node, drain); err != nil {
+			return nil, err
+		}
+	}
+	return nodeDrainView(node, drain), nil
+}
+
+// planNodeDrainMigrations returns the non-system apps of the node to migrate to the group, which are deployed to the node only,
+// and the skipped ones deployed to the other schedulable nodes too. The selector of the group is checked by checkAppSelector
+func (api *API) planNodeDrainMigrations(c *common.Context, ns string, node *v1.Node, group *models.NodeGroup) ([]*v1.Application, []string, error) {
+	var migrations []*v1.Application
+	var skipped []string
+	for _, info := range node.Desire.AppInfos(false) {
+		app, err := api.App.Get(ns, info.Name, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		if common.ValidIsInvisible(app.Labels) {
+			continue
+		}
+		others, err := api.appTargetsOtherNodes(ns, node.Name, app)
+		if err != nil {
+			return nil, nil, err
+		}
+		if others {
+			skipped = append(skipped, app.Name)
+			continue
+		}
+		target := *app
+		target.Selector = group.Selector
+		if _, err = api.checkAppSelector(c, ns, &target, app); err != nil {
+			return nil, nil, err
+		}
+		migrations = append(migrations, app)
+	}
+	return migrations, skipped, nil
+}
+
+// appTargetsOtherNodes returns whether the selector of the app matches the schedulable nodes other than the node
+func (api *API) appTargetsOtherNodes(ns, name string, app *v1.Application) (bool, error) {
+	if app.Selector == "" {
+		return false, nil
+	}
+	nodes, err := api.Node.List(ns, &models.ListOptions{LabelSelector: app.Selector})
+	if err != nil {
+		return false, err
+	}
+	for i := range nodes.Items {
+		if nodes.Items[i].Name != name && !models.IsNodeUnschedulable(&nodes.Items[i]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UndrainNode marks the node schedulable again, the apps matching its labels are deployed to it
+func (api *API) UndrainNode(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	node, err := api.Node.Get(nil, ns, n)
+	if err != nil {
+		return nil, err
+	}
+	if models.GetNodeDrain(node) == nil {
+		return nil, nil
+	}
+	return nil, api.setNodeDrain(ns, node, nil)
+}
+
+// setNodeDrain keeps the drain state in the attributes of the node and rematches the apps of the node
+func (api *API) setNodeDrain(ns string, node *v1.Node, drain *models.NodeDrain) error {
+	if node.Attributes == nil {
+		node.Attributes = map[string]interface{}{}
+	}
+	if drain == nil {
+		delete(node.Attributes, common.AttributeNodeDrain)
+	} else {
+		node.Attributes[common.AttributeNodeDrain] = drain
+	}
+	_, err := api.Node.Update(ns, node)
+	return err
+}
+
+// nodeDrainView returns the drain state of the node, the draining node is drained once it reports
+// that the non-system apps are stopped, the apps still running are listed as pending
+func nodeDrainView(node *v1.Node, drain *models.NodeDrain) *models.NodeDrain {
+	if drain == nil || drain.State != models.NodeDrainDraining {
+		return drain
+	}
+	if drain.Force {
+		drain.State = models.NodeDrainDrained
+		return drain
+	}
+	drain.Pending = nil
+	for _, info := range node.Report.AppInfos(false) {
+		drain.Pending = append(drain.Pending, info.Name)
+	}
+	if len(drain.Pending) == 0 {
+		drain.State = models.NodeDrainDrained
+	}
+	return drain
+}
+
+// checkNodeDrained rejects deleting the node which is still draining
+func checkNodeDrained(node *v1.Node) error {
+	drain := nodeDrainView(node, models.GetNodeDrain(node))
+	if drain == nil || drain.State != models.NodeDrainDraining {
+		return nil
+	}
+	return common.Error(common.ErrRequestParamInvalid, common.Field("error",
+		fmt.Sprintf("the node (%s) is draining, wait for the apps (%v) to stop or drain it with force=true", node.Name, drain.Pending)))
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestDrainNode(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	sGroup := ms.NewMockNodeGroupService(mockCtl)
+	sApp := ms.NewMockApplicationService(mockCtl)
+	fa := mf.NewMockFacade(mockCtl)
+	api := &API{Node: sNode, NodeGroup: sGroup, Facade: fa, AppCombinedService: &service.AppCombinedService{App: sApp}}
+
+	router := gin.New()
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	router.POST("/v1/nodes/:name/drain", mockIM, common.Wrapper(api.DrainNode))
+	router.DELETE("/v1/nodes/:name/drain", mockIM, common.Wrapper(api.UndrainNode))
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	newNode := func() *specV1.Node {
+		return &specV1.Node{
+			Name:      "node01",
+			Namespace: "default",
+			Desire: specV1.Desire{
+				common.DesiredApplications: []specV1.AppInfo{{Name: "app01", Version: "1"}},
+			},
+			Report: specV1.Report{
+				"apps": []interface{}{map[string]interface{}{"name": "app01", "version": "1"}},
+			},
+		}
+	}
+
+	// the node is draining until the apps are stopped
+	node := newNode()
+	sNode.EXPECT().Get(nil, "default", "node01").Return(node, nil).Times(1)
+	sNode.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, n *specV1.Node) (*specV1.Node, error) {
+		assert.True(t, models.IsNodeUnschedulable(n))
+		return n, nil
+	}).Times(1)
+	w := do(http.MethodPost, "/v1/nodes/node01/drain")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"state":"draining"`)
+	assert.Contains(t, w.Body.String(), `"pending":["app01"]`)
+
+	err := checkNodeDrained(node)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the node (node01) is draining")
+	node.Report = specV1.Report{}
+	assert.NoError(t, checkNodeDrained(node))
+	assert.Equal(t, models.NodeDrainDrained, nodeDrainView(node, models.GetNodeDrain(node)).State)
+
+	// the apps are migrated to the group, and the node is drained at once if forced
+	node = newNode()
+	group := &models.NodeGroup{Name: "group01", Namespace: "default", Selector: "region=bj"}
+	app := &specV1.Application{Name: "app01", Namespace: "default", Selector: "name=node01"}
+	sNode.EXPECT().Get(nil, "default", "node01").Return(node, nil).Times(1)
+	sGroup.EXPECT().Get("default", "group01").Return(group, nil).Times(1)
+	sNode.EXPECT().Update("default", node).Return(node, nil).Times(2)
+	sApp.EXPECT().Get("default", "app01", "").Return(app, nil).Times(1)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "name=node01"}).Return(&models.NodeList{Items: []specV1.Node{*node}}, nil).Times(1)
+	fa.EXPECT().UpdateApp("default", app, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _ *specV1.Application, newApp *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "region=bj", newApp.Selector)
+			return newApp, nil
+		}).Times(1)
+	w = do(http.MethodPost, "/v1/nodes/node01/drain?force=true&group=group01")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"state":"drained"`)
+	assert.Contains(t, w.Body.String(), `"migrated":["app01"]`)
+	assert.NoError(t, checkNodeDrained(node))
+
+	// the app deployed to the other nodes too is kept on them, the failed migration is reported
+	node = newNode()
+	node.Desire[common.DesiredApplications] = []specV1.AppInfo{{Name: "app01", Version: "1"}, {Name: "app02", Version: "1"}}
+	app = &specV1.Application{Name: "app01", Namespace: "default", Selector: "env=prod"}
+	app2 := &specV1.Application{Name: "app02", Namespace: "default", Selector: "name=node01"}
+	sNode.EXPECT().Get(nil, "default", "node01").Return(node, nil).Times(1)
+	sGroup.EXPECT().Get("default", "group01").Return(group, nil).Times(1)
+	sApp.EXPECT().Get("default", "app01", "").Return(app, nil).Times(1)
+	sApp.EXPECT().Get("default", "app02", "").Return(app2, nil).Times(1)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "env=prod"}).Return(&models.NodeList{Items: []specV1.Node{*node, {Name: "node02"}}}, nil).Times(1)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "name=node01"}).Return(&models.NodeList{Items: []specV1.Node{*node}}, nil).Times(1)
+	sNode.EXPECT().Update("default", node).Return(node, nil).Times(2)
+	fa.EXPECT().UpdateApp("default", app2, gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("the canary is in progress")).Times(1)
+	w = do(http.MethodPost, "/v1/nodes/node01/drain?force=true&group=group01")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"skipped":["app01"]`)
+	assert.Contains(t, w.Body.String(), `"failed":[{"app":"app02","message":"the canary is in progress"}]`)
+	assert.NotContains(t, w.Body.String(), `"migrated"`)
+	drain := models.GetNodeDrain(node)
+	assert.Equal(t, []models.NodeDrainFailure{{App: "app02", Message: "the canary is in progress"}}, drain.Failed)
+
+	// the selector of the group matching too many nodes is confirmed before the node is drained
+	api.appSelectorConfirmThreshold = 1
+	node = newNode()
+	app = &specV1.Application{Name: "app01", Namespace: "default", Selector: "name=node01"}
+	sNode.EXPECT().Get(nil, "default", "node01").Return(node, nil).Times(1)
+	sGroup.EXPECT().Get("default", "group01").Return(group, nil).Times(1)
+	sApp.EXPECT().Get("default", "app01", "").Return(app, nil).Times(1)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "name=node01"}).Return(&models.NodeList{Items: []specV1.Node{*node}}, nil).Times(1)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "region=bj"}).Return(&models.NodeList{Items: []specV1.Node{{Name: "node02"}, {Name: "node03"}}}, nil).Times(1)
+	w = do(http.MethodPost, "/v1/nodes/node01/drain?group=group01")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "set confirm=true")
+	assert.False(t, models.IsNodeUnschedulable(node))
+	api.appSelectorConfirmThreshold = 0
+
+	// the drain state loaded from the storage is decoded
+	node = newNode()
+	node.Attributes = map[string]interface{}{
+		common.AttributeNodeDrain: map[string]interface{}{"state": "draining", "startTime": "2022-01-01T00:00:00Z"},
+	}
+	sNode.EXPECT().Get(nil, "default", "node01").Return(node, nil).Times(1)
+	sNode.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, n *specV1.Node) (*specV1.Node, error) {
+		assert.False(t, models.IsNodeUnschedulable(n))
+		return n, nil
+	}).Times(1)
+	w = do(http.MethodDelete, "/v1/nodes/node01/drain")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetNodeGroup get a node group
+func (api *API) GetNodeGroup(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	group, err := api.NodeGroup.Get(ns, n)
+	if err = checkResourceFound(group, err, common.NodeGroup, n); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// ListNodeGroup list node groups
+func (api *API) ListNodeGroup(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	params, err := api.ParseListOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	return api.NodeGroup.List(ns, params)
+}
+
+// CreateNodeGroup create a node group
+func (api *API) CreateNodeGroup(c *common.Context) (interface{}, error) {
+	group, err := api.parseAndCheckNodeGroup(c)
+	if err != nil {
+		return nil, err
+	}
+	ns := c.GetNamespace()
+	group.Namespace = ns
+
+	old, err := api.NodeGroup.Get(ns, group.Name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return nil, err
+		}
+	}
+	if old != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
+	}
+	return api.NodeGroup.Create(group)
+}
+
+// UpdateNodeGroup update the selector, labels and description of a node group, the apps deployed to the group
+// whose selectors differ from the group's are redeployed on every update, so that the apps failed to redeploy
+// by the last update are redeployed by the retry. All apps are tried and the first failure is returned
+func (api *API) UpdateNodeGroup(c *common.Context) (interface{}, error) {
+	group, err := api.parseAndCheckNodeGroup(c)
+	if err != nil {
+		return nil, err
+	}
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	old, err := api.NodeGroup.Get(ns, n)
+	if err != nil {
+		return nil, err
+	}
+	old.Selector = group.Selector
+	old.Labels = group.Labels
+	old.Description = group.Description
+	old.UpdateTimestamp = time.Now()
+	res, err := api.NodeGroup.Update(old)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := api.listNodeGroupApps(ns, n)
+	if err != nil {
+		return nil, err
+	}
+	var failed error
+	for _, app := range apps {
+		if app.Selector == old.Selector {
+			continue
+		}
+		if _, err = api.deployAppToNodeGroup(ns, old, app); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	if failed != nil {
+		return nil, failed
+	}
+	return res, nil
+}
+
+// DeleteNodeGroup delete a node group, the apps deployed to the group are kept with the selector last resolved
+func (api *API) DeleteNodeGroup(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	_, err := api.NodeGroup.Get(ns, n)
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	apps, err := api.listNodeGroupApps(ns, n)
+	if err != nil {
+		return nil, err
+	}
+	for _, oldApp := range apps {
+		app := *oldApp
+		app.Labels = map[string]string{}
+		for k, v := range oldApp.Labels {
+			if k != common.LabelNodeGroup {
+				app.Labels[k] = v
+			}
+		}
+		configs, err := api.checkAppDependencies(ns, &app, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = api.Facade.UpdateApp(ns, oldApp, &app, configs); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return nil, api.NodeGroup.Delete(ns, n)
+}
+
+// listNodeGroupApps returns the apps deployed to the node group
+func (api *API) listNodeGroupApps(ns, name string) ([]*v1.Application, error) {
+	list, err := api.App.List(ns, &models.ListOptions{LabelSelector: common.LabelNodeGroup + "=" + name})
+	if err != nil {
+		return nil, err
+	}
+	var apps []*v1.Application
+	for _, item := range list.Items {
+		app, err := api.App.Get(ns, item.Name, "")
+		if err != nil {
+			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				continue
+			}
+			return nil, err
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// ListNodeGroupNodes list the nodes matching the selector of the node group
+func (api *API) ListNodeGroupNodes(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	group, err := api.NodeGroup.Get(ns, n)
+	if err != nil {
+		return nil, err
+	}
+	params, err := api.ParseListOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	if params.LabelSelector == "" {
+		params.LabelSelector = group.Selector
+	} else {
+		params.LabelSelector = group.Selector + "," + params.LabelSelector
+	}
+	if _, err = common.ParseLabelSelector(params.LabelSelector); err != nil {
+		return nil, err
+	}
+	nodeList, err := api.Node.List(ns, params)
+	if err != nil {
+		return nil, err
+	}
+	res := models.NodeViewList{
+		Total:       nodeList.Total,
+		ListOptions: nodeList.ListOptions,
+		Items:       make([]v1.NodeView, 0, len(nodeList.Items)),
+	}
+	for i := range nodeList.Items {
+		view, err := api.ToNodeView(&nodeList.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		view.Desire = nil
+		res.Items = append(res.Items, *view)
+	}
+	return res, nil
+}
+
+// DeployNodeGroupApp deploy the app to all nodes of the group, the app references the group by LabelNodeGroup
+// and its selector is resolved from the group, so the nodes joining the group later get the app as well,
+// and the selector follows the updates of the group
+func (api *API) DeployNodeGroupApp(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.NodeGroupApp{}
+	if err := c.LoadBody(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	group, err := api.NodeGroup.Get(ns, n)
+	if err != nil {
+		return nil, err
+	}
+
+	oldApp, err := api.App.Get(ns, params.Name, "")
+	if err != nil {
+		return nil, err
+	}
+	res, err := api.deployAppToNodeGroup(ns, group, oldApp)
+	if err != nil {
+		return nil, err
+	}
+	return api.ToApplicationView(res)
+}
+
+func (api *API) deployAppToNodeGroup(ns string, group *models.NodeGroup, oldApp *v1.Application) (*v1.Application, error) {
+	// sys app: core、init、function is not visible
+	if common.ValidIsInvisible(oldApp.Labels) {
+		return nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", oldApp.Name))
+	}
+	if oldApp.Selector == group.Selector && oldApp.Labels[common.LabelNodeGroup] == group.Name {
+		return oldApp, nil
+	}
+
+	// only the selector and labels are changed, a shallow copy is enough along with a copy of the labels
+	app := *oldApp
+	app.Labels = map[string]string{}
+	for k, v := range oldApp.Labels {
+		app.Labels[k] = v
+	}
+	app.Labels[common.LabelNodeGroup] = group.Name
+	app.Selector = group.Selector
+	configs, err := api.checkAppDependencies(ns, &app, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := api.Facade.UpdateApp(ns, oldApp, &app, configs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return res, nil
+}
+
+func (api *API) parseAndCheckNodeGroup(c *common.Context) (*models.NodeGroup, error) {
+	group := new(models.NodeGroup)
+	if err := c.LoadBody(group); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if name := c.GetNameFromParam(); name != "" {
+		group.Name = name
+	}
+	if group.Name == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+	}
+	if _, err := common.ParseLabelSelector(group.Selector); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
@@ -0,0 +1,370 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initNodeGroupAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L()}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		groups := v1.Group("/nodegroups")
+		groups.GET("/:name", mockIM, common.Wrapper(api.GetNodeGroup))
+		groups.PUT("/:name", mockIM, common.Wrapper(api.UpdateNodeGroup))
+		groups.DELETE("/:name", mockIM, common.Wrapper(api.DeleteNodeGroup))
+		groups.POST("", mockIM, common.Wrapper(api.CreateNodeGroup))
+		groups.GET("", mockIM, common.Wrapper(api.ListNodeGroup))
+		groups.GET("/:name/nodes", mockIM, common.Wrapper(api.ListNodeGroupNodes))
+		groups.POST("/:name/apps", mockIM, common.Wrapper(api.DeployNodeGroupApp))
+	}
+	return api, router, mockCtl
+}
+
+func TestCreateNodeGroup(t *testing.T) {
+	api, router, mockCtl := initNodeGroupAPI(t)
+	defer mockCtl.Finish()
+	sGroup := ms.NewMockNodeGroupService(mockCtl)
+	api.NodeGroup = sGroup
+
+	group := &models.NodeGroup{
+		Name:     "group01",
+		Selector: "region=bj",
+	}
+	expect := &models.NodeGroup{
+		Name:      "group01",
+		Namespace: "default",
+		Selector:  "region=bj",
+	}
+	sGroup.EXPECT().Get("default", "group01").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	sGroup.EXPECT().Create(gomock.Any()).DoAndReturn(func(group *models.NodeGroup) (*models.NodeGroup, error) {
+		assert.Equal(t, expect.Name, group.Name)
+		assert.Equal(t, expect.Namespace, group.Namespace)
+		assert.Equal(t, expect.Selector, group.Selector)
+		return expect, nil
+	}).Times(1)
+	body, _ := json.Marshal(group)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/nodegroups", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// name in use
+	sGroup.EXPECT().Get("default", "group01").Return(expect, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodegroups", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// invalid selector
+	body, _ = json.Marshal(&models.NodeGroup{Name: "group02", Selector: "region in (bj"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodegroups", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// selector is required
+	body, _ = json.Marshal(&models.NodeGroup{Name: "group02"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodegroups", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetAndListNodeGroup(t *testing.T) {
+	api, router, mockCtl := initNodeGroupAPI(t)
+	defer mockCtl.Finish()
+	sGroup := ms.NewMockNodeGroupService(mockCtl)
+	api.NodeGroup = sGroup
+
+	group := &models.NodeGroup{
+		Name:      "group01",
+		Namespace: "default",
+		Selector:  "region=bj",
+	}
+	sGroup.EXPECT().Get("default", "group01").Return(group, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodegroups/group01", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sGroup.EXPECT().Get("default", "group02").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodegroups/group02", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "The (nodegroup) resource (group02) is not found.")
+
+	// the missing group is not responded with null
+	sGroup.EXPECT().Get("default", "group03").Return(nil, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodegroups/group03", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "The (nodegroup) resource (group03) is not found.")
+
+	list := &models.NodeGroupList{Total: 1, Items: []models.NodeGroup{*group}}
+	sGroup.EXPECT().List("default", gomock.Any()).Return(list, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodegroups", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.NodeGroupList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "group01", res.Items[0].Name)
+}
+
+func TestUpdateAndDeleteNodeGroup(t *testing.T) {
+	api, router, mockCtl := initNodeGroupAPI(t)
+	defer mockCtl.Finish()
+	sGroup := ms.NewMockNodeGroupService(mockCtl)
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	fa := mf.NewMockFacade(mockCtl)
+	api.NodeGroup = sGroup
+	api.Facade = fa
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Secret: sSecret}
+
+	old := &models.NodeGroup{
+		Name:      "group01",
+		Namespace: "default",
+		Selector:  "region=bj",
+	}
+	deployed := &specV1.Application{
+		Name:      "app01",
+		Namespace: "default",
+		Selector:  "region=bj",
+		Labels:    map[string]string{"a": "b", common.LabelNodeGroup: "group01"},
+	}
+	groupApps := &models.ListOptions{LabelSelector: common.LabelNodeGroup + "=group01"}
+
+	// the selectors of the apps deployed to the group are resolved again
+	sGroup.EXPECT().Get("default", "group01").Return(old, nil).Times(1)
+	sGroup.EXPECT().Update(gomock.Any()).DoAndReturn(func(group *models.NodeGroup) (*models.NodeGroup, error) {
+		assert.Equal(t, "region in (bj, sh)", group.Selector)
+		assert.Equal(t, "desc", group.Description)
+		return group, nil
+	}).Times(1)
+	sApp.EXPECT().List("default", groupApps).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "app01"}}}, nil).Times(1)
+	sApp.EXPECT().Get("default", "app01", "").Return(deployed, nil).Times(1)
+	fa.EXPECT().UpdateApp("default", deployed, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _ *specV1.Application, app *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "region in (bj, sh)", app.Selector)
+			assert.Equal(t, "group01", app.Labels[common.LabelNodeGroup])
+			return app, nil
+		}).Times(1)
+	body, _ := json.Marshal(&models.NodeGroup{Selector: "region in (bj, sh)", Description: "desc"})
+	req, _ := http.NewRequest(http.MethodPut, "/v1/nodegroups/group01", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the apps are kept as they are if their selectors are the group's
+	sGroup.EXPECT().Get("default", "group01").Return(&models.NodeGroup{Name: "group01", Selector: "region=bj"}, nil).Times(1)
+	sGroup.EXPECT().Update(gomock.Any()).DoAndReturn(func(group *models.NodeGroup) (*models.NodeGroup, error) {
+		return group, nil
+	}).Times(1)
+	sApp.EXPECT().List("default", groupApps).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "app01"}}}, nil).Times(1)
+	sApp.EXPECT().Get("default", "app01", "").Return(deployed, nil).Times(1)
+	body, _ = json.Marshal(&models.NodeGroup{Selector: "region=bj", Description: "other"})
+	req, _ = http.NewRequest(http.MethodPut, "/v1/nodegroups/group01", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the apps failed to redeploy by the last update are redeployed by the retry with the selector unchanged
+	stale := &specV1.Application{
+		Name:      "app02",
+		Namespace: "default",
+		Selector:  "region=sh",
+		Labels:    map[string]string{common.LabelNodeGroup: "group01"},
+	}
+	sGroup.EXPECT().Get("default", "group01").Return(&models.NodeGroup{Name: "group01", Selector: "region=bj"}, nil).Times(2)
+	sGroup.EXPECT().Update(gomock.Any()).DoAndReturn(func(group *models.NodeGroup) (*models.NodeGroup, error) {
+		return group, nil
+	}).Times(2)
+	sApp.EXPECT().List("default", groupApps).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "app02"}}}, nil).Times(2)
+	sApp.EXPECT().Get("default", "app02", "").Return(stale, nil).Times(2)
+	fa.EXPECT().UpdateApp("default", stale, gomock.Any(), gomock.Any()).Return(nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the canary is in progress"))).Times(1)
+	fa.EXPECT().UpdateApp("default", stale, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _ *specV1.Application, app *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "region=bj", app.Selector)
+			return app, nil
+		}).Times(1)
+	for _, code := range []int{http.StatusBadRequest, http.StatusOK} {
+		body, _ = json.Marshal(&models.NodeGroup{Selector: "region=bj"})
+		req, _ = http.NewRequest(http.MethodPut, "/v1/nodegroups/group01", bytes.NewReader(body))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, code, w.Code)
+	}
+
+	// the apps deployed to the deleted group keep the selector but no longer reference the group
+	sGroup.EXPECT().Get("default", "group01").Return(old, nil).Times(1)
+	sApp.EXPECT().List("default", groupApps).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "app01"}}}, nil).Times(1)
+	sApp.EXPECT().Get("default", "app01", "").Return(deployed, nil).Times(1)
+	fa.EXPECT().UpdateApp("default", deployed, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _ *specV1.Application, app *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "region=bj", app.Selector)
+			assert.Equal(t, map[string]string{"a": "b"}, app.Labels)
+			return app, nil
+		}).Times(1)
+	sGroup.EXPECT().Delete("default", "group01").Return(nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/nodegroups/group01", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// deleting a missing group is a no-op
+	sGroup.EXPECT().Get("default", "group02").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/nodegroups/group02", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestListNodeGroupNodes(t *testing.T) {
+	api, router, mockCtl := initNodeGroupAPI(t)
+	defer mockCtl.Finish()
+	sGroup := ms.NewMockNodeGroupService(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.NodeGroup = sGroup
+	api.Node = sNode
+
+	group := &models.NodeGroup{
+		Name:      "group01",
+		Namespace: "default",
+		Selector:  "region=bj",
+	}
+	node := specV1.Node{
+		Name:       "node01",
+		Namespace:  "default",
+		Labels:     map[string]string{"region": "bj", "zone": "a"},
+		Attributes: map[string]interface{}{specV1.BaetylCoreFrequency: "20"},
+	}
+	sGroup.EXPECT().Get("default", "group01").Return(group, nil).Times(2)
+	sNode.EXPECT().List("default", gomock.Any()).DoAndReturn(func(_ string, params *models.ListOptions) (*models.NodeList, error) {
+		assert.Equal(t, "region=bj", params.LabelSelector)
+		return &models.NodeList{Total: 1, ListOptions: params, Items: []specV1.Node{node}}, nil
+	}).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodegroups/group01/nodes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.NodeViewList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "node01", res.Items[0].Name)
+
+	// the selector of request narrows the members
+	sNode.EXPECT().List("default", gomock.Any()).DoAndReturn(func(_ string, params *models.ListOptions) (*models.NodeList, error) {
+		assert.Equal(t, "region=bj,zone=b", params.LabelSelector)
+		return &models.NodeList{ListOptions: params, Items: []specV1.Node{}}, nil
+	}).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodegroups/group01/nodes?selector=zone%3Db", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestDeployNodeGroupApp(t *testing.T) {
+	api, router, mockCtl := initNodeGroupAPI(t)
+	defer mockCtl.Finish()
+	sGroup := ms.NewMockNodeGroupService(mockCtl)
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	fa := mf.NewMockFacade(mockCtl)
+	api.NodeGroup = sGroup
+	api.Facade = fa
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Secret: sSecret,
+	}
+
+	group := &models.NodeGroup{
+		Name:      "group01",
+		Namespace: "default",
+		Selector:  "region=bj",
+	}
+	app := &specV1.Application{
+		Name:      "app01",
+		Namespace: "default",
+		Selector:  "name=node01",
+		Type:      specV1.AppTypeContainer,
+	}
+	sGroup.EXPECT().Get("default", "group01").Return(group, nil).Times(1)
+	sApp.EXPECT().Get("default", "app01", "").Return(app, nil).Times(1)
+	fa.EXPECT().UpdateApp("default", app, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _ *specV1.Application, newApp *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "region=bj", newApp.Selector)
+			assert.Equal(t, "group01", newApp.Labels[common.LabelNodeGroup])
+			return newApp, nil
+		}).Times(1)
+	sSecret.EXPECT().List("default", gomock.Any()).Return(&models.SecretList{}, nil).AnyTimes()
+	body, _ := json.Marshal(&models.NodeGroupApp{Name: "app01"})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/nodegroups/group01/apps", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.ApplicationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, "region=bj", res.Selector)
+	// the stored app is not modified in place
+	assert.Equal(t, "name=node01", app.Selector)
+	assert.Empty(t, app.Labels)
+
+	// app already deployed to the group
+	deployed := &specV1.Application{
+		Name:      "app01",
+		Namespace: "default",
+		Selector:  "region=bj",
+		Labels:    map[string]string{common.LabelNodeGroup: "group01"},
+		Type:      specV1.AppTypeContainer,
+	}
+	sGroup.EXPECT().Get("default", "group01").Return(group, nil).Times(1)
+	sApp.EXPECT().Get("default", "app01", "").Return(deployed, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodegroups/group01/apps", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// invisible app
+	invisible := &specV1.Application{
+		Name:      "baetyl-core",
+		Namespace: "default",
+		Labels:    map[string]string{common.ResourceInvisible: "true"},
+	}
+	sGroup.EXPECT().Get("default", "group01").Return(group, nil).Times(1)
+	sApp.EXPECT().Get("default", "baetyl-core", "").Return(invisible, nil).Times(1)
+	body, _ = json.Marshal(&models.NodeGroupApp{Name: "baetyl-core"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodegroups/group01/apps", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// app name is required
+	body, _ = json.Marshal(&models.NodeGroupApp{})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodegroups/group01/apps", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
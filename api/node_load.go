@@ -0,0 +1,66 @@
+package api
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	// defaultNodeStatsWindow the freshness window of the stats joined into the nodes if not given
+	defaultNodeStatsWindow = 5 * time.Minute
+	// maxNodeStatsWindow the max freshness window, the older stats are no longer the current load
+	maxNodeStatsWindow = time.Hour
+	// nodeLoadCPUWeight the weight of the cpu in the load of a node, the rest is the weight of the memory
+	nodeLoadCPUWeight = 0.5
+)
+
+// checkNodeStatsWindow checks the freshness window of the stats joined into the nodes, and sets the default
+func checkNodeStatsWindow(params *models.ListOptions) error {
+	if !params.IncludeStats {
+		return nil
+	}
+	if params.StatsWindow < 0 || params.StatsWindow > maxNodeStatsWindow {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "statsWindow should be a positive duration up to 1h"))
+	}
+	if params.StatsWindow == 0 {
+		params.StatsWindow = defaultNodeStatsWindow
+	}
+	return nil
+}
+
+// joinNodeStats joins the latest utilization of the nodes reported within the window, the stats are left
+// empty for the nodes without stats reported within the window
+func (api *API) joinNodeStats(ns string, list *models.NodeViewList, window time.Duration) (*models.NodeLoadViewList, error) {
+	names := make([]string, 0, len(list.Items))
+	for _, n := range list.Items {
+		names = append(names, n.Name)
+	}
+	stats, err := api.Node.ListLatestStats(ns, names, time.Now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+	res := &models.NodeLoadViewList{
+		Total:       list.Total,
+		ListOptions: list.ListOptions,
+		Items:       make([]models.NodeLoadView, 0, len(list.Items)),
+	}
+	for _, n := range list.Items {
+		res.Items = append(res.Items, models.NodeLoadView{NodeView: n, Stats: nodeLoad(stats[n.Name])})
+	}
+	return res, nil
+}
+
+func nodeLoad(point *models.NodeStatsPoint) *models.NodeLoad {
+	if point == nil {
+		return nil
+	}
+	cpu, memory := point.Percent["cpu"], point.Percent["memory"]
+	return &models.NodeLoad{
+		Time:   point.Time,
+		CPU:    cpu,
+		Memory: memory,
+		Load:   cpu*nodeLoadCPUWeight + memory*(1-nodeLoadCPUWeight),
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestListNodeWithStats(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api := &API{Node: sNode, log: log.L()}
+
+	router := gin.New()
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	router.GET("/v1/nodes", mockIM, common.Wrapper(api.ListNode))
+	do := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/v1/nodes"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	attrs := map[string]interface{}{specV1.BaetylCoreFrequency: "20"}
+	nodes := &models.NodeList{Total: 2, Items: []specV1.Node{
+		{Name: "node01", Namespace: "default", Attributes: attrs},
+		{Name: "node02", Namespace: "default", Attributes: attrs},
+	}}
+	now := time.Now().UTC().Truncate(time.Second)
+	sNode.EXPECT().List("default", gomock.Any()).Return(nodes, nil).Times(1)
+	sNode.EXPECT().ListLatestStats("default", []string{"node01", "node02"}, gomock.Any()).
+		DoAndReturn(func(_ string, _ []string, since time.Time) (map[string]*models.NodeStatsPoint, error) {
+			assert.WithinDuration(t, time.Now().Add(-time.Minute), since, 5*time.Second)
+			return map[string]*models.NodeStatsPoint{
+				"node01": {Time: now, Percent: map[string]float64{"cpu": 40, "memory": 60, "disk": 90}},
+			}, nil
+		}).Times(1)
+	w := do("?includeStats=true&statsWindow=1m")
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.NodeLoadViewList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, "node01", res.Items[0].Name)
+	assert.Equal(t, &models.NodeLoad{Time: now, CPU: 40, Memory: 60, Load: 50}, res.Items[0].Stats)
+	assert.Equal(t, "node02", res.Items[1].Name)
+	assert.Nil(t, res.Items[1].Stats)
+
+	// the stats are not joined by default
+	sNode.EXPECT().List("default", gomock.Any()).Return(nodes, nil).Times(1)
+	w = do("")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), `"stats"`)
+
+	// the default window
+	sNode.EXPECT().List("default", gomock.Any()).Return(nodes, nil).Times(1)
+	sNode.EXPECT().ListLatestStats("default", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ string, _ []string, since time.Time) (map[string]*models.NodeStatsPoint, error) {
+			assert.WithinDuration(t, time.Now().Add(-defaultNodeStatsWindow), since, 5*time.Second)
+			return nil, common.Error(common.ErrNotSupported, common.Field("operation", "node stats"))
+		}).Times(1)
+	w = do("?includeStats=true")
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	assert.Equal(t, http.StatusBadRequest, do("?includeStats=true&statsWindow=2h").Code)
+	assert.Equal(t, http.StatusBadRequest, do("?includeStats=true&statsWindow=-1m").Code)
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const nodeLogBufferSize = 32 * 1024
+
+// GetAppLogs streams the logs of the app on the node from the agent of the node by chunked transfer,
+// the stream is kept until the client disconnects if follow is true, or ends after the logs are read out
+func (api *API) GetAppLogs(c *common.Context) (interface{}, error) {
+	ns, n, app := c.GetNamespace(), c.GetNameFromParam(), c.Param("app")
+	options, err := api.parseAppLogOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	node, err := api.Node.Get(nil, ns, n)
+	if err = checkResourceFound(node, err, common.Node, n); err != nil {
+		return nil, err
+	}
+	desire, err := api.Node.GetDesire(ns, n)
+	if err != nil {
+		return nil, err
+	}
+	if !hasAppInfo(desire.AppInfos(false), app) && !hasAppInfo(desire.AppInfos(true), app) {
+		return nil, resourceNotFoundError(common.Application, app)
+	}
+
+	if !api.nodeLogStreams.acquire(ns) {
+		return nil, common.Error(common.ErrTooManyRequests,
+			common.Field("error", "the number of log streams reaches the limit"))
+	}
+	defer api.nodeLogStreams.release(ns)
+
+	stream, err := api.NodeLog.Stream(ns, n, app, options)
+	if err != nil {
+		return nil, err
+	}
+	// the stream is closed once the client disconnects to stop the pending read
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-c.Request.Context().Done():
+		case <-done:
+		}
+		stream.Close()
+	}()
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+	buf := make([]byte, nodeLogBufferSize)
+	for {
+		l, err := stream.Read(buf)
+		if l > 0 {
+			if _, werr := c.Writer.Write(buf[:l]); werr != nil {
+				return nil, nil
+			}
+			c.Writer.Flush()
+		}
+		if err != nil {
+			if c.IsShutdown() {
+				c.Writer.Write([]byte("\n" + common.ErrShutdown.Error() + "\n"))
+				c.Writer.Flush()
+				return nil, nil
+			}
+			if err != io.EOF && c.Request.Context().Err() == nil {
+				log.L().Warn("failed to read the log stream", log.Any(c.GetTrace()),
+					log.Any("node", n), log.Any("app", app), log.Error(err))
+			}
+			return nil, nil
+		}
+	}
+}
+
+func (api *API) parseAppLogOptions(c *common.Context) (*models.AppLogOptions, error) {
+	options := &models.AppLogOptions{}
+	if tail := c.Query("tail"); tail != "" {
+		t, err := strconv.Atoi(tail)
+		if err != nil || t <= 0 || (api.nodeLogMaxTail > 0 && t > api.nodeLogMaxTail) {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the tail should be a positive integer no more than %d", api.nodeLogMaxTail)))
+		}
+		options.Tail = t
+	}
+	if since := c.Query("since"); since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil || d <= 0 {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "the since should be a positive duration, e.g. 10m"))
+		}
+		options.Since = d
+	}
+	if follow := c.Query("follow"); follow != "" {
+		f, err := strconv.ParseBool(follow)
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("follow", follow))
+		}
+		options.Follow = f
+	}
+	return options, nil
+}
+
+func hasAppInfo(infos []v1.AppInfo, name string) bool {
+	for _, info := range infos {
+		if info.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initNodeLogAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{nodeLogStreams: newNamespaceLimiter(1), nodeLogMaxTail: 100}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		nodes := v1.Group("/nodes")
+		nodes.GET("/:name/apps/:app/logs", mockIM, common.WrapperNative(api.GetAppLogs, true))
+	}
+	return api, router, mockCtl
+}
+
+func TestGetAppLogs(t *testing.T) {
+	api, router, mockCtl := initNodeLogAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+	sLog := ms.NewMockNodeLogService(mockCtl)
+	api.NodeLog = sLog
+
+	getLogs := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, query := range []string{"tail=0", "tail=101", "tail=a", "since=-1m", "since=a", "follow=a"} {
+		w := getLogs("/v1/nodes/n1/apps/a1/logs?" + query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	node := &specV1.Node{Name: "n1", Namespace: "default"}
+	desire := &specV1.Desire{
+		"apps":    []specV1.AppInfo{{Name: "a1", Version: "1"}},
+		"sysapps": []specV1.AppInfo{{Name: "baetyl-core", Version: "1"}},
+	}
+	sNode.EXPECT().Get(nil, "default", "n1").Return(node, nil).AnyTimes()
+	sNode.EXPECT().GetDesire("default", "n1").Return(desire, nil).AnyTimes()
+
+	w := getLogs("/v1/nodes/n1/apps/a2/logs")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	options := &models.AppLogOptions{Tail: 10, Since: 10 * time.Minute, Follow: true}
+	sLog.EXPECT().Stream("default", "n1", "a1", options).
+		Return(io.NopCloser(strings.NewReader("line1\nline2\n")), nil).Times(1)
+	w = getLogs("/v1/nodes/n1/apps/a1/logs?tail=10&since=10m&follow=true")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "line1\nline2\n", w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+
+	sLog.EXPECT().Stream("default", "n1", "baetyl-core", &models.AppLogOptions{}).
+		Return(nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "node offline"))).Times(1)
+	w = getLogs("/v1/nodes/n1/apps/baetyl-core/logs")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the number of streams is limited per namespace
+	assert.True(t, api.nodeLogStreams.acquire("default"))
+	w = getLogs("/v1/nodes/n1/apps/a1/logs")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	api.nodeLogStreams.release("default")
+
+	// the stream is closed with a message when the server shuts down
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	sLog.EXPECT().Stream("default", "n1", "a1", &models.AppLogOptions{}).Return(pr, nil).Times(1)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(common.ErrShutdown)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/v1/nodes/n1/apps/a1/logs", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "\nthe server is shutting down\n", w.Body.String())
+}
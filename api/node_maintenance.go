@@ -0,0 +1,78 @@
+package api
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+// UpdateNodeMaintenance puts the node under maintenance or takes it out, the offline and online alerts of the node
+// under maintenance are suppressed. If NodeMaintenance.ExcludeQuota is set, the node quota is released when the node
+// is put under maintenance and acquired again when it is taken out, which is rejected if the namespace is over the quota.
+// The apps of the node are not touched
+func (api *API) UpdateNodeMaintenance(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	maintenance := new(models.NodeMaintenance)
+	if err := c.LoadBody(maintenance); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	node, err := api.Node.Get(nil, ns, n)
+	if err != nil {
+		return nil, err
+	}
+
+	old := models.GetNodeMaintenance(node)
+	var value interface{}
+	if maintenance.Maintenance {
+		if old != nil {
+			maintenance.StartTime = old.StartTime
+		} else {
+			maintenance.StartTime = time.Now().UTC()
+		}
+		value = maintenance
+	} else {
+		maintenance = &models.NodeMaintenance{}
+	}
+
+	// the quota is released before the node enters maintenance and acquired before it leaves,
+	// and the change of the quota is rolled back if the node is not updated
+	var rollback func() error
+	if api.nodeMaintenanceExcludeQuota {
+		if maintenance.Maintenance && old == nil {
+			if err = api.ReleaseQuota(ns, plugin.QuotaNode, NodeNumber); err != nil {
+				return nil, err
+			}
+			rollback = func() error { return api.Quota.AcquireQuota(ns, plugin.QuotaNode, NodeNumber) }
+		} else if !maintenance.Maintenance && old != nil {
+			if err = api.Quota.AcquireQuota(ns, plugin.QuotaNode, NodeNumber); err != nil {
+				if !api.TolerateLicense(c, err) {
+					return nil, err
+				}
+			} else {
+				rollback = func() error { return api.ReleaseQuota(ns, plugin.QuotaNode, NodeNumber) }
+			}
+		}
+	}
+	if _, err = api.Node.UpdateNodeAttributes(ns, n, map[string]interface{}{common.AttributeNodeMaintenance: value}); err != nil {
+		if rollback != nil {
+			if e := rollback(); e != nil {
+				log.L().Error("failed to roll back the node quota", log.Any("namespace", ns), log.Any("node", n), log.Error(e))
+			}
+		}
+		return nil, err
+	}
+	api.log.Info("node maintenance is updated", log.Any(c.GetTrace()), log.Any("namespace", ns),
+		log.Any("node", n), log.Any("maintenance", maintenance.Maintenance))
+	return maintenance, nil
+}
+
+// nodeHoldsQuota returns whether the node is counted in the node quota, the quota of the nodes under maintenance
+// is released if NodeMaintenance.ExcludeQuota is set
+func (api *API) nodeHoldsQuota(node *specV1.Node) bool {
+	return !api.nodeMaintenanceExcludeQuota || models.GetNodeMaintenance(node) == nil
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestUpdateNodeMaintenance(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api := &API{Node: sNode, log: log.L()}
+
+	router := gin.New()
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	router.PUT("/v1/nodes/:name/maintenance", mockIM, common.Wrapper(api.UpdateNodeMaintenance))
+	do := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPut, "/v1/nodes/node01/maintenance", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// put the node under maintenance
+	node := &specV1.Node{Name: "node01", Namespace: "default"}
+	sNode.EXPECT().Get(nil, "default", "node01").Return(node, nil).Times(1)
+	sNode.EXPECT().UpdateNodeAttributes("default", "node01", gomock.Any()).DoAndReturn(
+		func(_, _ string, attrs map[string]interface{}) (*specV1.Node, error) {
+			m, ok := attrs[common.AttributeNodeMaintenance].(*models.NodeMaintenance)
+			assert.True(t, ok)
+			assert.True(t, m.Maintenance)
+			assert.Equal(t, "upgrade", m.Message)
+			assert.False(t, m.StartTime.IsZero())
+			node.Attributes = attrs
+			return node, nil
+		}).Times(1)
+	w := do(`{"maintenance":true,"message":"upgrade"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"maintenance":true`)
+	assert.NotNil(t, models.GetNodeMaintenance(node))
+
+	// the start time is kept while the node is still under maintenance
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	node.Attributes = map[string]interface{}{common.AttributeNodeMaintenance: &models.NodeMaintenance{Maintenance: true, StartTime: start}}
+	sNode.EXPECT().Get(nil, "default", "node01").Return(node, nil).Times(1)
+	sNode.EXPECT().UpdateNodeAttributes("default", "node01", gomock.Any()).DoAndReturn(
+		func(_, _ string, attrs map[string]interface{}) (*specV1.Node, error) {
+			assert.Equal(t, start, attrs[common.AttributeNodeMaintenance].(*models.NodeMaintenance).StartTime)
+			return node, nil
+		}).Times(1)
+	w = do(`{"maintenance":true,"message":"still"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// take the node out of maintenance
+	sNode.EXPECT().Get(nil, "default", "node01").Return(node, nil).Times(1)
+	sNode.EXPECT().UpdateNodeAttributes("default", "node01", map[string]interface{}{common.AttributeNodeMaintenance: nil}).Return(node, nil).Times(1)
+	w = do(`{"maintenance":false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"maintenance":false`)
+
+	// invalid body
+	w = do(`{"maintenance":"yes"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateNodeMaintenanceQuota(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	sQuota := ms.NewMockQuotaService(mockCtl)
+	api := &API{Node: sNode, Quota: sQuota, log: log.L(), nodeMaintenanceExcludeQuota: true}
+
+	router := gin.New()
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	router.PUT("/v1/nodes/:name/maintenance", mockIM, common.Wrapper(api.UpdateNodeMaintenance))
+	do := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPut, "/v1/nodes/node01/maintenance", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	node := &specV1.Node{Name: "node01", Namespace: "default"}
+	sNode.EXPECT().Get(nil, "default", "node01").Return(node, nil).AnyTimes()
+
+	// the quota is released when the node enters maintenance, and acquired back if the node fails to be updated
+	sQuota.EXPECT().ReleaseQuota("default", plugin.QuotaNode, NodeNumber).Return(nil).Times(2)
+	sQuota.EXPECT().AcquireQuota("default", plugin.QuotaNode, NodeNumber).Return(nil).Times(1)
+	sNode.EXPECT().UpdateNodeAttributes("default", "node01", gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	w := do(`{"maintenance":true}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	sNode.EXPECT().UpdateNodeAttributes("default", "node01", gomock.Any()).Return(node, nil).Times(1)
+	w = do(`{"maintenance":true}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the quota is not released again while the node stays under maintenance
+	node.Attributes = map[string]interface{}{common.AttributeNodeMaintenance: &models.NodeMaintenance{Maintenance: true}}
+	sNode.EXPECT().UpdateNodeAttributes("default", "node01", gomock.Any()).Return(node, nil).Times(1)
+	w = do(`{"maintenance":true}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, api.nodeHoldsQuota(node))
+
+	// the node stays under maintenance if the namespace is over the quota
+	sQuota.EXPECT().AcquireQuota("default", plugin.QuotaNode, NodeNumber).Return(common.Error(common.ErrLicenseQuota, common.Field("name", plugin.QuotaNode), common.Field("limit", 1))).Times(1)
+	w = do(`{"maintenance":false}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the quota is acquired when the node leaves maintenance
+	sQuota.EXPECT().AcquireQuota("default", plugin.QuotaNode, NodeNumber).Return(nil).Times(1)
+	sNode.EXPECT().UpdateNodeAttributes("default", "node01", map[string]interface{}{common.AttributeNodeMaintenance: nil}).Return(node, nil).Times(1)
+	w = do(`{"maintenance":false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/gin-gonic/gin/binding"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// the content types of the patches of the nodes, the merge patch is taken by default
+const (
+	ContentTypeMergePatch          = "application/merge-patch+json"
+	ContentTypeStrategicMergePatch = "application/strategic-merge-patch+json"
+)
+
+// PatchNode updates the fields of the node given in the patch only, the patch is a json merge patch (RFC 7386),
+// or a strategic merge patch if the content type is application/strategic-merge-patch+json.
+// The patched node is updated as UpdateNode does, and the name and the create time can't be changed
+func (api *API) PatchNode(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	patch, err := c.GetRawData()
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	oldNode, err := api.Node.Get(nil, ns, n)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkResourceVersion(c, common.Node, oldNode.Name, oldNode.Version); err != nil {
+		return nil, err
+	}
+	node, err := patchNode(oldNode, patch, c.ContentType())
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if node.Name != oldNode.Name || node.Namespace != oldNode.Namespace {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the name and the namespace of the node can't be changed"))
+	}
+	if !node.CreationTimestamp.Equal(oldNode.CreationTimestamp) {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the create time of the node can't be changed"))
+	}
+	if err = binding.Validator.ValidateStruct(node); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err = api.NodeModeParamCheck(node); err != nil {
+		return nil, err
+	}
+	if err = api.CheckNodeOptionalSysApps(node.SysApps, node.NodeMode); err != nil {
+		return nil, err
+	}
+	return api.updateNode(c, oldNode, node)
+}
+
+// patchNode applies the patch to a copy of the node
+func patchNode(node *v1.Node, patch []byte, contentType string) (*v1.Node, error) {
+	original, err := json.Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+	var patched []byte
+	switch strings.ToLower(contentType) {
+	case ContentTypeStrategicMergePatch:
+		patched, err = strategicpatch.StrategicMergePatch(original, patch, v1.Node{})
+	default:
+		patched, err = jsonpatch.MergePatch(original, patch)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := new(v1.Node)
+	if err = json.Unmarshal(patched, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
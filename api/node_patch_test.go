@@ -0,0 +1,102 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/context"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+)
+
+func TestPatchNode(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api := &API{Node: sNode}
+
+	router := gin.New()
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	router.PATCH("/v1/nodes/:name", mockIM, common.Wrapper(api.PatchNode))
+	do := func(contentType, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPatch, "/v1/nodes/node01", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newNode := func() *specV1.Node {
+		return &specV1.Node{
+			Name:              "node01",
+			Namespace:         "default",
+			Version:           "1",
+			CreationTimestamp: created,
+			NodeMode:          context.RunModeKube,
+			Labels:            map[string]string{"a": "1", "b": "2"},
+			Description:       "old",
+			Attributes: map[string]interface{}{
+				specV1.BaetylCoreFrequency: common.DefaultCoreFrequency,
+			},
+		}
+	}
+
+	// the merge patch changes the fields given only
+	sNode.EXPECT().Get(nil, "default", "node01").Return(newNode(), nil).Times(1)
+	sNode.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, n *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "new", n.Description)
+		assert.Equal(t, "2", n.Labels["b"])
+		assert.Equal(t, "3", n.Labels["c"])
+		_, ok := n.Labels["a"]
+		assert.False(t, ok)
+		assert.Equal(t, created, n.CreationTimestamp)
+		return n, nil
+	}).Times(1)
+	w := do(ContentTypeMergePatch, `{"description":"new","labels":{"a":null,"c":"3"}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"description":"new"`)
+
+	// the strategic merge patch
+	sNode.EXPECT().Get(nil, "default", "node01").Return(newNode(), nil).Times(1)
+	sNode.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, n *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "old", n.Description)
+		assert.Equal(t, "x", n.Labels["a"])
+		assert.Equal(t, "2", n.Labels["b"])
+		return n, nil
+	}).Times(1)
+	w = do(ContentTypeStrategicMergePatch, `{"labels":{"a":"x"}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the immutable fields can't be changed
+	sNode.EXPECT().Get(nil, "default", "node01").Return(newNode(), nil).Times(1)
+	w = do(ContentTypeMergePatch, `{"name":"node02"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "can't be changed")
+	sNode.EXPECT().Get(nil, "default", "node01").Return(newNode(), nil).Times(1)
+	w = do(ContentTypeMergePatch, `{"createTime":"2021-01-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the create time of the node can't be changed")
+
+	// the invalid patches
+	sNode.EXPECT().Get(nil, "default", "node01").Return(newNode(), nil).Times(1)
+	w = do(ContentTypeMergePatch, `{"labels":`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	sNode.EXPECT().Get(nil, "default", "node01").Return(newNode(), nil).Times(1)
+	w = do(ContentTypeMergePatch, `{"nodeMode":"unknown"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the patch based on a stale version is rejected
+	sNode.EXPECT().Get(nil, "default", "node01").Return(newNode(), nil).Times(1)
+	req, _ := http.NewRequest(http.MethodPatch, "/v1/nodes/node01?resourceVersion=0", bytes.NewReader([]byte(`{}`)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	// defaultStatsHistoryWindow the window of the history if from is not given
+	defaultStatsHistoryWindow = time.Hour
+	// maxStatsHistoryPoints the max number of the steps within the window
+	maxStatsHistoryPoints = 11000
+)
+
+// GetNodeStatsHistory returns the resource usage of the node within [from, to), which are in unix seconds,
+// the points are averaged by step if given, such as 5m
+func (api *API) GetNodeStatsHistory(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	from, to, step, err := parseStatsHistoryRange(c)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = api.Node.Get(nil, ns, n); err != nil {
+		return nil, err
+	}
+	points, err := api.Node.ListStatsHistory(ns, n, from, to, step)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.NodeStatsHistory{
+		Name:   n,
+		From:   from,
+		To:     to,
+		Points: points,
+	}
+	if step > 0 {
+		res.Step = step.String()
+	}
+	if res.Points == nil {
+		res.Points = []models.NodeStatsPoint{}
+	}
+	return res, nil
+}
+
+func parseStatsHistoryRange(c *common.Context) (from, to time.Time, step time.Duration, err error) {
+	to = time.Now().UTC()
+	if v := c.Query("to"); v != "" {
+		if to, err = parseUnixTime(v); err != nil {
+			return from, to, step, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the to should be in unix seconds"))
+		}
+	}
+	from = to.Add(-defaultStatsHistoryWindow)
+	if v := c.Query("from"); v != "" {
+		if from, err = parseUnixTime(v); err != nil {
+			return from, to, step, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the from should be in unix seconds"))
+		}
+	}
+	if !from.Before(to) {
+		return from, to, step, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the from should be before the to"))
+	}
+	if v := c.Query("step"); v != "" {
+		if step, err = time.ParseDuration(v); err != nil || step <= 0 {
+			return from, to, step, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "the step should be a positive duration, e.g. 5m"))
+		}
+		if to.Sub(from)/step > maxStatsHistoryPoints {
+			return from, to, step, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the step is too small, the window should contain no more than %d steps", maxStatsHistoryPoints)))
+		}
+	}
+	return from, to, step, nil
+}
+
+func parseUnixTime(v string) (time.Time, error) {
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, 0).UTC(), nil
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestGetNodeStatsHistory(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api := &API{Node: sNode, log: log.L()}
+
+	router := gin.New()
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	router.GET("/v1/nodes/:name/stats/history", mockIM, common.Wrapper(api.GetNodeStatsHistory))
+	do := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/node01/stats/history"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	from, to := time.Unix(1700000000, 0).UTC(), time.Unix(1700003600, 0).UTC()
+	points := []models.NodeStatsPoint{{Time: from, Usage: map[string]float64{"cpu": 0.5}, Percent: map[string]float64{"cpu": 25}}}
+	sNode.EXPECT().Get(nil, "default", "node01").Return(&specV1.Node{Name: "node01"}, nil).Times(1)
+	sNode.EXPECT().ListStatsHistory("default", "node01", from, to, 5*time.Minute).Return(points, nil).Times(1)
+	w := do("?from=1700000000&to=1700003600&step=5m")
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.NodeStatsHistory{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, &models.NodeStatsHistory{Name: "node01", From: from, To: to, Step: "5m0s", Points: points}, res)
+
+	// the last hour by default
+	sNode.EXPECT().Get(nil, "default", "node01").Return(&specV1.Node{Name: "node01"}, nil).Times(1)
+	sNode.EXPECT().ListStatsHistory("default", "node01", gomock.Any(), gomock.Any(), time.Duration(0)).
+		DoAndReturn(func(_, _ string, from, to time.Time, _ time.Duration) ([]models.NodeStatsPoint, error) {
+			assert.Equal(t, time.Hour, to.Sub(from))
+			return nil, nil
+		}).Times(1)
+	w = do("")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"points":[]`)
+
+	// node not found
+	sNode.EXPECT().Get(nil, "default", "node01").Return(nil, common.Error(common.ErrResourceNotFound, common.Field("type", "node"), common.Field("name", "node01"))).Times(1)
+	assert.Equal(t, http.StatusNotFound, do("").Code)
+
+	for _, q := range []string{"?from=abc", "?to=abc", "?from=1700003600&to=1700000000", "?step=-1m", "?step=abc", "?step=100ms"} {
+		w = do(q)
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/context"
 	"github.com/baetyl/baetyl-go/v2/json"
@@ -76,18 +78,29 @@ func initNodeAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		nodes := v1.Group("/nodes")
 		nodes.GET("/:name", mockIM, common.Wrapper(api.GetNode))
 		nodes.PUT("", mockIM, common.Wrapper(api.GetNodes))
+		nodes.POST("/batchGet", mockIM, common.Wrapper(api.BatchGetNodes))
 		nodes.GET("/:name/stats", mockIM, common.Wrapper(api.GetNodeStats))
 		nodes.GET("/:name/apps", mockIM, common.Wrapper(api.GetAppByNode))
 		nodes.GET("/:name/functions", mockIM, common.Wrapper(api.GetFunctionsByNode))
 		nodes.PUT("/:name", mockIM, common.Wrapper(api.UpdateNode))
 		nodes.DELETE("/:name", mockIM, common.Wrapper(api.DeleteNode))
+		nodes.POST("/batch/delete", mockIM, common.Wrapper(api.BatchDeleteNodes))
 		nodes.GET("/:name/init", mockIM, common.Wrapper(api.GenInitCmdFromNode))
+		nodes.GET("/:name/init/status", mockIM, common.Wrapper(api.GetNodeInitStatus))
+		nodes.POST("/:name/token", mockIM, common.Wrapper(api.GenNodeToken))
+		nodes.DELETE("/:name/token", mockIM, common.Wrapper(api.RevokeNodeToken))
 		nodes.POST("", mockIM, common.Wrapper(api.CreateNode))
 		nodes.GET("", mockIM, common.Wrapper(api.ListNode))
 		nodes.GET("/:name/deploys", mockIM, common.Wrapper(api.GetNodeDeployHistory))
+		nodes.GET("/:name/deploys/:id", mockIM, common.Wrapper(api.GetNodeDeployRecord))
 		nodes.GET("/:name/properties", mockIM, common.Wrapper(api.GetNodeProperties))
 		nodes.PUT("/:name/properties", mockIM, common.Wrapper(api.UpdateNodeProperties))
+		nodes.POST("/properties/batch", mockIM, common.Wrapper(api.BatchUpdateNodeProperties))
+		nodes.POST("/labels", mockIM, common.Wrapper(api.BatchUpdateNodeLabels))
+		nodes.GET("/:name/properties/history", mockIM, common.Wrapper(api.GetNodePropertyHistory))
 		nodes.PUT("/:name/mode", mockIM, common.Wrapper(api.UpdateNodeMode))
+		nodes.GET("/:name/tags", mockIM, common.Wrapper(api.GetNodeTags))
+		nodes.PUT("/:name/tags", mockIM, common.Wrapper(api.UpdateNodeTags))
 		nodes.PUT("/:name/core/configs", mockIM, common.Wrapper(api.UpdateCoreApp))
 		nodes.GET("/:name/core/configs", mockIM, common.Wrapper(api.GetCoreAppConfigs))
 		nodes.GET("/:name/core/versions", mockIM, common.Wrapper(api.GetCoreAppVersions))
@@ -133,10 +146,13 @@ func TestGetNode(t *testing.T) {
 	defer mockCtl.Finish()
 	sNode := ms.NewMockNodeService(mockCtl)
 	api.Node = sNode
+	sOffline := ms.NewMockNodeOfflineService(mockCtl)
+	api.Offline = sOffline
 
 	mNode := getMockNode()
 
 	sNode.EXPECT().Get(nil, mNode.Namespace, mNode.Name).Return(mNode, nil)
+	sOffline.EXPECT().Status(mNode.Namespace, time.Time{}).Return(models.ReadyTypeUninstall)
 
 	// 200
 	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc", nil)
@@ -144,7 +160,21 @@ func TestGetNode(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 	bytes := w.Body.Bytes()
-	assert.Equal(t, string(bytes), "{\"namespace\":\"default\",\"name\":\"abc\",\"createTime\":\"0001-01-01T00:00:00Z\",\"labels\":{\"baetyl-node-name\":\"abc\",\"tag\":\"baidu\"},\"sysApps\":[\"a\"],\"cluster\":false,\"ready\":0,\"mode\":\"cloud\"}\n")
+	assert.Equal(t, string(bytes), "{\"namespace\":\"default\",\"name\":\"abc\",\"createTime\":\"0001-01-01T00:00:00Z\",\"labels\":{\"baetyl-node-name\":\"abc\",\"tag\":\"baidu\"},\"sysApps\":[\"a\"],\"cluster\":false,\"ready\":0,\"mode\":\"cloud\",\"status\":\"uninstall\"}\n")
+
+	// the node offline
+	reportTime := time.Now().Add(-time.Hour).UTC()
+	mNode.Report = specV1.Report{"time": reportTime.Format(time.RFC3339Nano)}
+	sNode.EXPECT().Get(nil, mNode.Namespace, mNode.Name).Return(mNode, nil)
+	sOffline.EXPECT().Status(mNode.Namespace, gomock.Any()).DoAndReturn(func(_ string, rt time.Time) string {
+		assert.True(t, reportTime.Equal(rt))
+		return models.ReadyTypOffline
+	})
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"offline"`)
 
 	sNode.EXPECT().Get(nil, mNode.Namespace, mNode.Name).Return(nil, common.Error(common.ErrResourceNotFound))
 	// 404
@@ -438,6 +468,40 @@ func TestListNode(t *testing.T) {
 	assert.Equal(t, "", nodelist.Items[0].Labels[common.LabelNodeName])
 	assert.Equal(t, "node01", nodelist.Items[0].Name)
 	assert.Nil(t, nodelist.Items[0].Desire)
+
+	// 400 invalid selector
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes?selector=env%3Dprod,region~cn", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrRequestParamInvalid)
+
+	// 200 selector is passed to the node service
+	sNode.EXPECT().List("default", &models.ListOptions{
+		LabelSelector: "env=prod,region notin (cn-north)",
+	}).Return(mClist, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes?selector=env%3Dprod,region%20notin%20(cn-north)", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 200 sorted by create time
+	sNode.EXPECT().List("default", &models.ListOptions{
+		SortBy: models.SortByCreateTime,
+		Order:  models.SortOrderAsc,
+	}).Return(mClist, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes?sortBy=createTime&order=asc", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 400 the nodes are not sorted by update time
+	for _, query := range []string{"sortBy=updateTime", "sortBy=name&createSort=asc", "order=random"} {
+		req, _ = http.NewRequest(http.MethodGet, "/v1/nodes?"+query, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
 }
 
 func TestCreateNode(t *testing.T) {
@@ -471,6 +535,10 @@ func TestCreateNode(t *testing.T) {
 	wrpper, _ := service.NewWrapperService(cfg)
 	api.Wrapper = wrpper
 
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	api.NS = sNS
+	sNS.EXPECT().GetSettings(gomock.Any()).Return(&models.NamespaceSettings{}, nil).AnyTimes()
+
 	mNode := getMockNode2()
 
 	mQuota.EXPECT().AcquireQuota(mNode.Namespace, plugin.QuotaNode, 1).Return(nil)
@@ -504,6 +572,7 @@ func TestCreateNode(t *testing.T) {
 
 	sNode.EXPECT().Get(nil, gomock.Any(), gomock.Any()).Return(nil, nil)
 	sNode.EXPECT().Create(nil, mNode.Namespace, gomock.Any()).Return(nil, fmt.Errorf("create node error"))
+	sNode.EXPECT().Get(nil, gomock.Any(), gomock.Any()).Return(nil, nil)
 	mQuota.EXPECT().AcquireQuota(mNode.Namespace, plugin.QuotaNode, 1).Return(nil)
 	mQuota.EXPECT().ReleaseQuota(mNode.Namespace, plugin.QuotaNode, 1).Return(nil)
 	w = httptest.NewRecorder()
@@ -537,7 +606,8 @@ func TestCreateNode(t *testing.T) {
 	body, _ = json.Marshal(mNode)
 	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes", bytes.NewReader(body))
 	router.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrResourceConflict)
 
 	mNode.Name = ""
 	mNode.Labels[common.LabelNodeName] = mNode.Name
@@ -549,6 +619,100 @@ func TestCreateNode(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "The request parameter is invalid. (name is required)")
 }
 
+func TestCreateNodeConflict(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	mQuota := ms.NewMockQuotaService(mockCtl)
+	api.Quota = mQuota
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+	sModule := ms.NewMockModuleService(mockCtl)
+	api.Module = sModule
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	api.NS = sNS
+	cfg := &config.CloudConfig{}
+	cfg.Plugin.Tx = "defaulttx"
+	wrpper, _ := service.NewWrapperService(cfg)
+	api.Wrapper = wrpper
+
+	mNode := getMockNode2()
+	sModule.EXPECT().GetLatestModule(gomock.Any()).Return(&models.Module{Name: "baetyl", Version: "2.1.2"}, nil).AnyTimes()
+	sNS.EXPECT().GetSettings(gomock.Any()).Return(&models.NamespaceSettings{}, nil).AnyTimes()
+
+	// the node is created by a racing request after checked, the quota acquired is released
+	sNode.EXPECT().Get(nil, mNode.Namespace, mNode.Name).Return(nil, nil)
+	mQuota.EXPECT().AcquireQuota(mNode.Namespace, plugin.QuotaNode, 1).Return(nil)
+	sNode.EXPECT().Create(nil, mNode.Namespace, gomock.Any()).Return(nil, fmt.Errorf("UNIQUE constraint failed: baetyl_node.namespace, baetyl_node.name"))
+	sNode.EXPECT().Get(nil, mNode.Namespace, mNode.Name).Return(mNode, nil)
+	mQuota.EXPECT().ReleaseQuota(mNode.Namespace, plugin.QuotaNode, 1).Return(nil)
+	body, _ := json.Marshal(mNode)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/nodes", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrResourceConflict)
+
+	// the quota is released if the core version is not available
+	sModule2 := ms.NewMockModuleService(mockCtl)
+	api.Module = sModule2
+	sNode.EXPECT().Get(nil, mNode.Namespace, mNode.Name).Return(nil, nil)
+	mQuota.EXPECT().AcquireQuota(mNode.Namespace, plugin.QuotaNode, 1).Return(nil)
+	sModule2.EXPECT().GetLatestModule(gomock.Any()).Return(nil, fmt.Errorf("error"))
+	mQuota.EXPECT().ReleaseQuota(mNode.Namespace, plugin.QuotaNode, 1).Return(nil)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestCreateNodeWithNamespaceLabels(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	mQuota := ms.NewMockQuotaService(mockCtl)
+	api.Quota = mQuota
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+	sModule := ms.NewMockModuleService(mockCtl)
+	api.Module = sModule
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	api.NS = sNS
+	cfg := &config.CloudConfig{}
+	cfg.Plugin.Tx = "defaulttx"
+	wrpper, _ := service.NewWrapperService(cfg)
+	api.Wrapper = wrpper
+
+	mNode := getMockNode2()
+	sModule.EXPECT().GetLatestModule(gomock.Any()).Return(&models.Module{Name: "baetyl", Version: "2.1.2"}, nil).AnyTimes()
+
+	// the default labels of the namespace are merged, the label given is kept on conflict
+	sNode.EXPECT().Get(nil, gomock.Any(), gomock.Any()).Return(nil, nil)
+	sNS.EXPECT().GetSettings(mNode.Namespace).Return(&models.NamespaceSettings{
+		NodeLabels: map[string]string{"tenant": "acme", "tag": "default"},
+	}, nil)
+	mQuota.EXPECT().AcquireQuota(mNode.Namespace, plugin.QuotaNode, 1).Return(nil)
+	sNode.EXPECT().Create(nil, mNode.Namespace, gomock.Any()).DoAndReturn(func(_ interface{}, _ string, n *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "acme", n.Labels["tenant"])
+		assert.Equal(t, "baidu", n.Labels["tag"])
+		assert.Equal(t, "abc", n.Labels[common.LabelNodeName])
+		return n, nil
+	})
+	body, _ := json.Marshal(getMockNode2())
+	req, _ := http.NewRequest(http.MethodPost, "/v1/nodes", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `299 - "the label (tag) is kept as (baidu) rather than the default (default) of the namespace"`, w.Header().Get(HeaderWarning))
+
+	sNode.EXPECT().Get(nil, gomock.Any(), gomock.Any()).Return(nil, nil)
+	sNS.EXPECT().GetSettings(mNode.Namespace).Return(nil, fmt.Errorf("error"))
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
 func TestCreateNodeWithSysApps(t *testing.T) {
 	api, router, mockCtl := initNodeAPI(t)
 	defer mockCtl.Finish()
@@ -582,6 +746,10 @@ func TestCreateNodeWithSysApps(t *testing.T) {
 	wrpper, _ := service.NewWrapperService(cfg)
 	api.Wrapper = wrpper
 
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	api.NS = sNS
+	sNS.EXPECT().GetSettings(gomock.Any()).Return(&models.NamespaceSettings{}, nil).AnyTimes()
+
 	mNode := &specV1.Node{
 		Namespace: "default",
 		Name:      "abc",
@@ -630,6 +798,7 @@ func TestCreateNodeWithSysApps(t *testing.T) {
 
 	sNode.EXPECT().Get(nil, gomock.Any(), gomock.Any()).Return(nil, nil)
 	sNode.EXPECT().Create(nil, mNode.Namespace, gomock.Any()).Return(nil, fmt.Errorf("create node error"))
+	sNode.EXPECT().Get(nil, gomock.Any(), gomock.Any()).Return(nil, nil)
 	mQuota.EXPECT().AcquireQuota(mNode.Namespace, plugin.QuotaNode, 1).Return(nil)
 	mQuota.EXPECT().ReleaseQuota(mNode.Namespace, plugin.QuotaNode, 1).Return(nil)
 	w = httptest.NewRecorder()
@@ -663,7 +832,8 @@ func TestCreateNodeWithSysApps(t *testing.T) {
 	body, _ = json.Marshal(mNode)
 	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes", bytes.NewReader(body))
 	router.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrResourceConflict)
 
 	mNode.Name = ""
 	mNode.Labels[common.LabelNodeName] = mNode.Name
@@ -1359,6 +1529,16 @@ func TestDeleteNode(t *testing.T) {
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// 412: the node was modified since the version read
+	mNode.Version = "3"
+	sNode.EXPECT().Get(nil, gomock.Any(), gomock.Any()).Return(mNode, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/nodes/abc", nil)
+	req.Header.Set(common.HeaderIfMatch, "2")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrPreconditionFailed")
 }
 
 func TestDeleteNodeError(t *testing.T) {
@@ -1522,6 +1702,112 @@ func TestDeleteNodeError(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w3.Code)
 }
 
+func TestBatchGetNodes(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+
+	n1, n3 := getMockNode(), getMockNode()
+	n1.Name, n3.Name = "n1", "n3"
+
+	// the names are deduplicated and looked up in order
+	gomock.InOrder(
+		sNode.EXPECT().Get(nil, "default", "n1").Return(n1, nil),
+		sNode.EXPECT().Get(nil, "default", "n2").Return(nil, common.Error(common.ErrResourceNotFound)),
+		sNode.EXPECT().Get(nil, "default", "n3").Return(n3, nil),
+	)
+	body, _ := json.Marshal(models.NodeBatchNames{Names: []string{"n3", "n2", "n1", "n3"}})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/nodes/batchGet", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res models.NodeBatchGetResult
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, "n1", res.Items[0].Name)
+	assert.Equal(t, "n3", res.Items[1].Name)
+	assert.Equal(t, []string{"n2"}, res.NotFound)
+
+	// none found
+	sNode.EXPECT().Get(nil, "default", "n2").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	body, _ = json.Marshal(models.NodeBatchNames{Names: []string{"n2"}})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/batchGet", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"total":0,"items":[],"notFound":["n2"]}`, w.Body.String())
+
+	// 500
+	sNode.EXPECT().Get(nil, "default", "n1").Return(nil, fmt.Errorf("error")).Times(1)
+	body, _ = json.Marshal(models.NodeBatchNames{Names: []string{"n1"}})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/batchGet", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// 400
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/batchGet", bytes.NewReader([]byte("{}")))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBatchDeleteNodes(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	mQuota := ms.NewMockQuotaService(mockCtl)
+	api.Quota = mQuota
+	sNode, sIndex := ms.NewMockNodeService(mockCtl), ms.NewMockIndexService(mockCtl)
+	api.Node, api.Index = sNode, sIndex
+
+	n1 := &specV1.Node{Namespace: "default", Name: "n1"}
+	n2 := &specV1.Node{Namespace: "default", Name: "n2"}
+	n4 := &specV1.Node{Namespace: "default", Name: "n4"}
+
+	sNode.EXPECT().Get(nil, "default", "n1").Return(n1, nil).Times(1)
+	sNode.EXPECT().Delete(nil, "default", n1).Return(nil).Times(1)
+	mQuota.EXPECT().ReleaseQuota("default", plugin.QuotaNode, 1).Return(nil).Times(1)
+
+	sNode.EXPECT().Get(nil, "default", "n2").Return(n2, nil).Times(1)
+	sNode.EXPECT().Delete(nil, "default", n2).Return(common.Error(common.ErrResourceHasBeenUsed, common.Field("name", "n2"))).Times(1)
+
+	sNode.EXPECT().Get(nil, "default", "n3").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+
+	sNode.EXPECT().Get(nil, "default", "n4").Return(n4, nil).Times(1)
+	sNode.EXPECT().Delete(nil, "default", n4).Return(nil).Times(1)
+	mQuota.EXPECT().ReleaseQuota("default", plugin.QuotaNode, 1).Return(common.Error(common.ErrLicenseQuotaRelease)).Times(1)
+
+	body, _ := json.Marshal(models.NodeBatchNames{Names: []string{"n1", "n2", "n3", "n4"}})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/nodes/batch/delete", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res models.NodeDeleteResultList
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 4, res.Total)
+	assert.Equal(t, 2, res.Failed)
+	assert.Equal(t, models.NodeDeleteResult{Name: "n1", Deleted: true}, res.Items[0])
+	assert.False(t, res.Items[1].Deleted)
+	assert.Equal(t, common.ErrResourceHasBeenUsed, res.Items[1].Code)
+	assert.False(t, res.Items[2].Deleted)
+	assert.Equal(t, common.ErrResourceNotFound, res.Items[2].Code)
+	// the node deleted is not reported as failed, but with a warning if the quota isn't released
+	assert.True(t, res.Items[3].Deleted)
+	assert.Empty(t, res.Items[3].Code)
+	assert.Contains(t, res.Items[3].Warning, "the node quota failed to be released")
+	assert.Equal(t, []string{`299 - "the node quota of (n4) failed to be released"`}, w.Header().Values(HeaderWarning))
+
+	// 400
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/batch/delete", bytes.NewReader([]byte("{}")))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func genDesireOfSysApps() specV1.Desire {
 	content := `
 	{
@@ -1546,10 +1832,76 @@ func TestAPI_GetNodeDeployHistory(t *testing.T) {
 	sNode := ms.NewMockNodeService(mockCtl)
 	api.Node = sNode
 
+	list := &models.NodeDeployRecordList{
+		Total: 1,
+		Items: []models.NodeDeployRecord{{
+			ID:     1,
+			Node:   "abc",
+			Action: models.NodeDeployActionDeploy,
+			App:    models.NodeDeployApp{Name: "app01", Version: "2", PrevVersion: "1"},
+		}},
+	}
+	// the page size is 20 by default
+	sNode.EXPECT().Get(nil, "default", "abc").Return(&specV1.Node{Name: "abc"}, nil).Times(1)
+	sNode.EXPECT().ListDeployHistory("default", "abc", &models.Filter{PageNo: 1, PageSize: NodeDeployHistoryDefaultPageSize}).Return(list, nil).Times(1)
 	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/deploys", nil)
 	w2 := httptest.NewRecorder()
 	router.ServeHTTP(w2, req)
 	assert.Equal(t, http.StatusOK, w2.Code)
+	res := &models.NodeDeployRecordList{}
+	assert.NoError(t, json.Unmarshal(w2.Body.Bytes(), res))
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "1", res.Items[0].App.PrevVersion)
+
+	sNode.EXPECT().Get(nil, "default", "abc").Return(&specV1.Node{Name: "abc"}, nil).Times(1)
+	sNode.EXPECT().ListDeployHistory("default", "abc", &models.Filter{PageNo: 2, PageSize: 5}).Return(list, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/deploys?pageNo=2&pageSize=5", nil)
+	w2 = httptest.NewRecorder()
+	router.ServeHTTP(w2, req)
+	assert.Equal(t, http.StatusOK, w2.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/deploys?pageSize=1000", nil)
+	w2 = httptest.NewRecorder()
+	router.ServeHTTP(w2, req)
+	assert.Equal(t, http.StatusBadRequest, w2.Code)
+
+	sNode.EXPECT().Get(nil, "default", "none").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/none/deploys", nil)
+	w2 = httptest.NewRecorder()
+	router.ServeHTTP(w2, req)
+	assert.Equal(t, http.StatusNotFound, w2.Code)
+}
+
+func TestAPI_GetNodeDeployRecord(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+
+	record := &models.NodeDeployRecord{
+		ID:     3,
+		Node:   "abc",
+		Action: models.NodeDeployActionUndeploy,
+		App:    models.NodeDeployApp{Name: "app01", PrevVersion: "1"},
+		Desire: specV1.Desire{"apps": []interface{}{}},
+	}
+	sNode.EXPECT().GetDeployRecord("default", "abc", int64(3)).Return(record, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/deploys/3", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"desire":{"apps":[]}`)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/deploys/x", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sNode.EXPECT().GetDeployRecord("default", "abc", int64(4)).Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/deploys/4", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
 func TestGenInitCmdFromNode(t *testing.T) {
@@ -1587,6 +1939,143 @@ func TestGenInitCmdFromNode(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestGenInitCmdFromNode_Platform(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+	sInit := ms.NewMockInitService(mockCtl)
+	api.Init = sInit
+
+	node := getMockNode()
+	sNode.EXPECT().Get(nil, node.Namespace, node.Name).Return(node, nil).AnyTimes()
+
+	genInitCmd := func(query string) (int, *models.InitCMD) {
+		req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/init?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		res := &models.InitCMD{}
+		if w.Code == http.StatusOK {
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+		}
+		return w.Code, res
+	}
+
+	params := map[string]interface{}{
+		"InitApplyYaml":    "baetyl-init-apply.json",
+		"mode":             "native",
+		"arch":             "arm64",
+		"template":         service.TemplateBaetylInitCommand + "-linux-arm64",
+		"fallbackTemplate": service.TemplateInitCommandWget,
+	}
+	sInit.EXPECT().GetResource("default", "abc", service.TemplateBaetylInitCommand, params).Return([]byte("sh"), nil).Times(1)
+	code, res := genInitCmd("platform=linux-arm64&mode=native&method=wget")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, &models.InitCMD{CMD: "sh", Format: models.InitCMDFormatShell}, res)
+
+	params = map[string]interface{}{
+		"InitApplyYaml": "baetyl-init-apply.json",
+		"mode":          "native",
+		"template":      service.TemplateInitCommandWindows,
+	}
+	sInit.EXPECT().GetResource("default", "abc", service.TemplateBaetylInitCommand, params).Return([]byte("ps"), nil).Times(1)
+	code, res = genInitCmd("platform=windows&mode=native")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, &models.InitCMD{CMD: "ps", Format: models.InitCMDFormatPowershell}, res)
+
+	params = map[string]interface{}{
+		"Mode":         "kube",
+		"KubeNodeName": "k3s",
+	}
+	sInit.EXPECT().GetResource("default", "abc", service.TemplateInitManifest, params).Return([]byte("kind: Namespace"), nil).Times(1)
+	code, res = genInitCmd("platform=k8s-yaml&node=k3s")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, &models.InitCMD{CMD: "kind: Namespace", Format: models.InitCMDFormatYaml}, res)
+
+	for _, query := range []string{"platform=k8s-yaml&mode=native", "platform=k8s-yaml&oneTime=true", "platform=darwin"} {
+		code, _ = genInitCmd(query)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+}
+
+func TestGenInitCmdFromNode_OneTime(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+	sInit := ms.NewMockInitService(mockCtl)
+	api.Init = sInit
+
+	node := getMockNode()
+	params := map[string]interface{}{
+		"InitApplyYaml": "baetyl-init-deployment.yml",
+		"mode":          "kube",
+		"template":      service.TemplateBaetylInitCommand,
+		"ttl":           10 * time.Minute,
+		"oneTime":       true,
+	}
+	sInit.EXPECT().GetResource("default", "abc", service.TemplateBaetylInitCommand, params).Return([]byte("setup"), nil).Times(1)
+	sNode.EXPECT().Get(nil, node.Namespace, node.Name).Return(node, nil).Times(4)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/init?ttl=10m&oneTime=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	for _, query := range []string{"ttl=abc", "ttl=-1m", "ttl=1000h"} {
+		req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/init?"+query, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	expireTime := time.Unix(1600000000, 0).UTC()
+	status := &models.NodeInitTokenStatus{OneTime: true, Expired: true, ExpireTime: &expireTime}
+	sInit.EXPECT().GetInitTokenStatus("default", "abc").Return(status, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/init/status", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.NodeInitTokenStatus{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, status, res)
+}
+
+func TestGenInitCmdFromNode_NodeToken(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	sNode := ms.NewMockNodeService(mockCtl)
+	sSign := ms.NewMockSignService(mockCtl)
+	sInit := ms.NewMockInitService(mockCtl)
+	api.Node, api.Sign, api.Init = sNode, sSign, sInit
+
+	node := getMockNode()
+	sNode.EXPECT().Get(nil, node.Namespace, node.Name).Return(node, nil).AnyTimes()
+	sNode.EXPECT().UpdateNodeAttributes("default", "abc", gomock.Any()).Return(node, nil).Times(1)
+	sSign.EXPECT().GenToken(gomock.Any()).Return("token", nil).Times(1)
+	sInit.EXPECT().GetResource("default", "abc", service.TemplateBaetylInitCommand, gomock.Any()).DoAndReturn(
+		func(_, _, _ string, params map[string]interface{}) (interface{}, error) {
+			assert.Equal(t, NodeTokenPrefix+"token", params["NodeToken"])
+			return []byte(fmt.Sprintf("setup?nodeToken=%s", params["NodeToken"])), nil
+		}).Times(1)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/init?nodeToken=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.InitCMD{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Contains(t, res.CMD, "nodeToken="+NodeTokenPrefix+"token")
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/init?nodeToken=x", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestGenInitCmdFromNode_ErrNode(t *testing.T) {
 	api, router, mockCtl := initNodeAPI(t)
 	defer mockCtl.Finish()
@@ -1812,6 +2301,8 @@ func TestUpdateNodeProperties(t *testing.T) {
 
 	sNode := ms.NewMockNodeService(mockCtl)
 	api.Node = sNode
+	sLocker := ms.NewMockLockerService(mockCtl)
+	api.Locker = sLocker
 
 	nodeProps := &models.NodeProperties{
 		State: models.NodePropertiesState{
@@ -1819,7 +2310,9 @@ func TestUpdateNodeProperties(t *testing.T) {
 			Desire: map[string]interface{}{"b": "2"},
 		},
 	}
-	sNode.EXPECT().UpdateNodeProperties(gomock.Any(), gomock.Any(), gomock.Any()).Return(nodeProps, nil).AnyTimes()
+	sLocker.EXPECT().Lock(gomock.Any(), "node_default_abc", int64(0)).Return("v0", nil)
+	sNode.EXPECT().UpdateNodeProperties(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nodeProps, nil).AnyTimes()
+	sLocker.EXPECT().Unlock(gomock.Any(), "node_default_abc", "v0")
 
 	reqNodeProps := &models.NodeProperties{}
 	data, err := json.Marshal(reqNodeProps)
@@ -1850,6 +2343,143 @@ func TestUpdateNodeProperties(t *testing.T) {
 	req, _ = http.NewRequest(http.MethodPut, "/v1/nodes/abc/properties", bytes.NewReader(data))
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the node is locked by the other update
+	sLocker.EXPECT().Lock(gomock.Any(), "node_default_abc", int64(0)).Return("", fmt.Errorf("locked"))
+	data, _ = json.Marshal(&models.NodeProperties{})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/nodes/abc/properties", bytes.NewReader(data))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestGetNodePropertyHistory(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+
+	params := &models.NodePropertyRecordListOptions{Property: "a", Type: models.NodePropertyDesire, StartTime: 100, EndTime: 200}
+	list := &models.NodePropertyRecordList{
+		Total:                         1,
+		NodePropertyRecordListOptions: params,
+		Items: []models.NodePropertyRecord{{ID: 1, Namespace: "default", Node: "abc", Property: "a",
+			Type: models.NodePropertyDesire, Value: "1", Actor: "user01"}},
+	}
+	sNode.EXPECT().Get(nil, "default", "abc").Return(&specV1.Node{Name: "abc"}, nil)
+	sNode.EXPECT().ListPropertyHistory("default", "abc", params).Return(list, nil)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/properties/history?property=a&type=desire&startTime=100&endTime=200", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.NodePropertyRecordList
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "a", res.Property)
+	assert.Equal(t, "user01", res.Items[0].Actor)
+
+	// invalid time range
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/properties/history?startTime=200&endTime=100", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// invalid type
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/properties/history?type=other", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the node is not found
+	sNode.EXPECT().Get(nil, "default", "abc").Return(nil, common.Error(common.ErrResourceNotFound, common.Field("type", "node"), common.Field("name", "abc")))
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/properties/history", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPropertyActor(t *testing.T) {
+	c := common.NewContext(&gin.Context{})
+	assert.Equal(t, "", propertyActor(c))
+	c.SetUserInfo(common.UserInfo{User: common.User{ID: "u1"}})
+	assert.Equal(t, "u1", propertyActor(c))
+	c.SetUser(common.User{ID: "u2", Name: "user02"})
+	assert.Equal(t, "user02", propertyActor(c))
+}
+
+func TestBatchUpdateNodeProperties(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+	sLocker := ms.NewMockLockerService(mockCtl)
+	api.Locker = sLocker
+
+	props := models.NodeProperties{
+		State: models.NodePropertiesState{
+			Desire: map[string]interface{}{"b": "2"},
+		},
+	}
+	nodes := &models.NodeList{Items: []specV1.Node{{Name: "n0"}, {Name: "n1"}, {Name: "n2"}}}
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "env=prod"}).Return(nodes, nil)
+	current := &models.NodeProperties{
+		State: models.NodePropertiesState{
+			Report: map[string]interface{}{"r": "0"},
+			Desire: map[string]interface{}{"a": "1", "b": "1"},
+		},
+	}
+	merged := &models.NodeProperties{
+		State: models.NodePropertiesState{
+			Desire: map[string]interface{}{"a": "1", "b": "2"},
+		},
+	}
+	sLocker.EXPECT().Lock(gomock.Any(), "node_default_n0", int64(0)).Return("v0", nil)
+	sNode.EXPECT().GetNodeProperties("default", "n0").Return(current, nil)
+	sNode.EXPECT().UpdateNodeProperties("default", "n0", "", merged).Return(merged, nil)
+	sLocker.EXPECT().Unlock(gomock.Any(), "node_default_n0", "v0")
+	sLocker.EXPECT().Lock(gomock.Any(), "node_default_n1", int64(0)).Return("", fmt.Errorf("locked"))
+	sLocker.EXPECT().Lock(gomock.Any(), "node_default_n2", int64(0)).Return("v2", nil)
+	sNode.EXPECT().GetNodeProperties("default", "n2").Return(nil, common.Error(common.ErrResourceNotFound, common.Field("type", "node"), common.Field("name", "n2")))
+	sLocker.EXPECT().Unlock(gomock.Any(), "node_default_n2", "v2")
+
+	data, _ := json.Marshal(&models.NodePropertiesBatch{Selector: "env=prod", Properties: props})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/nodes/properties/batch", bytes.NewReader(data))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.NodePropertiesResultList
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, 2, res.Failed)
+	assert.Equal(t, models.NodePropertiesResult{Name: "n0", Updated: true}, res.Items[0])
+	assert.Equal(t, models.NodePropertiesResult{Name: "n1", Code: common.ErrUnknown, Message: "locked"}, res.Items[1])
+	assert.Equal(t, "n2", res.Items[2].Name)
+	assert.Equal(t, common.ErrResourceNotFound, res.Items[2].Code)
+
+	// selector is required
+	data, _ = json.Marshal(&models.NodePropertiesBatch{Properties: props})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/properties/batch", bytes.NewReader(data))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// desire value should be string
+	data, _ = json.Marshal(&models.NodePropertiesBatch{Selector: "env=prod", Properties: models.NodeProperties{
+		State: models.NodePropertiesState{Desire: map[string]interface{}{"a": 1}},
+	}})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/properties/batch", bytes.NewReader(data))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sNode.EXPECT().List("default", gomock.Any()).Return(nil, fmt.Errorf("error"))
+	data, _ = json.Marshal(&models.NodePropertiesBatch{Selector: "env=prod", Properties: props})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/properties/batch", bytes.NewReader(data))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
 func TestUpdateNodeMode(t *testing.T) {
@@ -1887,6 +2517,102 @@ func TestUpdateNodeMode(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestNodeTags(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+
+	node := &specV1.Node{
+		Namespace: "default",
+		Name:      "abc",
+		Labels:    map[string]string{"env": "prod"},
+		Annotations: map[string]string{
+			"other":                                  "x",
+			common.AnnotationNodeTagPrefix + "owner": "alice",
+		},
+	}
+
+	// get
+	sNode.EXPECT().Get(nil, "default", "abc").Return(node, nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/tags", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"tags":{"owner":"alice"}}`+"\n", w.Body.String())
+
+	sNode.EXPECT().Get(nil, "default", "abc").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/tags", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// update
+	tags := map[string]string{"owner": "bob", "room": "B2"}
+	sNode.EXPECT().UpdateNodeTags("default", "abc", tags).Return(tags, nil).Times(1)
+	data, _ := json.Marshal(models.NodeTags{Tags: tags})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/nodes/abc/tags", bytes.NewReader(data))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"tags":{"owner":"bob","room":"B2"}}`+"\n", w.Body.String())
+
+	sNode.EXPECT().UpdateNodeTags("default", "abc", tags).Return(nil, errors.New("error")).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/nodes/abc/tags", bytes.NewReader(data))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// invalid tags
+	for _, invalid := range []map[string]string{
+		{"": "empty"},
+		{strings.Repeat("k", NodeTagKeyMaxLength+1): "v"},
+		{"k": strings.Repeat("v", NodeTagValueMaxLength+1)},
+	} {
+		data, _ = json.Marshal(models.NodeTags{Tags: invalid})
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodPut, "/v1/nodes/abc/tags", bytes.NewReader(data))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+	many := map[string]string{}
+	for i := 0; i <= NodeTagsMaxCount; i++ {
+		many[fmt.Sprintf("k%d", i)] = "v"
+	}
+	data, _ = json.Marshal(models.NodeTags{Tags: many})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/nodes/abc/tags", bytes.NewReader(data))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// list searches the tags, which are returned only if required
+	list := func() *models.NodeList {
+		return &models.NodeList{Total: 1, Items: []specV1.Node{{
+			Name:        "abc",
+			Annotations: map[string]string{"other": "x", common.AnnotationNodeTagPrefix + "owner": "alice"},
+			Attributes:  map[string]interface{}{specV1.BaetylCoreFrequency: common.DefaultCoreFrequency},
+		}}}
+	}
+	sNode.EXPECT().List("default", &models.ListOptions{NodeOptions: models.NodeOptions{Tag: "ali"}}).Return(list(), nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes?tag=ali", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := new(models.NodeViewList)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, map[string]string{"other": "x"}, res.Items[0].Annotations)
+
+	sNode.EXPECT().List("default", &models.ListOptions{NodeOptions: models.NodeOptions{WithTags: true}}).Return(list(), nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes?withTags=true", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = new(models.NodeViewList)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, "alice", res.Items[0].Annotations[common.AnnotationNodeTagPrefix+"owner"])
+}
+
 func TestAPI_UpdateCoreApp(t *testing.T) {
 	api, router, mockCtl := initNodeAPI(t)
 	defer mockCtl.Finish()
@@ -2351,3 +3077,63 @@ func TestAPI_GetCoreAppVersions(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+func TestBatchUpdateNodeLabels(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+
+	post := func(batch *models.NodeLabelsBatch) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(batch)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/v1/nodes/labels", bytes.NewReader(data))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	nodes := &models.NodeList{Items: []specV1.Node{
+		{Name: "n0", Labels: map[string]string{common.LabelNodeName: "n0", "env": "prod", "zone": "a"}},
+		{Name: "n1", Labels: map[string]string{common.LabelNodeName: "n1", "env": "prod", "zone": "b"}},
+		{Name: "n2", Labels: map[string]string{common.LabelNodeName: "n2", "env": "prod", "zone": "b", "old": "x"}},
+	}}
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "env=prod"}).Return(nodes, nil).Times(1)
+	// each changed node is updated once with all the changes, the unchanged one is skipped
+	sNode.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, node *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "n0", node.Name)
+		assert.Equal(t, map[string]string{common.LabelNodeName: "n0", "env": "prod", "zone": "b"}, node.Labels)
+		return node, nil
+	}).Times(1)
+	sNode.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, node *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "n2", node.Name)
+		assert.Equal(t, map[string]string{common.LabelNodeName: "n2", "env": "prod", "zone": "b"}, node.Labels)
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "shadow"), common.Field("name", "n2"))
+	}).Times(1)
+
+	w := post(&models.NodeLabelsBatch{Selector: "env=prod", Add: map[string]string{"zone": "b"}, Remove: []string{"old"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.NodeLabelsResultList
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, 1, res.Failed)
+	assert.Equal(t, models.NodeLabelsResult{Name: "n0", Updated: true}, res.Items[0])
+	assert.Equal(t, models.NodeLabelsResult{Name: "n1"}, res.Items[1])
+	assert.Equal(t, common.ErrResourceNotFound, res.Items[2].Code)
+
+	for _, batch := range []*models.NodeLabelsBatch{
+		{Add: map[string]string{"zone": "b"}},
+		{Selector: "env=prod"},
+		{Selector: "env=prod", Add: map[string]string{"zone": "b"}, Remove: []string{"zone"}},
+		{Selector: "env=prod", Remove: []string{common.LabelNodeName}},
+		{Selector: "env=prod", Add: map[string]string{common.LabelNodeMode: "kube"}},
+		{Selector: "env=prod", Add: map[string]string{"zone": "a/b"}},
+	} {
+		w = post(batch)
+		assert.Equal(t, http.StatusBadRequest, w.Code, batch)
+	}
+
+	sNode.EXPECT().List("default", gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	w = post(&models.NodeLabelsBatch{Selector: "env=prod", Add: map[string]string{"zone": "b"}})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
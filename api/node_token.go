@@ -0,0 +1,117 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+const (
+	// NodeTokenPrefix the prefix distinguishing the node tokens from the tokens of the users
+	NodeTokenPrefix = "baetyl-node-"
+
+	NodeTokenDefaultTTL = 30 * 24 * time.Hour
+	NodeTokenMaxTTL     = 365 * 24 * time.Hour
+
+	// the attributes of a node keeping the state of its latest node token
+	AttrNodeTokenID     = "BaetylNodeTokenID"
+	AttrNodeTokenExpiry = "BaetylNodeTokenExpiry"
+
+	nodeTokenIDLength = 16
+)
+
+// GenNodeToken issues a node token, which replaces the former one of the node
+func (api *API) GenNodeToken(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.Param("name")
+	ttl := NodeTokenDefaultTTL
+	if str := c.Query("ttl"); str != "" {
+		d, err := time.ParseDuration(str)
+		if err != nil || d <= 0 || d > NodeTokenMaxTTL {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the ttl should be a positive duration no more than %s", NodeTokenMaxTTL)))
+		}
+		ttl = d
+	}
+	if _, err := api.Node.Get(nil, ns, name); err != nil {
+		return nil, err
+	}
+	return api.issueNodeToken(ns, name, ttl)
+}
+
+// RevokeNodeToken revokes the node token of the node
+func (api *API) RevokeNodeToken(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.Param("name")
+	if _, err := api.Node.Get(nil, ns, name); err != nil {
+		return nil, err
+	}
+	_, err := api.Node.UpdateNodeAttributes(ns, name, map[string]interface{}{
+		AttrNodeTokenID:     nil,
+		AttrNodeTokenExpiry: nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.L().Info("node token is revoked", log.Any(c.GetTrace()), log.Any("namespace", ns), log.Any("node", name))
+	return nil, nil
+}
+
+func (api *API) issueNodeToken(ns, name string, ttl time.Duration) (*models.NodeToken, error) {
+	id := common.RandString(nodeTokenIDLength)
+	expiry := time.Unix(time.Now().Add(ttl).Unix(), 0).UTC()
+	_, err := api.Node.UpdateNodeAttributes(ns, name, map[string]interface{}{
+		AttrNodeTokenID:     id,
+		AttrNodeTokenExpiry: expiry.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+	token, err := api.Sign.GenToken(map[string]interface{}{
+		service.InfoNamespace: ns,
+		service.InfoName:      name,
+		service.InfoExpiry:    expiry.Unix(),
+		service.InfoNodeToken: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.NodeToken{Token: NodeTokenPrefix + token, ExpireTime: expiry}, nil
+}
+
+// IsNodeToken returns whether the authorization carries a node token, with or without the bearer scheme
+func IsNodeToken(authorization string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(authorization, "Bearer "), NodeTokenPrefix)
+}
+
+// CheckNodeToken returns the namespace and the name of the node of the token,
+// the token is valid only if it is the latest one of the node and not revoked
+func CheckNodeToken(authorization string, genToken func(map[string]interface{}) (string, error),
+	getNode func(ns, name string) (*specV1.Node, error)) (string, string, error) {
+	if !IsNodeToken(authorization) {
+		return "", "", common.Error(common.ErrInvalidToken)
+	}
+	info, err := CheckAndParseToken(strings.TrimPrefix(strings.TrimPrefix(authorization, "Bearer "), NodeTokenPrefix), genToken)
+	if err != nil {
+		return "", "", err
+	}
+	id, _ := info[service.InfoNodeToken].(string)
+	if id == "" {
+		return "", "", common.Error(common.ErrInvalidToken)
+	}
+	ns, name := info[service.InfoNamespace].(string), info[service.InfoName].(string)
+	node, err := getNode(ns, name)
+	if err != nil {
+		return "", "", err
+	}
+	if current, _ := node.Attributes[AttrNodeTokenID].(string); current != id {
+		log.L().Info("node token is replaced or revoked", log.Any("namespace", ns), log.Any("node", name))
+		return "", "", common.Error(common.ErrInvalidToken)
+	}
+	return ns, name, nil
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestAPI_GenNodeToken(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	sSign := ms.NewMockSignService(mockCtl)
+	api.Node, api.Sign = sNode, sSign
+
+	node := &specV1.Node{Namespace: "default", Name: "abc"}
+	var id string
+	sNode.EXPECT().Get(nil, "default", "abc").Return(node, nil)
+	sNode.EXPECT().UpdateNodeAttributes("default", "abc", gomock.Any()).DoAndReturn(
+		func(_, _ string, attrs map[string]interface{}) (*specV1.Node, error) {
+			id = attrs[AttrNodeTokenID].(string)
+			assert.Len(t, id, nodeTokenIDLength)
+			assert.NotEmpty(t, attrs[AttrNodeTokenExpiry])
+			return node, nil
+		})
+	sSign.EXPECT().GenToken(gomock.Any()).DoAndReturn(func(info map[string]interface{}) (string, error) {
+		assert.Equal(t, id, info[service.InfoNodeToken])
+		assert.Equal(t, "abc", info[service.InfoName])
+		return "token", nil
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/nodes/abc/token?ttl=1h", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.NodeToken{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, NodeTokenPrefix+"token", res.Token)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), res.ExpireTime, time.Minute)
+
+	for _, ttl := range []string{"x", "-1h", "9000h"} {
+		req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/abc/token?ttl="+ttl, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, ttl)
+	}
+
+	sNode.EXPECT().Get(nil, "default", "abc").Return(nil, fmt.Errorf("error"))
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/abc/token", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestAPI_RevokeNodeToken(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+
+	node := &specV1.Node{Namespace: "default", Name: "abc"}
+	sNode.EXPECT().Get(nil, "default", "abc").Return(node, nil)
+	sNode.EXPECT().UpdateNodeAttributes("default", "abc", map[string]interface{}{
+		AttrNodeTokenID:     nil,
+		AttrNodeTokenExpiry: nil,
+	}).Return(node, nil)
+	req, _ := http.NewRequest(http.MethodDelete, "/v1/nodes/abc/token", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCheckNodeToken(t *testing.T) {
+	info := map[string]interface{}{
+		service.InfoNamespace: "default",
+		service.InfoName:      "abc",
+		service.InfoExpiry:    time.Now().Add(time.Hour).Unix(),
+		service.InfoNodeToken: "id",
+	}
+	data, err := json.Marshal(info)
+	assert.NoError(t, err)
+	token := "0123456789" + hex.EncodeToString(data)
+	genToken := func(map[string]interface{}) (string, error) { return token, nil }
+	getNode := func(ns, name string) (*specV1.Node, error) {
+		return &specV1.Node{Namespace: ns, Name: name, Attributes: map[string]interface{}{AttrNodeTokenID: "id"}}, nil
+	}
+
+	assert.True(t, IsNodeToken("Bearer "+NodeTokenPrefix+token))
+	assert.False(t, IsNodeToken(token))
+
+	ns, name, err := CheckNodeToken("Bearer "+NodeTokenPrefix+token, genToken, getNode)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", ns)
+	assert.Equal(t, "abc", name)
+
+	// not a node token
+	_, _, err = CheckNodeToken(token, genToken, getNode)
+	assert.Error(t, err)
+
+	// signature not matched
+	_, _, err = CheckNodeToken(NodeTokenPrefix+token, func(map[string]interface{}) (string, error) { return "x", nil }, getNode)
+	assert.Error(t, err)
+
+	// replaced or revoked
+	_, _, err = CheckNodeToken(NodeTokenPrefix+token, genToken, func(ns, name string) (*specV1.Node, error) {
+		return &specV1.Node{Namespace: ns, Name: name}, nil
+	})
+	assert.Error(t, err)
+
+	// node not found
+	_, _, err = CheckNodeToken(NodeTokenPrefix+token, genToken, func(ns, name string) (*specV1.Node, error) {
+		return nil, fmt.Errorf("error")
+	})
+	assert.Error(t, err)
+
+	// init token without the id
+	delete(info, service.InfoNodeToken)
+	data, err = json.Marshal(info)
+	assert.NoError(t, err)
+	initToken := "0123456789" + hex.EncodeToString(data)
+	_, _, err = CheckNodeToken(NodeTokenPrefix+initToken, func(map[string]interface{}) (string, error) { return initToken, nil }, getNode)
+	assert.Error(t, err)
+}
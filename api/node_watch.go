@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gorilla/websocket"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	DefaultNodeWatchInterval  = 3 * time.Second
+	DefaultNodeWatchHeartbeat = 30 * time.Second
+	nodeWatchWriteTimeout     = 10 * time.Second
+)
+
+var nodeStatsUpgrader = websocket.Upgrader{}
+
+// namespaceLimiter bounds the number of concurrent long connections per namespace
+type namespaceLimiter struct {
+	max    int
+	counts map[string]int
+	sync.Mutex
+}
+
+func newNamespaceLimiter(max int) *namespaceLimiter {
+	return &namespaceLimiter{max: max, counts: map[string]int{}}
+}
+
+func (l *namespaceLimiter) acquire(namespace string) bool {
+	l.Lock()
+	defer l.Unlock()
+	if l.max > 0 && l.counts[namespace] >= l.max {
+		return false
+	}
+	l.counts[namespace]++
+	return true
+}
+
+func (l *namespaceLimiter) release(namespace string) {
+	l.Lock()
+	defer l.Unlock()
+	l.counts[namespace]--
+	if l.counts[namespace] <= 0 {
+		delete(l.counts, namespace)
+	}
+}
+
+// nodeStatsWatchers bounds the number of concurrent watchers of node stats per namespace
+type nodeStatsWatchers struct {
+	*namespaceLimiter
+	interval  time.Duration
+	heartbeat time.Duration
+}
+
+func newNodeStatsWatchers(max int, interval, heartbeat time.Duration) *nodeStatsWatchers {
+	if interval <= 0 {
+		interval = DefaultNodeWatchInterval
+	}
+	if heartbeat <= 0 {
+		heartbeat = DefaultNodeWatchHeartbeat
+	}
+	return &nodeStatsWatchers{
+		namespaceLimiter: newNamespaceLimiter(max),
+		interval:         interval,
+		heartbeat:        heartbeat,
+	}
+}
+
+// WatchNodeStats upgrades the request to websocket and pushes the stats of the node when its report changes,
+// the connection is closed after the node is deleted
+func (api *API) WatchNodeStats(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	view, err := api.getNodeStatsView(ns, n)
+	if err != nil {
+		return nil, err
+	}
+	if !api.nodeStatsWatchers.acquire(ns) {
+		return nil, common.Error(common.ErrTooManyRequests,
+			common.Field("error", "the number of node stats watchers reaches the limit"))
+	}
+	defer api.nodeStatsWatchers.release(ns)
+
+	conn, err := nodeStatsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// the upgrader has replied the error to client
+		log.L().Warn("failed to upgrade node stats watcher", log.Any(c.GetTrace()), log.Error(err))
+		return nil, nil
+	}
+	defer conn.Close()
+
+	heartbeat := api.nodeStatsWatchers.heartbeat
+	conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	})
+	// the messages of client are discarded, reading is required to process the control messages
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	last, err := writeNodeStatsEvent(conn, models.NodeStatsEventStats, view)
+	if err != nil {
+		return nil, nil
+	}
+	statsTicker := time.NewTicker(api.nodeStatsWatchers.interval)
+	defer statsTicker.Stop()
+	heartbeatTicker := time.NewTicker(heartbeat)
+	defer heartbeatTicker.Stop()
+	for {
+		select {
+		case <-closed:
+			return nil, nil
+		case <-c.Request.Context().Done():
+			// the server shuts down
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, common.ErrShutdown.Error()),
+				time.Now().Add(nodeWatchWriteTimeout))
+			return nil, nil
+		case <-heartbeatTicker.C:
+			if err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(nodeWatchWriteTimeout)); err != nil {
+				return nil, nil
+			}
+		case <-statsTicker.C:
+			view, err = api.getNodeStatsView(ns, n)
+			if err != nil {
+				if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+					writeNodeStatsEvent(conn, models.NodeStatsEventDeleted, nil)
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseNormalClosure, "node deleted"),
+						time.Now().Add(nodeWatchWriteTimeout))
+					return nil, nil
+				}
+				log.L().Warn("failed to get node stats", log.Any(c.GetTrace()), log.Any("name", n), log.Error(err))
+				continue
+			}
+			data, err := json.Marshal(view)
+			if err != nil || bytes.Equal(data, last) {
+				continue
+			}
+			if last, err = writeNodeStatsEvent(conn, models.NodeStatsEventStats, view); err != nil {
+				return nil, nil
+			}
+		}
+	}
+}
+
+// writeNodeStatsEvent writes the event and returns the marshaled node view to detect changes
+func writeNodeStatsEvent(conn *websocket.Conn, typ string, view *v1.NodeView) ([]byte, error) {
+	conn.SetWriteDeadline(time.Now().Add(nodeWatchWriteTimeout))
+	err := conn.WriteJSON(&models.NodeStatsEvent{
+		Type:      typ,
+		Node:      view,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if view == nil {
+		return nil, nil
+	}
+	return json.Marshal(view)
+}
+
+func (api *API) getNodeStatsView(ns, n string) (*v1.NodeView, error) {
+	node, err := api.Node.Get(nil, ns, n)
+	if err != nil {
+		return nil, err
+	}
+	view, err := api.ToNodeView(node)
+	if err != nil {
+		return nil, err
+	}
+	view.Desire = nil
+	return view, nil
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initNodeWatchAPI(t *testing.T) (*API, *httptest.Server, *gomock.Controller) {
+	api := &API{nodeStatsWatchers: newNodeStatsWatchers(1, 10*time.Millisecond, time.Second)}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		nodes := v1.Group("/nodes")
+		nodes.GET("/:name/stats/watch", mockIM, common.WrapperNative(api.WatchNodeStats, true))
+	}
+	return api, httptest.NewServer(router), mockCtl
+}
+
+func TestNodeStatsWatchers(t *testing.T) {
+	w := newNodeStatsWatchers(2, 0, 0)
+	assert.Equal(t, DefaultNodeWatchInterval, w.interval)
+	assert.Equal(t, DefaultNodeWatchHeartbeat, w.heartbeat)
+	assert.True(t, w.acquire("a"))
+	assert.True(t, w.acquire("a"))
+	assert.False(t, w.acquire("a"))
+	assert.True(t, w.acquire("b"))
+	w.release("a")
+	assert.True(t, w.acquire("a"))
+	w.release("a")
+	w.release("a")
+	w.release("b")
+	assert.Len(t, w.counts, 0)
+
+	unlimited := newNodeStatsWatchers(0, 0, 0)
+	for i := 0; i < 100; i++ {
+		assert.True(t, unlimited.acquire("a"))
+	}
+}
+
+func TestWatchNodeStats(t *testing.T) {
+	api, server, mockCtl := initNodeWatchAPI(t)
+	defer mockCtl.Finish()
+	defer server.Close()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+
+	node := &specV1.Node{
+		Name:       "node01",
+		Namespace:  "default",
+		Attributes: map[string]interface{}{specV1.BaetylCoreFrequency: "20"},
+		Report:     specV1.Report{"modeinfo": "m1"},
+	}
+	updated := &specV1.Node{
+		Name:       "node01",
+		Namespace:  "default",
+		Attributes: map[string]interface{}{specV1.BaetylCoreFrequency: "20"},
+		Report:     specV1.Report{"modeinfo": "m2"},
+	}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/nodes/node01/stats/watch"
+
+	// not found before upgrade
+	sNode.EXPECT().Get(nil, "default", "node02").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	_, resp, err := websocket.DefaultDialer.Dial(strings.Replace(url, "node01", "node02", 1), nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	gomock.InOrder(
+		sNode.EXPECT().Get(nil, "default", "node01").Return(node, nil).Times(3),
+		sNode.EXPECT().Get(nil, "default", "node01").Return(updated, nil).Times(1),
+		sNode.EXPECT().Get(nil, "default", "node01").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1),
+	)
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// the number of watchers is bounded
+	sNode.EXPECT().Get(nil, "default", "node03").Return(node, nil).Times(1)
+	_, resp, err = websocket.DefaultDialer.Dial(strings.Replace(url, "node01", "node03", 1), nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	event := &models.NodeStatsEvent{}
+	assert.NoError(t, conn.ReadJSON(event))
+	assert.Equal(t, models.NodeStatsEventStats, event.Type)
+	assert.Equal(t, "m1", event.Node.Report.ModeInfo)
+
+	// unchanged stats are not pushed
+	event = &models.NodeStatsEvent{}
+	assert.NoError(t, conn.ReadJSON(event))
+	assert.Equal(t, models.NodeStatsEventStats, event.Type)
+	assert.Equal(t, "m2", event.Node.Report.ModeInfo)
+
+	event = &models.NodeStatsEvent{}
+	assert.NoError(t, conn.ReadJSON(event))
+	assert.Equal(t, models.NodeStatsEventDeleted, event.Type)
+	assert.Nil(t, event.Node)
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// checkResourceFound returns the not found error of the resource if it is missing, whether the storage reports
+// it missing or returns nothing, so that the handlers respond 404 uniformly instead of 200 with null
+func checkResourceFound(res interface{}, err error, typ common.Resource, name string) error {
+	if err != nil {
+		return wrapResourceNotFoundError(err, typ, name)
+	}
+	if isNilResource(res) {
+		return resourceNotFoundError(typ, name)
+	}
+	return nil
+}
+
+// wrapResourceNotFoundError replaces the not found error reported by the storage, which may be a plain error
+// of kubernetes, with the one of the resource type and name, other errors are returned as they are
+func wrapResourceNotFoundError(err error, typ common.Resource, name string) error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(errors.Coder)
+	if (ok && e.Code() == common.ErrResourceNotFound) || (!ok && strings.Contains(err.Error(), "not found")) {
+		return resourceNotFoundError(typ, name)
+	}
+	return err
+}
+
+func resourceNotFoundError(typ common.Resource, name string) error {
+	return common.Error(common.ErrResourceNotFound, common.Field("type", typ), common.Field("name", name))
+}
+
+func isNilResource(res interface{}) bool {
+	if res == nil {
+		return true
+	}
+	v := reflect.ValueOf(res)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
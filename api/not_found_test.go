@@ -0,0 +1,42 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestCheckResourceFound(t *testing.T) {
+	assertNotFound := func(err error, msg string) {
+		assert.Error(t, err)
+		e, ok := err.(errors.Coder)
+		assert.True(t, ok)
+		assert.Equal(t, common.ErrResourceNotFound, e.Code())
+		assert.Contains(t, err.Error(), msg)
+	}
+
+	assert.NoError(t, checkResourceFound(&specV1.Configuration{}, nil, common.Config, "c1"))
+	assert.NoError(t, checkResourceFound([]models.Module{{}}, nil, common.Module, "m1"))
+
+	var cfg *specV1.Configuration
+	assertNotFound(checkResourceFound(cfg, nil, common.Config, "c1"), "The (config) resource (c1) is not found.")
+	assertNotFound(checkResourceFound(nil, nil, common.Secret, "s1"), "The (secret) resource (s1) is not found.")
+
+	// the not found errors are reported with the resource type and name
+	err := common.Error(common.ErrResourceNotFound, common.Field("name", "n1"))
+	assertNotFound(checkResourceFound(nil, err, common.Node, "n1"), "The (node) resource (n1) is not found.")
+	err = fmt.Errorf(`secrets "r1" not found`)
+	assertNotFound(checkResourceFound(nil, err, common.Registry, "r1"), "The (registry) resource (r1) is not found.")
+
+	// other errors are returned as they are
+	err = common.Error(common.ErrRequestParamInvalid, common.Field("error", "invalid"))
+	assert.Equal(t, err, checkResourceFound(nil, err, common.APP, "a1"))
+	err = fmt.Errorf("timeout")
+	assert.Equal(t, err, checkResourceFound(nil, err, common.APP, "a1"))
+}
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/baetyl/baetyl-go/v2/errors"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
@@ -69,10 +72,20 @@ func (api *API) GetObjectPathV2(c *common.Context) (interface{}, error) {
 		return nil, errors.Trace(err)
 	}
 
+	opts, err := api.parseObjectURLOptions(c, false)
+	if err != nil {
+		return nil, err
+	}
+
 	var res *models.ObjectURL
-	if params.Account == OtherAccount {
+	switch {
+	case params.Account == OtherAccount && opts != nil:
+		res, err = api.Obj.PresignExternalObjectURL(params.ExternalObjectInfo, params.Bucket, params.Object, params.Source, opts)
+	case params.Account == OtherAccount:
 		res, err = api.Obj.GenExternalObjectURL(params.ExternalObjectInfo, params.Bucket, params.Object, params.Source)
-	} else {
+	case opts != nil:
+		res, err = api.Obj.PresignInternalObjectURL(c.GetUser().ID, params.Bucket, params.Object, params.Source, opts)
+	default:
 		res, err = api.Obj.GenInternalObjectURL(c.GetUser().ID, params.Bucket, params.Object, params.Source)
 	}
 	if err != nil {
@@ -91,13 +104,132 @@ func (api *API) GetObjectPutPathV2(c *common.Context) (interface{}, error) {
 	if params.Account == OtherAccount {
 		return nil, errors.Trace(common.Error(common.ErrRequestParamInvalid, common.Field("error", "this operation is not allowed")))
 	}
-	res, err = api.Obj.GenInternalObjectPutURL(c.GetUser().ID, params.Bucket, params.Object, params.Source)
+	opts, err := api.parseObjectURLOptions(c, true)
+	if err != nil {
+		return nil, err
+	}
+	if err = api.Obj.CheckObjectUpload(newObjectUpload(c, params, opts)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if opts != nil {
+		res, err = api.Obj.PresignInternalObjectPutURL(c.GetUser().ID, params.Bucket, params.Object, params.Source, opts)
+	} else {
+		res, err = api.Obj.GenInternalObjectPutURL(c.GetUser().ID, params.Bucket, params.Object, params.Source)
+	}
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return res, nil
 }
 
+// CompleteObjectPutV2 is called after the object is uploaded by the put url, the object is removed or quarantined
+// if the upload hook rejects it by its metadata or content, and the verdict is returned
+func (api *API) CompleteObjectPutV2(c *common.Context) (interface{}, error) {
+	params := &models.ObjectRequestParams{}
+	if err := c.ShouldBindQuery(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if params.Account == OtherAccount {
+		return nil, errors.Trace(common.Error(common.ErrRequestParamInvalid, common.Field("error", "this operation is not allowed")))
+	}
+	if params.Object == "" {
+		return nil, errors.Trace(common.Error(common.ErrRequestParamInvalid, common.Field("error", "the parameter 'object' is required")))
+	}
+	params.Source = c.Param("source")
+	params.Bucket = c.Param("bucket")
+	res, err := api.Obj.CompleteObjectUpload(newObjectUpload(c, params, nil))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return res, nil
+}
+
+func newObjectUpload(c *common.Context, params *models.ObjectRequestParams, opts *models.ObjectURLOptions) *models.ObjectUpload {
+	upload := &models.ObjectUpload{
+		UserID: c.GetUser().ID,
+		Source: params.Source,
+		Bucket: params.Bucket,
+		Object: params.Object,
+	}
+	if opts != nil {
+		upload.ContentType = opts.ContentType
+		upload.MaxSize = opts.MaxSize
+	}
+	return upload
+}
+
+// GetBucketLifecycleV2 gets the lifecycle rules of the bucket, which fails with ErrNotSupported if the source has no lifecycle support
+func (api *API) GetBucketLifecycleV2(c *common.Context) (interface{}, error) {
+	params, err := api.parseObject(c)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var res *models.BucketLifecycle
+	if params.Account == OtherAccount {
+		res, err = api.Obj.GetExternalBucketLifecycle(params.ExternalObjectInfo, params.Bucket, params.Source)
+	} else {
+		res, err = api.Obj.GetInternalBucketLifecycle(c.GetUser().ID, params.Bucket, params.Source)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return res, nil
+}
+
+// PutBucketLifecycleV2 replaces the lifecycle rules of the bucket, the rules are removed if empty
+func (api *API) PutBucketLifecycleV2(c *common.Context) (interface{}, error) {
+	params, err := api.parseObject(c)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	lifecycle := &models.BucketLifecycle{}
+	if err = c.LoadBody(lifecycle); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	ids := map[string]bool{}
+	for _, r := range lifecycle.Rules {
+		if ids[r.ID] {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the id (%s) of the rules is duplicated", r.ID)))
+		}
+		ids[r.ID] = true
+	}
+
+	if params.Account == OtherAccount {
+		err = api.Obj.PutExternalBucketLifecycle(params.ExternalObjectInfo, params.Bucket, params.Source, lifecycle)
+	} else {
+		err = api.Obj.PutInternalBucketLifecycle(c.GetUser().ID, params.Bucket, params.Source, lifecycle)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return lifecycle, nil
+}
+
+// parseObjectURLOptions returns nil if no option is given, and the expiry is bounded by the max of the server
+func (api *API) parseObjectURLOptions(c *common.Context, upload bool) (*models.ObjectURLOptions, error) {
+	opts := &models.ObjectURLOptions{}
+	if err := c.Bind(opts); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if *opts == (models.ObjectURLOptions{}) {
+		return nil, nil
+	}
+	if opts.Expire < 0 || opts.MaxSize < 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "expire and maxSize should not be negative"))
+	}
+	if max := int64(api.objectURLMaxExpiration / time.Second); opts.Expire > max {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("expire should not exceed %d seconds", max)))
+	}
+	if upload && opts.ContentDisposition != "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "contentDisposition is only for downloads"))
+	}
+	if !upload && (opts.ContentType != "" || opts.MaxSize != 0) {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "contentType and maxSize are only for uploads"))
+	}
+	return opts, nil
+}
+
 func (api *API) parseObject(c *common.Context) (*models.ObjectRequestParams, error) {
 	params := &models.ObjectRequestParams{}
 	params.ExternalObjectInfo.AddressFormat = PathStyle
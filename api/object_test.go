@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,7 +19,7 @@ import (
 )
 
 func initObjectV2API(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
-	api := &API{}
+	api := &API{objectURLMaxExpiration: time.Hour}
 	router := gin.Default()
 	mockCtl := gomock.NewController(t)
 	mockIM := func(c *gin.Context) {
@@ -33,6 +34,9 @@ func initObjectV2API(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		objects.GET("/:source/buckets/:bucket/objects", mockIM, common.Wrapper(api.ListBucketObjectsV2))
 		objects.GET("/:source/buckets/:bucket/object", mockIM, common.Wrapper(api.GetObjectPathV2))
 		objects.GET("/:source/buckets/:bucket/object/put", mockIM, common.Wrapper(api.GetObjectPutPathV2))
+		objects.POST("/:source/buckets/:bucket/object/complete", mockIM, common.Wrapper(api.CompleteObjectPutV2))
+		objects.GET("/:source/buckets/:bucket/lifecycle", mockIM, common.Wrapper(api.GetBucketLifecycleV2))
+		objects.PUT("/:source/buckets/:bucket/lifecycle", mockIM, common.Wrapper(api.PutBucketLifecycleV2))
 	}
 	return api, router, mockCtl
 }
@@ -276,6 +280,7 @@ func TestGetObjectPutPathV2(t *testing.T) {
 	}
 
 	// 200 internal
+	mkObjectService.EXPECT().CheckObjectUpload(&models.ObjectUpload{UserID: "default", Source: "baidubos", Bucket: "baetyl-test", Object: "abc/abc.json"}).Return(nil).Times(1)
 	mkObjectService.EXPECT().GenInternalObjectPutURL("default", "baetyl-test", "abc/abc.json", "baidubos").Return(object, nil).Times(1)
 	req, _ := http.NewRequest(http.MethodGet, "/v2/objects/baidubos/buckets/baetyl-test/object/put?object=abc%2Fabc.json", nil)
 	w := httptest.NewRecorder()
@@ -288,10 +293,189 @@ func TestGetObjectPutPathV2(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
+	// rejected by the upload hook
+	mkObjectService.EXPECT().CheckObjectUpload(&models.ObjectUpload{UserID: "default", Source: "baidubos", Bucket: "baetyl-test", Object: "abc.exe"}).
+		Return(common.Error(common.ErrObjectRejected, common.Field("name", "abc.exe"), common.Field("reason", "executable"))).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/baidubos/buckets/baetyl-test/object/put?object=abc.exe", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrObjectRejected")
+
 	// 500
+	mkObjectService.EXPECT().CheckObjectUpload(gomock.Any()).Return(nil).Times(1)
 	mkObjectService.EXPECT().GenInternalObjectPutURL("default", "baetyl-test", "abc", "baidubos").Return(nil, errors.New("error")).Times(1)
 	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/baidubos/buckets/baetyl-test/object/put?object=abc", nil)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+func TestGetObjectPathV2WithOptions(t *testing.T) {
+	api, router, mockCtl := initObjectV2API(t)
+	defer mockCtl.Finish()
+	mkObjectService := ms.NewMockObjectService(mockCtl)
+	api.Obj = mkObjectService
+
+	object := &models.ObjectURL{URL: "http://xxx"}
+	opts := &models.ObjectURLOptions{Expire: 600, ContentDisposition: "attachment; filename=abc.json"}
+	mkObjectService.EXPECT().PresignInternalObjectURL("default", "baetyl-test", "abc/abc.json", "awss3", opts).Return(object, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v2/objects/awss3/buckets/baetyl-test/object?object=abc%2Fabc.json&expire=600&contentDisposition=attachment%3B+filename%3Dabc.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	info := models.ExternalObjectInfo{Endpoint: "x", Ak: "xx", Sk: "xxx", AddressFormat: PathStyle}
+	mkObjectService.EXPECT().PresignExternalObjectURL(info, "baetyl-test", "abc", "awss3", &models.ObjectURLOptions{Expire: 60}).Return(object, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/awss3/buckets/baetyl-test/object?object=abc&account=other&endpoint=x&ak=xx&sk=xxx&expire=60", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// exceeds the max
+	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/awss3/buckets/baetyl-test/object?object=abc&expire=3601", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "expire should not exceed 3600 seconds")
+
+	// for uploads only
+	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/awss3/buckets/baetyl-test/object?object=abc&maxSize=10", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/awss3/buckets/baetyl-test/object?object=abc&expire=-1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// not supported by the source
+	mkObjectService.EXPECT().PresignInternalObjectURL("default", "baetyl-test", "abc", "baidubos", &models.ObjectURLOptions{Expire: 60}).
+		Return(nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the source (baidubos) does not support signing the urls with the expiry or constraints"))).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/baidubos/buckets/baetyl-test/object?object=abc&expire=60", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetObjectPutPathV2WithOptions(t *testing.T) {
+	api, router, mockCtl := initObjectV2API(t)
+	defer mockCtl.Finish()
+	mkObjectService := ms.NewMockObjectService(mockCtl)
+	api.Obj = mkObjectService
+
+	object := &models.ObjectURL{URL: "http://xxx", Method: http.MethodPost, Fields: map[string]string{"key": "abc"}}
+	opts := &models.ObjectURLOptions{Expire: 600, ContentType: "application/zip", MaxSize: 1048576}
+	upload := &models.ObjectUpload{UserID: "default", Source: "awss3", Bucket: "baetyl-test", Object: "abc", ContentType: "application/zip", MaxSize: 1048576}
+	mkObjectService.EXPECT().CheckObjectUpload(upload).Return(nil).Times(1)
+	mkObjectService.EXPECT().PresignInternalObjectPutURL("default", "baetyl-test", "abc", "awss3", opts).Return(object, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v2/objects/awss3/buckets/baetyl-test/object/put?object=abc&expire=600&contentType=application%2Fzip&maxSize=1048576", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.ObjectURL{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, object, res)
+
+	// for downloads only
+	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/awss3/buckets/baetyl-test/object/put?object=abc&contentDisposition=attachment", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/awss3/buckets/baetyl-test/object/put?object=abc&maxSize=abc", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCompleteObjectPutV2(t *testing.T) {
+	api, router, mockCtl := initObjectV2API(t)
+	defer mockCtl.Finish()
+	mkObjectService := ms.NewMockObjectService(mockCtl)
+	api.Obj = mkObjectService
+
+	verdict := &models.ObjectUploadVerdict{Action: models.ObjectUploadQuarantine, Reason: "virus", Location: "quarantine/abc.zip"}
+	upload := &models.ObjectUpload{UserID: "default", Source: "awss3", Bucket: "baetyl-test", Object: "abc.zip"}
+	mkObjectService.EXPECT().CompleteObjectUpload(upload).Return(verdict, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodPost, "/v2/objects/awss3/buckets/baetyl-test/object/complete?object=abc.zip", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.ObjectUploadVerdict{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, verdict, res)
+
+	req, _ = http.NewRequest(http.MethodPost, "/v2/objects/awss3/buckets/baetyl-test/object/complete", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/v2/objects/awss3/buckets/baetyl-test/object/complete?object=abc&account=other", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mkObjectService.EXPECT().CompleteObjectUpload(gomock.Any()).Return(nil, errors.New("error")).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v2/objects/awss3/buckets/baetyl-test/object/complete?object=abc", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestBucketLifecycleV2(t *testing.T) {
+	api, router, mockCtl := initObjectV2API(t)
+	defer mockCtl.Finish()
+	mkObjectService := ms.NewMockObjectService(mockCtl)
+	api.Obj = mkObjectService
+
+	lifecycle := &models.BucketLifecycle{Rules: []models.BucketLifecycleRule{{ID: "uploads", Prefix: "uploads/", Enabled: true, ExpirationDays: 30}}}
+	mkObjectService.EXPECT().GetInternalBucketLifecycle("default", "baetyl-test", "awss3").Return(lifecycle, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v2/objects/awss3/buckets/baetyl-test/lifecycle", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.BucketLifecycle{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, lifecycle, res)
+
+	info := models.ExternalObjectInfo{Endpoint: "x", Ak: "xx", Sk: "xxx", AddressFormat: PathStyle}
+	mkObjectService.EXPECT().GetExternalBucketLifecycle(info, "baetyl-test", "awss3").Return(lifecycle, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/awss3/buckets/baetyl-test/lifecycle?account=other&endpoint=x&ak=xx&sk=xxx", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// not supported by the source
+	mkObjectService.EXPECT().GetInternalBucketLifecycle("default", "baetyl-test", "baidubos").
+		Return(nil, common.Error(common.ErrNotSupported, common.Field("operation", "bucket lifecycle"), common.Field("source", "baidubos"))).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v2/objects/baidubos/buckets/baetyl-test/lifecycle", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrNotSupported")
+
+	put := func(path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPut, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	mkObjectService.EXPECT().PutInternalBucketLifecycle("default", "baetyl-test", "awss3", lifecycle).Return(nil).Times(1)
+	w = put("/v2/objects/awss3/buckets/baetyl-test/lifecycle", `{"rules":[{"id":"uploads","prefix":"uploads/","enabled":true,"expirationDays":30}]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the rules are removed
+	mkObjectService.EXPECT().PutExternalBucketLifecycle(info, "baetyl-test", "awss3", &models.BucketLifecycle{Rules: []models.BucketLifecycleRule{}}).Return(nil).Times(1)
+	w = put("/v2/objects/awss3/buckets/baetyl-test/lifecycle?account=other&endpoint=x&ak=xx&sk=xxx", `{"rules":[]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = put("/v2/objects/awss3/buckets/baetyl-test/lifecycle", `{"rules":[{"id":"a","expirationDays":0}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = put("/v2/objects/awss3/buckets/baetyl-test/lifecycle", `{"rules":[{"expirationDays":1}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = put("/v2/objects/awss3/buckets/baetyl-test/lifecycle", `{"rules":[{"id":"a","expirationDays":1},{"id":"a","expirationDays":2}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the id (a) of the rules is duplicated")
+}
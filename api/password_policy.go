@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+// checkPassword checks the password against the policy, the violations are returned without the password
+func checkPassword(policy config.PasswordPolicy, field, password string) error {
+	if !policy.Enable {
+		return nil
+	}
+	var violations []string
+	if n := len([]rune(password)); n < policy.MinLength {
+		violations = append(violations, fmt.Sprintf("at least %d characters are required", policy.MinLength))
+	}
+	if n := passwordClasses(password); n < policy.MinClasses {
+		violations = append(violations, fmt.Sprintf("at least %d of the upper case letters, the lower case letters, the digits and the other characters are required", policy.MinClasses))
+	}
+	for _, v := range policy.Forbidden {
+		if strings.EqualFold(v, password) {
+			violations = append(violations, "the value is forbidden")
+			break
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return common.Error(common.ErrRequestParamInvalid,
+		common.Field("error", fmt.Sprintf("the %s violates the password policy: %s", field, strings.Join(violations, "; "))))
+}
+
+// passwordClasses counts the classes of the characters of the password
+func passwordClasses(password string) int {
+	var upper, lower, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return upper + lower + digit + other
+}
+
+// checkSecretPasswords checks the values of the secret taken as passwords by their keys, the values unchanged
+// from the old ones are not checked, so that the secrets saved before the policy can still be updated
+func checkSecretPasswords(policy config.PasswordPolicy, data, old map[string]string) error {
+	if !policy.Enable {
+		return nil
+	}
+	var keys []string
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if v, ok := old[k]; ok && v == data[k] {
+			continue
+		}
+		if !isPasswordKey(policy.SecretKeys, k) {
+			continue
+		}
+		if err := checkPassword(policy, fmt.Sprintf("value of the key (%s)", k), data[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isPasswordKey(keys []string, key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range keys {
+		if k != "" && strings.Contains(key, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
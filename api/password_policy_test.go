@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestCheckPassword(t *testing.T) {
+	policy := config.PasswordPolicy{
+		Enable:     true,
+		MinLength:  8,
+		MinClasses: 3,
+		Forbidden:  []string{"Password1!"},
+		SecretKeys: []string{"password", "token"},
+	}
+	assert.NoError(t, checkPassword(policy, "password", "Abcdef12"))
+	assert.NoError(t, checkPassword(policy, "password", "abc-def-12"))
+
+	err := checkPassword(policy, "password", "abc")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the password violates the password policy: at least 8 characters are required; at least 3 of")
+	assert.NotContains(t, err.Error(), "abc ")
+
+	err = checkPassword(policy, "password", "abcdefgh")
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "at least 8 characters")
+	assert.Contains(t, err.Error(), "at least 3 of")
+
+	err = checkPassword(policy, "password", "PASSWORD1!")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the value is forbidden")
+	assert.NotContains(t, err.Error(), "PASSWORD1!")
+
+	policy.Enable = false
+	assert.NoError(t, checkPassword(policy, "password", "abc"))
+}
+
+func TestCheckSecretPasswords(t *testing.T) {
+	policy := config.PasswordPolicy{Enable: true, MinLength: 8, MinClasses: 3, SecretKeys: []string{"password", "token"}}
+	assert.NoError(t, checkSecretPasswords(policy, map[string]string{"user": "a", "DB_Password": "Abcdef12"}, nil))
+
+	err := checkSecretPasswords(policy, map[string]string{"user": "a", "api_token": "weak"}, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the value of the key (api_token) violates the password policy")
+
+	// the values unchanged are not checked
+	assert.NoError(t, checkSecretPasswords(policy, map[string]string{"api_token": "weak", "user": "b"}, map[string]string{"api_token": "weak"}))
+	assert.Error(t, checkSecretPasswords(policy, map[string]string{"api_token": "weak2"}, map[string]string{"api_token": "weak"}))
+}
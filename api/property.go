@@ -6,7 +6,12 @@ import (
 )
 
 func (api *API) GetProperty(c *common.Context) (interface{}, error) {
-	return api.Prop.GetProperty(c.Param("name"))
+	name := c.Param("name")
+	property, err := api.Prop.GetProperty(name)
+	if err = checkResourceFound(property, err, common.Property, name); err != nil {
+		return nil, err
+	}
+	return property, nil
 }
 
 func (api *API) CreateProperty(c *common.Context) (interface{}, error) {
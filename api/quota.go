@@ -1,17 +1,208 @@
 package api
 
 import (
+	"bytes"
+	"io"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
 	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
-// GetQuota  for admin api
+// GetQuota  for admin api, returns the limit and usage of every tracked resource type
 func (api *API) GetQuota(c *common.Context) (interface{}, error) {
-	ns := c.GetNamespace()
+	return api.quotaUsages(c.GetNamespace())
+}
+
+// GetQuotaUsage for admin api, returns the limit and usage of every tracked resource type as GetQuota does
+func (api *API) GetQuotaUsage(c *common.Context) (interface{}, error) {
+	return api.quotaUsages(c.GetNamespace())
+}
+
+// quotaUsages returns the limits and the usages of the quotas, along with the usages of the resources without limits
+func (api *API) quotaUsages(ns string) (map[string]models.QuotaUsage, error) {
 	quotas, err := api.Quota.GetQuota(ns)
-	return quotas, err
+	if err != nil {
+		return nil, err
+	}
+	usages, err := api.Quota.CollectUsage(ns, api.QuotaCollectors()...)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]models.QuotaUsage{}
+	for k, v := range quotas {
+		res[k] = models.QuotaUsage{Limit: v, Used: usages[k]}
+	}
+	for k, v := range usages {
+		if _, ok := res[k]; !ok {
+			res[k] = models.QuotaUsage{Used: v}
+		}
+	}
+	return res, nil
+}
+
+// QuotaCollectors returns the collectors of all the resource types limited by quota
+func (api *API) QuotaCollectors() []plugin.QuotaCollector {
+	return []plugin.QuotaCollector{
+		api.NodeNumberCollector,
+		api.AppNumberCollector,
+		api.ConfigNumberCollector,
+		api.StorageSizeCollector,
+	}
+}
+
+// AppNumberCollector counts the apps created by user
+func (api *API) AppNumberCollector(namespace string) (map[string]int, error) {
+	list, err := api.App.List(namespace, &models.ListOptions{LabelSelector: "!" + common.LabelSystem})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int{
+		plugin.QuotaApp: list.Total,
+	}, nil
+}
+
+// ConfigNumberCollector counts the configs created by user
+func (api *API) ConfigNumberCollector(namespace string) (map[string]int, error) {
+	list, err := api.Config.List(namespace, &models.ListOptions{LabelSelector: "!" + common.LabelSystem})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int{
+		plugin.QuotaConfig: list.Total,
+	}, nil
+}
+
+// StorageSizeCollector sums the bytes of the data of the configs and secrets created by user
+func (api *API) StorageSizeCollector(namespace string) (map[string]int, error) {
+	opts := &models.ListOptions{LabelSelector: "!" + common.LabelSystem}
+	configs, err := api.Config.List(namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := api.Secret.List(namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+	size := 0
+	for i := range configs.Items {
+		size += configStorageSize(&configs.Items[i])
+	}
+	for i := range secrets.Items {
+		size += secretStorageSize(&secrets.Items[i])
+	}
+	return map[string]int{
+		plugin.QuotaStorage: size,
+	}, nil
+}
+
+// CheckStorageQuota checks that the total bytes of the configs and secrets stay within the storage quota
+// after the config or secret in the request body is saved, the bytes of the one replaced are not counted
+func (api *API) CheckStorageQuota(c *common.Context, resource common.Resource) error {
+	ns := c.GetNamespace()
+	limits, err := api.Quota.GetQuota(ns)
+	if err != nil {
+		return err
+	}
+	limit := limits[plugin.QuotaStorage]
+	if limit <= 0 {
+		return nil
+	}
+	requested, err := requestStorageSize(c, resource)
+	if err != nil {
+		return err
+	}
+	usages, err := api.StorageSizeCollector(ns)
+	if err != nil {
+		return err
+	}
+	used := usages[plugin.QuotaStorage]
+	replaced := 0
+	if name := c.GetNameFromParam(); name != "" {
+		if replaced, err = api.storedSize(ns, name, resource); err != nil {
+			return err
+		}
+	}
+	if used-replaced+requested > limit {
+		return &common.QuotaError{Name: plugin.QuotaStorage, Limit: limit, Used: used, Requested: requested}
+	}
+	return nil
+}
+
+// storedSize returns the bytes of the stored config or secret, 0 if it doesn't exist
+func (api *API) storedSize(namespace, name string, resource common.Resource) (int, error) {
+	var size int
+	var err error
+	switch resource {
+	case common.Config:
+		var cfg *specV1.Configuration
+		if cfg, err = api.Config.Get(nil, namespace, name, ""); err == nil && cfg != nil {
+			size = configStorageSize(cfg)
+		}
+	default:
+		var secret *specV1.Secret
+		if secret, err = api.Secret.Get(namespace, name, ""); err == nil && secret != nil {
+			size = secretStorageSize(secret)
+		}
+	}
+	if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+		return 0, nil
+	}
+	return size, err
+}
+
+// requestStorageSize estimates the bytes of the config or secret in the request body, the body is kept for the handler
+func requestStorageSize(c *common.Context, resource common.Resource) (int, error) {
+	buf, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return 0, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(buf))
+
+	size := 0
+	switch resource {
+	case common.Config:
+		view := &models.ConfigurationView{}
+		if err = json.Unmarshal(buf, view); err != nil {
+			return 0, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+		for _, item := range view.Data {
+			size += len(item.Key)
+			for _, v := range item.Value {
+				size += len(v)
+			}
+		}
+	default:
+		view := &models.SecretView{}
+		if err = json.Unmarshal(buf, view); err != nil {
+			return 0, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+		for k, v := range view.Data {
+			size += len(k) + len(v)
+		}
+	}
+	return size, nil
+}
+
+func configStorageSize(cfg *specV1.Configuration) int {
+	size := 0
+	for k, v := range cfg.Data {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+func secretStorageSize(secret *specV1.Secret) int {
+	size := 0
+	for k, v := range secret.Data {
+		size += len(k) + len(v)
+	}
+	return size
 }
 
 // GetQuota for mis server api
@@ -9,13 +9,16 @@ import (
 	"testing"
 
 	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 var namespace = "default"
@@ -29,6 +32,7 @@ func initQuotaAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 	{
 		quota := v1.Group("/quotas")
 		quota.GET("", mockIM, common.Wrapper(api.GetQuota))
+		quota.GET("/usage", mockIM, common.Wrapper(api.GetQuotaUsage))
 
 		quota.POST("", common.WrapperMis(api.CreateQuota))
 		quota.DELETE("", common.WrapperMis(api.DeleteQuota))
@@ -48,21 +52,72 @@ func TestAPI_GetQuota(t *testing.T) {
 
 	quotas := map[string]int{
 		"maxNodeCount": 10,
+		"maxAppCount":  20,
+	}
+	usages := map[string]int{
+		"maxNodeCount":   1,
+		"maxAppCount":    2,
+		"maxConfigCount": 3,
+	}
+
+	expect := map[string]models.QuotaUsage{
+		"maxNodeCount":   {Limit: 10, Used: 1},
+		"maxAppCount":    {Limit: 20, Used: 2},
+		"maxConfigCount": {Limit: 0, Used: 3},
+	}
+	// 200 the same limits and usages are served by both routes
+	for _, path := range []string{"/v1/quotas", "/v1/quotas/usage"} {
+		mQuota.EXPECT().GetQuota(namespace).Return(quotas, nil)
+		mQuota.EXPECT().CollectUsage(namespace, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(usages, nil)
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		result, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+		actual := map[string]models.QuotaUsage{}
+		err = json.Unmarshal(result, &actual)
+		assert.NoError(t, err)
+		assert.Equal(t, expect, actual)
 	}
 
 	mQuota.EXPECT().GetQuota(namespace).Return(quotas, nil)
-	// 200
+	mQuota.EXPECT().CollectUsage(namespace, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("collect error"))
 	req, _ := http.NewRequest(http.MethodGet, "/v1/quotas", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
 
-	result, err := ioutil.ReadAll(w.Body)
+func TestAPI_QuotaCollectors(t *testing.T) {
+	api, _, mockCtl := initQuotaAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+	}
+	assert.Len(t, api.QuotaCollectors(), 4)
+
+	selector := &models.ListOptions{LabelSelector: "!" + common.LabelSystem}
+	sApp.EXPECT().List(namespace, selector).Return(&models.ApplicationList{Total: 2}, nil)
+	counts, err := api.AppNumberCollector(namespace)
 	assert.NoError(t, err)
-	actual := map[string]int{}
-	err = json.Unmarshal(result, &actual)
+	assert.Equal(t, map[string]int{plugin.QuotaApp: 2}, counts)
+
+	sConfig.EXPECT().List(namespace, selector).Return(&models.ConfigurationList{Total: 3}, nil)
+	counts, err = api.ConfigNumberCollector(namespace)
 	assert.NoError(t, err)
-	assert.Equal(t, quotas, actual)
+	assert.Equal(t, map[string]int{plugin.QuotaConfig: 3}, counts)
+
+	sApp.EXPECT().List(namespace, selector).Return(nil, fmt.Errorf("list error"))
+	_, err = api.AppNumberCollector(namespace)
+	assert.Error(t, err)
 }
 
 func TestAPI_UpdateQuota(t *testing.T) {
@@ -209,3 +264,76 @@ func TestAPI_RealseNodeQuota(t *testing.T) {
 	err = api.ReleaseQuota(ns, plugin.QuotaNode, number)
 	assert.NoError(t, err)
 }
+
+func TestAPI_StorageQuota(t *testing.T) {
+	api, _, mockCtl := initQuotaAPI(t)
+	defer mockCtl.Finish()
+
+	mQuota := ms.NewMockQuotaService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.Quota = mQuota
+	api.AppCombinedService = &service.AppCombinedService{
+		Config: sConfig,
+		Secret: sSecret,
+	}
+
+	selector := &models.ListOptions{LabelSelector: "!" + common.LabelSystem}
+	configs := &models.ConfigurationList{Items: []specV1.Configuration{
+		{Name: "c1", Data: map[string]string{"k1": "12345678"}},
+	}}
+	secrets := &models.SecretList{Items: []specV1.Secret{
+		{Name: "s1", Data: map[string][]byte{"k2": []byte("12345678")}},
+	}}
+	sConfig.EXPECT().List(namespace, selector).Return(configs, nil).AnyTimes()
+	sSecret.EXPECT().List(namespace, selector).Return(secrets, nil).AnyTimes()
+
+	counts, err := api.StorageSizeCollector(namespace)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{plugin.QuotaStorage: 20}, counts)
+
+	newContext := func(method, name string, body interface{}) *common.Context {
+		data, _ := json.Marshal(body)
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(method, "/v1/configs", bytes.NewReader(data))
+		if name != "" {
+			c.Params = gin.Params{{Key: "name", Value: name}}
+		}
+		cc := common.NewContext(c)
+		cc.SetNamespace(namespace)
+		return cc
+	}
+	config := &models.ConfigurationView{Name: "c2", Data: []models.ConfigDataItem{
+		{Key: "k3", Value: map[string]string{"value": "12345678"}},
+	}}
+
+	// no storage quota
+	mQuota.EXPECT().GetQuota(namespace).Return(map[string]int{plugin.QuotaApp: 10}, nil)
+	assert.NoError(t, api.CheckStorageQuota(newContext(http.MethodPost, "", config), common.Config))
+
+	// the body is kept for the handler
+	mQuota.EXPECT().GetQuota(namespace).Return(map[string]int{plugin.QuotaStorage: 30}, nil)
+	cc := newContext(http.MethodPost, "", config)
+	assert.NoError(t, api.CheckStorageQuota(cc, common.Config))
+	view := &models.ConfigurationView{}
+	assert.NoError(t, cc.LoadBody(view))
+	assert.Equal(t, "c2", view.Name)
+
+	mQuota.EXPECT().GetQuota(namespace).Return(map[string]int{plugin.QuotaStorage: 25}, nil)
+	err = api.CheckStorageQuota(newContext(http.MethodPost, "", config), common.Config)
+	assert.Error(t, err)
+	assert.Equal(t, "Check (maxStorageBytes) quota failed, the limited number is (25), the used number is (20) and the requested number is (10)", err.Error())
+
+	// the bytes of the secret replaced are released
+	secret := &models.SecretView{Name: "s1", Data: map[string]string{"k2": "1234567890123"}}
+	mQuota.EXPECT().GetQuota(namespace).Return(map[string]int{plugin.QuotaStorage: 25}, nil)
+	sSecret.EXPECT().Get(namespace, "s1", "").Return(&secrets.Items[0], nil)
+	assert.NoError(t, api.CheckStorageQuota(newContext(http.MethodPut, "s1", secret), common.Secret))
+
+	mQuota.EXPECT().GetQuota(namespace).Return(map[string]int{plugin.QuotaStorage: 24}, nil)
+	sSecret.EXPECT().Get(namespace, "s1", "").Return(&secrets.Items[0], nil)
+	assert.Error(t, api.CheckStorageQuota(newContext(http.MethodPut, "s1", secret), common.Secret))
+
+	mQuota.EXPECT().GetQuota(namespace).Return(nil, fmt.Errorf("quota error"))
+	assert.EqualError(t, api.CheckStorageQuota(newContext(http.MethodPost, "", config), common.Config), "quota error")
+}
@@ -1,28 +1,54 @@
 package api
 
 import (
+	"context"
+	"database/sql/driver"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
+// RegistryVerifyTimeout the bound of the whole verification of a registry
+const RegistryVerifyTimeout = 10 * time.Second
+
+// newRegistryClient returns the client verifying the registries, which never dials the loopback and link-local
+// addresses, nor the private ones unless allowed, neither of the registry nor of the realm it redirects the token requests to
+var newRegistryClient = func(timeout time.Duration, allowPrivate bool) *http.Client {
+	return common.NewGuardedHTTPClient(timeout, allowPrivate)
+}
+
 // TODO: optimize this layer, general abstraction
 
 // GetRegistry get a Registry
 func (api *API) GetRegistry(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
 	secret, err := api.Secret.Get(ns, n, "")
-	if err != nil {
-		return nil, wrapSecretLikedResourceNotFoundError(n, common.Registry, err)
+	if err = checkResourceFound(secret, err, common.Registry, n); err != nil {
+		return nil, err
 	}
 
-	return hidePwd(api.ToRegistryView(secret)), nil
+	registry := hidePwd(api.ToRegistryView(secret))
+	if registry != nil {
+		refresh, err := api.Registry.GetRefresh(ns, n)
+		if err != nil {
+			if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+				return nil, err
+			}
+		}
+		registry.LastRefresh = refresh
+	}
+	return registry, nil
 }
 
 // ListRegistry list Registry
@@ -63,6 +89,9 @@ func (api *API) CreateRegistry(c *common.Context) (interface{}, error) {
 	if err = api.ValidateRegistryModel(cfg); err != nil {
 		return nil, err
 	}
+	if err = checkPassword(api.passwordPolicy, "password", cfg.Password); err != nil {
+		return nil, err
+	}
 	secret, err := api.Facade.CreateSecret(ns, cfg.ToSecret())
 	if err != nil {
 		return nil, err
@@ -93,22 +122,29 @@ func (api *API) UpdateRegistry(c *common.Context) (interface{}, error) {
 	if err = api.ValidateRegistryModel(sd); err != nil {
 		return nil, err
 	}
-	secret, err = api.Secret.Update(ns, sd.ToSecret())
+	secret, err = api.Secret.Update(nil, ns, sd.ToSecret())
 	if err != nil {
 		return nil, err
 	}
 	return hidePwd(api.ToRegistryView(secret)), nil
 }
 
+// RefreshRegistryPassword replaces the password of the registry, the transient failures are retried with backoff,
+// and the new credentials are checked against the registry before being saved if verify=true.
+// The result of the refresh is kept and returned along with the registry
 func (api *API) RefreshRegistryPassword(c *common.Context) (interface{}, error) {
 	cfg, err := api.parseAndCheckRegistryModel(c)
 	if err != nil {
 		return nil, err
 	}
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
-	secret, err := api.Secret.Get(ns, n, "")
+	var secret *specV1.Secret
+	attempts, err := api.retryRegistryRefresh(func() (err error) {
+		secret, err = api.Secret.Get(ns, n, "")
+		return err
+	})
 	if err != nil {
-		return nil, wrapSecretLikedResourceNotFoundError(n, common.Registry, err)
+		return nil, registryRefreshError(n, wrapSecretLikedResourceNotFoundError(n, common.Registry, err), attempts)
 	}
 
 	sd := api.ToRegistryView(secret)
@@ -117,18 +153,125 @@ func (api *API) RefreshRegistryPassword(c *common.Context) (interface{}, error)
 	if err = api.ValidateRegistryModel(sd); err != nil {
 		return nil, err
 	}
+	if err = checkPassword(api.passwordPolicy, "password", sd.Password); err != nil {
+		return nil, err
+	}
 
-	secret, err = api.Facade.UpdateSecret(ns, sd.ToSecret())
+	if c.Query("verify") == "true" {
+		if err = checkRegistryCredentials(sd, api.registryAllowPrivate); err != nil {
+			api.saveRegistryRefresh(ns, n, 1, err)
+			return nil, err
+		}
+	}
+	attempts, err = api.retryRegistryRefresh(func() (err error) {
+		secret, err = api.Facade.UpdateSecret(ns, sd.ToSecret())
+		return err
+	})
+	api.saveRegistryRefresh(ns, n, attempts, err)
 	if err != nil {
-		return nil, err
+		return nil, registryRefreshError(n, err, attempts)
 	}
 	return hidePwd(api.ToRegistryView(secret)), nil
 }
 
+// retryRegistryRefresh calls the function until it succeeds or fails permanently, the temporary failures are retried
+// with the backoff doubled each time, returns the number of the attempts made
+func (api *API) retryRegistryRefresh(f func() error) (int, error) {
+	backoff := api.registryRefresh.Backoff
+	attempts := 0
+	for {
+		attempts++
+		err := f()
+		if err == nil || !isTemporaryError(err) || attempts >= api.registryRefresh.Attempts {
+			return attempts, err
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; api.registryRefresh.MaxBackoff > 0 && backoff > api.registryRefresh.MaxBackoff {
+			backoff = api.registryRefresh.MaxBackoff
+		}
+	}
+}
+
+// saveRegistryRefresh keeps the result of the refresh, which doesn't fail the refresh
+func (api *API) saveRegistryRefresh(ns, name string, attempts int, err error) {
+	refresh := &models.RegistryRefresh{
+		Namespace: ns,
+		Name:      name,
+		Result:    models.RegistryRefreshSucceeded,
+		Attempts:  attempts,
+		Time:      time.Now().UTC(),
+	}
+	if err != nil {
+		refresh.Message = err.Error()
+		refresh.Result = models.RegistryRefreshFailed
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrRegistryAuthFailed {
+			refresh.Result = models.RegistryRefreshAuthFailure
+		} else if isTemporaryError(err) {
+			refresh.Result = models.RegistryRefreshTemporaryFailure
+		}
+	}
+	if err = api.Registry.SaveRefresh(refresh); err != nil {
+		api.log.Warn("failed to save the result of the registry refresh",
+			log.Any("namespace", ns), log.Any("name", name), log.Error(err))
+	}
+}
+
+// registryRefreshError reports the temporary failures left after the retries as ErrTemporaryFailure, so they can be told
+// from the permanent ones by the clients
+func registryRefreshError(name string, err error, attempts int) error {
+	if !isTemporaryError(err) {
+		return err
+	}
+	return common.Error(common.ErrTemporaryFailure,
+		common.Field("error", fmt.Sprintf("failed to refresh the registry (%s) after %d attempts: %s", name, attempts, err.Error())))
+}
+
+// checkRegistryCredentials checks the credentials against the registry, the rejected credentials are reported as
+// ErrRegistryAuthFailed, and the registry unreachable or failing as ErrTemporaryFailure
+func checkRegistryCredentials(r *models.Registry, allowPrivate bool) error {
+	res := verifyRegistry(r, RegistryVerifyTimeout, allowPrivate)
+	switch {
+	case res.Authenticated:
+		return nil
+	case res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden:
+		return common.Error(common.ErrRegistryAuthFailed, common.Field("name", r.Name), common.Field("error", res.Message))
+	}
+	return common.Error(common.ErrTemporaryFailure, common.Field("error", res.Message))
+}
+
+// isTemporaryError returns whether the error is transient, such as timeouts, throttling, conflicts and the unavailability
+// of the storage, which may succeed if retried. The errors unknown are treated as permanent
+func isTemporaryError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if e, ok := err.(errors.Coder); ok {
+		return e.Code() == common.ErrTemporaryFailure
+	}
+	cause := errors.Cause(err)
+	if apierrors.IsTimeout(cause) || apierrors.IsServerTimeout(cause) || apierrors.IsTooManyRequests(cause) ||
+		apierrors.IsServiceUnavailable(cause) || apierrors.IsInternalError(cause) || apierrors.IsConflict(cause) {
+		return true
+	}
+	if cause == context.DeadlineExceeded || cause == driver.ErrBadConn {
+		return true
+	}
+	ne, ok := cause.(net.Error)
+	return ok && ne.Timeout()
+}
+
 // DeleteRegistry delete the Registry
 func (api *API) DeleteRegistry(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
-	return api.DeleteSecretResource(ns, n, "registry")
+	res, err := api.DeleteSecretResource(ns, n, "registry")
+	if err != nil {
+		return nil, err
+	}
+	if err = api.Registry.DeleteRefresh(ns, n); err != nil {
+		api.log.Warn("failed to delete the result of the registry refresh",
+			log.Any("namespace", ns), log.Any("name", n), log.Error(err))
+	}
+	return res, nil
 }
 
 // GetAppByRegistry list app
@@ -141,6 +284,144 @@ func (api *API) GetAppByRegistry(c *common.Context) (interface{}, error) {
 	return api.listAppBySecret(ns, secret.Name)
 }
 
+// VerifyRegistry ping the registry by docker registry v2 api with the stored credentials,
+// the registries of the internal addresses are reported unreachable
+func (api *API) VerifyRegistry(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	secret, err := api.Secret.Get(ns, n, "")
+	if err != nil {
+		return nil, wrapSecretLikedResourceNotFoundError(n, common.Registry, err)
+	}
+	registry := api.ToFilteredRegistryView(secret)
+	if registry == nil {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", common.Registry), common.Field("name", n))
+	}
+	return verifyRegistry(registry, RegistryVerifyTimeout, api.registryAllowPrivate), nil
+}
+
+func verifyRegistry(r *models.Registry, timeout time.Duration, allowPrivate bool) *models.RegistryVerification {
+	res := &models.RegistryVerification{
+		Name:    r.Name,
+		Address: r.Address,
+	}
+	cli := newRegistryClient(timeout, allowPrivate)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ping := registryBaseURL(r.Address) + "/v2/"
+	resp, err := registryGet(ctx, cli, ping, nil)
+	if err != nil {
+		res.Message = err.Error()
+		return res
+	}
+	res.Reachable = true
+	res.StatusCode = resp.StatusCode
+	if resp.StatusCode == http.StatusOK {
+		res.Authenticated = true
+		return res
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		res.Message = "unexpected response of registry: " + resp.Status
+		return res
+	}
+
+	scheme, params := parseAuthenticateHeader(resp.Header.Get("WWW-Authenticate"))
+	switch strings.ToLower(scheme) {
+	case "basic":
+		resp, err = registryGet(ctx, cli, ping, func(req *http.Request) {
+			req.SetBasicAuth(r.Username, r.Password)
+		})
+	case "bearer":
+		realm, perr := url.Parse(params["realm"])
+		if perr != nil || realm.Host == "" {
+			res.Message = "invalid token realm of registry"
+			return res
+		}
+		if perr = checkTokenRealm(realm, r.Address); perr != nil {
+			res.Message = perr.Error()
+			return res
+		}
+		q := realm.Query()
+		if v, ok := params["service"]; ok {
+			q.Set("service", v)
+		}
+		realm.RawQuery = q.Encode()
+		resp, err = registryGet(ctx, cli, realm.String(), func(req *http.Request) {
+			req.SetBasicAuth(r.Username, r.Password)
+		})
+	default:
+		res.Message = "unsupported authentication scheme of registry: " + scheme
+		return res
+	}
+	if err != nil {
+		res.Message = err.Error()
+		return res
+	}
+	res.StatusCode = resp.StatusCode
+	if resp.StatusCode == http.StatusOK {
+		res.Authenticated = true
+		return res
+	}
+	res.Message = "failed to authenticate with the stored credentials: " + resp.Status
+	return res
+}
+
+// registryGet send a request to the registry, only the status and headers of the response are kept
+func registryGet(ctx context.Context, cli *http.Client, address string, decorate func(*http.Request)) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if decorate != nil {
+		decorate(req)
+	}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// checkTokenRealm the credentials of the registry are sent to the token realm over https only,
+// unless the registry itself is served over http
+func checkTokenRealm(realm *url.URL, address string) error {
+	if strings.EqualFold(realm.Scheme, "https") {
+		return nil
+	}
+	if strings.EqualFold(realm.Scheme, "http") && strings.HasPrefix(registryBaseURL(address), "http://") {
+		return nil
+	}
+	return errors.New("the token realm of registry should be served over https")
+}
+
+func registryBaseURL(address string) string {
+	address = strings.TrimSuffix(address, "/")
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "https://" + address
+	}
+	switch address {
+	case "https://docker.io", "https://index.docker.io":
+		return "https://registry-1.docker.io"
+	}
+	return address
+}
+
+// parseAuthenticateHeader parse the challenge such as: Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseAuthenticateHeader(header string) (string, map[string]string) {
+	params := map[string]string{}
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	if len(parts) == 2 {
+		for _, kv := range strings.Split(parts[1], ",") {
+			pair := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+			if len(pair) == 2 {
+				params[strings.ToLower(pair[0])] = strings.Trim(pair[1], `"`)
+			}
+		}
+	}
+	return parts[0], params
+}
+
 // parseAndCheckRegistryModel parse and check the config model
 func (api *API) parseAndCheckRegistryModel(c *common.Context) (*models.Registry, error) {
 	registry := new(models.Registry)
@@ -199,12 +480,5 @@ func (api *API) ValidateRegistryModel(r *models.Registry) error {
 }
 
 func wrapSecretLikedResourceNotFoundError(name string, secretType common.Resource, err error) error {
-	if err != nil {
-		e, ok := err.(errors.Coder)
-		if (ok && e.Code() == common.ErrResourceNotFound) || (!ok && strings.Contains(err.Error(), "not found")) {
-			return common.Error(common.ErrResourceNotFound, common.Field("type", secretType),
-				common.Field("name", name))
-		}
-	}
-	return err
+	return wrapResourceNotFoundError(err, secretType, name)
 }
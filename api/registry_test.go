@@ -2,19 +2,27 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"database/sql/driver"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
 	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
@@ -24,7 +32,7 @@ import (
 // TODO: optimize this layer, general abstraction
 
 func initRegistryAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
-	api := &API{}
+	api := &API{log: log.L(), registryRefresh: config.Retry{Attempts: 3, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}}
 	router := gin.Default()
 	mockCtl := gomock.NewController(t)
 	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
@@ -35,6 +43,7 @@ func initRegistryAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		configs.GET("/:name/apps", mockIM, common.Wrapper(api.GetAppByRegistry))
 		configs.PUT("/:name", mockIM, common.Wrapper(api.UpdateRegistry))
 		configs.POST(":name/refresh", mockIM, common.Wrapper(api.RefreshRegistryPassword))
+		configs.POST("/:name/verify", mockIM, common.Wrapper(api.VerifyRegistry))
 		configs.DELETE("/:name", mockIM, common.Wrapper(api.DeleteRegistry))
 		configs.POST("", mockIM, common.Wrapper(api.CreateRegistry))
 		configs.GET("", mockIM, common.Wrapper(api.ListRegistry))
@@ -65,14 +74,42 @@ func TestGetRegistry(t *testing.T) {
 		},
 	}
 
-	sSecret.EXPECT().Get(mConf.Namespace, mConf.Name, "").Return(mConf2, nil)
+	sRegistry := ms.NewMockRegistryService(mockCtl)
+	api.Registry = sRegistry
+
+	sSecret.EXPECT().Get(mConf.Namespace, mConf.Name, "").Return(mConf2, nil).Times(2)
 	sSecret.EXPECT().Get(mConf.Namespace, "cba", "").Return(nil, fmt.Errorf("error"))
+	sRegistry.EXPECT().GetRefresh(mConf.Namespace, mConf.Name).Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
 
 	// 200
 	req, _ := http.NewRequest(http.MethodGet, "/v1/registries/abc", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "lastRefresh")
+
+	// 200 with the last refresh
+	refreshTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sRegistry.EXPECT().GetRefresh(mConf.Namespace, mConf.Name).Return(&models.RegistryRefresh{
+		Namespace: mConf.Namespace,
+		Name:      mConf.Name,
+		Result:    models.RegistryRefreshAuthFailure,
+		Message:   "unauthorized",
+		Attempts:  1,
+		Time:      refreshTime,
+	}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/registries/abc", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.Registry{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, &models.RegistryRefresh{
+		Result:   models.RegistryRefreshAuthFailure,
+		Message:  "unauthorized",
+		Attempts: 1,
+		Time:     refreshTime,
+	}, res.LastRefresh)
 
 	// 404
 	req, _ = http.NewRequest(http.MethodGet, "/v1/registries/cba", nil)
@@ -204,7 +241,7 @@ func TestUpdateRegistry(t *testing.T) {
 		Description: "haha modify",
 	}
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(mConfSecret, nil)
-	sSecret.EXPECT().Update(mConfSecret2.Namespace, gomock.Any()).Return(nil, common.Error(common.ErrRequestParamInvalid))
+	sSecret.EXPECT().Update(nil, mConfSecret2.Namespace, gomock.Any()).Return(nil, common.Error(common.ErrRequestParamInvalid))
 	w3 := httptest.NewRecorder()
 	body3, _ := json.Marshal(mConfSecret2)
 	req3, _ := http.NewRequest(http.MethodPut, "/v1/registries/cba", bytes.NewReader(body3))
@@ -263,8 +300,16 @@ func TestRefreshRegistryPassword(t *testing.T) {
 		Name:      "abc",
 		Password:  "haha",
 	}
+	sRegistry := ms.NewMockRegistryService(mockCtl)
+	api.Registry = sRegistry
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(mConfSecret, nil)
 	fSecret.EXPECT().UpdateSecret(mConf2.Namespace, gomock.Any()).Return(mConfSecret, nil)
+	sRegistry.EXPECT().SaveRefresh(gomock.Any()).DoAndReturn(func(r *models.RegistryRefresh) error {
+		assert.Equal(t, "cba", r.Name)
+		assert.Equal(t, models.RegistryRefreshSucceeded, r.Result)
+		assert.Equal(t, 1, r.Attempts)
+		return nil
+	}).Times(1)
 	w3 := httptest.NewRecorder()
 	body3, _ := json.Marshal(mConf2)
 	req3, _ := http.NewRequest(http.MethodPost, "/v1/registries/cba/refresh", bytes.NewReader(body3))
@@ -272,6 +317,162 @@ func TestRefreshRegistryPassword(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w3.Code)
 }
 
+func TestRefreshRegistryPasswordRetry(t *testing.T) {
+	api, router, mockCtl := initRegistryAPI(t)
+	defer mockCtl.Finish()
+
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{Secret: sSecret}
+	fSecret := mf.NewMockFacade(mockCtl)
+	api.Facade = fSecret
+	sRegistry := ms.NewMockRegistryService(mockCtl)
+	api.Registry = sRegistry
+
+	secret := (&models.Registry{Name: "abc", Address: "address", Username: "user", Password: "old"}).ToSecret()
+	body, _ := json.Marshal(&models.Registry{Password: "new"})
+	refresh := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/v1/registries/abc/refresh"+query, bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+	timeout := apierrors.NewTimeoutError("etcd timeout", 1)
+
+	// the temporary failures are retried
+	sSecret.EXPECT().Get("default", "abc", "").Return(nil, timeout).Times(1)
+	sSecret.EXPECT().Get("default", "abc", "").Return(secret, nil).Times(1)
+	fSecret.EXPECT().UpdateSecret("default", gomock.Any()).Return(nil, apierrors.NewTooManyRequests("throttled", 1)).Times(1)
+	fSecret.EXPECT().UpdateSecret("default", gomock.Any()).DoAndReturn(func(_ string, s *specV1.Secret) (*specV1.Secret, error) {
+		assert.Equal(t, "new", string(s.Data["password"]))
+		return s, nil
+	}).Times(1)
+	sRegistry.EXPECT().SaveRefresh(gomock.Any()).DoAndReturn(func(r *models.RegistryRefresh) error {
+		assert.Equal(t, models.RegistryRefreshSucceeded, r.Result)
+		assert.Equal(t, 2, r.Attempts)
+		return nil
+	}).Times(1)
+	w := refresh("")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "new")
+
+	// the attempts are bounded
+	sSecret.EXPECT().Get("default", "abc", "").Return(secret, nil).Times(1)
+	fSecret.EXPECT().UpdateSecret("default", gomock.Any()).Return(nil, timeout).Times(3)
+	sRegistry.EXPECT().SaveRefresh(gomock.Any()).DoAndReturn(func(r *models.RegistryRefresh) error {
+		assert.Equal(t, models.RegistryRefreshTemporaryFailure, r.Result)
+		assert.Equal(t, 3, r.Attempts)
+		assert.Contains(t, r.Message, "etcd timeout")
+		return nil
+	}).Times(1)
+	w = refresh("")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrTemporaryFailure")
+	assert.Contains(t, w.Body.String(), "after 3 attempts")
+
+	// the permanent failures are not retried
+	sSecret.EXPECT().Get("default", "abc", "").Return(secret, nil).Times(1)
+	fSecret.EXPECT().UpdateSecret("default", gomock.Any()).Return(nil, common.Error(common.ErrRequestParamInvalid)).Times(1)
+	sRegistry.EXPECT().SaveRefresh(gomock.Any()).DoAndReturn(func(r *models.RegistryRefresh) error {
+		assert.Equal(t, models.RegistryRefreshFailed, r.Result)
+		assert.Equal(t, 1, r.Attempts)
+		return nil
+	}).Times(1)
+	w = refresh("")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the failure to save the result doesn't fail the refresh
+	sSecret.EXPECT().Get("default", "abc", "").Return(secret, nil).Times(1)
+	fSecret.EXPECT().UpdateSecret("default", gomock.Any()).Return(secret, nil).Times(1)
+	sRegistry.EXPECT().SaveRefresh(gomock.Any()).Return(fmt.Errorf("error")).Times(1)
+	w = refresh("")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the registry not found
+	sSecret.EXPECT().Get("default", "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	w = refresh("")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// allowInternalRegistries lets the registries served by httptest on the loopback be verified
+func allowInternalRegistries() func() {
+	origin := newRegistryClient
+	newRegistryClient = func(timeout time.Duration, _ bool) *http.Client {
+		return &http.Client{Timeout: timeout}
+	}
+	return func() { newRegistryClient = origin }
+}
+
+func TestRefreshRegistryPasswordVerify(t *testing.T) {
+	defer allowInternalRegistries()()
+	api, router, mockCtl := initRegistryAPI(t)
+	defer mockCtl.Finish()
+
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{Secret: sSecret}
+	fSecret := mf.NewMockFacade(mockCtl)
+	api.Facade = fSecret
+	sRegistry := ms.NewMockRegistryService(mockCtl)
+	api.Registry = sRegistry
+
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); ok && u == "user" && p == "secret-pwd" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer hub.Close()
+
+	secret := (&models.Registry{Name: "abc", Address: hub.URL, Username: "user", Password: "old"}).ToSecret()
+	refresh := func(pwd string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(&models.Registry{Password: pwd})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/v1/registries/abc/refresh?verify=true", bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// the credentials rejected are not saved
+	sSecret.EXPECT().Get("default", "abc", "").Return(secret, nil).Times(1)
+	sRegistry.EXPECT().SaveRefresh(gomock.Any()).DoAndReturn(func(r *models.RegistryRefresh) error {
+		assert.Equal(t, models.RegistryRefreshAuthFailure, r.Result)
+		return nil
+	}).Times(1)
+	w := refresh("wrong-pwd")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrRegistryAuthFailed")
+
+	sSecret.EXPECT().Get("default", "abc", "").Return(secret, nil).Times(1)
+	fSecret.EXPECT().UpdateSecret("default", gomock.Any()).Return(secret, nil).Times(1)
+	sRegistry.EXPECT().SaveRefresh(gomock.Any()).Return(nil).Times(1)
+	w = refresh("secret-pwd")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the registry unreachable
+	hub.Close()
+	sSecret.EXPECT().Get("default", "abc", "").Return(secret, nil).Times(1)
+	sRegistry.EXPECT().SaveRefresh(gomock.Any()).DoAndReturn(func(r *models.RegistryRefresh) error {
+		assert.Equal(t, models.RegistryRefreshTemporaryFailure, r.Result)
+		return nil
+	}).Times(1)
+	w = refresh("secret-pwd")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestIsTemporaryError(t *testing.T) {
+	assert.False(t, isTemporaryError(nil))
+	assert.False(t, isTemporaryError(fmt.Errorf("error")))
+	assert.False(t, isTemporaryError(common.Error(common.ErrResourceNotFound)))
+	assert.False(t, isTemporaryError(apierrors.NewBadRequest("bad")))
+	assert.True(t, isTemporaryError(common.Error(common.ErrTemporaryFailure)))
+	assert.True(t, isTemporaryError(apierrors.NewServiceUnavailable("unavailable")))
+	assert.True(t, isTemporaryError(apierrors.NewConflict(schema.GroupResource{}, "abc", fmt.Errorf("conflict"))))
+	assert.True(t, isTemporaryError(errors.Trace(context.DeadlineExceeded)))
+	assert.True(t, isTemporaryError(driver.ErrBadConn))
+	assert.True(t, isTemporaryError(&net.DNSError{IsTimeout: true}))
+}
+
 func TestDeleteRegistry(t *testing.T) {
 	api, router, mockCtl := initRegistryAPI(t)
 	defer mockCtl.Finish()
@@ -301,6 +502,9 @@ func TestDeleteRegistry(t *testing.T) {
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(mConfSecret, nil)
 	fSecret.EXPECT().DeleteSecret(mConfSecret.Namespace, mConfSecret.Name).Return(nil).AnyTimes()
 	sIndex.EXPECT().ListAppIndexBySecret(gomock.Any(), gomock.Any()).Return(nil, nil)
+	sRegistry := ms.NewMockRegistryService(mockCtl)
+	api.Registry = sRegistry
+	sRegistry.EXPECT().DeleteRefresh("default", "abc").Return(nil).Times(1)
 	// 200
 	req, _ := http.NewRequest(http.MethodDelete, "/v1/registries/abc", nil)
 	w := httptest.NewRecorder()
@@ -377,3 +581,111 @@ func TestGetAppByRegistry(t *testing.T) {
 	router.ServeHTTP(w4, req4)
 	assert.Equal(t, http.StatusOK, w4.Code)
 }
+
+func TestVerifyRegistry(t *testing.T) {
+	defer allowInternalRegistries()()
+	api, router, mockCtl := initRegistryAPI(t)
+	defer mockCtl.Finish()
+
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		Secret: sSecret,
+	}
+
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); ok && u == "user" && p == "secret-pwd" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer hub.Close()
+
+	genSecret := func(pwd string) *specV1.Secret {
+		r := &models.Registry{
+			Name:     "abc",
+			Address:  hub.URL,
+			Username: "user",
+			Password: pwd,
+		}
+		return r.ToSecret()
+	}
+
+	// 200 authenticated
+	sSecret.EXPECT().Get("default", "abc", "").Return(genSecret("secret-pwd"), nil).Times(1)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/registries/abc/verify", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret-pwd")
+	res := &models.RegistryVerification{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.True(t, res.Reachable)
+	assert.True(t, res.Authenticated)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	// 200 wrong password
+	sSecret.EXPECT().Get("default", "abc", "").Return(genSecret("wrong-pwd"), nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/registries/abc/verify", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "wrong-pwd")
+	res = &models.RegistryVerification{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.True(t, res.Reachable)
+	assert.False(t, res.Authenticated)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	// 404
+	sSecret.EXPECT().Get("default", "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/registries/abc/verify", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestVerifyRegistryWithToken(t *testing.T) {
+	var auth *httptest.Server
+	auth = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test.registry"`, auth.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		u, p, ok := r.BasicAuth()
+		if r.URL.Path == "/token" && r.URL.Query().Get("service") == "test.registry" && ok && u == "user" && p == "pwd" {
+			w.Write([]byte(`{"token":"abc"}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer auth.Close()
+
+	// the internal addresses are never dialed by default
+	res := verifyRegistry(&models.Registry{Name: "abc", Address: auth.URL, Username: "user", Password: "pwd"}, RegistryVerifyTimeout, false)
+	assert.False(t, res.Reachable)
+	assert.Contains(t, res.Message, "is forbidden")
+
+	defer allowInternalRegistries()()
+	res = verifyRegistry(&models.Registry{Name: "abc", Address: auth.URL, Username: "user", Password: "pwd"}, RegistryVerifyTimeout, false)
+	assert.True(t, res.Reachable)
+	assert.True(t, res.Authenticated)
+
+	res = verifyRegistry(&models.Registry{Name: "abc", Address: auth.URL, Username: "user", Password: "bad"}, RegistryVerifyTimeout, false)
+	assert.True(t, res.Reachable)
+	assert.False(t, res.Authenticated)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	// unreachable
+	addr := auth.URL
+	auth.Close()
+	res = verifyRegistry(&models.Registry{Name: "abc", Address: addr, Username: "user", Password: "pwd"}, time.Second, false)
+	assert.False(t, res.Reachable)
+	assert.False(t, res.Authenticated)
+	assert.NotEmpty(t, res.Message)
+
+	assert.Equal(t, "https://registry-1.docker.io", registryBaseURL("docker.io"))
+	assert.Equal(t, "https://harbor.example.com", registryBaseURL("harbor.example.com/"))
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// checkResourceVersion rejects the update based on a stale version of the resource with 409, the version read
+// is given by ?resourceVersion=, and the update is not checked if it is not given. The updates of the apps,
+// configs and nodes hold the lock of the namespace, so the version doesn't change between the check and the update
+func checkResourceVersion(c *common.Context, typ common.Resource, name, current string) error {
+	version := c.Query(common.ResourceVersion)
+	if version == "" || version == current {
+		return nil
+	}
+	return common.Error(common.ErrVersionConflict,
+		common.Field("type", typ),
+		common.Field("name", name),
+		common.Field("version", version),
+		common.Field("current", current))
+}
+
+// checkIfMatch rejects the delete based on a stale version of the resource with 412, the version read is given by
+// the header If-Match, and the delete is not checked if it is not given. The header carries the raw resource version
+// as returned in the body, or the ETag of the response of GET, and "*" matches any version. The current version is
+// empty if the resource is not found, which matches nothing. The deletes of the apps, configs and nodes hold the lock
+// of the namespace as the updates
+func checkIfMatch(c *common.Context, typ common.Resource, name, current string) error {
+	header := strings.TrimSpace(c.GetHeader(common.HeaderIfMatch))
+	if header == "" {
+		return nil
+	}
+	for _, version := range strings.Split(header, ",") {
+		version = strings.Trim(strings.TrimPrefix(strings.TrimSpace(version), "W/"), `"`)
+		if current != "" && (version == "*" || version == current) {
+			return nil
+		}
+	}
+	return common.Error(common.ErrPreconditionFailed,
+		common.Field("type", typ),
+		common.Field("name", name),
+		common.Field("version", header),
+		common.Field("current", current))
+}
+
+// setResourceETag tags the response with the resource version, so that it can be given back by If-Match as is.
+// The tag is weak since the body may be compressed on the way out
+func setResourceETag(c *common.Context, version string) {
+	if version != "" {
+		c.Header(common.HeaderETag, `W/"`+version+`"`)
+	}
+}
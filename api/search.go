@@ -0,0 +1,137 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	SearchDefaultLimit = 20
+	SearchMaxLimit     = 100
+)
+
+// the scores of the matches, the lower the more relevant
+const (
+	searchScoreExactName = iota
+	searchScoreNamePrefix
+	searchScoreNameContains
+	searchScoreDescription
+)
+
+var searchTypeOrder = map[string]int{
+	models.SearchTypeApp:         0,
+	models.SearchTypeConfig:      1,
+	models.SearchTypeSecret:      2,
+	models.SearchTypeCertificate: 3,
+	models.SearchTypeRegistry:    4,
+	models.SearchTypeNode:        5,
+}
+
+// GlobalSearch search the apps, configs, secrets, certificates, registries and nodes of the namespace by name and description
+func (api *API) GlobalSearch(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	params := &models.SearchParams{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	q := strings.ToLower(strings.TrimSpace(params.Query))
+	if q == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the query is required"))
+	}
+	limit := params.Limit
+	if limit == 0 {
+		limit = SearchDefaultLimit
+	}
+	if limit > SearchMaxLimit {
+		limit = SearchMaxLimit
+	}
+
+	res := &models.SearchResultList{Counts: map[string]int{}, Items: []models.SearchResult{}}
+	add := func(typ, name, desc string) {
+		score, ok := searchScore(q, name, desc)
+		if !ok {
+			return
+		}
+		res.Counts[typ]++
+		res.Items = append(res.Items, models.SearchResult{Type: typ, Name: name, Description: desc, Score: score})
+	}
+
+	userSelector := "!" + common.LabelSystem
+	apps, err := api.App.List(ns, &models.ListOptions{LabelSelector: userSelector})
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range apps.Items {
+		add(models.SearchTypeApp, app.Name, app.Description)
+	}
+	configs, err := api.Config.List(ns, &models.ListOptions{LabelSelector: userSelector})
+	if err != nil {
+		return nil, err
+	}
+	for _, cfg := range configs.Items {
+		add(models.SearchTypeConfig, cfg.Name, cfg.Description)
+	}
+	for typ, label := range map[string]string{
+		models.SearchTypeSecret:      specV1.SecretConfig,
+		models.SearchTypeCertificate: specV1.SecretCertificate,
+		models.SearchTypeRegistry:    specV1.SecretRegistry,
+	} {
+		secrets, err := api.Secret.List(ns, &models.ListOptions{
+			LabelSelector: fmt.Sprintf("%s,%s=%s", userSelector, specV1.SecretLabel, label),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets.Items {
+			add(typ, secret.Name, secret.Description)
+		}
+	}
+	nodes, err := api.Node.List(ns, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes.Items {
+		add(models.SearchTypeNode, node.Name, node.Description)
+	}
+
+	sort.SliceStable(res.Items, func(i, j int) bool {
+		a, b := res.Items[i], res.Items[j]
+		if a.Score != b.Score {
+			return a.Score < b.Score
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		if a.Type != b.Type {
+			return searchTypeOrder[a.Type] < searchTypeOrder[b.Type]
+		}
+		return a.Name < b.Name
+	})
+	res.Total = len(res.Items)
+	if len(res.Items) > limit {
+		res.Items = res.Items[:limit]
+	}
+	return res, nil
+}
+
+// searchScore returns the relevance of the resource to the lower-cased query, the names are more relevant than the descriptions
+func searchScore(q, name, desc string) (int, bool) {
+	name = strings.ToLower(name)
+	switch {
+	case name == q:
+		return searchScoreExactName, true
+	case strings.HasPrefix(name, q):
+		return searchScoreNamePrefix, true
+	case strings.Contains(name, q):
+		return searchScoreNameContains, true
+	case strings.Contains(strings.ToLower(desc), q):
+		return searchScoreDescription, true
+	}
+	return 0, false
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestGlobalSearch(t *testing.T) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	router.GET("/v1/search", mockIM, common.Wrapper(api.GlobalSearch))
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret}
+	api.Node = sNode
+
+	sApp.EXPECT().List("default", &models.ListOptions{LabelSelector: "!" + common.LabelSystem}).Return(&models.ApplicationList{
+		Items: []models.AppItem{{Name: "foo"}, {Name: "bar", Description: "uses Foo"}, {Name: "baz"}},
+	}, nil).Times(2)
+	sConfig.EXPECT().List("default", &models.ListOptions{LabelSelector: "!" + common.LabelSystem}).Return(&models.ConfigurationList{
+		Items: []specV1.Configuration{{Name: "foo-conf"}},
+	}, nil).Times(2)
+	sSecret.EXPECT().List("default", gomock.Any()).DoAndReturn(func(_ string, opts *models.ListOptions) (*models.SecretList, error) {
+		switch opts.LabelSelector {
+		case "!" + common.LabelSystem + "," + specV1.SecretLabel + "=" + specV1.SecretConfig:
+			return &models.SecretList{Items: []specV1.Secret{{Name: "my-foo"}}}, nil
+		case "!" + common.LabelSystem + "," + specV1.SecretLabel + "=" + specV1.SecretRegistry:
+			return &models.SecretList{Items: []specV1.Secret{{Name: "fo"}}}, nil
+		}
+		return &models.SecretList{}, nil
+	}).Times(6)
+	sNode.EXPECT().List("default", &models.ListOptions{}).Return(&models.NodeList{
+		Items: []specV1.Node{{Name: "foo"}, {Name: "edge"}},
+	}, nil).Times(2)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/search?q=Foo", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.SearchResultList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 5, res.Total)
+	assert.Equal(t, map[string]int{models.SearchTypeApp: 2, models.SearchTypeConfig: 1, models.SearchTypeSecret: 1, models.SearchTypeNode: 1}, res.Counts)
+	assert.Equal(t, []models.SearchResult{
+		{Type: models.SearchTypeApp, Name: "foo", Score: 0},
+		{Type: models.SearchTypeNode, Name: "foo", Score: 0},
+		{Type: models.SearchTypeConfig, Name: "foo-conf", Score: 1},
+		{Type: models.SearchTypeSecret, Name: "my-foo", Score: 2},
+		{Type: models.SearchTypeApp, Name: "bar", Description: "uses Foo", Score: 3},
+	}, res.Items)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/search?q=foo&limit=2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = &models.SearchResultList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 5, res.Total)
+	assert.Len(t, res.Items, 2)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/search", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
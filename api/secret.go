@@ -18,6 +18,22 @@ import (
 func (api *API) GetSecret(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
 	res, err := api.Secret.Get(ns, n, "")
+	if err = checkResourceFound(res, err, common.Secret, n); err != nil {
+		return nil, err
+	}
+	return api.ToSecretView(res), nil
+}
+
+// GetSecretVersions list the kept versions of a secret, the latest first
+func (api *API) GetSecretVersions(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Secret.ListVersions(ns, n)
+}
+
+// GetSecretVersion get a kept version of a secret
+func (api *API) GetSecretVersion(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	res, err := api.Secret.GetVersion(ns, n, c.Param("version"))
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +73,9 @@ func (api *API) CreateSecret(c *common.Context) (interface{}, error) {
 	if sd != nil {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
 	}
+	if err = checkSecretPasswords(api.passwordPolicy, cfg.Data, nil); err != nil {
+		return nil, err
+	}
 	res, err := api.Facade.CreateSecret(ns, cfg.ToSecret())
 	if err != nil {
 		return nil, err
@@ -80,6 +99,9 @@ func (api *API) UpdateSecret(c *common.Context) (interface{}, error) {
 	if sd.Equal(cfg) {
 		return sd, nil
 	}
+	if err = checkSecretPasswords(api.passwordPolicy, cfg.Data, sd.Data); err != nil {
+		return nil, err
+	}
 
 	cfg.Version = sd.Version
 	cfg.UpdateTimestamp = time.Now()
@@ -106,6 +128,35 @@ func (api *API) GetAppBySecret(c *common.Context) (interface{}, error) {
 	return api.listAppBySecret(ns, secret.Name)
 }
 
+// EncryptSecrets for mis server api, encrypts the plaintext secrets stored before the kms is configured
+//   - param namespace string, the secrets of all namespaces are encrypted if it is empty
+func (api *API) EncryptSecrets(c *common.Context) (interface{}, error) {
+	namespaces := []string{}
+	if ns := c.Query("namespace"); ns != "" {
+		namespaces = append(namespaces, ns)
+	} else {
+		list, err := api.NS.List(&models.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range list.Items {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	res := &models.SecretEncryption{Namespaces: map[string]int{}}
+	for _, ns := range namespaces {
+		count, err := api.Secret.Encrypt(ns)
+		res.Total += count
+		res.Namespaces[ns] = count
+		if err != nil {
+			log.L().Error("failed to encrypt secrets", log.Any(common.KeyContextNamespace, ns), log.Any("encrypted", res), log.Error(err))
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
 // parseAndCheckSecretModel parse and check the config model
 func (api *API) parseAndCheckSecretModel(c *common.Context) (*models.SecretView, error) {
 	secret := new(models.SecretView)
@@ -157,7 +208,7 @@ func (api *API) DeleteSecretResource(namespace, secret, secretType string) (inte
 		return nil, err
 	}
 	if len(appNames) > 0 {
-		return nil, common.Error(common.ErrResourceHasBeenUsed, common.Field("type", secretType), common.Field("name", secret))
+		return nil, api.resourceHasBeenUsedError(namespace, secretType, secret, appNames)
 	}
 	return nil, api.Facade.DeleteSecret(namespace, secret)
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
 	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
@@ -36,6 +38,7 @@ func initSecretAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		configs.DELETE("/:name", mockIM, common.Wrapper(api.DeleteSecret))
 		configs.POST("", mockIM, common.Wrapper(api.CreateSecret))
 		configs.GET("", mockIM, common.Wrapper(api.ListSecret))
+		configs.POST("/encrypt", common.WrapperMis(api.EncryptSecrets))
 	}
 
 	return api, router, mockCtl
@@ -148,6 +151,18 @@ func TestCreateSecret(t *testing.T) {
 	req2, _ := http.NewRequest(http.MethodPost, "/v1/secrets", bytes.NewReader(body2))
 	router.ServeHTTP(w2, req2)
 	assert.Equal(t, http.StatusBadRequest, w2.Code)
+
+	// the weak password is rejected without being echoed
+	api.passwordPolicy = config.PasswordPolicy{Enable: true, MinLength: 8, MinClasses: 3, SecretKeys: []string{"password"}}
+	mConf.Data = map[string]string{"a": "b", "db_password": "weak"}
+	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	w4 := httptest.NewRecorder()
+	body4, _ := json.Marshal(mConf)
+	req4, _ := http.NewRequest(http.MethodPost, "/v1/secrets", bytes.NewReader(body4))
+	router.ServeHTTP(w4, req4)
+	assert.Equal(t, http.StatusBadRequest, w4.Code)
+	assert.Contains(t, w4.Body.String(), "the value of the key (db_password) violates the password policy")
+	assert.NotContains(t, w4.Body.String(), "weak")
 }
 
 func TestUpdateSecret(t *testing.T) {
@@ -261,11 +276,22 @@ func TestDeleteSecret(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 
 	sIndex.EXPECT().ListAppIndexBySecret(gomock.Any(), gomock.Any()).Return([]string{"app1", "app2"}, nil)
-	// 400
+	sApp.EXPECT().ListByNames("default", []string{"app1", "app2"}).Return([]models.AppItem{{Name: "app1", Version: "1"}, {Name: "app2", Version: "2"}}, nil).Times(1)
+	// 403
 	req2, _ := http.NewRequest(http.MethodDelete, "/v1/secrets/abc", nil)
 	w2 := httptest.NewRecorder()
 	router.ServeHTTP(w2, req2)
 	assert.Equal(t, http.StatusForbidden, w2.Code)
+	assert.Contains(t, w2.Body.String(), "has been used by the applications (app1:1, app2:2)")
+
+	// the names are kept if the versions fail to be read
+	sIndex.EXPECT().ListAppIndexBySecret(gomock.Any(), gomock.Any()).Return([]string{"app1", "app2"}, nil)
+	sApp.EXPECT().ListByNames("default", []string{"app1", "app2"}).Return(nil, fmt.Errorf("error")).Times(1)
+	req3, _ := http.NewRequest(http.MethodDelete, "/v1/secrets/abc", nil)
+	w3 := httptest.NewRecorder()
+	router.ServeHTTP(w3, req3)
+	assert.Equal(t, http.StatusForbidden, w3.Code)
+	assert.Contains(t, w3.Body.String(), "has been used by the applications (app1, app2)")
 }
 
 func TestGetAppBySecret(t *testing.T) {
@@ -342,3 +368,40 @@ func TestGetAppBySecret(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, res.Total, 0)
 }
+
+func TestEncryptSecrets(t *testing.T) {
+	api, router, mockCtl := initSecretAPI(t)
+	defer mockCtl.Finish()
+
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		Secret: sSecret,
+	}
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	api.NS = sNS
+
+	// the given namespace
+	sSecret.EXPECT().Encrypt("default").Return(2, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/secrets/encrypt?namespace=default", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":0,"msg":"ok","data":{"total":2,"namespaces":{"default":2}}}`, w.Body.String())
+
+	// all namespaces
+	sNS.EXPECT().List(gomock.Any()).Return(&models.NamespaceList{Items: []models.Namespace{{Name: "a"}, {Name: "b"}}}, nil).Times(1)
+	sSecret.EXPECT().Encrypt("a").Return(1, nil).Times(1)
+	sSecret.EXPECT().Encrypt("b").Return(0, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/secrets/encrypt", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":0,"msg":"ok","data":{"total":1,"namespaces":{"a":1,"b":0}}}`, w.Body.String())
+
+	// no kms is configured
+	sSecret.EXPECT().Encrypt("default").Return(0, common.Error(common.ErrRequestParamInvalid, common.Field("error", "no kms is configured to encrypt the secrets"))).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/secrets/encrypt?namespace=default", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), "no kms is configured")
+}
@@ -19,11 +19,14 @@ import (
 type SyncAPI interface {
 	Report(msg specV1.Message) (*specV1.Message, error)
 	Desire(msg specV1.Message) (*specV1.Message, error)
+	// CheckNodeToken returns the namespace and the name of the node of the node token in the authorization
+	CheckNodeToken(authorization string) (string, string, error)
 }
 
 type SyncAPIImpl struct {
 	Sync service.SyncService
 	Node service.NodeService
+	Sign service.SignService
 	log  *log.Logger
 }
 
@@ -36,13 +39,25 @@ func NewSyncAPI(cfg *config.CloudConfig) (SyncAPI, error) {
 	if err != nil {
 		return nil, err
 	}
+	signService, err := service.NewSignService(cfg)
+	if err != nil {
+		return nil, err
+	}
 	return &SyncAPIImpl{
 		Sync: syncService,
 		Node: nodeService,
+		Sign: signService,
 		log:  log.L().With(log.Any("api", "sync")),
 	}, nil
 }
 
+// CheckNodeToken checks the node token of the sync requests
+func (s *SyncAPIImpl) CheckNodeToken(authorization string) (string, string, error) {
+	return CheckNodeToken(authorization, s.Sign.GenToken, func(ns, name string) (*specV1.Node, error) {
+		return s.Node.Get(nil, ns, name)
+	})
+}
+
 // Report for node report
 func (s *SyncAPIImpl) Report(msg specV1.Message) (*specV1.Message, error) {
 	var report specV1.Report
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ListUnusedConfig list the configs not referenced by any application, so that they can be pruned
+func (api *API) ListUnusedConfig(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	params, err := api.parseUnusedListOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	list, err := api.Config.List(ns, allOf(params))
+	if err != nil {
+		return nil, err
+	}
+	res := &models.UnusedResourceList{ListOptions: params, Items: []models.UnusedResource{}}
+	for _, cfg := range list.Items {
+		apps, err := api.Index.ListAppIndexByConfig(ns, cfg.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(apps) > 0 {
+			continue
+		}
+		res.Items = append(res.Items, models.UnusedResource{
+			Name:        cfg.Name,
+			Namespace:   cfg.Namespace,
+			Labels:      cfg.Labels,
+			Description: cfg.Description,
+			UpdateTime:  cfg.UpdateTimestamp,
+		})
+	}
+	return pageUnused(res), nil
+}
+
+// ListUnusedSecret list the secrets not referenced by any application, so that they can be pruned.
+// The certificates and registries are not included
+func (api *API) ListUnusedSecret(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	params, err := api.parseUnusedListOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	opts := allOf(params)
+	opts.LabelSelector += "," + fmt.Sprintf("%s=%s", specV1.SecretLabel, specV1.SecretConfig)
+	list, err := api.Secret.List(ns, opts)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.UnusedResourceList{ListOptions: params, Items: []models.UnusedResource{}}
+	for _, secret := range list.Items {
+		apps, err := api.Index.ListAppIndexBySecret(ns, secret.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(apps) > 0 {
+			continue
+		}
+		res.Items = append(res.Items, models.UnusedResource{
+			Name:        secret.Name,
+			Namespace:   secret.Namespace,
+			Labels:      secret.Labels,
+			Description: secret.Description,
+			UpdateTime:  secret.UpdateTimestamp,
+		})
+	}
+	return pageUnused(res), nil
+}
+
+// parseUnusedListOptions parses the list options of the unused resources, which are paged by pageNo and pageSize only,
+// the system resources are not included
+func (api *API) parseUnusedListOptions(c *common.Context) (*models.ListOptions, error) {
+	params, err := api.ParseListOptionsAppendSystemLabel(c)
+	if err != nil {
+		return nil, err
+	}
+	if params.Cursor != "" || params.Limit > 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the unused resources are paged by pageNo and pageSize only"))
+	}
+	if params.PageNo < 0 || params.PageSize < 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "pageNo and pageSize should not be negative"))
+	}
+	return params, nil
+}
+
+// allOf returns the options listing all the resources matching the params, since the references are
+// checked one by one after listing, the page is cut from the unused ones
+func allOf(params *models.ListOptions) *models.ListOptions {
+	opts := *params
+	opts.Filter = models.Filter{Name: params.Name}
+	return &opts
+}
+
+func pageUnused(res *models.UnusedResourceList) *models.UnusedResourceList {
+	sort.SliceStable(res.Items, func(i, j int) bool {
+		return res.Items[i].Name < res.Items[j].Name
+	})
+	res.Total = len(res.Items)
+	start, end := models.GetPagingParam(res.ListOptions, res.Total)
+	res.Items = res.Items[start:end]
+	return res
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initUnusedAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	v1.GET("/configs/unused", mockIM, common.Wrapper(api.ListUnusedConfig))
+	v1.GET("/secrets/unused", mockIM, common.Wrapper(api.ListUnusedSecret))
+	return api, router, mockCtl
+}
+
+func TestListUnusedConfig(t *testing.T) {
+	api, router, mockCtl := initUnusedAPI(t)
+	defer mockCtl.Finish()
+	sConfig, sIndex := ms.NewMockConfigService(mockCtl), ms.NewMockIndexService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{Config: sConfig}
+	api.Index = sIndex
+
+	updated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	list := &models.ConfigurationList{Items: []specV1.Configuration{
+		{Namespace: "default", Name: "c3", UpdateTimestamp: updated},
+		{Namespace: "default", Name: "c1", UpdateTimestamp: updated},
+		{Namespace: "default", Name: "c2", UpdateTimestamp: updated},
+	}}
+	// all the configs are listed to be checked, and the page is cut from the unused ones
+	opts := &models.ListOptions{LabelSelector: "!" + common.LabelSystem}
+	sConfig.EXPECT().List("default", opts).Return(list, nil).Times(2)
+	sIndex.EXPECT().ListAppIndexByConfig("default", "c1").Return(nil, nil).Times(2)
+	sIndex.EXPECT().ListAppIndexByConfig("default", "c2").Return([]string{"app"}, nil).Times(2)
+	sIndex.EXPECT().ListAppIndexByConfig("default", "c3").Return([]string{}, nil).Times(2)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/configs/unused", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.UnusedResourceList
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, []models.UnusedResource{
+		{Namespace: "default", Name: "c1", UpdateTime: updated},
+		{Namespace: "default", Name: "c3", UpdateTime: updated},
+	}, res.Items)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs/unused?pageNo=2&pageSize=1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = models.UnusedResourceList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 2, res.Total)
+	assert.Len(t, res.Items, 1)
+	assert.Equal(t, "c3", res.Items[0].Name)
+
+	// 500
+	sConfig.EXPECT().List("default", opts).Return(list, nil).Times(1)
+	sIndex.EXPECT().ListAppIndexByConfig("default", "c3").Return(nil, fmt.Errorf("error")).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs/unused", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// 400
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs/unused?limit=10", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListUnusedSecret(t *testing.T) {
+	api, router, mockCtl := initUnusedAPI(t)
+	defer mockCtl.Finish()
+	sSecret, sIndex := ms.NewMockSecretService(mockCtl), ms.NewMockIndexService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{Secret: sSecret}
+	api.Index = sIndex
+
+	list := &models.SecretList{Items: []specV1.Secret{
+		{Namespace: "default", Name: "s1", Description: "unused"},
+		{Namespace: "default", Name: "s2"},
+	}}
+	opts := &models.ListOptions{
+		LabelSelector: fmt.Sprintf("!%s,%s=%s", common.LabelSystem, specV1.SecretLabel, specV1.SecretConfig),
+	}
+	sSecret.EXPECT().List("default", opts).Return(list, nil).Times(1)
+	sIndex.EXPECT().ListAppIndexBySecret("default", "s1").Return(nil, nil).Times(1)
+	sIndex.EXPECT().ListAppIndexBySecret("default", "s2").Return([]string{"app"}, nil).Times(1)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/secrets/unused", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.UnusedResourceList
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, []models.UnusedResource{{Namespace: "default", Name: "s1", Description: "unused"}}, res.Items)
+}
@@ -2,14 +2,23 @@ package api
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
+	"strconv"
+	"strings"
 
 	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
-// ValidateResourceForCreating validate when resource create
+// ValidateResourceForCreating validate when resource create. All the failures of validating the apps, configs,
+// secrets and certificates are collected and responded as a list, unless ?failFast=true is given,
+// then the first failure is responded as before
 func (api *API) ValidateResourceForCreating(c *common.Context) (interface{}, error) {
 	resource := struct {
 		Name string `json:"name,omitempty"`
@@ -26,12 +35,60 @@ func (api *API) ValidateResourceForCreating(c *common.Context) (interface{}, err
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
 	}
 
+	failFast, _ := strconv.ParseBool(c.Query("failFast"))
+	if failFast {
+		if !common.ValidNonBaetyl(resource.Name) {
+			return nil, common.Error(common.ErrInvalidName, common.Field("nonBaetyl", "Name"))
+		}
+		return nil, nil
+	}
+
+	var errs []common.FieldError
 	if !common.ValidNonBaetyl(resource.Name) {
-		return nil, common.Error(common.ErrInvalidName, common.Field("nonBaetyl", "Name"))
+		errs = append(errs, common.FieldError{Field: "name", Code: common.ErrInvalidName,
+			Message: common.Error(common.ErrInvalidName, common.Field("nonBaetyl", "Name")).Error()})
+	}
+	if model := creatingModel(c.FullPath()); model != nil {
+		if resource.Name == "" {
+			errs = append(errs, common.FieldError{Field: "name", Code: common.ErrInvalidRequired, Message: "name is required"})
+		}
+		if err = json.Unmarshal(buf, model); err != nil {
+			errs = append(errs, common.FieldError{Code: common.ErrRequestParamInvalid, Message: err.Error()})
+		} else if err = binding.Validator.ValidateStruct(model); err != nil {
+			if ves, ok := err.(validator.ValidationErrors); ok {
+				errs = append(errs, common.ToFieldErrors(ves)...)
+			} else {
+				errs = append(errs, common.FieldError{Code: common.ErrRequestParamInvalid, Message: err.Error()})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &common.ValidationError{Errors: errs}
 	}
 	return nil, nil
 }
 
+// creatingModel returns the model of the resource created by the route to validate the request body,
+// nil if the resource is not validated here
+func creatingModel(route string) interface{} {
+	parts := strings.Split(route, "/")
+	if len(parts) < 3 {
+		return nil
+	}
+	switch parts[2] {
+	case "apps":
+		return new(models.ApplicationView)
+	case "configs":
+		return new(models.ConfigurationView)
+	case "secrets":
+		return new(models.SecretView)
+	case "certificates":
+		return new(models.Certificate)
+	default:
+		return nil
+	}
+}
+
 // ValidateResourceForDeleting validate when resource delete
 func (api *API) ValidateResourceForDeleting(c *common.Context) (interface{}, error) {
 	name := c.GetNameFromParam()
@@ -40,3 +97,23 @@ func (api *API) ValidateResourceForDeleting(c *common.Context) (interface{}, err
 	}
 	return nil, nil
 }
+
+// resourceHasBeenUsedError returns the error of deleting a resource still in use,
+// which lists the name and version of the applications referencing it
+func (api *API) resourceHasBeenUsedError(namespace, resourceType, name string, appNames []string) error {
+	apps := appNames
+	list, err := api.listAppByNames(namespace, appNames)
+	if err != nil {
+		// the names are still useful if the versions fail to be read
+		log.L().Warn("failed to list the applications referencing the resource", log.Any("type", resourceType), log.Any("name", name), log.Any("namespace", namespace), log.Error(err))
+	} else if len(list.Items) > 0 {
+		apps = make([]string, 0, len(list.Items))
+		for _, app := range list.Items {
+			apps = append(apps, fmt.Sprintf("%s:%s", app.Name, app.Version))
+		}
+	}
+	return common.Error(common.ErrResourceHasBeenUsed,
+		common.Field("type", resourceType),
+		common.Field("name", name),
+		common.Field("apps", strings.Join(apps, ", ")))
+}
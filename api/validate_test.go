@@ -0,0 +1,68 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestValidateResourceForCreating(t *testing.T) {
+	api := &API{}
+	router := gin.New()
+	created := func(c *gin.Context) { c.Status(http.StatusCreated) }
+	router.POST("/v1/secrets", common.WrapperRaw(api.ValidateResourceForCreating, true), created)
+	router.POST("/v1/nodegroups", common.WrapperRaw(api.ValidateResourceForCreating, true), created)
+	do := func(path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/v1/secrets", `{"name":"abc","data":{"a":"b"}}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// all the failures are listed
+	w = do("/v1/secrets", `{"name":"my-baetyl-Secret"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var res struct {
+		Code   string              `json:"code"`
+		Errors []common.FieldError `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, common.ErrRequestParamInvalid, res.Code)
+	assert.Len(t, res.Errors, 3)
+	assert.Equal(t, "name", res.Errors[0].Field)
+	assert.Equal(t, common.ErrInvalidName, res.Errors[0].Code)
+	assert.Equal(t, "Name", res.Errors[1].Field)
+	assert.Equal(t, "res_name", res.Errors[1].Code)
+	assert.Equal(t, "Data", res.Errors[2].Field)
+	assert.Equal(t, common.ErrInvalidRequired, res.Errors[2].Code)
+
+	w = do("/v1/secrets", `{"data":"abc"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Len(t, res.Errors, 2)
+	assert.Equal(t, common.ErrInvalidRequired, res.Errors[0].Code)
+	assert.Equal(t, common.ErrRequestParamInvalid, res.Errors[1].Code)
+
+	// the first failure only with failFast, and the routes without models only check the name
+	w = do("/v1/secrets?failFast=true", `{"name":"baetyl-abc"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"nonBaetyl"`)
+	assert.NotContains(t, w.Body.String(), `"errors"`)
+	w = do("/v1/secrets?failFast=true", `{"name":"abc"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = do("/v1/nodegroups", `{}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = do("/v1/nodegroups", `{"name":"baetyl-abc"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"name"`)
+}
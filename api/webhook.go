@@ -0,0 +1,152 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetWebhook get a webhook
+func (api *API) GetWebhook(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	res, err := api.Webhook.Get(ns, n)
+	if err = checkResourceFound(res, err, common.Webhook, n); err != nil {
+		return nil, err
+	}
+	return hideWebhookSecret(res), nil
+}
+
+// ListWebhook list webhooks
+func (api *API) ListWebhook(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	params, err := api.ParseListOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	res, err := api.Webhook.List(ns, params)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res.Items {
+		hideWebhookSecret(&res.Items[i])
+	}
+	return res, nil
+}
+
+// CreateWebhook create a webhook
+func (api *API) CreateWebhook(c *common.Context) (interface{}, error) {
+	webhook, err := api.parseAndCheckWebhook(c)
+	if err != nil {
+		return nil, err
+	}
+	ns := c.GetNamespace()
+	webhook.Namespace = ns
+
+	old, err := api.Webhook.Get(ns, webhook.Name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return nil, err
+		}
+	}
+	if old != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
+	}
+	res, err := api.Webhook.Create(webhook)
+	if err != nil {
+		return nil, err
+	}
+	return hideWebhookSecret(res), nil
+}
+
+// UpdateWebhook update the url, events and description of a webhook, the secret is kept if not given
+func (api *API) UpdateWebhook(c *common.Context) (interface{}, error) {
+	webhook, err := api.parseAndCheckWebhook(c)
+	if err != nil {
+		return nil, err
+	}
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	old, err := api.Webhook.Get(ns, n)
+	if err != nil {
+		return nil, err
+	}
+	old.URL = webhook.URL
+	old.Events = webhook.Events
+	old.Description = webhook.Description
+	if webhook.Secret != "" {
+		old.Secret = webhook.Secret
+	}
+	old.UpdateTimestamp = time.Now()
+	res, err := api.Webhook.Update(old)
+	if err != nil {
+		return nil, err
+	}
+	return hideWebhookSecret(res), nil
+}
+
+// DeleteWebhook delete a webhook
+func (api *API) DeleteWebhook(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	_, err := api.Webhook.Get(ns, n)
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return nil, api.Webhook.Delete(ns, n)
+}
+
+// TestWebhook post a sample event to the webhook and return the result
+func (api *API) TestWebhook(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	webhook, err := api.Webhook.Get(ns, n)
+	if err != nil {
+		return nil, err
+	}
+	return api.Webhook.Test(webhook), nil
+}
+
+// ListWebhookDeadLetters list the payloads failed to be delivered to the webhook
+func (api *API) ListWebhookDeadLetters(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.Webhook.Get(ns, n); err != nil {
+		return nil, err
+	}
+	return api.Webhook.ListDeadLetters(ns, n, params)
+}
+
+func (api *API) parseAndCheckWebhook(c *common.Context) (*models.Webhook, error) {
+	webhook := new(models.Webhook)
+	if err := c.LoadBody(webhook); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if name := c.GetNameFromParam(); name != "" {
+		webhook.Name = name
+	}
+	if webhook.Name == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+	}
+	for _, e := range webhook.Events {
+		if !models.ValidWebhookEvent(e) {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the event type (%s) is not supported", e)))
+		}
+	}
+	return webhook, nil
+}
+
+// hideWebhookSecret the secrets of the webhooks are write-only
+func hideWebhookSecret(webhook *models.Webhook) *models.Webhook {
+	if webhook != nil {
+		webhook.Secret = ""
+	}
+	return webhook
+}
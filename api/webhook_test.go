@@ -0,0 +1,178 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initWebhookAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L()}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		webhooks := v1.Group("/webhooks")
+		webhooks.GET("/:name", mockIM, common.Wrapper(api.GetWebhook))
+		webhooks.PUT("/:name", mockIM, common.Wrapper(api.UpdateWebhook))
+		webhooks.DELETE("/:name", mockIM, common.Wrapper(api.DeleteWebhook))
+		webhooks.POST("", mockIM, common.Wrapper(api.CreateWebhook))
+		webhooks.GET("", mockIM, common.Wrapper(api.ListWebhook))
+		webhooks.POST("/:name/test", mockIM, common.Wrapper(api.TestWebhook))
+		webhooks.GET("/:name/deadletters", mockIM, common.Wrapper(api.ListWebhookDeadLetters))
+	}
+	return api, router, mockCtl
+}
+
+func TestCreateAndUpdateWebhook(t *testing.T) {
+	api, router, mockCtl := initWebhookAPI(t)
+	defer mockCtl.Finish()
+	sWebhook := ms.NewMockWebhookService(mockCtl)
+	api.Webhook = sWebhook
+
+	webhook := &models.Webhook{
+		Name:   "hook01",
+		URL:    "https://example.com/hook",
+		Secret: "s3cret",
+		Events: []string{"app.updated", models.WebhookEventNodeOffline},
+	}
+	sWebhook.EXPECT().Get("default", "hook01").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	sWebhook.EXPECT().Create(gomock.Any()).DoAndReturn(func(w *models.Webhook) (*models.Webhook, error) {
+		assert.Equal(t, "default", w.Namespace)
+		assert.Equal(t, "s3cret", w.Secret)
+		res := *w
+		return &res, nil
+	}).Times(1)
+	body, _ := json.Marshal(webhook)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/webhooks", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	// the secret is not returned
+	assert.NotContains(t, w.Body.String(), "s3cret")
+
+	// name in use
+	sWebhook.EXPECT().Get("default", "hook01").Return(webhook, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/webhooks", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// unsupported event, invalid url, no events
+	for _, invalid := range []*models.Webhook{
+		{Name: "hook02", URL: "https://example.com", Events: []string{"app.exploded"}},
+		{Name: "hook02", URL: "not a url", Events: []string{"*"}},
+		{Name: "hook02", URL: "https://example.com"},
+	} {
+		body, _ = json.Marshal(invalid)
+		req, _ = http.NewRequest(http.MethodPost, "/v1/webhooks", bytes.NewReader(body))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+
+	// update keeps the secret if not given
+	sWebhook.EXPECT().Get("default", "hook01").Return(&models.Webhook{Name: "hook01", Namespace: "default", Secret: "old"}, nil).Times(1)
+	sWebhook.EXPECT().Update(gomock.Any()).DoAndReturn(func(w *models.Webhook) (*models.Webhook, error) {
+		assert.Equal(t, "old", w.Secret)
+		assert.Equal(t, "https://example.com/new", w.URL)
+		assert.Equal(t, []string{"*"}, w.Events)
+		return w, nil
+	}).Times(1)
+	body, _ = json.Marshal(&models.Webhook{URL: "https://example.com/new", Events: []string{"*"}})
+	req, _ = http.NewRequest(http.MethodPut, "/v1/webhooks/hook01", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "old")
+}
+
+func TestGetListDeleteWebhook(t *testing.T) {
+	api, router, mockCtl := initWebhookAPI(t)
+	defer mockCtl.Finish()
+	sWebhook := ms.NewMockWebhookService(mockCtl)
+	api.Webhook = sWebhook
+
+	sWebhook.EXPECT().Get("default", "hook01").Return(&models.Webhook{Name: "hook01", Secret: "s3cret"}, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/webhooks/hook01", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+
+	sWebhook.EXPECT().List("default", gomock.Any()).Return(&models.WebhookList{
+		Total: 1,
+		Items: []models.Webhook{{Name: "hook01", Secret: "s3cret"}},
+	}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/webhooks", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "hook01")
+	assert.NotContains(t, w.Body.String(), "s3cret")
+
+	sWebhook.EXPECT().Get("default", "hook01").Return(&models.Webhook{Name: "hook01"}, nil).Times(1)
+	sWebhook.EXPECT().Delete("default", "hook01").Return(nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/webhooks/hook01", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// deleting the absent one is ok
+	sWebhook.EXPECT().Get("default", "hook02").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/webhooks/hook02", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTestWebhookAndDeadLetters(t *testing.T) {
+	api, router, mockCtl := initWebhookAPI(t)
+	defer mockCtl.Finish()
+	sWebhook := ms.NewMockWebhookService(mockCtl)
+	api.Webhook = sWebhook
+
+	webhook := &models.Webhook{Name: "hook01", Namespace: "default", URL: "https://example.com"}
+	sWebhook.EXPECT().Get("default", "hook01").Return(webhook, nil).Times(1)
+	sWebhook.EXPECT().Test(webhook).Return(&models.WebhookDelivery{
+		Payload:    &models.WebhookPayload{ID: "id", Event: models.WebhookEventTest},
+		StatusCode: http.StatusOK,
+	}).Times(1)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/webhooks/hook01/test", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var delivery models.WebhookDelivery
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &delivery))
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+	assert.Equal(t, models.WebhookEventTest, delivery.Payload.Event)
+
+	sWebhook.EXPECT().Get("default", "hook02").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/webhooks/hook02/test", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	sWebhook.EXPECT().Get("default", "hook01").Return(webhook, nil).Times(1)
+	sWebhook.EXPECT().ListDeadLetters("default", "hook01", &models.Filter{PageNo: 1, PageSize: 10}).Return(&models.WebhookDeadLetterList{
+		Total: 1,
+		Items: []models.WebhookDeadLetter{{ID: 1, Webhook: "hook01", Event: models.WebhookEventNodeOffline}},
+	}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/webhooks/hook01/deadletters?pageNo=1&pageSize=10", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), models.WebhookEventNodeOffline)
+}
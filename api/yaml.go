@@ -326,6 +326,12 @@ func (api *API) generateSecretResource(ns string, sec *corev1.Secret) (*specV1.S
 			return nil, err
 		}
 	}
+	// the labels of registries and certificates are kept too, so that they can be selected like other secrets
+	for k, v := range sec.Labels {
+		if _, ok := secret.Labels[k]; !ok {
+			secret.Labels[k] = v
+		}
+	}
 
 	err = validateSecret(secret)
 	if err != nil {
@@ -525,29 +531,30 @@ func (api *API) deleteConfig(ns string, r runtime.Object) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return api.deleteConfigByName(ns, cfg.Name)
+}
 
-	res, err := api.Config.Get(nil, ns, cfg.Name, "")
+func (api *API) deleteConfigByName(ns, name string) (string, error) {
+	res, err := api.Config.Get(nil, ns, name, "")
 	if err != nil {
 		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
 			return "", nil
 		}
-		api.log.Error("get config failed", log.Error(err), log.Any("name", cfg.Name), log.Any("namespace", ns))
+		api.log.Error("get config failed", log.Error(err), log.Any("name", name), log.Any("namespace", ns))
 		return "", err
 	}
 
 	appNames, err := api.Index.ListAppIndexByConfig(ns, res.Name)
 	if err != nil {
-		api.log.Error("list app index by config failed", log.Error(err), log.Any("name", cfg.Name), log.Any("namespace", ns))
+		api.log.Error("list app index by config failed", log.Error(err), log.Any("name", name), log.Any("namespace", ns))
 		return "", err
 	}
 
 	if len(appNames) > 0 {
-		return "", common.Error(common.ErrResourceHasBeenUsed,
-			common.Field("type", "config"),
-			common.Field("name", cfg.Name))
+		return "", api.resourceHasBeenUsedError(ns, "config", name, appNames)
 	}
 
-	return cfg.Name, api.Facade.DeleteConfig(ns, cfg.Name)
+	return name, api.Facade.DeleteConfig(ns, name)
 }
 
 func generateConfigData(userId string, cfgData, configData map[string]string) error {
@@ -701,6 +708,9 @@ func (api *API) updateApplication(ns string, r runtime.Object) (interface{}, err
 		(oldApp.Selector != app.Selector || !reflect.DeepEqual(oldApp.Labels, app.Labels) || !app.System) {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "selector，labels or system field can't be modified of sys apps"))
 	}
+	if err = api.Facade.CheckAppCanary(ns, oldApp); err != nil {
+		return nil, err
+	}
 
 	app.Version = oldApp.Version
 	app.CreationTimestamp = oldApp.CreationTimestamp
@@ -744,7 +754,10 @@ func (api *API) deleteApplication(ns string, r runtime.Object) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return api.deleteAppByName(ns, name)
+}
 
+func (api *API) deleteAppByName(ns, name string) (string, error) {
 	app, err := api.App.Get(ns, name, "")
 	if err != nil {
 		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
@@ -759,7 +772,7 @@ func (api *API) deleteApplication(ns string, r runtime.Object) (string, error) {
 		return "", common.Error(common.ErrAppReferencedByNode, common.Field("name", app.Name))
 	}
 
-	err = api.Facade.DeleteApp(ns, app.Name, app)
+	err = api.deleteApp(ns, app)
 	return app.Name, err
 }
 
@@ -19,6 +19,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	gmodels "github.com/baetyl/baetyl-cloud/v2/models"
@@ -894,6 +895,7 @@ func TestAPI_UpdateDeployApp(t *testing.T) {
 	}
 
 	sApp.EXPECT().Get("default", "nginx", "").Return(expectApp, nil).Times(1)
+	sFacade.EXPECT().CheckAppCanary("default", expectApp).Return(nil).Times(1)
 	sConfig.EXPECT().Get(nil, "default", "common-cm", "").Return(cfg, nil).Times(2)
 	sSecret.EXPECT().Get("default", "dcell", "").Return(secret, nil).Times(4)
 	sSecret.EXPECT().Get("default", "myregistrykey", "").Return(registry, nil).Times(4)
@@ -929,6 +931,28 @@ func TestAPI_UpdateDeployApp(t *testing.T) {
 	resapp.Services[0].Resources.Requests = nil
 	appView, _ := api.ToApplicationView(resapp)
 	assert.DeepEqual(t, &aaa, appView)
+
+	// bad case: the app has a canary in progress
+	expectApp.Version = "3"
+	sApp.EXPECT().Get("default", "nginx", "").Return(expectApp, nil).Times(1)
+	sFacade.EXPECT().CheckAppCanary("default", expectApp).Return(common.Error(common.ErrRequestParamInvalid,
+		common.Field("error", "the app (nginx) has a canary in progress, promote or abort the canary first"))).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "common-cm", "").Return(cfg, nil).AnyTimes()
+	sSecret.EXPECT().Get("default", "dcell", "").Return(secret, nil).AnyTimes()
+	sSecret.EXPECT().Get("default", "myregistrykey", "").Return(registry, nil).AnyTimes()
+
+	buf = new(bytes.Buffer)
+	w = multipart.NewWriter(buf)
+	fw, _ = w.CreateFormFile("file", "app.yaml")
+	io.Copy(fw, strings.NewReader(updateAppDeploy))
+	w.Close()
+
+	req, _ = http.NewRequest(http.MethodPut, "/v1/yaml", buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	re = httptest.NewRecorder()
+	router.ServeHTTP(re, req)
+	assert.Equal(t, http.StatusBadRequest, re.Code)
 }
 
 func TestAPI_UpdateDsApp(t *testing.T) {
@@ -1140,6 +1164,7 @@ func TestAPI_UpdateDsApp(t *testing.T) {
 	}
 
 	sApp.EXPECT().Get("default", "nginx", "").Return(dsApp, nil).Times(1)
+	sFacade.EXPECT().CheckAppCanary("default", dsApp).Return(nil).Times(1)
 	sConfig.EXPECT().Get(nil, "default", "common-cm", "").Return(cfg, nil).Times(2)
 	sSecret.EXPECT().Get("default", "dcell", "").Return(secret, nil).Times(4)
 	sSecret.EXPECT().Get("default", "myregistrykey", "").Return(registry, nil).Times(4)
@@ -1242,6 +1267,7 @@ func TestAPI_UpdateJobApp(t *testing.T) {
 	}
 
 	sApp.EXPECT().Get("default", "pi", "").Return(jobApp, nil).Times(1)
+	sFacade.EXPECT().CheckAppCanary("default", jobApp).Return(nil).Times(1)
 	sFacade.EXPECT().UpdateApp("default", jobApp, gomock.Any(), nil).Return(updateApp, nil).Times(1)
 
 	buf := new(bytes.Buffer)
@@ -0,0 +1,368 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin/binding"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	yamlResourceSecret = "secret"
+	yamlResourceConfig = "config"
+	yamlResourceApp    = "app"
+	yamlResourceSvc    = "service"
+
+	// yamlKindApp the kind of the pruned apps in the results, whatever their workloads are
+	yamlKindApp = "Application"
+)
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// yamlDocument a document of a multi-document yaml stream
+type yamlDocument struct {
+	index int
+	line  int
+	obj   runtime.Object
+}
+
+// ApplyYamlResource creates or updates all resources of a multi-document yaml,
+// the secrets and configs are applied before the apps which may reference them
+func (api *API) ApplyYamlResource(c *common.Context) (interface{}, error) {
+	params := &models.YamlApplyParams{}
+	if err := c.ShouldBindQuery(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	var label map[string]string
+	if params.Label != "" {
+		sel, err := labels.ConvertSelectorToLabelsMap(params.Label)
+		if err != nil || len(sel) != 1 {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "label should be in the form of key=value"))
+		}
+		label = sel
+	}
+	if params.Prune && label == nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "label is required to prune"))
+	}
+
+	data, err := readYamlBody(c)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := parseYamlDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		return yamlApplyOrder(docs[i].obj) < yamlApplyOrder(docs[j].obj)
+	})
+
+	ns := c.GetNamespace()
+	res := &models.YamlApplyResultList{Items: []models.YamlApplyResult{}}
+	applied := map[string]bool{}
+	failed := false
+	for _, doc := range docs {
+		r := api.applyYamlDocument(ns, c.GetUser().ID, doc, label)
+		if r.Action == models.YamlApplyFailed {
+			failed = true
+		}
+		applied[yamlResourceType(r.Kind)+"/"+r.Name] = true
+		res.Items = append(res.Items, r)
+	}
+	sort.SliceStable(res.Items, func(i, j int) bool {
+		return res.Items[i].Index < res.Items[j].Index
+	})
+
+	// nothing is pruned if any document failed, since the resources may be still referenced
+	if params.Prune && !failed {
+		pruned, err := api.pruneYamlResources(ns, params.Label, applied)
+		if err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, pruned...)
+	}
+	res.Total = len(res.Items)
+	return res, nil
+}
+
+// readYamlBody reads the yaml from the uploaded file if the request is a multipart form, otherwise from the body
+func readYamlBody(c *common.Context) ([]byte, error) {
+	if c.ContentType() != binding.MIMEMultipartPOSTForm {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+		return data, nil
+	}
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	defer file.Close()
+	if err = fileCheck(header.Filename); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// parseYamlDocuments splits the yaml stream into documents and decodes them,
+// the error of a document points at its index and the line in the whole stream
+func parseYamlDocuments(data []byte) ([]yamlDocument, error) {
+	var docs []yamlDocument
+	var content []string
+	start := 1
+	decode := func() error {
+		line := 0
+		for i, l := range content {
+			if t := strings.TrimSpace(l); t != "" && !strings.HasPrefix(t, "#") {
+				line = start + i
+				break
+			}
+		}
+		// ignore empty documents
+		if line == 0 {
+			return nil
+		}
+		index := len(docs)
+		obj, gvk, err := scheme.Codecs.UniversalDeserializer().Decode([]byte(strings.Join(content, "")), nil, nil)
+		if err != nil {
+			if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+				if n, e := strconv.Atoi(m[1]); e == nil {
+					line = start + n - 1
+				}
+			}
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("document %d at line %d: %s", index, line, err.Error())))
+		}
+		if yamlResourceType(gvk.Kind) == "" {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("document %d at line %d: kind %s is not supported", index, line, gvk.Kind)))
+		}
+		docs = append(docs, yamlDocument{index: index, line: line, obj: obj})
+		return nil
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	for i, l := range lines {
+		if t := strings.TrimSpace(l); t == "---" || strings.HasPrefix(t, "--- ") {
+			if err := decode(); err != nil {
+				return nil, err
+			}
+			content = nil
+			start = i + 2
+			continue
+		}
+		content = append(content, l)
+	}
+	if err := decode(); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func yamlResourceType(kind string) string {
+	switch kind {
+	case TypeSecret:
+		return yamlResourceSecret
+	case TypeConfig:
+		return yamlResourceConfig
+	case TypeDeploy, TypeDaemonset, TypeJob, yamlKindApp:
+		return yamlResourceApp
+	case TypeService:
+		return yamlResourceSvc
+	}
+	return ""
+}
+
+func yamlApplyOrder(obj runtime.Object) int {
+	switch yamlResourceType(obj.GetObjectKind().GroupVersionKind().Kind) {
+	case yamlResourceSecret, yamlResourceConfig:
+		return 0
+	case yamlResourceApp:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (api *API) applyYamlDocument(ns, userID string, doc yamlDocument, label map[string]string) models.YamlApplyResult {
+	kind := doc.obj.GetObjectKind().GroupVersionKind().Kind
+	res := models.YamlApplyResult{Index: doc.index, Line: doc.line, Kind: kind}
+	accessor, err := meta.Accessor(doc.obj)
+	if err != nil {
+		res.Action = models.YamlApplyFailed
+		res.Error = err.Error()
+		return res
+	}
+	res.Name = accessor.GetName()
+	if label != nil && kind != TypeService {
+		ls := accessor.GetLabels()
+		if ls == nil {
+			ls = map[string]string{}
+		}
+		for k, v := range label {
+			ls[k] = v
+		}
+		accessor.SetLabels(ls)
+	}
+
+	exist, err := api.isYamlResourceExist(ns, kind, res.Name)
+	if err == nil {
+		if exist {
+			res.Action = models.YamlApplyUpdated
+			err = api.updateYamlResource(ns, userID, doc.obj)
+		} else {
+			res.Action = models.YamlApplyCreated
+			err = api.createYamlResource(ns, userID, doc.obj)
+		}
+	}
+	if err != nil {
+		res.Action = models.YamlApplyFailed
+		res.Error = err.Error()
+	}
+	return res
+}
+
+func (api *API) isYamlResourceExist(ns, kind, name string) (bool, error) {
+	var exist bool
+	var err error
+	switch yamlResourceType(kind) {
+	case yamlResourceSecret:
+		var secret *specV1.Secret
+		secret, err = api.Secret.Get(ns, name, "")
+		exist = secret != nil
+	case yamlResourceConfig:
+		var config *specV1.Configuration
+		config, err = api.Config.Get(nil, ns, name, "")
+		exist = config != nil
+	case yamlResourceApp:
+		var app *specV1.Application
+		app, err = api.App.Get(ns, name, "")
+		exist = app != nil
+	default:
+		// services are merged into the ports of the selected apps, which are always updated
+		return true, nil
+	}
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return exist, nil
+}
+
+func (api *API) createYamlResource(ns, userID string, r runtime.Object) error {
+	var err error
+	switch yamlResourceType(r.GetObjectKind().GroupVersionKind().Kind) {
+	case yamlResourceSecret:
+		_, err = api.generateSecret(ns, r)
+	case yamlResourceConfig:
+		_, err = api.generateConfig(ns, userID, r)
+	case yamlResourceApp:
+		_, err = api.generateApplication(ns, r)
+	case yamlResourceSvc:
+		err = api.generateService(ns, r)
+	}
+	return err
+}
+
+func (api *API) updateYamlResource(ns, userID string, r runtime.Object) error {
+	var err error
+	switch yamlResourceType(r.GetObjectKind().GroupVersionKind().Kind) {
+	case yamlResourceSecret:
+		_, err = api.updateSecret(ns, r)
+	case yamlResourceConfig:
+		_, err = api.updateConfig(ns, userID, r)
+	case yamlResourceApp:
+		_, err = api.updateApplication(ns, r)
+	case yamlResourceSvc:
+		err = api.updateService(ns, r)
+	}
+	return err
+}
+
+// pruneYamlResources deletes the resources selected by the label but not applied,
+// the apps are deleted first to release the configs and secrets they reference
+func (api *API) pruneYamlResources(ns, label string, applied map[string]bool) ([]models.YamlApplyResult, error) {
+	opts := &models.ListOptions{LabelSelector: label}
+	apps, err := api.App.List(ns, opts)
+	if err != nil {
+		return nil, err
+	}
+	configs, err := api.Config.List(ns, opts)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := api.Secret.List(ns, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []models.YamlApplyResult
+	prune := func(kind, name string, del func() error) {
+		if applied[yamlResourceType(kind)+"/"+name] {
+			return
+		}
+		r := models.YamlApplyResult{Index: -1, Kind: kind, Name: name, Action: models.YamlApplyPruned}
+		if err := del(); err != nil {
+			r.Action = models.YamlApplyFailed
+			r.Error = err.Error()
+		}
+		res = append(res, r)
+	}
+	for _, app := range apps.Items {
+		if CheckIsSysResources(app.Labels) {
+			continue
+		}
+		name := app.Name
+		prune(yamlKindApp, name, func() error {
+			_, err := api.deleteAppByName(ns, name)
+			return err
+		})
+	}
+	for _, config := range configs.Items {
+		if CheckIsSysResources(config.Labels) {
+			continue
+		}
+		name := config.Name
+		prune(TypeConfig, name, func() error {
+			_, err := api.deleteConfigByName(ns, name)
+			return err
+		})
+	}
+	for _, secret := range secrets.Items {
+		if CheckIsSysResources(secret.Labels) {
+			continue
+		}
+		name, secretType := secret.Name, "secret"
+		switch secret.Labels[specV1.SecretLabel] {
+		case specV1.SecretRegistry:
+			secretType = "registry"
+		case specV1.SecretCertificate:
+			secretType = "certificate"
+		}
+		prune(TypeSecret, name, func() error {
+			_, err := api.DeleteSecretResource(ns, name, secretType)
+			return err
+		})
+	}
+	return res, nil
+}
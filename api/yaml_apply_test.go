@@ -0,0 +1,146 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func applyYaml(router http.Handler, query, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/v1/yaml/apply"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-yaml")
+	re := httptest.NewRecorder()
+	router.ServeHTTP(re, req)
+	return re
+}
+
+func TestAPI_ApplyYaml(t *testing.T) {
+	api, router, mockCtl := initYamlAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sIndex := ms.NewMockIndexService(mockCtl)
+	sFacade := mf.NewMockFacade(mockCtl)
+
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	api.Index = sIndex
+	api.Facade = sFacade
+
+	// the job referencing nothing is placed before the config on purpose, the config is still applied first
+	stream := testAppJob + "\n---" + commonCfg
+
+	// good case: create all and prune the resources not applied any more
+	jobApp := &specV1.Application{Name: "pi", Namespace: "default", Type: specV1.AppTypeContainer, Workload: "job"}
+	oldApp := &specV1.Application{Name: "old-app", Namespace: "default"}
+	oldSecret := &specV1.Secret{Name: "old-registry", Namespace: "default", Labels: map[string]string{
+		"deploy": "demo", specV1.SecretLabel: specV1.SecretRegistry}}
+	opts := &models.ListOptions{LabelSelector: "deploy=demo"}
+	gomock.InOrder(
+		sConfig.EXPECT().Get(nil, "default", "common-cm", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(2),
+		sFacade.EXPECT().CreateConfig("default", gomock.Any()).DoAndReturn(
+			func(ns string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+				assert.Equal(t, "demo", cfg.Labels["deploy"])
+				assert.Equal(t, "abc", cfg.Labels["abc"])
+				return cfg, nil
+			}),
+		sApp.EXPECT().Get("default", "pi", "").Return(nil, nil).Times(2),
+		sFacade.EXPECT().CreateApp("default", nil, gomock.Any(), nil).DoAndReturn(
+			func(ns string, _ interface{}, app *specV1.Application, _ interface{}) (*specV1.Application, error) {
+				assert.Equal(t, "demo", app.Labels["deploy"])
+				return jobApp, nil
+			}),
+		sApp.EXPECT().List("default", opts).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "pi"}, {Name: "old-app"}}}, nil),
+		sConfig.EXPECT().List("default", opts).Return(&models.ConfigurationList{Items: []specV1.Configuration{{Name: "common-cm"}}}, nil),
+		sSecret.EXPECT().List("default", opts).Return(&models.SecretList{Items: []specV1.Secret{*oldSecret}}, nil),
+		sApp.EXPECT().Get("default", "old-app", "").Return(oldApp, nil),
+		sFacade.EXPECT().DeleteApp("default", "old-app", oldApp).Return(nil),
+		sSecret.EXPECT().Get("default", "old-registry", "").Return(oldSecret, nil),
+		sIndex.EXPECT().ListAppIndexBySecret("default", "old-registry").Return(nil, nil),
+		sFacade.EXPECT().DeleteSecret("default", "old-registry").Return(nil),
+	)
+
+	re := applyYaml(router, "?prune=true&label=deploy=demo", stream)
+	assert.Equal(t, http.StatusOK, re.Code)
+	var res models.YamlApplyResultList
+	assert.NoError(t, json.Unmarshal(re.Body.Bytes(), &res))
+	assert.Equal(t, 4, res.Total)
+	assert.Equal(t, []models.YamlApplyResult{
+		{Index: 0, Line: 2, Kind: TypeJob, Name: "pi", Action: models.YamlApplyCreated},
+		{Index: 1, Line: 22, Kind: TypeConfig, Name: "common-cm", Action: models.YamlApplyCreated},
+		{Index: -1, Kind: yamlKindApp, Name: "old-app", Action: models.YamlApplyPruned},
+		{Index: -1, Kind: TypeSecret, Name: "old-registry", Action: models.YamlApplyPruned},
+	}, res.Items)
+
+	// a failed document is reported, and nothing is pruned
+	sConfig.EXPECT().Get(nil, "default", "common-cm", "").Return(nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "db down")))
+	sApp.EXPECT().Get("default", "pi", "").Return(nil, nil).Times(2)
+	sFacade.EXPECT().CreateApp("default", nil, gomock.Any(), nil).Return(jobApp, nil)
+
+	re = applyYaml(router, "?prune=true&label=deploy=demo", stream)
+	assert.Equal(t, http.StatusOK, re.Code)
+	res = models.YamlApplyResultList{}
+	assert.NoError(t, json.Unmarshal(re.Body.Bytes(), &res))
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, models.YamlApplyCreated, res.Items[0].Action)
+	assert.Equal(t, models.YamlApplyFailed, res.Items[1].Action)
+	assert.Contains(t, res.Items[1].Error, "db down")
+
+	// bad case: label is required to prune
+	re = applyYaml(router, "?prune=true", stream)
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+	assert.Contains(t, re.Body.String(), "label is required to prune")
+
+	// bad case: label is not a single key=value
+	re = applyYaml(router, "?label=a", stream)
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+}
+
+func TestParseYamlDocuments(t *testing.T) {
+	stream := `# configs
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+---
+# the second document
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+`
+	docs, err := parseYamlDocuments([]byte(stream))
+	assert.NoError(t, err)
+	assert.Len(t, docs, 2)
+	assert.Equal(t, 0, docs[0].index)
+	assert.Equal(t, 2, docs[0].line)
+	assert.Equal(t, 1, docs[1].index)
+	assert.Equal(t, 9, docs[1].line)
+
+	// the error points at the document and the line in the stream
+	_, err = parseYamlDocuments([]byte(stream + " data: x\n"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "document 1 at line 12")
+
+	_, err = parseYamlDocuments([]byte(stream + "---\napiVersion: v1\nkind: Pod\nmetadata:\n  name: c\n"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "document 2 at line 14: kind Pod is not supported")
+}
@@ -236,6 +236,7 @@ func initYamlAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		yaml.POST("", mockIM, common.Wrapper(api.CreateYamlResource))
 		yaml.PUT("", mockIM, common.Wrapper(api.UpdateYamlResource))
 		yaml.POST("/delete", mockIM, common.Wrapper(api.DeleteYamlResource))
+		yaml.POST("/apply", mockIM, common.Wrapper(api.ApplyYamlResource))
 	}
 
 	return api, router, mockCtl
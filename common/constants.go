@@ -20,6 +20,10 @@ const (
 	ResourceName = "resourceName"
 	// ResourceVersion resource version
 	ResourceVersion = "resourceVersion"
+	// HeaderIfMatch the header carrying the resource version the delete is based on, raw or as the etag
+	HeaderIfMatch = "If-Match"
+	// HeaderETag the tag of the response, which is the resource version of the apps and configs
+	HeaderETag = "ETag"
 	// ResourceInvisible resource invisible
 	ResourceInvisible = "resource-invisible"
 	// Application application resource
@@ -41,6 +45,18 @@ const (
 	Deployment Resource = "deployment"
 	// Node node resource
 	Node Resource = "node"
+	// NodeGroup nodegroup resource
+	NodeGroup Resource = "nodegroup"
+	// AppTemplate apptemplate resource
+	AppTemplate Resource = "apptemplate"
+	// Webhook webhook resource
+	Webhook Resource = "webhook"
+	// Property property resource
+	Property Resource = "property"
+	// Module module resource
+	Module Resource = "module"
+	// Job job resource
+	Job Resource = "job"
 	// Shadow shadow resource
 	Shadow Resource = "shadow"
 	// NodeDesire nodedesire resource
@@ -132,6 +148,20 @@ const (
 	LabelCluster     = "baetyl-cluster"
 	LabelNodeMode    = "baetyl-node-mode"
 	LabelAppMode     = "baetyl-app-mode"
+	// LabelConfigSchema the name of the schema config validating the content of a config
+	LabelConfigSchema = "baetyl-config-schema"
+	// LabelConfigTemplate the kv items of the config are templates rendered for each node
+	LabelConfigTemplate = "baetyl-config-template"
+	// LabelPinnedConfigs the names of the configs joined by "_" whose versions referenced by the volumes of the app
+	// are pinned, instead of following the latest versions of the configs
+	LabelPinnedConfigs = "baetyl-pinned-configs"
+	// LabelAutoMountSecrets the secrets labeled for the app by LabelAppName are mounted automatically
+	LabelAutoMountSecrets = "baetyl-auto-mount-secrets"
+	// LabelNodeGroup the node group the app is deployed to, the selector of the app is resolved from the group
+	LabelNodeGroup = "baetyl-node-group"
+	// LabelSecretEncrypted marks the stored secrets whose values are encrypted by the kms, the label is set and
+	// removed by the secret service only, so it is never seen by the users
+	LabelSecretEncrypted = "baetyl-secret-encrypted"
 )
 
 const (
@@ -152,6 +182,12 @@ const (
 	AnnotationNodeSelector    = BaetylCloudGroup + "/" + NodeSelector
 	AnnotationWorkLoad        = BaetylCloudGroup + "/" + WorkLoad
 	AnnotationJobConfig       = BaetylCloudGroup + "/" + JobConfig
+	// AnnotationNodeTagPrefix the prefix of the annotations keeping the descriptive tags of a node
+	AnnotationNodeTagPrefix = "tag." + BaetylCloudGroup + "/"
+	// AttributeNodeDrain the attribute keeping the drain state of a node
+	AttributeNodeDrain = "BaetylNodeDrain"
+	// AttributeNodeMaintenance the attribute keeping the maintenance state of a node
+	AttributeNodeMaintenance = "BaetylNodeMaintenance"
 )
 
 const (
@@ -225,6 +261,8 @@ const (
 	NodeProps  = "nodeprops"
 	NodeInfo   = "node"
 	NodeStats  = "nodestats"
+	// NodeCommands the pending commands in the delta to the node, and the results of them in the report of the node
+	NodeCommands = "commands"
 )
 
 const (
@@ -150,8 +150,16 @@ type sucResponse struct {
 	Success bool `json:"success"`
 }
 
+// Accepted the response of a request accepted to be processed in background, which is responded with 202
+type Accepted struct {
+	Body interface{}
+}
+
 // PackageResponse PackageResponse
 func PackageResponse(res interface{}) (int, interface{}) {
+	if a, ok := res.(*Accepted); ok {
+		return http.StatusAccepted, a.Body
+	}
 	if res == nil {
 		res = &sucResponse{
 			Success: true,
@@ -181,6 +189,9 @@ func PopulateFailedResponse(cc *Context, err error, abort bool) {
 		"message": err.Error(),
 		k:         v,
 	}
+	if ve, ok := err.(*ValidationError); ok {
+		body["errors"] = ve.Errors
+	}
 	if abort {
 		cc.AbortWithStatusJSON(status, body)
 	} else {
@@ -269,6 +280,10 @@ func WrapperRaw(handler HandlerFunc, abort bool) func(c *gin.Context) {
 		if res == nil {
 			return
 		}
+		if a, ok := res.(*Accepted); ok {
+			cc.PureJSON(PackageResponse(a))
+			return
+		}
 		if data, ok := res.([]byte); ok {
 			cc.Data(http.StatusOK, "application/octet-stream", data)
 		} else {
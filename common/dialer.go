@@ -0,0 +1,64 @@
+package common
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// metadataIPs the addresses of the metadata services of the cloud providers out of the link-local ranges
+var metadataIPs = []net.IP{
+	net.ParseIP("100.100.100.200"),
+	net.ParseIP("fd00:ec2::254"),
+}
+
+// IsForbiddenIP returns whether the ip is forbidden to the outbound requests made on behalf of the users, which are
+// the loopback, link-local, unspecified, multicast and metadata service addresses, and the private ones unless allowed
+func IsForbiddenIP(ip net.IP, allowPrivate bool) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, m := range metadataIPs {
+		if m.Equal(ip) {
+			return true
+		}
+	}
+	return !allowPrivate && ip.IsPrivate()
+}
+
+// GuardedDialControl rejects the connections to the addresses forbidden by IsForbiddenIP, it is checked at dial time
+// against the resolved address, so that the hosts resolved to the forbidden addresses and the redirects are rejected too
+func GuardedDialControl(allowPrivate bool) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		ip := net.ParseIP(host)
+		if ip == nil || IsForbiddenIP(ip, allowPrivate) {
+			return fmt.Errorf("the address (%s) is forbidden", host)
+		}
+		return nil
+	}
+}
+
+// NewGuardedHTTPClient returns the http client of the outbound requests made on behalf of the users,
+// which never dials the addresses forbidden by IsForbiddenIP, nor goes through the proxies of the environment
+func NewGuardedHTTPClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: GuardedDialControl(allowPrivate),
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
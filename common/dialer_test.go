@@ -0,0 +1,35 @@
+package common
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsForbiddenIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "::1", "169.254.169.254", "fe80::1", "0.0.0.0", "224.0.0.1", "100.100.100.200", "fd00:ec2::254"} {
+		assert.True(t, IsForbiddenIP(net.ParseIP(ip), true), ip)
+	}
+	for _, ip := range []string{"10.0.0.1", "172.16.0.1", "192.168.1.1", "fd12::1"} {
+		assert.False(t, IsForbiddenIP(net.ParseIP(ip), true), ip)
+		assert.True(t, IsForbiddenIP(net.ParseIP(ip), false), ip)
+	}
+	assert.False(t, IsForbiddenIP(net.ParseIP("8.8.8.8"), false))
+}
+
+func TestNewGuardedHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	_, err := NewGuardedHTTPClient(time.Second, true).Get(server.URL)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is forbidden")
+
+	resp, err := server.Client().Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+}
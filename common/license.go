@@ -0,0 +1,47 @@
+package common
+
+import (
+	"fmt"
+)
+
+// the modes of handling the requests exceeding the license limits
+const (
+	// LicenseModeEnforce rejects the requests exceeding the limits
+	LicenseModeEnforce = "enforce"
+	// LicenseModeWarn allows the requests exceeding the limits with a warning
+	LicenseModeWarn = "warn"
+	// LicenseModeReport allows the requests exceeding the limits, which are only reported by the overage header
+	LicenseModeReport = "report"
+
+	// HeaderLicenseOverage the header of the responses of the requests allowed over the license limits
+	HeaderLicenseOverage = "X-License-Overage"
+)
+
+// QuotaError the usage of a resource exceeding the limit of the license
+type QuotaError struct {
+	Name      string
+	Limit     int
+	Used      int
+	Requested int
+}
+
+func (e *QuotaError) Code() string {
+	return ErrLicenseQuota
+}
+
+func (e *QuotaError) Error() string {
+	return Error(ErrLicenseQuota,
+		Field("name", e.Name),
+		Field("limit", e.Limit),
+		Field("used", e.Used),
+		Field("requested", e.Requested)).Error()
+}
+
+// Overage returns the overage as the value of the overage header, such as maxNodeCount; limit=10; used=10
+func (e *QuotaError) Overage() string {
+	res := fmt.Sprintf("%s; limit=%d; used=%d", e.Name, e.Limit, e.Used)
+	if e.Requested > 0 {
+		res += fmt.Sprintf("; requested=%d", e.Requested)
+	}
+	return res
+}
@@ -42,6 +42,7 @@ const (
 	ErrAppNameConflict         = "ErrAppNameConflict"
 	ErrVolumeNotFoundWhenMount = "ErrVolumeNotFoundWhenMount"
 	ErrAppReferencedByNode     = "ErrAppReferencedByNode"
+	ErrAppDependencyMissing    = "ErrAppDependencyMissing"
 	// * node
 	ErrNodeNumMaxLimit       = "ErrNodeNumMaxLimit"
 	ErrNodeNumQueryException = "ErrNodeNumQueryException"
@@ -96,9 +97,31 @@ const (
 	ErrResourceInvisible = "ErrResourceInvisible"
 	ErrConvertConflict   = "ErrConvertConflict"
 
-	ErrPubsubTimeout   = "ErrPubsubTimeout"
-	ErrUpdateSubLabels = "ErrUpdateSubLabels"
-	ErrDataTooLarge    = "ErrDataTooLarge"
+	ErrPubsubTimeout    = "ErrPubsubTimeout"
+	ErrUpdateSubLabels  = "ErrUpdateSubLabels"
+	ErrDataTooLarge     = "ErrDataTooLarge"
+	ErrTooManyRequests  = "ErrTooManyRequests"
+	ErrServiceReadOnly  = "ErrServiceReadOnly"
+	ErrBodyTooLarge     = "ErrBodyTooLarge"
+	ErrPermissionDenied = "ErrPermissionDenied"
+	ErrFeatureDisabled  = "ErrFeatureDisabled"
+	// ErrTemporaryFailure the request failed transiently and can be retried later
+	ErrTemporaryFailure   = "ErrTemporaryFailure"
+	ErrRegistryAuthFailed = "ErrRegistryAuthFailed"
+	// ErrNotSupported the operation is not supported by the backend, such as a plugin
+	ErrNotSupported = "ErrNotSupported"
+	// ErrVersionConflict the resource was modified since the version the request is based on
+	ErrVersionConflict = "ErrVersionConflict"
+	// ErrHostPortConflict the host ports of the app are bound by the other apps on the same nodes
+	ErrHostPortConflict = "ErrHostPortConflict"
+	// ErrObjectRejected the object uploaded or going to be uploaded is rejected by the upload hook
+	ErrObjectRejected = "ErrObjectRejected"
+	// ErrAPIGone the deprecated api is disabled, the replacement should be used instead
+	ErrAPIGone = "ErrAPIGone"
+	// ErrPreconditionFailed the resource is not of the version the delete is based on
+	ErrPreconditionFailed = "ErrPreconditionFailed"
+	// ErrGatewayTimeout the backend, such as the function invoked, did not respond in time
+	ErrGatewayTimeout = "ErrGatewayTimeout"
 )
 
 var templates = map[Code]string{
@@ -113,7 +136,7 @@ var templates = map[Code]string{
 	ErrResourceNotFound:        "访问不存在的资源。\nThe {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} is not found{{if .namespace}} in namespace({{.namespace}}){{end}}.",
 	ErrResourceAccessForbidden: "The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} can not be accessed{{if .namespace}} in namespace({{.namespace}}){{end}}.",
 	ErrResourceConflict:        "The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} already exist.",
-	ErrResourceHasBeenUsed:     "该资源名称已被占用，请更换命名。The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} has been used.",
+	ErrResourceHasBeenUsed:     "该资源名称已被占用，请更换命名。The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} has been used{{if .apps}} by the applications ({{.apps}}){{end}}.",
 	ErrSubResourceExist:        "该资源下存在子资源未删除，请删除后重试。The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} exist",
 	ErrResourceDeleteForbidden: "The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} can not be deleted{{if .namespace}} in namespace({{.namespace}}){{end}}",
 	// * volumes
@@ -125,6 +148,7 @@ var templates = map[Code]string{
 	ErrVolumeNotFoundWhenMount: "The mount volume name{{if .name}}({{.name}}){{end}} can't find in the Volumes[].",
 	ErrNodeNotReady:            "The node {{if .name}}({{.name}} ){{end}}is not ready, please retry later.",
 	ErrAppReferencedByNode:     "The {{if .name}}({{.name}}){{end}} app is still referenced by a node.",
+	ErrAppDependencyMissing:    "The app{{if .name}} ({{.name}}){{end}}{{if .version}} of version ({{.version}}){{end}} references resources that no longer exist:{{if .missing}} {{.missing}}{{end}}.",
 	// * node
 	ErrNodeNumMaxLimit:       "节点个数已达上线，请联系相关人员申请更高节点限额。\nThe number of nodes reaches the maximum limit",
 	ErrNodeNumQueryException: "The number of nodes is null",
@@ -165,7 +189,7 @@ var templates = map[Code]string{
 
 	// * License
 	ErrLicenseExpired:      "The license {{if .name}}({{.name}}){{end}} has expired. {{if .error}} ({{.error}}){{end}}",
-	ErrLicenseQuota:        "Check {{if .name}}({{.name}}){{end}} quota failed, the limited number is {{if .limit}}({{.limit}}){{end}}",
+	ErrLicenseQuota:        "Check {{if .name}}({{.name}}){{end}} quota failed, the limited number is {{if .limit}}({{.limit}}){{end}}{{if .used}}, the used number is ({{.used}}){{end}}{{if .requested}} and the requested number is ({{.requested}}){{end}}",
 	ErrLicenseQuotaAcquire: "Check {{if .name}}({{.name}}){{end}} quota acquire failed, the acquire number is {{if .number}}({{.number}}){{end}}",
 	ErrLicenseQuotaRelease: "Check {{if .name}}({{.name}}){{end}} quota release failed, the acquire number is {{if .number}}({{.number}}){{end}}",
 
@@ -179,9 +203,23 @@ var templates = map[Code]string{
 	ErrResourceInvisible: "The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} is not visible.",
 	ErrConvertConflict:   "Problem with converting {{if .name}} ({{.name}}){{end}}.{{if .error}} ({{.error}}){{end}}",
 
-	ErrPubsubTimeout:   "Publish or subscribe message timeout. {{if .error}} ({{.error}}){{end}}",
-	ErrUpdateSubLabels: "Failed to update sub node labels. {{if .error}} ({{.error}}){{end}}",
-	ErrDataTooLarge:    "数据量过大。\nData too large. Resource {{if .name}}({{.name}}){{end}}, size={{if .size}}({{.size}}){{end}}, max={{if .max}}({{.max}}){{end}}",
+	ErrPubsubTimeout:      "Publish or subscribe message timeout. {{if .error}} ({{.error}}){{end}}",
+	ErrUpdateSubLabels:    "Failed to update sub node labels. {{if .error}} ({{.error}}){{end}}",
+	ErrDataTooLarge:       "数据量过大。\nData too large. Resource {{if .name}}({{.name}}){{end}}, size={{if .size}}({{.size}}){{end}}, max={{if .max}}({{.max}}){{end}}",
+	ErrTooManyRequests:    "请求过多，请稍后重试。\nToo many requests.{{if .error}} ({{.error}}){{end}}",
+	ErrBodyTooLarge:       "请求体过大。\nThe request body is too large.{{if .max}} (max {{.max}} bytes){{end}}",
+	ErrServiceReadOnly:    "系统维护中，暂不支持修改。\nThe service is read-only for maintenance.{{if .message}} ({{.message}}){{end}}",
+	ErrPermissionDenied:   "权限不足。\nThe permission ({{.permission}}) is required{{if .role}}, which is not granted to the role ({{.role}}){{end}}.",
+	ErrFeatureDisabled:    "功能未开启。\nThe feature ({{.feature}}) is not enabled in the namespace.",
+	ErrTemporaryFailure:   "服务暂时不可用，请稍后重试。\nThe request failed temporarily, please retry later.{{if .error}} ({{.error}}){{end}}",
+	ErrRegistryAuthFailed: "镜像仓库认证失败。\nThe registry{{if .name}} ({{.name}}){{end}} rejected the credentials.{{if .error}} ({{.error}}){{end}}",
+	ErrNotSupported:       "不支持该操作。\nThe operation{{if .operation}} ({{.operation}}){{end}} is not supported{{if .source}} by the source ({{.source}}){{end}}.",
+	ErrVersionConflict:    "资源已被修改，请刷新后重试。\nThe {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} has been modified, the current version is {{.current}}, not {{.version}}.",
+	ErrHostPortConflict:   "主机端口冲突。\nThe host ports of the app ({{.name}}) are bound by the other apps on the same nodes ({{.conflicts}}).",
+	ErrObjectRejected:     "对象被拒绝。\nThe object ({{.name}}) is rejected.{{if .reason}} ({{.reason}}){{end}}",
+	ErrAPIGone:            "接口已下线。\nThe deprecated api is disabled, please use {{.replacement}} instead.",
+	ErrPreconditionFailed: "资源已被修改或删除，请刷新后重试。\nThe {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} {{if .current}}is of the version {{.current}}{{else}}does not exist{{end}}, not {{.version}}.",
+	ErrGatewayTimeout:     "请求超时。\nThe {{if .type}}{{.type}} {{end}}{{if .name}}({{.name}}) {{end}}did not respond {{if .timeout}}within {{.timeout}}{{else}}in time{{end}}.",
 }
 
 func getHTTPStatus(c Code) int {
@@ -190,8 +228,24 @@ func getHTTPStatus(c Code) int {
 		return http.StatusNotFound
 	case ErrRequestAccessDenied:
 		return http.StatusUnauthorized
-	case ErrResourceHasBeenUsed:
+	case ErrResourceHasBeenUsed, ErrPermissionDenied, ErrFeatureDisabled:
 		return http.StatusForbidden
+	case ErrResourceConflict, ErrVersionConflict, ErrHostPortConflict:
+		return http.StatusConflict
+	case ErrTooManyRequests:
+		return http.StatusTooManyRequests
+	case ErrAPIGone:
+		return http.StatusGone
+	case ErrPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case ErrBodyTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case ErrServiceReadOnly, ErrTemporaryFailure:
+		return http.StatusServiceUnavailable
+	case ErrNotSupported:
+		return http.StatusNotImplemented
+	case ErrGatewayTimeout:
+		return http.StatusGatewayTimeout
 	case ErrUnknown:
 		return http.StatusInternalServerError
 	default:
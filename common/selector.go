@@ -0,0 +1,40 @@
+package common
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// ParseLabelSelector parses a kubernetes-style label selector, such as `env=prod,region!=cn-north,tier in (a,b),!canary`.
+// The error returned for an invalid selector is ErrRequestParamInvalid containing the offending token.
+func ParseLabelSelector(selector string) (labels.Selector, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return nil, Error(ErrRequestParamInvalid, Field("error", "invalid selector: "+err.Error()))
+	}
+	return s, nil
+}
+
+// SelectorEqualities returns the label key/value pairs that a node must carry to match the selector,
+// a value of empty string means the key only needs to exist. It is used to pre-filter rows in storage.
+func SelectorEqualities(s labels.Selector) map[string]string {
+	res := map[string]string{}
+	reqs, _ := s.Requirements()
+	for _, r := range reqs {
+		switch r.Operator() {
+		case selection.Equals, selection.DoubleEquals:
+			res[r.Key()] = r.Values().List()[0]
+		case selection.In:
+			if r.Values().Len() == 1 {
+				res[r.Key()] = r.Values().List()[0]
+			} else if _, ok := res[r.Key()]; !ok {
+				res[r.Key()] = ""
+			}
+		case selection.Exists:
+			if _, ok := res[r.Key()]; !ok {
+				res[r.Key()] = ""
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,37 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	s, err := ParseLabelSelector("env=prod,region!=cn-north,tier in (a,b),zone notin (z1),gpu,!canary")
+	assert.NoError(t, err)
+	assert.True(t, s.Matches(labels.Set{"env": "prod", "region": "cn-south", "tier": "a", "gpu": "true"}))
+	assert.False(t, s.Matches(labels.Set{"env": "prod", "region": "cn-north", "tier": "a", "gpu": "true"}))
+	assert.False(t, s.Matches(labels.Set{"env": "prod", "tier": "c", "gpu": "true"}))
+	assert.False(t, s.Matches(labels.Set{"env": "prod", "tier": "a", "zone": "z1", "gpu": "true"}))
+	assert.False(t, s.Matches(labels.Set{"env": "prod", "tier": "a"}))
+	assert.False(t, s.Matches(labels.Set{"env": "prod", "tier": "a", "gpu": "true", "canary": "1"}))
+
+	s, err = ParseLabelSelector("")
+	assert.NoError(t, err)
+	assert.True(t, s.Empty())
+
+	_, err = ParseLabelSelector("env=prod,region~cn")
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, ErrRequestParamInvalid, e.Code())
+	assert.Contains(t, err.Error(), "~")
+}
+
+func TestSelectorEqualities(t *testing.T) {
+	s, err := ParseLabelSelector("env=prod,tier in (a),zone in (z1,z2),gpu,region!=cn,!canary")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "tier": "a", "zone": "", "gpu": ""}, SelectorEqualities(s))
+}
@@ -0,0 +1,14 @@
+package common
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrShutdown the cause of the contexts of the streaming requests canceled when the server shuts down
+var ErrShutdown = errors.New("the server is shutting down")
+
+// IsShutdown returns whether the request is canceled since the server shuts down
+func (c *Context) IsShutdown() bool {
+	return context.Cause(c.Request.Context()) == ErrShutdown
+}
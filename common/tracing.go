@@ -0,0 +1,23 @@
+package common
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName the name of the tracer of the spans created by baetyl-cloud
+const TracerName = "github.com/baetyl/baetyl-cloud"
+
+// StartSpan starts a child span of the span in the context, which is a no-op if the tracing is not enabled
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, opts...)
+}
+
+// InjectTrace sets the traceparent of the span in the context into the headers of an outgoing request
+func InjectTrace(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
@@ -21,3 +21,23 @@ func TestAES(t *testing.T) {
 	otext, _ := Decrypt(itext, []byte("0123456789abcdef"))
 	assert.Equal(t, "hello world", string(otext))
 }
+
+func TestGCM(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	c1, err := SealGCM([]byte("hello world"), key)
+	assert.NoError(t, err)
+	c2, err := SealGCM([]byte("hello world"), key)
+	assert.NoError(t, err)
+	assert.NotEqual(t, c1, c2)
+
+	p, err := OpenGCM(c1, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(p))
+
+	_, err = OpenGCM(c1, []byte("fedcba9876543210fedcba9876543210"))
+	assert.Error(t, err)
+	_, err = OpenGCM([]byte("short"), key)
+	assert.Error(t, err)
+	_, err = SealGCM([]byte("hello world"), []byte("bad key"))
+	assert.Error(t, err)
+}
@@ -52,6 +52,45 @@ func AddSystemLabel(labels map[string]string, infos map[string]string) map[strin
 	return labels
 }
 
+// GetNodeTags returns the tags kept in the annotations of a node
+func GetNodeTags(annotations map[string]string) map[string]string {
+	tags := map[string]string{}
+	for k, v := range annotations {
+		if strings.HasPrefix(k, AnnotationNodeTagPrefix) {
+			tags[strings.TrimPrefix(k, AnnotationNodeTagPrefix)] = v
+		}
+	}
+	return tags
+}
+
+// SetNodeTags replaces the tags kept in the annotations of a node, the other annotations are kept
+func SetNodeTags(annotations, tags map[string]string) map[string]string {
+	if annotations == nil && len(tags) == 0 {
+		return nil
+	}
+	res := map[string]string{}
+	for k, v := range annotations {
+		if !strings.HasPrefix(k, AnnotationNodeTagPrefix) {
+			res[k] = v
+		}
+	}
+	for k, v := range tags {
+		res[AnnotationNodeTagPrefix+k] = v
+	}
+	return res
+}
+
+// MatchNodeTags returns whether the key or value of any tag of a node contains the text case-insensitively
+func MatchNodeTags(annotations map[string]string, text string) bool {
+	text = strings.ToLower(text)
+	for k, v := range GetNodeTags(annotations) {
+		if strings.Contains(strings.ToLower(k), text) || strings.Contains(strings.ToLower(v), text) {
+			return true
+		}
+	}
+	return false
+}
+
 func UpdateSysAppByAccelerator(accelerator string, sysApps []string) []string {
 	found := false
 	index := 0
@@ -51,6 +51,21 @@ func TestAddSystemLabel(t *testing.T) {
 	}
 }
 
+func TestNodeTags(t *testing.T) {
+	assert.Nil(t, SetNodeTags(nil, nil))
+	assert.Empty(t, GetNodeTags(nil))
+
+	annotations := SetNodeTags(map[string]string{"a": "b", AnnotationNodeTagPrefix + "old": "x"}, map[string]string{"Owner": "Alice"})
+	assert.Equal(t, map[string]string{"a": "b", AnnotationNodeTagPrefix + "Owner": "Alice"}, annotations)
+	assert.Equal(t, map[string]string{"Owner": "Alice"}, GetNodeTags(annotations))
+
+	assert.True(t, MatchNodeTags(annotations, "owner"))
+	assert.True(t, MatchNodeTags(annotations, "LIC"))
+	assert.False(t, MatchNodeTags(annotations, "b"))
+
+	assert.Equal(t, map[string]string{"a": "b"}, SetNodeTags(annotations, nil))
+}
+
 func TestUpdateSysAppByAccelerator(t *testing.T) {
 	sysApps := []string{
 		specV1.BaetylGPUMetrics,
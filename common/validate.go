@@ -55,3 +55,48 @@ func ValidateKeyValue(k string) error {
 	}
 	return nil
 }
+
+// FieldError the failure of validating a field of the request
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists all the failures of validating the request, which are responded in the field errors
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Code() string {
+	return ErrRequestParamInvalid
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		if fe.Field == "" {
+			msgs = append(msgs, fe.Message)
+		} else {
+			msgs = append(msgs, fe.Field+": "+fe.Message)
+		}
+	}
+	return Error(ErrRequestParamInvalid, Field("error", strings.Join(msgs, "; "))).Error()
+}
+
+// ToFieldErrors converts the failures of the validator, the fields are named by their path in the struct validated
+func ToFieldErrors(errs validator.ValidationErrors) []FieldError {
+	res := make([]FieldError, 0, len(errs))
+	for _, v := range errs {
+		field := v.Namespace()
+		if i := strings.Index(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+		msg := v.Error()
+		if _, ok := templates[Code(v.Tag())]; ok {
+			msg = Error(Code(v.Tag()), Field(v.Tag(), v.Field()), Field("error", v.Error())).Error()
+		}
+		res = append(res, FieldError{Field: field, Code: v.Tag(), Message: msg})
+	}
+	return res
+}
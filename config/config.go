@@ -10,18 +10,116 @@ import (
 // CloudConfig baetyl-cloud config
 type CloudConfig struct {
 	InitServer  Server      `yaml:"initServer" json:"initServer" default:"{\"port\":\":9003\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000}"`
-	AdminServer AdminServer `yaml:"adminServer" json:"adminServer" default:"{\"port\":\":9004\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000,\"cacheEnable\":false,\"cacheDuration\":2000000000}"`
+	AdminServer AdminServer `yaml:"adminServer" json:"adminServer" default:"{\"port\":\":9004\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000,\"cacheEnable\":false,\"cacheDuration\":30000000000}"`
 	MisServer   MisServer   `yaml:"misServer" json:"misServer" default:"{\"port\":\":9006\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000,\"authToken\":\"baetyl-cloud-token\",\"tokenHeader\":\"baetyl-cloud-token\",\"userHeader\":\"baetyl-cloud-user\"}"`
 	LogInfo     log.Config  `yaml:"logger" json:"logger"`
 	Task        Task        `yaml:"task" json:"task"`
 	Lock        Lock        `yaml:"lock" json:"lock"`
 	CronJobs    []CronJob   `yaml:"cronJobs" json:"cronJobs" default:"[]"`
-	Cache       struct {
+	NodeOffline NodeOffline `yaml:"nodeOffline" json:"nodeOffline"`
+	Webhook     Webhook     `yaml:"webhook" json:"webhook"`
+	NodeCommand NodeCommand `yaml:"nodeCommand" json:"nodeCommand"`
+	AppSchedule AppSchedule `yaml:"appSchedule" json:"appSchedule"`
+	AlertRules  AlertRules  `yaml:"alertRules" json:"alertRules"`
+	// PasswordPolicy the policy of the passwords of the registries and the credential-like values of the secrets
+	PasswordPolicy PasswordPolicy `yaml:"passwordPolicy" json:"passwordPolicy"`
+	// ObjectUpload the handling of the objects rejected or quarantined by the upload hook
+	ObjectUpload ObjectUpload `yaml:"objectUpload" json:"objectUpload"`
+	// Tracing exports the spans of the requests of the admin server by OTLP over http
+	Tracing Tracing `yaml:"tracing" json:"tracing"`
+	// RegistryRefresh the retries of the transient failures when refreshing the password of a registry
+	RegistryRefresh Retry `yaml:"registryRefresh" json:"registryRefresh"`
+	Registry        struct {
+		// AllowPrivateNetwork whether the registries on the private networks are verified and inspected, the loopback,
+		// link-local and metadata service addresses are never allowed, neither of the registries nor of their token realms
+		AllowPrivateNetwork bool `yaml:"allowPrivateNetwork" json:"allowPrivateNetwork" default:"true"`
+	} `yaml:"registry" json:"registry"`
+	// Features the global defaults of the feature flags, which can be overridden by the settings of each namespace
+	Features map[string]bool `yaml:"features" json:"features" default:"{\"functions\":true,\"objectsV2\":true,\"canary\":true}"`
+	Cache    struct {
 		ExpirationDuration time.Duration `yaml:"expirationDuration" json:"expirationDuration" default:"10m"`
 	} `yaml:"cache" json:"cache"`
+	Certificate struct {
+		RotationOverlap time.Duration `yaml:"rotationOverlap" json:"rotationOverlap" default:"72h"`
+		// ReapInterval how often the certificates replaced by rotation are deleted from pki once their overlap ends
+		ReapInterval time.Duration `yaml:"reapInterval" json:"reapInterval" default:"1h"`
+		// CSR the policy of signing the certificate signing requests submitted by the clients
+		CSR CSRPolicy `yaml:"csr" json:"csr"`
+	} `yaml:"certificate" json:"certificate"`
+	Function struct {
+		// InvokeTimeout the timeout of invoking a function to test it
+		InvokeTimeout time.Duration `yaml:"invokeTimeout" json:"invokeTimeout" default:"30s"`
+		// InvokeMaxSize the max size of the payload in bytes to invoke a function
+		InvokeMaxSize int `yaml:"invokeMaxSize" json:"invokeMaxSize" default:"1048576"`
+		// UploadMaxSize the max size of the code archive in bytes to upload a function, the body limit of the upload route
+		// follows it unless the route is configured in AdminServer.BodyLimit.Routes
+		UploadMaxSize int `yaml:"uploadMaxSize" json:"uploadMaxSize" default:"10485760"`
+	} `yaml:"function" json:"function"`
+	NodeWatch struct {
+		MaxWatchers int           `yaml:"maxWatchers" json:"maxWatchers" default:"10"`
+		Interval    time.Duration `yaml:"interval" json:"interval" default:"3s"`
+		Heartbeat   time.Duration `yaml:"heartbeat" json:"heartbeat" default:"30s"`
+	} `yaml:"nodeWatch" json:"nodeWatch"`
+	// NodeLog the log streams of the apps on the nodes, which are enabled only if the plugin nodeLog is set
+	NodeLog struct {
+		// MaxStreams the max number of the concurrent log streams per namespace, 0 means no limit
+		MaxStreams int `yaml:"maxStreams" json:"maxStreams" default:"10"`
+		// MaxTail the max number of the latest lines a stream may ask for
+		MaxTail int `yaml:"maxTail" json:"maxTail" default:"10000"`
+	} `yaml:"nodeLog" json:"nodeLog"`
 	Template struct {
 		Path string `yaml:"path" json:"path" default:"/etc/baetyl/templates"`
 	} `yaml:"template" json:"template"`
+	AppTrash struct {
+		// Retention how long the deleted apps are kept in the trash, the apps are deleted at once if 0
+		Retention time.Duration `yaml:"retention" json:"retention" default:"168h"`
+		// ReapInterval how often the apps past retention are purged
+		ReapInterval time.Duration `yaml:"reapInterval" json:"reapInterval" default:"1h"`
+	} `yaml:"appTrash" json:"appTrash"`
+	NodeMaintenance struct {
+		// ExcludeQuota the nodes under maintenance are not counted in the node quota of their namespaces if true.
+		// The quota of a node is released when it is put under maintenance, and acquired again when it is taken
+		// out, which is rejected if the namespace has run out of the node quota meanwhile, so the node stays under
+		// maintenance until the quota is raised or other nodes are deleted. The nodes are always counted in the
+		// total of the license
+		ExcludeQuota bool `yaml:"excludeQuota" json:"excludeQuota" default:"false"`
+	} `yaml:"nodeMaintenance" json:"nodeMaintenance"`
+	ResourceVersion struct {
+		// Retention the max number of the versions kept for each config or secret, no versions are kept if 0
+		Retention int `yaml:"retention" json:"retention" default:"10"`
+	} `yaml:"resourceVersion" json:"resourceVersion"`
+	License struct {
+		// Mode how to handle the requests exceeding the license limits, one of enforce, warn and report
+		Mode string `yaml:"mode" json:"mode" default:"enforce"`
+	} `yaml:"license" json:"license"`
+	AppCapacity struct {
+		// Check how to handle the apps requesting more cpu or memory than the capacity of their target nodes, one of off, warn and error
+		Check string `yaml:"check" json:"check" default:"warn"`
+	} `yaml:"appCapacity" json:"appCapacity"`
+	AppSelector struct {
+		// ConfirmThreshold the apps whose selector matches more nodes than it are deployed only with ?confirm=true, 0 means no limit
+		ConfirmThreshold int `yaml:"confirmThreshold" json:"confirmThreshold" default:"100"`
+	} `yaml:"appSelector" json:"appSelector"`
+	AppPortConflict struct {
+		// Check the apps binding the host ports already bound by the other apps on the same nodes are rejected if true
+		Check bool `yaml:"check" json:"check" default:"true"`
+	} `yaml:"appPortConflict" json:"appPortConflict"`
+	Object struct {
+		// MaxURLExpiration the max expiry the callers may ask for the signed urls of the objects
+		MaxURLExpiration time.Duration `yaml:"maxURLExpiration" json:"maxURLExpiration" default:"168h"`
+	} `yaml:"object" json:"object"`
+	AuthCache struct {
+		// TTL how long the successful auth results are kept by the hash of the token in the cache store of the admin server,
+		// no results are kept if 0, nor the results of the auth plugins depending on more than the token
+		TTL time.Duration `yaml:"ttl" json:"ttl" default:"30s"`
+		// MaxEntries the max number of the results kept in the memory, the earliest expiring ones are evicted once exceeded,
+		// it is not bounded if 0. The results kept in redis are bounded by the eviction policy of redis instead
+		MaxEntries int `yaml:"maxEntries" json:"maxEntries" default:"10000"`
+	} `yaml:"authCache" json:"authCache"`
+	Health struct {
+		// Timeout the timeout of each dependency check
+		Timeout time.Duration `yaml:"timeout" json:"timeout" default:"3s"`
+	} `yaml:"health" json:"health"`
 	Plugin struct {
 		Pubsub     string   `yaml:"pubsub" json:"pubsub" default:"defaultpubsub"`
 		PKI        string   `yaml:"pki" json:"pki" default:"defaultpki"`
@@ -35,6 +133,16 @@ type CloudConfig struct {
 		Record     string   `yaml:"record" json:"record" default:"database"`
 		Callback   string   `yaml:"callback" json:"callback" default:"database"`
 		AppHistory string   `yaml:"appHistory" json:"appHistory" default:"database"`
+		NodeGroup  string   `yaml:"nodeGroup" json:"nodeGroup" default:"database"`
+		AppTpl     string   `yaml:"appTemplate" json:"appTemplate" default:"database"`
+		AuditSink  string   `yaml:"auditSink" json:"auditSink" default:"database"`
+		EventSink  string   `yaml:"eventSink" json:"eventSink" default:"database"`
+		Webhook    string   `yaml:"webhook" json:"webhook" default:"database"`
+		NodeCmd    string   `yaml:"nodeCommand" json:"nodeCommand" default:"database"`
+		AppSched   string   `yaml:"appSchedule" json:"appSchedule" default:"database"`
+		RegRefresh string   `yaml:"registryRefresh" json:"registryRefresh" default:"database"`
+		Job        string   `yaml:"job" json:"job" default:"database"`
+		NodeLog    string   `yaml:"nodeLog" json:"nodeLog"`
 		Objects    []string `yaml:"objects" json:"objects" default:"[]"`
 		Functions  []string `yaml:"functions" json:"functions" default:"[]"`
 		Property   string   `yaml:"property" json:"property" default:"database"`
@@ -43,12 +151,17 @@ type CloudConfig struct {
 		Locker     string   `yaml:"locker" json:"locker" default:"defaultlocker"`
 		Task       string   `yaml:"task" json:"task" default:"defaulttask"`
 		Sign       string   `yaml:"sign" json:"sign" default:"defaultsign"`
+		KMS        string   `yaml:"kms" json:"kms"`
 		DM         string   `yaml:"dm" json:"dm" default:"database"`
 		Tx         string   `yaml:"tx" json:"tx" default:"defaulttx"`
 		Cron       string   `yaml:"cron" json:"cron" default:"database"`
 		Csrf       string   `yaml:"csrf" json:"csrf" default:"defaultcsrf"`
 		JWT        string   `yaml:"jwt" json:"jwt" default:"defaultjwt"`
 		Cache      string   `yaml:"cache" json:"cache" default:"freecache"`
+		// ObjectUploadHook checks the objects uploaded to the internal storage
+		ObjectUploadHook string `yaml:"objectUploadHook" json:"objectUploadHook" default:"defaultobjectuploadhook"`
+		// MetricsStore stores the history of the resource usage of the nodes
+		MetricsStore string `yaml:"metricsStore" json:"metricsStore" default:"defaultmetricsstore"`
 	} `yaml:"plugin" json:"plugin"`
 }
 
@@ -67,7 +180,213 @@ type MisServer struct {
 type AdminServer struct {
 	Server        `yaml:",inline" json:",inline"`
 	CacheEnable   bool          `yaml:"cacheEnable" json:"cacheEnable" default:"false"`
-	CacheDuration time.Duration `yaml:"cacheDuration" json:"cacheDuration" default:"2s"`
+	CacheDuration time.Duration `yaml:"cacheDuration" json:"cacheDuration" default:"30s"`
+	Cache         APICache      `yaml:"cache" json:"cache"`
+	RateLimit     RateLimit     `yaml:"rateLimit" json:"rateLimit"`
+	Idempotency   Idempotency   `yaml:"idempotency" json:"idempotency"`
+	BodyLimit     BodyLimit     `yaml:"bodyLimit" json:"bodyLimit"`
+	RBAC          RBAC          `yaml:"rbac" json:"rbac"`
+	Compression   Compression   `yaml:"compression" json:"compression"`
+	CORS          CORS          `yaml:"cors" json:"cors"`
+	// DisableDeprecatedObjects rejects the requests of the deprecated v1 objects apis with 410, pointing to the v2 ones
+	DisableDeprecatedObjects bool `yaml:"disableDeprecatedObjects" json:"disableDeprecatedObjects" default:"false"`
+	// ReadOnly rejects all requests except GET with 503 until the switch is toggled at runtime by PUT /v1/admin/readonly,
+	// the switch toggled is kept in the cache store and shared by the replicas if the store is of redis
+	ReadOnly bool `yaml:"readOnly" json:"readOnly" default:"false"`
+}
+
+// RateLimit the token bucket limiting the requests of each namespace, redis is required to share the buckets between replicas
+type RateLimit struct {
+	Enable bool `yaml:"enable" json:"enable" default:"false"`
+	// Rate the number of tokens added to the bucket per second
+	Rate float64 `yaml:"rate" json:"rate" default:"20"`
+	// Burst the capacity of the bucket
+	Burst int `yaml:"burst" json:"burst" default:"40"`
+	// ByToken limits the requests of each api token of the namespace separately
+	ByToken bool `yaml:"byToken" json:"byToken" default:"false"`
+	// ByClientIP limits the requests of each client IP of the namespace separately, the client IP is taken
+	// from X-Forwarded-For only if the request is from the trusted proxies
+	ByClientIP bool   `yaml:"byClientIP" json:"byClientIP" default:"false"`
+	Type       string `yaml:"type" json:"type" default:"memory"`
+	Address    string `yaml:"address" json:"address"`
+	Password   string `yaml:"password" json:"password"`
+	DB         int    `yaml:"db" json:"db"`
+}
+
+// RBAC checks the roles of the users against the permissions required by the routes, a permission is
+// written as resource:verb such as nodes:write, and * matches any resource or verb
+type RBAC struct {
+	Enable bool `yaml:"enable" json:"enable" default:"false"`
+	// DefaultRole the role of the users without any role known, they are denied if it is empty
+	DefaultRole string `yaml:"defaultRole" json:"defaultRole" default:"viewer"`
+	// Roles the permissions of the custom roles, or of the built-in roles (viewer, editor and admin) to override
+	Roles map[string][]string `yaml:"roles" json:"roles"`
+}
+
+// Compression compresses the responses with the encodings accepted by the clients
+type Compression struct {
+	Enable bool `yaml:"enable" json:"enable" default:"true"`
+	// MinSize the responses smaller than it in bytes are not compressed
+	MinSize int `yaml:"minSize" json:"minSize" default:"1024"`
+	// Encodings the encodings supported, gzip and zstd, in the order preferred if the client accepts several equally
+	Encodings []string `yaml:"encodings" json:"encodings" default:"[\"gzip\",\"zstd\"]"`
+}
+
+// CORS the cross-origin requests allowed, all are denied if no origin is allowed
+type CORS struct {
+	// AllowOrigins the origins allowed, such as https://console.example.com, or * for any origin without the credentials
+	AllowOrigins  []string `yaml:"allowOrigins" json:"allowOrigins"`
+	AllowMethods  []string `yaml:"allowMethods" json:"allowMethods" default:"[\"GET\",\"POST\",\"PUT\",\"PATCH\",\"DELETE\"]"`
+	AllowHeaders  []string `yaml:"allowHeaders" json:"allowHeaders" default:"[\"Authorization\",\"Content-Type\",\"If-Match\",\"Idempotency-Key\"]"`
+	ExposeHeaders []string `yaml:"exposeHeaders" json:"exposeHeaders"`
+	// AllowCredentials allows the credentials to the origins listed, which can't be combined with *
+	AllowCredentials bool          `yaml:"allowCredentials" json:"allowCredentials" default:"false"`
+	MaxAge           time.Duration `yaml:"maxAge" json:"maxAge" default:"10m"`
+}
+
+// Retry the bounded retries with exponential backoff, the backoff is doubled after each attempt up to the max
+type Retry struct {
+	// Attempts the max attempts including the first one
+	Attempts   int           `yaml:"attempts" json:"attempts" default:"3"`
+	Backoff    time.Duration `yaml:"backoff" json:"backoff" default:"200ms"`
+	MaxBackoff time.Duration `yaml:"maxBackoff" json:"maxBackoff" default:"2s"`
+}
+
+// NodeOffline the detection of the nodes going offline, a node is offline if it hasn't reported for the threshold
+type NodeOffline struct {
+	// Enable emits the events when the nodes go offline or come back, it is supposed to be enabled on a single replica
+	Enable bool `yaml:"enable" json:"enable" default:"false"`
+	// Threshold how long the node is marked offline after its last report
+	Threshold time.Duration `yaml:"threshold" json:"threshold" default:"5m"`
+	// Namespaces the thresholds of the namespaces overriding the default one
+	Namespaces map[string]time.Duration `yaml:"namespaces" json:"namespaces"`
+	// Interval how often the report times of the nodes are checked
+	Interval time.Duration `yaml:"interval" json:"interval" default:"30s"`
+}
+
+// Webhook the delivery of the events to the webhooks, a payload failed after all attempts is kept as a dead letter
+type Webhook struct {
+	// Timeout the timeout of posting a payload
+	Timeout time.Duration `yaml:"timeout" json:"timeout" default:"10s"`
+	// MaxAttempts how many times a payload is posted at most
+	MaxAttempts int `yaml:"maxAttempts" json:"maxAttempts" default:"5"`
+	// Backoff the delay before the first retry, which is doubled on each retry up to MaxBackoff
+	Backoff    time.Duration `yaml:"backoff" json:"backoff" default:"1s"`
+	MaxBackoff time.Duration `yaml:"maxBackoff" json:"maxBackoff" default:"1m"`
+	// Concurrency how many payloads are posted at the same time
+	Concurrency int `yaml:"concurrency" json:"concurrency" default:"16"`
+	// QueueSize how many payloads are delivered at most, including the ones waiting for retries,
+	// the payloads beyond are kept as dead letters at once
+	QueueSize int `yaml:"queueSize" json:"queueSize" default:"1024"`
+	// AllowPrivateNetwork whether the webhooks on the private networks are allowed, the loopback, link-local
+	// and metadata service addresses are never allowed
+	AllowPrivateNetwork bool `yaml:"allowPrivateNetwork" json:"allowPrivateNetwork" default:"true"`
+	// CertExpiringWithin the certificates expiring within the duration are notified on each check
+	CertExpiringWithin time.Duration `yaml:"certExpiringWithin" json:"certExpiringWithin" default:"720h"`
+	// CertCheckInterval how often the expiring certificates are checked, it is disabled if 0,
+	// and is supposed to be enabled on a single replica
+	CertCheckInterval time.Duration `yaml:"certCheckInterval" json:"certCheckInterval"`
+}
+
+// AlertRules the thresholds of the prometheus alerting rules generated for the namespaces, the offline threshold
+// of the nodes is taken from NodeOffline. The rules are built on the metrics named here, which carry the label namespace
+type AlertRules struct {
+	// CertExpiringWithin the certificates expiring within the duration are alerted
+	CertExpiringWithin time.Duration `yaml:"certExpiringWithin" json:"certExpiringWithin" default:"720h"`
+	// QuotaPercent the quotas used up to the percent of their limits are alerted
+	QuotaPercent int `yaml:"quotaPercent" json:"quotaPercent" default:"90"`
+	// For how long the conditions hold before the alerts fire
+	For time.Duration `yaml:"for" json:"for" default:"5m"`
+	// CertExpiryMetric the expiry timestamp in seconds of each certificate, labeled by name
+	CertExpiryMetric string `yaml:"certExpiryMetric" json:"certExpiryMetric" default:"baetyl_cloud_certificate_expiry_timestamp_seconds"`
+	// QuotaUsedMetric the usage of each quota, labeled by quota
+	QuotaUsedMetric string `yaml:"quotaUsedMetric" json:"quotaUsedMetric" default:"baetyl_cloud_quota_used"`
+	// NodeReportMetric the timestamp in seconds of the last report of each node, labeled by name
+	NodeReportMetric string `yaml:"nodeReportMetric" json:"nodeReportMetric" default:"baetyl_cloud_node_report_timestamp_seconds"`
+}
+
+// PasswordPolicy the passwords violating the policy are rejected when they are saved, the ones saved before are kept
+type PasswordPolicy struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// MinLength the min number of the characters of a password
+	MinLength int `yaml:"minLength" json:"minLength" default:"8"`
+	// MinClasses the min number of the classes of the characters, which are the upper and lower case letters,
+	// the digits and the others
+	MinClasses int `yaml:"minClasses" json:"minClasses" default:"3"`
+	// Forbidden the values forbidden regardless of the case, such as the common passwords
+	Forbidden []string `yaml:"forbidden" json:"forbidden" default:"[\"password\",\"12345678\",\"123456789\",\"qwerty123\",\"admin123\",\"baetyl123\"]"`
+	// SecretKeys the keys of the data of the secrets which are taken as passwords, matched as substrings regardless of the case
+	SecretKeys []string `yaml:"secretKeys" json:"secretKeys" default:"[\"password\",\"passwd\",\"pwd\",\"token\",\"secret\"]"`
+}
+
+// Tracing the spans are sampled by the traceparent of the request if present, otherwise by SampleRatio
+type Tracing struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// Endpoint the host and port of the OTLP/HTTP receiver, the spans are posted to /v1/traces
+	Endpoint string `yaml:"endpoint" json:"endpoint" default:"localhost:4318"`
+	// Insecure sends the spans by http instead of https
+	Insecure    bool              `yaml:"insecure" json:"insecure"`
+	Headers     map[string]string `yaml:"headers" json:"headers"`
+	ServiceName string            `yaml:"serviceName" json:"serviceName" default:"baetyl-cloud"`
+	SampleRatio float64           `yaml:"sampleRatio" json:"sampleRatio" default:"1"`
+}
+
+// ObjectUpload the objects quarantined are moved to the same bucket with the prefix
+type ObjectUpload struct {
+	QuarantinePrefix string `yaml:"quarantinePrefix" json:"quarantinePrefix" default:"quarantine/"`
+}
+
+// NodeCommand the commands queued for the nodes, which are picked up on their next sync
+type NodeCommand struct {
+	// Limit how many commands can be dispatched to a node within the window
+	Limit  int           `yaml:"limit" json:"limit" default:"5"`
+	Window time.Duration `yaml:"window" json:"window" default:"1m"`
+	// Expiration the pending commands are not picked up once expired
+	Expiration time.Duration `yaml:"expiration" json:"expiration" default:"1h"`
+}
+
+// AppSchedule the scheduled deploys of the applications, which are applied by the replica claiming them when they are due
+type AppSchedule struct {
+	// Interval how often the due deploys are checked, it is disabled if 0
+	Interval time.Duration `yaml:"interval" json:"interval" default:"30s"`
+}
+
+// Idempotency the responses of the creating requests with the Idempotency-Key header are kept in the api cache store,
+// and replayed to the retries with the same key within the ttl
+type Idempotency struct {
+	Enable bool          `yaml:"enable" json:"enable" default:"true"`
+	TTL    time.Duration `yaml:"ttl" json:"ttl" default:"24h"`
+}
+
+// BodyLimit the max size in bytes of the request bodies, the requests exceeding it are rejected with 413
+type BodyLimit struct {
+	// Default the limit of the routes not configured, 0 means no limit
+	Default int64 `yaml:"default" json:"default" default:"10485760"`
+	// Routes the limits by the prefix of the route, such as /v1/yaml, the longest matched prefix is applied
+	Routes map[string]int64 `yaml:"routes" json:"routes" default:"{\"/v1/yaml\":2097152,\"/v1/configs\":2097152}"`
+}
+
+// CSRPolicy the limits of the certificate signing requests, the subject is checked against the namespace as well
+type CSRPolicy struct {
+	// MaxValidity the max validity of the signed certificates, which is given to the requests not asking for one
+	MaxValidity time.Duration `yaml:"maxValidity" json:"maxValidity" default:"8760h"`
+	// KeyAlgorithms the algorithms of the public keys accepted, among RSA, ECDSA and Ed25519
+	KeyAlgorithms []string `yaml:"keyAlgorithms" json:"keyAlgorithms" default:"[\"RSA\",\"ECDSA\"]"`
+	MinRSABits    int      `yaml:"minRSABits" json:"minRSABits" default:"2048"`
+	MinECDSABits  int      `yaml:"minECDSABits" json:"minECDSABits" default:"256"`
+	// AllowSANs whether the requests may ask for the subject alternative names, such as the dns names and the ips
+	AllowSANs bool `yaml:"allowSANs" json:"allowSANs" default:"false"`
+}
+
+// APICache the store of cached api responses, redis is required to share the cache between replicas
+type APICache struct {
+	Type     string `yaml:"type" json:"type" default:"memory"`
+	Address  string `yaml:"address" json:"address"`
+	Password string `yaml:"password" json:"password"`
+	DB       int    `yaml:"db" json:"db"`
+	// Routes the ttl of the cached responses by route pattern, such as /v1/modules or /v1/nodes/*/stats,
+	// the routes not matched use the cache duration of the admin server, bounded by 3s for the routes serving the node reports
+	Routes map[string]time.Duration `yaml:"routes" json:"routes"`
 }
 
 // Server server config
@@ -77,6 +396,9 @@ type Server struct {
 	WriteTimeout time.Duration     `yaml:"writeTimeout" json:"writeTimeout" default:"30s"`
 	ShutdownTime time.Duration     `yaml:"shutdownTime" json:"shutdownTime" default:"3s"`
 	Certificate  utils.Certificate `yaml:",inline" json:",inline"`
+	// TrustedProxies the IPs or CIDRs of the proxies, such as the load balancer, whose X-Forwarded-For and X-Real-IP
+	// are trusted to get the client IP, no proxy is trusted by default and the client IP is the remote address
+	TrustedProxies []string `yaml:"trustedProxies" json:"trustedProxies"`
 }
 
 type Task struct {
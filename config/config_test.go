@@ -20,7 +20,26 @@ func TestDefaultValue(t *testing.T) {
 	expect.AdminServer.ReadTimeout = time.Second * 30
 	expect.AdminServer.ShutdownTime = time.Second * 3
 	expect.AdminServer.CacheEnable = false
-	expect.AdminServer.CacheDuration = time.Second * 2
+	expect.AdminServer.CacheDuration = time.Second * 30
+	expect.AdminServer.Cache.Type = "memory"
+	expect.AdminServer.RateLimit.Rate = 20
+	expect.AdminServer.RateLimit.Burst = 40
+	expect.AdminServer.RateLimit.Type = "memory"
+	expect.AdminServer.Idempotency.Enable = true
+	expect.AdminServer.Idempotency.TTL = time.Hour * 24
+	expect.AdminServer.BodyLimit.Default = 10 << 20
+	expect.AdminServer.BodyLimit.Routes = map[string]int64{"/v1/yaml": 2 << 20, "/v1/configs": 2 << 20}
+	expect.AdminServer.RBAC.DefaultRole = "viewer"
+	expect.AdminServer.Compression = Compression{Enable: true, MinSize: 1024, Encodings: []string{"gzip", "zstd"}}
+	expect.AdminServer.CORS = CORS{
+		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowHeaders: []string{"Authorization", "Content-Type", "If-Match", "Idempotency-Key"},
+		MaxAge:       10 * time.Minute,
+	}
+
+	expect.RegistryRefresh = Retry{Attempts: 3, Backoff: 200 * time.Millisecond, MaxBackoff: 2 * time.Second}
+	expect.Registry.AllowPrivateNetwork = true
+	expect.Features = map[string]bool{"functions": true, "objectsV2": true, "canary": true}
 
 	expect.MisServer.Port = ":9006"
 	expect.MisServer.WriteTimeout = time.Second * 30
@@ -46,6 +65,15 @@ func TestDefaultValue(t *testing.T) {
 	expect.Plugin.Record = "database"
 	expect.Plugin.Callback = "database"
 	expect.Plugin.AppHistory = "database"
+	expect.Plugin.NodeGroup = "database"
+	expect.Plugin.AppTpl = "database"
+	expect.Plugin.AuditSink = "database"
+	expect.Plugin.EventSink = "database"
+	expect.Plugin.Webhook = "database"
+	expect.Plugin.NodeCmd = "database"
+	expect.Plugin.AppSched = "database"
+	expect.Plugin.RegRefresh = "database"
+	expect.Plugin.Job = "database"
 	expect.Plugin.Functions = []string{}
 	expect.Plugin.Objects = []string{}
 	expect.Plugin.Property = "database"
@@ -63,11 +91,73 @@ func TestDefaultValue(t *testing.T) {
 	expect.Plugin.JWT = "defaultjwt"
 	expect.Plugin.Quota = "defaultquota"
 	expect.Plugin.Cache = "freecache"
+	expect.Plugin.ObjectUploadHook = "defaultobjectuploadhook"
+	expect.Plugin.MetricsStore = "defaultmetricsstore"
 
 	expect.Template.Path = "/etc/baetyl/templates"
 
 	expect.Cache.ExpirationDuration = time.Minute * 10
 
+	expect.Certificate.RotationOverlap = time.Hour * 72
+	expect.Certificate.ReapInterval = time.Hour
+	expect.Certificate.CSR = CSRPolicy{
+		MaxValidity:   time.Hour * 8760,
+		KeyAlgorithms: []string{"RSA", "ECDSA"},
+		MinRSABits:    2048,
+		MinECDSABits:  256,
+	}
+	expect.Function.InvokeTimeout = time.Second * 30
+	expect.Function.InvokeMaxSize = 1048576
+	expect.Function.UploadMaxSize = 10485760
+	expect.NodeWatch.MaxWatchers = 10
+	expect.NodeWatch.Interval = time.Second * 3
+	expect.NodeWatch.Heartbeat = time.Second * 30
+	expect.NodeLog.MaxStreams = 10
+	expect.NodeLog.MaxTail = 10000
+	expect.Health.Timeout = time.Second * 3
+	expect.AppTrash.Retention = time.Hour * 168
+	expect.AppTrash.ReapInterval = time.Hour
+	expect.ResourceVersion.Retention = 10
+	expect.License.Mode = "enforce"
+	expect.AppCapacity.Check = "warn"
+	expect.AppSelector.ConfirmThreshold = 100
+	expect.AlertRules.CertExpiringWithin = time.Hour * 720
+	expect.PasswordPolicy.MinLength = 8
+	expect.PasswordPolicy.MinClasses = 3
+	expect.PasswordPolicy.Forbidden = []string{"password", "12345678", "123456789", "qwerty123", "admin123", "baetyl123"}
+	expect.PasswordPolicy.SecretKeys = []string{"password", "passwd", "pwd", "token", "secret"}
+	expect.ObjectUpload.QuarantinePrefix = "quarantine/"
+	expect.Tracing.Endpoint = "localhost:4318"
+	expect.Tracing.ServiceName = "baetyl-cloud"
+	expect.Tracing.SampleRatio = 1
+	expect.AlertRules.QuotaPercent = 90
+	expect.AlertRules.For = time.Minute * 5
+	expect.AlertRules.CertExpiryMetric = "baetyl_cloud_certificate_expiry_timestamp_seconds"
+	expect.AlertRules.QuotaUsedMetric = "baetyl_cloud_quota_used"
+	expect.AlertRules.NodeReportMetric = "baetyl_cloud_node_report_timestamp_seconds"
+	expect.AppPortConflict.Check = true
+	expect.Object.MaxURLExpiration = time.Hour * 168
+	expect.AuthCache.TTL = time.Second * 30
+	expect.AuthCache.MaxEntries = 10000
+	expect.NodeOffline.Threshold = time.Minute * 5
+	expect.NodeOffline.Interval = time.Second * 30
+	expect.NodeCommand = NodeCommand{
+		Limit:      5,
+		Window:     time.Minute,
+		Expiration: time.Hour,
+	}
+	expect.AppSchedule.Interval = time.Second * 30
+	expect.Webhook = Webhook{
+		Timeout:             time.Second * 10,
+		MaxAttempts:         5,
+		Backoff:             time.Second,
+		MaxBackoff:          time.Minute,
+		Concurrency:         16,
+		QueueSize:           1024,
+		AllowPrivateNetwork: true,
+		CertExpiringWithin:  time.Hour * 720,
+	}
+
 	expect.CronJobs = []CronJob{}
 	expect.Task.ScheduleTime = 30
 	expect.Task.ConcurrentNum = 10
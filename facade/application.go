@@ -2,6 +2,7 @@ package facade
 
 import (
 	"strings"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
@@ -32,6 +33,44 @@ func (a *facade) GetApp(ns, name, version string) (*specV1.Application, error) {
 }
 
 func (a *facade) CreateApp(ns string, baseApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+	if err := a.checkAppTrash(ns, app.Name); err != nil {
+		return nil, err
+	}
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return nil, errTx
+	}
+	var err error
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+	app, err = a.createApp(tx, ns, baseApp, app, configs)
+	return app, err
+}
+
+// checkAppTrash rejects the name held by an app in the trash, since the history and the generated configs
+// of the trashed app are purged by name
+func (a *facade) checkAppTrash(ns, name string) error {
+	_, err := a.app.GetTrash(ns, name)
+	if err == nil {
+		return common.Error(common.ErrResourceHasBeenUsed, common.Field("type", common.APP), common.Field("name", name),
+			common.Field("error", "the name is held by the app in the trash, restore or purge it first"))
+	}
+	if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+		return nil
+	}
+	return err
+}
+
+// RestoreApp recreates the application deleted into the trash
+func (a *facade) RestoreApp(ns string, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
 	tx, errTx := a.txFactory.BeginTx()
 	if errTx != nil {
 		return nil, errTx
@@ -47,7 +86,17 @@ func (a *facade) CreateApp(ns string, baseApp, app *specV1.Application, configs
 			a.txFactory.Commit(tx)
 		}
 	}()
-	err = a.updateGenConfigsOfFunctionApp(tx, ns, configs)
+	if app, err = a.createApp(tx, ns, nil, app, configs); err != nil {
+		return nil, err
+	}
+	if err = a.app.DeleteTrash(tx, ns, app.Name); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+func (a *facade) createApp(tx interface{}, ns string, baseApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+	err := a.updateGenConfigsOfFunctionApp(tx, ns, configs)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +127,9 @@ func (a *facade) CreateApp(ns string, baseApp, app *specV1.Application, configs
 }
 
 func (a *facade) UpdateApp(ns string, oldApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+	if err := a.CheckAppCanary(ns, oldApp); err != nil {
+		return nil, err
+	}
 	var err error
 	tx, errTx := a.txFactory.BeginTx()
 	if errTx != nil {
@@ -93,8 +145,12 @@ func (a *facade) UpdateApp(ns string, oldApp, app *specV1.Application, configs [
 			a.txFactory.Commit(tx)
 		}
 	}()
+	app, err = a.updateApp(tx, ns, oldApp, app, configs)
+	return app, err
+}
 
-	err = a.updateGenConfigsOfFunctionApp(tx, ns, configs)
+func (a *facade) updateApp(tx interface{}, ns string, oldApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+	err := a.updateGenConfigsOfFunctionApp(tx, ns, configs)
 	if err != nil {
 		return nil, err
 	}
@@ -176,6 +232,75 @@ func (a *facade) DeleteApp(ns, name string, app *specV1.Application) error {
 	return nil
 }
 
+// TrashApp deletes the application into the trash, the generated configs of function services
+// are kept until the application is purged so that it can be restored
+func (a *facade) TrashApp(ns string, app *specV1.Application, expireTime time.Time) error {
+	var err error
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return errTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+
+	if app.CronStatus == specV1.CronWait {
+		// the selector is kept by the cron job until deployed
+		var cronApp *models.Cron
+		if cronApp, err = a.cron.GetCron(app.Name, ns); err == nil {
+			app.Selector = cronApp.Selector
+		}
+		if err = a.cron.DeleteCron(app.Name, ns); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if err = a.app.Trash(tx, ns, app, expireTime); err != nil {
+		return err
+	}
+	err = a.DeleteNodeAndAppIndex(tx, ns, app)
+	return err
+}
+
+// PurgeApp deletes the application from the trash for good
+func (a *facade) PurgeApp(ns string, trash *models.AppTrash) error {
+	var err error
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return errTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+
+	// the app of the same name recreated before the name was held by the trash keeps its history and configs
+	if _, err = a.app.Get(ns, trash.Name, ""); err == nil {
+		err = a.app.DeleteTrash(tx, ns, trash.Name)
+		return err
+	} else if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+		return err
+	}
+	if err = a.app.PurgeTrash(tx, ns, trash.Name); err != nil {
+		return err
+	}
+	a.cleanGenConfigsOfFunctionApp(tx, nil, trash.Application)
+	return nil
+}
+
 func (a *facade) DeleteNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application) error {
 	_, err := a.node.DeleteNodeAppVersion(tx, namespace, app)
 	if err != nil {
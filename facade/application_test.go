@@ -4,10 +4,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/baetyl/baetyl-go/v2/errors"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
@@ -32,8 +34,15 @@ func TestCreateApplication(t *testing.T) {
 	configs := []specV1.Configuration{*config}
 	ns := "baetyl-cloud"
 
-	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, unknownErr)
+	// the name is held by the app in the trash
+	mAppFacade.sApp.EXPECT().GetTrash(ns, app.Name).Return(&models.AppTrash{Name: app.Name}, nil).Times(1)
 	_, err := appFacade.CreateApp(ns, app, app, configs)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrResourceHasBeenUsed, err.(errors.Coder).Code())
+
+	mAppFacade.sApp.EXPECT().GetTrash(ns, app.Name).Return(nil, common.Error(common.ErrResourceNotFound)).AnyTimes()
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, unknownErr)
+	_, err = appFacade.CreateApp(ns, app, app, configs)
 	assert.Error(t, err, unknownErr)
 
 	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, nil).AnyTimes()
@@ -147,6 +156,88 @@ func TestDeleteApplication(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestAppTrash(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{
+		Namespace:  ns,
+		Name:       "abc",
+		CronStatus: specV1.CronWait,
+		Volumes: []specV1.Volume{{
+			Name:         "code",
+			VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-function-config-abc"}},
+		}},
+	}
+	expire := time.Now().Add(time.Hour)
+	mAppFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// the selector of the cron job is kept in the trash, the generated configs are kept
+	mAppFacade.sCron.EXPECT().GetCron(app.Name, ns).Return(&models.Cron{Selector: "a=b"}, nil).Times(1)
+	mAppFacade.sCron.EXPECT().DeleteCron(app.Name, ns).Return(nil).Times(1)
+	mAppFacade.sApp.EXPECT().Trash(nil, ns, app, expire).Return(unknownErr).Times(1)
+	err := appFacade.TrashApp(ns, app, expire)
+	assert.Error(t, err, unknownErr)
+	assert.Equal(t, "a=b", app.Selector)
+
+	app.CronStatus = specV1.CronNotSet
+	mAppFacade.sApp.EXPECT().Trash(nil, ns, app, expire).Return(nil).Times(1)
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, app.Name, []string{}).Return(nil).Times(1)
+	err = appFacade.TrashApp(ns, app, expire)
+	assert.NoError(t, err)
+
+	// restore
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(app, nil).Times(1)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return([]string{"n1"}, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, app.Name, []string{"n1"}).Return(nil).Times(1)
+	mAppFacade.sApp.EXPECT().DeleteTrash(nil, ns, app.Name).Return(nil).Times(1)
+	res, err := appFacade.RestoreApp(ns, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, app, res)
+
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(nil, unknownErr).Times(1)
+	_, err = appFacade.RestoreApp(ns, app, nil)
+	assert.Error(t, err)
+
+	// purge
+	trash := &models.AppTrash{Name: app.Name, Namespace: ns, Application: app}
+	mAppFacade.sApp.EXPECT().Get(ns, app.Name, "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(2)
+	mAppFacade.sApp.EXPECT().PurgeTrash(nil, ns, app.Name).Return(unknownErr).Times(1)
+	err = appFacade.PurgeApp(ns, trash)
+	assert.Error(t, err, unknownErr)
+
+	mAppFacade.sApp.EXPECT().PurgeTrash(nil, ns, app.Name).Return(nil).Times(1)
+	mAppFacade.sConfig.EXPECT().Delete(nil, ns, "baetyl-function-config-abc").Return(nil).Times(1)
+	err = appFacade.PurgeApp(ns, trash)
+	assert.NoError(t, err)
+
+	// the app can't be recreated while the name is held by the trash
+	mAppFacade.sApp.EXPECT().GetTrash(ns, app.Name).Return(trash, nil).Times(1)
+	_, err = appFacade.CreateApp(ns, nil, app, nil)
+	assert.Error(t, err)
+
+	// the app recreated before keeps its history and generated configs when the trash is purged
+	mAppFacade.sApp.EXPECT().Get(ns, app.Name, "").Return(app, nil).Times(1)
+	mAppFacade.sApp.EXPECT().DeleteTrash(nil, ns, app.Name).Return(nil).Times(1)
+	err = appFacade.PurgeApp(ns, trash)
+	assert.NoError(t, err)
+
+	mAppFacade.sApp.EXPECT().Get(ns, app.Name, "").Return(nil, unknownErr).Times(1)
+	err = appFacade.PurgeApp(ns, trash)
+	assert.Error(t, err, unknownErr)
+}
+
 func TestUpdateApplication(t *testing.T) {
 	mAppFacade, mCtl := InitMockEnvironment(t)
 	defer mCtl.Finish()
@@ -215,8 +306,14 @@ func TestUpdateApplication(t *testing.T) {
 	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
 	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
 
-	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
+	mAppFacade.sApp.EXPECT().GetCanary(ns, app.Name).Return(&models.AppCanary{Name: app.Name, Version: app.Version}, nil).Times(1)
 	_, err := appFacade.UpdateApp(ns, app, app, configs)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has a canary in progress")
+
+	mAppFacade.sApp.EXPECT().GetCanary(ns, gomock.Any()).Return(nil, common.Error(common.ErrResourceNotFound)).AnyTimes()
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
+	_, err = appFacade.UpdateApp(ns, app, app, configs)
 	assert.Error(t, err, unknownErr)
 
 	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, nil).AnyTimes()
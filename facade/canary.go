@@ -0,0 +1,204 @@
+package facade
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// CheckAppCanary rejects rolling a new version of the application out to all nodes while the canary of its current version
+// is in progress, which would end the canary silently
+func (a *facade) CheckAppCanary(ns string, app *specV1.Application) error {
+	canary, err := a.app.GetCanary(ns, app.Name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil
+		}
+		return err
+	}
+	if canary != nil && canary.Version == app.Version {
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the app (%s) has a canary in progress, promote or abort the canary first", app.Name)))
+	}
+	return nil
+}
+
+// UpdateAppCanary updates the application and rolls the new version out to the percent of its nodes only,
+// the other nodes keep running the stable version until the canary is promoted
+func (a *facade) UpdateAppCanary(ns string, oldApp, app *specV1.Application, percent int) (*specV1.Application, *models.AppCanary, error) {
+	var err error
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return nil, nil, errTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+
+	app, err = a.app.Update(tx, ns, app)
+	if err != nil {
+		return nil, nil, err
+	}
+	targets, err := a.index.ListNodesByApp(ns, app.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes := selectCanaryNodes(app.Name, targets, nil, percent)
+	if err = a.node.UpdateDesire(tx, ns, nodes, app, service.RefreshNodeDesireByApp); err != nil {
+		return nil, nil, err
+	}
+
+	canary, err := a.app.SaveCanary(tx, &models.AppCanary{
+		Name:          app.Name,
+		Namespace:     ns,
+		Version:       app.Version,
+		StableVersion: oldApp.Version,
+		Percent:       percent,
+		Nodes:         nodes,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return app, canary, nil
+}
+
+// PromoteAppCanary advances the canary to the percent of the nodes, the nodes already in the canary are kept.
+// The new version is rolled out to all nodes and the canary is finished if percent is 0 or 100.
+func (a *facade) PromoteAppCanary(ns string, app *specV1.Application, canary *models.AppCanary, percent int) (*models.AppCanary, error) {
+	var err error
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return nil, errTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+
+	if percent == 0 || percent >= 100 {
+		if err = a.UpdateNodeAndAppIndex(tx, ns, app); err != nil {
+			return nil, err
+		}
+		err = a.app.DeleteCanary(tx, ns, app.Name)
+		return nil, err
+	}
+
+	targets, err := a.index.ListNodesByApp(ns, app.Name)
+	if err != nil {
+		return nil, err
+	}
+	nodes := selectCanaryNodes(app.Name, targets, canary.Nodes, percent)
+	in := map[string]bool{}
+	for _, n := range canary.Nodes {
+		in[n] = true
+	}
+	var added []string
+	for _, n := range nodes {
+		if !in[n] {
+			added = append(added, n)
+		}
+	}
+	if err = a.node.UpdateDesire(tx, ns, added, app, service.RefreshNodeDesireByApp); err != nil {
+		return nil, err
+	}
+
+	canary.Percent = percent
+	canary.Nodes = nodes
+	canary, err = a.app.SaveCanary(tx, canary)
+	return canary, err
+}
+
+// AbortAppCanary rolls the application back to the stable version on all nodes, which is saved as a new version
+func (a *facade) AbortAppCanary(ns string, stable *specV1.Application) (*specV1.Application, error) {
+	var err error
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return nil, errTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+
+	app, err := a.app.Update(tx, ns, stable)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.UpdateNodeAndAppIndex(tx, ns, app); err != nil {
+		return nil, err
+	}
+	if err = a.app.DeleteCanary(tx, ns, app.Name); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// selectCanaryNodes returns the chosen nodes still targeted, along with the nodes ranked first by the hash of
+// their names and the app name until the percent of the targets is reached, so that the same nodes are selected
+// whenever the canary is advanced. At least one node is selected if there are any targets.
+func selectCanaryNodes(app string, targets, chosen []string, percent int) []string {
+	count := (len(targets)*percent + 99) / 100
+	targeted := map[string]bool{}
+	for _, n := range targets {
+		targeted[n] = true
+	}
+	selected := map[string]bool{}
+	res := []string{}
+	for _, n := range chosen {
+		if targeted[n] && !selected[n] {
+			selected[n] = true
+			res = append(res, n)
+		}
+	}
+
+	ranked := make([]string, 0, len(targets))
+	ranks := map[string]uint64{}
+	for _, n := range targets {
+		if selected[n] {
+			continue
+		}
+		h := fnv.New64a()
+		h.Write([]byte(app + "/" + n))
+		ranks[n] = h.Sum64()
+		ranked = append(ranked, n)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranks[ranked[i]] != ranks[ranked[j]] {
+			return ranks[ranked[i]] < ranks[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	for _, n := range ranked {
+		if len(res) >= count {
+			break
+		}
+		res = append(res, n)
+	}
+	return res
+}
@@ -0,0 +1,131 @@
+package facade
+
+import (
+	"fmt"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestSelectCanaryNodes(t *testing.T) {
+	var targets []string
+	for i := 0; i < 20; i++ {
+		targets = append(targets, fmt.Sprintf("node-%02d", i))
+	}
+
+	assert.Len(t, selectCanaryNodes("app", targets, nil, 10), 2)
+	assert.Len(t, selectCanaryNodes("app", targets[:3], nil, 10), 1)
+	assert.Len(t, selectCanaryNodes("app", nil, nil, 10), 0)
+
+	// the selection is stable
+	first := selectCanaryNodes("app", targets, nil, 10)
+	assert.Equal(t, first, selectCanaryNodes("app", targets, nil, 10))
+	reversed := make([]string, len(targets))
+	for i, n := range targets {
+		reversed[len(targets)-1-i] = n
+	}
+	assert.Equal(t, first, selectCanaryNodes("app", reversed, nil, 10))
+
+	// the chosen nodes stay in the canary when it is advanced
+	more := selectCanaryNodes("app", targets, first, 50)
+	assert.Len(t, more, 10)
+	assert.Equal(t, first, more[:2])
+
+	// the chosen nodes no longer targeted are dropped
+	res := selectCanaryNodes("app", targets[:1], []string{"gone", targets[0]}, 50)
+	assert.Equal(t, []string{targets[0]}, res)
+}
+
+func TestAppCanary(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	oldApp := &specV1.Application{Namespace: ns, Name: "abc", Version: "1"}
+	app := &specV1.Application{Namespace: ns, Name: "abc", Version: "1"}
+	newApp := &specV1.Application{Namespace: ns, Name: "abc", Version: "2"}
+	targets := []string{"n1", "n2", "n3", "n4"}
+	mAppFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// update
+	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(newApp, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, app.Name).Return(targets, nil).Times(2)
+	chosen := selectCanaryNodes(app.Name, targets, nil, 25)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, chosen, newApp, gomock.Any()).Return(nil).Times(1)
+	mAppFacade.sApp.EXPECT().SaveCanary(nil, gomock.Any()).DoAndReturn(
+		func(_ interface{}, canary *models.AppCanary) (*models.AppCanary, error) {
+			return canary, nil
+		}).Times(2)
+	resApp, canary, err := appFacade.UpdateAppCanary(ns, oldApp, app, 25)
+	assert.NoError(t, err)
+	assert.Equal(t, newApp, resApp)
+	assert.Equal(t, "2", canary.Version)
+	assert.Equal(t, "1", canary.StableVersion)
+	assert.Equal(t, chosen, canary.Nodes)
+
+	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(nil, unknownErr).Times(1)
+	_, _, err = appFacade.UpdateAppCanary(ns, oldApp, app, 25)
+	assert.Error(t, err)
+
+	// promote to more nodes, only the added nodes are updated
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, gomock.Len(1), newApp, gomock.Any()).Return(nil).Times(1)
+	canary, err = appFacade.PromoteAppCanary(ns, newApp, canary, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, 50, canary.Percent)
+	assert.Len(t, canary.Nodes, 2)
+	assert.Equal(t, chosen[0], canary.Nodes[0])
+
+	// promote to all nodes
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, newApp).Return(targets, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, newApp.Name, targets).Return(nil).Times(1)
+	mAppFacade.sApp.EXPECT().DeleteCanary(nil, ns, newApp.Name).Return(nil).Times(1)
+	canary, err = appFacade.PromoteAppCanary(ns, newApp, canary, 100)
+	assert.NoError(t, err)
+	assert.Nil(t, canary)
+
+	// abort
+	rolled := &specV1.Application{Namespace: ns, Name: "abc", Version: "3"}
+	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(rolled, nil).Times(1)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, rolled).Return(targets, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, rolled.Name, targets).Return(nil).Times(1)
+	mAppFacade.sApp.EXPECT().DeleteCanary(nil, ns, rolled.Name).Return(unknownErr).Times(1)
+	_, err = appFacade.AbortAppCanary(ns, app)
+	assert.Error(t, err)
+}
+
+func TestCheckAppCanary(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{app: mAppFacade.sApp}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Namespace: ns, Name: "abc", Version: "2"}
+
+	mAppFacade.sApp.EXPECT().GetCanary(ns, "abc").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	assert.NoError(t, appFacade.CheckAppCanary(ns, app))
+
+	// the canary of an older version is over
+	mAppFacade.sApp.EXPECT().GetCanary(ns, "abc").Return(&models.AppCanary{Name: "abc", Version: "1"}, nil).Times(1)
+	assert.NoError(t, appFacade.CheckAppCanary(ns, app))
+
+	mAppFacade.sApp.EXPECT().GetCanary(ns, "abc").Return(&models.AppCanary{Name: "abc", Version: "2"}, nil).Times(1)
+	err := appFacade.CheckAppCanary(ns, app)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the app (abc) has a canary in progress")
+
+	mAppFacade.sApp.EXPECT().GetCanary(ns, "abc").Return(nil, fmt.Errorf("error")).Times(1)
+	assert.Error(t, appFacade.CheckAppCanary(ns, app))
+}
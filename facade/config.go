@@ -8,6 +8,7 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 func (a *facade) CreateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error) {
@@ -30,23 +31,33 @@ func (a *facade) CreateConfig(ns string, config *specV1.Configuration) (*specV1.
 	return config, err
 }
 
+// UpdateConfig updates the config and the apps referencing it, which is rejected if any of the apps not pinning
+// the config has a canary in progress
 func (a *facade) UpdateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error) {
 	var res *specV1.Configuration
 	var err error
-	res, err = a.config.Update(nil, ns, config)
+	var apps []*specV1.Application
+	apps, err = a.listAppsByConfig(ns, config.Name)
 	if err != nil {
-		log.L().Error("Update config failed", log.Error(err))
+		log.L().Error("list apps by config failed", log.Error(err))
 		return nil, err
 	}
+	for _, app := range apps {
+		if service.IsConfigPinned(app, config.Name) {
+			continue
+		}
+		if err = a.CheckAppCanary(ns, app); err != nil {
+			return nil, err
+		}
+	}
 
-	var appNames []string
-	appNames, err = a.index.ListAppIndexByConfig(ns, res.Name)
+	res, err = a.config.Update(nil, ns, config)
 	if err != nil {
-		log.L().Error("list app index by config failed", log.Error(err))
+		log.L().Error("Update config failed", log.Error(err))
 		return nil, err
 	}
 
-	if err = a.updateNodeAndApp(ns, res, appNames); err != nil {
+	if err = a.updateNodeAndApp(nil, ns, res, apps); err != nil {
 		log.L().Error("update node and app failed", log.Error(err))
 		return nil, err
 	}
@@ -57,25 +68,36 @@ func (a *facade) DeleteConfig(ns, name string) error {
 	return a.config.Delete(nil, ns, name)
 }
 
-func (a *facade) updateNodeAndApp(namespace string, config *specV1.Configuration, appNames []string) error {
+func (a *facade) listAppsByConfig(namespace, name string) ([]*specV1.Application, error) {
+	appNames, err := a.index.ListAppIndexByConfig(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	var apps []*specV1.Application
 	for _, appName := range appNames {
 		app, err := a.app.Get(namespace, appName, "")
 		if err != nil {
 			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
 				continue
 			}
-			return err
+			return nil, err
 		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
 
+func (a *facade) updateNodeAndApp(tx interface{}, namespace string, config *specV1.Configuration, apps []*specV1.Application) error {
+	for _, app := range apps {
 		if !needUpdateApp(config, app) {
 			continue
 		}
 		// Todo remove by list watch
-		app, err = a.app.Update(nil, namespace, app)
+		app, err := a.app.Update(tx, namespace, app)
 		if err != nil {
 			return err
 		}
-		_, err = a.node.UpdateNodeAppVersion(nil, namespace, app)
+		_, err = a.node.UpdateNodeAppVersion(tx, namespace, app)
 		if err != nil {
 			return err
 		}
@@ -88,6 +110,7 @@ func needUpdateApp(config *specV1.Configuration, app *specV1.Application) bool {
 	for _, volume := range app.Volumes {
 		if volume.Config != nil &&
 			volume.Config.Name == config.Name &&
+			!service.IsConfigPinned(app, config.Name) &&
 			// config's version must increment
 			strings.Compare(config.Version, volume.Config.Version) > 0 {
 			volume.Config.Version = config.Version
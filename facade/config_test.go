@@ -57,24 +57,6 @@ func TestUpdateConfig(t *testing.T) {
 			common.ConfigObjectPrefix + "function": `{"metadata":{"bucket":"baetyl","function":"process","handler":"index.handler","object":"a.zip","runtime":"python36","type":"function","userID":"default","version":"1"}}`,
 		},
 	}
-	mConf2 := &models.ConfigurationView{
-		Namespace: ns,
-		Data: []models.ConfigDataItem{
-			{
-				Key: "function",
-				Value: map[string]string{
-					"type":     "function",
-					"function": "process",
-					"version":  "1",
-					"runtime":  "python36",
-					"handler":  "index.handler",
-					"bucket":   "baetyl",
-					"object":   "a.zip",
-				},
-			},
-		},
-		Description: "update",
-	}
 	res3 := &specV1.Configuration{
 		Name:      name,
 		Namespace: ns,
@@ -87,26 +69,26 @@ func TestUpdateConfig(t *testing.T) {
 		Description: "diff",
 	}
 
-	mFacade.sConfig.EXPECT().Update(nil, ns, gomock.Any()).Return(res, unknownErr).Times(1)
+	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, name).Return(nil, unknownErr).Times(1)
 	_, err := cfgFacade.UpdateConfig(ns, res3)
 	assert.Error(t, err, unknownErr)
 
-	mFacade.sConfig.EXPECT().Update(nil, ns, gomock.Any()).Return(res, nil).AnyTimes()
-	mFacade.sIndex.EXPECT().ListAppIndexByConfig(mConf2.Namespace, "abc").Return(nil, unknownErr).Times(1)
+	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, name).Return([]string{}, nil).Times(2)
+	mFacade.sConfig.EXPECT().Update(nil, ns, gomock.Any()).Return(res, unknownErr).Times(1)
 	_, err = cfgFacade.UpdateConfig(ns, res3)
 	assert.Error(t, err, unknownErr)
 
-	appNames := make([]string, 0)
-	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, name).Return(appNames, nil).Times(1)
+	mFacade.sConfig.EXPECT().Update(nil, ns, gomock.Any()).Return(res, nil).AnyTimes()
 	_, err = cfgFacade.UpdateConfig(ns, res3)
 	assert.NoError(t, err)
 
-	appNames = []string{"app01", "app02"}
+	appNames := []string{"app01", "app02"}
 	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, name).Return(appNames, nil).Times(1)
 	mFacade.sApp.EXPECT().Get(ns, "app01", "").Return(nil, errors.New("err")).Times(1)
 	_, err = cfgFacade.UpdateConfig(ns, res3)
 	assert.Error(t, err, unknownErr)
 
+	mFacade.sApp.EXPECT().GetCanary(ns, gomock.Any()).Return(nil, common.Error(common.ErrResourceNotFound)).AnyTimes()
 	apps := []*specV1.Application{
 		{
 			Namespace: "default",
@@ -152,7 +134,7 @@ func TestUpdateConfig(t *testing.T) {
 			},
 		},
 	}
-	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, name).Return(appNames, nil).AnyTimes()
+	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, name).Return(appNames, nil).Times(2)
 	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
 	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
 	_, err = cfgFacade.UpdateConfig(ns, res3)
@@ -166,6 +148,41 @@ func TestUpdateConfig(t *testing.T) {
 	assert.Error(t, err, unknownErr)
 }
 
+// the config referenced by the app with a canary in progress is not updated, unless pinned by the app
+func TestUpdateConfigWithAppCanary(t *testing.T) {
+	mFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	cfgFacade := &facade{
+		node:      mFacade.sNode,
+		app:       mFacade.sApp,
+		config:    mFacade.sConfig,
+		index:     mFacade.sIndex,
+		txFactory: mFacade.txFactory,
+	}
+	ns := "default"
+	cfg := &specV1.Configuration{Namespace: ns, Name: "abc", Version: "2"}
+	app := &specV1.Application{
+		Namespace: ns,
+		Name:      "app01",
+		Version:   "5",
+		Volumes: []specV1.Volume{{
+			Name:         "vol0",
+			VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "abc", Version: "1"}},
+		}},
+	}
+	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, "abc").Return([]string{"app01"}, nil).AnyTimes()
+	mFacade.sApp.EXPECT().Get(ns, "app01", "").Return(app, nil).AnyTimes()
+	mFacade.sApp.EXPECT().GetCanary(ns, "app01").Return(&models.AppCanary{Name: "app01", Version: "5"}, nil).Times(1)
+	_, err := cfgFacade.UpdateConfig(ns, cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has a canary in progress")
+
+	app.Labels = map[string]string{common.LabelPinnedConfigs: "abc"}
+	mFacade.sConfig.EXPECT().Update(nil, ns, cfg).Return(cfg, nil).Times(1)
+	_, err = cfgFacade.UpdateConfig(ns, cfg)
+	assert.NoError(t, err)
+}
+
 func TestDeleteConfig(t *testing.T) {
 	mFacade, mCtl := InitMockEnvironment(t)
 	defer mCtl.Finish()
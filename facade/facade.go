@@ -1,11 +1,14 @@
 package facade
 
 import (
+	"time"
+
 	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/service"
 )
@@ -17,6 +20,13 @@ type Facade interface {
 	CreateApp(ns string, baseApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error)
 	UpdateApp(ns string, oldApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error)
 	DeleteApp(ns, name string, app *specV1.Application) error
+	TrashApp(ns string, app *specV1.Application, expireTime time.Time) error
+	RestoreApp(ns string, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error)
+	PurgeApp(ns string, trash *models.AppTrash) error
+	UpdateAppCanary(ns string, oldApp, app *specV1.Application, percent int) (*specV1.Application, *models.AppCanary, error)
+	PromoteAppCanary(ns string, app *specV1.Application, canary *models.AppCanary, percent int) (*models.AppCanary, error)
+	AbortAppCanary(ns string, stable *specV1.Application) (*specV1.Application, error)
+	CheckAppCanary(ns string, app *specV1.Application) error
 
 	CreateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error)
 	UpdateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error)
@@ -25,6 +35,8 @@ type Facade interface {
 	CreateSecret(ns string, secret *specV1.Secret) (*specV1.Secret, error)
 	UpdateSecret(ns string, secret *specV1.Secret) (*specV1.Secret, error)
 	DeleteSecret(ns, name string) error
+
+	ImportResources(ns string, resources []models.NamespaceImportResource) error
 }
 
 type facade struct {
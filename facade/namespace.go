@@ -0,0 +1,125 @@
+package facade
+
+import (
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// ImportResources creates or overwrites the secrets, configs and apps of an imported bundle in one transaction,
+// along with the apps referencing the overwritten secrets and configs. The resources are written in the given order,
+// the apps of the bundle reference the versions of the secrets and configs written before them.
+// It is rejected if any of the apps to update has a canary in progress
+func (a *facade) ImportResources(ns string, resources []models.NamespaceImportResource) error {
+	// the apps referencing the overwritten secrets and configs, which are not in the bundle
+	bundleApps := map[string]bool{}
+	for _, r := range resources {
+		if r.Application != nil {
+			bundleApps[r.Application.Name] = true
+		}
+	}
+	refs := make([][]*specV1.Application, len(resources))
+	for i, r := range resources {
+		var err error
+		switch {
+		case r.Application != nil:
+			if r.Old == nil {
+				err = a.checkAppTrash(ns, r.Application.Name)
+			} else {
+				err = a.CheckAppCanary(ns, r.Old.(*specV1.Application))
+			}
+		case r.Configuration != nil && r.Old != nil:
+			refs[i], err = a.listAppsByConfig(ns, r.Configuration.Name)
+		case r.Secret != nil && r.Old != nil:
+			refs[i], err = a.listAppsBySecret(ns, r.Secret.Name)
+		}
+		if err != nil {
+			return err
+		}
+		var apps []*specV1.Application
+		for _, app := range refs[i] {
+			if bundleApps[app.Name] || (r.Configuration != nil && service.IsConfigPinned(app, r.Configuration.Name)) {
+				continue
+			}
+			if err = a.CheckAppCanary(ns, app); err != nil {
+				return err
+			}
+			apps = append(apps, app)
+		}
+		refs[i] = apps
+	}
+
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return errTx
+	}
+	var err error
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+	configs := map[string]string{}
+	secrets := map[string]string{}
+	for i, r := range resources {
+		switch {
+		case r.Secret != nil:
+			var secret *specV1.Secret
+			if r.Old == nil {
+				secret, err = a.secret.Create(tx, ns, r.Secret)
+			} else {
+				secret, err = a.secret.Update(tx, ns, r.Secret)
+			}
+			if err != nil {
+				return err
+			}
+			secrets[secret.Name] = secret.Version
+			if err = a.updateAppSecret(tx, ns, secret, refs[i]); err != nil {
+				return err
+			}
+		case r.Configuration != nil:
+			var cfg *specV1.Configuration
+			if r.Old == nil {
+				cfg, err = a.config.Create(tx, ns, r.Configuration)
+			} else {
+				cfg, err = a.config.Update(tx, ns, r.Configuration)
+			}
+			if err != nil {
+				return err
+			}
+			configs[cfg.Name] = cfg.Version
+			if err = a.updateNodeAndApp(tx, ns, cfg, refs[i]); err != nil {
+				return err
+			}
+		case r.Application != nil:
+			app := r.Application
+			for _, v := range app.Volumes {
+				if v.Config != nil && !service.IsConfigPinned(app, v.Config.Name) {
+					if version, ok := configs[v.Config.Name]; ok {
+						v.Config.Version = version
+					}
+				}
+				if v.Secret != nil {
+					if version, ok := secrets[v.Secret.Name]; ok {
+						v.Secret.Version = version
+					}
+				}
+			}
+			if r.Old == nil {
+				_, err = a.createApp(tx, ns, nil, app, r.Configs)
+			} else {
+				_, err = a.updateApp(tx, ns, r.Old.(*specV1.Application), app, r.Configs)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package facade
+
+import (
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestImportResources(t *testing.T) {
+	mFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	sFacade := &facade{
+		node:      mFacade.sNode,
+		app:       mFacade.sApp,
+		config:    mFacade.sConfig,
+		secret:    mFacade.sSecret,
+		index:     mFacade.sIndex,
+		cron:      mFacade.sCron,
+		txFactory: mFacade.txFactory,
+	}
+	ns, tx := "default", "tx"
+	notFound := common.Error(common.ErrResourceNotFound)
+	secret := &specV1.Secret{Name: "s1", Namespace: ns, Version: "1", Data: map[string][]byte{"k": []byte("v")}}
+	cfg := &specV1.Configuration{Name: "c1", Namespace: ns, Data: map[string]string{"a": "b"}}
+	app := &specV1.Application{Name: "a1", Namespace: ns, Volumes: []specV1.Volume{
+		{Name: "v1", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s1"}}},
+		{Name: "v2", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c1"}}},
+	}}
+	// the app out of the bundle referencing the overwritten secret
+	other := &specV1.Application{Name: "a2", Namespace: ns, Version: "3", Volumes: []specV1.Volume{
+		{Name: "v1", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s1", Version: "1"}}},
+	}}
+	resources := []models.NamespaceImportResource{
+		{Secret: secret, Old: &specV1.Secret{Name: "s1"}},
+		{Configuration: cfg},
+		{Application: app},
+	}
+
+	// the canary in progress of the referencing app rejects the import before the transaction
+	mFacade.sIndex.EXPECT().ListAppIndexBySecret(ns, "s1").Return([]string{"a1", "a2"}, nil).Times(1)
+	mFacade.sApp.EXPECT().Get(ns, "a1", "").Return(app, nil).Times(1)
+	mFacade.sApp.EXPECT().Get(ns, "a2", "").Return(other, nil).Times(1)
+	mFacade.sApp.EXPECT().GetCanary(ns, "a2").Return(&models.AppCanary{Name: "a2", Version: "3"}, nil).Times(1)
+	err := sFacade.ImportResources(ns, resources)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "canary in progress")
+
+	// all the resources are written in one transaction, the app references the versions written
+	mFacade.sApp.EXPECT().GetTrash(ns, "a1").Return(nil, notFound).Times(1)
+	mFacade.sIndex.EXPECT().ListAppIndexBySecret(ns, "s1").Return([]string{"a1", "a2"}, nil).Times(1)
+	mFacade.sApp.EXPECT().Get(ns, "a1", "").Return(app, nil).Times(1)
+	mFacade.sApp.EXPECT().Get(ns, "a2", "").Return(other, nil).Times(1)
+	mFacade.sApp.EXPECT().GetCanary(ns, "a2").Return(nil, notFound).Times(1)
+	mFacade.txFactory.EXPECT().BeginTx().Return(tx, nil).Times(1)
+	mFacade.sSecret.EXPECT().Update(tx, ns, secret).Return(&specV1.Secret{Name: "s1", Version: "5"}, nil).Times(1)
+	mFacade.sApp.EXPECT().Update(tx, ns, other).DoAndReturn(func(_ interface{}, _ string, a *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "5", a.Volumes[0].Secret.Version)
+		return a, nil
+	}).Times(1)
+	mFacade.sNode.EXPECT().UpdateNodeAppVersion(tx, ns, other).Return(nil, nil).Times(1)
+	mFacade.sConfig.EXPECT().Create(tx, ns, cfg).Return(&specV1.Configuration{Name: "c1", Version: "6"}, nil).Times(1)
+	mFacade.sApp.EXPECT().CreateWithBase(tx, ns, app, nil).DoAndReturn(func(_ interface{}, _ string, a, _ *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "5", a.Volumes[0].Secret.Version)
+		assert.Equal(t, "6", a.Volumes[1].Config.Version)
+		return a, nil
+	}).Times(1)
+	mFacade.sNode.EXPECT().UpdateNodeAppVersion(tx, ns, app).Return([]string{"n1"}, nil).Times(1)
+	mFacade.sIndex.EXPECT().RefreshNodesIndexByApp(tx, ns, "a1", []string{"n1"}).Return(nil).Times(1)
+	mFacade.txFactory.EXPECT().Commit(tx).Times(1)
+	assert.NoError(t, sFacade.ImportResources(ns, resources))
+
+	// the failure rolls back the transaction
+	mFacade.txFactory.EXPECT().BeginTx().Return(tx, nil).Times(1)
+	mFacade.sConfig.EXPECT().Create(tx, ns, cfg).Return(nil, unknownErr).Times(1)
+	mFacade.txFactory.EXPECT().Rollback(tx).Times(1)
+	assert.Equal(t, unknownErr, sFacade.ImportResources(ns, []models.NamespaceImportResource{{Configuration: cfg}}))
+}
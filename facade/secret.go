@@ -29,12 +29,22 @@ func (a *facade) CreateSecret(ns string, secret *specV1.Secret) (*specV1.Secret,
 	return secret, err
 }
 
+// UpdateSecret updates the secret and the apps referencing it, which is rejected if any of the apps has a canary in progress
 func (a *facade) UpdateSecret(ns string, secret *specV1.Secret) (*specV1.Secret, error) {
-	secret, err := a.secret.Update(ns, secret)
+	apps, err := a.listAppsBySecret(ns, secret.Name)
 	if err != nil {
 		return nil, err
 	}
-	err = a.updateAppSecret(ns, secret)
+	for _, app := range apps {
+		if err = a.CheckAppCanary(ns, app); err != nil {
+			return nil, err
+		}
+	}
+	secret, err = a.secret.Update(nil, ns, secret)
+	if err != nil {
+		return nil, err
+	}
+	err = a.updateAppSecret(nil, ns, secret, apps)
 	if err != nil {
 		return nil, err
 	}
@@ -45,27 +55,35 @@ func (a *facade) DeleteSecret(ns, name string) error {
 	return a.secret.Delete(nil, ns, name)
 }
 
-func (a *facade) updateAppSecret(namespace string, secret *specV1.Secret) error {
-	appNames, err := a.index.ListAppIndexBySecret(namespace, secret.Name)
+func (a *facade) listAppsBySecret(namespace, name string) ([]*specV1.Application, error) {
+	appNames, err := a.index.ListAppIndexBySecret(namespace, name)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	var apps []*specV1.Application
 	for _, appName := range appNames {
 		app, err := a.app.Get(namespace, appName, "")
 		if err != nil {
 			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
 				continue
 			}
-			return err
+			return nil, err
 		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+func (a *facade) updateAppSecret(tx interface{}, namespace string, secret *specV1.Secret, apps []*specV1.Application) error {
+	for _, app := range apps {
 		if !needUpdateAppSecret(secret, app) {
 			continue
 		}
-		app, err = a.app.Update(nil, namespace, app)
+		app, err := a.app.Update(tx, namespace, app)
 		if err != nil {
 			return err
 		}
-		_, err = a.node.UpdateNodeAppVersion(nil, namespace, app)
+		_, err = a.node.UpdateNodeAppVersion(tx, namespace, app)
 		if err != nil {
 			return err
 		}
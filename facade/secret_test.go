@@ -6,6 +6,9 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 func TestCreateSecret(t *testing.T) {
@@ -95,11 +98,17 @@ func TestUpdateSecret(t *testing.T) {
 		},
 	}
 
-	mFacade.sSecret.EXPECT().Update(ns, gomock.Any()).Return(nil, unknownErr)
+	mFacade.sIndex.EXPECT().ListAppIndexBySecret(ns, name).Return(nil, unknownErr).Times(1)
 	_, err := sFacade.UpdateSecret(ns, mConf)
 	assert.Error(t, err, unknownErr)
 
-	mFacade.sSecret.EXPECT().Update(ns, gomock.Any()).Return(mConfSecret3, nil).AnyTimes()
+	mFacade.sIndex.EXPECT().ListAppIndexBySecret(ns, name).Return([]string{}, nil).Times(1)
+	mFacade.sSecret.EXPECT().Update(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
+	_, err = sFacade.UpdateSecret(ns, mConf)
+	assert.Error(t, err, unknownErr)
+
+	mFacade.sApp.EXPECT().GetCanary(ns, gomock.Any()).Return(nil, common.Error(common.ErrResourceNotFound)).AnyTimes()
+	mFacade.sSecret.EXPECT().Update(nil, ns, gomock.Any()).Return(mConfSecret3, nil).AnyTimes()
 	mFacade.sIndex.EXPECT().ListAppIndexBySecret(ns, name).Return(appNames, nil).Times(1)
 	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
 	mFacade.sApp.EXPECT().Get(ns, appNames[1], "").Return(apps[1], nil).Times(1)
@@ -135,6 +144,36 @@ func TestUpdateSecret(t *testing.T) {
 	assert.Error(t, err, unknownErr)
 }
 
+// the secret referenced by the app with a canary in progress is not updated
+func TestUpdateSecretWithAppCanary(t *testing.T) {
+	mFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	sFacade := &facade{
+		node:      mFacade.sNode,
+		app:       mFacade.sApp,
+		secret:    mFacade.sSecret,
+		index:     mFacade.sIndex,
+		txFactory: mFacade.txFactory,
+	}
+	ns := "default"
+	secret := &specV1.Secret{Namespace: ns, Name: "abc", Version: "2"}
+	app := &specV1.Application{
+		Namespace: ns,
+		Name:      "app01",
+		Version:   "5",
+		Volumes: []specV1.Volume{{
+			Name:         "vol0",
+			VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "abc", Version: "1"}},
+		}},
+	}
+	mFacade.sIndex.EXPECT().ListAppIndexBySecret(ns, "abc").Return([]string{"app01"}, nil).Times(1)
+	mFacade.sApp.EXPECT().Get(ns, "app01", "").Return(app, nil).Times(1)
+	mFacade.sApp.EXPECT().GetCanary(ns, "app01").Return(&models.AppCanary{Name: "app01", Version: "5"}, nil).Times(1)
+	_, err := sFacade.UpdateSecret(ns, secret)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has a canary in progress")
+}
+
 func TestDeleteSecret(t *testing.T) {
 	mFacade, mCtl := InitMockEnvironment(t)
 	defer mCtl.Finish()
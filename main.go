@@ -17,8 +17,11 @@ import (
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/decryption"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/auth"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/csrf"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/kms"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/license"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/lock"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/metrics"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/objectupload"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/pki"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/quota"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/sign"
@@ -26,6 +29,7 @@ import (
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/transaction"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/kube"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/link/httplink"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/oidc"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/sign"
 	"github.com/baetyl/baetyl-cloud/v2/server"
 )
@@ -45,10 +49,26 @@ func main() {
 
 		common.SetConfFile(ctx.ConfFile())
 
+		shutdownTracing, err := server.InitTracing(cfg.Tracing)
+		if err != nil {
+			return err
+		}
+		defer shutdownTracing()
+
 		a, err := api.NewAPI(&cfg)
 		if err != nil {
 			return err
 		}
+		stop := make(chan struct{})
+		defer close(stop)
+		go a.RunAppTrashReaper(stop)
+		go a.RunCertificateReaper(stop)
+		go a.RunAppScheduler(stop)
+		go a.Offline.Run(stop)
+		go a.Webhook.Run(stop)
+		// the events being delivered to the webhooks are waited for on shutdown
+		defer a.Webhook.Close()
+		defer a.Offline.Close()
 		sa, err := api.NewSyncAPI(&cfg)
 		if err != nil {
 			return err
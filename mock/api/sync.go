@@ -33,6 +33,22 @@ func (m *MockSyncAPI) EXPECT() *MockSyncAPIMockRecorder {
 	return m.recorder
 }
 
+// CheckNodeToken mocks base method
+func (m *MockSyncAPI) CheckNodeToken(arg0 string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckNodeToken", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CheckNodeToken indicates an expected call of CheckNodeToken
+func (mr *MockSyncAPIMockRecorder) CheckNodeToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckNodeToken", reflect.TypeOf((*MockSyncAPI)(nil).CheckNodeToken), arg0)
+}
+
 // Desire mocks base method
 func (m *MockSyncAPI) Desire(arg0 v1.Message) (*v1.Message, error) {
 	m.ctrl.T.Helper()
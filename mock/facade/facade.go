@@ -5,35 +5,67 @@
 package facade
 
 import (
+	reflect "reflect"
+	time "time"
+
+	models "github.com/baetyl/baetyl-cloud/v2/models"
 	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	gomock "github.com/golang/mock/gomock"
-	reflect "reflect"
 )
 
-// MockFacade is a mock of Facade interface
+// MockFacade is a mock of Facade interface.
 type MockFacade struct {
 	ctrl     *gomock.Controller
 	recorder *MockFacadeMockRecorder
 }
 
-// MockFacadeMockRecorder is the mock recorder for MockFacade
+// MockFacadeMockRecorder is the mock recorder for MockFacade.
 type MockFacadeMockRecorder struct {
 	mock *MockFacade
 }
 
-// NewMockFacade creates a new mock instance
+// NewMockFacade creates a new mock instance.
 func NewMockFacade(ctrl *gomock.Controller) *MockFacade {
 	mock := &MockFacade{ctrl: ctrl}
 	mock.recorder = &MockFacadeMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockFacade) EXPECT() *MockFacadeMockRecorder {
 	return m.recorder
 }
 
-// CreateApp mocks base method
+// AbortAppCanary mocks base method.
+func (m *MockFacade) AbortAppCanary(arg0 string, arg1 *v1.Application) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortAppCanary", arg0, arg1)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AbortAppCanary indicates an expected call of AbortAppCanary.
+func (mr *MockFacadeMockRecorder) AbortAppCanary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortAppCanary", reflect.TypeOf((*MockFacade)(nil).AbortAppCanary), arg0, arg1)
+}
+
+// CheckAppCanary mocks base method.
+func (m *MockFacade) CheckAppCanary(arg0 string, arg1 *v1.Application) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAppCanary", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckAppCanary indicates an expected call of CheckAppCanary.
+func (mr *MockFacadeMockRecorder) CheckAppCanary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAppCanary", reflect.TypeOf((*MockFacade)(nil).CheckAppCanary), arg0, arg1)
+}
+
+// CreateApp mocks base method.
 func (m *MockFacade) CreateApp(arg0 string, arg1, arg2 *v1.Application, arg3 []v1.Configuration) (*v1.Application, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateApp", arg0, arg1, arg2, arg3)
@@ -42,13 +74,13 @@ func (m *MockFacade) CreateApp(arg0 string, arg1, arg2 *v1.Application, arg3 []v
 	return ret0, ret1
 }
 
-// CreateApp indicates an expected call of CreateApp
+// CreateApp indicates an expected call of CreateApp.
 func (mr *MockFacadeMockRecorder) CreateApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApp", reflect.TypeOf((*MockFacade)(nil).CreateApp), arg0, arg1, arg2, arg3)
 }
 
-// CreateConfig mocks base method
+// CreateConfig mocks base method.
 func (m *MockFacade) CreateConfig(arg0 string, arg1 *v1.Configuration) (*v1.Configuration, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateConfig", arg0, arg1)
@@ -57,13 +89,13 @@ func (m *MockFacade) CreateConfig(arg0 string, arg1 *v1.Configuration) (*v1.Conf
 	return ret0, ret1
 }
 
-// CreateConfig indicates an expected call of CreateConfig
+// CreateConfig indicates an expected call of CreateConfig.
 func (mr *MockFacadeMockRecorder) CreateConfig(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfig", reflect.TypeOf((*MockFacade)(nil).CreateConfig), arg0, arg1)
 }
 
-// CreateSecret mocks base method
+// CreateSecret mocks base method.
 func (m *MockFacade) CreateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateSecret", arg0, arg1)
@@ -72,13 +104,13 @@ func (m *MockFacade) CreateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, err
 	return ret0, ret1
 }
 
-// CreateSecret indicates an expected call of CreateSecret
+// CreateSecret indicates an expected call of CreateSecret.
 func (mr *MockFacadeMockRecorder) CreateSecret(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecret", reflect.TypeOf((*MockFacade)(nil).CreateSecret), arg0, arg1)
 }
 
-// DeleteApp mocks base method
+// DeleteApp mocks base method.
 func (m *MockFacade) DeleteApp(arg0, arg1 string, arg2 *v1.Application) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteApp", arg0, arg1, arg2)
@@ -86,13 +118,13 @@ func (m *MockFacade) DeleteApp(arg0, arg1 string, arg2 *v1.Application) error {
 	return ret0
 }
 
-// DeleteApp indicates an expected call of DeleteApp
+// DeleteApp indicates an expected call of DeleteApp.
 func (mr *MockFacadeMockRecorder) DeleteApp(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApp", reflect.TypeOf((*MockFacade)(nil).DeleteApp), arg0, arg1, arg2)
 }
 
-// DeleteConfig mocks base method
+// DeleteConfig mocks base method.
 func (m *MockFacade) DeleteConfig(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteConfig", arg0, arg1)
@@ -100,13 +132,13 @@ func (m *MockFacade) DeleteConfig(arg0, arg1 string) error {
 	return ret0
 }
 
-// DeleteConfig indicates an expected call of DeleteConfig
+// DeleteConfig indicates an expected call of DeleteConfig.
 func (mr *MockFacadeMockRecorder) DeleteConfig(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfig", reflect.TypeOf((*MockFacade)(nil).DeleteConfig), arg0, arg1)
 }
 
-// DeleteSecret mocks base method
+// DeleteSecret mocks base method.
 func (m *MockFacade) DeleteSecret(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteSecret", arg0, arg1)
@@ -114,13 +146,13 @@ func (m *MockFacade) DeleteSecret(arg0, arg1 string) error {
 	return ret0
 }
 
-// DeleteSecret indicates an expected call of DeleteSecret
+// DeleteSecret indicates an expected call of DeleteSecret.
 func (mr *MockFacadeMockRecorder) DeleteSecret(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockFacade)(nil).DeleteSecret), arg0, arg1)
 }
 
-// GetApp mocks base method
+// GetApp mocks base method.
 func (m *MockFacade) GetApp(arg0, arg1, arg2 string) (*v1.Application, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetApp", arg0, arg1, arg2)
@@ -129,13 +161,85 @@ func (m *MockFacade) GetApp(arg0, arg1, arg2 string) (*v1.Application, error) {
 	return ret0, ret1
 }
 
-// GetApp indicates an expected call of GetApp
+// GetApp indicates an expected call of GetApp.
 func (mr *MockFacadeMockRecorder) GetApp(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApp", reflect.TypeOf((*MockFacade)(nil).GetApp), arg0, arg1, arg2)
 }
 
-// UpdateApp mocks base method
+// ImportResources mocks base method.
+func (m *MockFacade) ImportResources(arg0 string, arg1 []models.NamespaceImportResource) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportResources", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportResources indicates an expected call of ImportResources.
+func (mr *MockFacadeMockRecorder) ImportResources(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportResources", reflect.TypeOf((*MockFacade)(nil).ImportResources), arg0, arg1)
+}
+
+// PromoteAppCanary mocks base method.
+func (m *MockFacade) PromoteAppCanary(arg0 string, arg1 *v1.Application, arg2 *models.AppCanary, arg3 int) (*models.AppCanary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromoteAppCanary", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.AppCanary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PromoteAppCanary indicates an expected call of PromoteAppCanary.
+func (mr *MockFacadeMockRecorder) PromoteAppCanary(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteAppCanary", reflect.TypeOf((*MockFacade)(nil).PromoteAppCanary), arg0, arg1, arg2, arg3)
+}
+
+// PurgeApp mocks base method.
+func (m *MockFacade) PurgeApp(arg0 string, arg1 *models.AppTrash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeApp", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeApp indicates an expected call of PurgeApp.
+func (mr *MockFacadeMockRecorder) PurgeApp(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeApp", reflect.TypeOf((*MockFacade)(nil).PurgeApp), arg0, arg1)
+}
+
+// RestoreApp mocks base method.
+func (m *MockFacade) RestoreApp(arg0 string, arg1 *v1.Application, arg2 []v1.Configuration) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreApp indicates an expected call of RestoreApp.
+func (mr *MockFacadeMockRecorder) RestoreApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreApp", reflect.TypeOf((*MockFacade)(nil).RestoreApp), arg0, arg1, arg2)
+}
+
+// TrashApp mocks base method.
+func (m *MockFacade) TrashApp(arg0 string, arg1 *v1.Application, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrashApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TrashApp indicates an expected call of TrashApp.
+func (mr *MockFacadeMockRecorder) TrashApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashApp", reflect.TypeOf((*MockFacade)(nil).TrashApp), arg0, arg1, arg2)
+}
+
+// UpdateApp mocks base method.
 func (m *MockFacade) UpdateApp(arg0 string, arg1, arg2 *v1.Application, arg3 []v1.Configuration) (*v1.Application, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateApp", arg0, arg1, arg2, arg3)
@@ -144,13 +248,29 @@ func (m *MockFacade) UpdateApp(arg0 string, arg1, arg2 *v1.Application, arg3 []v
 	return ret0, ret1
 }
 
-// UpdateApp indicates an expected call of UpdateApp
+// UpdateApp indicates an expected call of UpdateApp.
 func (mr *MockFacadeMockRecorder) UpdateApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApp", reflect.TypeOf((*MockFacade)(nil).UpdateApp), arg0, arg1, arg2, arg3)
 }
 
-// UpdateConfig mocks base method
+// UpdateAppCanary mocks base method.
+func (m *MockFacade) UpdateAppCanary(arg0 string, arg1, arg2 *v1.Application, arg3 int) (*v1.Application, *models.AppCanary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAppCanary", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(*models.AppCanary)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateAppCanary indicates an expected call of UpdateAppCanary.
+func (mr *MockFacadeMockRecorder) UpdateAppCanary(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppCanary", reflect.TypeOf((*MockFacade)(nil).UpdateAppCanary), arg0, arg1, arg2, arg3)
+}
+
+// UpdateConfig mocks base method.
 func (m *MockFacade) UpdateConfig(arg0 string, arg1 *v1.Configuration) (*v1.Configuration, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateConfig", arg0, arg1)
@@ -159,13 +279,13 @@ func (m *MockFacade) UpdateConfig(arg0 string, arg1 *v1.Configuration) (*v1.Conf
 	return ret0, ret1
 }
 
-// UpdateConfig indicates an expected call of UpdateConfig
+// UpdateConfig indicates an expected call of UpdateConfig.
 func (mr *MockFacadeMockRecorder) UpdateConfig(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockFacade)(nil).UpdateConfig), arg0, arg1)
 }
 
-// UpdateSecret mocks base method
+// UpdateSecret mocks base method.
 func (m *MockFacade) UpdateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateSecret", arg0, arg1)
@@ -174,7 +294,7 @@ func (m *MockFacade) UpdateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, err
 	return ret0, ret1
 }
 
-// UpdateSecret indicates an expected call of UpdateSecret
+// UpdateSecret indicates an expected call of UpdateSecret.
 func (mr *MockFacadeMockRecorder) UpdateSecret(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockFacade)(nil).UpdateSecret), arg0, arg1)
@@ -1,39 +1,41 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: Function)
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: Function,FunctionInvoker,FunctionUploader)
 
 // Package plugin is a generated GoMock package.
 package plugin
 
 import (
+	context "context"
+	reflect "reflect"
+
 	models "github.com/baetyl/baetyl-cloud/v2/models"
 	gomock "github.com/golang/mock/gomock"
-	reflect "reflect"
 )
 
-// MockFunction is a mock of Function interface
+// MockFunction is a mock of Function interface.
 type MockFunction struct {
 	ctrl     *gomock.Controller
 	recorder *MockFunctionMockRecorder
 }
 
-// MockFunctionMockRecorder is the mock recorder for MockFunction
+// MockFunctionMockRecorder is the mock recorder for MockFunction.
 type MockFunctionMockRecorder struct {
 	mock *MockFunction
 }
 
-// NewMockFunction creates a new mock instance
+// NewMockFunction creates a new mock instance.
 func NewMockFunction(ctrl *gomock.Controller) *MockFunction {
 	mock := &MockFunction{ctrl: ctrl}
 	mock.recorder = &MockFunctionMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockFunction) EXPECT() *MockFunctionMockRecorder {
 	return m.recorder
 }
 
-// Close mocks base method
+// Close mocks base method.
 func (m *MockFunction) Close() error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Close")
@@ -41,13 +43,13 @@ func (m *MockFunction) Close() error {
 	return ret0
 }
 
-// Close indicates an expected call of Close
+// Close indicates an expected call of Close.
 func (mr *MockFunctionMockRecorder) Close() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockFunction)(nil).Close))
 }
 
-// Get mocks base method
+// Get mocks base method.
 func (m *MockFunction) Get(arg0, arg1, arg2 string) (*models.Function, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2)
@@ -56,13 +58,13 @@ func (m *MockFunction) Get(arg0, arg1, arg2 string) (*models.Function, error) {
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get
+// Get indicates an expected call of Get.
 func (mr *MockFunctionMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockFunction)(nil).Get), arg0, arg1, arg2)
 }
 
-// List mocks base method
+// List mocks base method.
 func (m *MockFunction) List(arg0 string) ([]models.Function, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "List", arg0)
@@ -71,13 +73,13 @@ func (m *MockFunction) List(arg0 string) ([]models.Function, error) {
 	return ret0, ret1
 }
 
-// List indicates an expected call of List
+// List indicates an expected call of List.
 func (mr *MockFunctionMockRecorder) List(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFunction)(nil).List), arg0)
 }
 
-// ListFunctionVersions mocks base method
+// ListFunctionVersions mocks base method.
 func (m *MockFunction) ListFunctionVersions(arg0, arg1 string) ([]models.Function, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListFunctionVersions", arg0, arg1)
@@ -86,8 +88,84 @@ func (m *MockFunction) ListFunctionVersions(arg0, arg1 string) ([]models.Functio
 	return ret0, ret1
 }
 
-// ListFunctionVersions indicates an expected call of ListFunctionVersions
+// ListFunctionVersions indicates an expected call of ListFunctionVersions.
 func (mr *MockFunctionMockRecorder) ListFunctionVersions(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFunctionVersions", reflect.TypeOf((*MockFunction)(nil).ListFunctionVersions), arg0, arg1)
 }
+
+// MockFunctionInvoker is a mock of FunctionInvoker interface.
+type MockFunctionInvoker struct {
+	ctrl     *gomock.Controller
+	recorder *MockFunctionInvokerMockRecorder
+}
+
+// MockFunctionInvokerMockRecorder is the mock recorder for MockFunctionInvoker.
+type MockFunctionInvokerMockRecorder struct {
+	mock *MockFunctionInvoker
+}
+
+// NewMockFunctionInvoker creates a new mock instance.
+func NewMockFunctionInvoker(ctrl *gomock.Controller) *MockFunctionInvoker {
+	mock := &MockFunctionInvoker{ctrl: ctrl}
+	mock.recorder = &MockFunctionInvokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFunctionInvoker) EXPECT() *MockFunctionInvokerMockRecorder {
+	return m.recorder
+}
+
+// Invoke mocks base method.
+func (m *MockFunctionInvoker) Invoke(arg0 context.Context, arg1, arg2, arg3 string, arg4 []byte) (*models.FunctionInvocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invoke", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.FunctionInvocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Invoke indicates an expected call of Invoke.
+func (mr *MockFunctionInvokerMockRecorder) Invoke(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invoke", reflect.TypeOf((*MockFunctionInvoker)(nil).Invoke), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MockFunctionUploader is a mock of FunctionUploader interface.
+type MockFunctionUploader struct {
+	ctrl     *gomock.Controller
+	recorder *MockFunctionUploaderMockRecorder
+}
+
+// MockFunctionUploaderMockRecorder is the mock recorder for MockFunctionUploader.
+type MockFunctionUploaderMockRecorder struct {
+	mock *MockFunctionUploader
+}
+
+// NewMockFunctionUploader creates a new mock instance.
+func NewMockFunctionUploader(ctrl *gomock.Controller) *MockFunctionUploader {
+	mock := &MockFunctionUploader{ctrl: ctrl}
+	mock.recorder = &MockFunctionUploaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFunctionUploader) EXPECT() *MockFunctionUploaderMockRecorder {
+	return m.recorder
+}
+
+// Upload mocks base method.
+func (m *MockFunctionUploader) Upload(arg0 string, arg1 *models.Function, arg2 []byte) (*models.Function, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Function)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockFunctionUploaderMockRecorder) Upload(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockFunctionUploader)(nil).Upload), arg0, arg1, arg2)
+}
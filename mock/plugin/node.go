@@ -138,3 +138,18 @@ func (mr *MockNodeMockRecorder) UpdateNode(arg0, arg1, arg2 interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNode", reflect.TypeOf((*MockNode)(nil).UpdateNode), arg0, arg1, arg2)
 }
+
+// UpdateNodeIfVersion mocks base method.
+func (m *MockNode) UpdateNodeIfVersion(arg0 interface{}, arg1 string, arg2 *v1.Node, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNodeIfVersion", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNodeIfVersion indicates an expected call of UpdateNodeIfVersion.
+func (mr *MockNodeMockRecorder) UpdateNodeIfVersion(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeIfVersion", reflect.TypeOf((*MockNode)(nil).UpdateNodeIfVersion), arg0, arg1, arg2, arg3)
+}
@@ -9,6 +9,7 @@ import (
 	plugin "github.com/baetyl/baetyl-cloud/v2/plugin"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockPKI is a mock of PKI interface
@@ -194,6 +195,44 @@ func (mr *MockPKIMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockPKI)(nil).Close))
 }
 
+// MockPKIValidity is a mock of PKIValidity interface
+type MockPKIValidity struct {
+	ctrl     *gomock.Controller
+	recorder *MockPKIValidityMockRecorder
+}
+
+// MockPKIValidityMockRecorder is the mock recorder for MockPKIValidity
+type MockPKIValidityMockRecorder struct {
+	mock *MockPKIValidity
+}
+
+// NewMockPKIValidity creates a new mock instance
+func NewMockPKIValidity(ctrl *gomock.Controller) *MockPKIValidity {
+	mock := &MockPKIValidity{ctrl: ctrl}
+	mock.recorder = &MockPKIValidityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPKIValidity) EXPECT() *MockPKIValidityMockRecorder {
+	return m.recorder
+}
+
+// CreateClientCertWithValidity mocks base method
+func (m *MockPKIValidity) CreateClientCertWithValidity(csr []byte, rootId string, validity time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClientCertWithValidity", csr, rootId, validity)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClientCertWithValidity indicates an expected call of CreateClientCertWithValidity
+func (mr *MockPKIValidityMockRecorder) CreateClientCertWithValidity(csr, rootId, validity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClientCertWithValidity", reflect.TypeOf((*MockPKIValidity)(nil).CreateClientCertWithValidity), csr, rootId, validity)
+}
+
 // MockPKIStorage is a mock of PKIStorage interface
 type MockPKIStorage struct {
 	ctrl     *gomock.Controller
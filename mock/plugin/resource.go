@@ -435,17 +435,32 @@ func (mr *MockResourceMockRecorder) UpdateNode(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNode", reflect.TypeOf((*MockResource)(nil).UpdateNode), arg0, arg1, arg2)
 }
 
+// UpdateNodeIfVersion mocks base method.
+func (m *MockResource) UpdateNodeIfVersion(arg0 interface{}, arg1 string, arg2 *v1.Node, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNodeIfVersion", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNodeIfVersion indicates an expected call of UpdateNodeIfVersion.
+func (mr *MockResourceMockRecorder) UpdateNodeIfVersion(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeIfVersion", reflect.TypeOf((*MockResource)(nil).UpdateNodeIfVersion), arg0, arg1, arg2, arg3)
+}
+
 // UpdateSecret mocks base method.
-func (m *MockResource) UpdateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, error) {
+func (m *MockResource) UpdateSecret(arg0 interface{}, arg1 string, arg2 *v1.Secret) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSecret", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateSecret", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*v1.Secret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateSecret indicates an expected call of UpdateSecret.
-func (mr *MockResourceMockRecorder) UpdateSecret(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockResourceMockRecorder) UpdateSecret(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockResource)(nil).UpdateSecret), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockResource)(nil).UpdateSecret), arg0, arg1, arg2)
 }
@@ -95,16 +95,16 @@ func (mr *MockSecretMockRecorder) ListSecret(arg0, arg1 interface{}) *gomock.Cal
 }
 
 // UpdateSecret mocks base method.
-func (m *MockSecret) UpdateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, error) {
+func (m *MockSecret) UpdateSecret(arg0 interface{}, arg1 string, arg2 *v1.Secret) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSecret", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateSecret", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*v1.Secret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateSecret indicates an expected call of UpdateSecret.
-func (mr *MockSecretMockRecorder) UpdateSecret(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockSecretMockRecorder) UpdateSecret(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockSecret)(nil).UpdateSecret), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockSecret)(nil).UpdateSecret), arg0, arg1, arg2)
 }
@@ -6,6 +6,7 @@ package service
 
 import (
 	reflect "reflect"
+	time "time"
 
 	models "github.com/baetyl/baetyl-cloud/v2/models"
 	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
@@ -79,6 +80,34 @@ func (mr *MockApplicationServiceMockRecorder) Delete(arg0, arg1, arg2, arg3 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockApplicationService)(nil).Delete), arg0, arg1, arg2, arg3)
 }
 
+// DeleteCanary mocks base method.
+func (m *MockApplicationService) DeleteCanary(arg0 interface{}, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCanary", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCanary indicates an expected call of DeleteCanary.
+func (mr *MockApplicationServiceMockRecorder) DeleteCanary(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCanary", reflect.TypeOf((*MockApplicationService)(nil).DeleteCanary), arg0, arg1, arg2)
+}
+
+// DeleteTrash mocks base method.
+func (m *MockApplicationService) DeleteTrash(arg0 interface{}, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTrash", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTrash indicates an expected call of DeleteTrash.
+func (mr *MockApplicationServiceMockRecorder) DeleteTrash(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrash", reflect.TypeOf((*MockApplicationService)(nil).DeleteTrash), arg0, arg1, arg2)
+}
+
 // Get mocks base method.
 func (m *MockApplicationService) Get(arg0, arg1, arg2 string) (*v1.Application, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +123,51 @@ func (mr *MockApplicationServiceMockRecorder) Get(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockApplicationService)(nil).Get), arg0, arg1, arg2)
 }
 
+// GetCanary mocks base method.
+func (m *MockApplicationService) GetCanary(arg0, arg1 string) (*models.AppCanary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCanary", arg0, arg1)
+	ret0, _ := ret[0].(*models.AppCanary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCanary indicates an expected call of GetCanary.
+func (mr *MockApplicationServiceMockRecorder) GetCanary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCanary", reflect.TypeOf((*MockApplicationService)(nil).GetCanary), arg0, arg1)
+}
+
+// GetHistory mocks base method.
+func (m *MockApplicationService) GetHistory(arg0, arg1, arg2 string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockApplicationServiceMockRecorder) GetHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockApplicationService)(nil).GetHistory), arg0, arg1, arg2)
+}
+
+// GetTrash mocks base method.
+func (m *MockApplicationService) GetTrash(arg0, arg1 string) (*models.AppTrash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", arg0, arg1)
+	ret0, _ := ret[0].(*models.AppTrash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockApplicationServiceMockRecorder) GetTrash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockApplicationService)(nil).GetTrash), arg0, arg1)
+}
+
 // List mocks base method.
 func (m *MockApplicationService) List(arg0 string, arg1 *models.ListOptions) (*models.ApplicationList, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +198,79 @@ func (mr *MockApplicationServiceMockRecorder) ListByNames(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByNames", reflect.TypeOf((*MockApplicationService)(nil).ListByNames), arg0, arg1)
 }
 
+// ListExpiredTrash mocks base method.
+func (m *MockApplicationService) ListExpiredTrash(arg0 time.Time, arg1 int) ([]models.AppTrash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredTrash", arg0, arg1)
+	ret0, _ := ret[0].([]models.AppTrash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredTrash indicates an expected call of ListExpiredTrash.
+func (mr *MockApplicationServiceMockRecorder) ListExpiredTrash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredTrash", reflect.TypeOf((*MockApplicationService)(nil).ListExpiredTrash), arg0, arg1)
+}
+
+// ListTrash mocks base method.
+func (m *MockApplicationService) ListTrash(arg0 string) (*models.AppTrashList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", arg0)
+	ret0, _ := ret[0].(*models.AppTrashList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockApplicationServiceMockRecorder) ListTrash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockApplicationService)(nil).ListTrash), arg0)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockApplicationService) PurgeTrash(arg0 interface{}, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockApplicationServiceMockRecorder) PurgeTrash(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockApplicationService)(nil).PurgeTrash), arg0, arg1, arg2)
+}
+
+// SaveCanary mocks base method.
+func (m *MockApplicationService) SaveCanary(arg0 interface{}, arg1 *models.AppCanary) (*models.AppCanary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCanary", arg0, arg1)
+	ret0, _ := ret[0].(*models.AppCanary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveCanary indicates an expected call of SaveCanary.
+func (mr *MockApplicationServiceMockRecorder) SaveCanary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCanary", reflect.TypeOf((*MockApplicationService)(nil).SaveCanary), arg0, arg1)
+}
+
+// Trash mocks base method.
+func (m *MockApplicationService) Trash(arg0 interface{}, arg1 string, arg2 *v1.Application, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trash", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Trash indicates an expected call of Trash.
+func (mr *MockApplicationServiceMockRecorder) Trash(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trash", reflect.TypeOf((*MockApplicationService)(nil).Trash), arg0, arg1, arg2, arg3)
+}
+
 // Update mocks base method.
 func (m *MockApplicationService) Update(arg0 interface{}, arg1 string, arg2 *v1.Application) (*v1.Application, error) {
 	m.ctrl.T.Helper()
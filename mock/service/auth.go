@@ -89,3 +89,15 @@ func (mr *MockAuthServiceMockRecorder) Verify(arg0, arg1 interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockAuthService)(nil).Verify), arg0, arg1)
 }
+
+// InvalidateToken mocks base method
+func (m *MockAuthService) InvalidateToken(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateToken", arg0)
+}
+
+// InvalidateToken indicates an expected call of InvalidateToken
+func (mr *MockAuthServiceMockRecorder) InvalidateToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateToken", reflect.TypeOf((*MockAuthService)(nil).InvalidateToken), arg0)
+}
@@ -79,6 +79,21 @@ func (mr *MockConfigServiceMockRecorder) Get(arg0, arg1, arg2, arg3 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockConfigService)(nil).Get), arg0, arg1, arg2, arg3)
 }
 
+// GetVersion mocks base method.
+func (m *MockConfigService) GetVersion(arg0, arg1, arg2 string) (*v1.Configuration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Configuration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockConfigServiceMockRecorder) GetVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockConfigService)(nil).GetVersion), arg0, arg1, arg2)
+}
+
 // List mocks base method.
 func (m *MockConfigService) List(arg0 string, arg1 *models.ListOptions) (*models.ConfigurationList, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +109,21 @@ func (mr *MockConfigServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockConfigService)(nil).List), arg0, arg1)
 }
 
+// ListVersions mocks base method.
+func (m *MockConfigService) ListVersions(arg0, arg1 string) (*models.ResourceVersionList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", arg0, arg1)
+	ret0, _ := ret[0].(*models.ResourceVersionList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockConfigServiceMockRecorder) ListVersions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockConfigService)(nil).ListVersions), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockConfigService) Update(arg0 interface{}, arg1 string, arg2 *v1.Configuration) (*v1.Configuration, error) {
 	m.ctrl.T.Helper()
@@ -5,36 +5,51 @@
 package service
 
 import (
+	reflect "reflect"
+
 	common "github.com/baetyl/baetyl-cloud/v2/common"
 	models "github.com/baetyl/baetyl-cloud/v2/models"
 	gomock "github.com/golang/mock/gomock"
-	reflect "reflect"
 )
 
-// MockModuleService is a mock of ModuleService interface
+// MockModuleService is a mock of ModuleService interface.
 type MockModuleService struct {
 	ctrl     *gomock.Controller
 	recorder *MockModuleServiceMockRecorder
 }
 
-// MockModuleServiceMockRecorder is the mock recorder for MockModuleService
+// MockModuleServiceMockRecorder is the mock recorder for MockModuleService.
 type MockModuleServiceMockRecorder struct {
 	mock *MockModuleService
 }
 
-// NewMockModuleService creates a new mock instance
+// NewMockModuleService creates a new mock instance.
 func NewMockModuleService(ctrl *gomock.Controller) *MockModuleService {
 	mock := &MockModuleService{ctrl: ctrl}
 	mock.recorder = &MockModuleServiceMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockModuleService) EXPECT() *MockModuleServiceMockRecorder {
 	return m.recorder
 }
 
-// CreateModule mocks base method
+// CheckDependencies mocks base method.
+func (m *MockModuleService) CheckDependencies(arg0 *models.Module) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckDependencies", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckDependencies indicates an expected call of CheckDependencies.
+func (mr *MockModuleServiceMockRecorder) CheckDependencies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDependencies", reflect.TypeOf((*MockModuleService)(nil).CheckDependencies), arg0)
+}
+
+// CreateModule mocks base method.
 func (m *MockModuleService) CreateModule(arg0 *models.Module) (*models.Module, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateModule", arg0)
@@ -43,13 +58,13 @@ func (m *MockModuleService) CreateModule(arg0 *models.Module) (*models.Module, e
 	return ret0, ret1
 }
 
-// CreateModule indicates an expected call of CreateModule
+// CreateModule indicates an expected call of CreateModule.
 func (mr *MockModuleServiceMockRecorder) CreateModule(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModule", reflect.TypeOf((*MockModuleService)(nil).CreateModule), arg0)
 }
 
-// DeleteModuleByVersion mocks base method
+// DeleteModuleByVersion mocks base method.
 func (m *MockModuleService) DeleteModuleByVersion(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteModuleByVersion", arg0, arg1)
@@ -57,13 +72,13 @@ func (m *MockModuleService) DeleteModuleByVersion(arg0, arg1 string) error {
 	return ret0
 }
 
-// DeleteModuleByVersion indicates an expected call of DeleteModuleByVersion
+// DeleteModuleByVersion indicates an expected call of DeleteModuleByVersion.
 func (mr *MockModuleServiceMockRecorder) DeleteModuleByVersion(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModuleByVersion", reflect.TypeOf((*MockModuleService)(nil).DeleteModuleByVersion), arg0, arg1)
 }
 
-// DeleteModules mocks base method
+// DeleteModules mocks base method.
 func (m *MockModuleService) DeleteModules(arg0 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteModules", arg0)
@@ -71,13 +86,28 @@ func (m *MockModuleService) DeleteModules(arg0 string) error {
 	return ret0
 }
 
-// DeleteModules indicates an expected call of DeleteModules
+// DeleteModules indicates an expected call of DeleteModules.
 func (mr *MockModuleServiceMockRecorder) DeleteModules(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModules", reflect.TypeOf((*MockModuleService)(nil).DeleteModules), arg0)
 }
 
-// GetLatestModule mocks base method
+// GetDependencyTree mocks base method.
+func (m *MockModuleService) GetDependencyTree(arg0, arg1 string) (*models.ModuleDependencyTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDependencyTree", arg0, arg1)
+	ret0, _ := ret[0].(*models.ModuleDependencyTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDependencyTree indicates an expected call of GetDependencyTree.
+func (mr *MockModuleServiceMockRecorder) GetDependencyTree(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDependencyTree", reflect.TypeOf((*MockModuleService)(nil).GetDependencyTree), arg0, arg1)
+}
+
+// GetLatestModule mocks base method.
 func (m *MockModuleService) GetLatestModule(arg0 string) (*models.Module, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetLatestModule", arg0)
@@ -86,13 +116,13 @@ func (m *MockModuleService) GetLatestModule(arg0 string) (*models.Module, error)
 	return ret0, ret1
 }
 
-// GetLatestModule indicates an expected call of GetLatestModule
+// GetLatestModule indicates an expected call of GetLatestModule.
 func (mr *MockModuleServiceMockRecorder) GetLatestModule(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestModule", reflect.TypeOf((*MockModuleService)(nil).GetLatestModule), arg0)
 }
 
-// GetLatestModuleImage mocks base method
+// GetLatestModuleImage mocks base method.
 func (m *MockModuleService) GetLatestModuleImage(arg0 string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetLatestModuleImage", arg0)
@@ -101,13 +131,13 @@ func (m *MockModuleService) GetLatestModuleImage(arg0 string) (string, error) {
 	return ret0, ret1
 }
 
-// GetLatestModuleImage indicates an expected call of GetLatestModuleImage
+// GetLatestModuleImage indicates an expected call of GetLatestModuleImage.
 func (mr *MockModuleServiceMockRecorder) GetLatestModuleImage(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestModuleImage", reflect.TypeOf((*MockModuleService)(nil).GetLatestModuleImage), arg0)
 }
 
-// GetLatestModuleProgram mocks base method
+// GetLatestModuleProgram mocks base method.
 func (m *MockModuleService) GetLatestModuleProgram(arg0, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetLatestModuleProgram", arg0, arg1)
@@ -116,13 +146,13 @@ func (m *MockModuleService) GetLatestModuleProgram(arg0, arg1 string) (string, e
 	return ret0, ret1
 }
 
-// GetLatestModuleProgram indicates an expected call of GetLatestModuleProgram
+// GetLatestModuleProgram indicates an expected call of GetLatestModuleProgram.
 func (mr *MockModuleServiceMockRecorder) GetLatestModuleProgram(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestModuleProgram", reflect.TypeOf((*MockModuleService)(nil).GetLatestModuleProgram), arg0, arg1)
 }
 
-// GetModuleByImage mocks base method
+// GetModuleByImage mocks base method.
 func (m *MockModuleService) GetModuleByImage(arg0, arg1 string) (*models.Module, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetModuleByImage", arg0, arg1)
@@ -131,13 +161,13 @@ func (m *MockModuleService) GetModuleByImage(arg0, arg1 string) (*models.Module,
 	return ret0, ret1
 }
 
-// GetModuleByImage indicates an expected call of GetModuleByImage
+// GetModuleByImage indicates an expected call of GetModuleByImage.
 func (mr *MockModuleServiceMockRecorder) GetModuleByImage(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleByImage", reflect.TypeOf((*MockModuleService)(nil).GetModuleByImage), arg0, arg1)
 }
 
-// GetModuleByVersion mocks base method
+// GetModuleByVersion mocks base method.
 func (m *MockModuleService) GetModuleByVersion(arg0, arg1 string) (*models.Module, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetModuleByVersion", arg0, arg1)
@@ -146,13 +176,13 @@ func (m *MockModuleService) GetModuleByVersion(arg0, arg1 string) (*models.Modul
 	return ret0, ret1
 }
 
-// GetModuleByVersion indicates an expected call of GetModuleByVersion
+// GetModuleByVersion indicates an expected call of GetModuleByVersion.
 func (mr *MockModuleServiceMockRecorder) GetModuleByVersion(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleByVersion", reflect.TypeOf((*MockModuleService)(nil).GetModuleByVersion), arg0, arg1)
 }
 
-// GetModules mocks base method
+// GetModules mocks base method.
 func (m *MockModuleService) GetModules(arg0 string) ([]models.Module, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetModules", arg0)
@@ -161,13 +191,28 @@ func (m *MockModuleService) GetModules(arg0 string) ([]models.Module, error) {
 	return ret0, ret1
 }
 
-// GetModules indicates an expected call of GetModules
+// GetModules indicates an expected call of GetModules.
 func (mr *MockModuleServiceMockRecorder) GetModules(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModules", reflect.TypeOf((*MockModuleService)(nil).GetModules), arg0)
 }
 
-// ListModules mocks base method
+// ListDependents mocks base method.
+func (m *MockModuleService) ListDependents(arg0, arg1 string) ([]models.Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDependents", arg0, arg1)
+	ret0, _ := ret[0].([]models.Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDependents indicates an expected call of ListDependents.
+func (mr *MockModuleServiceMockRecorder) ListDependents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDependents", reflect.TypeOf((*MockModuleService)(nil).ListDependents), arg0, arg1)
+}
+
+// ListModules mocks base method.
 func (m *MockModuleService) ListModules(arg0 *models.Filter, arg1 common.ModuleType) ([]models.Module, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListModules", arg0, arg1)
@@ -176,13 +221,13 @@ func (m *MockModuleService) ListModules(arg0 *models.Filter, arg1 common.ModuleT
 	return ret0, ret1
 }
 
-// ListModules indicates an expected call of ListModules
+// ListModules indicates an expected call of ListModules.
 func (mr *MockModuleServiceMockRecorder) ListModules(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModules", reflect.TypeOf((*MockModuleService)(nil).ListModules), arg0, arg1)
 }
 
-// UpdateModuleByVersion mocks base method
+// UpdateModuleByVersion mocks base method.
 func (m *MockModuleService) UpdateModuleByVersion(arg0 *models.Module) (*models.Module, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateModuleByVersion", arg0)
@@ -191,7 +236,7 @@ func (m *MockModuleService) UpdateModuleByVersion(arg0 *models.Module) (*models.
 	return ret0, ret1
 }
 
-// UpdateModuleByVersion indicates an expected call of UpdateModuleByVersion
+// UpdateModuleByVersion indicates an expected call of UpdateModuleByVersion.
 func (mr *MockModuleServiceMockRecorder) UpdateModuleByVersion(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModuleByVersion", reflect.TypeOf((*MockModuleService)(nil).UpdateModuleByVersion), arg0)
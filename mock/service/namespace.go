@@ -5,35 +5,36 @@
 package service
 
 import (
+	reflect "reflect"
+
 	models "github.com/baetyl/baetyl-cloud/v2/models"
 	gomock "github.com/golang/mock/gomock"
-	reflect "reflect"
 )
 
-// MockNamespaceService is a mock of NamespaceService interface
+// MockNamespaceService is a mock of NamespaceService interface.
 type MockNamespaceService struct {
 	ctrl     *gomock.Controller
 	recorder *MockNamespaceServiceMockRecorder
 }
 
-// MockNamespaceServiceMockRecorder is the mock recorder for MockNamespaceService
+// MockNamespaceServiceMockRecorder is the mock recorder for MockNamespaceService.
 type MockNamespaceServiceMockRecorder struct {
 	mock *MockNamespaceService
 }
 
-// NewMockNamespaceService creates a new mock instance
+// NewMockNamespaceService creates a new mock instance.
 func NewMockNamespaceService(ctrl *gomock.Controller) *MockNamespaceService {
 	mock := &MockNamespaceService{ctrl: ctrl}
 	mock.recorder = &MockNamespaceServiceMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockNamespaceService) EXPECT() *MockNamespaceServiceMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method
+// Create mocks base method.
 func (m *MockNamespaceService) Create(arg0 *models.Namespace) (*models.Namespace, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", arg0)
@@ -42,13 +43,13 @@ func (m *MockNamespaceService) Create(arg0 *models.Namespace) (*models.Namespace
 	return ret0, ret1
 }
 
-// Create indicates an expected call of Create
+// Create indicates an expected call of Create.
 func (mr *MockNamespaceServiceMockRecorder) Create(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNamespaceService)(nil).Create), arg0)
 }
 
-// Delete mocks base method
+// Delete mocks base method.
 func (m *MockNamespaceService) Delete(arg0 *models.Namespace) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Delete", arg0)
@@ -56,13 +57,13 @@ func (m *MockNamespaceService) Delete(arg0 *models.Namespace) error {
 	return ret0
 }
 
-// Delete indicates an expected call of Delete
+// Delete indicates an expected call of Delete.
 func (mr *MockNamespaceServiceMockRecorder) Delete(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNamespaceService)(nil).Delete), arg0)
 }
 
-// Get mocks base method
+// Get mocks base method.
 func (m *MockNamespaceService) Get(arg0 string) (*models.Namespace, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", arg0)
@@ -71,13 +72,58 @@ func (m *MockNamespaceService) Get(arg0 string) (*models.Namespace, error) {
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get
+// Get indicates an expected call of Get.
 func (mr *MockNamespaceServiceMockRecorder) Get(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNamespaceService)(nil).Get), arg0)
 }
 
-// List mocks base method
+// GetFeatures mocks base method.
+func (m *MockNamespaceService) GetFeatures(arg0 string) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatures", arg0)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatures indicates an expected call of GetFeatures.
+func (mr *MockNamespaceServiceMockRecorder) GetFeatures(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatures", reflect.TypeOf((*MockNamespaceService)(nil).GetFeatures), arg0)
+}
+
+// GetSettings mocks base method.
+func (m *MockNamespaceService) GetSettings(arg0 string) (*models.NamespaceSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", arg0)
+	ret0, _ := ret[0].(*models.NamespaceSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockNamespaceServiceMockRecorder) GetSettings(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockNamespaceService)(nil).GetSettings), arg0)
+}
+
+// IsFeatureEnabled mocks base method.
+func (m *MockNamespaceService) IsFeatureEnabled(arg0, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFeatureEnabled", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsFeatureEnabled indicates an expected call of IsFeatureEnabled.
+func (mr *MockNamespaceServiceMockRecorder) IsFeatureEnabled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFeatureEnabled", reflect.TypeOf((*MockNamespaceService)(nil).IsFeatureEnabled), arg0, arg1)
+}
+
+// List mocks base method.
 func (m *MockNamespaceService) List(arg0 *models.ListOptions) (*models.NamespaceList, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "List", arg0)
@@ -86,8 +132,23 @@ func (m *MockNamespaceService) List(arg0 *models.ListOptions) (*models.Namespace
 	return ret0, ret1
 }
 
-// List indicates an expected call of List
+// List indicates an expected call of List.
 func (mr *MockNamespaceServiceMockRecorder) List(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNamespaceService)(nil).List), arg0)
 }
+
+// UpdateSettings mocks base method.
+func (m *MockNamespaceService) UpdateSettings(arg0 string, arg1 *models.NamespaceSettings) (*models.NamespaceSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettings", arg0, arg1)
+	ret0, _ := ret[0].(*models.NamespaceSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockNamespaceServiceMockRecorder) UpdateSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockNamespaceService)(nil).UpdateSettings), arg0, arg1)
+}
@@ -6,6 +6,7 @@ package service
 
 import (
 	reflect "reflect"
+	time "time"
 
 	models "github.com/baetyl/baetyl-cloud/v2/models"
 	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
@@ -124,6 +125,21 @@ func (mr *MockNodeServiceMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNodeService)(nil).Get), arg0, arg1, arg2)
 }
 
+// GetDeployRecord mocks base method.
+func (m *MockNodeService) GetDeployRecord(arg0, arg1 string, arg2 int64) (*models.NodeDeployRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeployRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.NodeDeployRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeployRecord indicates an expected call of GetDeployRecord.
+func (mr *MockNodeServiceMockRecorder) GetDeployRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeployRecord", reflect.TypeOf((*MockNodeService)(nil).GetDeployRecord), arg0, arg1, arg2)
+}
+
 // GetDesire mocks base method.
 func (m *MockNodeService) GetDesire(arg0, arg1 string) (*v1.Desire, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +185,81 @@ func (mr *MockNodeServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNodeService)(nil).List), arg0, arg1)
 }
 
+// ListDeployHistory mocks base method.
+func (m *MockNodeService) ListDeployHistory(arg0, arg1 string, arg2 *models.Filter) (*models.NodeDeployRecordList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeployHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.NodeDeployRecordList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeployHistory indicates an expected call of ListDeployHistory.
+func (mr *MockNodeServiceMockRecorder) ListDeployHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeployHistory", reflect.TypeOf((*MockNodeService)(nil).ListDeployHistory), arg0, arg1, arg2)
+}
+
+// ListPropertyHistory mocks base method.
+func (m *MockNodeService) ListPropertyHistory(arg0, arg1 string, arg2 *models.NodePropertyRecordListOptions) (*models.NodePropertyRecordList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPropertyHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.NodePropertyRecordList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPropertyHistory indicates an expected call of ListPropertyHistory.
+func (mr *MockNodeServiceMockRecorder) ListPropertyHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPropertyHistory", reflect.TypeOf((*MockNodeService)(nil).ListPropertyHistory), arg0, arg1, arg2)
+}
+
+// ListReportTime mocks base method.
+func (m *MockNodeService) ListReportTime(arg0 string) (map[string]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReportTime", arg0)
+	ret0, _ := ret[0].(map[string]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReportTime indicates an expected call of ListReportTime.
+func (mr *MockNodeServiceMockRecorder) ListReportTime(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReportTime", reflect.TypeOf((*MockNodeService)(nil).ListReportTime), arg0)
+}
+
+// ListStatsHistory mocks base method.
+func (m *MockNodeService) ListStatsHistory(arg0, arg1 string, arg2, arg3 time.Time, arg4 time.Duration) ([]models.NodeStatsPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatsHistory", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]models.NodeStatsPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatsHistory indicates an expected call of ListStatsHistory.
+func (mr *MockNodeServiceMockRecorder) ListStatsHistory(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatsHistory", reflect.TypeOf((*MockNodeService)(nil).ListStatsHistory), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ListLatestStats mocks base method.
+func (m *MockNodeService) ListLatestStats(arg0 string, arg1 []string, arg2 time.Time) (map[string]*models.NodeStatsPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLatestStats", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[string]*models.NodeStatsPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLatestStats indicates an expected call of ListLatestStats.
+func (mr *MockNodeServiceMockRecorder) ListLatestStats(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestStats", reflect.TypeOf((*MockNodeService)(nil).ListLatestStats), arg0, arg1, arg2)
+}
+
 // Update mocks base method.
 func (m *MockNodeService) Update(arg0 string, arg1 *v1.Node) (*v1.Node, error) {
 	m.ctrl.T.Helper()
@@ -228,6 +319,36 @@ func (mr *MockNodeServiceMockRecorder) UpdateNodeAppVersion(arg0, arg1, arg2 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeAppVersion", reflect.TypeOf((*MockNodeService)(nil).UpdateNodeAppVersion), arg0, arg1, arg2)
 }
 
+// UpdateNodeAttributes mocks base method.
+func (m *MockNodeService) UpdateNodeAttributes(arg0, arg1 string, arg2 map[string]interface{}) (*v1.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNodeAttributes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNodeAttributes indicates an expected call of UpdateNodeAttributes.
+func (mr *MockNodeServiceMockRecorder) UpdateNodeAttributes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeAttributes", reflect.TypeOf((*MockNodeService)(nil).UpdateNodeAttributes), arg0, arg1, arg2)
+}
+
+// UpdateNodeAttributesIf mocks base method.
+func (m *MockNodeService) UpdateNodeAttributesIf(arg0, arg1 string, arg2 func(*v1.Node) error, arg3 map[string]interface{}) (*v1.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNodeAttributesIf", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNodeAttributesIf indicates an expected call of UpdateNodeAttributesIf.
+func (mr *MockNodeServiceMockRecorder) UpdateNodeAttributesIf(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeAttributesIf", reflect.TypeOf((*MockNodeService)(nil).UpdateNodeAttributesIf), arg0, arg1, arg2, arg3)
+}
+
 // UpdateNodeMode mocks base method.
 func (m *MockNodeService) UpdateNodeMode(arg0, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -243,18 +364,33 @@ func (mr *MockNodeServiceMockRecorder) UpdateNodeMode(arg0, arg1, arg2 interface
 }
 
 // UpdateNodeProperties mocks base method.
-func (m *MockNodeService) UpdateNodeProperties(arg0, arg1 string, arg2 *models.NodeProperties) (*models.NodeProperties, error) {
+func (m *MockNodeService) UpdateNodeProperties(arg0, arg1, arg2 string, arg3 *models.NodeProperties) (*models.NodeProperties, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateNodeProperties", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UpdateNodeProperties", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*models.NodeProperties)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateNodeProperties indicates an expected call of UpdateNodeProperties.
-func (mr *MockNodeServiceMockRecorder) UpdateNodeProperties(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockNodeServiceMockRecorder) UpdateNodeProperties(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeProperties", reflect.TypeOf((*MockNodeService)(nil).UpdateNodeProperties), arg0, arg1, arg2, arg3)
+}
+
+// UpdateNodeTags mocks base method.
+func (m *MockNodeService) UpdateNodeTags(arg0, arg1 string, arg2 map[string]string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNodeTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNodeTags indicates an expected call of UpdateNodeTags.
+func (mr *MockNodeServiceMockRecorder) UpdateNodeTags(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeProperties", reflect.TypeOf((*MockNodeService)(nil).UpdateNodeProperties), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeTags", reflect.TypeOf((*MockNodeService)(nil).UpdateNodeTags), arg0, arg1, arg2)
 }
 
 // UpdateReport mocks base method.
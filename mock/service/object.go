@@ -5,35 +5,36 @@
 package service
 
 import (
+	reflect "reflect"
+
 	models "github.com/baetyl/baetyl-cloud/v2/models"
 	gomock "github.com/golang/mock/gomock"
-	reflect "reflect"
 )
 
-// MockObjectService is a mock of ObjectService interface
+// MockObjectService is a mock of ObjectService interface.
 type MockObjectService struct {
 	ctrl     *gomock.Controller
 	recorder *MockObjectServiceMockRecorder
 }
 
-// MockObjectServiceMockRecorder is the mock recorder for MockObjectService
+// MockObjectServiceMockRecorder is the mock recorder for MockObjectService.
 type MockObjectServiceMockRecorder struct {
 	mock *MockObjectService
 }
 
-// NewMockObjectService creates a new mock instance
+// NewMockObjectService creates a new mock instance.
 func NewMockObjectService(ctrl *gomock.Controller) *MockObjectService {
 	mock := &MockObjectService{ctrl: ctrl}
 	mock.recorder = &MockObjectServiceMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockObjectService) EXPECT() *MockObjectServiceMockRecorder {
 	return m.recorder
 }
 
-// CreateExternalBucket mocks base method
+// CreateExternalBucket mocks base method.
 func (m *MockObjectService) CreateExternalBucket(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateExternalBucket", arg0, arg1, arg2, arg3)
@@ -41,13 +42,13 @@ func (m *MockObjectService) CreateExternalBucket(arg0 models.ExternalObjectInfo,
 	return ret0
 }
 
-// CreateExternalBucket indicates an expected call of CreateExternalBucket
+// CreateExternalBucket indicates an expected call of CreateExternalBucket.
 func (mr *MockObjectServiceMockRecorder) CreateExternalBucket(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalBucket", reflect.TypeOf((*MockObjectService)(nil).CreateExternalBucket), arg0, arg1, arg2, arg3)
 }
 
-// CreateInternalBucketIfNotExist mocks base method
+// CreateInternalBucketIfNotExist mocks base method.
 func (m *MockObjectService) CreateInternalBucketIfNotExist(arg0, arg1, arg2, arg3 string) (*models.Bucket, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateInternalBucketIfNotExist", arg0, arg1, arg2, arg3)
@@ -56,13 +57,13 @@ func (m *MockObjectService) CreateInternalBucketIfNotExist(arg0, arg1, arg2, arg
 	return ret0, ret1
 }
 
-// CreateInternalBucketIfNotExist indicates an expected call of CreateInternalBucketIfNotExist
+// CreateInternalBucketIfNotExist indicates an expected call of CreateInternalBucketIfNotExist.
 func (mr *MockObjectServiceMockRecorder) CreateInternalBucketIfNotExist(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInternalBucketIfNotExist", reflect.TypeOf((*MockObjectService)(nil).CreateInternalBucketIfNotExist), arg0, arg1, arg2, arg3)
 }
 
-// DeleteExternalObject mocks base method
+// DeleteExternalObject mocks base method.
 func (m *MockObjectService) DeleteExternalObject(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteExternalObject", arg0, arg1, arg2, arg3)
@@ -70,13 +71,13 @@ func (m *MockObjectService) DeleteExternalObject(arg0 models.ExternalObjectInfo,
 	return ret0
 }
 
-// DeleteExternalObject indicates an expected call of DeleteExternalObject
+// DeleteExternalObject indicates an expected call of DeleteExternalObject.
 func (mr *MockObjectServiceMockRecorder) DeleteExternalObject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExternalObject", reflect.TypeOf((*MockObjectService)(nil).DeleteExternalObject), arg0, arg1, arg2, arg3)
 }
 
-// GenExternalObjectURL mocks base method
+// GenExternalObjectURL mocks base method.
 func (m *MockObjectService) GenExternalObjectURL(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string) (*models.ObjectURL, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenExternalObjectURL", arg0, arg1, arg2, arg3)
@@ -85,13 +86,13 @@ func (m *MockObjectService) GenExternalObjectURL(arg0 models.ExternalObjectInfo,
 	return ret0, ret1
 }
 
-// GenExternalObjectURL indicates an expected call of GenExternalObjectURL
+// GenExternalObjectURL indicates an expected call of GenExternalObjectURL.
 func (mr *MockObjectServiceMockRecorder) GenExternalObjectURL(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenExternalObjectURL", reflect.TypeOf((*MockObjectService)(nil).GenExternalObjectURL), arg0, arg1, arg2, arg3)
 }
 
-// GenInternalObjectPutURL mocks base method
+// GenInternalObjectPutURL mocks base method.
 func (m *MockObjectService) GenInternalObjectPutURL(arg0, arg1, arg2, arg3 string) (*models.ObjectURL, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenInternalObjectPutURL", arg0, arg1, arg2, arg3)
@@ -100,13 +101,13 @@ func (m *MockObjectService) GenInternalObjectPutURL(arg0, arg1, arg2, arg3 strin
 	return ret0, ret1
 }
 
-// GenInternalObjectPutURL indicates an expected call of GenInternalObjectPutURL
+// GenInternalObjectPutURL indicates an expected call of GenInternalObjectPutURL.
 func (mr *MockObjectServiceMockRecorder) GenInternalObjectPutURL(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenInternalObjectPutURL", reflect.TypeOf((*MockObjectService)(nil).GenInternalObjectPutURL), arg0, arg1, arg2, arg3)
 }
 
-// GenInternalObjectURL mocks base method
+// GenInternalObjectURL mocks base method.
 func (m *MockObjectService) GenInternalObjectURL(arg0, arg1, arg2, arg3 string) (*models.ObjectURL, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenInternalObjectURL", arg0, arg1, arg2, arg3)
@@ -115,13 +116,28 @@ func (m *MockObjectService) GenInternalObjectURL(arg0, arg1, arg2, arg3 string)
 	return ret0, ret1
 }
 
-// GenInternalObjectURL indicates an expected call of GenInternalObjectURL
+// GenInternalObjectURL indicates an expected call of GenInternalObjectURL.
 func (mr *MockObjectServiceMockRecorder) GenInternalObjectURL(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenInternalObjectURL", reflect.TypeOf((*MockObjectService)(nil).GenInternalObjectURL), arg0, arg1, arg2, arg3)
 }
 
-// GetExternalObject mocks base method
+// GetExternalBucketLifecycle mocks base method.
+func (m *MockObjectService) GetExternalBucketLifecycle(arg0 models.ExternalObjectInfo, arg1, arg2 string) (*models.BucketLifecycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalBucketLifecycle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.BucketLifecycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalBucketLifecycle indicates an expected call of GetExternalBucketLifecycle.
+func (mr *MockObjectServiceMockRecorder) GetExternalBucketLifecycle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalBucketLifecycle", reflect.TypeOf((*MockObjectService)(nil).GetExternalBucketLifecycle), arg0, arg1, arg2)
+}
+
+// GetExternalObject mocks base method.
 func (m *MockObjectService) GetExternalObject(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string) (*models.Object, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetExternalObject", arg0, arg1, arg2, arg3)
@@ -130,13 +146,28 @@ func (m *MockObjectService) GetExternalObject(arg0 models.ExternalObjectInfo, ar
 	return ret0, ret1
 }
 
-// GetExternalObject indicates an expected call of GetExternalObject
+// GetExternalObject indicates an expected call of GetExternalObject.
 func (mr *MockObjectServiceMockRecorder) GetExternalObject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalObject", reflect.TypeOf((*MockObjectService)(nil).GetExternalObject), arg0, arg1, arg2, arg3)
 }
 
-// HeadExternalObject mocks base method
+// GetInternalBucketLifecycle mocks base method.
+func (m *MockObjectService) GetInternalBucketLifecycle(arg0, arg1, arg2 string) (*models.BucketLifecycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalBucketLifecycle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.BucketLifecycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInternalBucketLifecycle indicates an expected call of GetInternalBucketLifecycle.
+func (mr *MockObjectServiceMockRecorder) GetInternalBucketLifecycle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalBucketLifecycle", reflect.TypeOf((*MockObjectService)(nil).GetInternalBucketLifecycle), arg0, arg1, arg2)
+}
+
+// HeadExternalObject mocks base method.
 func (m *MockObjectService) HeadExternalObject(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string) (*models.ObjectMeta, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "HeadExternalObject", arg0, arg1, arg2, arg3)
@@ -145,13 +176,42 @@ func (m *MockObjectService) HeadExternalObject(arg0 models.ExternalObjectInfo, a
 	return ret0, ret1
 }
 
-// HeadExternalObject indicates an expected call of HeadExternalObject
+// HeadExternalObject indicates an expected call of HeadExternalObject.
 func (mr *MockObjectServiceMockRecorder) HeadExternalObject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadExternalObject", reflect.TypeOf((*MockObjectService)(nil).HeadExternalObject), arg0, arg1, arg2, arg3)
 }
 
-// HeadInternalObject mocks base method
+// CheckObjectUpload mocks base method.
+func (m *MockObjectService) CheckObjectUpload(arg0 *models.ObjectUpload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckObjectUpload", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckObjectUpload indicates an expected call of CheckObjectUpload.
+func (mr *MockObjectServiceMockRecorder) CheckObjectUpload(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckObjectUpload", reflect.TypeOf((*MockObjectService)(nil).CheckObjectUpload), arg0)
+}
+
+// CompleteObjectUpload mocks base method.
+func (m *MockObjectService) CompleteObjectUpload(arg0 *models.ObjectUpload) (*models.ObjectUploadVerdict, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteObjectUpload", arg0)
+	ret0, _ := ret[0].(*models.ObjectUploadVerdict)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteObjectUpload indicates an expected call of CompleteObjectUpload.
+func (mr *MockObjectServiceMockRecorder) CompleteObjectUpload(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteObjectUpload", reflect.TypeOf((*MockObjectService)(nil).CompleteObjectUpload), arg0)
+}
+
+// HeadInternalObject mocks base method.
 func (m *MockObjectService) HeadInternalObject(arg0, arg1, arg2, arg3 string) (*models.ObjectMeta, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "HeadInternalObject", arg0, arg1, arg2, arg3)
@@ -160,13 +220,13 @@ func (m *MockObjectService) HeadInternalObject(arg0, arg1, arg2, arg3 string) (*
 	return ret0, ret1
 }
 
-// HeadInternalObject indicates an expected call of HeadInternalObject
+// HeadInternalObject indicates an expected call of HeadInternalObject.
 func (mr *MockObjectServiceMockRecorder) HeadInternalObject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadInternalObject", reflect.TypeOf((*MockObjectService)(nil).HeadInternalObject), arg0, arg1, arg2, arg3)
 }
 
-// ListExternalBucketObjects mocks base method
+// ListExternalBucketObjects mocks base method.
 func (m *MockObjectService) ListExternalBucketObjects(arg0 models.ExternalObjectInfo, arg1, arg2 string) (*models.ListObjectsResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListExternalBucketObjects", arg0, arg1, arg2)
@@ -175,13 +235,13 @@ func (m *MockObjectService) ListExternalBucketObjects(arg0 models.ExternalObject
 	return ret0, ret1
 }
 
-// ListExternalBucketObjects indicates an expected call of ListExternalBucketObjects
+// ListExternalBucketObjects indicates an expected call of ListExternalBucketObjects.
 func (mr *MockObjectServiceMockRecorder) ListExternalBucketObjects(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalBucketObjects", reflect.TypeOf((*MockObjectService)(nil).ListExternalBucketObjects), arg0, arg1, arg2)
 }
 
-// ListExternalBuckets mocks base method
+// ListExternalBuckets mocks base method.
 func (m *MockObjectService) ListExternalBuckets(arg0 models.ExternalObjectInfo, arg1 string) ([]models.Bucket, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListExternalBuckets", arg0, arg1)
@@ -190,13 +250,13 @@ func (m *MockObjectService) ListExternalBuckets(arg0 models.ExternalObjectInfo,
 	return ret0, ret1
 }
 
-// ListExternalBuckets indicates an expected call of ListExternalBuckets
+// ListExternalBuckets indicates an expected call of ListExternalBuckets.
 func (mr *MockObjectServiceMockRecorder) ListExternalBuckets(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalBuckets", reflect.TypeOf((*MockObjectService)(nil).ListExternalBuckets), arg0, arg1)
 }
 
-// ListInternalBucketObjects mocks base method
+// ListInternalBucketObjects mocks base method.
 func (m *MockObjectService) ListInternalBucketObjects(arg0, arg1, arg2 string) (*models.ListObjectsResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListInternalBucketObjects", arg0, arg1, arg2)
@@ -205,13 +265,13 @@ func (m *MockObjectService) ListInternalBucketObjects(arg0, arg1, arg2 string) (
 	return ret0, ret1
 }
 
-// ListInternalBucketObjects indicates an expected call of ListInternalBucketObjects
+// ListInternalBucketObjects indicates an expected call of ListInternalBucketObjects.
 func (mr *MockObjectServiceMockRecorder) ListInternalBucketObjects(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInternalBucketObjects", reflect.TypeOf((*MockObjectService)(nil).ListInternalBucketObjects), arg0, arg1, arg2)
 }
 
-// ListInternalBuckets mocks base method
+// ListInternalBuckets mocks base method.
 func (m *MockObjectService) ListInternalBuckets(arg0, arg1 string) ([]models.Bucket, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListInternalBuckets", arg0, arg1)
@@ -220,13 +280,13 @@ func (m *MockObjectService) ListInternalBuckets(arg0, arg1 string) ([]models.Buc
 	return ret0, ret1
 }
 
-// ListInternalBuckets indicates an expected call of ListInternalBuckets
+// ListInternalBuckets indicates an expected call of ListInternalBuckets.
 func (mr *MockObjectServiceMockRecorder) ListInternalBuckets(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInternalBuckets", reflect.TypeOf((*MockObjectService)(nil).ListInternalBuckets), arg0, arg1)
 }
 
-// ListSources mocks base method
+// ListSources mocks base method.
 func (m *MockObjectService) ListSources() map[string]models.ObjectStorageSourceV2 {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListSources")
@@ -234,13 +294,72 @@ func (m *MockObjectService) ListSources() map[string]models.ObjectStorageSourceV
 	return ret0
 }
 
-// ListSources indicates an expected call of ListSources
+// ListSources indicates an expected call of ListSources.
 func (mr *MockObjectServiceMockRecorder) ListSources() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSources", reflect.TypeOf((*MockObjectService)(nil).ListSources))
 }
 
-// PutExternalObject mocks base method
+// PresignExternalObjectURL mocks base method.
+func (m *MockObjectService) PresignExternalObjectURL(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string, arg4 *models.ObjectURLOptions) (*models.ObjectURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignExternalObjectURL", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.ObjectURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresignExternalObjectURL indicates an expected call of PresignExternalObjectURL.
+func (mr *MockObjectServiceMockRecorder) PresignExternalObjectURL(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignExternalObjectURL", reflect.TypeOf((*MockObjectService)(nil).PresignExternalObjectURL), arg0, arg1, arg2, arg3, arg4)
+}
+
+// PresignInternalObjectPutURL mocks base method.
+func (m *MockObjectService) PresignInternalObjectPutURL(arg0, arg1, arg2, arg3 string, arg4 *models.ObjectURLOptions) (*models.ObjectURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignInternalObjectPutURL", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.ObjectURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresignInternalObjectPutURL indicates an expected call of PresignInternalObjectPutURL.
+func (mr *MockObjectServiceMockRecorder) PresignInternalObjectPutURL(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignInternalObjectPutURL", reflect.TypeOf((*MockObjectService)(nil).PresignInternalObjectPutURL), arg0, arg1, arg2, arg3, arg4)
+}
+
+// PresignInternalObjectURL mocks base method.
+func (m *MockObjectService) PresignInternalObjectURL(arg0, arg1, arg2, arg3 string, arg4 *models.ObjectURLOptions) (*models.ObjectURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignInternalObjectURL", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.ObjectURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresignInternalObjectURL indicates an expected call of PresignInternalObjectURL.
+func (mr *MockObjectServiceMockRecorder) PresignInternalObjectURL(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignInternalObjectURL", reflect.TypeOf((*MockObjectService)(nil).PresignInternalObjectURL), arg0, arg1, arg2, arg3, arg4)
+}
+
+// PutExternalBucketLifecycle mocks base method.
+func (m *MockObjectService) PutExternalBucketLifecycle(arg0 models.ExternalObjectInfo, arg1, arg2 string, arg3 *models.BucketLifecycle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutExternalBucketLifecycle", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutExternalBucketLifecycle indicates an expected call of PutExternalBucketLifecycle.
+func (mr *MockObjectServiceMockRecorder) PutExternalBucketLifecycle(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutExternalBucketLifecycle", reflect.TypeOf((*MockObjectService)(nil).PutExternalBucketLifecycle), arg0, arg1, arg2, arg3)
+}
+
+// PutExternalObject mocks base method.
 func (m *MockObjectService) PutExternalObject(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string, arg4 []byte) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PutExternalObject", arg0, arg1, arg2, arg3, arg4)
@@ -248,13 +367,13 @@ func (m *MockObjectService) PutExternalObject(arg0 models.ExternalObjectInfo, ar
 	return ret0
 }
 
-// PutExternalObject indicates an expected call of PutExternalObject
+// PutExternalObject indicates an expected call of PutExternalObject.
 func (mr *MockObjectServiceMockRecorder) PutExternalObject(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutExternalObject", reflect.TypeOf((*MockObjectService)(nil).PutExternalObject), arg0, arg1, arg2, arg3, arg4)
 }
 
-// PutExternalObjectFromURL mocks base method
+// PutExternalObjectFromURL mocks base method.
 func (m *MockObjectService) PutExternalObjectFromURL(arg0 models.ExternalObjectInfo, arg1, arg2, arg3, arg4 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PutExternalObjectFromURL", arg0, arg1, arg2, arg3, arg4)
@@ -262,13 +381,27 @@ func (m *MockObjectService) PutExternalObjectFromURL(arg0 models.ExternalObjectI
 	return ret0
 }
 
-// PutExternalObjectFromURL indicates an expected call of PutExternalObjectFromURL
+// PutExternalObjectFromURL indicates an expected call of PutExternalObjectFromURL.
 func (mr *MockObjectServiceMockRecorder) PutExternalObjectFromURL(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutExternalObjectFromURL", reflect.TypeOf((*MockObjectService)(nil).PutExternalObjectFromURL), arg0, arg1, arg2, arg3, arg4)
 }
 
-// PutInternalObject mocks base method
+// PutInternalBucketLifecycle mocks base method.
+func (m *MockObjectService) PutInternalBucketLifecycle(arg0, arg1, arg2 string, arg3 *models.BucketLifecycle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutInternalBucketLifecycle", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutInternalBucketLifecycle indicates an expected call of PutInternalBucketLifecycle.
+func (mr *MockObjectServiceMockRecorder) PutInternalBucketLifecycle(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutInternalBucketLifecycle", reflect.TypeOf((*MockObjectService)(nil).PutInternalBucketLifecycle), arg0, arg1, arg2, arg3)
+}
+
+// PutInternalObject mocks base method.
 func (m *MockObjectService) PutInternalObject(arg0, arg1, arg2, arg3 string, arg4 []byte) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PutInternalObject", arg0, arg1, arg2, arg3, arg4)
@@ -276,13 +409,13 @@ func (m *MockObjectService) PutInternalObject(arg0, arg1, arg2, arg3 string, arg
 	return ret0
 }
 
-// PutInternalObject indicates an expected call of PutInternalObject
+// PutInternalObject indicates an expected call of PutInternalObject.
 func (mr *MockObjectServiceMockRecorder) PutInternalObject(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutInternalObject", reflect.TypeOf((*MockObjectService)(nil).PutInternalObject), arg0, arg1, arg2, arg3, arg4)
 }
 
-// PutInternalObjectFromURLIfNotExist mocks base method
+// PutInternalObjectFromURLIfNotExist mocks base method.
 func (m *MockObjectService) PutInternalObjectFromURLIfNotExist(arg0, arg1, arg2, arg3, arg4 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PutInternalObjectFromURLIfNotExist", arg0, arg1, arg2, arg3, arg4)
@@ -290,7 +423,7 @@ func (m *MockObjectService) PutInternalObjectFromURLIfNotExist(arg0, arg1, arg2,
 	return ret0
 }
 
-// PutInternalObjectFromURLIfNotExist indicates an expected call of PutInternalObjectFromURLIfNotExist
+// PutInternalObjectFromURLIfNotExist indicates an expected call of PutInternalObjectFromURLIfNotExist.
 func (mr *MockObjectServiceMockRecorder) PutInternalObjectFromURLIfNotExist(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutInternalObjectFromURLIfNotExist", reflect.TypeOf((*MockObjectService)(nil).PutInternalObjectFromURLIfNotExist), arg0, arg1, arg2, arg3, arg4)
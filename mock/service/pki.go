@@ -8,6 +8,7 @@ import (
 	models "github.com/baetyl/baetyl-cloud/v2/models"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockPKIService is a mock of PKIService interface
@@ -76,6 +77,21 @@ func (mr *MockPKIServiceMockRecorder) GetCA() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCA", reflect.TypeOf((*MockPKIService)(nil).GetCA))
 }
 
+// SignCertificateRequest mocks base method
+func (m *MockPKIService) SignCertificateRequest(arg0 []byte, arg1 time.Duration) (*models.PEMCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignCertificateRequest", arg0, arg1)
+	ret0, _ := ret[0].(*models.PEMCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignCertificateRequest indicates an expected call of SignCertificateRequest
+func (mr *MockPKIServiceMockRecorder) SignCertificateRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignCertificateRequest", reflect.TypeOf((*MockPKIService)(nil).SignCertificateRequest), arg0, arg1)
+}
+
 // SignClientCertificate mocks base method
 func (m *MockPKIService) SignClientCertificate(arg0 string, arg1 models.AltNames) (*models.PEMCredential, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +64,21 @@ func (mr *MockSecretServiceMockRecorder) Delete(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSecretService)(nil).Delete), arg0, arg1, arg2)
 }
 
+// Encrypt mocks base method.
+func (m *MockSecretService) Encrypt(arg0 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encrypt", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encrypt indicates an expected call of Encrypt.
+func (mr *MockSecretServiceMockRecorder) Encrypt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockSecretService)(nil).Encrypt), arg0)
+}
+
 // Get mocks base method.
 func (m *MockSecretService) Get(arg0, arg1, arg2 string) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +109,21 @@ func (mr *MockSecretServiceMockRecorder) GetTx(arg0, arg1, arg2, arg3 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTx", reflect.TypeOf((*MockSecretService)(nil).GetTx), arg0, arg1, arg2, arg3)
 }
 
+// GetVersion mocks base method.
+func (m *MockSecretService) GetVersion(arg0, arg1, arg2 string) (*v1.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockSecretServiceMockRecorder) GetVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockSecretService)(nil).GetVersion), arg0, arg1, arg2)
+}
+
 // List mocks base method.
 func (m *MockSecretService) List(arg0 string, arg1 *models.ListOptions) (*models.SecretList, error) {
 	m.ctrl.T.Helper()
@@ -109,17 +139,32 @@ func (mr *MockSecretServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSecretService)(nil).List), arg0, arg1)
 }
 
+// ListVersions mocks base method.
+func (m *MockSecretService) ListVersions(arg0, arg1 string) (*models.ResourceVersionList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", arg0, arg1)
+	ret0, _ := ret[0].(*models.ResourceVersionList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockSecretServiceMockRecorder) ListVersions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockSecretService)(nil).ListVersions), arg0, arg1)
+}
+
 // Update mocks base method.
-func (m *MockSecretService) Update(arg0 string, arg1 *v1.Secret) (*v1.Secret, error) {
+func (m *MockSecretService) Update(arg0 interface{}, arg1 string, arg2 *v1.Secret) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*v1.Secret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockSecretServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockSecretServiceMockRecorder) Update(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSecretService)(nil).Update), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSecretService)(nil).Update), arg0, arg1, arg2)
 }
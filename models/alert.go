@@ -0,0 +1,21 @@
+package models
+
+// AlertRuleGroups the prometheus alerting rules, which can be loaded as a rule file of prometheus
+type AlertRuleGroups struct {
+	Groups []AlertRuleGroup `yaml:"groups" json:"groups"`
+}
+
+// AlertRuleGroup a group of the prometheus alerting rules
+type AlertRuleGroup struct {
+	Name  string      `yaml:"name" json:"name"`
+	Rules []AlertRule `yaml:"rules" json:"rules"`
+}
+
+// AlertRule a prometheus alerting rule
+type AlertRule struct {
+	Alert       string            `yaml:"alert" json:"alert"`
+	Expr        string            `yaml:"expr" json:"expr"`
+	For         string            `yaml:"for,omitempty" json:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// the status of the scheduled deploys, a deploy is pending until it is due,
+// then it is running while being applied by a replica
+const (
+	AppSchedulePending   = "pending"
+	AppScheduleRunning   = "running"
+	AppScheduleSucceeded = "succeeded"
+	AppScheduleFailed    = "failed"
+	AppScheduleCanceled  = "canceled"
+)
+
+// AppSchedule an update of the application scheduled at a future time, which is given as a RFC3339 time,
+// or as a cron expression to deploy at the next time matching it, such as the next maintenance window
+type AppSchedule struct {
+	ID                int64            `json:"id"`
+	Namespace         string           `json:"namespace,omitempty"`
+	Name              string           `json:"name,omitempty"`
+	Time              *time.Time       `json:"time,omitempty"`
+	Cron              string           `json:"cron,omitempty"`
+	DueTime           time.Time        `json:"dueTime,omitempty"`
+	Application       *ApplicationView `json:"application,omitempty" binding:"required"`
+	Status            string           `json:"status,omitempty"`
+	Message           string           `json:"message,omitempty"`
+	CreationTimestamp time.Time        `json:"createTime,omitempty"`
+	UpdateTimestamp   time.Time        `json:"updateTime,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// AppTemplate a parameterized application, the template is the application in yaml or json with placeholders
+// like {{ .param }}, which are rendered with the values of the declared parameters when it is instantiated
+type AppTemplate struct {
+	Name              string                 `json:"name,omitempty" binding:"omitempty,res_name"`
+	Namespace         string                 `json:"namespace,omitempty"`
+	Labels            map[string]string      `json:"labels,omitempty"`
+	Description       string                 `json:"description"`
+	Parameters        []AppTemplateParameter `json:"parameters,omitempty" binding:"dive"`
+	Template          string                 `json:"template" binding:"required"`
+	CreationTimestamp time.Time              `json:"createTime,omitempty"`
+	UpdateTimestamp   time.Time              `json:"updateTime,omitempty"`
+}
+
+// AppTemplateParameter the declared parameter of the template
+type AppTemplateParameter struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description,omitempty"`
+	// Required the value must be given if there is no default value
+	Required bool   `json:"required,omitempty"`
+	Default  string `json:"default,omitempty"`
+}
+
+// AppTemplateList app template list
+type AppTemplateList struct {
+	Total        int `json:"total"`
+	*ListOptions `json:",inline"`
+	Items        []AppTemplate `json:"items"`
+}
+
+// AppTemplateInstance the values of the parameters to instantiate the template
+type AppTemplateInstance struct {
+	// Name the name of the application, overrides the name in the template if set
+	Name       string            `json:"name,omitempty" binding:"omitempty,res_name"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
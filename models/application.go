@@ -9,6 +9,15 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 )
 
+const (
+	EnvSourceSecret = "secret"
+	EnvSourceConfig = "config"
+	// EnvSourceVolumePrefix the prefix of the volumes of the secrets and configs referenced by the environment variables.
+	// The references are kept as the mounts of the volumes, whose sub paths are the keys and mount paths are the names
+	// of the variables, which are resolved into the values of the variables when the app is synced to the nodes
+	EnvSourceVolumePrefix = "baetyl-env-"
+)
+
 type ApplicationView struct {
 	Name              string                `json:"name,omitempty" binding:"res_name"`
 	Mode              string                `json:"mode,omitempty" default:"kube"`
@@ -35,6 +44,12 @@ type ApplicationView struct {
 	Ota               specV1.OtaInfo        `json:"ota,omitempty"`
 	AutoScaleCfg      *specV1.AutoScaleCfg  `json:"autoScaleCfg,omitempty"`
 	PreserveUpdates   bool                  `json:"preserveUpdates,omitempty"`
+	Strategy          *AppStrategy          `json:"strategy,omitempty"`
+	Canary            *AppCanary            `json:"canary,omitempty"`
+	// AutoMountSecrets mounts the secrets labeled for the app to all services without listing each
+	AutoMountSecrets bool `json:"autoMountSecrets,omitempty"`
+	// Warnings the problems found on saving, such as the target nodes without enough capacity
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 func (a *ApplicationView) ImageTrim() {
@@ -84,11 +99,274 @@ type ApplicationList struct {
 	Items        []AppItem `json:"items"`
 }
 
+// AppRollback the target version of rolling back an application
+type AppRollback struct {
+	Version string `json:"version" binding:"required"`
+}
+
+// AppLabelsPatch the JSON merge patch (RFC 7386) of the labels of an application, the labels set to null are removed
+// and the others are added or replaced. The applications have no annotations, so patching them is rejected
+type AppLabelsPatch struct {
+	Labels      map[string]*string `json:"labels,omitempty"`
+	Annotations map[string]*string `json:"annotations,omitempty"`
+}
+
+// AppTrash an application soft deleted, it can be restored until purged after the expire time
+type AppTrash struct {
+	Name        string              `json:"name"`
+	Namespace   string              `json:"namespace"`
+	Version     string              `json:"version"`
+	Type        string              `json:"type,omitempty"`
+	Labels      map[string]string   `json:"labels,omitempty"`
+	Description string              `json:"description,omitempty"`
+	DeleteTime  time.Time           `json:"deleteTime"`
+	ExpireTime  time.Time           `json:"expireTime"`
+	Application *specV1.Application `json:"-"`
+}
+
+// AppTrashList the soft deleted applications of a namespace
+type AppTrashList struct {
+	Total int        `json:"total"`
+	Items []AppTrash `json:"items"`
+}
+
+const (
+	AppStrategyRolling = "rolling"
+	AppStrategyCanary  = "canary"
+)
+
+// AppStrategy the strategy of rolling out an application update,
+// the update is rolled out to Percent of the target nodes first if Type is canary
+type AppStrategy struct {
+	Type    string `json:"type,omitempty" binding:"omitempty,oneof=rolling canary"`
+	Percent int    `json:"percent,omitempty" binding:"omitempty,min=1,max=99"`
+}
+
+// AppCanary the canary of an application update, the nodes of the canary run the new version
+// while the other nodes keep running the stable version until the canary is promoted or aborted
+type AppCanary struct {
+	Name          string    `json:"name"`
+	Namespace     string    `json:"namespace"`
+	Version       string    `json:"version"`
+	StableVersion string    `json:"stableVersion"`
+	Percent       int       `json:"percent"`
+	Nodes         []string  `json:"nodes"`
+	CreateTime    time.Time `json:"createTime"`
+	UpdateTime    time.Time `json:"updateTime"`
+}
+
+// AppPromote the percent of the target nodes the canary is advanced to, the canary is fully promoted if it is 0 or 100
+type AppPromote struct {
+	Percent int `json:"percent,omitempty" binding:"omitempty,min=1,max=100"`
+}
+
+// ApplicationDryRun the rendered application of a dry run and its diff against the current version
+type ApplicationDryRun struct {
+	Application *specV1.Application    `json:"application"`
+	Configs     []specV1.Configuration `json:"configs,omitempty"`
+	Diff        string                 `json:"diff"`
+	Warnings    []string               `json:"warnings,omitempty"`
+}
+
+// AppDiffParams the versions of an application to compare
+type AppDiffParams struct {
+	From string `form:"from" binding:"required"`
+	To   string `form:"to" binding:"required"`
+}
+
+const (
+	DiffAdded    = "added"
+	DiffRemoved  = "removed"
+	DiffModified = "modified"
+)
+
+// ApplicationDiff the structured difference between two versions of an application
+type ApplicationDiff struct {
+	Name     string        `json:"name"`
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Services []ServiceDiff `json:"services,omitempty"`
+	Configs  []FieldChange `json:"configs,omitempty"`
+	Patch    []JSONPatchOp `json:"patch"`
+	Diff     string        `json:"diff"`
+}
+
+// ServiceDiff the changes of a service, the details are omitted for an added or removed service
+type ServiceDiff struct {
+	Name         string        `json:"name"`
+	Change       string        `json:"change"`
+	Image        *FieldChange  `json:"image,omitempty"`
+	VolumeMounts []FieldChange `json:"volumeMounts,omitempty"`
+	Env          []FieldChange `json:"env,omitempty"`
+	Resources    []FieldChange `json:"resources,omitempty"`
+}
+
+// FieldChange the change of a named field
+type FieldChange struct {
+	Name   string      `json:"name"`
+	Change string      `json:"change"`
+	From   interface{} `json:"from,omitempty"`
+	To     interface{} `json:"to,omitempty"`
+}
+
+// JSONPatchOp an operation of json patch (RFC 6902)
+type JSONPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// AppNodeList the nodes currently matching the selector of the application
+type AppNodeList struct {
+	Selector string    `json:"selector"`
+	Total    int       `json:"total"`
+	Items    []AppNode `json:"items"`
+}
+
+// AppTargetsPreview the selector of an application to be created, whose target nodes are previewed
+type AppTargetsPreview struct {
+	Selector string `json:"selector" binding:"required"`
+}
+
+// AppTargets the number of the nodes matching the selector and a sample of their names
+type AppTargets struct {
+	Selector string   `json:"selector"`
+	Total    int      `json:"total"`
+	Sample   []string `json:"sample"`
+}
+
+// AppNode a node the application is deployed to, it is synced if the node reports the current version of the application
+type AppNode struct {
+	Name            string            `json:"name"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	ReportedVersion string            `json:"reportedVersion,omitempty"`
+	Synced          bool              `json:"synced"`
+}
+
+// the rollout status of an application on a node
+const (
+	AppRolloutApplied = "applied"
+	AppRolloutPending = "pending"
+	AppRolloutFailed  = "failed"
+)
+
+// AppRollout the roll-up of the rollout of the current version of an application across the nodes it targets,
+// the rollout is complete once every node has applied the version
+type AppRollout struct {
+	Name     string           `json:"name"`
+	Version  string           `json:"version"`
+	Selector string           `json:"selector"`
+	Total    int              `json:"total"`
+	Applied  int              `json:"applied"`
+	Pending  int              `json:"pending"`
+	Failed   int              `json:"failed"`
+	Complete bool             `json:"complete"`
+	Items    []AppRolloutNode `json:"items"`
+}
+
+// AppRolloutNode the rollout status of the application on a node, with the version desired by the node,
+// the version it currently applies and the status reported of the application
+type AppRolloutNode struct {
+	Name           string        `json:"name"`
+	Status         string        `json:"status"`
+	DesiredVersion string        `json:"desiredVersion,omitempty"`
+	AppliedVersion string        `json:"appliedVersion,omitempty"`
+	AppStatus      specV1.Status `json:"appStatus,omitempty"`
+	Cause          string        `json:"cause,omitempty"`
+}
+
+// the health of the instances of an application on the nodes
+const (
+	AppHealthHealthy   = "healthy"
+	AppHealthUnhealthy = "unhealthy"
+	AppHealthUnknown   = "unknown"
+
+	// AppHealthExtensionReady the key of the extension of the instance stats reported by the node, whose bool value
+	// is the readiness of the instance, such as the result of the readiness probes
+	AppHealthExtensionReady = "ready"
+)
+
+// AppHealth the roll-up of the health of the application across the nodes it targets, counted by the nodes.
+//
+// The health is taken from the instances of the application in the report of the node (report.apps[].instances).
+// An instance is healthy if it is running and ready, the readiness is the bool value of "ready" in the extension
+// of the instance if the node reports it, or whether all the containers of the instance are running otherwise.
+// It is unhealthy if it is failed, not ready, or any of its containers is not running, and unknown if the status
+// is pending or unknown. A node is healthy if all its instances are healthy, unhealthy if any of them is unhealthy,
+// and unknown otherwise, such as the node is not online, or it reports no instance or another version of the application
+type AppHealth struct {
+	Name      string          `json:"name"`
+	Version   string          `json:"version"`
+	Selector  string          `json:"selector"`
+	Total     int             `json:"total"`
+	Healthy   int             `json:"healthy"`
+	Unhealthy int             `json:"unhealthy"`
+	Unknown   int             `json:"unknown"`
+	Items     []AppHealthNode `json:"items"`
+}
+
+// AppHealthNode the health of the application on a node
+type AppHealthNode struct {
+	Name       string              `json:"name"`
+	Health     string              `json:"health"`
+	NodeStatus string              `json:"nodeStatus"`
+	Version    string              `json:"version,omitempty"`
+	Instances  []AppHealthInstance `json:"instances"`
+}
+
+// AppHealthInstance the health of an instance of the application, the service is given if the instance runs one service
+type AppHealthInstance struct {
+	Name    string        `json:"name"`
+	Service string        `json:"service,omitempty"`
+	Health  string        `json:"health"`
+	Status  specV1.Status `json:"status,omitempty"`
+	Cause   string        `json:"cause,omitempty"`
+}
+
 type ServiceFunction struct {
 	Functions []specV1.ServiceFunction `json:"functions,omitempty"`
 }
 
 type ServiceView struct {
 	specV1.Service `json:",inline"`
-	ProgramConfig  string `json:"programConfig,omitempty"`
+	Env            []EnvironmentView `json:"env,omitempty" binding:"dive"`
+	ProgramConfig  string            `json:"programConfig,omitempty"`
+}
+
+// EnvironmentView an environment variable of the service, the value can reference a key of a secret or config
+type EnvironmentView struct {
+	Name      string        `json:"name,omitempty"`
+	Value     string        `json:"value,omitempty"`
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty"`
+}
+
+// EnvVarSource references a key of a secret or config, exactly one of them is given
+type EnvVarSource struct {
+	Secret string `json:"secret,omitempty"`
+	Config string `json:"config,omitempty"`
+	Key    string `json:"key,omitempty" binding:"required"`
+}
+
+// VolumeName returns the name of the volume added for the secret or config referenced
+func (s *EnvVarSource) VolumeName() string {
+	if s.Secret != "" {
+		return EnvSourceVolumePrefix + EnvSourceSecret + "-" + s.Secret
+	}
+	return EnvSourceVolumePrefix + EnvSourceConfig + "-" + s.Config
+}
+
+// IsEnvSourceVolume returns whether the volume is added for the references of the environment variables
+func IsEnvSourceVolume(name string) bool {
+	return strings.HasPrefix(name, EnvSourceVolumePrefix)
+}
+
+// ApplicationCascadeDeletion the configs and secrets referenced by the app deleted with cascade,
+// the ones referenced only by the app are deleted along and the shared ones are kept
+type ApplicationCascadeDeletion struct {
+	DeletedConfigs []string `json:"deletedConfigs"`
+	DeletedSecrets []string `json:"deletedSecrets"`
+	SharedConfigs  []string `json:"sharedConfigs"`
+	SharedSecrets  []string `json:"sharedSecrets"`
+	// Failed the resources failed to delete, formatted as <type>/<name>
+	Failed []string `json:"failed,omitempty"`
 }
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Audit the record of a mutating api call
+type Audit struct {
+	ID        int64     `json:"id"`
+	Namespace string    `json:"namespace"`
+	UserID    string    `json:"userId,omitempty"`
+	UserName  string    `json:"userName,omitempty"`
+	Resource  string    `json:"resource"`
+	Name      string    `json:"name,omitempty"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	BodyHash  string    `json:"bodyHash,omitempty"`
+	Status    int       `json:"status"`
+	RequestID string    `json:"requestId,omitempty"`
+	ClientIP  string    `json:"clientIp,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AuditListOptions the filters of audit records, the time range is in unix seconds
+type AuditListOptions struct {
+	Resource  string `form:"resource,omitempty" json:"resource,omitempty"`
+	StartTime int64  `form:"startTime,omitempty" json:"startTime,omitempty"`
+	EndTime   int64  `form:"endTime,omitempty" json:"endTime,omitempty"`
+	Filter    `json:",inline"`
+}
+
+type AuditList struct {
+	Total             int `json:"total"`
+	*AuditListOptions `json:",inline"`
+	Items             []Audit `json:"items"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// TokenRevocation the token revoked, whose cached auth result is forgotten at once
+type TokenRevocation struct {
+	// Token the value of the Authorization header, such as Bearer xxx
+	Token string `json:"token" binding:"required"`
+}
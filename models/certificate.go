@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/jinzhu/copier"
 )
@@ -36,6 +37,22 @@ type PEMCredential struct {
 	CertId  string
 }
 
+// CSRRequest the certificate signing request of the client, whose private key never leaves the client
+type CSRRequest struct {
+	// CSR the pem of the request, whose common name is supposed to be <namespace>.client:<name>, such as default.client:console
+	CSR string `json:"csr" binding:"required"`
+	// ValidityDays how many days the certificate is valid, the max validity of the policy if 0
+	ValidityDays int `json:"validityDays,omitempty" binding:"omitempty,min=1"`
+}
+
+// CSRCertificate the certificate signed for the csr, and the chain of the certificate up to the ca
+type CSRCertificate struct {
+	CertId      string    `json:"certId"`
+	Certificate string    `json:"certificate"`
+	Chain       string    `json:"chain"`
+	NotAfter    time.Time `json:"notAfter"`
+}
+
 // CertStorage contains certName and keyName which can be used to
 // storage certificate and private key pem data to secret.
 type CertStorage struct {
@@ -58,6 +75,17 @@ type Certificate struct {
 	UpdateTimestamp    time.Time           `json:"updateTime,omitempty"`
 	Description        string              `json:"description"`
 	Version            string              `json:"version,omitempty"`
+	// CertId the id in pki of the certificate issued by baetyl-cloud
+	CertId string `json:"-"`
+	// DeprecatedCerts the certificates replaced by rotation, still valid until the overlap ends
+	DeprecatedCerts []DeprecatedCertificate `json:"-"`
+}
+
+// DeprecatedCertificate a certificate replaced by rotation, which is deleted from pki after it expires
+type DeprecatedCertificate struct {
+	CertId     string    `json:"certId"`
+	Client     bool      `json:"client,omitempty"`
+	ExpireTime time.Time `json:"expireTime"`
 }
 
 type CertificateDataItem struct {
@@ -72,6 +100,63 @@ type CertificateList struct {
 	Items        []Certificate `json:"items"`
 }
 
+const (
+	CertificateImportCreated = "created"
+	CertificateImportSkipped = "skipped"
+	CertificateImportFailed  = "failed"
+)
+
+// CertificateImport the certificates imported in bulk, the duplicates of the existing certificates or of the
+// earlier items by fingerprint are skipped or failed by DuplicatePolicy
+type CertificateImport struct {
+	DuplicatePolicy string                  `json:"duplicatePolicy" default:"skip" binding:"omitempty,oneof=skip fail"`
+	Items           []CertificateImportItem `json:"items" binding:"required,min=1,max=500"`
+}
+
+// CertificateImportItem a certificate and its private key in PEM
+type CertificateImportItem struct {
+	Name        string              `json:"name" binding:"required,res_name"`
+	Description string              `json:"description,omitempty"`
+	Data        CertificateDataItem `json:"data"`
+}
+
+// CertificateImportResult the result of importing a certificate, Duplicate is the name of the certificate
+// with the same fingerprint if it is a duplicate
+type CertificateImportResult struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	FingerPrint string `json:"fingerPrint,omitempty"`
+	Duplicate   string `json:"duplicate,omitempty"`
+	Code        string `json:"code,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+type CertificateImportResultList struct {
+	Total   int                       `json:"total"`
+	Created int                       `json:"created"`
+	Skipped int                       `json:"skipped"`
+	Failed  int                       `json:"failed"`
+	Items   []CertificateImportResult `json:"items"`
+}
+
+// ExpiringCertificate a certificate which expires soon and the apps referencing it
+type ExpiringCertificate struct {
+	Name          string    `json:"name"`
+	Namespace     string    `json:"namespace,omitempty"`
+	Issuer        string    `json:"issuer,omitempty"`
+	SerialNumber  string    `json:"serialNumber,omitempty"`
+	NotAfter      time.Time `json:"notAfter"`
+	DaysRemaining int       `json:"daysRemaining"`
+	Apps          []string  `json:"apps"`
+}
+
+// ExpiringCertificateList the certificates expiring within the duration, sorted by the soonest
+type ExpiringCertificateList struct {
+	Total  int                   `json:"total"`
+	Within string                `json:"within"`
+	Items  []ExpiringCertificate `json:"items"`
+}
+
 func (r *Certificate) Equal(target *Certificate) bool {
 	return reflect.DeepEqual(r.Data, target.Data) &&
 		reflect.DeepEqual(r.Description, target.Description)
@@ -97,6 +182,16 @@ func (r *Certificate) ToSecret() *specV1.Secret {
 		"issuer":             []byte(r.Issuer),
 		"fingerPrint":        []byte(r.FingerPrint),
 	}
+	if r.CertId != "" {
+		res.Data["certId"] = []byte(r.CertId)
+	}
+	if len(r.DeprecatedCerts) > 0 {
+		data, err := json.Marshal(r.DeprecatedCerts)
+		if err != nil {
+			panic(fmt.Sprintf("json marshal exception: %s", err.Error()))
+		}
+		res.Data["deprecatedCerts"] = data
+	}
 	return res
 }
 
@@ -128,6 +223,19 @@ func (r *Certificate) ParseCertInfo() error {
 	return nil
 }
 
+// ParseNotAfter parses the certificate data and returns the time it expires
+func (r *Certificate) ParseNotAfter() (time.Time, error) {
+	block, _ := pem.Decode([]byte(r.Data.Certificate))
+	if block == nil {
+		return time.Time{}, errors.New("failed to find certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Errorf("failed to parse certificate, err: %s", err)
+	}
+	return cert.NotAfter, nil
+}
+
 func fingerprint(data []byte) string {
 	digest := sha256.Sum256(data)
 	buf := &bytes.Buffer{}
@@ -24,6 +24,10 @@ type ConfigurationView struct {
 	Description       string            `json:"description,omitempty"`
 	Version           string            `json:"version,omitempty"`
 	System            bool              `json:"system,omitempty"`
+	// Schema the name of the config holding the JSON schema which the content is validated against
+	Schema string `json:"schema,omitempty" binding:"omitempty,res_name"`
+	// Template the kv items are templates rendered with the variables of the node they are delivered to
+	Template bool `json:"template,omitempty"`
 }
 
 type ConfigDataItem struct {
@@ -52,6 +56,19 @@ type ConfigObjectItem struct {
 	AddressFormat string `json:"addressFormat,omitempty" default:"pathStyle"`
 }
 
+// ConfigValidation the result of validating the content of a config against its schema
+type ConfigValidation struct {
+	Schema string                  `json:"schema,omitempty"`
+	Valid  bool                    `json:"valid"`
+	Errors []ConfigValidationError `json:"errors,omitempty"`
+}
+
+// ConfigValidationError the field failed to be validated and the reason
+type ConfigValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 func EqualConfig(config1, config2 *specV1.Configuration) bool {
 	return reflect.DeepEqual(config1.Labels, config2.Labels) &&
 		reflect.DeepEqual(config1.Data, config2.Data) &&
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	EventKindNode = "Node"
+
+	EventNodeOffline = "NodeOffline"
+	EventNodeOnline  = "NodeOnline"
+)
+
+// Event an event of a resource, such as a node going offline
+type Event struct {
+	ID        int64     `json:"id"`
+	Namespace string    `json:"namespace"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"encoding/base64"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
 )
@@ -16,6 +19,17 @@ const (
 
 	NodeSortAsc  = "asc"
 	NodeSortDesc = "desc"
+
+	// the fields the lists are sorted by, the name is the secondary sort of the others to keep the order stable
+	SortByName       = "name"
+	SortByCreateTime = "createTime"
+	SortByUpdateTime = "updateTime"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+
+	// DeprecatedOffsetPaging the hint returned in the list when the offset pagination is used
+	DeprecatedOffsetPaging = "pagination by pageNo and pageSize is deprecated, use limit and cursor instead"
 )
 
 type Filter struct {
@@ -33,14 +47,29 @@ type ListOptions struct {
 	Alias         string `form:"alias,omitempty" json:"alias,omitempty"`
 	Limit         int64  `form:"limit,omitempty" json:"limit,omitempty"`
 	Continue      string `form:"continue,omitempty" json:"continue,omitempty"`
-	NodeOptions   `json:",inline"`
-	Filter        `json:",inline"`
+	Cursor        string `form:"cursor,omitempty" json:"cursor,omitempty"`
+	SortBy        string `form:"sortBy,omitempty" json:"sortBy,omitempty"`
+	Order         string `form:"order,omitempty" json:"order,omitempty"`
+	NextCursor    string `form:"-" json:"nextCursor,omitempty"`
+	Deprecation   string `form:"-" json:"deprecation,omitempty"`
+	// CursorPaging whether the cursor param is given, which is empty to fetch the first page by keyset pagination
+	CursorPaging bool `form:"-" json:"-"`
+	NodeOptions  `json:",inline"`
+	Filter       `json:",inline"`
 }
 
 type NodeOptions struct {
 	Cluster    string `form:"cluster,omitempty" json:"cluster,omitempty" `
 	Ready      string `form:"ready,omitempty" json:"ready,omitempty" `
+	Status     string `form:"status,omitempty" json:"status,omitempty" `
 	CreateSort string `form:"createSort,omitempty" json:"createSort,omitempty" `
+	// Tag searches the text in the keys and values of the node tags
+	Tag string `form:"tag,omitempty" json:"tag,omitempty" `
+	// WithTags returns the tags in the annotations of the nodes
+	WithTags bool `form:"withTags,omitempty" json:"withTags,omitempty" `
+	// IncludeStats joins the latest resource utilization reported within StatsWindow into the nodes
+	IncludeStats bool          `form:"includeStats,omitempty" json:"includeStats,omitempty" `
+	StatsWindow  time.Duration `form:"statsWindow,omitempty" json:"statsWindow,omitempty" `
 }
 
 func (f *Filter) GetLimitOffset() int {
@@ -81,6 +110,71 @@ func (l *ListOptions) GetFuzzyAlias() string {
 	return l.Alias
 }
 
+// IsCursorPaging returns whether the keyset pagination is requested, which is the case only when the cursor
+// param is given, an empty one fetches the first page. The lists requested by limit alone are paged as before.
+// The total of a page fetched by cursor is the number of its items.
+func (l *ListOptions) IsCursorPaging() bool {
+	return l.Cursor != "" || l.CursorPaging
+}
+
+// CheckCursorPaging checks the cursor, and marks the offset pagination deprecated if it's used
+func (l *ListOptions) CheckCursorPaging() error {
+	if l.Cursor != "" {
+		if _, err := DecodeCursor(l.Cursor); err != nil {
+			return err
+		}
+	}
+	if l.PageNo > 0 {
+		l.Deprecation = DeprecatedOffsetPaging
+	}
+	return nil
+}
+
+// CheckSort checks the sort of the list against the fields allowed, the list is sorted by name ascending if sortBy is not set.
+// The sort is not supported by the keyset pagination, which keeps the order of the primary key
+func (l *ListOptions) CheckSort(fields ...string) error {
+	if l.SortBy == "" && l.Order == "" {
+		return nil
+	}
+	if l.SortBy != "" {
+		allowed := false
+		for _, f := range fields {
+			allowed = allowed || f == l.SortBy
+		}
+		if !allowed {
+			return errors.Errorf("sortBy should be one of %s", strings.Join(fields, ", "))
+		}
+	}
+	if l.Order != "" && l.Order != SortOrderAsc && l.Order != SortOrderDesc {
+		return errors.Errorf("order should be %s or %s", SortOrderAsc, SortOrderDesc)
+	}
+	if l.CreateSort != "" {
+		return errors.New("sortBy and order can't be used with createSort")
+	}
+	if l.IsCursorPaging() {
+		return errors.New("sortBy and order can't be used with cursor")
+	}
+	return nil
+}
+
+// EncodeCursor encodes the last-seen primary key into an opaque cursor
+func EncodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// DecodeCursor decodes the last-seen primary key from the cursor
+func DecodeCursor(cursor string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.New("cursor is invalid")
+	}
+	id, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("cursor is invalid")
+	}
+	return id, nil
+}
+
 func GetPagingParam(listOptions *ListOptions, resLen int) (start, end int) {
 	start = 0
 	end = resLen
@@ -102,11 +196,17 @@ func (l *ListOptions) NodeOptionsCheck() error {
 	if l.Ready != "" && l.Ready != ReadyTypeOnline && l.Ready != ReadyTypOffline && l.Ready != ReadyTypeUninstall {
 		return errors.Trace(errors.New("filter node ready  value error "))
 	}
+	if l.Status != "" && l.Status != ReadyTypeOnline && l.Status != ReadyTypOffline && l.Status != ReadyTypeUninstall {
+		return errors.Trace(errors.New("filter node status value error "))
+	}
 	if l.Cluster != "" && l.Cluster != NodeTypeCluster && l.Cluster != NodeTypeSingle {
 		return errors.Trace(errors.New("filter node cluster  value error "))
 	}
 	if l.CreateSort != "" && l.CreateSort != NodeSortAsc && l.CreateSort != NodeSortDesc {
 		return errors.Trace(errors.New("filter node create sort  value error "))
 	}
+	if l.IsCursorPaging() && (l.Ready != "" || l.Status != "" || l.Cluster != "" || l.CreateSort != "") {
+		return errors.Trace(errors.New("filter node ready, status, cluster and create sort can't be used with cursor"))
+	}
 	return nil
 }
@@ -26,3 +26,11 @@ type FunctionCode struct {
 	Sha256   string `yaml:"sha256,omitempty" json:"sha256,omitempty"`
 	Location string `yaml:"location,omitempty" json:"location,omitempty"`
 }
+
+// FunctionInvocation the result and execution logs of invoking a function
+type FunctionInvocation struct {
+	Result string `yaml:"result,omitempty" json:"result,omitempty"`
+	// Error the error raised by the function
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+	Logs  string `yaml:"logs,omitempty" json:"logs,omitempty"`
+}
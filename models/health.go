@@ -0,0 +1,27 @@
+package models
+
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"
+	HealthStatusUnhealthy = "unhealthy"
+
+	HealthCheckUp   = "up"
+	HealthCheckDown = "down"
+)
+
+// Health the overall status of the server, degraded if a non-critical dependency is down
+// and unhealthy if a critical one is
+type Health struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+// HealthCheck the status of a dependency
+type HealthCheck struct {
+	Name     string `json:"name"`
+	Plugin   string `json:"plugin,omitempty"`
+	Critical bool   `json:"critical"`
+	Status   string `json:"status"`
+	Latency  string `json:"latency"`
+	Error    string `json:"error,omitempty"`
+}
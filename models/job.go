@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// the status of the jobs, a job is pending until it is started by the replica accepting it,
+// and it is canceled at once if pending, or at its next step if running
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCanceled  = "canceled"
+)
+
+// the types of the jobs
+const (
+	JobNamespaceImport = "namespaceImport"
+	JobNamespaceExport = "namespaceExport"
+)
+
+// Job a long operation running in background, which is polled by its id for the progress and the result
+type Job struct {
+	ID        int64  `json:"jobId"`
+	Namespace string `json:"namespace,omitempty"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	// Progress the percentage of the work done
+	Progress          int         `json:"progress"`
+	Message           string      `json:"message,omitempty"`
+	Result            interface{} `json:"result,omitempty"`
+	CreationTimestamp time.Time   `json:"createTime,omitempty"`
+	UpdateTimestamp   time.Time   `json:"updateTime,omitempty"`
+}
+
+// IsJobDone returns whether the job is succeeded, failed or canceled
+func IsJobDone(status string) bool {
+	return status == JobSucceeded || status == JobFailed || status == JobCanceled
+}
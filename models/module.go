@@ -5,20 +5,70 @@ import (
 )
 
 type Module struct {
-	Name              string            `json:"name,omitempty"`
-	Version           string            `json:"version,omitempty"`
-	Image             string            `json:"image,omitempty"`
-	Programs          map[string]string `json:"programs,omitempty"`
-	Type              string            `json:"type,omitempty"`
-	Flag              int               `json:"flag"`
-	IsLatest          bool              `json:"isLatest,omitempty"`
-	Description       string            `json:"description,omitempty"`
-	CreationTimestamp time.Time         `json:"createTime,omitempty"`
-	UpdateTimestamp   time.Time         `json:"updateTime,omitempty"`
+	Name              string             `json:"name,omitempty"`
+	Version           string             `json:"version,omitempty"`
+	Image             string             `json:"image,omitempty"`
+	Programs          map[string]string  `json:"programs,omitempty"`
+	Type              string             `json:"type,omitempty"`
+	Flag              int                `json:"flag"`
+	IsLatest          bool               `json:"isLatest,omitempty"`
+	Description       string             `json:"description,omitempty"`
+	Dependencies      []ModuleDependency `json:"dependencies,omitempty" binding:"omitempty,dive"`
+	CreatedBy         string             `json:"createdBy,omitempty"`
+	CreationTimestamp time.Time          `json:"createTime,omitempty"`
+	UpdateTimestamp   time.Time          `json:"updateTime,omitempty"`
 }
 
+// ModuleDependency the module version depended on
+type ModuleDependency struct {
+	Name    string `json:"name" binding:"required"`
+	Version string `json:"version" binding:"required"`
+}
+
+// ModuleHistoryOptions the filters of the version history of a module, the time range of the creation is in unix seconds
+type ModuleHistoryOptions struct {
+	StartTime int64 `form:"startTime,omitempty" json:"startTime,omitempty"`
+	EndTime   int64 `form:"endTime,omitempty" json:"endTime,omitempty"`
+	Filter    `json:",inline"`
+}
+
+// ModuleHistory the versions of a module in the order of creation
+type ModuleHistory struct {
+	Name                  string `json:"name"`
+	Total                 int    `json:"total"`
+	*ModuleHistoryOptions `json:",inline"`
+	Items                 []ModuleHistoryItem `json:"items"`
+}
+
+// ModuleHistoryItem a version of the module, Changes summarizes the changes of the image, programs and
+// dependencies from the Previous version, which is empty for the first version
+type ModuleHistoryItem struct {
+	Version    string    `json:"version"`
+	Image      string    `json:"image,omitempty"`
+	IsLatest   bool      `json:"isLatest,omitempty"`
+	CreatedBy  string    `json:"createdBy,omitempty"`
+	CreateTime time.Time `json:"createTime"`
+	Previous   string    `json:"previous,omitempty"`
+	Changes    []string  `json:"changes,omitempty"`
+}
+
+// ModuleDependencyTree the module version with its dependencies resolved recursively
+type ModuleDependencyTree struct {
+	Name         string                 `json:"name"`
+	Version      string                 `json:"version"`
+	Dependencies []ModuleDependencyTree `json:"dependencies,omitempty"`
+}
+
+// the formats of the init command
+const (
+	InitCMDFormatShell      = "shell"
+	InitCMDFormatPowershell = "powershell"
+	InitCMDFormatYaml       = "yaml"
+)
+
 type InitCMD struct {
 	CMD    string `json:"cmd,omitempty"`
+	Format string `json:"format,omitempty"`
 	APK    string `json:"apk,omitempty"`
 	APKSys string `json:"apk_sys,omitempty"`
 }
@@ -29,3 +79,21 @@ type ModuleList struct {
 	PageSize int         `json:"pageSize,omitempty"`
 	Items    interface{} `json:"items"`
 }
+
+// ModuleArchValidation the result of checking the platforms of the image of the module against the nodes,
+// Missing lists the architectures of the nodes not supported by the image
+type ModuleArchValidation struct {
+	Name      string              `json:"name"`
+	Version   string              `json:"version"`
+	Image     string              `json:"image"`
+	Platforms []string            `json:"platforms"`
+	Supported bool                `json:"supported"`
+	Missing   []ModuleArchMissing `json:"missing,omitempty"`
+	// Unknown the nodes whose architectures are not reported yet
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+type ModuleArchMissing struct {
+	Arch  string   `json:"arch"`
+	Nodes []string `json:"nodes"`
+}
@@ -1,13 +1,168 @@
 package models
 
+import (
+	"sort"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+)
+
 // Namespace Namespace
 type Namespace struct {
 	Name string `json:"name,omitempty" binding:"namespace"`
 }
 
+// the feature flags gating the optional routes
+const (
+	FeatureFunctions = "functions"
+	FeatureObjectsV2 = "objectsV2"
+	FeatureCanary    = "canary"
+)
+
+// NamespaceSettings the settings of a namespace
+type NamespaceSettings struct {
+	// NodeLabels the labels merged into the nodes created in the namespace, the labels given by the nodes take precedence
+	NodeLabels map[string]string `json:"nodeLabels,omitempty" binding:"omitempty,label"`
+	// Features the feature flags overriding the global defaults in the namespace
+	Features map[string]bool `json:"features,omitempty"`
+	// DefaultLimits the cpu and memory limits of the services of the apps which don't set their own
+	DefaultLimits map[string]string `json:"defaultLimits,omitempty"`
+	// MaxLimits the cap of the cpu and memory limits of the services, the apps exceeding it are rejected
+	MaxLimits map[string]string `json:"maxLimits,omitempty"`
+}
+
+// NamespaceFeatures the feature flags in effect in a namespace
+type NamespaceFeatures struct {
+	Features map[string]bool `json:"features"`
+}
+
+// NamespaceSettingsResult the settings saved, along with the nodes updated by the default labels if applied to the existing nodes
+type NamespaceSettingsResult struct {
+	*NamespaceSettings `json:",inline"`
+	Updated            []string `json:"updated,omitempty"`
+	Warnings           []string `json:"warnings,omitempty"`
+}
+
+// MergeNodeLabels returns the labels merged with the default node labels and the keys whose values given differ from the defaults,
+// the given labels are not changed
+func (s *NamespaceSettings) MergeNodeLabels(labels map[string]string) (map[string]string, []string) {
+	res := make(map[string]string, len(labels)+len(s.NodeLabels))
+	for k, v := range labels {
+		res[k] = v
+	}
+	var conflicts []string
+	for k, v := range s.NodeLabels {
+		if old, ok := res[k]; ok {
+			if old != v {
+				conflicts = append(conflicts, k)
+			}
+			continue
+		}
+		res[k] = v
+	}
+	sort.Strings(conflicts)
+	return res, conflicts
+}
+
 // NamespaceList namespace list
 type NamespaceList struct {
 	Total        int `json:"total"`
 	*ListOptions `json:",inline"`
 	Items        []Namespace `json:"items"`
 }
+
+const (
+	BundleKindApplication   = "Application"
+	BundleKindConfiguration = "Configuration"
+	BundleKindSecret        = "Secret"
+	BundleKindCertificate   = "Certificate"
+	BundleKindRegistry      = "Registry"
+	BundleKindNode          = "Node"
+
+	BundleSecretPlain     = "plain"
+	BundleSecretRedacted  = "redacted"
+	BundleSecretEncrypted = "encrypted"
+
+	ConflictPolicySkip      = "skip"
+	ConflictPolicyOverwrite = "overwrite"
+	ConflictPolicyFail      = "fail"
+)
+
+// NamespaceExportParams the params of exporting a namespace
+type NamespaceExportParams struct {
+	// plain, redacted or encrypted, the data of secrets is redacted by default
+	SecretMode string `form:"secretMode" binding:"omitempty,oneof=plain redacted encrypted"`
+}
+
+// NamespaceBundleItem a document of the exported namespace bundle, certificates and registries are kept as secrets
+type NamespaceBundleItem struct {
+	Kind       string `yaml:"kind" json:"kind"`
+	SecretMode string `yaml:"secretMode,omitempty" json:"secretMode,omitempty"`
+	// the salt of the key derived from the passphrase to encrypt the secret, base64 encoded
+	Salt          string                `yaml:"salt,omitempty" json:"salt,omitempty"`
+	Application   *specV1.Application   `yaml:"application,omitempty" json:"application,omitempty"`
+	Configuration *specV1.Configuration `yaml:"configuration,omitempty" json:"configuration,omitempty"`
+	Secret        *specV1.Secret        `yaml:"secret,omitempty" json:"secret,omitempty"`
+	Node          *specV1.Node          `yaml:"node,omitempty" json:"node,omitempty"`
+}
+
+// NamespaceImport the request of importing a bundle into a namespace
+type NamespaceImport struct {
+	Bundle         string `json:"bundle" binding:"required"`
+	ConflictPolicy string `json:"conflictPolicy" default:"fail" binding:"omitempty,oneof=skip overwrite fail"`
+}
+
+// NamespaceImportResource a secret, config or app of the bundle to import, Old is the existing one to overwrite if any
+type NamespaceImportResource struct {
+	Secret        *specV1.Secret
+	Configuration *specV1.Configuration
+	Application   *specV1.Application
+	// the generated configs of the function app
+	Configs []specV1.Configuration
+	Old     interface{}
+}
+
+// NamespaceImportResult the resources of the bundle, named as kind/name
+type NamespaceImportResult struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Skipped []string `json:"skipped"`
+}
+
+// NamespaceSummary the overview of the resources in a namespace
+type NamespaceSummary struct {
+	Namespace string `json:"namespace"`
+	// Resources the numbers of the resources created by user by type, such as apps and configs
+	Resources map[string]int `json:"resources"`
+	Nodes     NodeSummary    `json:"nodes"`
+	// DeployedApps the number of the apps deployed to at least one node
+	DeployedApps int                   `json:"deployedApps"`
+	Quotas       map[string]QuotaUsage `json:"quotas"`
+}
+
+// NodeSummary the numbers of the nodes by status
+type NodeSummary struct {
+	Total     int `json:"total"`
+	Online    int `json:"online"`
+	Offline   int `json:"offline"`
+	Uninstall int `json:"uninstall"`
+}
+
+// NamespaceIsolationCheck the result of reading the resources of the namespace from a probe namespace, it passes
+// if all the reads are blocked by the storage itself, rather than by the namespace guard of the services during the check
+type NamespaceIsolationCheck struct {
+	Namespace string `json:"namespace"`
+	Probe     string `json:"probe"`
+	Passed    bool   `json:"passed"`
+	// BlockedReads the number of the reads of other namespaces blocked by the namespace guard since the start
+	BlockedReads int64                    `json:"blockedReads"`
+	Items        []NamespaceIsolationItem `json:"items"`
+}
+
+// NamespaceIsolationItem the cross-namespace reads of a resource type, the resource is skipped if the namespace has none
+type NamespaceIsolationItem struct {
+	Resource string `json:"resource"`
+	Name     string `json:"name,omitempty"`
+	Blocked  bool   `json:"blocked"`
+	Skipped  bool   `json:"skipped,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
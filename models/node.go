@@ -1,9 +1,107 @@
 package models
 
 import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+const (
+	NodeStatsEventStats   = "stats"
+	NodeStatsEventDeleted = "deleted"
+
+	NodeDrainDraining = "draining"
+	NodeDrainDrained  = "drained"
 )
 
+// NodeDetailView the view of a node along with its status by the offline threshold of the namespace
+type NodeDetailView struct {
+	*specV1.NodeView `json:",inline"`
+	Status           string           `json:"status"`
+	Drain            *NodeDrain       `json:"drain,omitempty"`
+	Maintenance      *NodeMaintenance `json:"maintenance,omitempty"`
+}
+
+// NodeDrain the drain state of a node, the drained node is unschedulable for the non-system apps.
+// It is draining until the node reports that the apps are stopped, or drained at once if forced.
+// The apps deployed to the other nodes too are kept on them rather than migrated, which are listed as skipped
+type NodeDrain struct {
+	State     string             `json:"state"`
+	Force     bool               `json:"force,omitempty"`
+	Group     string             `json:"group,omitempty"`
+	Migrated  []string           `json:"migrated,omitempty"`
+	Skipped   []string           `json:"skipped,omitempty"`
+	Failed    []NodeDrainFailure `json:"failed,omitempty"`
+	Pending   []string           `json:"pending,omitempty"`
+	StartTime time.Time          `json:"startTime"`
+}
+
+// NodeDrainFailure the app failed to migrate to the group, it is removed from the drained node still
+type NodeDrainFailure struct {
+	App     string `json:"app"`
+	Message string `json:"message"`
+}
+
+// NodeDrainParams the options to drain a node
+//   - Force: the node is drained without waiting for the apps to stop
+//   - Group: the apps deployed to the drained node only are migrated to the nodes of the group
+//     by setting the selector of the group to them
+type NodeDrainParams struct {
+	Force bool   `form:"force" json:"force"`
+	Group string `form:"group" json:"group"`
+}
+
+// GetNodeDrain returns the drain state kept in the attributes of the node, nil if it is not drained
+func GetNodeDrain(node *specV1.Node) *NodeDrain {
+	v, ok := node.Attributes[common.AttributeNodeDrain]
+	if !ok || v == nil {
+		return nil
+	}
+	// the attributes loaded from the storage are decoded as maps
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	drain := new(NodeDrain)
+	if err = json.Unmarshal(data, drain); err != nil || drain.State == "" {
+		return nil
+	}
+	return drain
+}
+
+// IsNodeUnschedulable returns whether the node is drained or draining, the non-system apps are not deployed to it
+func IsNodeUnschedulable(node *specV1.Node) bool {
+	return GetNodeDrain(node) != nil
+}
+
+// NodeMaintenance the maintenance state of a node, the offline and online alerts of the node are suppressed
+// during maintenance, and it is not counted in the node quota of the namespace if NodeMaintenance.ExcludeQuota is set
+type NodeMaintenance struct {
+	Maintenance bool      `json:"maintenance"`
+	Message     string    `json:"message,omitempty"`
+	StartTime   time.Time `json:"startTime,omitempty"`
+}
+
+// GetNodeMaintenance returns the maintenance state kept in the attributes of the node, nil if it is not under maintenance
+func GetNodeMaintenance(node *specV1.Node) *NodeMaintenance {
+	v, ok := node.Attributes[common.AttributeNodeMaintenance]
+	if !ok || v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	maintenance := new(NodeMaintenance)
+	if err = json.Unmarshal(data, maintenance); err != nil || !maintenance.Maintenance {
+		return nil
+	}
+	return maintenance
+}
+
 // NodeViewList node view list
 type NodeViewList struct {
 	Total        int `json:"total"`
@@ -22,6 +120,72 @@ type NodeNames struct {
 	Names []string `json:"names," binding:"max=20"`
 }
 
+type NodeBatchNames struct {
+	Names []string `json:"names" binding:"required,max=500"`
+}
+
+// NodeBatchGetResult the nodes found by names, and the names not found
+type NodeBatchGetResult struct {
+	Total    int               `json:"total"`
+	Items    []specV1.NodeView `json:"items"`
+	NotFound []string          `json:"notFound"`
+}
+
+type NodeDeleteResult struct {
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Warning the failure after the node is deleted, such as the node quota failed to be released
+	Warning string `json:"warning,omitempty"`
+}
+
+type NodeDeleteResultList struct {
+	Total  int                `json:"total"`
+	Failed int                `json:"failed"`
+	Items  []NodeDeleteResult `json:"items"`
+}
+
+// NodePropertiesBatch the properties updated on the nodes matching the selector
+type NodePropertiesBatch struct {
+	Selector   string         `json:"selector" binding:"required"`
+	Properties NodeProperties `json:"properties"`
+}
+
+type NodePropertiesResult struct {
+	Name    string `json:"name"`
+	Updated bool   `json:"updated"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type NodePropertiesResultList struct {
+	Total  int                    `json:"total"`
+	Failed int                    `json:"failed"`
+	Items  []NodePropertiesResult `json:"items"`
+}
+
+// NodeLabelsBatch the labels added to or removed from the nodes matching the selector
+type NodeLabelsBatch struct {
+	Selector string            `json:"selector" binding:"required"`
+	Add      map[string]string `json:"add,omitempty" binding:"omitempty,label"`
+	Remove   []string          `json:"remove,omitempty"`
+}
+
+// NodeLabelsResult the result of relabeling a node, the node whose labels are unchanged is not updated
+type NodeLabelsResult struct {
+	Name    string `json:"name"`
+	Updated bool   `json:"updated"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type NodeLabelsResultList struct {
+	Total  int                `json:"total"`
+	Failed int                `json:"failed"`
+	Items  []NodeLabelsResult `json:"items"`
+}
+
 type FunctionList struct {
 	Functions []string `json:"functions"`
 }
@@ -52,6 +216,98 @@ type NodeMode struct {
 	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
 }
 
+// NodeTags the descriptive tags of a node, which are not used to select the node unlike the labels
+type NodeTags struct {
+	Tags map[string]string `yaml:"tags" json:"tags"`
+}
+
+// NodeInitTokenStatus the status of the latest one-time token embedded in the init command of a node
+type NodeInitTokenStatus struct {
+	OneTime     bool       `yaml:"oneTime" json:"oneTime"`
+	Expired     bool       `yaml:"expired" json:"expired"`
+	Consumed    bool       `yaml:"consumed" json:"consumed"`
+	ExpireTime  *time.Time `yaml:"expireTime,omitempty" json:"expireTime,omitempty"`
+	ConsumeTime *time.Time `yaml:"consumeTime,omitempty" json:"consumeTime,omitempty"`
+}
+
+// NodeToken the token of a node, which is only allowed to access the sync endpoints of the node
+type NodeToken struct {
+	Token      string    `yaml:"token" json:"token"`
+	ExpireTime time.Time `yaml:"expireTime" json:"expireTime"`
+}
+
+// the actions of the node deploy records
+const (
+	NodeDeployActionDeploy   = "deploy"
+	NodeDeployActionUndeploy = "undeploy"
+)
+
+// NodeDeployRecord a change of the applications desired by the node, the desired spec is only returned with a single record
+type NodeDeployRecord struct {
+	ID        int64  `yaml:"id" json:"id"`
+	Namespace string `yaml:"namespace" json:"namespace"`
+	Node      string `yaml:"node" json:"node"`
+	Action    string `yaml:"action" json:"action"`
+	// App the application deployed or undeployed, its versions can be compared by the diff of the application
+	App     NodeDeployApp    `yaml:"app" json:"app"`
+	Apps    []specV1.AppInfo `yaml:"apps" json:"apps"`
+	SysApps []specV1.AppInfo `yaml:"sysapps" json:"sysapps"`
+	Desire  specV1.Desire    `yaml:"desire,omitempty" json:"desire,omitempty"`
+	Time    time.Time        `yaml:"createTime" json:"createTime"`
+}
+
+// NodeDeployApp the version of the application desired by the node before and after the deploy
+type NodeDeployApp struct {
+	Name        string `yaml:"name" json:"name"`
+	System      bool   `yaml:"system,omitempty" json:"system,omitempty"`
+	Version     string `yaml:"version,omitempty" json:"version,omitempty"`
+	PrevVersion string `yaml:"prevVersion,omitempty" json:"prevVersion,omitempty"`
+}
+
+// NodeDeployRecordList the deploy records of a node, the latest first
+type NodeDeployRecordList struct {
+	Total   int `json:"total"`
+	*Filter `json:",inline"`
+	Items   []NodeDeployRecord `json:"items"`
+}
+
+// the types of the node property records
+const (
+	NodePropertyDesire = "desire"
+	NodePropertyReport = "report"
+
+	// NodePropertyActorNode the actor of the properties reported by the node
+	NodePropertyActorNode = "node"
+)
+
+// NodePropertyRecord a change of a desired or reported property of the node, the value is nil if the property is deleted
+type NodePropertyRecord struct {
+	ID        int64       `yaml:"id" json:"id"`
+	Namespace string      `yaml:"namespace" json:"namespace"`
+	Node      string      `yaml:"node" json:"node"`
+	Property  string      `yaml:"property" json:"property"`
+	Type      string      `yaml:"type" json:"type"`
+	Value     interface{} `yaml:"value" json:"value"`
+	Actor     string      `yaml:"actor" json:"actor"`
+	Time      time.Time   `yaml:"createTime" json:"createTime"`
+}
+
+// NodePropertyRecordListOptions the filters of the node property records, the time range is in unix seconds
+type NodePropertyRecordListOptions struct {
+	Property  string `form:"property,omitempty" json:"property,omitempty"`
+	Type      string `form:"type,omitempty" json:"type,omitempty" binding:"omitempty,oneof=desire report"`
+	StartTime int64  `form:"startTime,omitempty" json:"startTime,omitempty"`
+	EndTime   int64  `form:"endTime,omitempty" json:"endTime,omitempty"`
+	Filter    `json:",inline"`
+}
+
+// NodePropertyRecordList the property records of a node, the latest first
+type NodePropertyRecordList struct {
+	Total                          int `json:"total"`
+	*NodePropertyRecordListOptions `json:",inline"`
+	Items                          []NodePropertyRecord `json:"items"`
+}
+
 type NodePropertiesMetadata struct {
 	ReportMeta map[string]interface{} `yaml:"report,omitempty" json:"report,omitempty"`
 	DesireMeta map[string]interface{} `yaml:"desire,omitempty" json:"desire,omitempty"`
@@ -87,3 +343,48 @@ type NodeSysAppInfo struct {
 	Description string            `yaml:"description,omitempty" json:"description,omitempty"`
 	Programs    map[string]string `yaml:"programs,omitempty" json:"programs,omitempty"`
 }
+
+// NodeStatsEvent the message pushed to the watchers of node stats
+type NodeStatsEvent struct {
+	Type      string           `json:"type"`
+	Node      *specV1.NodeView `json:"node,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// NodeStatsPoint the resource usage of a node at a time, summed up over the hosts of the node,
+// the cpu is in cores while the memory and the disk are in bytes
+type NodeStatsPoint struct {
+	Time     time.Time          `json:"time"`
+	Usage    map[string]float64 `json:"usage,omitempty"`
+	Capacity map[string]float64 `json:"capacity,omitempty"`
+	Percent  map[string]float64 `json:"percent,omitempty"`
+}
+
+// NodeLoad the latest utilization of a node in percent, Load is the weighted utilization of the cpu and the memory
+type NodeLoad struct {
+	Time   time.Time `json:"time"`
+	CPU    float64   `json:"cpu"`
+	Memory float64   `json:"memory"`
+	Load   float64   `json:"load"`
+}
+
+// NodeLoadView the node with its latest utilization, Stats is nil if the node reports no stats within the window
+type NodeLoadView struct {
+	specV1.NodeView `json:",inline"`
+	Stats           *NodeLoad `json:"stats"`
+}
+
+type NodeLoadViewList struct {
+	Total        int `json:"total"`
+	*ListOptions `json:",inline"`
+	Items        []NodeLoadView `json:"items"`
+}
+
+// NodeStatsHistory the points of the resource usage of a node within [from, to), averaged by step if set
+type NodeStatsHistory struct {
+	Name   string           `json:"name"`
+	From   time.Time        `json:"from"`
+	To     time.Time        `json:"to"`
+	Step   string           `json:"step,omitempty"`
+	Points []NodeStatsPoint `json:"points"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// the types of the commands dispatched to the nodes
+const (
+	NodeCommandRestartCore = "restart-core"
+	NodeCommandReboot      = "reboot"
+	NodeCommandCollectLogs = "collect-logs"
+)
+
+// the status of the commands, a command is pending until the node picks it up on its next sync,
+// then the node reports the result of it
+const (
+	NodeCommandPending    = "pending"
+	NodeCommandDispatched = "dispatched"
+	NodeCommandSucceeded  = "succeeded"
+	NodeCommandFailed     = "failed"
+	NodeCommandExpired    = "expired"
+)
+
+// ValidNodeCommand returns whether the type of command is supported
+func ValidNodeCommand(t string) bool {
+	switch t {
+	case NodeCommandRestartCore, NodeCommandReboot, NodeCommandCollectLogs:
+		return true
+	}
+	return false
+}
+
+// NodeCommand a command queued for the node
+type NodeCommand struct {
+	ID                int64             `json:"id"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Node              string            `json:"node,omitempty"`
+	Type              string            `json:"type" binding:"required"`
+	Params            map[string]string `json:"params,omitempty"`
+	Status            string            `json:"status,omitempty"`
+	Message           string            `json:"message,omitempty"`
+	CreationTimestamp time.Time         `json:"createTime,omitempty"`
+	UpdateTimestamp   time.Time         `json:"updateTime,omitempty"`
+}
+
+// NodeCommandList the commands of the node, the latest first
+type NodeCommandList struct {
+	Total   int `json:"total"`
+	*Filter `json:",inline"`
+	Items   []NodeCommand `json:"items"`
+}
+
+// NodeCommandResult the result of a command reported by the node
+type NodeCommandResult struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// NodeGroup a group of nodes defined by the label selector,
+// the membership is resolved on demand, so the nodes labeled later join automatically
+type NodeGroup struct {
+	Name              string            `json:"name,omitempty" binding:"omitempty,res_name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Selector          string            `json:"selector" binding:"required"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Description       string            `json:"description"`
+	CreationTimestamp time.Time         `json:"createTime,omitempty"`
+	UpdateTimestamp   time.Time         `json:"updateTime,omitempty"`
+}
+
+// NodeGroupList node group list
+type NodeGroupList struct {
+	Total        int `json:"total"`
+	*ListOptions `json:",inline"`
+	Items        []NodeGroup `json:"items"`
+}
+
+// NodeGroupApp the application to deploy to the nodes of group
+type NodeGroupApp struct {
+	Name string `json:"name" binding:"required"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// AppLogOptions the options to stream the logs of an app on a node
+type AppLogOptions struct {
+	// Tail the number of the latest lines to show, all lines are shown if 0
+	Tail int `json:"tail,omitempty"`
+	// Since only the logs newer than the duration are shown, all logs are shown if 0
+	Since time.Duration `json:"since,omitempty"`
+	// Follow keeps streaming the new logs until the stream is closed
+	Follow bool `json:"follow,omitempty"`
+}
@@ -83,6 +83,63 @@ type ObjectURL struct {
 	URL   string `json:"url,omitempty"`
 	MD5   string `json:"md5,omitempty"`
 	Token string `json:"token,omitempty"`
+	// Method the http method to request the url if it is not the default GET or PUT
+	Method string `json:"method,omitempty"`
+	// Headers the headers signed, which should be sent as is when requesting the url
+	Headers map[string]string `json:"headers,omitempty"`
+	// Fields the form fields of the signed POST policy, which should be posted along with the file
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+const (
+	ObjectUploadAccept     = "accept"
+	ObjectUploadReject     = "reject"
+	ObjectUploadQuarantine = "quarantine"
+)
+
+// ObjectUpload the object uploaded or going to be uploaded to the internal storage,
+// ContentType and MaxSize are the constraints of the url if signed with options
+type ObjectUpload struct {
+	UserID      string `json:"-"`
+	Source      string `json:"source,omitempty"`
+	Bucket      string `json:"bucket,omitempty"`
+	Object      string `json:"object,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	MaxSize     int64  `json:"maxSize,omitempty"`
+}
+
+// ObjectUploadVerdict the verdict of the object uploaded, the object is removed if rejected
+// and moved to the quarantine location if quarantined
+type ObjectUploadVerdict struct {
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+	// Location the object name after quarantined
+	Location string `json:"location,omitempty"`
+}
+
+// ObjectURLOptions the options of the signed url of an object,
+// ContentDisposition is for downloads while ContentType and MaxSize are for uploads
+type ObjectURLOptions struct {
+	// Expire the expiry in seconds, the default of the source is used if 0
+	Expire             int64  `form:"expire,omitempty"`
+	ContentDisposition string `form:"contentDisposition,omitempty"`
+	ContentType        string `form:"contentType,omitempty"`
+	// MaxSize the max size in bytes of the object uploaded
+	MaxSize int64 `form:"maxSize,omitempty"`
+}
+
+// BucketLifecycle the lifecycle rules of a bucket, the bucket has no rule if they are empty
+type BucketLifecycle struct {
+	Rules []BucketLifecycleRule `json:"rules" binding:"dive"`
+}
+
+// BucketLifecycleRule expires the objects whose names have the prefix after the days since they are uploaded,
+// all objects of the bucket are matched if the prefix is empty
+type BucketLifecycleRule struct {
+	ID             string `json:"id" binding:"required,max=255"`
+	Prefix         string `json:"prefix,omitempty"`
+	Enabled        bool   `json:"enabled"`
+	ExpirationDays int64  `json:"expirationDays" binding:"min=1"`
 }
 
 type ObjectRequestParams struct {
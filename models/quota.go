@@ -7,3 +7,19 @@ type Quota struct {
 	Quota     int    `json:"quota" default:"0"`
 	UsedNum   int    `json:"usedNum" default:"0"`
 }
+
+// QuotaUsage the usage and limit of a quota, the limit 0 means unlimited
+type QuotaUsage struct {
+	Limit int `json:"limit"`
+	Used  int `json:"used"`
+}
+
+// LicenseStatus the status of the license and the usages of the namespace against the limits of the license,
+// Overages are the resources used up or over the limits
+type LicenseStatus struct {
+	Mode     string                `json:"mode"`
+	Valid    bool                  `json:"valid"`
+	Message  string                `json:"message,omitempty"`
+	Limits   map[string]QuotaUsage `json:"limits"`
+	Overages []string              `json:"overages"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// ReadOnlyMode the read-only switch of the admin server, the requests except GET are rejected if it is on
+type ReadOnlyMode struct {
+	ReadOnly bool `json:"readOnly"`
+	// Message tells the users why the service is read-only, e.g. the maintenance window
+	Message string `json:"message,omitempty"`
+}
@@ -20,6 +20,28 @@ type Registry struct {
 	UpdateTimestamp   time.Time `json:"updateTime,omitempty"`
 	Description       string    `json:"description"`
 	Version           string    `json:"version,omitempty"`
+	// LastRefresh the last refresh of the password, only returned when getting a registry
+	LastRefresh *RegistryRefresh `json:"lastRefresh,omitempty"`
+}
+
+// the results of refreshing the password of a registry
+const (
+	RegistryRefreshSucceeded = "succeeded"
+	// RegistryRefreshTemporaryFailure the refresh failed transiently and can be retried later
+	RegistryRefreshTemporaryFailure = "temporaryFailure"
+	// RegistryRefreshAuthFailure the registry rejected the new credentials
+	RegistryRefreshAuthFailure = "authFailure"
+	RegistryRefreshFailed      = "failed"
+)
+
+// RegistryRefresh the result of the last refresh of the password of a registry
+type RegistryRefresh struct {
+	Namespace string    `json:"-"`
+	Name      string    `json:"-"`
+	Result    string    `json:"result"`
+	Message   string    `json:"message,omitempty"`
+	Attempts  int       `json:"attempts"`
+	Time      time.Time `json:"time"`
 }
 
 type RegistryView struct {
@@ -28,6 +50,16 @@ type RegistryView struct {
 	Username string `json:"username,omitempty"`
 }
 
+// RegistryVerification the result of connecting to the registry with the stored credentials
+type RegistryVerification struct {
+	Name          string `json:"name"`
+	Address       string `json:"address"`
+	Reachable     bool   `json:"reachable"`
+	Authenticated bool   `json:"authenticated"`
+	StatusCode    int    `json:"statusCode,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
 // RegistryList Registry List
 type RegistryList struct {
 	Total        int `json:"total"`
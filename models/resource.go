@@ -1,9 +1,27 @@
 // Package models 模型定义
 package models
 
+import "time"
+
 type ResourceList struct {
 	Total        int `json:"total"`
 	*ListOptions `json:",inline"`
 	Items        []string `json:"items"`
 	SysItems     []string `json:"sysItems"`
 }
+
+// UnusedResource a config or secret not referenced by any application
+type UnusedResource struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Description string            `json:"description,omitempty"`
+	UpdateTime  time.Time         `json:"updateTime"`
+}
+
+// UnusedResourceList the unused resources ordered by name, the total is the number of all unused ones
+type UnusedResourceList struct {
+	Total        int `json:"total"`
+	*ListOptions `json:",inline"`
+	Items        []UnusedResource `json:"items"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// the kinds of the resources whose versions are kept
+const (
+	ResourceVersionConfig = "config"
+	ResourceVersionSecret = "secret"
+)
+
+// ResourceVersion a version kept in the history of a config or secret, the content is the resource in json
+type ResourceVersion struct {
+	Namespace  string    `json:"namespace"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	Version    string    `json:"version"`
+	Content    string    `json:"-"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// ResourceVersionList the kept versions of a config or secret, the latest first
+type ResourceVersionList struct {
+	Total int               `json:"total"`
+	Items []ResourceVersion `json:"items"`
+}
@@ -0,0 +1,31 @@
+package models
+
+const (
+	SearchTypeApp         = "app"
+	SearchTypeConfig      = "config"
+	SearchTypeSecret      = "secret"
+	SearchTypeCertificate = "certificate"
+	SearchTypeRegistry    = "registry"
+	SearchTypeNode        = "node"
+)
+
+// SearchParams the params of searching the resources of all types by name and description
+type SearchParams struct {
+	Query string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1"`
+}
+
+// SearchResult a resource matched, the more relevant the lower the score
+type SearchResult struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Score       int    `json:"score"`
+}
+
+// SearchResultList the results ordered by relevance, the total and counts are of all results before the limit
+type SearchResultList struct {
+	Total  int            `json:"total"`
+	Counts map[string]int `json:"counts"`
+	Items  []SearchResult `json:"items"`
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/baetyl/baetyl-go/v2/json"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/jinzhu/copier"
 )
@@ -88,6 +89,13 @@ func FromSecretToCertificate(s *specV1.Secret, needToFilter bool) *Certificate {
 	if v, ok := s.Data["fingerPrint"]; ok {
 		res.FingerPrint = string(v)
 	}
+	if v, ok := s.Data["certId"]; ok {
+		res.CertId = string(v)
+	}
+	if v, ok := s.Data["deprecatedCerts"]; ok {
+		// malformed records are ignored, they only delay the clean up of deprecated certificates
+		_ = json.Unmarshal(v, &res.DeprecatedCerts)
+	}
 	return res
 }
 
@@ -180,3 +188,9 @@ func FromSecretListToView(s *SecretList, needToFilter bool) *SecretViewList {
 	}
 	return res
 }
+
+// SecretEncryption the number of the plaintext secrets encrypted in place
+type SecretEncryption struct {
+	Total      int            `json:"total"`
+	Namespaces map[string]int `json:"namespaces"`
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// the types of the events delivered to the webhooks, the changes made through the api are typed as <kind>.<created|updated|deleted>
+const (
+	WebhookEventAll          = "*"
+	WebhookEventNodeOffline  = "node.offline"
+	WebhookEventNodeOnline   = "node.online"
+	WebhookEventCertExpiring = "cert.expiring"
+	WebhookEventTest         = "webhook.test"
+
+	WebhookActionCreated = "created"
+	WebhookActionUpdated = "updated"
+	WebhookActionDeleted = "deleted"
+)
+
+// WebhookResourceKinds the kinds in the event types of the resources changed through the api, by the resources of the routes
+var WebhookResourceKinds = map[string]string{
+	"apps":         "app",
+	"configs":      "config",
+	"secrets":      "secret",
+	"certificates": "cert",
+	"registries":   "registry",
+	"nodes":        "node",
+	"nodegroups":   "nodegroup",
+	"apptemplates": "apptemplate",
+}
+
+// ValidWebhookEvent returns whether the webhooks can subscribe to the type of events
+func ValidWebhookEvent(eventType string) bool {
+	switch eventType {
+	case WebhookEventAll, WebhookEventNodeOffline, WebhookEventNodeOnline, WebhookEventCertExpiring, WebhookEventTest:
+		return true
+	}
+	parts := strings.Split(eventType, ".")
+	if len(parts) != 2 {
+		return false
+	}
+	switch parts[1] {
+	case WebhookActionCreated, WebhookActionUpdated, WebhookActionDeleted:
+	default:
+		return false
+	}
+	for _, kind := range WebhookResourceKinds {
+		if kind == parts[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook a subscription posting the events of the namespace to the url, the payloads are signed by the secret with HMAC-SHA256
+type Webhook struct {
+	Name              string    `json:"name,omitempty" binding:"omitempty,res_name"`
+	Namespace         string    `json:"namespace,omitempty"`
+	URL               string    `json:"url" binding:"required,url"`
+	Secret            string    `json:"secret,omitempty"`
+	Events            []string  `json:"events" binding:"required,min=1"`
+	Description       string    `json:"description"`
+	CreationTimestamp time.Time `json:"createTime,omitempty"`
+	UpdateTimestamp   time.Time `json:"updateTime,omitempty"`
+}
+
+// WebhookList webhook list
+type WebhookList struct {
+	Total        int `json:"total"`
+	*ListOptions `json:",inline"`
+	Items        []Webhook `json:"items"`
+}
+
+// Subscribes returns whether the webhook subscribes to the type of events
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, e := range w.Events {
+		if e == eventType || e == WebhookEventAll {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload the json body posted to the webhooks
+type WebhookPayload struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	Namespace string    `json:"namespace"`
+	Kind      string    `json:"kind,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookDeadLetter a payload failed to be delivered to the webhook after all attempts
+type WebhookDeadLetter struct {
+	ID        int64     `json:"id"`
+	Namespace string    `json:"namespace"`
+	Webhook   string    `json:"webhook"`
+	Event     string    `json:"event"`
+	Payload   string    `json:"payload"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookDeadLetterList the dead letters of a webhook, the latest first
+type WebhookDeadLetterList struct {
+	Total   int `json:"total"`
+	*Filter `json:",inline"`
+	Items   []WebhookDeadLetter `json:"items"`
+}
+
+// WebhookDelivery the result of posting a payload to the webhook
+type WebhookDelivery struct {
+	Payload    *WebhookPayload `json:"payload"`
+	StatusCode int             `json:"statusCode,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
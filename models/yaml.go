@@ -4,3 +4,32 @@ type YamlResourceList struct {
 	Total int           `json:"total"`
 	Items []interface{} `json:"items"`
 }
+
+const (
+	YamlApplyCreated = "created"
+	YamlApplyUpdated = "updated"
+	YamlApplyPruned  = "pruned"
+	YamlApplyFailed  = "failed"
+)
+
+// YamlApplyParams the parameters of applying yaml resources,
+// the resources labeled with Label but not in the applied yaml are deleted if Prune is true
+type YamlApplyParams struct {
+	Prune bool   `form:"prune" json:"prune"`
+	Label string `form:"label" json:"label"`
+}
+
+// YamlApplyResult the result of applying a document of the yaml stream
+type YamlApplyResult struct {
+	Index  int    `json:"index"`
+	Line   int    `json:"line,omitempty"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+type YamlApplyResultList struct {
+	Total int               `json:"total"`
+	Items []YamlApplyResult `json:"items"`
+}
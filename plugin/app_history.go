@@ -0,0 +1,54 @@
+package plugin
+
+import (
+	"io"
+	"time"
+
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/app_history.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppHistory
+
+// AppHistory keeps every version of an application, append only,
+// the soft deleted applications until they are restored or purged,
+// the canaries of the application updates until they are promoted or aborted,
+// the deploy records of the nodes, the changes of the node properties,
+// and the versions of the configs and secrets
+type AppHistory interface {
+	CreateApplicationHis(tx interface{}, application *v1.Application) (*v1.Application, error)
+	GetApplicationHis(tx interface{}, namespace, name, version string) (*v1.Application, error)
+	DeleteApplicationHis(tx interface{}, namespace, name string) error
+
+	// CreateApplicationTrash replaces the application of the same name in the trash
+	CreateApplicationTrash(tx interface{}, application *v1.Application, expireTime time.Time) (*models.AppTrash, error)
+	GetApplicationTrash(tx interface{}, namespace, name string) (*models.AppTrash, error)
+	ListApplicationTrash(tx interface{}, namespace string) ([]models.AppTrash, error)
+	// ListExpiredApplicationTrash lists the applications of all namespaces expired before the time
+	ListExpiredApplicationTrash(tx interface{}, before time.Time, limit int) ([]models.AppTrash, error)
+	DeleteApplicationTrash(tx interface{}, namespace, name string) error
+
+	// SaveApplicationCanary replaces the canary of the application
+	SaveApplicationCanary(tx interface{}, canary *models.AppCanary) (*models.AppCanary, error)
+	GetApplicationCanary(tx interface{}, namespace, name string) (*models.AppCanary, error)
+	DeleteApplicationCanary(tx interface{}, namespace, name string) error
+
+	CreateNodeDeployRecords(tx interface{}, records []*models.NodeDeployRecord) error
+	// ListNodeDeployRecord lists the records of the node without the desired specs, the latest first
+	ListNodeDeployRecord(tx interface{}, namespace, node string, filter *models.Filter) (*models.NodeDeployRecordList, error)
+	GetNodeDeployRecord(tx interface{}, namespace, node string, id int64) (*models.NodeDeployRecord, error)
+
+	CreateNodePropertyRecords(tx interface{}, records []*models.NodePropertyRecord) error
+	// ListNodePropertyRecord lists the records of the node matching the filters, the latest first
+	ListNodePropertyRecord(tx interface{}, namespace, node string, params *models.NodePropertyRecordListOptions) (*models.NodePropertyRecordList, error)
+
+	// CreateResourceVersion records a version of the config or secret, a version already recorded is kept as is
+	CreateResourceVersion(tx interface{}, version *models.ResourceVersion) error
+	GetResourceVersion(tx interface{}, namespace, kind, name, version string) (*models.ResourceVersion, error)
+	// ListResourceVersion lists the versions of the config or secret without the contents, the latest first
+	ListResourceVersion(tx interface{}, namespace, kind, name string) ([]models.ResourceVersion, error)
+	// DeleteResourceVersion deletes the versions of the config or secret except the latest keep ones, all if keep is 0
+	DeleteResourceVersion(tx interface{}, namespace, kind, name string, keep int) error
+	io.Closer
+}
@@ -0,0 +1,23 @@
+package plugin
+
+import (
+	"io"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/app_schedule.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppSchedule
+
+// AppSchedule stores the scheduled deploys of the applications
+type AppSchedule interface {
+	CreateAppSchedule(tx interface{}, schedule *models.AppSchedule) (*models.AppSchedule, error)
+	// GetAppSchedule gets the latest scheduled deploy of the application
+	GetAppSchedule(tx interface{}, namespace, name string) (*models.AppSchedule, error)
+	// ListDueAppSchedule lists the pending deploys due before the time, the earliest first
+	ListDueAppSchedule(tx interface{}, before time.Time, limit int) ([]models.AppSchedule, error)
+	// UpdateAppScheduleStatus updates the status of the deploy only if its status is still the old one,
+	// returns false if it isn't, so a deploy is claimed by one replica only
+	UpdateAppScheduleStatus(tx interface{}, id int64, oldStatus, status, message string) (bool, error)
+	io.Closer
+}
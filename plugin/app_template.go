@@ -0,0 +1,19 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/app_template.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppTemplate
+
+// AppTemplate stores the parameterized applications
+type AppTemplate interface {
+	GetAppTemplate(tx interface{}, namespace, name string) (*models.AppTemplate, error)
+	ListAppTemplate(tx interface{}, namespace string, listOptions *models.ListOptions) (*models.AppTemplateList, error)
+	CreateAppTemplate(tx interface{}, template *models.AppTemplate) (*models.AppTemplate, error)
+	UpdateAppTemplate(tx interface{}, template *models.AppTemplate) (*models.AppTemplate, error)
+	DeleteAppTemplate(tx interface{}, namespace, name string) error
+	io.Closer
+}
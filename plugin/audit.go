@@ -0,0 +1,16 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/audit.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AuditSink
+
+// AuditSink persists the audit records of the mutating api calls
+type AuditSink interface {
+	CreateAudit(tx interface{}, audit *models.Audit) error
+	ListAudit(tx interface{}, namespace string, params *models.AuditListOptions) (*models.AuditList, error)
+	io.Closer
+}
@@ -3,6 +3,7 @@ package plugin
 import (
 	"errors"
 	"io"
+	"time"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 )
@@ -17,6 +18,13 @@ type Auth interface {
 	io.Closer
 }
 
+// CacheableAuth is implemented by the auth plugins of which the results of Authenticate only depend on the token
+// of the Authorization header, the results of the other plugins are never cached
+type CacheableAuth interface {
+	// TokenExpiry returns when the token of the authenticated request expires, zero if it never expires
+	TokenExpiry(c *common.Context) time.Time
+}
+
 const (
 	PermissionRead = "READ"
 	PermissionFull = "FULL_CONTROL"
@@ -29,6 +37,7 @@ const (
 	PermissionResourceDevice      = "device"
 	PermissionResourceDeviceModel = "devicemodel"
 	PermissionResourceDriver      = "driver"
+	PermissionResourceSystem      = "system"
 )
 
 var (
@@ -2,13 +2,18 @@ package awss3
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
@@ -267,3 +272,41 @@ func TestCheckResourceNotFound(t *testing.T) {
 	err = errors.New("RequestError")
 	assert.False(t, checkResourceNotFound(err))
 }
+
+func TestSignObjectURLWithOptions(t *testing.T) {
+	s, err := newS3Session("http://127.0.0.1:9000", "ak", "sk", "", "pathStyle")
+	assert.NoError(t, err)
+	cli := s3.New(s)
+
+	res, err := signObjectURL(cli, "bucket", "a/b.zip", &models.ObjectURLOptions{Expire: 600, ContentDisposition: "attachment"}, time.Hour)
+	assert.NoError(t, err)
+	assert.Contains(t, res.URL, "http://127.0.0.1:9000/bucket/a/b.zip?")
+	assert.Contains(t, res.URL, "X-Amz-Expires=600")
+	assert.Contains(t, res.URL, "response-content-disposition=attachment")
+
+	res, err = signPutObjectURL(cli, "bucket", "a/b.zip", &models.ObjectURLOptions{ContentType: "application/zip"}, time.Hour)
+	assert.NoError(t, err)
+	assert.Contains(t, res.URL, "X-Amz-Expires=3600")
+	assert.Contains(t, res.URL, "content-type")
+	assert.Equal(t, map[string]string{"Content-Type": "application/zip"}, res.Headers)
+
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	res, err = signPostPolicy(cli, "bucket", "a/b.zip", &models.ObjectURLOptions{ContentType: "application/zip", MaxSize: 1024}, time.Hour, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:9000/bucket", res.URL)
+	assert.Equal(t, http.MethodPost, res.Method)
+	assert.Equal(t, "a/b.zip", res.Fields["key"])
+	assert.Equal(t, "application/zip", res.Fields["Content-Type"])
+	assert.Equal(t, "ak/20210102/us-east-1/s3/aws4_request", res.Fields["x-amz-credential"])
+	assert.Equal(t, "20210102T030405Z", res.Fields["x-amz-date"])
+	policy, err := base64.StdEncoding.DecodeString(res.Fields["policy"])
+	assert.NoError(t, err)
+	assert.Contains(t, string(policy), `"expiration":"2021-01-02T04:04:05.000Z"`)
+	assert.Contains(t, string(policy), `["content-length-range",0,1024]`)
+
+	key := hmacSHA256([]byte("AWS4sk"), "20210102")
+	key = hmacSHA256(key, "us-east-1")
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	assert.Equal(t, hex.EncodeToString(hmacSHA256(key, res.Fields["policy"])), res.Fields["x-amz-signature"])
+}
@@ -0,0 +1,133 @@
+package awss3
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const errNoSuchLifecycleConfiguration = "NoSuchLifecycleConfiguration"
+
+// GetInternalBucketLifecycle GetInternalBucketLifecycle
+func (c *awss3Storage) GetInternalBucketLifecycle(_, bucket string) (*models.BucketLifecycle, error) {
+	err := c.checkInternalSupported()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return getBucketLifecycle(c.s3Client, bucket)
+}
+
+// PutInternalBucketLifecycle PutInternalBucketLifecycle
+func (c *awss3Storage) PutInternalBucketLifecycle(_, bucket string, lifecycle *models.BucketLifecycle) error {
+	err := c.checkInternalSupported()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return putBucketLifecycle(c.s3Client, bucket, lifecycle)
+}
+
+// GetExternalBucketLifecycle GetExternalBucketLifecycle
+func (c *awss3Storage) GetExternalBucketLifecycle(info models.ExternalObjectInfo, bucket string) (*models.BucketLifecycle, error) {
+	cli, _, err := newS3(info)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return getBucketLifecycle(cli, bucket)
+}
+
+// PutExternalBucketLifecycle PutExternalBucketLifecycle
+func (c *awss3Storage) PutExternalBucketLifecycle(info models.ExternalObjectInfo, bucket string, lifecycle *models.BucketLifecycle) error {
+	cli, _, err := newS3(info)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return putBucketLifecycle(cli, bucket, lifecycle)
+}
+
+func getBucketLifecycle(cli *s3.S3, bucket string) (*models.BucketLifecycle, error) {
+	if err := headBucket(cli, bucket); err != nil {
+		return nil, errors.Trace(err)
+	}
+	out, err := cli.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		// the bucket without any rule
+		if e, ok := err.(awserr.Error); ok && e.Code() == errNoSuchLifecycleConfiguration {
+			return &models.BucketLifecycle{Rules: []models.BucketLifecycleRule{}}, nil
+		}
+		return nil, lifecycleError(err)
+	}
+	return fromS3LifecycleRules(out.Rules), nil
+}
+
+func putBucketLifecycle(cli *s3.S3, bucket string, lifecycle *models.BucketLifecycle) error {
+	if err := headBucket(cli, bucket); err != nil {
+		return errors.Trace(err)
+	}
+	var err error
+	if len(lifecycle.Rules) == 0 {
+		_, err = cli.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{
+			Bucket: aws.String(bucket),
+		})
+	} else {
+		_, err = cli.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+			Bucket:                 aws.String(bucket),
+			LifecycleConfiguration: &s3.BucketLifecycleConfiguration{Rules: toS3LifecycleRules(lifecycle)},
+		})
+	}
+	if err != nil {
+		return lifecycleError(err)
+	}
+	return nil
+}
+
+// lifecycleError reports the endpoints not implementing the lifecycle api as ErrNotSupported
+func lifecycleError(err error) error {
+	if e, ok := err.(awserr.Error); ok && e.Code() == "NotImplemented" {
+		return common.Error(common.ErrNotSupported, common.Field("operation", "bucket lifecycle"), common.Field("source", "awss3"))
+	}
+	return common.Error(common.ErrObjectOperationException, common.Field("error", err.Error()), common.Field("source", "awss3"))
+}
+
+// fromS3LifecycleRules keeps the rules expiring the objects by days, the others can't be shown as the rules of the model
+func fromS3LifecycleRules(rules []*s3.LifecycleRule) *models.BucketLifecycle {
+	res := &models.BucketLifecycle{Rules: []models.BucketLifecycleRule{}}
+	for _, r := range rules {
+		if r.Expiration == nil || r.Expiration.Days == nil {
+			continue
+		}
+		prefix := aws.StringValue(r.Prefix)
+		if r.Filter != nil && r.Filter.Prefix != nil {
+			prefix = aws.StringValue(r.Filter.Prefix)
+		}
+		res.Rules = append(res.Rules, models.BucketLifecycleRule{
+			ID:             aws.StringValue(r.ID),
+			Prefix:         prefix,
+			Enabled:        aws.StringValue(r.Status) == s3.ExpirationStatusEnabled,
+			ExpirationDays: aws.Int64Value(r.Expiration.Days),
+		})
+	}
+	return res
+}
+
+func toS3LifecycleRules(lifecycle *models.BucketLifecycle) []*s3.LifecycleRule {
+	var rules []*s3.LifecycleRule
+	for _, r := range lifecycle.Rules {
+		status := s3.ExpirationStatusDisabled
+		if r.Enabled {
+			status = s3.ExpirationStatusEnabled
+		}
+		rules = append(rules, &s3.LifecycleRule{
+			ID:         aws.String(r.ID),
+			Status:     aws.String(status),
+			Filter:     &s3.LifecycleRuleFilter{Prefix: aws.String(r.Prefix)},
+			Expiration: &s3.LifecycleExpiration{Days: aws.Int64(r.ExpirationDays)},
+		})
+	}
+	return rules
+}
@@ -0,0 +1,90 @@
+package awss3
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestBucketLifecycle(t *testing.T) {
+	var lifecycle string
+	var body []byte
+	notImplemented := false
+	s3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bucket1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if notImplemented && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusNotImplemented)
+			io.WriteString(w, `<Error><Code>NotImplemented</Code><Message>not implemented</Message></Error>`)
+			return
+		}
+		switch r.Method {
+		case http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			if lifecycle == "" {
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `<Error><Code>NoSuchLifecycleConfiguration</Code><Message>The lifecycle configuration does not exist</Message></Error>`)
+				return
+			}
+			io.WriteString(w, lifecycle)
+		case http.MethodPut:
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			body = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer s3.Close()
+
+	c := &awss3Storage{}
+	info := models.ExternalObjectInfo{Endpoint: s3.URL, Ak: "ak", Sk: "sk"}
+
+	// the bucket without any rule
+	res, err := c.GetExternalBucketLifecycle(info, "bucket1")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.BucketLifecycle{Rules: []models.BucketLifecycleRule{}}, res)
+
+	lifecycle = `<LifecycleConfiguration>
+<Rule><ID>uploads</ID><Filter><Prefix>uploads/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>30</Days></Expiration></Rule>
+<Rule><ID>legacy</ID><Prefix>logs/</Prefix><Status>Disabled</Status><Expiration><Days>7</Days></Expiration></Rule>
+<Rule><ID>date</ID><Status>Enabled</Status><Expiration><Date>2030-01-01T00:00:00Z</Date></Expiration></Rule>
+</LifecycleConfiguration>`
+	res, err = c.GetExternalBucketLifecycle(info, "bucket1")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.BucketLifecycleRule{
+		{ID: "uploads", Prefix: "uploads/", Enabled: true, ExpirationDays: 30},
+		{ID: "legacy", Prefix: "logs/", Enabled: false, ExpirationDays: 7},
+	}, res.Rules)
+
+	err = c.PutExternalBucketLifecycle(info, "bucket1", &models.BucketLifecycle{Rules: []models.BucketLifecycleRule{
+		{ID: "uploads", Prefix: "uploads/", Enabled: true, ExpirationDays: 30},
+	}})
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "<ID>uploads</ID>")
+	assert.Contains(t, string(body), "<Prefix>uploads/</Prefix>")
+	assert.Contains(t, string(body), "<Days>30</Days>")
+	assert.Contains(t, string(body), "<Status>Enabled</Status>")
+
+	// the rules are removed if empty
+	err = c.PutExternalBucketLifecycle(info, "bucket1", &models.BucketLifecycle{})
+	assert.NoError(t, err)
+	assert.Nil(t, body)
+
+	_, err = c.GetExternalBucketLifecycle(info, "bucket2")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The (bucket) resource (bucket2) is not found")
+
+	notImplemented = true
+	_, err = c.GetExternalBucketLifecycle(info, "bucket1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The operation (bucket lifecycle) is not supported by the source (awss3).")
+}
@@ -0,0 +1,171 @@
+package awss3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	signAlgorithm    = "AWS4-HMAC-SHA256"
+	policyTimeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// PresignInternalObjectURL PresignInternalObjectURL
+func (c *awss3Storage) PresignInternalObjectURL(_, bucket, name string, opts *models.ObjectURLOptions) (*models.ObjectURL, error) {
+	err := c.checkInternalSupported()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = headBucket(c.s3Client, bucket); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return signObjectURL(c.s3Client, bucket, name, opts, c.cfg.Expiration)
+}
+
+// PresignInternalPutObjectURL signs a POST policy limiting the size if the max size is given,
+// otherwise a PUT url with the content type signed
+func (c *awss3Storage) PresignInternalPutObjectURL(_, bucket, name string, opts *models.ObjectURLOptions) (*models.ObjectURL, error) {
+	err := c.checkInternalSupported()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = headBucket(c.s3Client, bucket); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if opts.MaxSize > 0 {
+		return signPostPolicy(c.s3Client, bucket, name, opts, c.cfg.Expiration, time.Now())
+	}
+	return signPutObjectURL(c.s3Client, bucket, name, opts, c.cfg.Expiration)
+}
+
+// PresignExternalObjectURL PresignExternalObjectURL
+func (c *awss3Storage) PresignExternalObjectURL(info models.ExternalObjectInfo, bucket, name string, opts *models.ObjectURLOptions) (*models.ObjectURL, error) {
+	cli, _, err := newS3(info)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = headBucket(cli, bucket); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return signObjectURL(cli, bucket, name, opts, time.Hour)
+}
+
+func signObjectURL(cli *s3.S3, bucket, name string, opts *models.ObjectURLOptions, expiration time.Duration) (*models.ObjectURL, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(name),
+	}
+	if opts.ContentDisposition != "" {
+		input.ResponseContentDisposition = aws.String(opts.ContentDisposition)
+	}
+	req, _ := cli.GetObjectRequest(input)
+	url, err := req.Presign(urlExpiration(opts, expiration))
+	if err != nil {
+		return nil, common.Error(common.ErrObjectOperationException, common.Field("error", err.Error()), common.Field("source", "awss3"))
+	}
+	return &models.ObjectURL{URL: url}, nil
+}
+
+func signPutObjectURL(cli *s3.S3, bucket, name string, opts *models.ObjectURLOptions, expiration time.Duration) (*models.ObjectURL, error) {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(name),
+	}
+	res := &models.ObjectURL{}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+		res.Headers = map[string]string{"Content-Type": opts.ContentType}
+	}
+	req, _ := cli.PutObjectRequest(input)
+	url, err := req.Presign(urlExpiration(opts, expiration))
+	if err != nil {
+		return nil, common.Error(common.ErrObjectOperationException, common.Field("error", err.Error()), common.Field("source", "awss3"))
+	}
+	res.URL = url
+	return res, nil
+}
+
+// signPostPolicy signs a browser-based upload policy of signature v4, since a presigned PUT url cannot limit the size
+func signPostPolicy(cli *s3.S3, bucket, name string, opts *models.ObjectURLOptions, expiration time.Duration, now time.Time) (*models.ObjectURL, error) {
+	creds, err := cli.Config.Credentials.Get()
+	if err != nil {
+		return nil, common.Error(common.ErrObjectOperationException, common.Field("error", err.Error()), common.Field("source", "awss3"))
+	}
+	req, _ := cli.HeadBucketRequest(&s3.HeadBucketInput{Bucket: aws.String(bucket)})
+	if err = req.Build(); err != nil {
+		return nil, common.Error(common.ErrObjectOperationException, common.Field("error", err.Error()), common.Field("source", "awss3"))
+	}
+
+	now = now.UTC()
+	date := now.Format("20060102")
+	region := aws.StringValue(cli.Config.Region)
+	fields := map[string]string{
+		"key":              name,
+		"x-amz-algorithm":  signAlgorithm,
+		"x-amz-credential": fmt.Sprintf("%s/%s/%s/s3/aws4_request", creds.AccessKeyID, date, region),
+		"x-amz-date":       now.Format("20060102T150405Z"),
+	}
+	if opts.ContentType != "" {
+		fields["Content-Type"] = opts.ContentType
+	}
+	if creds.SessionToken != "" {
+		fields["x-amz-security-token"] = creds.SessionToken
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	conditions := []interface{}{map[string]string{"bucket": bucket}}
+	for _, k := range keys {
+		conditions = append(conditions, map[string]string{k: fields[k]})
+	}
+	conditions = append(conditions, []interface{}{"content-length-range", 0, opts.MaxSize})
+	policy, err := json.Marshal(map[string]interface{}{
+		"expiration": now.Add(urlExpiration(opts, expiration)).Format(policyTimeFormat),
+		"conditions": conditions,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(policy)
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	fields["policy"] = encoded
+	fields["x-amz-signature"] = hex.EncodeToString(hmacSHA256(key, encoded))
+	return &models.ObjectURL{
+		URL:    req.HTTPRequest.URL.String(),
+		Method: http.MethodPost,
+		Fields: fields,
+	}, nil
+}
+
+func urlExpiration(opts *models.ObjectURLOptions, expiration time.Duration) time.Duration {
+	if opts.Expire > 0 {
+		return time.Duration(opts.Expire) * time.Second
+	}
+	return expiration
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
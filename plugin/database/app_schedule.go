@@ -0,0 +1,130 @@
+package database
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) CreateAppSchedule(tx interface{}, schedule *models.AppSchedule) (*models.AppSchedule, error) {
+	defer utils.Trace(d.Log.Debug, "CreateAppSchedule")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = d.CreateAppScheduleTx(transaction, schedule); err != nil {
+		return nil, err
+	}
+	return d.GetAppScheduleTx(transaction, schedule.Namespace, schedule.Name)
+}
+
+func (d *BaetylCloudDB) GetAppSchedule(tx interface{}, namespace, name string) (*models.AppSchedule, error) {
+	defer utils.Trace(d.Log.Debug, "GetAppSchedule")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetAppScheduleTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) ListDueAppSchedule(tx interface{}, before time.Time, limit int) ([]models.AppSchedule, error) {
+	defer utils.Trace(d.Log.Debug, "ListDueAppSchedule")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.ListDueAppScheduleTx(transaction, before, limit)
+}
+
+func (d *BaetylCloudDB) UpdateAppScheduleStatus(tx interface{}, id int64, oldStatus, status, message string) (bool, error) {
+	defer utils.Trace(d.Log.Debug, "UpdateAppScheduleStatus")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return false, err
+	}
+	return d.UpdateAppScheduleStatusTx(transaction, id, oldStatus, status, message)
+}
+
+func (d *BaetylCloudDB) GetAppScheduleTx(tx *sqlx.Tx, namespace, name string) (*models.AppSchedule, error) {
+	selectSQL := `
+SELECT id, namespace, name, cron, due_time, application, status, message, create_time, update_time
+FROM baetyl_app_schedule WHERE namespace=? AND name=? ORDER BY id DESC LIMIT 1
+`
+	schedules, err := d.queryAppSchedules(tx, selectSQL, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) > 0 {
+		return &schedules[0], nil
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "schedule"),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) ListDueAppScheduleTx(tx *sqlx.Tx, before time.Time, limit int) ([]models.AppSchedule, error) {
+	selectSQL := `
+SELECT id, namespace, name, cron, due_time, application, status, message, create_time, update_time
+FROM baetyl_app_schedule WHERE status=? AND due_time<=? ORDER BY due_time, id LIMIT ?
+`
+	return d.queryAppSchedules(tx, selectSQL, models.AppSchedulePending, before.UTC(), limit)
+}
+
+func (d *BaetylCloudDB) CreateAppScheduleTx(tx *sqlx.Tx, schedule *models.AppSchedule) (int64, error) {
+	insertSQL := `
+INSERT INTO baetyl_app_schedule (namespace, name, cron, due_time, application, status, message, create_time, update_time)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	s, err := entities.FromAppScheduleModel(schedule)
+	if err != nil {
+		return 0, err
+	}
+	if s.CreateTime.IsZero() {
+		s.CreateTime = time.Now()
+	}
+	res, err := d.Exec(tx, insertSQL, s.Namespace, s.Name, s.Cron, s.DueTime.UTC(), s.Application, s.Status, s.Message,
+		s.CreateTime.UTC(), s.CreateTime.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (d *BaetylCloudDB) UpdateAppScheduleStatusTx(tx *sqlx.Tx, id int64, oldStatus, status, message string) (bool, error) {
+	updateSQL := `
+UPDATE baetyl_app_schedule SET status=?, message=?, update_time=?
+WHERE id=? AND status=?
+`
+	res, err := d.Exec(tx, updateSQL, status, message, time.Now().UTC(), id, oldStatus)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func (d *BaetylCloudDB) queryAppSchedules(tx *sqlx.Tx, selectSQL string, args ...interface{}) ([]models.AppSchedule, error) {
+	var schedules []entities.AppSchedule
+	if err := d.Query(tx, selectSQL, &schedules, args...); err != nil {
+		return nil, err
+	}
+	result := make([]models.AppSchedule, 0, len(schedules))
+	for i := range schedules {
+		schedule, err := entities.ToAppScheduleModel(&schedules[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *schedule)
+	}
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	appScheduleTables = []string{
+		`
+CREATE TABLE baetyl_app_schedule
+(
+	id                integer       PRIMARY KEY AUTOINCREMENT,
+	namespace         varchar(64)   NOT NULL DEFAULT '',
+	name              varchar(128)  NOT NULL DEFAULT '',
+	cron              varchar(128)  NOT NULL DEFAULT '',
+	due_time          timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	application       text          NOT NULL,
+	status            varchar(32)   NOT NULL DEFAULT '',
+	message           varchar(1024) NOT NULL DEFAULT '',
+	create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	update_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateAppScheduleTable() {
+	for _, sql := range appScheduleTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create app schedule exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestAppSchedule(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAppScheduleTable()
+
+	_, err = db.GetAppSchedule(nil, "default", "app01")
+	assert.Error(t, err)
+
+	now := time.Now()
+	old, err := db.CreateAppSchedule(nil, &models.AppSchedule{
+		Namespace:   "default",
+		Name:        "app01",
+		DueTime:     now.Add(-time.Minute),
+		Application: &models.ApplicationView{Name: "app01", Selector: "a=b"},
+		Status:      models.AppSchedulePending,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "app01", old.Application.Name)
+	assert.Equal(t, "a=b", old.Application.Selector)
+	assert.Equal(t, now.Add(-time.Minute).Unix(), old.DueTime.Unix())
+
+	later, err := db.CreateAppSchedule(nil, &models.AppSchedule{
+		Namespace:   "default",
+		Name:        "app02",
+		Cron:        "0 2 * * 6",
+		DueTime:     now.Add(time.Hour),
+		Application: &models.ApplicationView{Name: "app02"},
+		Status:      models.AppSchedulePending,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "0 2 * * 6", later.Cron)
+
+	due, err := db.ListDueAppSchedule(nil, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, old.ID, due[0].ID)
+
+	// claimed once
+	ok, err := db.UpdateAppScheduleStatus(nil, old.ID, models.AppSchedulePending, models.AppScheduleRunning, "")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = db.UpdateAppScheduleStatus(nil, old.ID, models.AppSchedulePending, models.AppScheduleRunning, "")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	due, err = db.ListDueAppSchedule(nil, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 0)
+
+	ok, err = db.UpdateAppScheduleStatus(nil, old.ID, models.AppScheduleRunning, models.AppScheduleFailed, "app not found")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// the latest one
+	res, err := db.CreateAppSchedule(nil, &models.AppSchedule{
+		Namespace:   "default",
+		Name:        "app01",
+		DueTime:     now.Add(time.Hour),
+		Application: &models.ApplicationView{Name: "app01"},
+		Status:      models.AppSchedulePending,
+	})
+	assert.NoError(t, err)
+	assert.NotEqual(t, old.ID, res.ID)
+	res, err = db.GetAppSchedule(nil, "default", "app01")
+	assert.NoError(t, err)
+	assert.Equal(t, models.AppSchedulePending, res.Status)
+	due, err = db.ListDueAppSchedule(nil, now.Add(2*time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 2)
+	assert.Equal(t, later.ID, due[0].ID)
+}
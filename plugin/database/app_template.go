@@ -0,0 +1,152 @@
+package database
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) GetAppTemplate(tx interface{}, namespace, name string) (*models.AppTemplate, error) {
+	defer utils.Trace(d.Log.Debug, "GetAppTemplate")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetAppTemplateTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) ListAppTemplate(tx interface{}, namespace string, listOptions *models.ListOptions) (*models.AppTemplateList, error) {
+	defer utils.Trace(d.Log.Debug, "ListAppTemplate")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	templates, resLen, err := d.ListAppTemplateTx(transaction, namespace, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AppTemplateList{
+		Total:       resLen,
+		ListOptions: listOptions,
+		Items:       templates,
+	}, nil
+}
+
+func (d *BaetylCloudDB) CreateAppTemplate(tx interface{}, template *models.AppTemplate) (*models.AppTemplate, error) {
+	defer utils.Trace(d.Log.Debug, "CreateAppTemplate")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.CreateAppTemplateTx(transaction, template); err != nil {
+		return nil, err
+	}
+	return d.GetAppTemplateTx(transaction, template.Namespace, template.Name)
+}
+
+func (d *BaetylCloudDB) UpdateAppTemplate(tx interface{}, template *models.AppTemplate) (*models.AppTemplate, error) {
+	defer utils.Trace(d.Log.Debug, "UpdateAppTemplate")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.UpdateAppTemplateTx(transaction, template); err != nil {
+		return nil, err
+	}
+	return d.GetAppTemplateTx(transaction, template.Namespace, template.Name)
+}
+
+func (d *BaetylCloudDB) DeleteAppTemplate(tx interface{}, namespace, name string) error {
+	defer utils.Trace(d.Log.Debug, "DeleteAppTemplate")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.DeleteAppTemplateTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) GetAppTemplateTx(tx *sqlx.Tx, namespace, name string) (*models.AppTemplate, error) {
+	selectSQL := `
+SELECT id, namespace, name, labels, description, parameters, template, create_time, update_time
+FROM baetyl_app_template WHERE namespace=? AND name=?
+`
+	var templates []entities.AppTemplate
+	if err := d.Query(tx, selectSQL, &templates, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(templates) > 0 {
+		return entities.ToAppTemplateModel(&templates[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", common.AppTemplate),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) ListAppTemplateTx(tx *sqlx.Tx, namespace string, listOptions *models.ListOptions) ([]models.AppTemplate, int, error) {
+	selectSQL := `
+SELECT id, namespace, name, labels, description, parameters, template, create_time, update_time
+FROM baetyl_app_template WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC
+`
+	var templates []entities.AppTemplate
+	if err := d.Query(tx, selectSQL, &templates, namespace, listOptions.GetFuzzyName()); err != nil {
+		return nil, 0, err
+	}
+	result := make([]models.AppTemplate, 0)
+	for i := range templates {
+		labels := map[string]string{}
+		if err := json.Unmarshal([]byte(templates[i].Labels), &labels); err != nil {
+			return nil, 0, errors.Trace(err)
+		}
+		if ok, err := utils.IsLabelMatch(listOptions.LabelSelector, labels); err != nil || !ok {
+			continue
+		}
+		template, err := entities.ToAppTemplateModel(&templates[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, *template)
+	}
+	start, end := models.GetPagingParam(listOptions, len(result))
+	return result[start:end], len(result), nil
+}
+
+func (d *BaetylCloudDB) CreateAppTemplateTx(tx *sqlx.Tx, template *models.AppTemplate) error {
+	insertSQL := `
+INSERT INTO baetyl_app_template (namespace, name, labels, description, parameters, template)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+	t, err := entities.FromAppTemplateModel(template)
+	if err != nil {
+		return err
+	}
+	_, err = d.Exec(tx, insertSQL, t.Namespace, t.Name, t.Labels, t.Description, t.Parameters, t.Template)
+	return err
+}
+
+func (d *BaetylCloudDB) UpdateAppTemplateTx(tx *sqlx.Tx, template *models.AppTemplate) error {
+	updateSQL := `
+UPDATE baetyl_app_template SET labels=?, description=?, parameters=?, template=?, update_time=CURRENT_TIMESTAMP
+WHERE namespace=? AND name=?
+`
+	t, err := entities.FromAppTemplateModel(template)
+	if err != nil {
+		return err
+	}
+	_, err = d.Exec(tx, updateSQL, t.Labels, t.Description, t.Parameters, t.Template, t.Namespace, t.Name)
+	return err
+}
+
+func (d *BaetylCloudDB) DeleteAppTemplateTx(tx *sqlx.Tx, namespace, name string) error {
+	deleteSQL := `
+DELETE FROM baetyl_app_template WHERE namespace=? AND name=?
+`
+	_, err := d.Exec(tx, deleteSQL, namespace, name)
+	return err
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	appTemplateTables = []string{
+		`
+CREATE TABLE baetyl_app_template
+(
+	id                integer       PRIMARY KEY AUTOINCREMENT,
+    namespace         varchar(64)   NOT NULL DEFAULT '',
+    name              varchar(128)  NOT NULL DEFAULT '',
+	labels            varchar(2048) NOT NULL DEFAULT '{}',
+	description       varchar(1024) NOT NULL DEFAULT '',
+	parameters        text          NOT NULL,
+	template          text          NOT NULL,
+    create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateAppTemplateTable() {
+	for _, sql := range appTemplateTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create app template exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestAppTemplate(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAppTemplateTable()
+
+	template := &models.AppTemplate{
+		Name:        "tpl01",
+		Namespace:   "default",
+		Labels:      map[string]string{"a": "b"},
+		Description: "desc",
+		Parameters:  []models.AppTemplateParameter{{Name: "image", Required: true}, {Name: "port", Default: "80"}},
+		Template:    "name: app\nservices:\n- image: '{{ .image }}'",
+	}
+	res, err := db.CreateAppTemplate(nil, template)
+	assert.NoError(t, err)
+	assert.Equal(t, template.Name, res.Name)
+	assert.Equal(t, template.Labels, res.Labels)
+	assert.Equal(t, template.Description, res.Description)
+	assert.Equal(t, template.Parameters, res.Parameters)
+	assert.Equal(t, template.Template, res.Template)
+
+	template2 := &models.AppTemplate{
+		Name:      "tpl02",
+		Namespace: "default",
+		Labels:    map[string]string{"a": "c"},
+		Template:  "name: app",
+	}
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	_, err = db.CreateAppTemplate(tx, template2)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	list, err := db.ListAppTemplate(nil, "default", &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	list, err = db.ListAppTemplate(nil, "default", &models.ListOptions{LabelSelector: "a=c"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "tpl02", list.Items[0].Name)
+	assert.Nil(t, list.Items[0].Parameters)
+
+	template.Template = "name: app2"
+	template.Parameters = nil
+	res, err = db.UpdateAppTemplate(nil, template)
+	assert.NoError(t, err)
+	assert.Equal(t, "name: app2", res.Template)
+	assert.Nil(t, res.Parameters)
+
+	assert.NoError(t, db.DeleteAppTemplate(nil, "default", "tpl01"))
+	_, err = db.GetAppTemplate(nil, "default", "tpl01")
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrResourceNotFound, e.Code())
+}
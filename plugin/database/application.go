@@ -39,6 +39,9 @@ func (d *BaetylCloudDB) CreateApplication(tx interface{}, namespace string, appl
 }
 
 func (d *BaetylCloudDB) createAndGetApplication(tx *sqlx.Tx, ns string, in *specV1.Application, out **specV1.Application) error {
+	if err := d.checkNameConflict(tx, "baetyl_application", "application", ns, in.Name); err != nil {
+		return err
+	}
 	_, err := d.CreateApplicationTx(tx, ns, in)
 	if err != nil {
 		return err
@@ -183,23 +186,39 @@ id, namespace, name, version, type, mode, is_system, labels,
 selector, node_selector, description, services, init_services, volumes, 
 create_time, cron_status, update_time, cron_time, 
 workload, host_network, replica, job_config , ota, autoScaleCfg, preserve_updates
-FROM baetyl_application WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC
-`
+FROM baetyl_application WHERE namespace=? AND name LIKE ?`
+	args := []interface{}{namespace, listOptions.GetFuzzyName()}
+	match := func(application *entities.Application) (*models.AppItem, error) {
+		labels := map[string]string{}
+		if err := json.Unmarshal([]byte(application.Labels), &labels); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ok, err := utils.IsLabelMatch(listOptions.LabelSelector, labels); err != nil || !ok {
+			return nil, nil
+		}
+		return entities.ToAppListModel(application), nil
+	}
+	if listOptions.IsCursorPaging() {
+		result, err := queryByCursor(d, tx, selectSQL, args, listOptions,
+			func(application *entities.Application) int64 { return application.ID }, match)
+		if err != nil {
+			return nil, 0, err
+		}
+		return result, len(result), nil
+	}
 	var applications []entities.Application
-	if err := d.Query(tx, selectSQL, &applications, namespace, listOptions.GetFuzzyName()); err != nil {
+	if err := d.Query(tx, selectSQL+orderBy(listOptions), &applications, args...); err != nil {
 		return nil, 0, err
 	}
 	result := make([]models.AppItem, 0)
-	for _, application := range applications {
-		labels := map[string]string{}
-		if err := json.Unmarshal([]byte(application.Labels), &labels); err != nil {
-			return nil, 0, errors.Trace(err)
+	for i := range applications {
+		app, err := match(&applications[i])
+		if err != nil {
+			return nil, 0, err
 		}
-		if ok, err := utils.IsLabelMatch(listOptions.LabelSelector, labels); err != nil || !ok {
-			continue
+		if app != nil {
+			result = append(result, *app)
 		}
-		app := entities.ToAppListModel(&application)
-		result = append(result, *app)
 	}
 	start, end := models.GetPagingParam(listOptions, len(result))
 	return result[start:end], len(result), nil
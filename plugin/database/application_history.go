@@ -0,0 +1,545 @@
+package database
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) CreateApplicationHis(tx interface{}, application *specV1.Application) (*specV1.Application, error) {
+	defer utils.Trace(d.Log.Debug, "CreateApplicationHis")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.CreateApplicationHisTx(transaction, application)
+}
+
+func (d *BaetylCloudDB) GetApplicationHis(tx interface{}, namespace, name, version string) (*specV1.Application, error) {
+	defer utils.Trace(d.Log.Debug, "GetApplicationHis")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetApplicationHisTx(transaction, namespace, name, version)
+}
+
+func (d *BaetylCloudDB) DeleteApplicationHis(tx interface{}, namespace, name string) error {
+	defer utils.Trace(d.Log.Debug, "DeleteApplicationHis")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.DeleteApplicationHisTx(transaction, namespace, name)
+}
+
+// CreateApplicationHisTx records a version of the application, a version already recorded is kept as is
+func (d *BaetylCloudDB) CreateApplicationHisTx(tx *sqlx.Tx, application *specV1.Application) (*specV1.Application, error) {
+	his, err := d.GetApplicationHisTx(tx, application.Namespace, application.Name, application.Version)
+	if err == nil {
+		return his, nil
+	}
+	if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+		return nil, err
+	}
+
+	insertSQL := `
+INSERT INTO baetyl_application_history (namespace, name, version, content)
+VALUES (?, ?, ?, ?)
+`
+	app, err := entities.FromAppHisModel(application)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = d.Exec(tx, insertSQL, app.Namespace, app.Name, app.Version, app.Content); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+func (d *BaetylCloudDB) GetApplicationHisTx(tx *sqlx.Tx, namespace, name, version string) (*specV1.Application, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, content, create_time
+FROM baetyl_application_history WHERE namespace=? AND name=? AND version=?
+`
+	var apps []entities.ApplicationHistory
+	if err := d.Query(tx, selectSQL, &apps, namespace, name, version); err != nil {
+		return nil, err
+	}
+	if len(apps) > 0 {
+		return entities.ToAppHisModel(&apps[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "application history"),
+		common.Field("name", name),
+		common.Field("version", version))
+}
+
+func (d *BaetylCloudDB) DeleteApplicationHisTx(tx *sqlx.Tx, namespace, name string) error {
+	deleteSQL := `
+DELETE FROM baetyl_application_history WHERE namespace=? AND name=?
+`
+	_, err := d.Exec(tx, deleteSQL, namespace, name)
+	return err
+}
+
+func (d *BaetylCloudDB) CreateApplicationTrash(tx interface{}, application *specV1.Application, expireTime time.Time) (*models.AppTrash, error) {
+	defer utils.Trace(d.Log.Debug, "CreateApplicationTrash")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.CreateApplicationTrashTx(transaction, application, expireTime)
+}
+
+func (d *BaetylCloudDB) GetApplicationTrash(tx interface{}, namespace, name string) (*models.AppTrash, error) {
+	defer utils.Trace(d.Log.Debug, "GetApplicationTrash")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetApplicationTrashTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) ListApplicationTrash(tx interface{}, namespace string) ([]models.AppTrash, error) {
+	defer utils.Trace(d.Log.Debug, "ListApplicationTrash")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.ListApplicationTrashTx(transaction, namespace)
+}
+
+func (d *BaetylCloudDB) ListExpiredApplicationTrash(tx interface{}, before time.Time, limit int) ([]models.AppTrash, error) {
+	defer utils.Trace(d.Log.Debug, "ListExpiredApplicationTrash")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.ListExpiredApplicationTrashTx(transaction, before, limit)
+}
+
+func (d *BaetylCloudDB) DeleteApplicationTrash(tx interface{}, namespace, name string) error {
+	defer utils.Trace(d.Log.Debug, "DeleteApplicationTrash")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.DeleteApplicationTrashTx(transaction, namespace, name)
+}
+
+// CreateApplicationTrashTx moves the application into the trash, an app of the same name already in the trash is not overwritten
+func (d *BaetylCloudDB) CreateApplicationTrashTx(tx *sqlx.Tx, application *specV1.Application, expireTime time.Time) (*models.AppTrash, error) {
+	if _, err := d.GetApplicationTrashTx(tx, application.Namespace, application.Name); err == nil {
+		return nil, common.Error(common.ErrResourceConflict, common.Field("type", "app trash"),
+			common.Field("name", application.Name), common.Field("namespace", application.Namespace))
+	} else if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+		return nil, err
+	}
+	insertSQL := `
+INSERT INTO baetyl_application_trash (namespace, name, version, content, expire_time, create_time)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+	trash, err := entities.FromAppTrashModel(application, expireTime.UTC())
+	if err != nil {
+		return nil, err
+	}
+	trash.CreateTime = time.Now().UTC()
+	if _, err = d.Exec(tx, insertSQL, trash.Namespace, trash.Name, trash.Version, trash.Content, trash.ExpireTime, trash.CreateTime); err != nil {
+		return nil, err
+	}
+	return entities.ToAppTrashModel(trash)
+}
+
+func (d *BaetylCloudDB) GetApplicationTrashTx(tx *sqlx.Tx, namespace, name string) (*models.AppTrash, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, content, expire_time, create_time
+FROM baetyl_application_trash WHERE namespace=? AND name=?
+`
+	var trashes []entities.ApplicationTrash
+	if err := d.Query(tx, selectSQL, &trashes, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(trashes) > 0 {
+		return entities.ToAppTrashModel(&trashes[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "app trash"),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) ListApplicationTrashTx(tx *sqlx.Tx, namespace string) ([]models.AppTrash, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, content, expire_time, create_time
+FROM baetyl_application_trash WHERE namespace=? ORDER BY create_time DESC
+`
+	var trashes []entities.ApplicationTrash
+	if err := d.Query(tx, selectSQL, &trashes, namespace); err != nil {
+		return nil, err
+	}
+	return toAppTrashModels(trashes)
+}
+
+func (d *BaetylCloudDB) ListExpiredApplicationTrashTx(tx *sqlx.Tx, before time.Time, limit int) ([]models.AppTrash, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, content, expire_time, create_time
+FROM baetyl_application_trash WHERE expire_time < ? ORDER BY expire_time LIMIT ?
+`
+	var trashes []entities.ApplicationTrash
+	if err := d.Query(tx, selectSQL, &trashes, before.UTC(), limit); err != nil {
+		return nil, err
+	}
+	return toAppTrashModels(trashes)
+}
+
+func (d *BaetylCloudDB) DeleteApplicationTrashTx(tx *sqlx.Tx, namespace, name string) error {
+	deleteSQL := `
+DELETE FROM baetyl_application_trash WHERE namespace=? AND name=?
+`
+	_, err := d.Exec(tx, deleteSQL, namespace, name)
+	return err
+}
+
+func toAppTrashModels(trashes []entities.ApplicationTrash) ([]models.AppTrash, error) {
+	res := make([]models.AppTrash, 0, len(trashes))
+	for i := range trashes {
+		trash, err := entities.ToAppTrashModel(&trashes[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *trash)
+	}
+	return res, nil
+}
+
+func (d *BaetylCloudDB) SaveApplicationCanary(tx interface{}, canary *models.AppCanary) (*models.AppCanary, error) {
+	defer utils.Trace(d.Log.Debug, "SaveApplicationCanary")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.SaveApplicationCanaryTx(transaction, canary)
+}
+
+func (d *BaetylCloudDB) GetApplicationCanary(tx interface{}, namespace, name string) (*models.AppCanary, error) {
+	defer utils.Trace(d.Log.Debug, "GetApplicationCanary")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetApplicationCanaryTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) DeleteApplicationCanary(tx interface{}, namespace, name string) error {
+	defer utils.Trace(d.Log.Debug, "DeleteApplicationCanary")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.DeleteApplicationCanaryTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) SaveApplicationCanaryTx(tx *sqlx.Tx, canary *models.AppCanary) (*models.AppCanary, error) {
+	if err := d.DeleteApplicationCanaryTx(tx, canary.Namespace, canary.Name); err != nil {
+		return nil, err
+	}
+	insertSQL := `
+INSERT INTO baetyl_application_canary (namespace, name, version, stable_version, percent, nodes, create_time, update_time)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+	entity, err := entities.FromAppCanaryModel(canary)
+	if err != nil {
+		return nil, err
+	}
+	entity.UpdateTime = time.Now().UTC()
+	if entity.CreateTime.IsZero() {
+		entity.CreateTime = entity.UpdateTime
+	} else {
+		entity.CreateTime = entity.CreateTime.UTC()
+	}
+	if _, err = d.Exec(tx, insertSQL, entity.Namespace, entity.Name, entity.Version, entity.StableVersion,
+		entity.Percent, entity.Nodes, entity.CreateTime, entity.UpdateTime); err != nil {
+		return nil, err
+	}
+	return entities.ToAppCanaryModel(entity)
+}
+
+func (d *BaetylCloudDB) GetApplicationCanaryTx(tx *sqlx.Tx, namespace, name string) (*models.AppCanary, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, stable_version, percent, nodes, create_time, update_time
+FROM baetyl_application_canary WHERE namespace=? AND name=?
+`
+	var canaries []entities.ApplicationCanary
+	if err := d.Query(tx, selectSQL, &canaries, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(canaries) > 0 {
+		return entities.ToAppCanaryModel(&canaries[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "app canary"),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) DeleteApplicationCanaryTx(tx *sqlx.Tx, namespace, name string) error {
+	deleteSQL := `
+DELETE FROM baetyl_application_canary WHERE namespace=? AND name=?
+`
+	_, err := d.Exec(tx, deleteSQL, namespace, name)
+	return err
+}
+
+func (d *BaetylCloudDB) CreateNodeDeployRecords(tx interface{}, records []*models.NodeDeployRecord) error {
+	defer utils.Trace(d.Log.Debug, "CreateNodeDeployRecords")()
+	if len(records) == 0 {
+		return nil
+	}
+	create := func(transaction *sqlx.Tx) error {
+		for _, record := range records {
+			if err := d.CreateNodeDeployRecordTx(transaction, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if tx == nil {
+		return d.Transact(create)
+	}
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return create(transaction)
+}
+
+func (d *BaetylCloudDB) ListNodeDeployRecord(tx interface{}, namespace, node string, filter *models.Filter) (*models.NodeDeployRecordList, error) {
+	defer utils.Trace(d.Log.Debug, "ListNodeDeployRecord")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	records, err := d.ListNodeDeployRecordTx(transaction, namespace, node, filter)
+	if err != nil {
+		return nil, err
+	}
+	total, err := d.CountNodeDeployRecordTx(transaction, namespace, node)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NodeDeployRecordList{
+		Total:  total,
+		Filter: filter,
+		Items:  records,
+	}, nil
+}
+
+func (d *BaetylCloudDB) GetNodeDeployRecord(tx interface{}, namespace, node string, id int64) (*models.NodeDeployRecord, error) {
+	defer utils.Trace(d.Log.Debug, "GetNodeDeployRecord")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetNodeDeployRecordTx(transaction, namespace, node, id)
+}
+
+func (d *BaetylCloudDB) CreateNodeDeployRecordTx(tx *sqlx.Tx, record *models.NodeDeployRecord) error {
+	insertSQL := `
+INSERT INTO baetyl_node_deploy_history
+(namespace, node, action, app, system, version, prev_version, apps, sysapps, desire, create_time)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	entity, err := entities.FromNodeDeployRecordModel(record)
+	if err != nil {
+		return err
+	}
+	if entity.CreateTime.IsZero() {
+		entity.CreateTime = time.Now()
+	}
+	_, err = d.Exec(tx, insertSQL, entity.Namespace, entity.Node, entity.Action, entity.App, entity.System,
+		entity.Version, entity.PrevVer, entity.Apps, entity.SysApps, entity.Desire, entity.CreateTime.UTC())
+	return err
+}
+
+func (d *BaetylCloudDB) ListNodeDeployRecordTx(tx *sqlx.Tx, namespace, node string, filter *models.Filter) ([]models.NodeDeployRecord, error) {
+	selectSQL := `
+SELECT id, namespace, node, action, app, system, version, prev_version, apps, sysapps, create_time
+FROM baetyl_node_deploy_history WHERE namespace=? AND node=? ORDER BY id DESC`
+	args := []interface{}{namespace, node}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL += " LIMIT ?,?"
+		args = append(args, filter.GetLimitOffset(), filter.GetLimitNumber())
+	}
+	var records []entities.NodeDeployRecord
+	if err := d.Query(tx, selectSQL, &records, args...); err != nil {
+		return nil, err
+	}
+	result := make([]models.NodeDeployRecord, 0, len(records))
+	for i := range records {
+		record, err := entities.ToNodeDeployRecordModel(&records[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *record)
+	}
+	return result, nil
+}
+
+func (d *BaetylCloudDB) CountNodeDeployRecordTx(tx *sqlx.Tx, namespace, node string) (int, error) {
+	selectSQL := `SELECT COUNT(id) FROM baetyl_node_deploy_history WHERE namespace=? AND node=?`
+	var count []int
+	if err := d.Query(tx, selectSQL, &count, namespace, node); err != nil {
+		return 0, err
+	}
+	return count[0], nil
+}
+
+func (d *BaetylCloudDB) GetNodeDeployRecordTx(tx *sqlx.Tx, namespace, node string, id int64) (*models.NodeDeployRecord, error) {
+	selectSQL := `
+SELECT id, namespace, node, action, app, system, version, prev_version, apps, sysapps, desire, create_time
+FROM baetyl_node_deploy_history WHERE namespace=? AND node=? AND id=?
+`
+	var records []entities.NodeDeployRecord
+	if err := d.Query(tx, selectSQL, &records, namespace, node, id); err != nil {
+		return nil, err
+	}
+	if len(records) > 0 {
+		return entities.ToNodeDeployRecordModel(&records[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "node deploy record"),
+		common.Field("name", strconv.FormatInt(id, 10)),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) CreateNodePropertyRecords(tx interface{}, records []*models.NodePropertyRecord) error {
+	defer utils.Trace(d.Log.Debug, "CreateNodePropertyRecords")()
+	if len(records) == 0 {
+		return nil
+	}
+	create := func(transaction *sqlx.Tx) error {
+		for _, record := range records {
+			if err := d.CreateNodePropertyRecordTx(transaction, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if tx == nil {
+		return d.Transact(create)
+	}
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return create(transaction)
+}
+
+func (d *BaetylCloudDB) ListNodePropertyRecord(tx interface{}, namespace, node string, params *models.NodePropertyRecordListOptions) (*models.NodePropertyRecordList, error) {
+	defer utils.Trace(d.Log.Debug, "ListNodePropertyRecord")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	records, err := d.ListNodePropertyRecordTx(transaction, namespace, node, params)
+	if err != nil {
+		return nil, err
+	}
+	total, err := d.CountNodePropertyRecordTx(transaction, namespace, node, params)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NodePropertyRecordList{
+		Total:                         total,
+		NodePropertyRecordListOptions: params,
+		Items:                         records,
+	}, nil
+}
+
+func (d *BaetylCloudDB) CreateNodePropertyRecordTx(tx *sqlx.Tx, record *models.NodePropertyRecord) error {
+	insertSQL := `
+INSERT INTO baetyl_node_property_history
+(namespace, node, property, type, value, actor, create_time)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+	entity, err := entities.FromNodePropertyRecordModel(record)
+	if err != nil {
+		return err
+	}
+	if entity.CreateTime.IsZero() {
+		entity.CreateTime = time.Now()
+	}
+	_, err = d.Exec(tx, insertSQL, entity.Namespace, entity.Node, entity.Property, entity.Type,
+		entity.Value, entity.Actor, entity.CreateTime.UTC())
+	return err
+}
+
+func (d *BaetylCloudDB) ListNodePropertyRecordTx(tx *sqlx.Tx, namespace, node string, params *models.NodePropertyRecordListOptions) ([]models.NodePropertyRecord, error) {
+	selectSQL := `
+SELECT id, namespace, node, property, type, value, actor, create_time
+FROM baetyl_node_property_history WHERE namespace=? AND node=?`
+	where, args := nodePropertyConditions(namespace, node, params)
+	selectSQL += where + " ORDER BY id DESC"
+	if params.GetLimitNumber() > 0 {
+		selectSQL += " LIMIT ?,?"
+		args = append(args, params.GetLimitOffset(), params.GetLimitNumber())
+	}
+	var records []entities.NodePropertyRecord
+	if err := d.Query(tx, selectSQL, &records, args...); err != nil {
+		return nil, err
+	}
+	result := make([]models.NodePropertyRecord, 0, len(records))
+	for i := range records {
+		record, err := entities.ToNodePropertyRecordModel(&records[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *record)
+	}
+	return result, nil
+}
+
+func (d *BaetylCloudDB) CountNodePropertyRecordTx(tx *sqlx.Tx, namespace, node string, params *models.NodePropertyRecordListOptions) (int, error) {
+	selectSQL := `SELECT COUNT(id) FROM baetyl_node_property_history WHERE namespace=? AND node=?`
+	where, args := nodePropertyConditions(namespace, node, params)
+	var count []int
+	if err := d.Query(tx, selectSQL+where, &count, args...); err != nil {
+		return 0, err
+	}
+	return count[0], nil
+}
+
+func nodePropertyConditions(namespace, node string, params *models.NodePropertyRecordListOptions) (string, []interface{}) {
+	where := ""
+	args := []interface{}{namespace, node}
+	if params.Property != "" {
+		where += " AND property=?"
+		args = append(args, params.Property)
+	}
+	if params.Type != "" {
+		where += " AND type=?"
+		args = append(args, params.Type)
+	}
+	if params.StartTime > 0 {
+		where += " AND create_time>=?"
+		args = append(args, time.Unix(params.StartTime, 0).UTC())
+	}
+	if params.EndTime > 0 {
+		where += " AND create_time<=?"
+		args = append(args, time.Unix(params.EndTime, 0).UTC())
+	}
+	return where, args
+}
@@ -0,0 +1,456 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	appHisTables = []string{
+		`
+CREATE TABLE baetyl_application_history
+(
+	id          integer      PRIMARY KEY AUTOINCREMENT,
+	namespace   varchar(64)  NOT NULL DEFAULT '',
+	name        varchar(128) NOT NULL DEFAULT '',
+	version     varchar(36)  NOT NULL DEFAULT '',
+	content     text         NOT NULL,
+	create_time timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		`
+CREATE TABLE baetyl_application_trash
+(
+	id          integer      PRIMARY KEY AUTOINCREMENT,
+	namespace   varchar(64)  NOT NULL DEFAULT '',
+	name        varchar(128) NOT NULL DEFAULT '',
+	version     varchar(36)  NOT NULL DEFAULT '',
+	content     text         NOT NULL,
+	expire_time timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	create_time timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		`
+CREATE TABLE baetyl_application_canary
+(
+	id             integer      PRIMARY KEY AUTOINCREMENT,
+	namespace      varchar(64)  NOT NULL DEFAULT '',
+	name           varchar(128) NOT NULL DEFAULT '',
+	version        varchar(36)  NOT NULL DEFAULT '',
+	stable_version varchar(36)  NOT NULL DEFAULT '',
+	percent        integer      NOT NULL DEFAULT 0,
+	nodes          text         NOT NULL,
+	create_time    timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	update_time    timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		`
+CREATE TABLE baetyl_node_deploy_history
+(
+	id           integer      PRIMARY KEY AUTOINCREMENT,
+	namespace    varchar(64)  NOT NULL DEFAULT '',
+	node         varchar(128) NOT NULL DEFAULT '',
+	action       varchar(16)  NOT NULL DEFAULT '',
+	app          varchar(128) NOT NULL DEFAULT '',
+	system       integer      NOT NULL DEFAULT 0,
+	version      varchar(36)  NOT NULL DEFAULT '',
+	prev_version varchar(36)  NOT NULL DEFAULT '',
+	apps         text         NOT NULL,
+	sysapps      text         NOT NULL,
+	desire       text         NOT NULL,
+	create_time  timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		`
+CREATE TABLE baetyl_node_property_history
+(
+	id          integer      PRIMARY KEY AUTOINCREMENT,
+	namespace   varchar(64)  NOT NULL DEFAULT '',
+	node        varchar(128) NOT NULL DEFAULT '',
+	property    varchar(128) NOT NULL DEFAULT '',
+	type        varchar(16)  NOT NULL DEFAULT '',
+	value       text         NOT NULL,
+	actor       varchar(128) NOT NULL DEFAULT '',
+	create_time timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		`
+CREATE TABLE baetyl_resource_version
+(
+	id          integer      PRIMARY KEY AUTOINCREMENT,
+	namespace   varchar(64)  NOT NULL DEFAULT '',
+	kind        varchar(32)  NOT NULL DEFAULT '',
+	name        varchar(128) NOT NULL DEFAULT '',
+	version     varchar(36)  NOT NULL DEFAULT '',
+	content     text         NOT NULL,
+	create_time timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateAppHisTable() {
+	for _, sql := range appHisTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create app history exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestApplicationHistory(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAppHisTable()
+
+	app := &specV1.Application{
+		Name:      "app",
+		Namespace: "default",
+		Version:   "1",
+		Labels:    map[string]string{"a": "b"},
+		Services: []specV1.Service{{
+			Name:  "s0",
+			Image: "image:v1",
+		}},
+	}
+	res, err := db.CreateApplicationHis(nil, app)
+	assert.NoError(t, err)
+	assert.Equal(t, app, res)
+
+	res, err = db.GetApplicationHis(nil, "default", "app", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "image:v1", res.Services[0].Image)
+	assert.Equal(t, app.Labels, res.Labels)
+
+	// the recorded version is never overwritten
+	app2 := *app
+	app2.Services = []specV1.Service{{Name: "s0", Image: "image:v2"}}
+	res, err = db.CreateApplicationHis(nil, &app2)
+	assert.NoError(t, err)
+	assert.Equal(t, "image:v1", res.Services[0].Image)
+
+	app2.Version = "2"
+	_, err = db.CreateApplicationHis(nil, &app2)
+	assert.NoError(t, err)
+	res, err = db.GetApplicationHis(nil, "default", "app", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, "image:v2", res.Services[0].Image)
+
+	_, err = db.GetApplicationHis(nil, "default", "app", "3")
+	assert.Error(t, err)
+
+	err = db.DeleteApplicationHis(nil, "default", "app")
+	assert.NoError(t, err)
+	_, err = db.GetApplicationHis(nil, "default", "app", "1")
+	assert.Error(t, err)
+}
+
+func TestApplicationTrash(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAppHisTable()
+
+	app := &specV1.Application{
+		Name:        "app",
+		Namespace:   "default",
+		Version:     "1",
+		Type:        specV1.AppTypeContainer,
+		Labels:      map[string]string{"a": "b"},
+		Description: "desc",
+		Services:    []specV1.Service{{Name: "s0", Image: "image:v1"}},
+	}
+	now := time.Now()
+	res, err := db.CreateApplicationTrash(nil, app, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, app, res.Application)
+	assert.Equal(t, "desc", res.Description)
+
+	app2 := &specV1.Application{Name: "app2", Namespace: "default", Version: "3"}
+	_, err = db.CreateApplicationTrash(nil, app2, now.Add(-time.Hour))
+	assert.NoError(t, err)
+	_, err = db.CreateApplicationTrash(nil, &specV1.Application{Name: "app", Namespace: "other"}, now.Add(-time.Minute))
+	assert.NoError(t, err)
+
+	res, err = db.GetApplicationTrash(nil, "default", "app")
+	assert.NoError(t, err)
+	assert.Equal(t, app, res.Application)
+	assert.Equal(t, "b", res.Labels["a"])
+	assert.WithinDuration(t, now.Add(time.Hour), res.ExpireTime, time.Second)
+	assert.WithinDuration(t, now, res.DeleteTime, time.Second)
+	_, err = db.GetApplicationTrash(nil, "default", "app3")
+	assert.Error(t, err)
+
+	list, err := db.ListApplicationTrash(nil, "default")
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	expired, err := db.ListExpiredApplicationTrash(nil, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, expired, 2)
+	assert.Equal(t, "app2", expired[0].Name)
+	assert.Equal(t, "other", expired[1].Namespace)
+	expired, err = db.ListExpiredApplicationTrash(nil, now, 1)
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+
+	// the app in the trash is not overwritten by another of the same name
+	app.Version = "2"
+	_, err = db.CreateApplicationTrash(nil, app, now.Add(-time.Hour))
+	assert.Error(t, err)
+	list, err = db.ListApplicationTrash(nil, "default")
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	res, err = db.GetApplicationTrash(nil, "default", "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", res.Version)
+
+	err = db.DeleteApplicationTrash(nil, "default", "app")
+	assert.NoError(t, err)
+	_, err = db.GetApplicationTrash(nil, "default", "app")
+	assert.Error(t, err)
+}
+
+func TestApplicationCanary(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAppHisTable()
+
+	canary := &models.AppCanary{
+		Name:          "app",
+		Namespace:     "default",
+		Version:       "2",
+		StableVersion: "1",
+		Percent:       10,
+		Nodes:         []string{"n1"},
+	}
+	res, err := db.SaveApplicationCanary(nil, canary)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, res.Nodes)
+	assert.False(t, res.CreateTime.IsZero())
+
+	res, err = db.GetApplicationCanary(nil, "default", "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", res.Version)
+	assert.Equal(t, "1", res.StableVersion)
+	assert.Equal(t, 10, res.Percent)
+	assert.Equal(t, []string{"n1"}, res.Nodes)
+	_, err = db.GetApplicationCanary(nil, "other", "app")
+	assert.Error(t, err)
+
+	// the canary advanced replaces the old one and keeps its create time
+	res.Percent = 50
+	res.Nodes = append(res.Nodes, "n2")
+	_, err = db.SaveApplicationCanary(nil, res)
+	assert.NoError(t, err)
+	advanced, err := db.GetApplicationCanary(nil, "default", "app")
+	assert.NoError(t, err)
+	assert.Equal(t, 50, advanced.Percent)
+	assert.Equal(t, []string{"n1", "n2"}, advanced.Nodes)
+	assert.WithinDuration(t, res.CreateTime, advanced.CreateTime, time.Second)
+
+	err = db.DeleteApplicationCanary(nil, "default", "app")
+	assert.NoError(t, err)
+	_, err = db.GetApplicationCanary(nil, "default", "app")
+	assert.Error(t, err)
+}
+
+func TestNodeDeployRecord(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAppHisTable()
+
+	var records []*models.NodeDeployRecord
+	for i := 1; i <= 3; i++ {
+		records = append(records, &models.NodeDeployRecord{
+			Namespace: "default",
+			Node:      "node01",
+			Action:    models.NodeDeployActionDeploy,
+			App:       models.NodeDeployApp{Name: "app01", Version: fmt.Sprint(i + 1), PrevVersion: fmt.Sprint(i)},
+			Apps:      []specV1.AppInfo{{Name: "app01", Version: fmt.Sprint(i + 1)}},
+			SysApps:   []specV1.AppInfo{{Name: "core", Version: "1"}},
+			Desire:    specV1.Desire{"apps": []interface{}{map[string]interface{}{"name": "app01", "version": fmt.Sprint(i + 1)}}},
+			Time:      time.Now(),
+		})
+	}
+	records = append(records, &models.NodeDeployRecord{Namespace: "default", Node: "node02", Action: models.NodeDeployActionUndeploy,
+		App: models.NodeDeployApp{Name: "app01", PrevVersion: "1"}})
+	assert.NoError(t, db.CreateNodeDeployRecords(nil, records))
+	assert.NoError(t, db.CreateNodeDeployRecords(nil, nil))
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	assert.NoError(t, db.CreateNodeDeployRecords(tx, records[3:]))
+	assert.NoError(t, tx.Commit())
+
+	// the latest first, without the desire
+	list, err := db.ListNodeDeployRecord(nil, "default", "node01", &models.Filter{PageNo: 1, PageSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, list.Total)
+	assert.Len(t, list.Items, 2)
+	assert.Equal(t, "4", list.Items[0].App.Version)
+	assert.Equal(t, "3", list.Items[0].App.PrevVersion)
+	assert.Equal(t, records[2].Apps, list.Items[0].Apps)
+	assert.Equal(t, records[2].SysApps, list.Items[0].SysApps)
+	assert.Nil(t, list.Items[0].Desire)
+
+	list, err = db.ListNodeDeployRecord(nil, "default", "node02", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Equal(t, models.NodeDeployActionUndeploy, list.Items[0].Action)
+	assert.Equal(t, []specV1.AppInfo{}, list.Items[0].Apps)
+
+	list, err = db.ListNodeDeployRecord(nil, "default", "node01", &models.Filter{PageNo: 1, PageSize: 1})
+	assert.NoError(t, err)
+	record, err := db.GetNodeDeployRecord(nil, "default", "node01", list.Items[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, records[2].Desire, record.Desire)
+	assert.Equal(t, "app01", record.App.Name)
+
+	_, err = db.GetNodeDeployRecord(nil, "default", "node02", list.Items[0].ID)
+	assert.Error(t, err)
+}
+
+func TestNodePropertyRecord(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAppHisTable()
+
+	start := time.Now().Add(-time.Hour).UTC()
+	records := []*models.NodePropertyRecord{
+		{Namespace: "default", Node: "node01", Property: "a", Type: models.NodePropertyDesire, Value: "1", Actor: "user01", Time: start},
+		{Namespace: "default", Node: "node01", Property: "b", Type: models.NodePropertyDesire, Value: map[string]interface{}{"k": "v"}, Actor: "user01", Time: start},
+		{Namespace: "default", Node: "node01", Property: "a", Type: models.NodePropertyReport, Value: "1", Actor: models.NodePropertyActorNode},
+		{Namespace: "default", Node: "node01", Property: "a", Type: models.NodePropertyDesire, Actor: "user02"},
+		{Namespace: "default", Node: "node02", Property: "a", Type: models.NodePropertyDesire, Value: "2", Actor: "user01"},
+	}
+	assert.NoError(t, db.CreateNodePropertyRecords(nil, records[:4]))
+	assert.NoError(t, db.CreateNodePropertyRecords(nil, nil))
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	assert.NoError(t, db.CreateNodePropertyRecords(tx, records[4:]))
+	assert.NoError(t, tx.Commit())
+
+	// the latest first, the deleted property has a nil value
+	list, err := db.ListNodePropertyRecord(nil, "default", "node01", &models.NodePropertyRecordListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, list.Total)
+	assert.Len(t, list.Items, 4)
+	assert.Equal(t, "user02", list.Items[0].Actor)
+	assert.Nil(t, list.Items[0].Value)
+	assert.Equal(t, map[string]interface{}{"k": "v"}, list.Items[2].Value)
+
+	list, err = db.ListNodePropertyRecord(nil, "default", "node01", &models.NodePropertyRecordListOptions{
+		Property: "a", Filter: models.Filter{PageNo: 1, PageSize: 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, list.Total)
+	assert.Len(t, list.Items, 2)
+	assert.Equal(t, models.NodePropertyReport, list.Items[1].Type)
+
+	list, err = db.ListNodePropertyRecord(nil, "default", "node01", &models.NodePropertyRecordListOptions{
+		Property: "a", Type: models.NodePropertyDesire})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+
+	// the time range
+	list, err = db.ListNodePropertyRecord(nil, "default", "node01", &models.NodePropertyRecordListOptions{
+		EndTime: start.Add(time.Minute).Unix()})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Equal(t, "b", list.Items[0].Property)
+	assert.Equal(t, start.Unix(), list.Items[0].Time.UTC().Unix())
+
+	list, err = db.ListNodePropertyRecord(nil, "default", "node01", &models.NodePropertyRecordListOptions{
+		StartTime: start.Add(time.Minute).Unix()})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+
+	list, err = db.ListNodePropertyRecord(nil, "default", "node03", &models.NodePropertyRecordListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, list.Total)
+	assert.Len(t, list.Items, 0)
+}
+
+func TestResourceVersion(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAppHisTable()
+
+	for _, v := range []string{"1", "2", "3"} {
+		err = db.CreateResourceVersion(nil, &models.ResourceVersion{
+			Namespace: "default", Kind: models.ResourceVersionConfig, Name: "cfg", Version: v, Content: "content-" + v,
+		})
+		assert.NoError(t, err)
+	}
+	// the recorded version is never overwritten
+	err = db.CreateResourceVersion(nil, &models.ResourceVersion{
+		Namespace: "default", Kind: models.ResourceVersionConfig, Name: "cfg", Version: "3", Content: "changed",
+	})
+	assert.NoError(t, err)
+	err = db.CreateResourceVersion(nil, &models.ResourceVersion{
+		Namespace: "default", Kind: models.ResourceVersionSecret, Name: "cfg", Version: "1", Content: "secret",
+	})
+	assert.NoError(t, err)
+
+	res, err := db.GetResourceVersion(nil, "default", models.ResourceVersionConfig, "cfg", "3")
+	assert.NoError(t, err)
+	assert.Equal(t, "content-3", res.Content)
+	_, err = db.GetResourceVersion(nil, "default", models.ResourceVersionConfig, "cfg", "4")
+	assert.Error(t, err)
+
+	list, err := db.ListResourceVersion(nil, "default", models.ResourceVersionConfig, "cfg")
+	assert.NoError(t, err)
+	assert.Len(t, list, 3)
+	assert.Equal(t, "3", list[0].Version)
+	assert.Empty(t, list[0].Content)
+
+	assert.NoError(t, db.DeleteResourceVersion(nil, "default", models.ResourceVersionConfig, "cfg", 2))
+	list, err = db.ListResourceVersion(nil, "default", models.ResourceVersionConfig, "cfg")
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "2", list[1].Version)
+
+	// nothing is deleted if there are no more versions than kept
+	assert.NoError(t, db.DeleteResourceVersion(nil, "default", models.ResourceVersionConfig, "cfg", 5))
+	list, err = db.ListResourceVersion(nil, "default", models.ResourceVersionConfig, "cfg")
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	assert.NoError(t, db.DeleteResourceVersion(nil, "default", models.ResourceVersionConfig, "cfg", 0))
+	list, err = db.ListResourceVersion(nil, "default", models.ResourceVersionConfig, "cfg")
+	assert.NoError(t, err)
+	assert.Len(t, list, 0)
+	list, err = db.ListResourceVersion(nil, "default", models.ResourceVersionSecret, "cfg")
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) CreateAudit(tx interface{}, audit *models.Audit) error {
+	defer utils.Trace(d.Log.Debug, "CreateAudit")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.CreateAuditTx(transaction, audit)
+}
+
+func (d *BaetylCloudDB) ListAudit(tx interface{}, namespace string, params *models.AuditListOptions) (*models.AuditList, error) {
+	defer utils.Trace(d.Log.Debug, "ListAudit")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	audits, err := d.ListAuditTx(transaction, namespace, params)
+	if err != nil {
+		return nil, err
+	}
+	total, err := d.CountAuditTx(transaction, namespace, params)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuditList{
+		Total:            total,
+		AuditListOptions: params,
+		Items:            audits,
+	}, nil
+}
+
+func (d *BaetylCloudDB) CreateAuditTx(tx *sqlx.Tx, audit *models.Audit) error {
+	insertSQL := `
+INSERT INTO baetyl_audit
+(namespace, user_id, user_name, resource, name, method, path, body_hash, status, request_id, client_ip, create_time)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	a := entities.FromAuditModel(audit)
+	if a.CreateTime.IsZero() {
+		a.CreateTime = time.Now()
+	}
+	_, err := d.Exec(tx, insertSQL, a.Namespace, a.UserID, a.UserName, a.Resource, a.Name,
+		a.Method, a.Path, a.BodyHash, a.Status, a.RequestID, a.ClientIP, a.CreateTime.UTC())
+	return err
+}
+
+func (d *BaetylCloudDB) ListAuditTx(tx *sqlx.Tx, namespace string, params *models.AuditListOptions) ([]models.Audit, error) {
+	selectSQL := `
+SELECT id, namespace, user_id, user_name, resource, name, method, path, body_hash, status, request_id, client_ip, create_time
+FROM baetyl_audit WHERE namespace=?`
+	where, args := auditConditions(namespace, params)
+	selectSQL += where + " ORDER BY id DESC"
+	if params.GetLimitNumber() > 0 {
+		selectSQL += " LIMIT ?,?"
+		args = append(args, params.GetLimitOffset(), params.GetLimitNumber())
+	}
+	var audits []entities.Audit
+	if err := d.Query(tx, selectSQL, &audits, args...); err != nil {
+		return nil, err
+	}
+	result := make([]models.Audit, 0, len(audits))
+	for i := range audits {
+		result = append(result, *entities.ToAuditModel(&audits[i]))
+	}
+	return result, nil
+}
+
+func (d *BaetylCloudDB) CountAuditTx(tx *sqlx.Tx, namespace string, params *models.AuditListOptions) (int, error) {
+	selectSQL := `SELECT COUNT(id) FROM baetyl_audit WHERE namespace=?`
+	where, args := auditConditions(namespace, params)
+	var count []int
+	if err := d.Query(tx, selectSQL+where, &count, args...); err != nil {
+		return 0, err
+	}
+	return count[0], nil
+}
+
+func auditConditions(namespace string, params *models.AuditListOptions) (string, []interface{}) {
+	where := ""
+	args := []interface{}{namespace}
+	if params.Resource != "" {
+		where += " AND resource=?"
+		args = append(args, params.Resource)
+	}
+	if params.StartTime > 0 {
+		where += " AND create_time>=?"
+		args = append(args, time.Unix(params.StartTime, 0).UTC())
+	}
+	if params.EndTime > 0 {
+		where += " AND create_time<=?"
+		args = append(args, time.Unix(params.EndTime, 0).UTC())
+	}
+	return where, args
+}
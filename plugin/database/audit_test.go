@@ -0,0 +1,104 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	auditTables = []string{
+		`
+CREATE TABLE baetyl_audit
+(
+	id                integer       PRIMARY KEY AUTOINCREMENT,
+    namespace         varchar(64)   NOT NULL DEFAULT '',
+	user_id           varchar(128)  NOT NULL DEFAULT '',
+	user_name         varchar(128)  NOT NULL DEFAULT '',
+	resource          varchar(64)   NOT NULL DEFAULT '',
+    name              varchar(128)  NOT NULL DEFAULT '',
+	method            varchar(16)   NOT NULL DEFAULT '',
+	path              varchar(1024) NOT NULL DEFAULT '',
+	body_hash         varchar(64)   NOT NULL DEFAULT '',
+	status            integer       NOT NULL DEFAULT 0,
+	request_id        varchar(64)   NOT NULL DEFAULT '',
+	client_ip         varchar(64)   NOT NULL DEFAULT '',
+    create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateAuditTable() {
+	for _, sql := range auditTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create audit exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAuditTable()
+
+	now := time.Now()
+	audits := []*models.Audit{
+		{Namespace: "default", UserID: "u1", UserName: "user1", Resource: "apps", Name: "app01", Method: "POST", Path: "/v1/apps", BodyHash: "hash", Status: 200, RequestID: "r1", ClientIP: "10.0.0.1", Timestamp: now.Add(-2 * time.Hour)},
+		{Namespace: "default", UserID: "u1", UserName: "user1", Resource: "configs", Name: "cfg01", Method: "PUT", Path: "/v1/configs/cfg01", Status: 400, Timestamp: now.Add(-time.Hour)},
+		{Namespace: "default", UserID: "u2", UserName: "user2", Resource: "apps", Name: "app01", Method: "DELETE", Path: "/v1/apps/app01", Status: 200, Timestamp: now},
+		{Namespace: "other", Resource: "apps", Method: "POST", Path: "/v1/apps", Status: 200, Timestamp: now},
+	}
+	for _, a := range audits {
+		assert.NoError(t, db.CreateAudit(nil, a))
+	}
+
+	res, err := db.ListAudit(nil, "default", &models.AuditListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res.Total)
+	assert.Len(t, res.Items, 3)
+	// the latest first
+	assert.Equal(t, "DELETE", res.Items[0].Method)
+	assert.Equal(t, "POST", res.Items[2].Method)
+	assert.Equal(t, "u1", res.Items[2].UserID)
+	assert.Equal(t, "user1", res.Items[2].UserName)
+	assert.Equal(t, "app01", res.Items[2].Name)
+	assert.Equal(t, "hash", res.Items[2].BodyHash)
+	assert.Equal(t, 200, res.Items[2].Status)
+	assert.Equal(t, "r1", res.Items[2].RequestID)
+	assert.Equal(t, "10.0.0.1", res.Items[2].ClientIP)
+	assert.Equal(t, now.Add(-2*time.Hour).Unix(), res.Items[2].Timestamp.Unix())
+
+	res, err = db.ListAudit(nil, "default", &models.AuditListOptions{Resource: "apps"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Total)
+	assert.Len(t, res.Items, 2)
+
+	res, err = db.ListAudit(nil, "default", &models.AuditListOptions{StartTime: now.Add(-90 * time.Minute).Unix()})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Total)
+
+	res, err = db.ListAudit(nil, "default", &models.AuditListOptions{
+		Resource:  "apps",
+		StartTime: now.Add(-3 * time.Hour).Unix(),
+		EndTime:   now.Add(-90 * time.Minute).Unix(),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "POST", res.Items[0].Method)
+
+	res, err = db.ListAudit(nil, "default", &models.AuditListOptions{Filter: models.Filter{PageNo: 2, PageSize: 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res.Total)
+	assert.Len(t, res.Items, 1)
+	assert.Equal(t, "POST", res.Items[0].Method)
+}
@@ -42,6 +42,9 @@ func (d *BaetylCloudDB) CreateConfig(tx interface{}, namespace string, configura
 }
 
 func (d *BaetylCloudDB) createAndGetConfig(tx *sqlx.Tx, ns string, in *specV1.Configuration, out **specV1.Configuration) error {
+	if err := d.checkNameConflict(tx, "baetyl_configuration", "config", ns, in.Name); err != nil {
+		return err
+	}
 	_, err := d.CreateConfigurationTx(tx, ns, in)
 	if err != nil {
 		return err
@@ -145,26 +148,43 @@ func (d *BaetylCloudDB) ListConfigurationTx(_ *sqlx.Tx, namespace string, listOp
 	selectSQL := `
 SELECT 
 id, namespace, name, labels, data, version, is_system, description, create_time, update_time
-FROM baetyl_configuration WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC
-`
-	var configs []entities.Configuration
-	if err := d.Query(nil, selectSQL, &configs, namespace, listOptions.GetFuzzyName()); err != nil {
-		return nil, 0, err
-	}
-	result := make([]specV1.Configuration, 0)
-	for _, config := range configs {
+FROM baetyl_configuration WHERE namespace=? AND name LIKE ?`
+	args := []interface{}{namespace, listOptions.GetFuzzyName()}
+	match := func(config *entities.Configuration) (*specV1.Configuration, error) {
 		labels := map[string]string{}
 		if err := json.Unmarshal([]byte(config.Labels), &labels); err != nil {
-			return nil, 0, errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		if ok, err := utils.IsLabelMatch(listOptions.LabelSelector, labels); err != nil || !ok {
-			continue
+			return nil, nil
+		}
+		cfg, err := entities.ToConfigModel(config)
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
-		cfg, err := entities.ToConfigModel(&config)
+		return cfg, nil
+	}
+	if listOptions.IsCursorPaging() {
+		result, err := queryByCursor(d, nil, selectSQL, args, listOptions,
+			func(config *entities.Configuration) int64 { return config.ID }, match)
 		if err != nil {
-			return nil, 0, errors.Trace(err)
+			return nil, 0, err
+		}
+		return result, len(result), nil
+	}
+	var configs []entities.Configuration
+	if err := d.Query(nil, selectSQL+orderBy(listOptions), &configs, args...); err != nil {
+		return nil, 0, err
+	}
+	result := make([]specV1.Configuration, 0)
+	for i := range configs {
+		cfg, err := match(&configs[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		if cfg != nil {
+			result = append(result, *cfg)
 		}
-		result = append(result, *cfg)
 	}
 	start, end := models.GetPagingParam(listOptions, len(result))
 	return result[start:end], len(result), nil
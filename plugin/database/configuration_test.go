@@ -78,6 +78,16 @@ func TestConfiguration(t *testing.T) {
 	assert.NoError(t, err)
 	checkCfg(t, cfg, res)
 
+	// the names differing only in case are conflicted in the same namespace
+	res, err = db.CreateConfig(nil, "default", &specV1.Configuration{Name: "CFG123", Namespace: "default"})
+	assert.Nil(t, res)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cfg123")
+	res, err = db.CreateConfig(nil, "tx", &specV1.Configuration{Name: "CFG123", Namespace: "tx"})
+	assert.NoError(t, err)
+	assert.Equal(t, "CFG123", res.Name)
+	assert.NoError(t, db.DeleteConfig(nil, "tx", "CFG123"))
+
 	cfg.Labels = map[string]string{"b": "b"}
 	res, err = db.UpdateConfig(nil, "default", cfg)
 	assert.NoError(t, err)
@@ -229,6 +239,22 @@ func TestListCfg(t *testing.T) {
 	assert.Equal(t, resList.Total, 1)
 	assert.Equal(t, cfg2.Name, resList.Items[0].Name)
 
+	// sorted by name descending
+	listOptions = &models.ListOptions{SortBy: models.SortByName, Order: models.SortOrderDesc}
+	resList, err = db.ListConfig("default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, resList.Total)
+	assert.Equal(t, cfg4.Name, resList.Items[0].Name)
+	assert.Equal(t, cfg1.Name, resList.Items[3].Name)
+
+	// the configs created at the same time are sorted by name ascending
+	listOptions = &models.ListOptions{SortBy: models.SortByCreateTime, Order: models.SortOrderDesc}
+	resList, err = db.ListConfig("default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, resList.Total)
+	assert.Equal(t, cfg1.Name, resList.Items[0].Name)
+	assert.Equal(t, cfg4.Name, resList.Items[3].Name)
+
 	err = db.DeleteConfig(nil, "default", cfg1.Name)
 	assert.NoError(t, err)
 	err = db.DeleteConfig(nil, "default", cfg2.Name)
@@ -256,3 +282,73 @@ func checkCfg(t *testing.T, expect, actual *specV1.Configuration) {
 	assert.Equal(t, expect.Data, actual.Data)
 	assert.EqualValues(t, expect.Labels, actual.Labels)
 }
+
+func TestListCfgByCursor(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateCfgTable()
+
+	for i := 0; i < 5; i++ {
+		label := "aaa"
+		if i%2 == 1 {
+			label = "bbb"
+		}
+		_, err = db.CreateConfig(nil, "default", &specV1.Configuration{
+			Name:   fmt.Sprintf("cfg-%d", i),
+			Labels: map[string]string{"label": label},
+			Data:   map[string]string{"k": "v"},
+		})
+		assert.NoError(t, err)
+	}
+
+	// the lists requested by limit alone are not paged by cursor
+	resList, err := db.ListConfig("default", &models.ListOptions{Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, resList.Total)
+	assert.Empty(t, resList.NextCursor)
+
+	// first page by an empty cursor, newest first
+	listOptions := &models.ListOptions{Limit: 2, CursorPaging: true}
+	resList, err = db.ListConfig("default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resList.Total)
+	assert.Equal(t, "cfg-4", resList.Items[0].Name)
+	assert.Equal(t, "cfg-3", resList.Items[1].Name)
+	assert.NotEmpty(t, resList.NextCursor)
+
+	listOptions = &models.ListOptions{Limit: 2, Cursor: resList.NextCursor}
+	resList, err = db.ListConfig("default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, "cfg-2", resList.Items[0].Name)
+	assert.Equal(t, "cfg-1", resList.Items[1].Name)
+	assert.NotEmpty(t, resList.NextCursor)
+
+	listOptions = &models.ListOptions{Limit: 2, Cursor: resList.NextCursor}
+	resList, err = db.ListConfig("default", listOptions)
+	assert.NoError(t, err)
+	assert.Len(t, resList.Items, 1)
+	assert.Equal(t, "cfg-0", resList.Items[0].Name)
+	assert.Empty(t, resList.NextCursor)
+
+	// the page is filled by the rows matched
+	listOptions = &models.ListOptions{Limit: 2, LabelSelector: "label=aaa", CursorPaging: true}
+	resList, err = db.ListConfig("default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, "cfg-4", resList.Items[0].Name)
+	assert.Equal(t, "cfg-2", resList.Items[1].Name)
+	assert.NotEmpty(t, resList.NextCursor)
+
+	listOptions = &models.ListOptions{Limit: 2, LabelSelector: "label=aaa", Cursor: resList.NextCursor}
+	resList, err = db.ListConfig("default", listOptions)
+	assert.NoError(t, err)
+	assert.Len(t, resList.Items, 1)
+	assert.Equal(t, "cfg-0", resList.Items[0].Name)
+	assert.Empty(t, resList.NextCursor)
+
+	_, err = db.ListConfig("default", &models.ListOptions{Cursor: "invalid"})
+	assert.Error(t, err)
+}
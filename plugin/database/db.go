@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"io"
+	"math"
 	"strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
@@ -114,6 +116,11 @@ func (d *DB) Close() (err error) {
 	return
 }
 
+// Health pings the database
+func (d *DB) Health(ctx context.Context) error {
+	return errors.Trace(d.db.PingContext(ctx))
+}
+
 func (d *DB) Transact(handler func(*sqlx.Tx) error) (err error) {
 	tx, err := d.db.Beginx()
 	if err != nil {
@@ -208,3 +215,93 @@ func (d *BaetylCloudDB) InterfaceToTx(tx interface{}) (*sqlx.Tx, error) {
 	}
 	return transaction, nil
 }
+
+// sortColumns the columns of the fields the lists are sorted by
+var sortColumns = map[string]string{
+	models.SortByName:       "name",
+	models.SortByCreateTime: "create_time",
+	models.SortByUpdateTime: "update_time",
+}
+
+// orderBy returns the order clause of the list, which is sorted by name ascending if sortBy is not set,
+// and by name ascending secondarily to keep the order of the rows with the same value stable
+func orderBy(listOptions *models.ListOptions) string {
+	order := "ASC"
+	if listOptions.Order == models.SortOrderDesc {
+		order = "DESC"
+	}
+	column, ok := sortColumns[listOptions.SortBy]
+	if !ok || column == "name" {
+		return " ORDER BY name " + order
+	}
+	return " ORDER BY " + column + " " + order + ", name ASC"
+}
+
+// cursorBatchSize the number of rows queried at a time by the keyset pagination
+const cursorBatchSize = 100
+
+// queryByCursor runs the keyset pagination on selectSQL, which must end with a where clause.
+// The rows are queried in descending id order in batches after the cursor, and converted by match
+// until the page is filled, match returns nil if the row is filtered out.
+// The next cursor is set to listOptions if there are more rows.
+func queryByCursor[E any, M any](d *BaetylCloudDB, tx *sqlx.Tx, selectSQL string, args []interface{},
+	listOptions *models.ListOptions, id func(*E) int64, match func(*E) (*M, error)) ([]M, error) {
+	lastID := int64(math.MaxInt64)
+	if listOptions.Cursor != "" {
+		cursor, err := models.DecodeCursor(listOptions.Cursor)
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+		lastID = cursor
+	}
+	limit := int(listOptions.Limit)
+	batch := cursorBatchSize
+	if limit >= batch {
+		batch = limit + 1
+	}
+	query := selectSQL + " AND id < ? ORDER BY id DESC LIMIT ?"
+	result := make([]M, 0)
+	var ids []int64
+	for {
+		var rows []E
+		params := append(append([]interface{}{}, args...), lastID, batch)
+		if err := d.Query(tx, query, &rows, params...); err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			m, err := match(&rows[i])
+			if err != nil {
+				return nil, err
+			}
+			if m == nil {
+				continue
+			}
+			result = append(result, *m)
+			ids = append(ids, id(&rows[i]))
+		}
+		if len(rows) < batch || (limit > 0 && len(result) > limit) {
+			break
+		}
+		lastID = id(&rows[len(rows)-1])
+	}
+	listOptions.NextCursor = ""
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+		listOptions.NextCursor = models.EncodeCursor(ids[limit-1])
+	}
+	return result, nil
+}
+
+// checkNameConflict returns a conflict error if a resource in the table of the namespace already has the name
+// ignoring case, the names differing only in case collide on the case-insensitive filesystems of some devices
+func (d *BaetylCloudDB) checkNameConflict(tx *sqlx.Tx, table, typ, namespace, name string) error {
+	selectSQL := "SELECT name FROM " + table + " WHERE namespace=? AND LOWER(name)=LOWER(?) LIMIT 1"
+	var names []string
+	if err := d.Query(tx, selectSQL, &names, namespace, name); err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return common.Error(common.ErrResourceConflict, common.Field("type", typ), common.Field("name", names[0]))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type AppSchedule struct {
+	ID          int64     `db:"id"`
+	Namespace   string    `db:"namespace"`
+	Name        string    `db:"name"`
+	Cron        string    `db:"cron"`
+	DueTime     time.Time `db:"due_time"`
+	Application string    `db:"application"`
+	Status      string    `db:"status"`
+	Message     string    `db:"message"`
+	CreateTime  time.Time `db:"create_time"`
+	UpdateTime  time.Time `db:"update_time"`
+}
+
+func ToAppScheduleModel(schedule *AppSchedule) (*models.AppSchedule, error) {
+	var app models.ApplicationView
+	if err := json.Unmarshal([]byte(schedule.Application), &app); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.AppSchedule{
+		ID:                schedule.ID,
+		Namespace:         schedule.Namespace,
+		Name:              schedule.Name,
+		Cron:              schedule.Cron,
+		DueTime:           schedule.DueTime.UTC(),
+		Application:       &app,
+		Status:            schedule.Status,
+		Message:           schedule.Message,
+		CreationTimestamp: schedule.CreateTime.UTC(),
+		UpdateTimestamp:   schedule.UpdateTime.UTC(),
+	}, nil
+}
+
+func FromAppScheduleModel(schedule *models.AppSchedule) (*AppSchedule, error) {
+	app, err := json.Marshal(schedule.Application)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &AppSchedule{
+		Namespace:   schedule.Namespace,
+		Name:        schedule.Name,
+		Cron:        schedule.Cron,
+		DueTime:     schedule.DueTime,
+		Application: string(app),
+		Status:      schedule.Status,
+		Message:     schedule.Message,
+		CreateTime:  schedule.CreationTimestamp,
+	}, nil
+}
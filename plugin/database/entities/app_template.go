@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type AppTemplate struct {
+	ID          int64     `db:"id"`
+	Namespace   string    `db:"namespace"`
+	Name        string    `db:"name"`
+	Labels      string    `db:"labels"`
+	Description string    `db:"description"`
+	Parameters  string    `db:"parameters"`
+	Template    string    `db:"template"`
+	CreateTime  time.Time `db:"create_time"`
+	UpdateTime  time.Time `db:"update_time"`
+}
+
+func ToAppTemplateModel(template *AppTemplate) (*models.AppTemplate, error) {
+	labels := map[string]string{}
+	if err := json.Unmarshal([]byte(template.Labels), &labels); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var params []models.AppTemplateParameter
+	if err := json.Unmarshal([]byte(template.Parameters), &params); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.AppTemplate{
+		Name:              template.Name,
+		Namespace:         template.Namespace,
+		Labels:            labels,
+		Description:       template.Description,
+		Parameters:        params,
+		Template:          template.Template,
+		CreationTimestamp: template.CreateTime.UTC(),
+		UpdateTimestamp:   template.UpdateTime.UTC(),
+	}, nil
+}
+
+func FromAppTemplateModel(template *models.AppTemplate) (*AppTemplate, error) {
+	labels, err := json.Marshal(template.Labels)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	params, err := json.Marshal(template.Parameters)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &AppTemplate{
+		Name:        template.Name,
+		Namespace:   template.Namespace,
+		Labels:      string(labels),
+		Description: template.Description,
+		Parameters:  string(params),
+		Template:    template.Template,
+	}, nil
+}
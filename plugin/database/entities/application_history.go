@@ -0,0 +1,257 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type ApplicationHistory struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	Version    string    `db:"version"`
+	Content    string    `db:"content"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToAppHisModel(his *ApplicationHistory) (*specV1.Application, error) {
+	app := &specV1.Application{}
+	if err := json.Unmarshal([]byte(his.Content), app); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return app, nil
+}
+
+func FromAppHisModel(app *specV1.Application) (*ApplicationHistory, error) {
+	content, err := json.Marshal(app)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ApplicationHistory{
+		Namespace: app.Namespace,
+		Name:      app.Name,
+		Version:   app.Version,
+		Content:   string(content),
+	}, nil
+}
+
+type ApplicationTrash struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	Version    string    `db:"version"`
+	Content    string    `db:"content"`
+	ExpireTime time.Time `db:"expire_time"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToAppTrashModel(trash *ApplicationTrash) (*models.AppTrash, error) {
+	app := &specV1.Application{}
+	if err := json.Unmarshal([]byte(trash.Content), app); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.AppTrash{
+		Name:        trash.Name,
+		Namespace:   trash.Namespace,
+		Version:     trash.Version,
+		Type:        app.Type,
+		Labels:      app.Labels,
+		Description: app.Description,
+		DeleteTime:  trash.CreateTime,
+		ExpireTime:  trash.ExpireTime,
+		Application: app,
+	}, nil
+}
+
+func FromAppTrashModel(app *specV1.Application, expireTime time.Time) (*ApplicationTrash, error) {
+	content, err := json.Marshal(app)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ApplicationTrash{
+		Namespace:  app.Namespace,
+		Name:       app.Name,
+		Version:    app.Version,
+		Content:    string(content),
+		ExpireTime: expireTime,
+	}, nil
+}
+
+type ApplicationCanary struct {
+	ID            int64     `db:"id"`
+	Namespace     string    `db:"namespace"`
+	Name          string    `db:"name"`
+	Version       string    `db:"version"`
+	StableVersion string    `db:"stable_version"`
+	Percent       int       `db:"percent"`
+	Nodes         string    `db:"nodes"`
+	CreateTime    time.Time `db:"create_time"`
+	UpdateTime    time.Time `db:"update_time"`
+}
+
+func ToAppCanaryModel(canary *ApplicationCanary) (*models.AppCanary, error) {
+	nodes := []string{}
+	if err := json.Unmarshal([]byte(canary.Nodes), &nodes); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.AppCanary{
+		Name:          canary.Name,
+		Namespace:     canary.Namespace,
+		Version:       canary.Version,
+		StableVersion: canary.StableVersion,
+		Percent:       canary.Percent,
+		Nodes:         nodes,
+		CreateTime:    canary.CreateTime,
+		UpdateTime:    canary.UpdateTime,
+	}, nil
+}
+
+func FromAppCanaryModel(canary *models.AppCanary) (*ApplicationCanary, error) {
+	nodes := canary.Nodes
+	if nodes == nil {
+		nodes = []string{}
+	}
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ApplicationCanary{
+		Namespace:     canary.Namespace,
+		Name:          canary.Name,
+		Version:       canary.Version,
+		StableVersion: canary.StableVersion,
+		Percent:       canary.Percent,
+		Nodes:         string(data),
+		CreateTime:    canary.CreateTime,
+		UpdateTime:    canary.UpdateTime,
+	}, nil
+}
+
+type NodeDeployRecord struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Node       string    `db:"node"`
+	Action     string    `db:"action"`
+	App        string    `db:"app"`
+	System     bool      `db:"system"`
+	Version    string    `db:"version"`
+	PrevVer    string    `db:"prev_version"`
+	Apps       string    `db:"apps"`
+	SysApps    string    `db:"sysapps"`
+	Desire     string    `db:"desire"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToNodeDeployRecordModel(record *NodeDeployRecord) (*models.NodeDeployRecord, error) {
+	res := &models.NodeDeployRecord{
+		ID:        record.ID,
+		Namespace: record.Namespace,
+		Node:      record.Node,
+		Action:    record.Action,
+		App: models.NodeDeployApp{
+			Name:        record.App,
+			System:      record.System,
+			Version:     record.Version,
+			PrevVersion: record.PrevVer,
+		},
+		Apps:    []specV1.AppInfo{},
+		SysApps: []specV1.AppInfo{},
+		Time:    record.CreateTime,
+	}
+	if err := json.Unmarshal([]byte(record.Apps), &res.Apps); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := json.Unmarshal([]byte(record.SysApps), &res.SysApps); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// the desire is not selected when listing
+	if record.Desire != "" {
+		if err := json.Unmarshal([]byte(record.Desire), &res.Desire); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return res, nil
+}
+
+func FromNodeDeployRecordModel(record *models.NodeDeployRecord) (*NodeDeployRecord, error) {
+	apps, sysApps := record.Apps, record.SysApps
+	if apps == nil {
+		apps = []specV1.AppInfo{}
+	}
+	if sysApps == nil {
+		sysApps = []specV1.AppInfo{}
+	}
+	appsData, err := json.Marshal(apps)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sysAppsData, err := json.Marshal(sysApps)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	desire, err := json.Marshal(record.Desire)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &NodeDeployRecord{
+		Namespace:  record.Namespace,
+		Node:       record.Node,
+		Action:     record.Action,
+		App:        record.App.Name,
+		System:     record.App.System,
+		Version:    record.App.Version,
+		PrevVer:    record.App.PrevVersion,
+		Apps:       string(appsData),
+		SysApps:    string(sysAppsData),
+		Desire:     string(desire),
+		CreateTime: record.Time,
+	}, nil
+}
+
+type NodePropertyRecord struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Node       string    `db:"node"`
+	Property   string    `db:"property"`
+	Type       string    `db:"type"`
+	Value      string    `db:"value"`
+	Actor      string    `db:"actor"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToNodePropertyRecordModel(record *NodePropertyRecord) (*models.NodePropertyRecord, error) {
+	res := &models.NodePropertyRecord{
+		ID:        record.ID,
+		Namespace: record.Namespace,
+		Node:      record.Node,
+		Property:  record.Property,
+		Type:      record.Type,
+		Actor:     record.Actor,
+		Time:      record.CreateTime,
+	}
+	if err := json.Unmarshal([]byte(record.Value), &res.Value); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return res, nil
+}
+
+func FromNodePropertyRecordModel(record *models.NodePropertyRecord) (*NodePropertyRecord, error) {
+	value, err := json.Marshal(record.Value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &NodePropertyRecord{
+		Namespace:  record.Namespace,
+		Node:       record.Node,
+		Property:   record.Property,
+		Type:       record.Type,
+		Value:      string(value),
+		Actor:      record.Actor,
+		CreateTime: record.Time,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type Audit struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	UserID     string    `db:"user_id"`
+	UserName   string    `db:"user_name"`
+	Resource   string    `db:"resource"`
+	Name       string    `db:"name"`
+	Method     string    `db:"method"`
+	Path       string    `db:"path"`
+	BodyHash   string    `db:"body_hash"`
+	Status     int       `db:"status"`
+	RequestID  string    `db:"request_id"`
+	ClientIP   string    `db:"client_ip"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToAuditModel(audit *Audit) *models.Audit {
+	return &models.Audit{
+		ID:        audit.ID,
+		Namespace: audit.Namespace,
+		UserID:    audit.UserID,
+		UserName:  audit.UserName,
+		Resource:  audit.Resource,
+		Name:      audit.Name,
+		Method:    audit.Method,
+		Path:      audit.Path,
+		BodyHash:  audit.BodyHash,
+		Status:    audit.Status,
+		RequestID: audit.RequestID,
+		ClientIP:  audit.ClientIP,
+		Timestamp: audit.CreateTime.UTC(),
+	}
+}
+
+func FromAuditModel(audit *models.Audit) *Audit {
+	return &Audit{
+		Namespace:  audit.Namespace,
+		UserID:     audit.UserID,
+		UserName:   audit.UserName,
+		Resource:   audit.Resource,
+		Name:       audit.Name,
+		Method:     audit.Method,
+		Path:       audit.Path,
+		BodyHash:   audit.BodyHash,
+		Status:     audit.Status,
+		RequestID:  audit.RequestID,
+		ClientIP:   audit.ClientIP,
+		CreateTime: audit.Timestamp,
+	}
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type Event struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Kind       string    `db:"kind"`
+	Name       string    `db:"name"`
+	Type       string    `db:"type"`
+	Message    string    `db:"message"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToEventModel(event *Event) *models.Event {
+	return &models.Event{
+		ID:        event.ID,
+		Namespace: event.Namespace,
+		Kind:      event.Kind,
+		Name:      event.Name,
+		Type:      event.Type,
+		Message:   event.Message,
+		Timestamp: event.CreateTime.UTC(),
+	}
+}
+
+func FromEventModel(event *models.Event) *Event {
+	return &Event{
+		Namespace:  event.Namespace,
+		Kind:       event.Kind,
+		Name:       event.Name,
+		Type:       event.Type,
+		Message:    event.Message,
+		CreateTime: event.Timestamp,
+	}
+}
@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type Job struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Type       string    `db:"type"`
+	Status     string    `db:"status"`
+	Progress   int       `db:"progress"`
+	Message    string    `db:"message"`
+	Result     string    `db:"result"`
+	CreateTime time.Time `db:"create_time"`
+	UpdateTime time.Time `db:"update_time"`
+}
+
+func ToJobModel(job *Job) (*models.Job, error) {
+	var result interface{}
+	if job.Result != "" {
+		if err := json.Unmarshal([]byte(job.Result), &result); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &models.Job{
+		ID:                job.ID,
+		Namespace:         job.Namespace,
+		Type:              job.Type,
+		Status:            job.Status,
+		Progress:          job.Progress,
+		Message:           job.Message,
+		Result:            result,
+		CreationTimestamp: job.CreateTime.UTC(),
+		UpdateTimestamp:   job.UpdateTime.UTC(),
+	}, nil
+}
+
+func FromJobModel(job *models.Job) (*Job, error) {
+	result := ""
+	if job.Result != nil {
+		data, err := json.Marshal(job.Result)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result = string(data)
+	}
+	return &Job{
+		ID:         job.ID,
+		Namespace:  job.Namespace,
+		Type:       job.Type,
+		Status:     job.Status,
+		Progress:   job.Progress,
+		Message:    job.Message,
+		Result:     result,
+		CreateTime: job.CreationTimestamp,
+	}, nil
+}
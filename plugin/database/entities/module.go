@@ -10,17 +10,19 @@ import (
 )
 
 type Module struct {
-	Id          uint64    `db:"id"`
-	Name        string    `db:"name"`
-	Version     string    `db:"version"`
-	Image       string    `db:"image"`
-	Programs    string    `db:"programs"`
-	Type        string    `db:"type"`
-	Flag        int       `db:"flag"`
-	IsLatest    bool      `db:"is_latest"`
-	Description string    `db:"description"`
-	CreateTime  time.Time `db:"create_time"`
-	UpdateTime  time.Time `db:"update_time"`
+	Id           uint64    `db:"id"`
+	Name         string    `db:"name"`
+	Version      string    `db:"version"`
+	Image        string    `db:"image"`
+	Programs     string    `db:"programs"`
+	Type         string    `db:"type"`
+	Flag         int       `db:"flag"`
+	IsLatest     bool      `db:"is_latest"`
+	Description  string    `db:"description"`
+	Dependencies string    `db:"dependencies"`
+	CreateUser   string    `db:"create_user"`
+	CreateTime   time.Time `db:"create_time"`
+	UpdateTime   time.Time `db:"update_time"`
 }
 
 func ToModuleModel(module *Module) (*models.Module, error) {
@@ -33,6 +35,7 @@ func ToModuleModel(module *Module) (*models.Module, error) {
 		Flag:              module.Flag,
 		IsLatest:          module.IsLatest,
 		Description:       module.Description,
+		CreatedBy:         module.CreateUser,
 		CreationTimestamp: module.CreateTime,
 		UpdateTimestamp:   module.UpdateTime,
 	}
@@ -46,6 +49,15 @@ func ToModuleModel(module *Module) (*models.Module, error) {
 			return nil, err
 		}
 	}
+	if module.Dependencies != "" {
+		err := json.Unmarshal([]byte(module.Dependencies), &m.Dependencies)
+		if err != nil {
+			log.L().Error("module db to module error",
+				log.Any("name", module.Name),
+				log.Any("version", module.Version))
+			return nil, err
+		}
+	}
 	return m, nil
 }
 
@@ -57,18 +69,31 @@ func FromModuleModel(module *models.Module) (*Module, error) {
 			log.Any("version", module.Version))
 		return nil, err
 	}
+	deps := ""
+	if len(module.Dependencies) > 0 {
+		d, err := json.Marshal(module.Dependencies)
+		if err != nil {
+			log.L().Error("module translate to db model error",
+				log.Any("name", module.Name),
+				log.Any("version", module.Version))
+			return nil, err
+		}
+		deps = string(d)
+	}
 
 	app := &Module{
-		Name:        module.Name,
-		Version:     module.Version,
-		Image:       module.Image,
-		Programs:    string(s),
-		Type:        module.Type,
-		Flag:        module.Flag,
-		IsLatest:    module.IsLatest,
-		Description: module.Description,
-		CreateTime:  time.Time{},
-		UpdateTime:  time.Time{},
+		Name:         module.Name,
+		Version:      module.Version,
+		Image:        module.Image,
+		Programs:     string(s),
+		Type:         module.Type,
+		Flag:         module.Flag,
+		IsLatest:     module.IsLatest,
+		Description:  module.Description,
+		Dependencies: deps,
+		CreateUser:   module.CreatedBy,
+		CreateTime:   time.Time{},
+		UpdateTime:   time.Time{},
 	}
 	return app, nil
 }
@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type NodeCommand struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Node       string    `db:"node"`
+	Type       string    `db:"type"`
+	Params     string    `db:"params"`
+	Status     string    `db:"status"`
+	Message    string    `db:"message"`
+	CreateTime time.Time `db:"create_time"`
+	UpdateTime time.Time `db:"update_time"`
+}
+
+func ToNodeCommandModel(command *NodeCommand) (*models.NodeCommand, error) {
+	var params map[string]string
+	if command.Params != "" {
+		if err := json.Unmarshal([]byte(command.Params), &params); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &models.NodeCommand{
+		ID:                command.ID,
+		Namespace:         command.Namespace,
+		Node:              command.Node,
+		Type:              command.Type,
+		Params:            params,
+		Status:            command.Status,
+		Message:           command.Message,
+		CreationTimestamp: command.CreateTime.UTC(),
+		UpdateTimestamp:   command.UpdateTime.UTC(),
+	}, nil
+}
+
+func FromNodeCommandModel(command *models.NodeCommand) (*NodeCommand, error) {
+	params, err := json.Marshal(command.Params)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &NodeCommand{
+		Namespace:  command.Namespace,
+		Node:       command.Node,
+		Type:       command.Type,
+		Params:     string(params),
+		Status:     command.Status,
+		Message:    command.Message,
+		CreateTime: command.CreationTimestamp,
+	}, nil
+}
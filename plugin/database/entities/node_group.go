@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type NodeGroup struct {
+	ID          int64     `db:"id"`
+	Namespace   string    `db:"namespace"`
+	Name        string    `db:"name"`
+	Selector    string    `db:"selector"`
+	Labels      string    `db:"labels"`
+	Description string    `db:"description"`
+	CreateTime  time.Time `db:"create_time"`
+	UpdateTime  time.Time `db:"update_time"`
+}
+
+func ToNodeGroupModel(group *NodeGroup) (*models.NodeGroup, error) {
+	labels := map[string]string{}
+	if err := json.Unmarshal([]byte(group.Labels), &labels); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.NodeGroup{
+		Name:              group.Name,
+		Namespace:         group.Namespace,
+		Selector:          group.Selector,
+		Labels:            labels,
+		Description:       group.Description,
+		CreationTimestamp: group.CreateTime.UTC(),
+		UpdateTimestamp:   group.UpdateTime.UTC(),
+	}, nil
+}
+
+func FromNodeGroupModel(group *models.NodeGroup) (*NodeGroup, error) {
+	labels, err := json.Marshal(group.Labels)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &NodeGroup{
+		Name:        group.Name,
+		Namespace:   group.Namespace,
+		Selector:    group.Selector,
+		Labels:      string(labels),
+		Description: group.Description,
+	}, nil
+}
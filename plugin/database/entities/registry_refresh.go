@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type RegistryRefresh struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	Result     string    `db:"result"`
+	Message    string    `db:"message"`
+	Attempts   int       `db:"attempts"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToRegistryRefreshModel(refresh *RegistryRefresh) *models.RegistryRefresh {
+	return &models.RegistryRefresh{
+		Namespace: refresh.Namespace,
+		Name:      refresh.Name,
+		Result:    refresh.Result,
+		Message:   refresh.Message,
+		Attempts:  refresh.Attempts,
+		Time:      refresh.CreateTime.UTC(),
+	}
+}
+
+func FromRegistryRefreshModel(refresh *models.RegistryRefresh) *RegistryRefresh {
+	return &RegistryRefresh{
+		Namespace:  refresh.Namespace,
+		Name:       refresh.Name,
+		Result:     refresh.Result,
+		Message:    refresh.Message,
+		Attempts:   refresh.Attempts,
+		CreateTime: refresh.Time,
+	}
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type ResourceVersion struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Kind       string    `db:"kind"`
+	Name       string    `db:"name"`
+	Version    string    `db:"version"`
+	Content    string    `db:"content"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToResourceVersionModel(version *ResourceVersion) *models.ResourceVersion {
+	return &models.ResourceVersion{
+		Namespace:  version.Namespace,
+		Kind:       version.Kind,
+		Name:       version.Name,
+		Version:    version.Version,
+		Content:    version.Content,
+		CreateTime: version.CreateTime.UTC(),
+	}
+}
+
+func FromResourceVersionModel(version *models.ResourceVersion) *ResourceVersion {
+	return &ResourceVersion{
+		Namespace: version.Namespace,
+		Kind:      version.Kind,
+		Name:      version.Name,
+		Version:   version.Version,
+		Content:   version.Content,
+	}
+}
@@ -0,0 +1,90 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type Webhook struct {
+	ID          int64     `db:"id"`
+	Namespace   string    `db:"namespace"`
+	Name        string    `db:"name"`
+	URL         string    `db:"url"`
+	Secret      string    `db:"secret"`
+	Events      string    `db:"events"`
+	Description string    `db:"description"`
+	CreateTime  time.Time `db:"create_time"`
+	UpdateTime  time.Time `db:"update_time"`
+}
+
+type WebhookDeadLetter struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Webhook    string    `db:"webhook"`
+	Event      string    `db:"event"`
+	Payload    string    `db:"payload"`
+	Attempts   int       `db:"attempts"`
+	Error      string    `db:"error"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToWebhookModel(webhook *Webhook) (*models.Webhook, error) {
+	var events []string
+	if err := json.Unmarshal([]byte(webhook.Events), &events); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.Webhook{
+		Name:              webhook.Name,
+		Namespace:         webhook.Namespace,
+		URL:               webhook.URL,
+		Secret:            webhook.Secret,
+		Events:            events,
+		Description:       webhook.Description,
+		CreationTimestamp: webhook.CreateTime.UTC(),
+		UpdateTimestamp:   webhook.UpdateTime.UTC(),
+	}, nil
+}
+
+func FromWebhookModel(webhook *models.Webhook) (*Webhook, error) {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Webhook{
+		Name:        webhook.Name,
+		Namespace:   webhook.Namespace,
+		URL:         webhook.URL,
+		Secret:      webhook.Secret,
+		Events:      string(events),
+		Description: webhook.Description,
+	}, nil
+}
+
+func ToWebhookDeadLetterModel(letter *WebhookDeadLetter) *models.WebhookDeadLetter {
+	return &models.WebhookDeadLetter{
+		ID:        letter.ID,
+		Namespace: letter.Namespace,
+		Webhook:   letter.Webhook,
+		Event:     letter.Event,
+		Payload:   letter.Payload,
+		Attempts:  letter.Attempts,
+		Error:     letter.Error,
+		Timestamp: letter.CreateTime.UTC(),
+	}
+}
+
+func FromWebhookDeadLetterModel(letter *models.WebhookDeadLetter) *WebhookDeadLetter {
+	return &WebhookDeadLetter{
+		Namespace:  letter.Namespace,
+		Webhook:    letter.Webhook,
+		Event:      letter.Event,
+		Payload:    letter.Payload,
+		Attempts:   letter.Attempts,
+		Error:      letter.Error,
+		CreateTime: letter.Timestamp,
+	}
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) SendEvent(event *models.Event) error {
+	defer utils.Trace(d.Log.Debug, "SendEvent")()
+	return d.CreateEventTx(nil, event)
+}
+
+func (d *BaetylCloudDB) CreateEventTx(tx *sqlx.Tx, event *models.Event) error {
+	insertSQL := `
+INSERT INTO baetyl_event
+(namespace, kind, name, type, message, create_time)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+	e := entities.FromEventModel(event)
+	if e.CreateTime.IsZero() {
+		e.CreateTime = time.Now()
+	}
+	_, err := d.Exec(tx, insertSQL, e.Namespace, e.Kind, e.Name, e.Type, e.Message, e.CreateTime.UTC())
+	return err
+}
+
+func (d *BaetylCloudDB) ListEventTx(tx *sqlx.Tx, namespace string) ([]models.Event, error) {
+	selectSQL := `
+SELECT id, namespace, kind, name, type, message, create_time
+FROM baetyl_event WHERE namespace=? ORDER BY id
+`
+	var events []entities.Event
+	if err := d.Query(tx, selectSQL, &events, namespace); err != nil {
+		return nil, err
+	}
+	res := make([]models.Event, 0, len(events))
+	for i := range events {
+		res = append(res, *entities.ToEventModel(&events[i]))
+	}
+	return res, nil
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	eventTables = []string{
+		`
+CREATE TABLE baetyl_event
+(
+	id                integer       PRIMARY KEY AUTOINCREMENT,
+	namespace         varchar(64)   NOT NULL DEFAULT '',
+	kind              varchar(64)   NOT NULL DEFAULT '',
+	name              varchar(128)  NOT NULL DEFAULT '',
+	type              varchar(64)   NOT NULL DEFAULT '',
+	message           varchar(1024) NOT NULL DEFAULT '',
+	create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateEventTable() {
+	for _, sql := range eventTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create event exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestEvent(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateEventTable()
+
+	now := time.Now().Truncate(time.Second)
+	err = db.SendEvent(&models.Event{Namespace: "default", Kind: models.EventKindNode, Name: "node01",
+		Type: models.EventNodeOffline, Message: "offline", Timestamp: now})
+	assert.NoError(t, err)
+	err = db.SendEvent(&models.Event{Namespace: "default", Kind: models.EventKindNode, Name: "node01", Type: models.EventNodeOnline})
+	assert.NoError(t, err)
+	err = db.SendEvent(&models.Event{Namespace: "other", Kind: models.EventKindNode, Name: "node02", Type: models.EventNodeOffline})
+	assert.NoError(t, err)
+
+	events, err := db.ListEventTx(nil, "default")
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "node01", events[0].Name)
+	assert.Equal(t, models.EventNodeOffline, events[0].Type)
+	assert.Equal(t, "offline", events[0].Message)
+	assert.True(t, now.Equal(events[0].Timestamp))
+	assert.Equal(t, models.EventNodeOnline, events[1].Type)
+	assert.False(t, events[1].Timestamp.IsZero())
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) CreateJob(tx interface{}, job *models.Job) (*models.Job, error) {
+	defer utils.Trace(d.Log.Debug, "CreateJob")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := d.CreateJobTx(transaction, job)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetJobTx(transaction, job.Namespace, id)
+}
+
+func (d *BaetylCloudDB) GetJob(tx interface{}, namespace string, id int64) (*models.Job, error) {
+	defer utils.Trace(d.Log.Debug, "GetJob")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetJobTx(transaction, namespace, id)
+}
+
+func (d *BaetylCloudDB) UpdateJob(tx interface{}, job *models.Job, oldStatus string) (bool, error) {
+	defer utils.Trace(d.Log.Debug, "UpdateJob")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return false, err
+	}
+	return d.UpdateJobTx(transaction, job, oldStatus)
+}
+
+func (d *BaetylCloudDB) GetJobTx(tx *sqlx.Tx, namespace string, id int64) (*models.Job, error) {
+	selectSQL := `
+SELECT id, namespace, type, status, progress, message, result, create_time, update_time
+FROM baetyl_job WHERE namespace=? AND id=?
+`
+	var jobs []entities.Job
+	if err := d.Query(tx, selectSQL, &jobs, namespace, id); err != nil {
+		return nil, err
+	}
+	if len(jobs) > 0 {
+		return entities.ToJobModel(&jobs[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "job"),
+		common.Field("name", id),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) CreateJobTx(tx *sqlx.Tx, job *models.Job) (int64, error) {
+	insertSQL := `
+INSERT INTO baetyl_job (namespace, type, status, progress, message, result, create_time, update_time)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+	j, err := entities.FromJobModel(job)
+	if err != nil {
+		return 0, err
+	}
+	if j.CreateTime.IsZero() {
+		j.CreateTime = time.Now()
+	}
+	res, err := d.Exec(tx, insertSQL, j.Namespace, j.Type, j.Status, j.Progress, j.Message, j.Result,
+		j.CreateTime.UTC(), j.CreateTime.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (d *BaetylCloudDB) UpdateJobTx(tx *sqlx.Tx, job *models.Job, oldStatus string) (bool, error) {
+	updateSQL := `
+UPDATE baetyl_job SET status=?, progress=?, message=?, result=?, update_time=?
+WHERE namespace=? AND id=? AND status=?
+`
+	j, err := entities.FromJobModel(job)
+	if err != nil {
+		return false, err
+	}
+	res, err := d.Exec(tx, updateSQL, j.Status, j.Progress, j.Message, j.Result, time.Now().UTC(),
+		j.Namespace, j.ID, oldStatus)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	jobTables = []string{
+		`
+CREATE TABLE baetyl_job
+(
+	id                integer       PRIMARY KEY AUTOINCREMENT,
+	namespace         varchar(64)   NOT NULL DEFAULT '',
+	type              varchar(64)   NOT NULL DEFAULT '',
+	status            varchar(32)   NOT NULL DEFAULT '',
+	progress          integer       NOT NULL DEFAULT 0,
+	message           varchar(1024) NOT NULL DEFAULT '',
+	result            text          NOT NULL DEFAULT '',
+	create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	update_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateJobTable() {
+	for _, sql := range jobTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create job exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestJob(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateJobTable()
+
+	job, err := db.CreateJob(nil, &models.Job{
+		Namespace: "default",
+		Type:      models.JobNamespaceImport,
+		Status:    models.JobPending,
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, job.ID)
+	assert.Equal(t, models.JobPending, job.Status)
+	assert.Nil(t, job.Result)
+
+	_, err = db.GetJob(nil, "other", job.ID)
+	assert.Error(t, err)
+	_, err = db.GetJob(nil, "default", job.ID+1)
+	assert.Error(t, err)
+
+	job.Status = models.JobRunning
+	ok, err := db.UpdateJob(nil, job, models.JobPending)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// the job isn't pending any more
+	ok, err = db.UpdateJob(nil, job, models.JobPending)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	job.Status, job.Progress = models.JobSucceeded, 100
+	job.Result = map[string]interface{}{"created": []interface{}{"config/c1"}}
+	ok, err = db.UpdateJob(nil, job, models.JobRunning)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	res, err := db.GetJob(nil, "default", job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobSucceeded, res.Status)
+	assert.Equal(t, 100, res.Progress)
+	assert.Equal(t, job.Result, res.Result)
+	assert.Equal(t, models.JobNamespaceImport, res.Type)
+}
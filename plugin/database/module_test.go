@@ -24,6 +24,8 @@ CREATE TABLE IF NOT EXISTS baetyl_module
   flag        int(10)          NOT NULL DEFAULT '0',
   is_latest   int(1)           NOT NULL DEFAULT '0',
   description varchar(1024)    NOT NULL DEFAULT '',
+  dependencies varchar(2048)   NOT NULL DEFAULT '',
+  create_user varchar(128)     NOT NULL DEFAULT '',
   create_time timestamp        NOT NULL DEFAULT CURRENT_TIMESTAMP,
   update_time timestamp        NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
@@ -84,9 +86,11 @@ func TestModule(t *testing.T) {
 			"linux-amd64":    "url-linux-amd64",
 			"linux-arm64-v8": "url-linux-arm64-v8",
 		},
-		Type:        "a",
-		IsLatest:    false,
-		Description: "for desp",
+		Type:         "a",
+		IsLatest:     false,
+		Description:  "for desp",
+		Dependencies: []models.ModuleDependency{{Name: "baetyl", Version: "v2.0.0"}},
+		CreatedBy:    "admin",
 	}
 
 	res, err = db.CreateModule(module01)
@@ -536,4 +540,6 @@ func checkModule(t *testing.T, expect, actual *models.Module) {
 	assert.EqualValues(t, expect.Type, actual.Type)
 	assert.Equal(t, expect.IsLatest, actual.IsLatest)
 	assert.Equal(t, expect.Description, actual.Description)
+	assert.Equal(t, expect.Dependencies, actual.Dependencies)
+	assert.Equal(t, expect.CreatedBy, actual.CreatedBy)
 }
@@ -3,6 +3,7 @@ package database
 
 import (
 	"database/sql"
+	"fmt"
 	"strings"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
@@ -11,6 +12,7 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/baetyl/baetyl-go/v2/utils"
 	"github.com/jmoiron/sqlx"
+	kl "k8s.io/apimachinery/pkg/labels"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
@@ -87,6 +89,15 @@ func (d *BaetylCloudDB) getAndUpdateNodeTx(tx *sqlx.Tx, namespace string, nodes
 	return err
 }
 
+func (d *BaetylCloudDB) UpdateNodeIfVersion(tx interface{}, namespace string, node *specV1.Node, version string) (bool, error) {
+	defer utils.Trace(d.Log.Debug, "UpdateNodeIfVersion")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return false, err
+	}
+	return d.UpdateNodeIfVersionTx(transaction, namespace, node, version)
+}
+
 func (d *BaetylCloudDB) DeleteNode(tx interface{}, namespace, name string) error {
 	defer utils.Trace(d.Log.Debug, "DeleteNode")()
 	transaction, err := d.InterfaceToTx(tx)
@@ -236,30 +247,94 @@ func (d *BaetylCloudDB) UpdateNodeTx(tx *sqlx.Tx, namespace string, nodes []*spe
 	return d.Exec(tx, updateSQL, params...)
 }
 
+func (d *BaetylCloudDB) UpdateNodeIfVersionTx(tx *sqlx.Tx, namespace string, node *specV1.Node, version string) (bool, error) {
+	nd, err := entities.FromNodeModel(namespace, node)
+	if err != nil {
+		return false, err
+	}
+	updateSQL := `
+UPDATE baetyl_node SET version=?, core_version=?, node_mode=?, description=?, labels=?, annotations=?, attributes=?
+WHERE namespace=? AND name=? AND version=?
+`
+	res, err := d.Exec(tx, updateSQL, nd.Version, nd.CoreVersion, nd.NodeMode, nd.Description,
+		nd.Labels, nd.Annotations, nd.Attributes, namespace, nd.Name, version)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows != 1 {
+		return false, nil
+	}
+	node.Version = nd.Version
+	return true, nil
+}
+
 func (d *BaetylCloudDB) ListNodeTx(_ *sqlx.Tx, namespace string, listOptions *models.ListOptions) ([]specV1.Node, int, error) {
+	selector, err := common.ParseLabelSelector(listOptions.LabelSelector)
+	if err != nil {
+		return nil, 0, err
+	}
 	selectSQL := `
 SELECT 
 id, namespace, name, version, core_version, node_mode, description, create_time, labels, annotations, attributes
-FROM baetyl_node WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC
-`
-	var nodes []entities.Node
-	if err := d.Query(nil, selectSQL, &nodes, namespace, listOptions.GetFuzzyName()); err != nil {
-		return nil, 0, err
+FROM baetyl_node WHERE namespace=? AND name LIKE ?`
+	args := []interface{}{namespace, listOptions.GetFuzzyName()}
+	// pre-filter rows by the equality requirements of the selector, the selector is fully matched below
+	for k, v := range common.SelectorEqualities(selector) {
+		selectSQL += " AND labels LIKE ?"
+		if v == "" {
+			args = append(args, fmt.Sprintf(`%%"%s":%%`, k))
+		} else {
+			args = append(args, fmt.Sprintf(`%%"%s":"%s"%%`, k, v))
+		}
 	}
-	var result []specV1.Node
-	for _, node := range nodes {
+	match := func(node *entities.Node) (*specV1.Node, error) {
 		labels := map[string]string{}
 		if err := json.Unmarshal([]byte(node.Labels), &labels); err != nil {
-			return nil, 0, errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
-		if ok, err := utils.IsLabelMatch(listOptions.LabelSelector, labels); err != nil || !ok {
-			continue
+		if !selector.Matches(kl.Set(labels)) {
+			return nil, nil
 		}
-		nd, err := entities.ToNodeModel(&node)
+		nd, err := entities.ToNodeModel(node)
 		if err != nil {
-			return nil, 0, errors.Trace(err)
+			return nil, errors.Trace(err)
+		}
+		if listOptions.Tag != "" && !common.MatchNodeTags(nd.Annotations, listOptions.Tag) {
+			return nil, nil
+		}
+		return nd, nil
+	}
+	if listOptions.IsCursorPaging() {
+		result, err := queryByCursor(d, nil, selectSQL, args, listOptions,
+			func(node *entities.Node) int64 { return node.ID }, match)
+		if err != nil {
+			return nil, 0, err
+		}
+		return result, len(result), nil
+	}
+	if listOptions.CreateSort != "" {
+		// the nodes sorted by createSort are reversed by the service if it's ascending
+		selectSQL += " ORDER BY create_time DESC, name ASC"
+	} else {
+		selectSQL += orderBy(listOptions)
+	}
+	var nodes []entities.Node
+	if err := d.Query(nil, selectSQL, &nodes, args...); err != nil {
+		return nil, 0, err
+	}
+	var result []specV1.Node
+	for i := range nodes {
+		nd, err := match(&nodes[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		if nd != nil {
+			result = append(result, *nd)
 		}
-		result = append(result, *nd)
 	}
 	return result, len(result), nil
 }
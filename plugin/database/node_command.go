@@ -0,0 +1,166 @@
+package database
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) CreateNodeCommand(tx interface{}, command *models.NodeCommand) (*models.NodeCommand, error) {
+	defer utils.Trace(d.Log.Debug, "CreateNodeCommand")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := d.CreateNodeCommandTx(transaction, command)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetNodeCommandTx(transaction, command.Namespace, command.Node, id)
+}
+
+func (d *BaetylCloudDB) ListNodeCommand(tx interface{}, namespace, node string, filter *models.Filter) (*models.NodeCommandList, error) {
+	defer utils.Trace(d.Log.Debug, "ListNodeCommand")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	commands, err := d.ListNodeCommandTx(transaction, namespace, node, filter)
+	if err != nil {
+		return nil, err
+	}
+	total, err := d.CountNodeCommandTx(transaction, namespace, node, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return &models.NodeCommandList{
+		Total:  total,
+		Filter: filter,
+		Items:  commands,
+	}, nil
+}
+
+func (d *BaetylCloudDB) CountNodeCommand(tx interface{}, namespace, node string, since time.Time) (int, error) {
+	defer utils.Trace(d.Log.Debug, "CountNodeCommand")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return 0, err
+	}
+	return d.CountNodeCommandTx(transaction, namespace, node, since)
+}
+
+func (d *BaetylCloudDB) ListPendingNodeCommand(tx interface{}, namespace, node string, since time.Time) ([]models.NodeCommand, error) {
+	defer utils.Trace(d.Log.Debug, "ListPendingNodeCommand")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.ListPendingNodeCommandTx(transaction, namespace, node, since)
+}
+
+func (d *BaetylCloudDB) UpdateNodeCommandStatus(tx interface{}, namespace, node string, id int64, status, message string) error {
+	defer utils.Trace(d.Log.Debug, "UpdateNodeCommandStatus")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.UpdateNodeCommandStatusTx(transaction, namespace, node, id, status, message)
+}
+
+func (d *BaetylCloudDB) GetNodeCommandTx(tx *sqlx.Tx, namespace, node string, id int64) (*models.NodeCommand, error) {
+	selectSQL := `
+SELECT id, namespace, node, type, params, status, message, create_time, update_time
+FROM baetyl_node_command WHERE namespace=? AND node=? AND id=?
+`
+	var commands []entities.NodeCommand
+	if err := d.Query(tx, selectSQL, &commands, namespace, node, id); err != nil {
+		return nil, err
+	}
+	if len(commands) > 0 {
+		return entities.ToNodeCommandModel(&commands[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "command"),
+		common.Field("name", id),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) ListNodeCommandTx(tx *sqlx.Tx, namespace, node string, filter *models.Filter) ([]models.NodeCommand, error) {
+	selectSQL := `
+SELECT id, namespace, node, type, params, status, message, create_time, update_time
+FROM baetyl_node_command WHERE namespace=? AND node=? ORDER BY id DESC`
+	args := []interface{}{namespace, node}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL += " LIMIT ?,?"
+		args = append(args, filter.GetLimitOffset(), filter.GetLimitNumber())
+	}
+	return d.queryNodeCommands(tx, selectSQL, args...)
+}
+
+func (d *BaetylCloudDB) ListPendingNodeCommandTx(tx *sqlx.Tx, namespace, node string, since time.Time) ([]models.NodeCommand, error) {
+	selectSQL := `
+SELECT id, namespace, node, type, params, status, message, create_time, update_time
+FROM baetyl_node_command WHERE namespace=? AND node=? AND status=? AND create_time>=? ORDER BY id
+`
+	return d.queryNodeCommands(tx, selectSQL, namespace, node, models.NodeCommandPending, since.UTC())
+}
+
+func (d *BaetylCloudDB) CreateNodeCommandTx(tx *sqlx.Tx, command *models.NodeCommand) (int64, error) {
+	insertSQL := `
+INSERT INTO baetyl_node_command (namespace, node, type, params, status, message, create_time, update_time)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+	c, err := entities.FromNodeCommandModel(command)
+	if err != nil {
+		return 0, err
+	}
+	if c.CreateTime.IsZero() {
+		c.CreateTime = time.Now()
+	}
+	res, err := d.Exec(tx, insertSQL, c.Namespace, c.Node, c.Type, c.Params, c.Status, c.Message,
+		c.CreateTime.UTC(), c.CreateTime.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (d *BaetylCloudDB) UpdateNodeCommandStatusTx(tx *sqlx.Tx, namespace, node string, id int64, status, message string) error {
+	updateSQL := `
+UPDATE baetyl_node_command SET status=?, message=?, update_time=?
+WHERE namespace=? AND node=? AND id=?
+`
+	_, err := d.Exec(tx, updateSQL, status, message, time.Now().UTC(), namespace, node, id)
+	return err
+}
+
+func (d *BaetylCloudDB) CountNodeCommandTx(tx *sqlx.Tx, namespace, node string, since time.Time) (int, error) {
+	selectSQL := `SELECT COUNT(id) FROM baetyl_node_command WHERE namespace=? AND node=? AND create_time>=?`
+	var count []int
+	if err := d.Query(tx, selectSQL, &count, namespace, node, since.UTC()); err != nil {
+		return 0, err
+	}
+	return count[0], nil
+}
+
+func (d *BaetylCloudDB) queryNodeCommands(tx *sqlx.Tx, selectSQL string, args ...interface{}) ([]models.NodeCommand, error) {
+	var commands []entities.NodeCommand
+	if err := d.Query(tx, selectSQL, &commands, args...); err != nil {
+		return nil, err
+	}
+	result := make([]models.NodeCommand, 0, len(commands))
+	for i := range commands {
+		command, err := entities.ToNodeCommandModel(&commands[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *command)
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	nodeCommandTables = []string{
+		`
+CREATE TABLE baetyl_node_command
+(
+	id                integer       PRIMARY KEY AUTOINCREMENT,
+	namespace         varchar(64)   NOT NULL DEFAULT '',
+	node              varchar(128)  NOT NULL DEFAULT '',
+	type              varchar(64)   NOT NULL DEFAULT '',
+	params            varchar(2048) NOT NULL DEFAULT '{}',
+	status            varchar(32)   NOT NULL DEFAULT '',
+	message           varchar(1024) NOT NULL DEFAULT '',
+	create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	update_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateNodeCommandTable() {
+	for _, sql := range nodeCommandTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create node command exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestNodeCommand(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateNodeCommandTable()
+
+	now := time.Now()
+	old, err := db.CreateNodeCommand(nil, &models.NodeCommand{
+		Namespace:         "default",
+		Node:              "node01",
+		Type:              models.NodeCommandReboot,
+		Status:            models.NodeCommandPending,
+		CreationTimestamp: now.Add(-2 * time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.NodeCommandReboot, old.Type)
+	assert.Nil(t, old.Params)
+
+	res, err := db.CreateNodeCommand(nil, &models.NodeCommand{
+		Namespace: "default",
+		Node:      "node01",
+		Type:      models.NodeCommandCollectLogs,
+		Params:    map[string]string{"since": "1h"},
+		Status:    models.NodeCommandPending,
+	})
+	assert.NoError(t, err)
+	assert.NotEqual(t, old.ID, res.ID)
+	assert.Equal(t, map[string]string{"since": "1h"}, res.Params)
+	assert.Equal(t, models.NodeCommandPending, res.Status)
+	_, err = db.CreateNodeCommand(nil, &models.NodeCommand{Namespace: "default", Node: "node02", Type: models.NodeCommandReboot})
+	assert.NoError(t, err)
+
+	count, err := db.CountNodeCommand(nil, "default", "node01", now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	pending, err := db.ListPendingNodeCommand(nil, "default", "node01", now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, res.ID, pending[0].ID)
+
+	assert.NoError(t, db.UpdateNodeCommandStatus(nil, "default", "node01", res.ID, models.NodeCommandFailed, "no such file"))
+	pending, err = db.ListPendingNodeCommand(nil, "default", "node01", now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, pending, 0)
+
+	list, err := db.ListNodeCommand(nil, "default", "node01", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	// the latest first
+	assert.Equal(t, res.ID, list.Items[0].ID)
+	assert.Equal(t, models.NodeCommandFailed, list.Items[0].Status)
+	assert.Equal(t, "no such file", list.Items[0].Message)
+
+	list, err = db.ListNodeCommand(nil, "default", "node01", &models.Filter{PageNo: 2, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, old.ID, list.Items[0].ID)
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) GetNodeGroup(tx interface{}, namespace, name string) (*models.NodeGroup, error) {
+	defer utils.Trace(d.Log.Debug, "GetNodeGroup")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetNodeGroupTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) ListNodeGroup(tx interface{}, namespace string, listOptions *models.ListOptions) (*models.NodeGroupList, error) {
+	defer utils.Trace(d.Log.Debug, "ListNodeGroup")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	groups, resLen, err := d.ListNodeGroupTx(transaction, namespace, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NodeGroupList{
+		Total:       resLen,
+		ListOptions: listOptions,
+		Items:       groups,
+	}, nil
+}
+
+func (d *BaetylCloudDB) CreateNodeGroup(tx interface{}, group *models.NodeGroup) (*models.NodeGroup, error) {
+	defer utils.Trace(d.Log.Debug, "CreateNodeGroup")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.CreateNodeGroupTx(transaction, group); err != nil {
+		return nil, err
+	}
+	return d.GetNodeGroupTx(transaction, group.Namespace, group.Name)
+}
+
+func (d *BaetylCloudDB) UpdateNodeGroup(tx interface{}, group *models.NodeGroup) (*models.NodeGroup, error) {
+	defer utils.Trace(d.Log.Debug, "UpdateNodeGroup")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.UpdateNodeGroupTx(transaction, group); err != nil {
+		return nil, err
+	}
+	return d.GetNodeGroupTx(transaction, group.Namespace, group.Name)
+}
+
+func (d *BaetylCloudDB) DeleteNodeGroup(tx interface{}, namespace, name string) error {
+	defer utils.Trace(d.Log.Debug, "DeleteNodeGroup")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.DeleteNodeGroupTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) GetNodeGroupTx(tx *sqlx.Tx, namespace, name string) (*models.NodeGroup, error) {
+	selectSQL := `
+SELECT id, namespace, name, selector, labels, description, create_time, update_time
+FROM baetyl_node_group WHERE namespace=? AND name=?
+`
+	var groups []entities.NodeGroup
+	if err := d.Query(tx, selectSQL, &groups, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(groups) > 0 {
+		return entities.ToNodeGroupModel(&groups[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", common.NodeGroup),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) ListNodeGroupTx(tx *sqlx.Tx, namespace string, listOptions *models.ListOptions) ([]models.NodeGroup, int, error) {
+	selectSQL := `
+SELECT id, namespace, name, selector, labels, description, create_time, update_time
+FROM baetyl_node_group WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC
+`
+	var groups []entities.NodeGroup
+	if err := d.Query(tx, selectSQL, &groups, namespace, listOptions.GetFuzzyName()); err != nil {
+		return nil, 0, err
+	}
+	result := make([]models.NodeGroup, 0)
+	for i := range groups {
+		labels := map[string]string{}
+		if err := json.Unmarshal([]byte(groups[i].Labels), &labels); err != nil {
+			return nil, 0, errors.Trace(err)
+		}
+		if ok, err := utils.IsLabelMatch(listOptions.LabelSelector, labels); err != nil || !ok {
+			continue
+		}
+		group, err := entities.ToNodeGroupModel(&groups[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, *group)
+	}
+	start, end := models.GetPagingParam(listOptions, len(result))
+	return result[start:end], len(result), nil
+}
+
+func (d *BaetylCloudDB) CreateNodeGroupTx(tx *sqlx.Tx, group *models.NodeGroup) error {
+	insertSQL := `
+INSERT INTO baetyl_node_group (namespace, name, selector, labels, description)
+VALUES (?, ?, ?, ?, ?)
+`
+	g, err := entities.FromNodeGroupModel(group)
+	if err != nil {
+		return err
+	}
+	_, err = d.Exec(tx, insertSQL, g.Namespace, g.Name, g.Selector, g.Labels, g.Description)
+	return err
+}
+
+func (d *BaetylCloudDB) UpdateNodeGroupTx(tx *sqlx.Tx, group *models.NodeGroup) error {
+	updateSQL := `
+UPDATE baetyl_node_group SET selector=?, labels=?, description=?, update_time=CURRENT_TIMESTAMP
+WHERE namespace=? AND name=?
+`
+	g, err := entities.FromNodeGroupModel(group)
+	if err != nil {
+		return err
+	}
+	_, err = d.Exec(tx, updateSQL, g.Selector, g.Labels, g.Description, g.Namespace, g.Name)
+	return err
+}
+
+func (d *BaetylCloudDB) DeleteNodeGroupTx(tx *sqlx.Tx, namespace, name string) error {
+	deleteSQL := `
+DELETE FROM baetyl_node_group WHERE namespace=? AND name=?
+`
+	_, err := d.Exec(tx, deleteSQL, namespace, name)
+	return err
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	nodeGroupTables = []string{
+		`
+CREATE TABLE baetyl_node_group
+(
+	id                integer       PRIMARY KEY AUTOINCREMENT,
+    namespace         varchar(64)   NOT NULL DEFAULT '',
+    name              varchar(128)  NOT NULL DEFAULT '',
+	selector          varchar(2048) NOT NULL DEFAULT '',
+	labels            varchar(2048) NOT NULL DEFAULT '{}',
+	description       varchar(1024) NOT NULL DEFAULT '',
+    create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateNodeGroupTable() {
+	for _, sql := range nodeGroupTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create node group exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestNodeGroup(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateNodeGroupTable()
+
+	group := &models.NodeGroup{
+		Name:        "group01",
+		Namespace:   "default",
+		Selector:    "region=bj",
+		Labels:      map[string]string{"a": "b"},
+		Description: "desc",
+	}
+	res, err := db.CreateNodeGroup(nil, group)
+	assert.NoError(t, err)
+	assert.Equal(t, group.Name, res.Name)
+	assert.Equal(t, group.Selector, res.Selector)
+	assert.Equal(t, group.Labels, res.Labels)
+	assert.Equal(t, group.Description, res.Description)
+
+	res, err = db.GetNodeGroup(nil, "default", "group01")
+	assert.NoError(t, err)
+	assert.Equal(t, "region=bj", res.Selector)
+
+	group2 := &models.NodeGroup{
+		Name:      "group02",
+		Namespace: "default",
+		Selector:  "region=sh",
+		Labels:    map[string]string{"a": "c"},
+	}
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	_, err = db.CreateNodeGroup(tx, group2)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	list, err := db.ListNodeGroup(nil, "default", &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Len(t, list.Items, 2)
+
+	list, err = db.ListNodeGroup(nil, "default", &models.ListOptions{LabelSelector: "a=c"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "group02", list.Items[0].Name)
+
+	list, err = db.ListNodeGroup(nil, "default", &models.ListOptions{Filter: models.Filter{Name: "01"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "group01", list.Items[0].Name)
+
+	list, err = db.ListNodeGroup(nil, "default", &models.ListOptions{Filter: models.Filter{PageNo: 2, PageSize: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Len(t, list.Items, 1)
+
+	group.Selector = "region in (bj, sh)"
+	group.Description = "updated"
+	res, err = db.UpdateNodeGroup(nil, group)
+	assert.NoError(t, err)
+	assert.Equal(t, "region in (bj, sh)", res.Selector)
+	assert.Equal(t, "updated", res.Description)
+
+	err = db.DeleteNodeGroup(nil, "default", "group01")
+	assert.NoError(t, err)
+	_, err = db.GetNodeGroup(nil, "default", "group01")
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrResourceNotFound, e.Code())
+
+	list, err = db.ListNodeGroup(nil, "default", &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+}
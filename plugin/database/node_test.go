@@ -10,6 +10,7 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
@@ -91,10 +92,25 @@ func TestNode(t *testing.T) {
 	assert.NoError(t, err)
 	checkNode(t, node, res)
 
+	// the node is updated only if it's still of the version
+	version := res.Version
+	res.Attributes["x"] = "y"
+	ok, err := db.UpdateNodeIfVersion(nil, "default", res, version)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NotEqual(t, version, res.Version)
+	res.Attributes["x"] = "z"
+	ok, err = db.UpdateNodeIfVersion(nil, "default", res, version)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	res, err = db.GetNode(nil, node.Namespace, node.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, "y", res.Attributes["x"])
+
 	resList, err := db.ListNode(nil, node.Namespace, listOptions)
 	assert.NoError(t, err)
 	assert.Equal(t, resList.Total, 1)
-	checkNode(t, node, &resList.Items[0])
+	checkNode(t, res, &resList.Items[0])
 
 	err = db.DeleteNode(nil, node.Namespace, node.Name)
 	assert.NoError(t, err)
@@ -128,7 +144,7 @@ func TestListNode(t *testing.T) {
 		Description:       "desc",
 		CreationTimestamp: time.Unix(1000, 1000),
 		Labels:            map[string]string{"label": "bbb"},
-		Annotations:       map[string]string{"annotation": "bbb"},
+		Annotations:       map[string]string{"annotation": "bbb", common.AnnotationNodeTagPrefix + "Owner": "Alice"},
 		Attributes:        map[string]interface{}{"attr": "bbb"},
 	}
 	node4 := &specV1.Node{
@@ -236,6 +252,46 @@ func TestListNode(t *testing.T) {
 	assert.Equal(t, resList.Total, 1)
 	checkNode(t, node2, &resList.Items[0])
 
+	// label selector
+	listOptions = &models.ListOptions{LabelSelector: "label=aaa"}
+	resList, err = db.ListNode(nil, "default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resList.Total)
+	listOptions = &models.ListOptions{LabelSelector: "label in (bbb,ccc)"}
+	resList, err = db.ListNode(nil, "default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resList.Total)
+	listOptions = &models.ListOptions{LabelSelector: "label,label!=bbb"}
+	resList, err = db.ListNode(nil, "default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resList.Total)
+	listOptions = &models.ListOptions{LabelSelector: "!label"}
+	resList, err = db.ListNode(nil, "default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resList.Total)
+	listOptions = &models.ListOptions{LabelSelector: "label=="}
+	resList, err = db.ListNode(nil, "default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resList.Total)
+	listOptions = &models.ListOptions{LabelSelector: "label~aaa"}
+	_, err = db.ListNode(nil, "default", listOptions)
+	assert.Error(t, err)
+
+	// tag search
+	listOptions = &models.ListOptions{NodeOptions: models.NodeOptions{Tag: "owner"}}
+	resList, err = db.ListNode(nil, "default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resList.Total)
+	checkNode(t, node3, &resList.Items[0])
+	listOptions = &models.ListOptions{NodeOptions: models.NodeOptions{Tag: "ali"}}
+	resList, err = db.ListNode(nil, "default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resList.Total)
+	listOptions = &models.ListOptions{NodeOptions: models.NodeOptions{Tag: "bbb"}}
+	resList, err = db.ListNode(nil, "default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resList.Total)
+
 	total, err := db.CountAllNode(nil)
 	assert.NoError(t, err)
 	assert.Equal(t, 4, total)
@@ -436,3 +492,36 @@ func TestGetNodeByNames(t *testing.T) {
 	assert.Equal(t, data[1].Name, "node_abc")
 
 }
+
+func TestListNodeByCursor(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateNodeTable()
+
+	for i := 0; i < 3; i++ {
+		_, err = db.CreateNode(nil, "default", &specV1.Node{
+			Name:   fmt.Sprintf("node-%d", i),
+			Labels: map[string]string{"label": "aaa"},
+		})
+		assert.NoError(t, err)
+	}
+
+	listOptions := &models.ListOptions{Limit: 2, LabelSelector: "label=aaa", CursorPaging: true}
+	resList, err := db.ListNode(nil, "default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resList.Total)
+	assert.Equal(t, "node-2", resList.Items[0].Name)
+	assert.Equal(t, "node-1", resList.Items[1].Name)
+	assert.NotEmpty(t, resList.NextCursor)
+
+	listOptions = &models.ListOptions{Limit: 2, LabelSelector: "label=aaa", Cursor: resList.NextCursor}
+	resList, err = db.ListNode(nil, "default", listOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resList.Total)
+	assert.Equal(t, "node-0", resList.Items[0].Name)
+	assert.Empty(t, resList.NextCursor)
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) SaveRegistryRefresh(tx interface{}, refresh *models.RegistryRefresh) error {
+	defer utils.Trace(d.Log.Debug, "SaveRegistryRefresh")()
+	if tx == nil {
+		return d.Transact(func(transaction *sqlx.Tx) error {
+			return d.SaveRegistryRefreshTx(transaction, refresh)
+		})
+	}
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.SaveRegistryRefreshTx(transaction, refresh)
+}
+
+func (d *BaetylCloudDB) GetRegistryRefresh(tx interface{}, namespace, name string) (*models.RegistryRefresh, error) {
+	defer utils.Trace(d.Log.Debug, "GetRegistryRefresh")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetRegistryRefreshTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) DeleteRegistryRefresh(tx interface{}, namespace, name string) error {
+	defer utils.Trace(d.Log.Debug, "DeleteRegistryRefresh")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.DeleteRegistryRefreshTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) SaveRegistryRefreshTx(tx *sqlx.Tx, refresh *models.RegistryRefresh) error {
+	if err := d.DeleteRegistryRefreshTx(tx, refresh.Namespace, refresh.Name); err != nil {
+		return err
+	}
+	insertSQL := `
+INSERT INTO baetyl_registry_refresh (namespace, name, result, message, attempts, create_time)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+	entity := entities.FromRegistryRefreshModel(refresh)
+	if entity.CreateTime.IsZero() {
+		entity.CreateTime = time.Now()
+	}
+	_, err := d.Exec(tx, insertSQL, entity.Namespace, entity.Name, entity.Result, entity.Message,
+		entity.Attempts, entity.CreateTime.UTC())
+	return err
+}
+
+func (d *BaetylCloudDB) GetRegistryRefreshTx(tx *sqlx.Tx, namespace, name string) (*models.RegistryRefresh, error) {
+	selectSQL := `
+SELECT id, namespace, name, result, message, attempts, create_time
+FROM baetyl_registry_refresh WHERE namespace=? AND name=?
+`
+	var refreshes []entities.RegistryRefresh
+	if err := d.Query(tx, selectSQL, &refreshes, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(refreshes) > 0 {
+		return entities.ToRegistryRefreshModel(&refreshes[0]), nil
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "registry refresh"),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) DeleteRegistryRefreshTx(tx *sqlx.Tx, namespace, name string) error {
+	deleteSQL := `
+DELETE FROM baetyl_registry_refresh WHERE namespace=? AND name=?
+`
+	_, err := d.Exec(tx, deleteSQL, namespace, name)
+	return err
+}
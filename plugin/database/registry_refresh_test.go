@@ -0,0 +1,90 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	registryRefreshTables = []string{
+		`
+CREATE TABLE baetyl_registry_refresh
+(
+	id                integer       PRIMARY KEY AUTOINCREMENT,
+	namespace         varchar(64)   NOT NULL DEFAULT '',
+	name              varchar(128)  NOT NULL DEFAULT '',
+	result            varchar(32)   NOT NULL DEFAULT '',
+	message           varchar(1024) NOT NULL DEFAULT '',
+	attempts          integer       NOT NULL DEFAULT 0,
+	create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateRegistryRefreshTable() {
+	for _, sql := range registryRefreshTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create registry refresh exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestRegistryRefresh(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateRegistryRefreshTable()
+
+	_, err = db.GetRegistryRefresh(nil, "default", "reg01")
+	assert.Error(t, err)
+
+	now := time.Now().Add(-time.Minute)
+	err = db.SaveRegistryRefresh(nil, &models.RegistryRefresh{
+		Namespace: "default",
+		Name:      "reg01",
+		Result:    models.RegistryRefreshTemporaryFailure,
+		Message:   "timeout",
+		Attempts:  3,
+		Time:      now,
+	})
+	assert.NoError(t, err)
+	res, err := db.GetRegistryRefresh(nil, "default", "reg01")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RegistryRefreshTemporaryFailure, res.Result)
+	assert.Equal(t, "timeout", res.Message)
+	assert.Equal(t, 3, res.Attempts)
+	assert.Equal(t, now.Unix(), res.Time.Unix())
+
+	// the last refresh replaces the previous one
+	err = db.SaveRegistryRefresh(nil, &models.RegistryRefresh{
+		Namespace: "default",
+		Name:      "reg01",
+		Result:    models.RegistryRefreshSucceeded,
+		Attempts:  1,
+	})
+	assert.NoError(t, err)
+	res, err = db.GetRegistryRefresh(nil, "default", "reg01")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RegistryRefreshSucceeded, res.Result)
+	assert.Equal(t, "", res.Message)
+	assert.Equal(t, 1, res.Attempts)
+	assert.False(t, res.Time.IsZero())
+
+	_, err = db.GetRegistryRefresh(nil, "other", "reg01")
+	assert.Error(t, err)
+
+	err = db.DeleteRegistryRefresh(nil, "default", "reg01")
+	assert.NoError(t, err)
+	_, err = db.GetRegistryRefresh(nil, "default", "reg01")
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) CreateResourceVersion(tx interface{}, version *models.ResourceVersion) error {
+	defer utils.Trace(d.Log.Debug, "CreateResourceVersion")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.CreateResourceVersionTx(transaction, version)
+}
+
+func (d *BaetylCloudDB) GetResourceVersion(tx interface{}, namespace, kind, name, version string) (*models.ResourceVersion, error) {
+	defer utils.Trace(d.Log.Debug, "GetResourceVersion")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetResourceVersionTx(transaction, namespace, kind, name, version)
+}
+
+func (d *BaetylCloudDB) ListResourceVersion(tx interface{}, namespace, kind, name string) ([]models.ResourceVersion, error) {
+	defer utils.Trace(d.Log.Debug, "ListResourceVersion")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.ListResourceVersionTx(transaction, namespace, kind, name)
+}
+
+func (d *BaetylCloudDB) DeleteResourceVersion(tx interface{}, namespace, kind, name string, keep int) error {
+	defer utils.Trace(d.Log.Debug, "DeleteResourceVersion")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.DeleteResourceVersionTx(transaction, namespace, kind, name, keep)
+}
+
+// CreateResourceVersionTx records a version of the config or secret, a version already recorded is kept as is
+func (d *BaetylCloudDB) CreateResourceVersionTx(tx *sqlx.Tx, version *models.ResourceVersion) error {
+	_, err := d.GetResourceVersionTx(tx, version.Namespace, version.Kind, version.Name, version.Version)
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+		return err
+	}
+
+	insertSQL := `
+INSERT INTO baetyl_resource_version (namespace, kind, name, version, content)
+VALUES (?, ?, ?, ?, ?)
+`
+	entity := entities.FromResourceVersionModel(version)
+	_, err = d.Exec(tx, insertSQL, entity.Namespace, entity.Kind, entity.Name, entity.Version, entity.Content)
+	return err
+}
+
+func (d *BaetylCloudDB) GetResourceVersionTx(tx *sqlx.Tx, namespace, kind, name, version string) (*models.ResourceVersion, error) {
+	selectSQL := `
+SELECT id, namespace, kind, name, version, content, create_time
+FROM baetyl_resource_version WHERE namespace=? AND kind=? AND name=? AND version=?
+`
+	var versions []entities.ResourceVersion
+	if err := d.Query(tx, selectSQL, &versions, namespace, kind, name, version); err != nil {
+		return nil, err
+	}
+	if len(versions) > 0 {
+		return entities.ToResourceVersionModel(&versions[0]), nil
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", kind+" version"),
+		common.Field("name", name+"@"+version),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) ListResourceVersionTx(tx *sqlx.Tx, namespace, kind, name string) ([]models.ResourceVersion, error) {
+	selectSQL := `
+SELECT id, namespace, kind, name, version, create_time
+FROM baetyl_resource_version WHERE namespace=? AND kind=? AND name=? ORDER BY id DESC
+`
+	var versions []entities.ResourceVersion
+	if err := d.Query(tx, selectSQL, &versions, namespace, kind, name); err != nil {
+		return nil, err
+	}
+	res := make([]models.ResourceVersion, 0, len(versions))
+	for i := range versions {
+		res = append(res, *entities.ToResourceVersionModel(&versions[i]))
+	}
+	return res, nil
+}
+
+func (d *BaetylCloudDB) DeleteResourceVersionTx(tx *sqlx.Tx, namespace, kind, name string, keep int) error {
+	deleteSQL := `DELETE FROM baetyl_resource_version WHERE namespace=? AND kind=? AND name=?`
+	args := []interface{}{namespace, kind, name}
+	if keep > 0 {
+		// the versions are deleted from the latest one out of the kept ones
+		selectSQL := `
+SELECT id FROM baetyl_resource_version WHERE namespace=? AND kind=? AND name=? ORDER BY id DESC LIMIT ?,1
+`
+		var ids []int64
+		if err := d.Query(tx, selectSQL, &ids, namespace, kind, name, keep); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		deleteSQL += " AND id<=?"
+		args = append(args, ids[0])
+	}
+	_, err := d.Exec(tx, deleteSQL, args...)
+	return err
+}
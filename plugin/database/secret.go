@@ -42,6 +42,9 @@ func (d *BaetylCloudDB) CreateSecret(tx interface{}, namespace string, secret *s
 }
 
 func (d *BaetylCloudDB) createAndGetSecret(tx *sqlx.Tx, ns string, in *specV1.Secret, out **specV1.Secret) error {
+	if err := d.checkNameConflict(tx, "baetyl_secret", "secret", ns, in.Name); err != nil {
+		return err
+	}
 	_, err := d.CreateSecretTx(tx, ns, in)
 	if err != nil {
 		return err
@@ -50,17 +53,26 @@ func (d *BaetylCloudDB) createAndGetSecret(tx *sqlx.Tx, ns string, in *specV1.Se
 	return err
 }
 
-func (d *BaetylCloudDB) UpdateSecret(namespace string, secret *specV1.Secret) (*specV1.Secret, error) {
+func (d *BaetylCloudDB) UpdateSecret(tx interface{}, namespace string, secret *specV1.Secret) (*specV1.Secret, error) {
 	var se *specV1.Secret
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
 	defer utils.Trace(d.Log.Debug, "UpdateSecret")()
-	err := d.Transact(func(tx *sqlx.Tx) error {
+	update := func(tx *sqlx.Tx) error {
 		_, err := d.UpdateSecretTx(tx, namespace, secret)
 		if err != nil {
 			return err
 		}
 		se, err = d.GetSecretTx(tx, namespace, secret.Name)
 		return err
-	})
+	}
+	if transaction == nil {
+		err = d.Transact(update)
+	} else {
+		err = update(transaction)
+	}
 	return se, err
 }
 
@@ -82,7 +82,7 @@ func TestSecret(t *testing.T) {
 	checkSecret(t, secret, res)
 
 	secret.Labels = map[string]string{"b": "b"}
-	res, err = db.UpdateSecret("default", secret)
+	res, err = db.UpdateSecret(nil, "default", secret)
 	assert.NoError(t, err)
 	checkSecret(t, secret, res)
 
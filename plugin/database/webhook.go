@@ -0,0 +1,218 @@
+package database
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) GetWebhook(tx interface{}, namespace, name string) (*models.Webhook, error) {
+	defer utils.Trace(d.Log.Debug, "GetWebhook")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetWebhookTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) ListWebhook(tx interface{}, namespace string, listOptions *models.ListOptions) (*models.WebhookList, error) {
+	defer utils.Trace(d.Log.Debug, "ListWebhook")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	webhooks, err := d.ListWebhookTx(transaction, namespace, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	start, end := models.GetPagingParam(listOptions, len(webhooks))
+	return &models.WebhookList{
+		Total:       len(webhooks),
+		ListOptions: listOptions,
+		Items:       webhooks[start:end],
+	}, nil
+}
+
+func (d *BaetylCloudDB) CreateWebhook(tx interface{}, webhook *models.Webhook) (*models.Webhook, error) {
+	defer utils.Trace(d.Log.Debug, "CreateWebhook")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.CreateWebhookTx(transaction, webhook); err != nil {
+		return nil, err
+	}
+	return d.GetWebhookTx(transaction, webhook.Namespace, webhook.Name)
+}
+
+func (d *BaetylCloudDB) UpdateWebhook(tx interface{}, webhook *models.Webhook) (*models.Webhook, error) {
+	defer utils.Trace(d.Log.Debug, "UpdateWebhook")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.UpdateWebhookTx(transaction, webhook); err != nil {
+		return nil, err
+	}
+	return d.GetWebhookTx(transaction, webhook.Namespace, webhook.Name)
+}
+
+func (d *BaetylCloudDB) DeleteWebhook(tx interface{}, namespace, name string) error {
+	defer utils.Trace(d.Log.Debug, "DeleteWebhook")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.DeleteWebhookTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) CreateWebhookDeadLetter(tx interface{}, letter *models.WebhookDeadLetter) error {
+	defer utils.Trace(d.Log.Debug, "CreateWebhookDeadLetter")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.CreateWebhookDeadLetterTx(transaction, letter)
+}
+
+func (d *BaetylCloudDB) ListWebhookDeadLetter(tx interface{}, namespace, webhook string, filter *models.Filter) (*models.WebhookDeadLetterList, error) {
+	defer utils.Trace(d.Log.Debug, "ListWebhookDeadLetter")()
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	letters, err := d.ListWebhookDeadLetterTx(transaction, namespace, webhook, filter)
+	if err != nil {
+		return nil, err
+	}
+	total, err := d.CountWebhookDeadLetterTx(transaction, namespace, webhook)
+	if err != nil {
+		return nil, err
+	}
+	return &models.WebhookDeadLetterList{
+		Total:  total,
+		Filter: filter,
+		Items:  letters,
+	}, nil
+}
+
+func (d *BaetylCloudDB) GetWebhookTx(tx *sqlx.Tx, namespace, name string) (*models.Webhook, error) {
+	selectSQL := `
+SELECT id, namespace, name, url, secret, events, description, create_time, update_time
+FROM baetyl_webhook WHERE namespace=? AND name=?
+`
+	var webhooks []entities.Webhook
+	if err := d.Query(tx, selectSQL, &webhooks, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(webhooks) > 0 {
+		return entities.ToWebhookModel(&webhooks[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", common.Webhook),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) ListWebhookTx(tx *sqlx.Tx, namespace string, listOptions *models.ListOptions) ([]models.Webhook, error) {
+	selectSQL := `
+SELECT id, namespace, name, url, secret, events, description, create_time, update_time
+FROM baetyl_webhook WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC
+`
+	var webhooks []entities.Webhook
+	if err := d.Query(tx, selectSQL, &webhooks, namespace, listOptions.GetFuzzyName()); err != nil {
+		return nil, err
+	}
+	result := make([]models.Webhook, 0, len(webhooks))
+	for i := range webhooks {
+		webhook, err := entities.ToWebhookModel(&webhooks[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *webhook)
+	}
+	return result, nil
+}
+
+func (d *BaetylCloudDB) CreateWebhookTx(tx *sqlx.Tx, webhook *models.Webhook) error {
+	insertSQL := `
+INSERT INTO baetyl_webhook (namespace, name, url, secret, events, description)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+	w, err := entities.FromWebhookModel(webhook)
+	if err != nil {
+		return err
+	}
+	_, err = d.Exec(tx, insertSQL, w.Namespace, w.Name, w.URL, w.Secret, w.Events, w.Description)
+	return err
+}
+
+func (d *BaetylCloudDB) UpdateWebhookTx(tx *sqlx.Tx, webhook *models.Webhook) error {
+	updateSQL := `
+UPDATE baetyl_webhook SET url=?, secret=?, events=?, description=?, update_time=CURRENT_TIMESTAMP
+WHERE namespace=? AND name=?
+`
+	w, err := entities.FromWebhookModel(webhook)
+	if err != nil {
+		return err
+	}
+	_, err = d.Exec(tx, updateSQL, w.URL, w.Secret, w.Events, w.Description, w.Namespace, w.Name)
+	return err
+}
+
+func (d *BaetylCloudDB) DeleteWebhookTx(tx *sqlx.Tx, namespace, name string) error {
+	deleteSQL := `
+DELETE FROM baetyl_webhook WHERE namespace=? AND name=?
+`
+	_, err := d.Exec(tx, deleteSQL, namespace, name)
+	return err
+}
+
+func (d *BaetylCloudDB) CreateWebhookDeadLetterTx(tx *sqlx.Tx, letter *models.WebhookDeadLetter) error {
+	insertSQL := `
+INSERT INTO baetyl_webhook_dead_letter
+(namespace, webhook, event, payload, attempts, error, create_time)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+	l := entities.FromWebhookDeadLetterModel(letter)
+	if l.CreateTime.IsZero() {
+		l.CreateTime = time.Now()
+	}
+	_, err := d.Exec(tx, insertSQL, l.Namespace, l.Webhook, l.Event, l.Payload, l.Attempts, l.Error, l.CreateTime.UTC())
+	return err
+}
+
+func (d *BaetylCloudDB) ListWebhookDeadLetterTx(tx *sqlx.Tx, namespace, webhook string, filter *models.Filter) ([]models.WebhookDeadLetter, error) {
+	selectSQL := `
+SELECT id, namespace, webhook, event, payload, attempts, error, create_time
+FROM baetyl_webhook_dead_letter WHERE namespace=? AND webhook=? ORDER BY id DESC`
+	args := []interface{}{namespace, webhook}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL += " LIMIT ?,?"
+		args = append(args, filter.GetLimitOffset(), filter.GetLimitNumber())
+	}
+	var letters []entities.WebhookDeadLetter
+	if err := d.Query(tx, selectSQL, &letters, args...); err != nil {
+		return nil, err
+	}
+	result := make([]models.WebhookDeadLetter, 0, len(letters))
+	for i := range letters {
+		result = append(result, *entities.ToWebhookDeadLetterModel(&letters[i]))
+	}
+	return result, nil
+}
+
+func (d *BaetylCloudDB) CountWebhookDeadLetterTx(tx *sqlx.Tx, namespace, webhook string) (int, error) {
+	selectSQL := `SELECT COUNT(id) FROM baetyl_webhook_dead_letter WHERE namespace=? AND webhook=?`
+	var count []int
+	if err := d.Query(tx, selectSQL, &count, namespace, webhook); err != nil {
+		return 0, err
+	}
+	return count[0], nil
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	webhookTables = []string{
+		`
+CREATE TABLE baetyl_webhook
+(
+	id                integer       PRIMARY KEY AUTOINCREMENT,
+	namespace         varchar(64)   NOT NULL DEFAULT '',
+	name              varchar(128)  NOT NULL DEFAULT '',
+	url               varchar(2048) NOT NULL DEFAULT '',
+	secret            varchar(256)  NOT NULL DEFAULT '',
+	events            varchar(2048) NOT NULL DEFAULT '[]',
+	description       varchar(1024) NOT NULL DEFAULT '',
+	create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	update_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		`
+CREATE TABLE baetyl_webhook_dead_letter
+(
+	id                integer       PRIMARY KEY AUTOINCREMENT,
+	namespace         varchar(64)   NOT NULL DEFAULT '',
+	webhook           varchar(128)  NOT NULL DEFAULT '',
+	event             varchar(64)   NOT NULL DEFAULT '',
+	payload           text          NOT NULL,
+	attempts          integer       NOT NULL DEFAULT 0,
+	error             varchar(1024) NOT NULL DEFAULT '',
+	create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateWebhookTable() {
+	for _, sql := range webhookTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create webhook exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateWebhookTable()
+
+	webhook := &models.Webhook{
+		Name:        "hook01",
+		Namespace:   "default",
+		URL:         "https://example.com/hook",
+		Secret:      "s3cret",
+		Events:      []string{models.WebhookEventNodeOffline, "app.updated"},
+		Description: "desc",
+	}
+	res, err := db.CreateWebhook(nil, webhook)
+	assert.NoError(t, err)
+	assert.Equal(t, webhook.Name, res.Name)
+	assert.Equal(t, webhook.URL, res.URL)
+	assert.Equal(t, webhook.Secret, res.Secret)
+	assert.Equal(t, webhook.Events, res.Events)
+	assert.Equal(t, webhook.Description, res.Description)
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	_, err = db.CreateWebhook(tx, &models.Webhook{Name: "hook02", Namespace: "default", URL: "http://b", Events: []string{"*"}})
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	list, err := db.ListWebhook(nil, "default", &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	list, err = db.ListWebhook(nil, "default", &models.ListOptions{Filter: models.Filter{Name: "02"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "hook02", list.Items[0].Name)
+	list, err = db.ListWebhook(nil, "other", &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, list.Total)
+
+	webhook.URL = "https://example.com/hook2"
+	webhook.Events = []string{models.WebhookEventCertExpiring}
+	res, err = db.UpdateWebhook(nil, webhook)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook2", res.URL)
+	assert.Equal(t, []string{models.WebhookEventCertExpiring}, res.Events)
+
+	assert.NoError(t, db.DeleteWebhook(nil, "default", "hook01"))
+	_, err = db.GetWebhook(nil, "default", "hook01")
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrResourceNotFound, e.Code())
+}
+
+func TestWebhookDeadLetter(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateWebhookTable()
+
+	for i := 0; i < 3; i++ {
+		err = db.CreateWebhookDeadLetter(nil, &models.WebhookDeadLetter{
+			Namespace: "default",
+			Webhook:   "hook01",
+			Event:     models.WebhookEventNodeOffline,
+			Payload:   fmt.Sprintf(`{"id":"%d"}`, i),
+			Attempts:  5,
+			Error:     "timeout",
+			Timestamp: time.Now(),
+		})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, db.CreateWebhookDeadLetter(nil, &models.WebhookDeadLetter{Namespace: "default", Webhook: "hook02", Payload: "{}"}))
+
+	list, err := db.ListWebhookDeadLetter(nil, "default", "hook01", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, list.Total)
+	assert.Len(t, list.Items, 3)
+	// the latest first
+	assert.Equal(t, `{"id":"2"}`, list.Items[0].Payload)
+	assert.Equal(t, 5, list.Items[0].Attempts)
+	assert.Equal(t, "timeout", list.Items[0].Error)
+
+	list, err = db.ListWebhookDeadLetter(nil, "default", "hook01", &models.Filter{PageNo: 2, PageSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, list.Total)
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, `{"id":"0"}`, list.Items[0].Payload)
+}
@@ -0,0 +1,9 @@
+package kms
+
+// CloudConfig baetyl-cloud config
+type CloudConfig struct {
+	DefaultKMS struct {
+		// Key the base64 encoded AES master key of 16, 24 or 32 bytes, which is kept in the config for development only
+		Key string `yaml:"key" json:"key" binding:"nonzero"`
+	} `yaml:"defaultkms" json:"defaultkms"`
+}
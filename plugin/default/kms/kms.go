@@ -0,0 +1,54 @@
+package kms
+
+import (
+	"crypto/aes"
+	"encoding/base64"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+// defaultKMS encrypts the data keys with a local AES master key
+type defaultKMS struct {
+	key []byte
+}
+
+func init() {
+	plugin.RegisterFactory("defaultkms", New)
+}
+
+// New New
+func New() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newDefaultKMS(cfg)
+}
+
+func newDefaultKMS(cfg CloudConfig) (*defaultKMS, error) {
+	key, err := base64.StdEncoding.DecodeString(cfg.DefaultKMS.Key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err = aes.NewCipher(key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &defaultKMS{key: key}, nil
+}
+
+func (d *defaultKMS) Encrypt(plaintext []byte) ([]byte, error) {
+	return util.SealGCM(plaintext, d.key)
+}
+
+func (d *defaultKMS) Decrypt(ciphertext []byte) ([]byte, error) {
+	return util.OpenGCM(ciphertext, d.key)
+}
+
+// Close Close
+func (d *defaultKMS) Close() error {
+	return nil
+}
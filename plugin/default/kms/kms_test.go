@@ -0,0 +1,36 @@
+package kms
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultKMS(t *testing.T) {
+	var cfg CloudConfig
+	cfg.DefaultKMS.Key = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	k, err := newDefaultKMS(cfg)
+	assert.NoError(t, err)
+
+	cipher, err := k.Encrypt([]byte("data key"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, []byte("data key"), cipher)
+	plain, err := k.Decrypt(cipher)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data key"), plain)
+	assert.NoError(t, k.Close())
+
+	cfg.DefaultKMS.Key = base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+	other, err := newDefaultKMS(cfg)
+	assert.NoError(t, err)
+	_, err = other.Decrypt(cipher)
+	assert.Error(t, err)
+
+	cfg.DefaultKMS.Key = base64.StdEncoding.EncodeToString([]byte("short"))
+	_, err = newDefaultKMS(cfg)
+	assert.Error(t, err)
+	cfg.DefaultKMS.Key = "!not base64"
+	_, err = newDefaultKMS(cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const (
+	// Retention the points older than it are dropped
+	Retention = 24 * time.Hour
+	// MaxPoints the max number of the points kept for each node
+	MaxPoints = 2880
+)
+
+// metrics keeps the recent points in memory, which are lost on restart and not shared by the instances,
+// a persistent store should be configured for the deployments with multiple instances
+type metrics struct {
+	mu     sync.RWMutex
+	points map[string][]models.NodeStatsPoint
+}
+
+func init() {
+	plugin.RegisterFactory("defaultmetricsstore", New)
+}
+
+func New() (plugin.Plugin, error) {
+	return &metrics{points: map[string][]models.NodeStatsPoint{}}, nil
+}
+
+var _ plugin.MetricsStore = &metrics{}
+
+func (m *metrics) WriteNodeStats(namespace, name string, point *models.NodeStatsPoint) error {
+	key := namespace + "/" + name
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	points := append(m.points[key], *point)
+	if len(points) > 1 && points[len(points)-1].Time.Before(points[len(points)-2].Time) {
+		sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	}
+	expired := sort.Search(len(points), func(i int) bool {
+		return !points[i].Time.Before(point.Time.Add(-Retention))
+	})
+	if over := len(points) - MaxPoints; over > expired {
+		expired = over
+	}
+	m.points[key] = points[expired:]
+	return nil
+}
+
+func (m *metrics) ListNodeStats(namespace, name string, from, to time.Time) ([]models.NodeStatsPoint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	points := m.points[namespace+"/"+name]
+	start := sort.Search(len(points), func(i int) bool { return !points[i].Time.Before(from) })
+	end := sort.Search(len(points), func(i int) bool { return !points[i].Time.Before(to) })
+	res := make([]models.NodeStatsPoint, end-start)
+	copy(res, points[start:end])
+	return res, nil
+}
+
+func (m *metrics) Close() error {
+	return nil
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestMetrics_NodeStats(t *testing.T) {
+	p, err := New()
+	assert.NoError(t, err)
+	m := p.(plugin.MetricsStore)
+	defer m.Close()
+
+	now := time.Now().UTC()
+	for _, d := range []time.Duration{-3 * time.Minute, -time.Minute, -2 * time.Minute, 0} {
+		assert.NoError(t, m.WriteNodeStats("default", "n1", &models.NodeStatsPoint{Time: now.Add(d)}))
+	}
+	assert.NoError(t, m.WriteNodeStats("default", "n2", &models.NodeStatsPoint{Time: now}))
+
+	res, err := m.ListNodeStats("default", "n1", now.Add(-2*time.Minute), now)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, now.Add(-2*time.Minute), res[0].Time)
+	assert.Equal(t, now.Add(-time.Minute), res[1].Time)
+
+	res, err = m.ListNodeStats("default", "n3", now.Add(-time.Hour), now)
+	assert.NoError(t, err)
+	assert.Len(t, res, 0)
+
+	// expired
+	assert.NoError(t, m.WriteNodeStats("default", "n1", &models.NodeStatsPoint{Time: now.Add(Retention - 150*time.Second)}))
+	res, err = m.ListNodeStats("default", "n1", now.Add(-time.Hour), now.Add(Retention))
+	assert.NoError(t, err)
+	assert.Len(t, res, 4)
+}
@@ -0,0 +1,31 @@
+package objectupload
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+type objectUploadHook struct {
+}
+
+func init() {
+	plugin.RegisterFactory("defaultobjectuploadhook", New)
+}
+
+func New() (plugin.Plugin, error) {
+	return &objectUploadHook{}, nil
+}
+
+var _ plugin.ObjectUploadHook = &objectUploadHook{}
+
+func (h *objectUploadHook) BeforeUpload(_ *models.ObjectUpload) error {
+	return nil
+}
+
+func (h *objectUploadHook) AfterUpload(_ *models.ObjectUpload, _ *models.ObjectMeta) (*models.ObjectUploadVerdict, error) {
+	return &models.ObjectUploadVerdict{Action: models.ObjectUploadAccept}, nil
+}
+
+func (h *objectUploadHook) Close() error {
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"io/ioutil"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/pki"
 
@@ -131,6 +132,15 @@ func (p *defaultPkiClient) CreateClientCert(csr []byte, rootId string) (string,
 	return p.createSubCert(csr, rootId)
 }
 
+// CreateClientCertWithValidity signs the client certificate valid for the days of the validity, at least one day
+func (p *defaultPkiClient) CreateClientCertWithValidity(csr []byte, rootId string, validity time.Duration) (string, error) {
+	days := int(validity.Hours() / 24)
+	if days < 1 {
+		days = 1
+	}
+	return p.createSubCertWithDays(csr, rootId, days)
+}
+
 func (p *defaultPkiClient) GetClientCert(certId string) ([]byte, error) {
 	return p.getCert(certId)
 }
@@ -165,11 +175,15 @@ func (p *defaultPkiClient) checkRootCA() error {
 }
 
 func (p *defaultPkiClient) createSubCert(csr []byte, rootId string) (string, error) {
+	return p.createSubCertWithDays(csr, rootId, (int)(p.cfg.PKI.SubDuration.Hours()/24))
+}
+
+func (p *defaultPkiClient) createSubCertWithDays(csr []byte, rootId string, days int) (string, error) {
 	parent, err := p.getRootCA(rootId)
 	if err != nil {
 		return "", err
 	}
-	crt, err := p.pkiClient.CreateSubCert(csr, (int)(p.cfg.PKI.SubDuration.Hours()/24), parent)
+	crt, err := p.pkiClient.CreateSubCert(csr, days, parent)
 	if err != nil {
 		return "", err
 	}
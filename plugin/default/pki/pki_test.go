@@ -9,6 +9,7 @@ import (
 	"path"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/pki"
 	"github.com/golang/mock/gomock"
@@ -252,6 +253,28 @@ func TestDefaultPkiClient_CreateClientCert(t *testing.T) {
 	assert.NotEqual(t, "", res)
 }
 
+func TestDefaultPkiClient_CreateClientCertWithValidity(t *testing.T) {
+	p, s := genDefaultPkiClient(t)
+	s.EXPECT().GetCert(RootCertId).Return(genRootCAView(), nil).Times(2)
+	var certs []plugin.Cert
+	s.EXPECT().CreateCert(gomock.Any()).DoAndReturn(func(cert plugin.Cert) error {
+		certs = append(certs, cert)
+		return nil
+	}).Times(2)
+
+	csr, err := base64.StdEncoding.DecodeString(base64CSR)
+	assert.NoError(t, err)
+	res, err := p.CreateClientCertWithValidity(csr, RootCertId, 10*24*time.Hour)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", res)
+	// at least one day
+	_, err = p.CreateClientCertWithValidity(csr, RootCertId, time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, certs, 2)
+	assert.Equal(t, 10*24*time.Hour, certs[0].NotAfter.Sub(certs[0].NotBefore))
+	assert.Equal(t, 24*time.Hour, certs[1].NotAfter.Sub(certs[1].NotBefore))
+}
+
 func TestDefaultPkiClient_GetClientCert(t *testing.T) {
 	p, s := genDefaultPkiClient(t)
 	s.EXPECT().GetCert(RootCertId).Return(genRootCAView(), nil).Times(1)
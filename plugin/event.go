@@ -0,0 +1,15 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/event.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin EventSink
+
+// EventSink delivers the events of the resources, such as the nodes going offline and coming back
+type EventSink interface {
+	SendEvent(event *models.Event) error
+	io.Closer
+}
@@ -1,12 +1,13 @@
 package plugin
 
 import (
+	"context"
 	"io"
 
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
-//go:generate mockgen -destination=../mock/plugin/function.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Function
+//go:generate mockgen -destination=../mock/plugin/function.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Function,FunctionInvoker,FunctionUploader
 
 // Function interface of Function
 type Function interface {
@@ -15,3 +16,15 @@ type Function interface {
 	Get(userID, name, version string) (*models.Function, error)
 	io.Closer
 }
+
+// FunctionInvoker the optional interface of the function plugins which can invoke the functions,
+// the invocation should be stopped once the context is done
+type FunctionInvoker interface {
+	Invoke(ctx context.Context, userID, name, version string, payload []byte) (*models.FunctionInvocation, error)
+}
+
+// FunctionUploader the optional interface of the function plugins which can store the code archives uploaded,
+// such as the local function sources, the archive is registered as the version of the function
+type FunctionUploader interface {
+	Upload(userID string, function *models.Function, archive []byte) (*models.Function, error)
+}
@@ -0,0 +1,19 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/job.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Job
+
+// Job stores the jobs running in background
+type Job interface {
+	CreateJob(tx interface{}, job *models.Job) (*models.Job, error)
+	GetJob(tx interface{}, namespace string, id int64) (*models.Job, error)
+	// UpdateJob updates the status, progress, message and result of the job only if its status is still the old one,
+	// returns false if it isn't, e.g. the job is canceled while running
+	UpdateJob(tx interface{}, job *models.Job, oldStatus string) (bool, error)
+	io.Closer
+}
@@ -0,0 +1,14 @@
+package plugin
+
+import "io"
+
+//go:generate mockgen -destination=../mock/plugin/kms.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin KMS
+
+// KMS the key management service protecting the data keys which encrypt the secrets at rest
+type KMS interface {
+	// Encrypt encrypts the data key with the master key
+	Encrypt(plaintext []byte) ([]byte, error)
+	// Decrypt decrypts the data key encrypted by Encrypt
+	Decrypt(ciphertext []byte) ([]byte, error)
+	io.Closer
+}
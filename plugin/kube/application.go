@@ -2,6 +2,8 @@ package kube
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/json"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
@@ -120,6 +122,26 @@ func fromListOptionsModel(listOptions *models.ListOptions) *metav1.ListOptions {
 	return res
 }
 
+// sortList sorts the items listed by the sort of listOptions, which are sorted by name ascending if sortBy is not set,
+// and by name ascending secondarily. The items are listed page by page, so only the items of the page are sorted
+func sortList[T any](items []T, listOptions *models.ListOptions, name func(*T) string, timeOf func(*T, string) time.Time) {
+	desc := listOptions.Order == models.SortOrderDesc
+	byTime := listOptions.SortBy != "" && listOptions.SortBy != models.SortByName
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := &items[i], &items[j]
+		if byTime {
+			if ta, tb := timeOf(a, listOptions.SortBy), timeOf(b, listOptions.SortBy); !ta.Equal(tb) {
+				return ta.Before(tb) != desc
+			}
+			return name(a) < name(b)
+		}
+		if desc {
+			return name(a) > name(b)
+		}
+		return name(a) < name(b)
+	})
+}
+
 func (c *client) GetApplication(_ interface{}, namespace, name, version string) (*specV1.Application, error) {
 	defer utils.Trace(c.log.Debug, "GetApplication")()
 	options := metav1.GetOptions{ResourceVersion: version}
@@ -165,6 +187,8 @@ func (c *client) ListApplication(tx interface{}, namespace string, listOptions *
 		return nil, err
 	}
 	res := toAppListModel(list)
+	sortList(res.Items, listOptions, func(app *models.AppItem) string { return app.Name },
+		func(app *models.AppItem, _ string) time.Time { return app.CreationTimestamp })
 	res.ListOptions = listOptions
 	return res, err
 }
@@ -2,6 +2,7 @@ package kube
 
 import (
 	"testing"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/log"
 
@@ -288,3 +289,34 @@ func TestListListApplicationByName(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 0, num)
 }
+
+func TestSortList(t *testing.T) {
+	now := time.Now()
+	items := []models.AppItem{
+		{Name: "c", CreationTimestamp: now},
+		{Name: "a", CreationTimestamp: now.Add(time.Minute)},
+		{Name: "b", CreationTimestamp: now},
+	}
+	names := func() []string {
+		var res []string
+		for _, item := range items {
+			res = append(res, item.Name)
+		}
+		return res
+	}
+	name := func(app *models.AppItem) string { return app.Name }
+	createTime := func(app *models.AppItem, _ string) time.Time { return app.CreationTimestamp }
+
+	sortList(items, &models.ListOptions{}, name, createTime)
+	assert.Equal(t, []string{"a", "b", "c"}, names())
+
+	sortList(items, &models.ListOptions{SortBy: models.SortByName, Order: models.SortOrderDesc}, name, createTime)
+	assert.Equal(t, []string{"c", "b", "a"}, names())
+
+	// the items created at the same time are sorted by name ascending
+	sortList(items, &models.ListOptions{SortBy: models.SortByCreateTime}, name, createTime)
+	assert.Equal(t, []string{"b", "c", "a"}, names())
+
+	sortList(items, &models.ListOptions{SortBy: models.SortByCreateTime, Order: models.SortOrderDesc}, name, createTime)
+	assert.Equal(t, []string{"a", "b", "c"}, names())
+}
@@ -112,6 +112,13 @@ func (c *client) ListConfig(namespace string, listOptions *models.ListOptions) (
 		return nil, err
 	}
 	res := toConfigurationListModel(list)
+	sortList(res.Items, listOptions, func(cfg *specV1.Configuration) string { return cfg.Name },
+		func(cfg *specV1.Configuration, sortBy string) time.Time {
+			if sortBy == models.SortByUpdateTime {
+				return cfg.UpdateTimestamp
+			}
+			return cfg.CreationTimestamp
+		})
 	res.ListOptions = listOptions
 	return res, err
 }
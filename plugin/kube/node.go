@@ -2,6 +2,7 @@ package kube
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/json"
 	"github.com/baetyl/baetyl-go/v2/log"
@@ -9,6 +10,7 @@ import (
 	"github.com/baetyl/baetyl-go/v2/utils"
 	"github.com/jinzhu/copier"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
@@ -201,6 +203,25 @@ func (c *client) UpdateNode(tx interface{}, namespace string, nodes []*specV1.No
 	return res, nil
 }
 
+func (c *client) UpdateNodeIfVersion(tx interface{}, namespace string, node *specV1.Node, version string) (bool, error) {
+	defer utils.Trace(c.log.Debug, "UpdateNodeIfVersion")()
+	n, err := fromNodeModel(node)
+	if err != nil {
+		return false, err
+	}
+	// the update is rejected by the apiserver if the resource version is stale
+	n.ResourceVersion = version
+	n, err = c.customClient.CloudV1alpha1().Nodes(namespace).Update(c.ctx, n, metav1.UpdateOptions{})
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	node.Version = n.ResourceVersion
+	return true, nil
+}
+
 func (c *client) DeleteNode(tx interface{}, namespace, name string) error {
 	defer utils.Trace(c.log.Debug, "DeleteNode")()
 	return c.customClient.CloudV1alpha1().Nodes(namespace).Delete(c.ctx, name, metav1.DeleteOptions{})
@@ -214,6 +235,23 @@ func (c *client) ListNode(tx interface{}, namespace string, listOptions *models.
 	}
 	listOptions.Continue = list.Continue
 	res := toNodeListModel(list)
+	sortOptions := listOptions
+	if listOptions.CreateSort != "" {
+		// the nodes sorted by createSort are reversed by the service if it's ascending
+		sortOptions = &models.ListOptions{SortBy: models.SortByCreateTime, Order: models.SortOrderDesc}
+	}
+	sortList(res.Items, sortOptions, func(n *specV1.Node) string { return n.Name },
+		func(n *specV1.Node, _ string) time.Time { return n.CreationTimestamp })
+	if listOptions.Tag != "" {
+		items := res.Items[:0]
+		for _, n := range res.Items {
+			if common.MatchNodeTags(n.Annotations, listOptions.Tag) {
+				items = append(items, n)
+			}
+		}
+		res.Items = items
+		res.Total = len(items)
+	}
 	res.ListOptions = listOptions
 	return res, nil
 }
@@ -107,7 +107,7 @@ func (c *client) CreateSecret(tx interface{}, namespace string, secretModel *spe
 	return c.toSecretModel(Secret), err
 }
 
-func (c *client) UpdateSecret(namespace string, secretMapModel *specV1.Secret) (*specV1.Secret, error) {
+func (c *client) UpdateSecret(_ any, namespace string, secretMapModel *specV1.Secret) (*specV1.Secret, error) {
 	model, err := c.fromSecretModel(secretMapModel)
 	if err != nil {
 		return nil, err
@@ -121,14 +121,14 @@ func TestUpdateSecret(t *testing.T) {
 			"service.yml": []byte("test"),
 		},
 	}
-	cfg2, err := c.UpdateSecret(cfg.Namespace, cfg)
+	cfg2, err := c.UpdateSecret(nil, cfg.Namespace, cfg)
 	assert.NoError(t, err)
 	assert.Equal(t, cfg.Name, cfg2.Name)
 	v, _ := cfg2.Data["service.yml"]
 	assert.Equal(t, v, []byte("test"))
 
 	cfg.Name = cfg.Name + "NULL"
-	_, err = c.UpdateSecret(cfg.Namespace, cfg)
+	_, err = c.UpdateSecret(nil, cfg.Namespace, cfg)
 	assert.NotNil(t, err)
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/baetyl/baetyl-go/v2/utils"
 	"github.com/gin-gonic/gin"
 
+	"github.com/baetyl/baetyl-cloud/v2/api"
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/server"
@@ -61,19 +62,18 @@ func NewHTTPLink() (plugin.Plugin, error) {
 		svr.TLSConfig = t
 	}
 
-	if svr.TLSConfig == nil {
-		server.HeaderCommonName = cfg.HTTPLink.CommonName
-		router.Use(server.ExtractNodeCommonNameFromHeader)
-	} else {
-		router.Use(server.ExtractNodeCommonNameFromCert)
-	}
-
 	link := &httpLink{
 		cfg:       &cfg,
 		router:    router,
 		svr:       svr,
 		msgRouter: map[string]interface{}{},
 	}
+	if svr.TLSConfig == nil {
+		server.HeaderCommonName = cfg.HTTPLink.CommonName
+		router.Use(link.extractNode(server.ExtractNodeCommonNameFromHeader))
+	} else {
+		router.Use(link.extractNode(server.ExtractNodeCommonNameFromCert))
+	}
 	link.initRouter()
 	link.setPortFromEnv()
 	return link, nil
@@ -115,6 +115,18 @@ func (l *httpLink) initRouter() {
 	}
 }
 
+// extractNode extracts the node of the node token if the request carries one, otherwise by the fallback
+func (l *httpLink) extractNode(fallback gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		check, ok := l.msgRouter[server.MsgRouterNodeToken].(server.NodeTokenChecker)
+		if !ok || !api.IsNodeToken(c.GetHeader("Authorization")) {
+			fallback(c)
+			return
+		}
+		server.ExtractNodeFromToken(check)(c)
+	}
+}
+
 func (l *httpLink) setPortFromEnv() {
 	nodePort := os.Getenv(HTTPLinkPort)
 	if nodePort != "" {
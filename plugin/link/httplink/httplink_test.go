@@ -1,6 +1,9 @@
 package httplink
 
 import (
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"testing"
@@ -9,8 +12,10 @@ import (
 	"github.com/baetyl/baetyl-go/v2/http"
 	"github.com/baetyl/baetyl-go/v2/json"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/baetyl/baetyl-cloud/v2/api"
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/server"
@@ -239,3 +244,41 @@ func TestNewHTTPLink(t *testing.T) {
 	assert.Equal(t, deviceDesiretMsg.Kind, drResp.Kind)
 	assert.EqualValues(t, deviceDesiretMsg.Content.Value, data)
 }
+
+func TestHTTPLink_ExtractNode(t *testing.T) {
+	l := &httpLink{msgRouter: map[string]interface{}{}}
+	r := gin.New()
+	r.Use(l.extractNode(server.ExtractNodeCommonNameFromHeader))
+	r.POST("/v1/sync/report", func(c *gin.Context) {
+		cc := common.NewContext(c)
+		c.String(nethttp.StatusOK, cc.GetNamespace()+"."+cc.GetName())
+	})
+	post := func(headers map[string]string) *httptest.ResponseRecorder {
+		req, _ := nethttp.NewRequest(nethttp.MethodPost, "/v1/sync/report", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// the common name is used without the node token checker
+	w := post(map[string]string{server.HeaderCommonName: "default.n0", "Authorization": api.NodeTokenPrefix + "token"})
+	assert.Equal(t, "default.n0", w.Body.String())
+
+	l.msgRouter[server.MsgRouterNodeToken] = server.NodeTokenChecker(func(authorization string) (string, string, error) {
+		if authorization != "Bearer "+api.NodeTokenPrefix+"token" {
+			return "", "", fmt.Errorf("invalid")
+		}
+		return "default", "n1", nil
+	})
+	w = post(map[string]string{server.HeaderCommonName: "default.n0", "Authorization": "Bearer " + api.NodeTokenPrefix + "token"})
+	assert.Equal(t, "default.n1", w.Body.String())
+
+	w = post(map[string]string{server.HeaderCommonName: "default.n0", "Authorization": api.NodeTokenPrefix + "other"})
+	assert.Equal(t, nethttp.StatusUnauthorized, w.Code)
+
+	w = post(map[string]string{server.HeaderCommonName: "default.n0"})
+	assert.Equal(t, "default.n0", w.Body.String())
+}
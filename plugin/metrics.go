@@ -0,0 +1,18 @@
+package plugin
+
+import (
+	"io"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/metrics.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin MetricsStore
+
+// MetricsStore stores the time series of the resource usage of the nodes
+type MetricsStore interface {
+	WriteNodeStats(namespace, name string, point *models.NodeStatsPoint) error
+	// ListNodeStats returns the points within [from, to) in the order of time
+	ListNodeStats(namespace, name string, from, to time.Time) ([]models.NodeStatsPoint, error)
+	io.Closer
+}
@@ -12,6 +12,8 @@ type Node interface {
 	GetNode(tx interface{}, namespace, name string) (*v1.Node, error)
 	CreateNode(tx interface{}, namespace string, node *v1.Node) (*v1.Node, error)
 	UpdateNode(tx interface{}, namespace string, node []*v1.Node) ([]*v1.Node, error)
+	// UpdateNodeIfVersion updates the node only if it's still of the version, returns false if the node has been changed since then
+	UpdateNodeIfVersion(tx interface{}, namespace string, node *v1.Node, version string) (bool, error)
 	DeleteNode(tx interface{}, namespace, name string) error
 	ListNode(tx interface{}, namespace string, listOptions *models.ListOptions) (*models.NodeList, error)
 	CountAllNode(tx interface{}) (int, error)
@@ -0,0 +1,22 @@
+package plugin
+
+import (
+	"io"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/node_command.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin NodeCommand
+
+// NodeCommand stores the commands queued for the nodes
+type NodeCommand interface {
+	CreateNodeCommand(tx interface{}, command *models.NodeCommand) (*models.NodeCommand, error)
+	ListNodeCommand(tx interface{}, namespace, node string, filter *models.Filter) (*models.NodeCommandList, error)
+	// CountNodeCommand counts the commands of the node created since the time
+	CountNodeCommand(tx interface{}, namespace, node string, since time.Time) (int, error)
+	// ListPendingNodeCommand lists the pending commands of the node created since the time, the earliest first
+	ListPendingNodeCommand(tx interface{}, namespace, node string, since time.Time) ([]models.NodeCommand, error)
+	UpdateNodeCommandStatus(tx interface{}, namespace, node string, id int64, status, message string) error
+	io.Closer
+}
@@ -0,0 +1,19 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/node_group.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin NodeGroup
+
+// NodeGroup stores the node groups defined by label selector
+type NodeGroup interface {
+	GetNodeGroup(tx interface{}, namespace, name string) (*models.NodeGroup, error)
+	ListNodeGroup(tx interface{}, namespace string, listOptions *models.ListOptions) (*models.NodeGroupList, error)
+	CreateNodeGroup(tx interface{}, group *models.NodeGroup) (*models.NodeGroup, error)
+	UpdateNodeGroup(tx interface{}, group *models.NodeGroup) (*models.NodeGroup, error)
+	DeleteNodeGroup(tx interface{}, namespace, name string) error
+	io.Closer
+}
@@ -0,0 +1,17 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/node_log.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin NodeLog
+
+// NodeLog the connection to the agents of the nodes, which streams the logs of the apps running on the nodes
+type NodeLog interface {
+	// StreamAppLogs opens the log stream of the app on the node, which ends after the logs are read out if not follow,
+	// the stream is closed by the caller, and closing it stops the agent from sending more logs
+	StreamAppLogs(namespace, node, app string, options *models.AppLogOptions) (io.ReadCloser, error)
+	io.Closer
+}
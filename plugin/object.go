@@ -6,7 +6,7 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
-//go:generate mockgen -destination=../mock/plugin/object.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Object
+//go:generate mockgen -destination=../mock/plugin/object.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Object,ObjectPresigner,ObjectLifecycle
 
 // Object Object
 // TODO: userID doesn't belong to Object, should in the metedata
@@ -40,3 +40,19 @@ type Object interface {
 
 	io.Closer
 }
+
+// ObjectPresigner is implemented by the object sources able to sign the urls with options
+type ObjectPresigner interface {
+	PresignInternalObjectURL(userID, bucket, name string, opts *models.ObjectURLOptions) (*models.ObjectURL, error)
+	PresignInternalPutObjectURL(userID, bucket, name string, opts *models.ObjectURLOptions) (*models.ObjectURL, error)
+	PresignExternalObjectURL(info models.ExternalObjectInfo, bucket, name string, opts *models.ObjectURLOptions) (*models.ObjectURL, error)
+}
+
+// ObjectLifecycle is implemented by the object sources supporting the lifecycle rules of the buckets
+type ObjectLifecycle interface {
+	GetInternalBucketLifecycle(userID, bucket string) (*models.BucketLifecycle, error)
+	// PutInternalBucketLifecycle replaces the rules of the bucket, the rules are removed if empty
+	PutInternalBucketLifecycle(userID, bucket string, lifecycle *models.BucketLifecycle) error
+	GetExternalBucketLifecycle(info models.ExternalObjectInfo, bucket string) (*models.BucketLifecycle, error)
+	PutExternalBucketLifecycle(info models.ExternalObjectInfo, bucket string, lifecycle *models.BucketLifecycle) error
+}
@@ -0,0 +1,19 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/object_upload.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin ObjectUploadHook
+
+// ObjectUploadHook checks the objects uploaded to the internal storage
+type ObjectUploadHook interface {
+	// BeforeUpload is called before the put url is signed, the upload is rejected if an error is returned
+	BeforeUpload(upload *models.ObjectUpload) error
+	// AfterUpload is called after the object is uploaded, such as to scan it by an external scanner,
+	// and returns whether the object is accepted, rejected or quarantined
+	AfterUpload(upload *models.ObjectUpload, meta *models.ObjectMeta) (*models.ObjectUploadVerdict, error)
+	io.Closer
+}
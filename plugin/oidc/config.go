@@ -0,0 +1,41 @@
+package oidc
+
+import "time"
+
+// CloudConfig baetyl-cloud config
+type CloudConfig struct {
+	OIDC Config `yaml:"oidc" json:"oidc"`
+}
+
+// Config the issuer trusted to authenticate the bearer tokens, and how the claims are mapped to the users
+type Config struct {
+	Issuer string `yaml:"issuer" json:"issuer" binding:"nonzero"`
+	// JWKSURL the url of the keys of the issuer, it is discovered from the openid configuration of the issuer if not set
+	JWKSURL string `yaml:"jwksUrl" json:"jwksUrl"`
+	// Audience the tokens are required to be issued to if set
+	Audience string `yaml:"audience" json:"audience"`
+	// Leeway the clock skew tolerated when checking the expiry of the tokens
+	Leeway time.Duration `yaml:"leeway" json:"leeway" default:"1m"`
+	// RefreshInterval how often the keys are fetched again, they are also fetched on an unknown key id at most once per MinRefreshInterval
+	RefreshInterval    time.Duration `yaml:"refreshInterval" json:"refreshInterval" default:"1h"`
+	MinRefreshInterval time.Duration `yaml:"minRefreshInterval" json:"minRefreshInterval" default:"30s"`
+	Timeout            time.Duration `yaml:"timeout" json:"timeout" default:"10s"`
+	// DefaultNamespace the namespace of the users whose tokens carry no namespace claim, the tokens are rejected if not set
+	DefaultNamespace string `yaml:"defaultNamespace" json:"defaultNamespace"`
+	Claims           Claims `yaml:"claims" json:"claims"`
+	Roles            Roles  `yaml:"roles" json:"roles"`
+}
+
+// Claims the names of the claims mapped to the users, the nested claims are named by dotted paths such as realm_access.roles
+type Claims struct {
+	Namespace string `yaml:"namespace" json:"namespace" default:"namespace"`
+	User      string `yaml:"user" json:"user" default:"sub"`
+	Name      string `yaml:"name" json:"name" default:"preferred_username"`
+	Roles     string `yaml:"roles" json:"roles" default:"roles"`
+}
+
+// Roles the roles granting the permissions, all authenticated users are granted full control if none is configured
+type Roles struct {
+	Read []string `yaml:"read" json:"read" default:"[]"`
+	Full []string `yaml:"full" json:"full" default:"[]"`
+}
@@ -0,0 +1,183 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+)
+
+const maxResponseSize = 1 << 20
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet caches the keys of the issuer by the key ids, the keys are fetched again periodically and
+// on an unknown key id so that the rotated keys are picked up
+type keySet struct {
+	cfg    Config
+	client *http.Client
+
+	mu        sync.Mutex
+	jwksURL   string
+	keys      map[string]crypto.PublicKey
+	fetchTime time.Time
+}
+
+func newKeySet(cfg Config) *keySet {
+	return &keySet{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		jwksURL: cfg.JWKSURL,
+	}
+}
+
+// get returns the key of the id, the only key is returned for the tokens without key id
+func (s *keySet) get(kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.keys == nil || now.Sub(s.fetchTime) > s.cfg.RefreshInterval {
+		if err := s.fetch(now); err != nil && s.keys == nil {
+			return nil, err
+		}
+	}
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	// the keys may be rotated
+	if now.Sub(s.fetchTime) > s.cfg.MinRefreshInterval {
+		if err := s.fetch(now); err != nil {
+			return nil, err
+		}
+		if key, ok := s.lookup(kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("the key (%s) of the token is unknown", kid)
+}
+
+func (s *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (s *keySet) fetch(now time.Time) error {
+	s.fetchTime = now
+	if s.jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := s.getJSON(strings.TrimSuffix(s.cfg.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return err
+		}
+		if discovery.JWKSURI == "" {
+			return errors.New("the jwks_uri is missing in the openid configuration of the issuer")
+		}
+		s.jwksURL = discovery.JWKSURI
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := s.getJSON(s.jwksURL, &set); err != nil {
+		return err
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	s.keys = keys
+	return nil
+}
+
+func (s *keySet) getJSON(url string, v interface{}) error {
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get %s with status code %d", url, resp.StatusCode)
+	}
+	return errors.Trace(json.Unmarshal(data, v))
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("the curve (%s) is not supported", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("the key type (%s) is not supported", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,138 @@
+package oidc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+// oidcAuth authenticates the bearer tokens issued by an OpenID Connect provider, the namespace, the user and
+// the roles of the requests are mapped from the claims of the tokens
+type oidcAuth struct {
+	cfg  Config
+	keys *keySet
+	log  *log.Logger
+}
+
+func init() {
+	plugin.RegisterFactory("oidc", New)
+}
+
+// New New
+func New() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newOIDCAuth(cfg.OIDC), nil
+}
+
+func newOIDCAuth(cfg Config) *oidcAuth {
+	return &oidcAuth{
+		cfg:  cfg,
+		keys: newKeySet(cfg),
+		log:  log.With(log.Any("plugin", "oidc")),
+	}
+}
+
+func (o *oidcAuth) Authenticate(c *common.Context) error {
+	auth := c.GetHeader("Authorization")
+	if len(auth) < 7 || !strings.EqualFold(auth[:7], "bearer ") {
+		return common.Error(common.ErrRequestAccessDenied, common.Field("error", "the bearer token is missing"))
+	}
+	cl, err := o.parseToken(strings.TrimSpace(auth[7:]), time.Now())
+	if err != nil {
+		return common.Error(common.ErrRequestAccessDenied, common.Field("error", err.Error()))
+	}
+	ns := cl.getString(o.cfg.Claims.Namespace)
+	if ns == "" {
+		ns = o.cfg.DefaultNamespace
+	}
+	if ns == "" {
+		return common.Error(common.ErrRequestAccessDenied, common.Field("error", "the namespace claim of the token is missing"))
+	}
+	user := common.User{ID: cl.getString(o.cfg.Claims.User), Name: cl.getString(o.cfg.Claims.Name)}
+	if user.Name == "" {
+		user.Name = user.ID
+	}
+	var roles []common.Role
+	for _, r := range cl.getStrings(o.cfg.Claims.Roles) {
+		roles = append(roles, common.Role{ID: r, Type: "oidc"})
+	}
+	c.SetNamespace(ns)
+	c.SetUser(user)
+	c.SetUserInfo(common.UserInfo{
+		User:   user,
+		Roles:  roles,
+		Domain: common.Domain{ID: ns, Name: ns},
+	})
+	return nil
+}
+
+// TokenExpiry returns the expiry claim of the bearer token, which is verified by Authenticate already
+func (o *oidcAuth) TokenExpiry(c *common.Context) time.Time {
+	auth := c.GetHeader("Authorization")
+	if len(auth) < 7 {
+		return time.Now()
+	}
+	parts := strings.Split(strings.TrimSpace(auth[7:]), ".")
+	var cl claims
+	if len(parts) != 3 || decodeSegment(parts[1], &cl) != nil {
+		return time.Now()
+	}
+	exp, ok := cl["exp"].(float64)
+	if !ok {
+		return time.Now()
+	}
+	return time.Unix(int64(exp), 0)
+}
+
+func (o *oidcAuth) AuthAndVerify(c *common.Context, pr *plugin.PermissionRequest) error {
+	if err := o.Authenticate(c); err != nil {
+		return err
+	}
+	return o.Verify(c, pr)
+}
+
+// Verify grants the roles of Roles.Full all permissions, and the roles of Roles.Read the READ permission
+func (o *oidcAuth) Verify(c *common.Context, pr *plugin.PermissionRequest) error {
+	if len(o.cfg.Roles.Read) == 0 && len(o.cfg.Roles.Full) == 0 {
+		return nil
+	}
+	roles := c.GetUserInfo().Roles
+	if hasRole(roles, o.cfg.Roles.Full) {
+		return nil
+	}
+	readOnly := true
+	for _, p := range pr.Permission {
+		if p != plugin.PermissionRead {
+			readOnly = false
+		}
+	}
+	if readOnly && hasRole(roles, o.cfg.Roles.Read) {
+		return nil
+	}
+	return common.Error(common.ErrRequestAccessDenied, common.Field("error",
+		"the roles of the user are not granted the permission of "+strings.Join(pr.Permission, ",")+" on "+pr.Resource))
+}
+
+// Close Close
+func (o *oidcAuth) Close() error {
+	return nil
+}
+
+func hasRole(roles []common.Role, granted []string) bool {
+	for _, r := range roles {
+		for _, g := range granted {
+			if r.ID == g {
+				return true
+			}
+		}
+	}
+	return false
+}
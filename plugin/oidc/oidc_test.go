@@ -0,0 +1,249 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+type testIssuer struct {
+	server  *httptest.Server
+	keys    []jwk
+	fetches int32
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	iss := &testIssuer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := json.Marshal(map[string]string{"issuer": iss.server.URL, "jwks_uri": iss.server.URL + "/keys"})
+		w.Write(data)
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&iss.fetches, 1)
+		data, _ := json.Marshal(map[string]interface{}{"keys": iss.keys})
+		w.Write(data)
+	})
+	iss.server = httptest.NewServer(mux)
+	t.Cleanup(iss.server.Close)
+	return iss
+}
+
+func (iss *testIssuer) addRSAKey(t *testing.T, kid string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	iss.keys = append(iss.keys, jwk{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	})
+	return key
+}
+
+func (iss *testIssuer) addECKey(t *testing.T, kid string) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	iss.keys = append(iss.keys, jwk{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	})
+	return key
+}
+
+func signToken(t *testing.T, alg, kid string, key crypto.Signer, cl map[string]interface{}) string {
+	h, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	p, _ := json.Marshal(cl)
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(p)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(input))
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest.Sum(nil))
+		assert.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest.Sum(nil))
+		assert.NoError(t, err)
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func newTestContext(token string) *common.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodGet, "/v1/nodes", nil)
+	if token != "" {
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+	}
+	return common.NewContext(c)
+}
+
+func newTestConfig(issuer string) Config {
+	return Config{
+		Issuer:             issuer,
+		Audience:           "baetyl-cloud",
+		Leeway:             time.Minute,
+		RefreshInterval:    time.Hour,
+		MinRefreshInterval: 0,
+		Timeout:            time.Second,
+		Claims: Claims{
+			Namespace: "namespace",
+			User:      "sub",
+			Name:      "preferred_username",
+			Roles:     "realm_access.roles",
+		},
+	}
+}
+
+func assertAccessDenied(t *testing.T, err error, msg string) {
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrRequestAccessDenied, e.Code())
+	assert.Contains(t, err.Error(), msg)
+}
+
+func TestAuthenticate(t *testing.T) {
+	iss := newTestIssuer(t)
+	rsaKey := iss.addRSAKey(t, "k1")
+	ecKey := iss.addECKey(t, "k2")
+	auth := newOIDCAuth(newTestConfig(iss.server.URL))
+
+	now := time.Now()
+	cl := map[string]interface{}{
+		"iss":                iss.server.URL,
+		"aud":                []string{"baetyl-cloud", "other"},
+		"sub":                "u-1",
+		"preferred_username": "alice",
+		"namespace":          "team-a",
+		"realm_access":       map[string]interface{}{"roles": []string{"viewer", "admin"}},
+		"exp":                now.Add(time.Hour).Unix(),
+	}
+	c := newTestContext(signToken(t, "RS256", "k1", rsaKey, cl))
+	assert.NoError(t, auth.Authenticate(c))
+	assert.Equal(t, "team-a", c.GetNamespace())
+	assert.Equal(t, common.User{ID: "u-1", Name: "alice"}, c.GetUser())
+	assert.Equal(t, []common.Role{{ID: "viewer", Type: "oidc"}, {ID: "admin", Type: "oidc"}}, c.GetUserInfo().Roles)
+
+	c = newTestContext(signToken(t, "ES256", "k2", ecKey, cl))
+	assert.NoError(t, auth.Authenticate(c))
+	assert.Equal(t, "team-a", c.GetNamespace())
+	// the results are cached until the token expires
+	assert.Equal(t, now.Add(time.Hour).Unix(), auth.TokenExpiry(c).Unix())
+	assert.False(t, auth.TokenExpiry(newTestContext("abc")).After(time.Now()))
+	// the keys are discovered and fetched once
+	assert.Equal(t, int32(1), atomic.LoadInt32(&iss.fetches))
+
+	assertAccessDenied(t, auth.Authenticate(newTestContext("")), "the bearer token is missing")
+	assertAccessDenied(t, auth.Authenticate(newTestContext("abc")), "the token is malformed")
+
+	// signed by another key
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	assertAccessDenied(t, auth.Authenticate(newTestContext(signToken(t, "RS256", "k1", other, cl))), "the signature of the token is invalid")
+	// the algorithm doesn't match the key
+	assertAccessDenied(t, auth.Authenticate(newTestContext(signToken(t, "ES256", "k1", ecKey, cl))), "the signature of the token is invalid")
+	assertAccessDenied(t, auth.Authenticate(newTestContext(signToken(t, "HS256", "k1", rsaKey, cl))), "the signing algorithm (HS256) is not supported")
+
+	expired := copyClaims(cl)
+	expired["exp"] = now.Add(-2 * time.Minute).Unix()
+	assertAccessDenied(t, auth.Authenticate(newTestContext(signToken(t, "RS256", "k1", rsaKey, expired))), "the token is expired")
+	// within the leeway
+	expired["exp"] = now.Add(-30 * time.Second).Unix()
+	assert.NoError(t, auth.Authenticate(newTestContext(signToken(t, "RS256", "k1", rsaKey, expired))))
+
+	untrusted := copyClaims(cl)
+	untrusted["iss"] = "https://evil.example.com"
+	assertAccessDenied(t, auth.Authenticate(newTestContext(signToken(t, "RS256", "k1", rsaKey, untrusted))), "is not trusted")
+
+	aud := copyClaims(cl)
+	aud["aud"] = "other"
+	assertAccessDenied(t, auth.Authenticate(newTestContext(signToken(t, "RS256", "k1", rsaKey, aud))), "the token is not issued to the audience")
+
+	noNS := copyClaims(cl)
+	delete(noNS, "namespace")
+	assertAccessDenied(t, auth.Authenticate(newTestContext(signToken(t, "RS256", "k1", rsaKey, noNS))), "the namespace claim of the token is missing")
+	auth.cfg.DefaultNamespace = "default"
+	c = newTestContext(signToken(t, "RS256", "k1", rsaKey, noNS))
+	assert.NoError(t, auth.Authenticate(c))
+	assert.Equal(t, "default", c.GetNamespace())
+}
+
+func TestKeyRotation(t *testing.T) {
+	iss := newTestIssuer(t)
+	iss.addRSAKey(t, "k1")
+	cfg := newTestConfig(iss.server.URL)
+	cfg.JWKSURL = iss.server.URL + "/keys"
+	cfg.MinRefreshInterval = time.Hour
+	auth := newOIDCAuth(cfg)
+	cl := map[string]interface{}{"iss": iss.server.URL, "aud": "baetyl-cloud", "namespace": "default", "exp": time.Now().Add(time.Hour).Unix()}
+
+	// the keys fetched recently are not fetched again on an unknown key id
+	_, err := auth.keys.get("k1")
+	assert.NoError(t, err)
+	key2 := iss.addRSAKey(t, "k2")
+	assertAccessDenied(t, auth.Authenticate(newTestContext(signToken(t, "RS256", "k2", key2, cl))), "the key (k2) of the token is unknown")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&iss.fetches))
+
+	// the rotated key is picked up once the keys can be fetched again
+	auth.keys.cfg.MinRefreshInterval = 0
+	assert.NoError(t, auth.Authenticate(newTestContext(signToken(t, "RS256", "k2", key2, cl))))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&iss.fetches))
+
+	// the cached keys are kept if the issuer is unavailable
+	iss.server.Close()
+	auth.keys.fetchTime = time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, auth.Authenticate(newTestContext(signToken(t, "RS256", "k2", key2, cl))))
+}
+
+func TestVerify(t *testing.T) {
+	auth := newOIDCAuth(newTestConfig("https://sso.example.com"))
+	c := newTestContext("")
+	c.SetUserInfo(common.UserInfo{Roles: []common.Role{{ID: "viewer"}}})
+	read := &plugin.PermissionRequest{Resource: plugin.PermissionResourceNode, Permission: []string{plugin.PermissionRead}}
+	full := &plugin.PermissionRequest{Resource: plugin.PermissionResourceNode, Permission: []string{plugin.PermissionFull}}
+
+	// all users are granted if no role is configured
+	assert.NoError(t, auth.Verify(c, full))
+
+	auth.cfg.Roles = Roles{Read: []string{"viewer"}, Full: []string{"admin"}}
+	assert.NoError(t, auth.Verify(c, read))
+	assertAccessDenied(t, auth.Verify(c, full), "FULL_CONTROL on node")
+
+	c.SetUserInfo(common.UserInfo{Roles: []common.Role{{ID: "admin"}}})
+	assert.NoError(t, auth.Verify(c, read))
+	assert.NoError(t, auth.Verify(c, full))
+
+	c.SetUserInfo(common.UserInfo{})
+	assertAccessDenied(t, auth.Verify(c, read), "READ on node")
+}
+
+func copyClaims(cl map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	for k, v := range cl {
+		res[k] = v
+	}
+	return res
+}
@@ -0,0 +1,188 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+)
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type claims map[string]interface{}
+
+// parseToken verifies the signature of the token by the key of the issuer and checks the standard claims
+func (o *oidcAuth) parseToken(token string, now time.Time) (claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("the token is malformed")
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, err
+	}
+	hash, ok := signingHashes[h.Alg]
+	if !ok {
+		return nil, fmt.Errorf("the signing algorithm (%s) is not supported", h.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("the signature of the token is malformed")
+	}
+	key, err := o.keys.get(h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(parts[0] + "." + parts[1]))
+	if err = verifySignature(h.Alg, key, hash, hasher.Sum(nil), sig); err != nil {
+		return nil, err
+	}
+
+	var c claims
+	if err = decodeSegment(parts[1], &c); err != nil {
+		return nil, err
+	}
+	if iss, _ := c["iss"].(string); iss != o.cfg.Issuer {
+		return nil, fmt.Errorf("the issuer (%s) of the token is not trusted", iss)
+	}
+	if o.cfg.Audience != "" && !c.hasAudience(o.cfg.Audience) {
+		return nil, errors.New("the token is not issued to the audience")
+	}
+	exp, ok := c["exp"].(float64)
+	if !ok {
+		return nil, errors.New("the expiry of the token is missing")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(o.cfg.Leeway)) {
+		return nil, errors.New("the token is expired")
+	}
+	if nbf, ok := c["nbf"].(float64); ok && now.Add(o.cfg.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return nil, errors.New("the token is not valid yet")
+	}
+	return c, nil
+}
+
+var signingHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"PS256": crypto.SHA256,
+	"PS384": crypto.SHA384,
+	"PS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+func verifySignature(alg string, key crypto.PublicKey, hash crypto.Hash, digest, sig []byte) error {
+	invalid := errors.New("the signature of the token is invalid")
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		var err error
+		switch alg[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(k, hash, digest, sig)
+		case "PS":
+			err = rsa.VerifyPSS(k, hash, digest, sig, nil)
+		default:
+			return invalid
+		}
+		if err != nil {
+			return invalid
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if alg[:2] != "ES" || len(sig) != 2*size {
+			return invalid
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return invalid
+		}
+		return nil
+	}
+	return invalid
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return errors.New("the token is malformed")
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return errors.New("the token is malformed")
+	}
+	return nil
+}
+
+func (c claims) hasAudience(aud string) bool {
+	switch v := c["aud"].(type) {
+	case string:
+		return v == aud
+	case []interface{}:
+		for _, a := range v {
+			if a == aud {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// get returns the claim of the dotted path
+func (c claims) get(path string) interface{} {
+	var cur interface{} = map[string]interface{}(c)
+	for _, p := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[p]
+	}
+	return cur
+}
+
+func (c claims) getString(path string) string {
+	if path == "" {
+		return ""
+	}
+	switch v := c.get(path).(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// getStrings returns the claim as a list, a string claim is split by spaces such as the scope claim
+func (c claims) getStrings(path string) []string {
+	if path == "" {
+		return nil
+	}
+	switch v := c.get(path).(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		res := make([]string, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				res = append(res, str)
+			}
+		}
+		return res
+	}
+	return nil
+}
@@ -43,6 +43,11 @@ type PKI interface {
 	io.Closer
 }
 
+// PKIValidity is implemented by the pkis which can sign the client certificates with the validity asked for
+type PKIValidity interface {
+	CreateClientCertWithValidity(csr []byte, rootId string, validity time.Duration) (string, error)
+}
+
 type PKIStorage interface {
 	CreateCert(cert Cert) error
 	DeleteCert(certId string) error
@@ -5,9 +5,13 @@ import "io"
 //go:generate mockgen -destination=../mock/plugin/quota.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Quota
 
 const (
-	QuotaNode  = "maxNodeCount"
-	QuotaBatch = "maxBatchCount"
-	MenuEnable = "menuEnable"
+	QuotaNode   = "maxNodeCount"
+	QuotaBatch  = "maxBatchCount"
+	QuotaApp    = "maxAppCount"
+	QuotaConfig = "maxConfigCount"
+	// QuotaStorage the total bytes of the data of the configs and secrets created by user
+	QuotaStorage = "maxStorageBytes"
+	MenuEnable   = "menuEnable"
 )
 
 type QuotaCollector func(namespace string) (map[string]int, error)
@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"io"
 	"strings"
 	"sync"
@@ -15,6 +16,11 @@ type Plugin interface {
 	io.Closer
 }
 
+// HealthChecker is implemented by the plugins able to check the backend they depend on
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
+
 // Factory create engine by given config
 type Factory func() (Plugin, error)
 
@@ -0,0 +1,18 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/registry_refresh.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin RegistryRefresh
+
+// RegistryRefresh stores the result of the last refresh of the password of each registry
+type RegistryRefresh interface {
+	// SaveRegistryRefresh replaces the result of the registry
+	SaveRegistryRefresh(tx interface{}, refresh *models.RegistryRefresh) error
+	GetRegistryRefresh(tx interface{}, namespace, name string) (*models.RegistryRefresh, error)
+	DeleteRegistryRefresh(tx interface{}, namespace, name string) error
+	io.Closer
+}
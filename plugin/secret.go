@@ -11,7 +11,7 @@ import (
 type Secret interface {
 	GetSecret(tx interface{}, namespace, name, version string) (*v1.Secret, error)
 	CreateSecret(tx interface{}, namespace string, secretModel *v1.Secret) (*v1.Secret, error)
-	UpdateSecret(namespace string, secretMapModel *v1.Secret) (*v1.Secret, error)
+	UpdateSecret(tx interface{}, namespace string, secretMapModel *v1.Secret) (*v1.Secret, error)
 	DeleteSecret(tx interface{}, namespace, name string) error
 	ListSecret(namespace string, listOptions *models.ListOptions) (*models.SecretList, error)
 }
@@ -0,0 +1,21 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/webhook.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Webhook
+
+// Webhook stores the webhook subscriptions and the payloads failed to be delivered
+type Webhook interface {
+	GetWebhook(tx interface{}, namespace, name string) (*models.Webhook, error)
+	ListWebhook(tx interface{}, namespace string, listOptions *models.ListOptions) (*models.WebhookList, error)
+	CreateWebhook(tx interface{}, webhook *models.Webhook) (*models.Webhook, error)
+	UpdateWebhook(tx interface{}, webhook *models.Webhook) (*models.Webhook, error)
+	DeleteWebhook(tx interface{}, namespace, name string) error
+	CreateWebhookDeadLetter(tx interface{}, letter *models.WebhookDeadLetter) error
+	ListWebhookDeadLetter(tx interface{}, namespace, webhook string, filter *models.Filter) (*models.WebhookDeadLetterList, error)
+	io.Closer
+}
@@ -2,6 +2,7 @@ $Addr="{{GetProperty "init-server-address"}}"
 $DeployYaml="{{.InitApplyYaml}}"
 $DbPath='{{.DBPath}}'
 $Token="{{.Token}}"
+$NodeToken="{{.NodeToken}}"
 $Mode='{{.Mode}}'
 
 function Check-User {
@@ -24,12 +25,16 @@ function Remove-DbFile {
 }
 
 function Install-Baetyl {
+    $Query = "token=$Token"
+    if ($NodeToken) {
+        $Query = "$Query&nodeToken=$NodeToken"
+    }
     Remove-DbFile
     if ($Mode -eq "native") {
         Write-Host "baetyl install in native mode"
         if (Get-Command baetyl -ErrorAction SilentlyContinue) {
             baetyl delete
-            baetyl apply -f "$Addr/v1/init/$($DeployYaml)?token=$Token" --skip-verify=true
+            baetyl apply -f "$Addr/v1/init/$($DeployYaml)?$Query" --skip-verify=true
         } else {
             Write-Warning "baetyl not installed yet, please install baetyl firstly"
             Break Script
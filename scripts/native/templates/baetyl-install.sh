@@ -4,6 +4,7 @@ ADDR="{{GetProperty "init-server-address"}}"
 DEPLOYYML="{{.InitApplyYaml}}"
 DB_PATH='{{.DBPath}}'
 TOKEN="{{.Token}}"
+NODE_TOKEN="{{.NodeToken}}"
 MODE='{{.Mode}}'
 SUDO=sudo
 
@@ -53,15 +54,19 @@ kube_apply() {
 }
 
 install_baetyl() {
+  QUERY="token=$TOKEN"
+  if [ -n "$NODE_TOKEN" ]; then
+    QUERY="$QUERY&nodeToken=$NODE_TOKEN"
+  fi
   dbfile_clean
   if [ $MODE = "kube" ]; then
     print_status "baetyl install in k8s mode"
     kube_clean
-    kube_apply "$ADDR/v1/init/$DEPLOYYML?token=$TOKEN"
+    kube_apply "$ADDR/v1/init/$DEPLOYYML?$QUERY"
   elif [ $MODE = "native" ]; then
     print_status "baetyl install in native mode"
     exec_cmd_nobail "baetyl delete" $SUDO
-    exec_cmd_nobail "baetyl apply -f '$ADDR/v1/init/$DEPLOYYML?token=$TOKEN' --skip-verify=true" $SUDO
+    exec_cmd_nobail "baetyl apply -f '$ADDR/v1/init/$DEPLOYYML?$QUERY' --skip-verify=true" $SUDO
   else
     print_status "Not supported install mode $MODE"
     exit 0
@@ -145,6 +145,8 @@ CREATE TABLE IF NOT EXISTS `baetyl_module` (
   `flag` int(10) NOT NULL DEFAULT '0' COMMENT '应用标识',
   `is_latest` int(1) NOT NULL DEFAULT '0' COMMENT '是否是最新版本',
   `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述',
+  `dependencies` varchar(2048) NOT NULL DEFAULT '' COMMENT '依赖的模块版本',
+  `create_user` varchar(128) NOT NULL DEFAULT '' COMMENT '创建者',
   `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '记录创建时间',
   `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '记录更新时间',
   PRIMARY KEY (`id`),
@@ -162,4 +164,222 @@ CREATE TABLE IF NOT EXISTS `baetyl_cron_app` (
   PRIMARY KEY (`id`),
   UNIQUE KEY `unique_name` (`namespace`,`name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='cron app table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_application_history` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '应用名称',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '应用版本',
+  `content` mediumtext NOT NULL COMMENT '应用内容',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'create time',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_version` (`namespace`,`name`,`version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='application history table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_node_group` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '节点组名称',
+  `selector` varchar(2048) NOT NULL DEFAULT '' COMMENT '节点标签选择器',
+  `labels` varchar(2048) NOT NULL DEFAULT '{}' COMMENT '标签，json格式字符串',
+  `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述信息',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='node group table';
+CREATE TABLE IF NOT EXISTS `baetyl_audit` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `user_id` varchar(128) NOT NULL DEFAULT '' COMMENT '用户ID',
+  `user_name` varchar(128) NOT NULL DEFAULT '' COMMENT '用户名称',
+  `resource` varchar(64) NOT NULL DEFAULT '' COMMENT '资源类型',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '资源名称',
+  `method` varchar(16) NOT NULL DEFAULT '' COMMENT '请求方法',
+  `path` varchar(1024) NOT NULL DEFAULT '' COMMENT '请求路径',
+  `body_hash` varchar(64) NOT NULL DEFAULT '' COMMENT '请求体sha256摘要',
+  `status` int(11) NOT NULL DEFAULT '0' COMMENT '响应状态码',
+  `request_id` varchar(64) NOT NULL DEFAULT '' COMMENT '请求ID',
+  `client_ip` varchar(64) NOT NULL DEFAULT '' COMMENT '客户端IP',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '请求时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_resource_time` (`namespace`,`resource`,`create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='audit table';
+CREATE TABLE IF NOT EXISTS `baetyl_application_trash` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '应用名称',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '应用版本',
+  `content` mediumtext NOT NULL COMMENT '应用内容',
+  `expire_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '过期时间',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`namespace`,`name`),
+  KEY `idx_expire_time` (`expire_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='application trash table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_resource_version` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `kind` varchar(32) NOT NULL DEFAULT '' COMMENT '资源类型，config或secret',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '资源名称',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '资源版本',
+  `content` mediumtext NOT NULL COMMENT '资源内容',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'create time',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_version` (`namespace`,`kind`,`name`,`version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='config and secret version table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_application_canary` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '应用名称',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '灰度版本',
+  `stable_version` varchar(36) NOT NULL DEFAULT '' COMMENT '稳定版本',
+  `percent` int(11) NOT NULL DEFAULT '0' COMMENT '灰度比例',
+  `nodes` mediumtext NOT NULL COMMENT '灰度节点',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='application canary table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_node_deploy_history` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `node` varchar(128) NOT NULL DEFAULT '' COMMENT '节点名称',
+  `action` varchar(16) NOT NULL DEFAULT '' COMMENT '部署或卸载',
+  `app` varchar(128) NOT NULL DEFAULT '' COMMENT '应用名称',
+  `system` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否系统应用',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '部署的应用版本',
+  `prev_version` varchar(36) NOT NULL DEFAULT '' COMMENT '部署前的应用版本',
+  `apps` mediumtext NOT NULL COMMENT '部署后的应用列表',
+  `sysapps` mediumtext NOT NULL COMMENT '部署后的系统应用列表',
+  `desire` mediumtext NOT NULL COMMENT '部署后的期望状态',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '部署时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_node` (`namespace`,`node`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='node deploy history table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_node_property_history` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `node` varchar(128) NOT NULL DEFAULT '' COMMENT '节点名称',
+  `property` varchar(128) NOT NULL DEFAULT '' COMMENT '属性名称',
+  `type` varchar(16) NOT NULL DEFAULT '' COMMENT '期望或上报',
+  `value` text NOT NULL COMMENT '变更后的属性值',
+  `actor` varchar(128) NOT NULL DEFAULT '' COMMENT '变更者',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '变更时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_node_property` (`namespace`,`node`,`property`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='node property history table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_event` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `kind` varchar(64) NOT NULL DEFAULT '' COMMENT '资源类型',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '资源名称',
+  `type` varchar(64) NOT NULL DEFAULT '' COMMENT '事件类型',
+  `message` varchar(1024) NOT NULL DEFAULT '' COMMENT '事件描述',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '事件时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_time` (`namespace`,`create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='event table';
+CREATE TABLE IF NOT EXISTS `baetyl_app_template` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '应用模板名称',
+  `labels` varchar(2048) NOT NULL DEFAULT '{}' COMMENT '标签，json格式字符串',
+  `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述信息',
+  `parameters` text NOT NULL COMMENT '参数定义，json格式字符串',
+  `template` mediumtext NOT NULL COMMENT '应用模板内容',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='app template table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_webhook` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT 'webhook名称',
+  `url` varchar(2048) NOT NULL DEFAULT '' COMMENT '推送地址',
+  `secret` varchar(1024) NOT NULL DEFAULT '' COMMENT '签名密钥，配置kms时加密存储',
+  `events` varchar(2048) NOT NULL DEFAULT '[]' COMMENT '订阅的事件类型，json格式字符串',
+  `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述信息',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='webhook table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_webhook_dead_letter` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `webhook` varchar(128) NOT NULL DEFAULT '' COMMENT 'webhook名称',
+  `event` varchar(64) NOT NULL DEFAULT '' COMMENT '事件类型',
+  `payload` text NOT NULL COMMENT '推送内容',
+  `attempts` int(11) NOT NULL DEFAULT '0' COMMENT '推送次数',
+  `error` varchar(1024) NOT NULL DEFAULT '' COMMENT '最后一次推送的错误',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_webhook` (`namespace`,`webhook`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='webhook dead letter table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_node_command` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `node` varchar(128) NOT NULL DEFAULT '' COMMENT '节点名称',
+  `type` varchar(64) NOT NULL DEFAULT '' COMMENT '命令类型',
+  `params` varchar(2048) NOT NULL DEFAULT '{}' COMMENT '命令参数，json格式字符串',
+  `status` varchar(32) NOT NULL DEFAULT '' COMMENT '命令状态',
+  `message` varchar(1024) NOT NULL DEFAULT '' COMMENT '节点上报的执行结果',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_node` (`namespace`,`node`,`create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='node command table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_app_schedule` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '应用名称',
+  `cron` varchar(128) NOT NULL DEFAULT '' COMMENT 'cron表达式',
+  `due_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '部署时间',
+  `application` mediumtext NOT NULL COMMENT '待部署的应用，json格式字符串',
+  `status` varchar(32) NOT NULL DEFAULT '' COMMENT '状态',
+  `message` varchar(1024) NOT NULL DEFAULT '' COMMENT '部署结果',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_name` (`namespace`,`name`),
+  KEY `idx_status_due_time` (`status`,`due_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='app schedule table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_registry_refresh` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '镜像仓库名称',
+  `result` varchar(32) NOT NULL DEFAULT '' COMMENT '刷新结果',
+  `message` varchar(1024) NOT NULL DEFAULT '' COMMENT '失败原因',
+  `attempts` int(11) NOT NULL DEFAULT '0' COMMENT '尝试次数',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '刷新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='registry refresh table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_job` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `type` varchar(64) NOT NULL DEFAULT '' COMMENT '任务类型',
+  `status` varchar(32) NOT NULL DEFAULT '' COMMENT '状态',
+  `progress` int(11) NOT NULL DEFAULT '0' COMMENT '进度百分比',
+  `message` varchar(1024) NOT NULL DEFAULT '' COMMENT '失败原因',
+  `result` mediumtext NOT NULL COMMENT '任务结果，json格式字符串',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace` (`namespace`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='async job table';
+
 COMMIT;
@@ -0,0 +1,9 @@
+-- The columns added to the existing tables since the installation, which tables.sql does not add to the tables
+-- already created. Run the statements of the columns the database does not have yet, in order, before upgrading.
+USE `baetyl_cloud`;
+
+-- the dependencies of the modules
+ALTER TABLE `baetyl_module` ADD COLUMN `dependencies` varchar(2048) NOT NULL DEFAULT '' COMMENT '依赖的模块版本' AFTER `description`;
+
+-- the creators of the module versions
+ALTER TABLE `baetyl_module` ADD COLUMN `create_user` varchar(128) NOT NULL DEFAULT '' COMMENT '创建者' AFTER `dependencies`;
@@ -1,9 +1,15 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/baetyl/baetyl-go/v2/cache"
@@ -15,6 +21,7 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/api"
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/service"
 )
@@ -27,19 +34,29 @@ type AdminServer struct {
 	ExternalHandlers []gin.HandlerFunc
 	APICache         persist.CacheStore
 
-	cfg    *config.CloudConfig
-	router *gin.Engine
-	server *http.Server
-	api    *api.API
-	log    *log.Logger
+	limiter  rateLimiter
+	readOnly *readOnlyState
+	drain    drainState
+	rbac     *rbacPolicy
+	health   service.HealthService
+	cfg      *config.CloudConfig
+	router   *gin.Engine
+	server   *http.Server
+	api      *api.API
+	log      *log.Logger
+
+	// the routes reading with other methods than GET
+	readRoutes map[string]bool
 }
 
 const (
-	DefaultAPICacheDuration = time.Second * 2
+	DefaultAPICacheDuration = time.Second * 30
 )
 
 var (
-	NodeCollector plugin.QuotaCollector
+	NodeCollector   plugin.QuotaCollector
+	AppCollector    plugin.QuotaCollector
+	ConfigCollector plugin.QuotaCollector
 )
 
 // NewAdminServer create admin server
@@ -59,7 +76,45 @@ func NewAdminServer(config *config.CloudConfig) (*AdminServer, error) {
 		return nil, err
 	}
 
-	router := gin.New()
+	apiCache, err := newAPICache(config.AdminServer.Cache)
+	if err != nil {
+		return nil, err
+	}
+
+	limiter, err := newRateLimiter(config.AdminServer.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	rbac, err := newRBACPolicy(config.AdminServer.RBAC)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkCORS(config.AdminServer.CORS); err != nil {
+		return nil, err
+	}
+
+	health, err := service.NewHealthService(config)
+	if err != nil {
+		return nil, err
+	}
+	if store, ok := apiCache.(*persist.RedisStore); ok {
+		health.AddCheck("apiCache", false, func(ctx context.Context) error {
+			return errors.Trace(store.RedisClient.Ping(ctx).Err())
+		})
+	}
+
+	if rl, ok := limiter.(*redisRateLimiter); ok {
+		health.AddCheck("rateLimit", false, func(ctx context.Context) error {
+			return errors.Trace(rl.cli.Ping(ctx).Err())
+		})
+	}
+
+	router, err := newRouter(config.AdminServer.Server)
+	if err != nil {
+		return nil, err
+	}
 	server := &http.Server{
 		Addr:           config.AdminServer.Port,
 		Handler:        router,
@@ -74,7 +129,11 @@ func NewAdminServer(config *config.CloudConfig) (*AdminServer, error) {
 		Auth:     auth,
 		License:  ls,
 		Quota:    qs,
-		APICache: persist.NewInMemoryStore(DefaultAPICacheDuration),
+		APICache: apiCache,
+		limiter:  limiter,
+		readOnly: newReadOnlyState(apiCache, config.AdminServer.ReadOnly),
+		rbac:     rbac,
+		health:   health,
 		log:      log.L().With(log.Any("server", "AdminServer")),
 	}, nil
 }
@@ -89,37 +148,74 @@ func (s *AdminServer) SetAPI(api *api.API) {
 	s.api = api
 }
 
-// Close server
+// Close server, the streaming requests are closed at once while the others are waited up to the shutdown time
 func (s *AdminServer) Close() {
-	ctx, _ := context.WithTimeout(context.Background(), s.cfg.AdminServer.ShutdownTime)
-	s.server.Shutdown(ctx)
+	streams := s.drain.closeStreams()
+	s.log.Info("admin server shutting down",
+		log.Any("inflight", atomic.LoadInt64(&s.drain.inflight)), log.Any("streams", streams))
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.AdminServer.ShutdownTime)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.log.Warn("admin server shut down with requests in flight",
+			log.Any("inflight", atomic.LoadInt64(&s.drain.inflight)), log.Error(err))
+	}
 }
 
 // InitRoute init router
 func (s *AdminServer) InitRoute() {
 	s.router.NoRoute(NoRouteHandler)
 	s.router.NoMethod(NoMethodHandler)
-	s.router.GET("/health", Health)
+	s.router.GET("/health", HealthCheck(s.health))
+	s.router.GET("/health/live", Health)
+	s.router.GET("/health/ready", Ready(s.health))
 	s.router.Use(RequestIDHandler)
+	s.router.Use(s.CORSHandler)
+	if s.cfg.Tracing.Enable {
+		s.router.Use(TracingHandler)
+	}
+	s.router.Use(s.DrainHandler)
 	s.router.Use(LoggerHandler)
+	s.router.Use(s.CompressHandler)
+	s.router.Use(s.BodyLimitHandler)
+	s.router.Use(s.AuditHandler)
+	s.router.Use(s.WebhookHandler)
+	s.router.Use(s.InvalidateCacheHandler)
 
 	NodeCollector = s.api.NodeNumberCollector
+	AppCollector = s.api.AppNumberCollector
+	ConfigCollector = s.api.ConfigNumberCollector
+
+	s.ReadRoute(http.MethodPost, "/v1/configs/:name/validate")
+	s.ReadRoute(http.MethodPut, "/v1/nodes")
+	s.ReadRoute(http.MethodPost, "/v1/nodes/batchGet")
+	s.ReadRoute(http.MethodPost, "/v1/apps/preview-targets")
 
 	v1 := s.GetV1RouterGroup()
+	{
+		admin := v1.Group("/admin")
+		admin.GET("/readonly", common.Wrapper(s.GetReadOnly))
+		admin.PUT("/readonly", common.Wrapper(s.UpdateReadOnly))
+		admin.POST("/auth/revoke", common.Wrapper(s.RevokeToken))
+	}
 	{
 		configs := v1.Group("/configs")
+		configs.GET("/unused", common.Wrapper(s.api.ListUnusedConfig))
 		configs.GET("/:name", s.WrapperCache(s.api.GetConfig))
-		configs.PUT("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UpdateConfig))
-		configs.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.Wrapper(s.api.DeleteConfig))
-		configs.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateConfig))
+		configs.PUT("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), s.StorageQuotaHandler(common.Config), common.Wrapper(s.api.UpdateConfig))
+		configs.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.DeleteConfig))
+		configs.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), s.ConfigQuotaHandler, s.StorageQuotaHandler(common.Config), common.Wrapper(s.api.CreateConfig))
 		configs.GET("", s.WrapperCache(s.api.ListConfig))
 		configs.GET("/:name/apps", common.Wrapper(s.api.GetAppByConfig))
+		configs.POST("/:name/validate", common.Wrapper(s.api.ValidateConfig))
+		configs.GET("/:name/versions", common.Wrapper(s.api.GetConfigVersions))
+		configs.GET("/:name/versions/:version", common.Wrapper(s.api.GetConfigVersion))
 	}
 	{
 		registry := v1.Group("/registries")
 		registry.GET("/:name", common.Wrapper(s.api.GetRegistry))
 		registry.PUT("/:name", common.Wrapper(s.api.UpdateRegistry))
 		registry.POST("/:name/refresh", common.Wrapper(s.api.RefreshRegistryPassword))
+		registry.POST("/:name/verify", common.Wrapper(s.api.VerifyRegistry))
 		registry.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.Wrapper(s.api.DeleteRegistry))
 		registry.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateRegistry))
 		registry.GET("", s.WrapperCache(s.api.ListRegistry))
@@ -127,76 +223,179 @@ func (s *AdminServer) InitRoute() {
 	}
 	{
 		certificate := v1.Group("/certificates")
+		certificate.GET("/expiring", common.Wrapper(s.api.ListExpiringCertificates))
+		certificate.POST("/csr", common.Wrapper(s.api.SignCSR))
+		certificate.POST("/import", common.Wrapper(s.api.ImportCertificates))
 		certificate.GET("/:name", common.Wrapper(s.api.GetCertificate))
 		certificate.PUT("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UpdateCertificate))
 		certificate.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.Wrapper(s.api.DeleteCertificate))
 		certificate.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateCertificate))
 		certificate.GET("", s.WrapperCache(s.api.ListCertificate))
 		certificate.GET("/:name/apps", common.Wrapper(s.api.GetAppByCertificate))
+		certificate.POST("/:name/rotate", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.RotateCertificate))
 	}
 	{
 		secrets := v1.Group("/secrets")
+		secrets.GET("/unused", common.Wrapper(s.api.ListUnusedSecret))
 		secrets.GET("/:name", common.Wrapper(s.api.GetSecret))
-		secrets.PUT("/:name", common.Wrapper(s.api.UpdateSecret))
+		secrets.PUT("/:name", s.StorageQuotaHandler(common.Secret), common.Wrapper(s.api.UpdateSecret))
 		secrets.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.Wrapper(s.api.DeleteSecret))
-		secrets.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateSecret))
+		secrets.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), s.StorageQuotaHandler(common.Secret), common.Wrapper(s.api.CreateSecret))
 		secrets.GET("", s.WrapperCache(s.api.ListSecret))
 		secrets.GET("/:name/apps", common.Wrapper(s.api.GetAppBySecret))
+		secrets.GET("/:name/versions", common.Wrapper(s.api.GetSecretVersions))
+		secrets.GET("/:name/versions/:version", common.Wrapper(s.api.GetSecretVersion))
 	}
 	{
 		nodes := v1.Group("/nodes")
 		nodes.GET("/:name", s.WrapperCache(s.api.GetNode))
 		nodes.PUT("", common.Wrapper(s.api.GetNodes))
+		nodes.POST("/batchGet", common.Wrapper(s.api.BatchGetNodes))
 		nodes.GET("/:name/apps", s.WrapperCache(s.api.GetAppByNode))
 		nodes.GET("/:name/functions", common.Wrapper(s.api.GetFunctionsByNode))
 		nodes.GET("/:name/stats", s.WrapperCache(s.api.GetNodeStats))
+		nodes.GET("/:name/stats/history", common.Wrapper(s.api.GetNodeStatsHistory))
+		nodes.GET("/:name/stats/watch", s.StreamHandler, common.WrapperNative(s.api.WatchNodeStats, true))
+		if s.cfg.Plugin.NodeLog != "" {
+			nodes.GET("/:name/apps/:app/logs", s.StreamHandler, common.WrapperNative(s.api.GetAppLogs, true))
+		}
 		nodes.PUT("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UpdateNode))
-		nodes.DELETE("/:name", common.Wrapper(s.api.DeleteNode))
+		nodes.PATCH("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.PatchNode))
+		nodes.DELETE("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.DeleteNode))
+		nodes.POST("/:name/drain", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.DrainNode))
+		nodes.DELETE("/:name/drain", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UndrainNode))
+		nodes.PUT("/:name/maintenance", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UpdateNodeMaintenance))
+		nodes.POST("/:name/commands", common.Wrapper(s.api.DispatchNodeCommand))
+		nodes.GET("/:name/commands", common.Wrapper(s.api.ListNodeCommand))
+		nodes.POST("/batch/delete", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.BatchDeleteNodes))
 		nodes.POST("", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), s.NodeQuotaHandler, common.Wrapper(s.api.CreateNode))
-		nodes.GET("", s.WrapperCache(s.api.ListNode))
+		nodes.GET("", s.WrapperCacheUnless(s.api.ListNode, withNodeStats))
 		nodes.GET("/:name/deploys", s.WrapperCache(s.api.GetNodeDeployHistory))
-		nodes.GET("/:name/init", s.WrapperCache(s.api.GenInitCmdFromNode))
+		nodes.GET("/:name/deploys/:id", s.WrapperCache(s.api.GetNodeDeployRecord))
+		nodes.GET("/:name/init", common.Wrapper(s.api.GenInitCmdFromNode))
+		nodes.GET("/:name/init/status", common.Wrapper(s.api.GetNodeInitStatus))
+		nodes.POST("/:name/token", common.Wrapper(s.api.GenNodeToken))
+		nodes.DELETE("/:name/token", common.Wrapper(s.api.RevokeNodeToken))
 		nodes.PUT("/:name/mode", common.Wrapper(s.api.UpdateNodeMode))
+		nodes.GET("/:name/tags", s.WrapperCache(s.api.GetNodeTags))
+		nodes.PUT("/:name/tags", common.Wrapper(s.api.UpdateNodeTags))
 		nodes.PUT("/:name/properties", common.Wrapper(s.api.UpdateNodeProperties))
+		nodes.POST("/properties/batch", common.Wrapper(s.api.BatchUpdateNodeProperties))
+		nodes.POST("/labels", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.BatchUpdateNodeLabels))
 		nodes.GET("/:name/properties", s.WrapperCache(s.api.GetNodeProperties))
+		nodes.GET("/:name/properties/history", common.Wrapper(s.api.GetNodePropertyHistory))
 		nodes.PUT("/:name/core/configs", common.Wrapper(s.api.UpdateCoreApp))
 		nodes.GET("/:name/core/configs", s.WrapperCache(s.api.GetCoreAppConfigs))
 		nodes.GET("/:name/core/versions", s.WrapperCache(s.api.GetCoreAppVersions))
 	}
+	{
+		nodeGroups := v1.Group("/nodegroups")
+		nodeGroups.GET("/:name", common.Wrapper(s.api.GetNodeGroup))
+		nodeGroups.PUT("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UpdateNodeGroup), s.InvalidateCacheOf("apps"))
+		nodeGroups.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.DeleteNodeGroup), s.InvalidateCacheOf("apps"))
+		nodeGroups.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateNodeGroup))
+		nodeGroups.GET("", s.WrapperCache(s.api.ListNodeGroup))
+		nodeGroups.GET("/:name/nodes", common.Wrapper(s.api.ListNodeGroupNodes))
+		nodeGroups.POST("/:name/apps", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.DeployNodeGroupApp), s.InvalidateCacheOf("apps"))
+	}
+	{
+		appTemplates := v1.Group("/apptemplates")
+		appTemplates.GET("/:name", common.Wrapper(s.api.GetAppTemplate))
+		appTemplates.PUT("/:name", common.Wrapper(s.api.UpdateAppTemplate))
+		appTemplates.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.Wrapper(s.api.DeleteAppTemplate))
+		appTemplates.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateAppTemplate))
+		appTemplates.GET("", s.WrapperCache(s.api.ListAppTemplate))
+		appTemplates.POST("/:name/instantiate", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), s.AppQuotaHandler, common.Wrapper(s.api.InstantiateAppTemplate))
+	}
+	{
+		webhooks := v1.Group("/webhooks")
+		webhooks.GET("/:name", common.Wrapper(s.api.GetWebhook))
+		webhooks.PUT("/:name", common.Wrapper(s.api.UpdateWebhook))
+		webhooks.DELETE("/:name", common.Wrapper(s.api.DeleteWebhook))
+		webhooks.POST("", common.Wrapper(s.api.CreateWebhook))
+		webhooks.GET("", common.Wrapper(s.api.ListWebhook))
+		webhooks.POST("/:name/test", common.Wrapper(s.api.TestWebhook))
+		webhooks.GET("/:name/deadletters", common.Wrapper(s.api.ListWebhookDeadLetters))
+	}
+	{
+		audits := v1.Group("/audits")
+		audits.GET("", common.Wrapper(s.api.ListAudit))
+	}
 	{
 		apps := v1.Group("/apps")
+		apps.GET("/trash", common.Wrapper(s.api.ListAppTrash))
+		apps.POST("/preview-targets", common.Wrapper(s.api.PreviewAppTargets))
+		apps.DELETE("/trash/:name", common.Wrapper(s.api.PurgeApplication))
+		apps.POST("/:name/restore", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), s.AppQuotaHandler, common.Wrapper(s.api.RestoreApplication))
 		apps.GET("/:name", s.WrapperCache(s.api.GetApplication))
 		apps.GET("/:name/configs", s.WrapperCache(s.api.GetSysAppConfigs))
 		apps.GET("/:name/secrets", s.WrapperCache(s.api.GetSysAppSecrets))
 		apps.GET("/:name/certificates", s.WrapperCache(s.api.GetSysAppCertificates))
 		apps.GET("/:name/registries", s.WrapperCache(s.api.GetSysAppRegistries))
+		apps.GET("/:name/diff", common.Wrapper(s.api.DiffApplication))
+		apps.GET("/:name/nodes", common.Wrapper(s.api.GetAppNodes))
+		apps.GET("/:name/rollout", common.Wrapper(s.api.GetAppRolloutStatus))
+		apps.GET("/:name/health", common.Wrapper(s.api.GetAppHealth))
+		apps.GET("/:name/schedule", common.Wrapper(s.api.GetAppSchedule))
+		apps.POST("/:name/schedule", common.Wrapper(s.api.ScheduleApplication))
+		apps.DELETE("/:name/schedule", common.Wrapper(s.api.CancelAppSchedule))
 		apps.PUT("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UpdateApplication))
-		apps.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.Wrapper(s.api.DeleteApplication))
-		apps.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.CreateApplication))
+		apps.PATCH("/:name/labels", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.PatchApplicationLabels))
+		apps.POST("/:name/rollback", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.RollbackApplication))
+		apps.POST("/:name/promote", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.PromoteApplication))
+		apps.POST("/:name/abort", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.AbortApplication))
+		apps.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.DeleteApplication))
+		apps.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), s.AppQuotaHandler, common.Wrapper(s.api.CreateApplication))
 		apps.GET("", s.WrapperCache(s.api.ListApplication))
 	}
+	{
+		alerts := v1.Group("/alerts")
+		alerts.GET("/rules", common.WrapperRaw(s.api.GetAlertRules, true))
+	}
 	{
 		namespace := v1.Group("/namespace")
 		namespace.POST("", common.Wrapper(s.api.CreateNamespace))
 		namespace.GET("", s.WrapperCache(s.api.GetNamespace))
+		namespace.GET("/summary", s.WrapperCache(s.api.GetNamespaceSummary))
 		namespace.DELETE("", common.Wrapper(s.api.DeleteNamespace))
+		namespace.GET("/settings", common.Wrapper(s.api.GetNamespaceSettings))
+		namespace.PUT("/settings", common.Wrapper(s.api.UpdateNamespaceSettings))
+		namespace.GET("/features", common.Wrapper(s.api.GetNamespaceFeatures))
+		namespace.GET("/isolation-check", common.Wrapper(s.CheckNamespaceIsolation))
+		namespace.GET("/export", common.WrapperRaw(s.api.ExportNamespace, true))
+		namespace.POST("/import", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.ImportNamespace),
+			s.InvalidateCacheOf("secrets", "certificates", "registries", "configs", "apps", "nodes"))
+	}
+	{
+		jobs := v1.Group("/jobs")
+		jobs.GET("/:id", common.Wrapper(s.api.GetJob))
+		jobs.POST("/:id/cancel", common.Wrapper(s.api.CancelJob))
 	}
 	{
 		function := v1.Group("/functions")
 		function.GET("", common.Wrapper(s.api.ListFunctionSources))
 		if len(s.cfg.Plugin.Functions) != 0 {
-			function.GET("/:source/functions", common.Wrapper(s.api.ListFunctions))
-			function.GET("/:source/functions/:name/versions", common.Wrapper(s.api.ListFunctionVersions))
-			function.POST("/:source/functions/:name/versions/:version", common.Wrapper(s.api.ImportFunction))
+			enabled := function.Group("", s.FeatureHandler(models.FeatureFunctions))
+			enabled.GET("/:source/functions", common.Wrapper(s.api.ListFunctions))
+			enabled.GET("/:source/functions/:name/versions", common.Wrapper(s.api.ListFunctionVersions))
+			enabled.POST("/:source/functions/:name/versions/:version", common.Wrapper(s.api.ImportFunction))
+			enabled.POST("/:source/functions/:name/versions/:version/invoke", common.Wrapper(s.api.InvokeFunction))
+			enabled.POST("/:source/functions/:name/versions/:version/upload", common.Wrapper(s.api.UploadFunction))
 		}
 	}
 	{
 		// Deprecated
 		objects := v1.Group("/objects")
-		objects.GET("", common.Wrapper(s.api.ListObjectSources))
-		if len(s.cfg.Plugin.Objects) != 0 {
-			objects.GET("/:source/buckets", common.Wrapper(s.api.ListBuckets))
-			objects.GET("/:source/buckets/:bucket/objects", common.Wrapper(s.api.ListBucketObjects))
+		if s.cfg.AdminServer.DisableDeprecatedObjects {
+			objects.GET("", s.GoneHandler)
+			objects.GET("/:source/buckets", s.GoneHandler)
+			objects.GET("/:source/buckets/:bucket/objects", s.GoneHandler)
+		} else {
+			objects.GET("", common.Wrapper(s.api.ListObjectSources))
+			if len(s.cfg.Plugin.Objects) != 0 {
+				objects.GET("/:source/buckets", common.Wrapper(s.api.ListBuckets))
+				objects.GET("/:source/buckets/:bucket/objects", common.Wrapper(s.api.ListBucketObjects))
+			}
 		}
 	}
 
@@ -241,7 +440,10 @@ func (s *AdminServer) InitRoute() {
 		module.GET("", s.WrapperCache(s.api.ListModules))
 		module.GET("/:name", s.WrapperCache(s.api.GetModules))
 		module.GET("/:name/version/:version", s.WrapperCache(s.api.GetModuleByVersion))
+		module.GET("/:name/version/:version/deps", s.WrapperCache(s.api.GetModuleDependencies))
+		module.POST("/:name/version/:version/validate-arch", common.Wrapper(s.api.ValidateModuleArch))
 		module.GET("/:name/latest", s.WrapperCache(s.api.GetLatestModule))
+		module.GET("/:name/history", s.WrapperCache(s.api.GetModuleHistory))
 		module.POST("", common.Wrapper(s.api.CreateModule))
 		module.PUT("/:name/version/:version", common.Wrapper(s.api.UpdateModule))
 		module.DELETE("/:name", common.Wrapper(s.api.DeleteModules))
@@ -249,13 +451,23 @@ func (s *AdminServer) InitRoute() {
 	}
 	{
 		quotas := v1.Group("/quotas")
-		quotas.GET("", s.WrapperCache(s.api.GetQuota))
+		// the usages are collected on every request, they change along with the resources of the namespace
+		quotas.GET("", common.Wrapper(s.api.GetQuota))
+		quotas.GET("/usage", common.Wrapper(s.api.GetQuotaUsage))
+	}
+	{
+		v1.GET("/license", common.Wrapper(s.api.GetLicense))
+	}
+	{
+		v1.GET("/search", s.WrapperCache(s.api.GlobalSearch))
 	}
 	{
 		yaml := v1.Group("yaml")
 		yaml.POST("", common.Wrapper(s.api.CreateYamlResource))
 		yaml.PUT("", common.Wrapper(s.api.UpdateYamlResource))
 		yaml.POST("/delete", common.Wrapper(s.api.DeleteYamlResource))
+		yaml.POST("/apply", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.ApplyYamlResource),
+			s.InvalidateCacheOf("secrets", "certificates", "registries", "configs", "apps"))
 	}
 
 	v2 := s.GetV2RouterGroup()
@@ -263,10 +475,14 @@ func (s *AdminServer) InitRoute() {
 		objects := v2.Group("/objects")
 		objects.GET("", s.WrapperCache(s.api.ListObjectSourcesV2))
 		if len(s.cfg.Plugin.Objects) != 0 {
-			objects.GET("/:source/buckets", common.Wrapper(s.api.ListBucketsV2))
-			objects.GET("/:source/buckets/:bucket/objects", common.Wrapper(s.api.ListBucketObjectsV2))
-			objects.GET("/:source/buckets/:bucket/object", common.Wrapper(s.api.GetObjectPathV2))
-			objects.GET("/:source/buckets/:bucket/object/put", common.Wrapper(s.api.GetObjectPutPathV2))
+			enabled := objects.Group("", s.FeatureHandler(models.FeatureObjectsV2))
+			enabled.GET("/:source/buckets", common.Wrapper(s.api.ListBucketsV2))
+			enabled.GET("/:source/buckets/:bucket/objects", common.Wrapper(s.api.ListBucketObjectsV2))
+			enabled.GET("/:source/buckets/:bucket/lifecycle", common.Wrapper(s.api.GetBucketLifecycleV2))
+			enabled.PUT("/:source/buckets/:bucket/lifecycle", common.Wrapper(s.api.PutBucketLifecycleV2))
+			enabled.GET("/:source/buckets/:bucket/object", common.Wrapper(s.api.GetObjectPathV2))
+			enabled.GET("/:source/buckets/:bucket/object/put", common.Wrapper(s.api.GetObjectPutPathV2))
+			enabled.POST("/:source/buckets/:bucket/object/complete", common.Wrapper(s.api.CompleteObjectPutV2))
 		}
 	}
 }
@@ -279,6 +495,10 @@ func (s *AdminServer) GetRoute() *gin.Engine {
 func (s *AdminServer) GetV1RouterGroup() *gin.RouterGroup {
 	router := s.router.Group("v1")
 	router.Use(s.AuthHandler)
+	router.Use(s.RBACHandler)
+	router.Use(s.ReadOnlyHandler)
+	router.Use(s.RateLimitHandler)
+	router.Use(s.IdempotencyHandler)
 	router.Use(s.ExternalHandlers...)
 	return router
 }
@@ -286,6 +506,10 @@ func (s *AdminServer) GetV1RouterGroup() *gin.RouterGroup {
 func (s *AdminServer) GetV2RouterGroup() *gin.RouterGroup {
 	router := s.router.Group("v2")
 	router.Use(s.AuthHandler)
+	router.Use(s.RBACHandler)
+	router.Use(s.ReadOnlyHandler)
+	router.Use(s.RateLimitHandler)
+	router.Use(s.IdempotencyHandler)
 	router.Use(s.ExternalHandlers...)
 	return router
 }
@@ -293,6 +517,13 @@ func (s *AdminServer) GetV2RouterGroup() *gin.RouterGroup {
 // auth handler
 func (s *AdminServer) AuthHandler(c *gin.Context) {
 	cc := common.NewContext(c)
+	// the node tokens are only allowed to access the sync endpoints of the nodes
+	if api.IsNodeToken(c.Request.Header.Get("Authorization")) {
+		s.log.Error("node token is not allowed on the admin api", log.Any(cc.GetTrace()))
+		common.PopulateFailedResponse(cc, common.Error(common.ErrRequestAccessDenied,
+			common.Field("error", "the node token is not allowed on the admin api")), true)
+		return
+	}
 	err := s.Auth.Authenticate(cc)
 	if err != nil {
 		s.log.Error("request authenticate failed",
@@ -304,10 +535,74 @@ func (s *AdminServer) AuthHandler(c *gin.Context) {
 	}
 }
 
+// RevokeToken forgets the cached auth result of the revoked token, only the users with the full control of the system are allowed
+func (s *AdminServer) RevokeToken(c *common.Context) (interface{}, error) {
+	err := s.Auth.Verify(c, &plugin.PermissionRequest{
+		Resource:   plugin.PermissionResourceSystem,
+		Permission: []string{plugin.PermissionFull},
+	})
+	if err != nil {
+		return nil, common.Error(common.ErrRequestAccessDenied, common.Field("error", err.Error()))
+	}
+	revocation := new(models.TokenRevocation)
+	if err = c.LoadBody(revocation); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	s.Auth.InvalidateToken(revocation.Token)
+	s.log.Info("token is revoked", log.Any(c.GetTrace()), log.Any("user", c.GetUser().Name))
+	return nil, nil
+}
+
+// CheckNamespaceIsolation checks the resources of the namespace can't be read from other namespaces,
+// only the users with the full control of the system are allowed
+func (s *AdminServer) CheckNamespaceIsolation(c *common.Context) (interface{}, error) {
+	err := s.Auth.Verify(c, &plugin.PermissionRequest{
+		Resource:   plugin.PermissionResourceSystem,
+		Permission: []string{plugin.PermissionFull},
+	})
+	if err != nil {
+		return nil, common.Error(common.ErrRequestAccessDenied, common.Field("error", err.Error()))
+	}
+	return s.api.CheckNamespaceIsolation(c)
+}
+
 func (s *AdminServer) NodeQuotaHandler(c *gin.Context) {
+	s.checkQuota(c, NodeCollector)
+}
+
+func (s *AdminServer) AppQuotaHandler(c *gin.Context) {
+	s.checkQuota(c, AppCollector)
+}
+
+func (s *AdminServer) ConfigQuotaHandler(c *gin.Context) {
+	s.checkQuota(c, ConfigCollector)
+}
+
+// StorageQuotaHandler rejects the config or secret of the request if the total bytes of the configs and secrets
+// would exceed the storage quota of the namespace
+func (s *AdminServer) StorageQuotaHandler(resource common.Resource) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		cc := common.NewContext(c)
+		if err := s.api.CheckStorageQuota(cc, resource); err != nil {
+			if s.api.TolerateLicense(cc, err) {
+				return
+			}
+			s.log.Error("storage quota out of limit",
+				log.Any(cc.GetTrace()),
+				log.Any("namespace", cc.GetNamespace()),
+				log.Error(err))
+			common.PopulateFailedResponse(cc, err, true)
+		}
+	}
+}
+
+func (s *AdminServer) checkQuota(c *gin.Context, collector plugin.QuotaCollector) {
 	cc := common.NewContext(c)
 	namespace := cc.GetNamespace()
-	if err := s.api.Quota.CheckQuota(namespace, NodeCollector); err != nil {
+	if err := s.api.Quota.CheckQuota(namespace, collector); err != nil {
+		if s.api.TolerateLicense(cc, err) {
+			return
+		}
 		s.log.Error("quota out of limit",
 			log.Any(cc.GetTrace()),
 			log.Any("namespace", cc.GetNamespace()),
@@ -316,26 +611,135 @@ func (s *AdminServer) NodeQuotaHandler(c *gin.Context) {
 	}
 }
 
+// AuditHandler records the mutating requests, the user and namespace are available after the request is authenticated
+func (s *AdminServer) AuditHandler(c *gin.Context) {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		c.Next()
+		return
+	}
+	var bodyHash string
+	if body := c.Request.Body; body != nil && body != http.NoBody {
+		// the body is read up to the limit of the route at most, the larger ones are rejected by BodyLimitHandler
+		// unless the limit is 0, and the body read is always restored ahead of the rest even if failed to read
+		var r io.Reader = body
+		if limit := s.routeBodyLimit(c.FullPath()); limit > 0 {
+			r = io.LimitReader(body, limit)
+		}
+		buf, err := io.ReadAll(r)
+		c.Request.Body = &auditBody{Reader: io.MultiReader(bytes.NewReader(buf), body), Closer: body}
+		if err == nil && len(buf) > 0 {
+			sum := sha256.Sum256(buf)
+			bodyHash = hex.EncodeToString(sum[:])
+		}
+	}
+	start := time.Now()
+	c.Next()
+
+	// the requests not matching any route are not recorded
+	if c.FullPath() == "" {
+		return
+	}
+	cc := common.NewContext(c)
+	user := cc.GetUser()
+	audit := &models.Audit{
+		Namespace: cc.GetNamespace(),
+		UserID:    user.ID,
+		UserName:  user.Name,
+		Resource:  routeResource(c.FullPath()),
+		Name:      c.Param("name"),
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		BodyHash:  bodyHash,
+		Status:    c.Writer.Status(),
+		RequestID: c.Writer.Header().Get(common.GetTraceHeader()),
+		ClientIP:  c.ClientIP(),
+		Timestamp: start,
+	}
+	// the record is written synchronously, so that it is never dropped by a full queue or a shutdown
+	if err := s.api.Audit.Create(audit); err != nil {
+		s.log.Error("failed to record audit",
+			log.Any(cc.GetTrace()),
+			log.Any("method", audit.Method),
+			log.Any("path", audit.Path),
+			log.Error(err))
+	}
+}
+
+// auditBody the body of the request restored after it is read for the audit
+type auditBody struct {
+	io.Reader
+	io.Closer
+}
+
+// ReadRoute declares the route reading with other methods than GET, which requires the read permission of the resource,
+// and neither evicts the cached responses nor is rejected if the server is read-only
+func (s *AdminServer) ReadRoute(method, route string) {
+	if s.readRoutes == nil {
+		s.readRoutes = map[string]bool{}
+	}
+	s.readRoutes[method+" "+route] = true
+	s.RequirePermission(method, route, routeResource(route)+":"+RBACVerbRead)
+}
+
+func (s *AdminServer) isReadRoute(method, route string) bool {
+	return s.readRoutes[method+" "+route]
+}
+
+// routeResource returns the resource type of the route, e.g. apps of /v1/apps/:name
+func routeResource(route string) string {
+	parts := strings.Split(strings.Trim(route, "/"), "/")
+	if len(parts) > 1 {
+		return parts[1]
+	}
+	return parts[0]
+}
+
+// WrapperCache caches the responses of the handler if the cache is enabled,
+// the ttl is configured by the route in AdminServer.Cache.Routes, or AdminServer.CacheDuration by default.
+// The responses are tagged with ETag whether cached or not, and 304 is responded to If-None-Match if unchanged
 func (s *AdminServer) WrapperCache(handler common.HandlerFunc) func(c *gin.Context) {
 	if s.cfg.AdminServer.CacheEnable {
-		dur := DefaultAPICacheDuration
-		if s.cfg.AdminServer.CacheDuration > 0 {
-			dur = s.cfg.AdminServer.CacheDuration
-		}
-		return s.WrapperCacheDuration(handler, dur)
+		return wrapperETag(s.wrapperCache(handler, s.routeCacheDuration, nil))
+	}
+	return wrapperETag(common.Wrapper(handler))
+}
+
+// WrapperCacheUnless caches the responses like WrapperCache, except the ones of the requests matching skip,
+// which are always served fresh, such as the ones joining the latest stats
+func (s *AdminServer) WrapperCacheUnless(handler common.HandlerFunc, skip func(c *gin.Context) bool) func(c *gin.Context) {
+	if s.cfg.AdminServer.CacheEnable {
+		return wrapperETag(s.wrapperCache(handler, s.routeCacheDuration, skip))
 	}
-	return common.Wrapper(handler)
+	return wrapperETag(common.Wrapper(handler))
 }
 
 func (s *AdminServer) WrapperCacheDuration(handler common.HandlerFunc, dur time.Duration) func(c *gin.Context) {
-	return cache.WCacheByRequestURI(
+	return wrapperETag(s.wrapperCache(handler, func(string) time.Duration { return dur }, nil))
+}
+
+// wrapperCache caches the successful responses only, the missing resources responded with 404 and other errors
+// are not cached, so that a resource is found as soon as it is created
+func (s *AdminServer) wrapperCache(handler common.HandlerFunc, durOf func(route string) time.Duration, skip func(c *gin.Context) bool) func(c *gin.Context) {
+	wrapped := common.Wrapper(handler)
+	return cache.WCache(
 		s.APICache,
-		dur,
-		common.Wrapper(handler),
+		DefaultAPICacheDuration,
+		wrapped,
+		cache.WithCacheStrategyByRequest(func(c *gin.Context) (cache.Strategy, bool) {
+			if skip != nil && skip(c) {
+				return cache.Strategy{}, false
+			}
+			return cache.Strategy{
+				CacheKey:      c.Request.RequestURI + "@" + s.cacheGeneration(c),
+				CacheDuration: durOf(c.FullPath()),
+			}, true
+		}),
 		cache.WithLogger(s),
 		cache.KeyWithGinContext([]string{"namespace"}),
 		cache.WithoutHeader(),
-		cache.WithoutHeaderIgnore([]string{"Content-Type"}),
+		// the tags set by the handlers are replayed along with the bodies
+		cache.WithoutHeaderIgnore([]string{"Content-Type", common.HeaderETag}),
 	)
 }
 
@@ -2,8 +2,11 @@ package server
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -15,6 +18,7 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/api"
 
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -32,6 +36,15 @@ func initAdminServerMock(t *testing.T) (*AdminServer, *mockPlugin.MockAuth, *moc
 	c.Plugin.Shadow = common.RandString(9)
 	c.Plugin.Index = common.RandString(9)
 	c.Plugin.AppHistory = common.RandString(9)
+	c.Plugin.NodeGroup = common.RandString(9)
+	c.Plugin.AppTpl = common.RandString(9)
+	c.Plugin.AuditSink = common.RandString(9)
+	c.Plugin.Webhook = common.RandString(9)
+	c.Plugin.NodeCmd = common.RandString(9)
+	c.Plugin.AppSched = common.RandString(9)
+	c.Plugin.RegRefresh = common.RandString(9)
+	c.Plugin.Job = common.RandString(9)
+	c.Plugin.EventSink = common.RandString(9)
 	c.Plugin.Objects = []string{common.RandString(9)}
 	c.Plugin.PKI = common.RandString(9)
 	c.Plugin.Functions = []string{common.RandString(9)}
@@ -108,6 +121,49 @@ func initAdminServerMock(t *testing.T) (*AdminServer, *mockPlugin.MockAuth, *moc
 		return mockIndex, nil
 	})
 
+	mockAppHis := mockPlugin.NewMockAppHistory(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppHistory, func() (plugin.Plugin, error) {
+		return mockAppHis, nil
+	})
+	mockNodeGroup := mockPlugin.NewMockNodeGroup(mockCtl)
+	plugin.RegisterFactory(c.Plugin.NodeGroup, func() (plugin.Plugin, error) {
+		return mockNodeGroup, nil
+	})
+	mockAppTemplate := mockPlugin.NewMockAppTemplate(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppTpl, func() (plugin.Plugin, error) {
+		return mockAppTemplate, nil
+	})
+
+	mockWebhook := mockPlugin.NewMockWebhook(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Webhook, func() (plugin.Plugin, error) {
+		return mockWebhook, nil
+	})
+	mockNodeCmd := mockPlugin.NewMockNodeCommand(mockCtl)
+	plugin.RegisterFactory(c.Plugin.NodeCmd, func() (plugin.Plugin, error) {
+		return mockNodeCmd, nil
+	})
+	mockAppSched := mockPlugin.NewMockAppSchedule(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppSched, func() (plugin.Plugin, error) {
+		return mockAppSched, nil
+	})
+	mockRegRefresh := mockPlugin.NewMockRegistryRefresh(mockCtl)
+	plugin.RegisterFactory(c.Plugin.RegRefresh, func() (plugin.Plugin, error) {
+		return mockRegRefresh, nil
+	})
+	mockJob := mockPlugin.NewMockJob(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Job, func() (plugin.Plugin, error) {
+		return mockJob, nil
+	})
+	mockAuditSink := mockPlugin.NewMockAuditSink(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AuditSink, func() (plugin.Plugin, error) {
+		return mockAuditSink, nil
+	})
+
+	mockEventSink := mockPlugin.NewMockEventSink(mockCtl)
+	plugin.RegisterFactory(c.Plugin.EventSink, func() (plugin.Plugin, error) {
+		return mockEventSink, nil
+	})
+
 	mockTask := mockPlugin.NewMockTask(mockCtl)
 	plugin.RegisterFactory(c.Plugin.Task, func() (plugin.Plugin, error) {
 		return mockTask, nil
@@ -148,6 +204,9 @@ func TestAdminServer_Handler(t *testing.T) {
 
 	mLock := service.NewMockLockerService(mockCtl)
 	s.api.Locker = mLock
+	mAudit := service.NewMockAuditService(mockCtl)
+	s.api.Audit = mAudit
+	mAudit.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
 
 	s.InitRoute()
 	r := s.GetRoute()
@@ -193,6 +252,7 @@ func TestAdminServer_Handler(t *testing.T) {
 	mQuota := service.NewMockQuotaService(mockCtl)
 	s.api.Quota = mQuota
 	mQuota.EXPECT().CheckQuota(gomock.Any(), gomock.Any()).Return(fmt.Errorf("quota error"))
+	mQuota.EXPECT().CheckQuota(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	mLock.EXPECT().Lock(gomock.Any(), gomock.Any(), gomock.Any()).Return("", nil)
 	mLock.EXPECT().Unlock(gomock.Any(), gomock.Any(), gomock.Any()).Return()
 	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes", nil)
@@ -294,3 +354,118 @@ func TestAdminServer_Handler(t *testing.T) {
 	go s.Run()
 	defer s.Close()
 }
+
+func TestAdminServer_AuditHandler(t *testing.T) {
+	s, mkAuth, _, mockCtl := initAdminServerMock(t)
+	defer mockCtl.Finish()
+
+	mAudit := service.NewMockAuditService(mockCtl)
+	s.api.Audit = mAudit
+	mkAuth.EXPECT().Authenticate(gomock.Any()).DoAndReturn(func(c *common.Context) error {
+		c.SetNamespace("default")
+		c.SetUser(common.User{ID: "u1", Name: "user1"})
+		return nil
+	}).AnyTimes()
+
+	// the client ip is taken from X-Forwarded-For of the trusted proxies only
+	r, err := newRouter(config.Server{TrustedProxies: []string{"10.0.0.0/8"}})
+	assert.NoError(t, err)
+	r.Use(RequestIDHandler, s.AuditHandler)
+	v1 := r.Group("v1", s.AuthHandler)
+	v1.GET("/apps/:name", func(c *gin.Context) { c.Status(http.StatusOK) })
+	v1.PUT("/apps/:name", func(c *gin.Context) { c.Status(http.StatusOK) })
+	v1.DELETE("/apps/:name", func(c *gin.Context) { c.Status(http.StatusBadRequest) })
+
+	// get is not recorded
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/app01", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// unmatched route is not recorded
+	req, _ = http.NewRequest(http.MethodPost, "/v1/unknown", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	body := []byte(`{"name":"app01"}`)
+	sum := sha256.Sum256(body)
+	mAudit.EXPECT().Create(gomock.Any()).DoAndReturn(func(audit *models.Audit) error {
+		assert.Equal(t, "default", audit.Namespace)
+		assert.Equal(t, "u1", audit.UserID)
+		assert.Equal(t, "user1", audit.UserName)
+		assert.Equal(t, "apps", audit.Resource)
+		assert.Equal(t, "app01", audit.Name)
+		assert.Equal(t, http.MethodPut, audit.Method)
+		assert.Equal(t, "/v1/apps/app01", audit.Path)
+		assert.Equal(t, hex.EncodeToString(sum[:]), audit.BodyHash)
+		assert.Equal(t, http.StatusOK, audit.Status)
+		assert.NotEmpty(t, audit.RequestID)
+		assert.Equal(t, "203.0.113.9", audit.ClientIP)
+		assert.False(t, audit.Timestamp.IsZero())
+		return nil
+	}).Times(1)
+	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/app01", bytes.NewReader(body))
+	req.RemoteAddr = "10.1.1.1:4321"
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the failure of audit sink doesn't fail the request
+	mAudit.EXPECT().Create(gomock.Any()).DoAndReturn(func(audit *models.Audit) error {
+		assert.Equal(t, http.StatusBadRequest, audit.Status)
+		assert.Empty(t, audit.BodyHash)
+		assert.Equal(t, "192.168.1.1", audit.ClientIP)
+		return fmt.Errorf("sink error")
+	}).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apps/app01", nil)
+	req.RemoteAddr = "192.168.1.1:4321"
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the body is hashed up to the limit, and the whole body is still read by the handler
+	s.cfg.AdminServer.BodyLimit.Default = 4
+	var read []byte
+	v1.POST("/apps", func(c *gin.Context) {
+		read, _ = io.ReadAll(c.Request.Body)
+		c.Status(http.StatusOK)
+	})
+	prefix := sha256.Sum256(body[:4])
+	mAudit.EXPECT().Create(gomock.Any()).DoAndReturn(func(audit *models.Audit) error {
+		assert.Equal(t, hex.EncodeToString(prefix[:]), audit.BodyHash)
+		return nil
+	}).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, read)
+}
+
+func TestAdminServer_AuthHandlerNodeToken(t *testing.T) {
+	s, mkAuth, _, mockCtl := initAdminServerMock(t)
+	defer mockCtl.Finish()
+
+	r := gin.New()
+	v1 := r.Group("v1", s.AuthHandler)
+	v1.GET("/nodes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// the node token is rejected before authenticated
+	for _, token := range []string{api.NodeTokenPrefix + "token", "Bearer " + api.NodeTokenPrefix + "token"} {
+		req, _ := http.NewRequest(http.MethodGet, "/v1/nodes", nil)
+		req.Header.Set("Authorization", token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	mkAuth.EXPECT().Authenticate(gomock.Any()).Return(nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+const (
+	// functionUploadRoute the route uploading the code archives of the functions, whose limit is derived from
+	// Function.UploadMaxSize unless the route itself is configured
+	functionUploadRoute = "/v1/functions/:source/functions/:name/versions/:version/upload"
+	// multipartOverhead the room for the boundaries and the other fields of the multipart body around the archive
+	multipartOverhead = 64 << 10
+)
+
+// BodyLimitHandler rejects the requests whose body exceeds the limit of the route with 413 before the body is read,
+// the body of unknown length is read up to the limit at most
+func (s *AdminServer) BodyLimitHandler(c *gin.Context) {
+	limit := s.routeBodyLimit(c.FullPath())
+	if limit <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return
+	}
+	if c.Request.ContentLength > limit {
+		s.rejectLargeBody(c, limit)
+		return
+	}
+	if c.Request.ContentLength >= 0 {
+		// the server never reads more than the content length
+		return
+	}
+	buf, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+	if err != nil {
+		common.PopulateFailedResponse(common.NewContext(c), common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", err.Error())), true)
+		return
+	}
+	if int64(len(buf)) > limit {
+		s.rejectLargeBody(c, limit)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(buf))
+}
+
+func (s *AdminServer) rejectLargeBody(c *gin.Context, limit int64) {
+	// the connection is closed since the rest of the body is not read
+	c.Header("Connection", "close")
+	common.PopulateFailedResponse(common.NewContext(c), common.Error(common.ErrBodyTooLarge, common.Field("max", limit)), true)
+}
+
+// routeBodyLimit returns the body limit of the route, the longest matched prefix is preferred,
+// then the limit derived from Function.UploadMaxSize for the upload of functions, then the default limit of the admin server
+func (s *AdminServer) routeBodyLimit(route string) int64 {
+	cfg := s.cfg.AdminServer.BodyLimit
+	limit, matched := cfg.Default, ""
+	for prefix, l := range cfg.Routes {
+		if len(prefix) <= len(matched) || !strings.HasPrefix(route, prefix) {
+			continue
+		}
+		// the prefix matches whole segments only, e.g. /v1/configs does not match /v1/configsx
+		if len(route) > len(prefix) && route[len(prefix)] != '/' && !strings.HasSuffix(prefix, "/") {
+			continue
+		}
+		limit, matched = l, prefix
+	}
+	if route == functionUploadRoute && matched != route && s.cfg.Function.UploadMaxSize > 0 {
+		limit = int64(s.cfg.Function.UploadMaxSize) + multipartOverhead
+	}
+	return limit
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestRouteBodyLimit(t *testing.T) {
+	cfg := &config.CloudConfig{}
+	cfg.AdminServer.BodyLimit = config.BodyLimit{
+		Default: 100,
+		Routes:  map[string]int64{"/v1/configs": 10, "/v1/configs/:name/apps": 0, "/v1/yaml": 20},
+	}
+	s := &AdminServer{cfg: cfg}
+	assert.Equal(t, int64(10), s.routeBodyLimit("/v1/configs"))
+	assert.Equal(t, int64(10), s.routeBodyLimit("/v1/configs/:name"))
+	assert.Equal(t, int64(0), s.routeBodyLimit("/v1/configs/:name/apps"))
+	assert.Equal(t, int64(20), s.routeBodyLimit("/v1/yaml/apply"))
+	assert.Equal(t, int64(100), s.routeBodyLimit("/v1/yamlx"))
+	assert.Equal(t, int64(100), s.routeBodyLimit("/v1/apps"))
+	assert.Equal(t, int64(100), s.routeBodyLimit(""))
+
+	// the upload of functions follows Function.UploadMaxSize unless the route is configured
+	cfg.Function.UploadMaxSize = 1000
+	cfg.AdminServer.BodyLimit.Routes["/v1/functions"] = 30
+	assert.Equal(t, int64(1000+multipartOverhead), s.routeBodyLimit(functionUploadRoute))
+	assert.Equal(t, int64(30), s.routeBodyLimit("/v1/functions/:source/functions/:name/versions/:version"))
+	cfg.AdminServer.BodyLimit.Routes[functionUploadRoute] = 40
+	assert.Equal(t, int64(40), s.routeBodyLimit(functionUploadRoute))
+}
+
+func TestAdminServer_BodyLimitHandler(t *testing.T) {
+	cfg := &config.CloudConfig{}
+	cfg.AdminServer.BodyLimit = config.BodyLimit{Default: 10, Routes: map[string]int64{"/v1/apps": 0}}
+	s := &AdminServer{cfg: cfg}
+	router := gin.New()
+	router.Use(s.BodyLimitHandler)
+	echo := func(c *gin.Context) {
+		buf, err := io.ReadAll(c.Request.Body)
+		assert.NoError(t, err)
+		c.String(http.StatusOK, string(buf))
+	}
+	router.POST("/v1/configs", echo)
+	router.POST("/v1/apps", echo)
+
+	post := func(path, body string, chunked bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if chunked {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/v1/configs", "0123456789", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0123456789", w.Body.String())
+	w = post("/v1/configs", "0123456789", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0123456789", w.Body.String())
+
+	w = post("/v1/configs", "0123456789a", false)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrBodyTooLarge")
+	w = post("/v1/configs", "0123456789a", true)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	// not limited
+	w = post("/v1/apps", "0123456789a", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
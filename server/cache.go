@@ -0,0 +1,153 @@
+package server
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+const (
+	CacheTypeMemory = service.CacheTypeMemory
+	CacheTypeRedis  = service.CacheTypeRedis
+
+	apiCacheGenerationPrefix = "baetyl-cloud:api-cache-gen:"
+	// apiCacheGenerationTTL the ttl of the generations, which should be longer than the ttl of any cached response
+	apiCacheGenerationTTL = 24 * time.Hour
+
+	// the scopes of the generations of a resource type, besides the ones of the named resources
+	cacheScopeType = "*"
+	cacheScopeList = "-"
+
+	// NodeReportCacheDuration the ttl of the cached responses of the routes serving the reports of the nodes by default,
+	// the reports are stored by the sync server without evicting the cached responses, so they are kept shortly
+	NodeReportCacheDuration = time.Second * 3
+)
+
+// nodeReportRoutes the routes serving the data reported by the nodes
+var nodeReportRoutes = map[string]bool{
+	"/v1/nodes":                  true,
+	"/v1/nodes/:name":            true,
+	"/v1/nodes/:name/stats":      true,
+	"/v1/nodes/:name/apps":       true,
+	"/v1/nodes/:name/properties": true,
+}
+
+// newAPICache creates the store of cached api responses, the generations of the cached resources are kept
+// in the store as well, so that the responses evicted by a replica are evicted for the others too
+func newAPICache(cfg config.APICache) (persist.CacheStore, error) {
+	return service.NewCacheStore(cfg, DefaultAPICacheDuration)
+}
+
+func cacheGenerationKey(namespace, resource, scope string) string {
+	return apiCacheGenerationPrefix + namespace + "/" + resource + "/" + scope
+}
+
+func (s *AdminServer) getCacheGeneration(key string) string {
+	var gen string
+	if err := s.APICache.Get(key, &gen); err != nil && err != persist.ErrCacheMiss {
+		s.log.Warn("failed to get api cache generation", log.Any("key", key), log.Error(err))
+	}
+	return gen
+}
+
+// cacheGeneration returns the generation of the resource served by the request, which is a part of the cache key,
+// so that the responses cached before the resource is changed are never hit again, even if they are cached after
+// the change by a request served concurrently. The routes of a named resource are of the generation of the resource,
+// and the others are of the generation of the list
+func (s *AdminServer) cacheGeneration(c *gin.Context) string {
+	ns, resource := c.GetString(common.KeyContextNamespace), routeResource(c.FullPath())
+	scope := cacheScopeList
+	if name := c.Param("name"); name != "" {
+		scope = name
+	}
+	return s.getCacheGeneration(cacheGenerationKey(ns, resource, cacheScopeType)) + "." +
+		s.getCacheGeneration(cacheGenerationKey(ns, resource, scope))
+}
+
+// routeCacheDuration returns the ttl of the cached responses of the route, the exact route is preferred,
+// then the longest matched pattern, and the cache duration of the admin server if none is matched,
+// which is bounded by NodeReportCacheDuration for the routes serving the reports of the nodes
+func (s *AdminServer) routeCacheDuration(route string) time.Duration {
+	dur := DefaultAPICacheDuration
+	if s.cfg.AdminServer.CacheDuration > 0 {
+		dur = s.cfg.AdminServer.CacheDuration
+	}
+	if nodeReportRoutes[route] && dur > NodeReportCacheDuration {
+		dur = NodeReportCacheDuration
+	}
+	routes := s.cfg.AdminServer.Cache.Routes
+	if d, ok := routes[route]; ok {
+		if d > 0 {
+			return d
+		}
+		return dur
+	}
+	matched := ""
+	for pattern, d := range routes {
+		if d <= 0 || len(pattern) <= len(matched) {
+			continue
+		}
+		if ok, _ := path.Match(pattern, route); ok {
+			matched, dur = pattern, d
+		}
+	}
+	return dur
+}
+
+// InvalidateCache evicts the cached responses of the resource and its list in the namespace,
+// all cached responses of the resource type are evicted if name is empty
+func (s *AdminServer) InvalidateCache(namespace, resourceType, name string) {
+	scopes := []string{cacheScopeType}
+	if name != "" {
+		scopes = []string{cacheScopeList, name}
+	}
+	for _, scope := range scopes {
+		key := cacheGenerationKey(namespace, resourceType, scope)
+		if err := s.APICache.Set(key, common.RandString(16), apiCacheGenerationTTL); err != nil {
+			s.log.Warn("failed to evict api cache", log.Any("key", key), log.Error(err))
+		}
+	}
+}
+
+// InvalidateCacheHandler evicts the cached responses of the resource changed by a successful mutating request
+func (s *AdminServer) InvalidateCacheHandler(c *gin.Context) {
+	c.Next()
+
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return
+	}
+	if c.FullPath() == "" || c.Writer.Status() >= http.StatusBadRequest || s.isReadRoute(c.Request.Method, c.FullPath()) {
+		return
+	}
+	s.InvalidateCache(common.NewContext(c).GetNamespace(), routeResource(c.FullPath()), c.Param("name"))
+}
+
+// InvalidateCacheOf evicts all cached responses of the resource types after a successful request changing them
+func (s *AdminServer) InvalidateCacheOf(resourceTypes ...string) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		ns := common.NewContext(c).GetNamespace()
+		for _, resourceType := range resourceTypes {
+			s.InvalidateCache(ns, resourceType, "")
+		}
+	}
+}
+
+// withNodeStats returns whether the nodes are listed with the latest stats, which are not cached,
+// otherwise the stats could be served beyond their freshness window
+func withNodeStats(c *gin.Context) bool {
+	res, _ := strconv.ParseBool(c.Query("includeStats"))
+	return res
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestNewAPICache(t *testing.T) {
+	store, err := newAPICache(config.APICache{Type: CacheTypeMemory})
+	assert.NoError(t, err)
+	assert.IsType(t, &persist.InMemoryStore{}, store)
+
+	store, err = newAPICache(config.APICache{Type: CacheTypeRedis, Address: "127.0.0.1:6379", DB: 1})
+	assert.NoError(t, err)
+	assert.IsType(t, &persist.RedisStore{}, store)
+	assert.Equal(t, 1, store.(*persist.RedisStore).RedisClient.Options().DB)
+
+	_, err = newAPICache(config.APICache{Type: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestAdminServer_InvalidateCache(t *testing.T) {
+	s := &AdminServer{
+		APICache: persist.NewInMemoryStore(time.Minute),
+		log:      log.L(),
+	}
+	calls := map[string]int{}
+	handler := func(c *common.Context) (interface{}, error) {
+		calls[c.Request.RequestURI]++
+		return calls[c.Request.RequestURI], nil
+	}
+	status := http.StatusOK
+	router := gin.New()
+	router.Use(func(c *gin.Context) { common.NewContext(c).SetNamespace("default") })
+	router.Use(s.InvalidateCacheHandler)
+	router.GET("/v1/apps", s.WrapperCacheDuration(handler, time.Minute))
+	router.GET("/v1/apps/:name", s.WrapperCacheDuration(handler, time.Minute))
+	router.GET("/v1/configs/:name", s.WrapperCacheDuration(handler, time.Minute))
+	router.PUT("/v1/apps/:name", func(c *gin.Context) { c.Status(status) })
+	router.POST("/v1/apps/preview-targets", func(c *gin.Context) { c.Status(http.StatusOK) })
+	s.ReadRoute(http.MethodPost, "/v1/apps/preview-targets")
+
+	get := func(uri string) string {
+		req := httptest.NewRequest(http.MethodGet, uri, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return strings.TrimSpace(w.Body.String())
+	}
+	put := func(uri string) {
+		req := httptest.NewRequest(http.MethodPut, uri, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	post := func(uri string) {
+		req := httptest.NewRequest(http.MethodPost, uri, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, "1", get("/v1/apps?pageNo=1"))
+	assert.Equal(t, "1", get("/v1/apps/a"))
+	assert.Equal(t, "1", get("/v1/apps/b"))
+	assert.Equal(t, "1", get("/v1/configs/a"))
+	assert.Equal(t, "1", get("/v1/apps?pageNo=1"))
+	assert.Equal(t, "1", get("/v1/apps/a"))
+
+	// failed writes keep the cache
+	status = http.StatusBadRequest
+	put("/v1/apps/a")
+	assert.Equal(t, "1", get("/v1/apps/a"))
+
+	// the read routes keep the cache
+	post("/v1/apps/preview-targets")
+	assert.Equal(t, "1", get("/v1/apps?pageNo=1"))
+
+	status = http.StatusOK
+	put("/v1/apps/a")
+	assert.Equal(t, "2", get("/v1/apps?pageNo=1"))
+	assert.Equal(t, "2", get("/v1/apps/a"))
+	assert.Equal(t, "1", get("/v1/apps/b"))
+	assert.Equal(t, "1", get("/v1/configs/a"))
+
+	s.InvalidateCache("default", "configs", "")
+	assert.Equal(t, "2", get("/v1/configs/a"))
+
+	// the responses cached by a request served during the write are not hit after the write
+	status = http.StatusOK
+	stale := make(chan struct{})
+	written := make(chan struct{})
+	router.GET("/v1/apps/:name/stale", s.WrapperCacheDuration(func(c *common.Context) (interface{}, error) {
+		close(stale)
+		<-written
+		return "stale", nil
+	}, time.Minute))
+	go func() {
+		<-stale
+		put("/v1/apps/c")
+		close(written)
+	}()
+	assert.Equal(t, "\"stale\"", get("/v1/apps/c/stale"))
+	assert.Equal(t, "1", get("/v1/apps/c"))
+
+	// the generations are kept in the shared store, so the writes of other replicas evict the cache too
+	other := &AdminServer{APICache: s.APICache, log: log.L()}
+	other.InvalidateCache("default", "apps", "b")
+	assert.Equal(t, "2", get("/v1/apps/b"))
+}
+
+func TestAdminServer_RouteCacheDuration(t *testing.T) {
+	cfg := &config.CloudConfig{}
+	s := &AdminServer{
+		cfg:      cfg,
+		APICache: persist.NewInMemoryStore(time.Minute),
+		log:      log.L(),
+	}
+	assert.Equal(t, DefaultAPICacheDuration, s.routeCacheDuration("/v1/modules"))
+	// the reports of the nodes are kept shortly unless configured
+	assert.Equal(t, NodeReportCacheDuration, s.routeCacheDuration("/v1/nodes/:name"))
+	assert.Equal(t, NodeReportCacheDuration, s.routeCacheDuration("/v1/nodes"))
+
+	cfg.AdminServer.CacheDuration = time.Minute
+	cfg.AdminServer.Cache.Routes = map[string]time.Duration{
+		"/v1/modules":        time.Hour,
+		"/v1/nodes/*/stats":  time.Millisecond * 10,
+		"/v1/nodes/*/*":      time.Second,
+		"/v1/nodes/:name/ab": 0,
+	}
+	assert.Equal(t, time.Hour, s.routeCacheDuration("/v1/modules"))
+	assert.Equal(t, time.Millisecond*10, s.routeCacheDuration("/v1/nodes/:name/stats"))
+	assert.Equal(t, time.Second, s.routeCacheDuration("/v1/nodes/:name/apps"))
+	assert.Equal(t, time.Minute, s.routeCacheDuration("/v1/nodes/:name/ab"))
+	assert.Equal(t, time.Minute, s.routeCacheDuration("/v1/apps"))
+	assert.Equal(t, NodeReportCacheDuration, s.routeCacheDuration("/v1/nodes/:name"))
+
+	// the responses expire by the ttl of their routes
+	cfg.AdminServer.CacheEnable = true
+	calls := 0
+	handler := func(c *common.Context) (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+	router := gin.New()
+	router.Use(func(c *gin.Context) { common.NewContext(c).SetNamespace("default") })
+	router.GET("/v1/modules", s.WrapperCache(handler))
+	router.GET("/v1/nodes/:name/stats", s.WrapperCache(handler))
+	get := func(uri string) string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, uri, nil))
+		return strings.TrimSpace(w.Body.String())
+	}
+	assert.Equal(t, "1", get("/v1/modules"))
+	assert.Equal(t, "2", get("/v1/nodes/a/stats"))
+	time.Sleep(time.Millisecond * 20)
+	assert.Equal(t, "1", get("/v1/modules"))
+	assert.Equal(t, "3", get("/v1/nodes/a/stats"))
+}
+
+func TestAdminServer_WrapperCacheUnless(t *testing.T) {
+	cfg := &config.CloudConfig{}
+	cfg.AdminServer.CacheEnable = true
+	s := &AdminServer{
+		cfg:      cfg,
+		APICache: persist.NewInMemoryStore(time.Minute),
+		log:      log.L(),
+	}
+	calls := 0
+	handler := func(c *common.Context) (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+	router := gin.New()
+	router.Use(func(c *gin.Context) { common.NewContext(c).SetNamespace("default") })
+	router.GET("/v1/nodes", s.WrapperCacheUnless(handler, withNodeStats))
+	get := func(uri string) string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, uri, nil))
+		return strings.TrimSpace(w.Body.String())
+	}
+	assert.Equal(t, "1", get("/v1/nodes"))
+	assert.Equal(t, "1", get("/v1/nodes"))
+	// the nodes with the stats are always served fresh
+	assert.Equal(t, "2", get("/v1/nodes?includeStats=true"))
+	assert.Equal(t, "3", get("/v1/nodes?includeStats=true"))
+	assert.Equal(t, "1", get("/v1/nodes"))
+}
+
+func TestAdminServer_CacheNotFound(t *testing.T) {
+	s := &AdminServer{
+		APICache: persist.NewInMemoryStore(time.Minute),
+		log:      log.L(),
+	}
+	found := false
+	handler := func(c *common.Context) (interface{}, error) {
+		if !found {
+			return nil, common.Error(common.ErrResourceNotFound, common.Field("type", common.APP), common.Field("name", c.Param("name")))
+		}
+		return c.Param("name"), nil
+	}
+	router := gin.New()
+	router.Use(func(c *gin.Context) { common.NewContext(c).SetNamespace("default") })
+	router.GET("/v1/apps/:name", s.WrapperCacheDuration(handler, time.Minute))
+
+	get := func(uri string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, uri, nil))
+		return w
+	}
+
+	w := get("/v1/apps/a")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "The (app) resource (a) is not found")
+
+	// the app is found once created, without waiting for the cache to expire
+	found = true
+	w = get("/v1/apps/a")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"a"`, strings.TrimSpace(w.Body.String()))
+
+	// the successful responses are cached
+	found = false
+	assert.Equal(t, http.StatusOK, get("/v1/apps/a").Code)
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	encodingGzip = "gzip"
+	encodingZstd = "zstd"
+)
+
+// CompressHandler compresses the responses with the encoding accepted by the client, the responses smaller than
+// the min size are sent as they are. It runs before the cache and the idempotency handlers, so the responses are
+// kept by them uncompressed and compressed again for each client on the way out.
+func (s *AdminServer) CompressHandler(c *gin.Context) {
+	cfg := s.cfg.AdminServer.Compression
+	if !cfg.Enable || c.Request.Method == http.MethodHead {
+		return
+	}
+	c.Writer.Header().Add("Vary", "Accept-Encoding")
+	encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), cfg.Encodings)
+	if encoding == "" {
+		return
+	}
+	w := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minSize: cfg.MinSize}
+	c.Writer = w
+	defer w.close()
+	c.Next()
+}
+
+// negotiateEncoding returns the supported encoding of the highest quality accepted by the client, the earlier
+// supported one is preferred if several are accepted equally
+func negotiateEncoding(accept string, supported []string) string {
+	if accept == "" {
+		return ""
+	}
+	qualities := map[string]float64{}
+	for _, item := range strings.Split(accept, ",") {
+		parts := strings.Split(item, ";")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		q := 1.0
+		for _, p := range parts[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") {
+				continue
+			}
+			v, err := strconv.ParseFloat(p[2:], 64)
+			if err != nil {
+				v = 0
+			}
+			q = v
+		}
+		qualities[name] = q
+	}
+	best, bestQ := "", 0.0
+	for _, enc := range supported {
+		if enc != encodingGzip && enc != encodingZstd {
+			continue
+		}
+		q, ok := qualities[enc]
+		if !ok {
+			q, ok = qualities["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the response until the min size is reached, then compresses it if it is not encoded yet
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+	buf      []byte
+	encoder  io.WriteCloser
+	// decided the response is being compressed or sent as it is
+	decided bool
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < w.minSize {
+		return len(b), nil
+	}
+	if err := w.decide(true); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written returns true if any of the body is buffered, so the handlers don't write the response twice
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// WriteHeaderNow sends the headers, so the response can't be compressed anymore
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush sends the buffered response, the streams are compressed and flushed chunk by chunk
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(true)
+	}
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.decided = true
+	return w.ResponseWriter.Hijack()
+}
+
+// decide compresses the response if wanted and allowed, and writes the buffered body
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.ResponseWriter.Header()
+	if compress && !w.ResponseWriter.Written() && header.Get("Content-Encoding") == "" && bodyAllowed(w.Status()) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		var err error
+		switch w.encoding {
+		case encodingZstd:
+			w.encoder, err = zstd.NewWriter(w.ResponseWriter, zstd.WithEncoderConcurrency(1))
+		default:
+			w.encoder = gzip.NewWriter(w.ResponseWriter)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close sends the response smaller than the min size as it is, or completes the compressed one
+func (w *compressWriter) close() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+	}
+}
+
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	supported := []string{encodingGzip, encodingZstd}
+	assert.Equal(t, "", negotiateEncoding("", supported))
+	assert.Equal(t, "", negotiateEncoding("identity", supported))
+	assert.Equal(t, encodingGzip, negotiateEncoding("gzip, deflate, br", supported))
+	assert.Equal(t, encodingZstd, negotiateEncoding("zstd", supported))
+	assert.Equal(t, encodingGzip, negotiateEncoding("zstd, gzip", supported))
+	assert.Equal(t, encodingZstd, negotiateEncoding("zstd, gzip;q=0.5", supported))
+	assert.Equal(t, encodingZstd, negotiateEncoding("gzip;q=0, *", supported))
+	assert.Equal(t, "", negotiateEncoding("gzip;q=0", supported))
+	assert.Equal(t, encodingZstd, negotiateEncoding("GZIP, ZSTD", []string{encodingZstd, encodingGzip}))
+	assert.Equal(t, "", negotiateEncoding("br", []string{"br"}))
+}
+
+func TestAdminServer_CompressHandler(t *testing.T) {
+	cfg := &config.CloudConfig{}
+	cfg.AdminServer.Compression = config.Compression{Enable: true, MinSize: 100, Encodings: []string{encodingGzip, encodingZstd}}
+	s := &AdminServer{
+		cfg:      cfg,
+		APICache: persist.NewInMemoryStore(time.Minute),
+		log:      log.L(),
+	}
+	large := strings.Repeat("baetyl", 100)
+	calls := 0
+	router := gin.New()
+	router.Use(s.CompressHandler)
+	router.Use(func(c *gin.Context) { common.NewContext(c).SetNamespace("default") })
+	router.GET("/small", func(c *gin.Context) { c.String(http.StatusOK, "small") })
+	router.GET("/large", func(c *gin.Context) { c.String(http.StatusOK, large) })
+	router.GET("/encoded", func(c *gin.Context) {
+		c.Header("Content-Encoding", "br")
+		c.String(http.StatusOK, large)
+	})
+	router.GET("/cached", s.WrapperCacheDuration(func(c *common.Context) (interface{}, error) {
+		calls++
+		return map[string]string{"data": large}, nil
+	}, time.Minute))
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept-Encoding", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) string {
+		var r io.Reader
+		switch w.Header().Get("Content-Encoding") {
+		case encodingGzip:
+			gr, err := gzip.NewReader(w.Body)
+			assert.NoError(t, err)
+			r = gr
+		case encodingZstd:
+			zr, err := zstd.NewReader(w.Body)
+			assert.NoError(t, err)
+			defer zr.Close()
+			r = zr
+		default:
+			r = w.Body
+		}
+		buf, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		return string(buf)
+	}
+
+	// the small responses are not compressed
+	w := get("/small", "gzip")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, "small", w.Body.String())
+
+	w = get("/large", "gzip")
+	assert.Equal(t, encodingGzip, w.Header().Get("Content-Encoding"))
+	assert.Less(t, w.Body.Len(), len(large))
+	assert.Equal(t, large, decode(w))
+
+	w = get("/large", "zstd")
+	assert.Equal(t, encodingZstd, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, decode(w))
+
+	w = get("/large", "")
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, w.Body.String())
+
+	// the responses encoded by the handlers are not compressed again
+	w = get("/encoded", "gzip")
+	assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, w.Body.String())
+
+	// the cached responses are kept uncompressed and encoded for each client
+	w = get("/cached", "gzip")
+	assert.Equal(t, encodingGzip, w.Header().Get("Content-Encoding"))
+	expect := decode(w)
+	assert.Contains(t, expect, large)
+	w = get("/cached", "")
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, expect, w.Body.String())
+	w = get("/cached", "zstd")
+	assert.Equal(t, encodingZstd, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, expect, decode(w))
+	assert.Equal(t, 1, calls)
+
+	// disabled
+	cfg.AdminServer.Compression.Enable = false
+	w = get("/large", "gzip")
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, w.Body.String())
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+// checkCORS refuses the credentials allowed to any origin, which would let any site call the apis as the users
+func checkCORS(cfg config.CORS) error {
+	if cfg.AllowCredentials && corsContains(cfg.AllowOrigins, "*") {
+		return errors.New("the credentials can't be allowed to any origin (*), list the origins allowed instead")
+	}
+	return nil
+}
+
+// CORSHandler allows the cross-origin requests from the origins configured, the requests from other origins are
+// served without the CORS headers, so they are denied by the browsers. The preflight requests are answered here
+// before the authentication, as the browsers send them without the credentials
+func (s *AdminServer) CORSHandler(c *gin.Context) {
+	origin := c.GetHeader("Origin")
+	if origin == "" {
+		return
+	}
+	cfg := s.cfg.AdminServer.CORS
+	c.Writer.Header().Add("Vary", "Origin")
+	allowed, wildcard := corsOriginAllowed(cfg.AllowOrigins, origin)
+	preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+	if !allowed {
+		if preflight {
+			c.AbortWithStatus(http.StatusForbidden)
+		}
+		return
+	}
+
+	h := c.Writer.Header()
+	if wildcard {
+		// any origin is allowed without the credentials
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+		if cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+	}
+	if !preflight {
+		if len(cfg.ExposeHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposeHeaders, ", "))
+		}
+		return
+	}
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	if !corsContains(cfg.AllowMethods, c.GetHeader("Access-Control-Request-Method")) {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	for _, header := range strings.Split(c.GetHeader("Access-Control-Request-Headers"), ",") {
+		if header = strings.TrimSpace(header); header != "" && !corsContains(cfg.AllowHeaders, header) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowMethods, ", "))
+	if len(cfg.AllowHeaders) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowHeaders, ", "))
+	}
+	if cfg.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+	}
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// corsOriginAllowed returns whether the origin is one of the allowed ones, or whether it is allowed only as any origin by *
+func corsOriginAllowed(allowed []string, origin string) (bool, bool) {
+	wildcard := false
+	for _, o := range allowed {
+		if o == "*" {
+			wildcard = true
+		} else if strings.EqualFold(strings.TrimSuffix(o, "/"), origin) {
+			return true, false
+		}
+	}
+	return wildcard, wildcard
+}
+
+// corsContains returns whether the method or the header is in the list, case-insensitively
+func corsContains(list []string, v string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestAdminServer_CORSHandler(t *testing.T) {
+	cfg := &config.CloudConfig{}
+	cfg.AdminServer.CORS = config.CORS{
+		AllowMethods:  []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowHeaders:  []string{"Authorization", "Content-Type"},
+		ExposeHeaders: []string{"X-Request-Id"},
+		MaxAge:        10 * time.Minute,
+	}
+	s := &AdminServer{cfg: cfg}
+	authed := 0
+	router := gin.New()
+	router.Use(s.CORSHandler)
+	v1 := router.Group("/v1", func(c *gin.Context) {
+		authed++
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		}
+	})
+	v1.GET("/nodes", func(c *gin.Context) { c.Status(http.StatusOK) })
+	do := func(method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, "/v1/nodes", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	preflight := map[string]string{"Access-Control-Request-Method": "PUT", "Access-Control-Request-Headers": "authorization, content-type"}
+
+	// all origins are denied by default
+	w := do(http.MethodOptions, "https://console.example.com", preflight)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	w = do(http.MethodGet, "https://console.example.com", map[string]string{"Authorization": "t"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	cfg.AdminServer.CORS.AllowOrigins = []string{"https://console.example.com"}
+	cfg.AdminServer.CORS.AllowCredentials = true
+	authed = 0
+
+	// the preflight is answered without the authentication
+	w = do(http.MethodOptions, "https://console.example.com", preflight)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://console.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "GET, POST, PUT, PATCH, DELETE", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization, Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, 0, authed)
+
+	// the methods and the headers not allowed
+	w = do(http.MethodOptions, "https://console.example.com", map[string]string{"Access-Control-Request-Method": "TRACE"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = do(http.MethodOptions, "https://console.example.com", map[string]string{"Access-Control-Request-Method": "GET", "Access-Control-Request-Headers": "x-custom"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// the actual request
+	w = do(http.MethodGet, "https://console.example.com", map[string]string{"Authorization": "t"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://console.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-Id", w.Header().Get("Access-Control-Expose-Headers"))
+	assert.Equal(t, []string{"Origin"}, w.Header().Values("Vary"))
+	w = do(http.MethodGet, "https://evil.example.com", map[string]string{"Authorization": "t"})
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	// the requests without origin are not cross-origin
+	w = do(http.MethodGet, "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, w.Header().Get("Vary"))
+
+	// any origin is allowed without the credentials, the origins listed keep them
+	cfg.AdminServer.CORS.AllowOrigins = []string{"*", "https://console.example.com"}
+	w = do(http.MethodGet, "https://evil.example.com", map[string]string{"Authorization": "t"})
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	w = do(http.MethodOptions, "https://evil.example.com", preflight)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	w = do(http.MethodGet, "https://console.example.com", map[string]string{"Authorization": "t"})
+	assert.Equal(t, "https://console.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCheckCORS(t *testing.T) {
+	assert.NoError(t, checkCORS(config.CORS{AllowOrigins: []string{"*"}}))
+	assert.NoError(t, checkCORS(config.CORS{AllowOrigins: []string{"https://console.example.com"}, AllowCredentials: true}))
+	assert.Error(t, checkCORS(config.CORS{AllowOrigins: []string{"https://console.example.com", "*"}, AllowCredentials: true}))
+}
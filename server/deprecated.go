@@ -0,0 +1,18 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// GoneHandler rejects the requests of the deprecated v1 apis disabled with 410,
+// the v2 api of the same path is returned in the message and the Link header
+func (s *AdminServer) GoneHandler(c *gin.Context) {
+	replacement := "/v2" + strings.TrimPrefix(c.Request.URL.Path, "/v1")
+	c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", replacement))
+	common.PopulateFailedResponse(common.NewContext(c), common.Error(common.ErrAPIGone, common.Field("replacement", replacement)), true)
+}
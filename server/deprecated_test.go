@@ -0,0 +1,23 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminServer_GoneHandler(t *testing.T) {
+	s := &AdminServer{}
+	router := gin.New()
+	router.GET("/v1/objects/:source/buckets", s.GoneHandler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/objects/awss3/buckets?account=other", nil))
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Equal(t, `</v2/objects/awss3/buckets>; rel="successor-version"`, w.Header().Get("Link"))
+	assert.Contains(t, w.Body.String(), "ErrAPIGone")
+	assert.Contains(t, w.Body.String(), "please use /v2/objects/awss3/buckets instead")
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// drainState tracks the requests in flight, the contexts of the streaming ones are canceled at shutdown
+// since the server can't wait for them to end
+type drainState struct {
+	inflight int64
+	sync.Mutex
+	closing bool
+	next    int
+	streams map[int]context.CancelCauseFunc
+}
+
+// addStream registers the cancel of a streaming request, which is canceled at once if the server is closing
+func (d *drainState) addStream(cancel context.CancelCauseFunc) int {
+	d.Lock()
+	defer d.Unlock()
+	if d.closing {
+		cancel(common.ErrShutdown)
+		return -1
+	}
+	if d.streams == nil {
+		d.streams = map[int]context.CancelCauseFunc{}
+	}
+	d.next++
+	d.streams[d.next] = cancel
+	return d.next
+}
+
+func (d *drainState) removeStream(id int) {
+	d.Lock()
+	defer d.Unlock()
+	delete(d.streams, id)
+}
+
+// closeStreams cancels the streaming requests and returns how many are canceled
+func (d *drainState) closeStreams() int {
+	d.Lock()
+	defer d.Unlock()
+	d.closing = true
+	for _, cancel := range d.streams {
+		cancel(common.ErrShutdown)
+	}
+	return len(d.streams)
+}
+
+// DrainHandler counts the requests in flight
+func (s *AdminServer) DrainHandler(c *gin.Context) {
+	atomic.AddInt64(&s.drain.inflight, 1)
+	defer atomic.AddInt64(&s.drain.inflight, -1)
+	c.Next()
+}
+
+// StreamHandler marks the request as streaming, the context of which is canceled with common.ErrShutdown
+// when the server shuts down, so the handler can close the stream with a clean message
+func (s *AdminServer) StreamHandler(c *gin.Context) {
+	ctx, cancel := context.WithCancelCause(c.Request.Context())
+	defer cancel(nil)
+	id := s.drain.addStream(cancel)
+	defer s.drain.removeStream(id)
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestAdminServer_Drain(t *testing.T) {
+	s := &AdminServer{cfg: &config.CloudConfig{}, server: &http.Server{}, log: log.L()}
+	s.cfg.AdminServer.ShutdownTime = time.Second
+
+	router := gin.New()
+	router.Use(s.DrainHandler)
+	started := make(chan struct{})
+	router.GET("/stream", s.StreamHandler, func(c *gin.Context) {
+		close(started)
+		<-c.Request.Context().Done()
+		assert.True(t, common.NewContext(c).IsShutdown())
+		c.String(http.StatusOK, common.ErrShutdown.Error())
+	})
+	router.GET("/ping", func(c *gin.Context) {
+		assert.Equal(t, int64(1), s.drain.inflight)
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(0), s.drain.inflight)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+		done <- w
+	}()
+	<-started
+	assert.Equal(t, int64(1), s.drain.inflight)
+	s.Close()
+	w = <-done
+	assert.Equal(t, "the server is shutting down", w.Body.String())
+	assert.Len(t, s.drain.streams, 0)
+
+	// the streams started after closing are canceled at once
+	ctx, cancel := context.WithCancelCause(context.Background())
+	assert.Equal(t, -1, s.drain.addStream(cancel))
+	assert.Equal(t, common.ErrShutdown, context.Cause(ctx))
+}
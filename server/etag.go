@@ -0,0 +1,133 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+const (
+	// etagMaxBodySize the max size of the bodies buffered to be tagged, the larger ones are written through untagged
+	etagMaxBodySize = 4 << 20
+)
+
+// wrapperETag tags the successful responses of the handler with the hash of their bodies, unless the handler tags them
+// such as by the resource versions, and responds 304 without the body if the client already has it. The tag is weak
+// since the body may be compressed on the way out. The tag is computed once the handler returns, after the cached
+// response is replayed if it is a hit, so it is computed for the hits as well. The responses streamed or larger than
+// etagMaxBodySize are not tagged
+func wrapperETag(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			handler(c)
+			return
+		}
+		w := &etagWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer func() {
+			c.Writer = w.ResponseWriter
+			w.flush(c.GetHeader("If-None-Match"))
+		}()
+		handler(c)
+	}
+}
+
+// etagWriter buffers the body until the handler returns
+type etagWriter struct {
+	gin.ResponseWriter
+	buf []byte
+	// through the body is written through untagged, since it is streamed or too large
+	through bool
+}
+
+func (w *etagWriter) Write(b []byte) (int, error) {
+	if !w.through && len(w.buf)+len(b) > etagMaxBodySize {
+		if err := w.writeThrough(); err != nil {
+			return 0, err
+		}
+	}
+	if w.through {
+		return w.ResponseWriter.Write(b)
+	}
+	w.buf = append(w.buf, b...)
+	return len(b), nil
+}
+
+func (w *etagWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written returns true if any of the body is buffered, so the handlers don't write the response twice
+func (w *etagWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush streams the body, which is written through untagged from then on
+func (w *etagWriter) Flush() {
+	_ = w.writeThrough()
+	w.ResponseWriter.Flush()
+}
+
+// writeThrough writes the body buffered so far, and the rest of the body is not buffered any more
+func (w *etagWriter) writeThrough() error {
+	if w.through {
+		return nil
+	}
+	w.through = true
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// tag sets the tag of the successful response if the handler doesn't set one
+func (w *etagWriter) tag() string {
+	if w.through || w.Status() != http.StatusOK {
+		return ""
+	}
+	if tag := w.Header().Get(common.HeaderETag); tag != "" {
+		return tag
+	}
+	sum := sha256.Sum256(w.buf)
+	tag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set(common.HeaderETag, tag)
+	return tag
+}
+
+func (w *etagWriter) flush(ifNoneMatch string) {
+	if w.through {
+		return
+	}
+	if tag := w.tag(); tag != "" && matchETag(ifNoneMatch, tag) {
+		w.Header().Del("Content-Type")
+		w.Header().Del("Content-Length")
+		w.ResponseWriter.WriteHeader(http.StatusNotModified)
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	if len(w.buf) > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf)
+	}
+}
+
+// matchETag compares the tags weakly, as If-None-Match is supposed to
+func matchETag(ifNoneMatch, tag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestAdminServer_ETag(t *testing.T) {
+	s := &AdminServer{
+		cfg:      &config.CloudConfig{},
+		APICache: persist.NewInMemoryStore(time.Minute),
+		log:      log.L(),
+	}
+	calls, value := 0, "v1"
+	handler := func(c *common.Context) (interface{}, error) {
+		calls++
+		if value == "" {
+			return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "app"), common.Field("name", "a"), common.Field("namespace", "default"))
+		}
+		return map[string]string{"value": value}, nil
+	}
+	router := gin.New()
+	router.Use(func(c *gin.Context) { common.NewContext(c).SetNamespace("default") })
+	router.Use(s.InvalidateCacheHandler)
+	router.GET("/v1/apps/:name", s.WrapperCacheDuration(handler, time.Minute))
+	router.GET("/v1/nodes", s.WrapperCache(handler))
+	router.GET("/v1/logs", wrapperETag(func(c *gin.Context) {
+		c.String(http.StatusOK, "a")
+		c.Writer.Flush()
+		c.String(http.StatusOK, "b")
+	}))
+	router.GET("/v1/objects", wrapperETag(func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/octet-stream", make([]byte, etagMaxBodySize+1))
+	}))
+	do := func(path, tag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if tag != "" {
+			req.Header.Set("If-None-Match", tag)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// the tag is cached along with the response
+	w := do("/v1/apps/a", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"value":"v1"}`, w.Body.String())
+	tag := w.Header().Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, tag)
+	w = do("/v1/apps/a", tag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	w = do("/v1/apps/a", `"other", `+tag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	w = do("/v1/apps/a", `W/"other"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, tag, w.Header().Get("ETag"))
+	assert.Equal(t, 1, calls)
+
+	// the tag changes along with the response
+	value = "v2"
+	s.InvalidateCache("default", "apps", "a")
+	w = do("/v1/apps/a", tag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"value":"v2"}`, w.Body.String())
+	assert.NotEqual(t, tag, w.Header().Get("ETag"))
+	assert.Equal(t, 2, calls)
+
+	// the responses are tagged without the cache
+	w = do("/v1/nodes", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	tag = w.Header().Get("ETag")
+	assert.NotEmpty(t, tag)
+	w = do("/v1/nodes", tag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, 4, calls)
+
+	// the failures are not tagged
+	value = ""
+	w = do("/v1/nodes", "*")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), "ErrResourceNotFound")
+
+	// the responses streamed or too large are written through untagged
+	w = do("/v1/logs", "*")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ab", w.Body.String())
+	assert.Empty(t, w.Header().Get("ETag"))
+	w = do("/v1/objects", "*")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, w.Body.Bytes(), etagMaxBodySize+1)
+	assert.Empty(t, w.Header().Get("ETag"))
+
+	// the tag is set for the hits replayed by the cache
+	value = "v3"
+	s.InvalidateCache("default", "apps", "a")
+	w = do("/v1/apps/a", "")
+	tag = w.Header().Get("ETag")
+	assert.NotEmpty(t, tag)
+	w = do("/v1/apps/a", "")
+	assert.Equal(t, tag, w.Header().Get("ETag"))
+	w = do("/v1/apps/a", tag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// the tags set by the handlers are kept, and replayed by the cache
+	router.GET("/v1/configs/:name", s.WrapperCacheDuration(func(c *common.Context) (interface{}, error) {
+		calls++
+		c.Header(common.HeaderETag, `W/"7"`)
+		return map[string]string{"value": value}, nil
+	}, time.Minute))
+	calls = 0
+	for i := 0; i < 2; i++ {
+		w = do("/v1/configs/c", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `W/"7"`, w.Header().Get("ETag"))
+	}
+	w = do("/v1/configs/c", `"7"`)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, 1, calls)
+}
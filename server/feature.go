@@ -0,0 +1,17 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// FeatureHandler rejects the requests with 403 if the feature is not enabled in the namespace of the request
+func (s *AdminServer) FeatureHandler(flag string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cc := common.NewContext(c)
+		if err := s.api.CheckFeature(cc.GetNamespace(), flag); err != nil {
+			common.PopulateFailedResponse(cc, err, true)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/api"
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAdminServer_FeatureHandler(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sNS := ms.NewMockNamespaceService(mockCtl)
+	s := &AdminServer{api: &api.API{NS: sNS}}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) { common.NewContext(c).SetNamespace("default") })
+	router.GET("/v1/functions/:source/functions", s.FeatureHandler(models.FeatureFunctions), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/functions/baidu-cfc/functions", nil))
+		return w
+	}
+
+	sNS.EXPECT().IsFeatureEnabled("default", models.FeatureFunctions).Return(true, nil)
+	assert.Equal(t, http.StatusOK, get().Code)
+
+	sNS.EXPECT().IsFeatureEnabled("default", models.FeatureFunctions).Return(false, nil)
+	w := get()
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrFeatureDisabled")
+}
@@ -3,14 +3,19 @@ package server
 import (
 	"bytes"
 	"io"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
 	"github.com/gin-gonic/gin"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 var (
@@ -25,6 +30,16 @@ func NoMethodHandler(c *gin.Context) {
 	common.PopulateFailedResponse(common.NewContext(c), common.Error(common.ErrRequestMethodNotFound), true)
 }
 
+// newRouter creates the router of the server, the client IP is taken from X-Forwarded-For and X-Real-IP
+// only if the request is from the trusted proxies, otherwise it is the remote address
+func newRouter(cfg config.Server) (*gin.Engine, error) {
+	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return router, nil
+}
+
 func RequestIDHandler(c *gin.Context) {
 	cc := common.NewContext(c)
 	cc.SetTrace()
@@ -60,10 +75,35 @@ func LoggerHandler(c *gin.Context) {
 	)
 }
 
+// Health the liveness of the server, ok as long as the server serves
 func Health(c *gin.Context) {
 	c.JSON(common.PackageResponse(nil))
 }
 
+// HealthCheck reports the status of each dependency of the server, 503 if the server is unhealthy
+func HealthCheck(hs service.HealthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res := hs.Check(c.Request.Context())
+		if res.Status == models.HealthStatusUnhealthy {
+			c.JSON(http.StatusServiceUnavailable, res)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// Ready the readiness of the server, not ready if a critical dependency is down
+func Ready(hs service.HealthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res := hs.Check(c.Request.Context())
+		if res.Status == models.HealthStatusUnhealthy {
+			c.JSON(http.StatusServiceUnavailable, res)
+			return
+		}
+		c.JSON(common.PackageResponse(nil))
+	}
+}
+
 func ExtractNodeCommonNameFromCert(c *gin.Context) {
 	cc := common.NewContext(c)
 	if len(c.Request.TLS.PeerCertificates) == 0 {
@@ -79,9 +119,26 @@ func ExtractNodeCommonNameFromHeader(c *gin.Context) {
 	extractNodeCommonName(cc, c.GetHeader(HeaderCommonName))
 }
 
+// ExtractNodeFromToken extracts the node of the node token in the authorization header
+func ExtractNodeFromToken(check NodeTokenChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cc := common.NewContext(c)
+		ns, name, err := check(c.GetHeader("Authorization"))
+		if err != nil {
+			log.L().Error("check node token error", log.Any(cc.GetTrace()), log.Error(err))
+			common.PopulateFailedResponse(cc, common.Error(common.ErrRequestAccessDenied), true)
+			return
+		}
+		cc.SetNamespace(ns)
+		cc.SetName(name)
+	}
+}
+
+// extractNodeCommonName extracts the node of the common name <namespace>.<node>, the names which can't be of nodes,
+// such as the ones of the certificates signed for the csrs, are denied
 func extractNodeCommonName(cc *common.Context, commonName string) {
 	res := strings.SplitN(commonName, ".", 2)
-	if len(res) != 2 || res[0] == "" || res[1] == "" {
+	if len(res) != 2 || res[0] == "" || common.ValidateResourceName(res[1]) != nil {
 		log.L().Error("extract node common name error",
 			log.Any(cc.GetTrace()),
 			log.Any("commonName", commonName),
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestExtractNodeCommonNameFromHeader(t *testing.T) {
+	router := gin.New()
+	router.GET("/v1/sync", ExtractNodeCommonNameFromHeader, func(c *gin.Context) {
+		cc := common.NewContext(c)
+		c.String(http.StatusOK, cc.GetNamespace()+"/"+cc.GetName())
+	})
+	get := func(cn string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/sync", nil)
+		req.Header.Set(HeaderCommonName, cn)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("default.n0.edge")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "default/n0.edge", w.Body.String())
+
+	// the common names of the certificates signed for the csrs are not of nodes
+	for _, cn := range []string{"default.client:console", "default", ".n0", "default.", "default.N0"} {
+		w = get(cn)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, cn)
+	}
+}
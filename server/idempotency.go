@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotencyReplayed = "Idempotency-Replayed"
+
+	idempotencyCacheKeyPrefix = "baetyl-cloud:idempotency:"
+	idempotencyKeyMaxLength   = 255
+)
+
+// idempotentResponse the response of a creating request kept for its retries,
+// the request is still in progress if the status is zero
+type idempotentResponse struct {
+	BodyHash    string
+	Status      int
+	ContentType string
+	Data        []byte
+}
+
+type idempotentResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotentResponseWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotentResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// isCreatingRoute returns whether the route creates a resource of the collection, such as /v1/apps
+func isCreatingRoute(method, route string) bool {
+	if method != http.MethodPost {
+		return false
+	}
+	parts := strings.Split(strings.Trim(route, "/"), "/")
+	return len(parts) == 2 && !strings.HasPrefix(parts[1], ":")
+}
+
+// IdempotencyHandler replays the response of the first successful creating request to the retries with the same Idempotency-Key,
+// so that the resource is not created twice. The keys are scoped by namespace and route, and the key reused with another body is rejected
+func (s *AdminServer) IdempotencyHandler(c *gin.Context) {
+	idemKey := c.GetHeader(HeaderIdempotencyKey)
+	if !s.cfg.AdminServer.Idempotency.Enable || idemKey == "" || !isCreatingRoute(c.Request.Method, c.FullPath()) {
+		return
+	}
+	cc := common.NewContext(c)
+	if len(idemKey) > idempotencyKeyMaxLength {
+		common.PopulateFailedResponse(cc, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the idempotency key is too long")), true)
+		return
+	}
+
+	var bodyHash string
+	if c.Request.Body != nil {
+		buf, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			common.PopulateFailedResponse(cc, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error())), true)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(buf))
+		if len(buf) > 0 {
+			sum := sha256.Sum256(buf)
+			bodyHash = hex.EncodeToString(sum[:])
+		}
+	}
+
+	key := idempotencyCacheKeyPrefix + cc.GetNamespace() + ":" + c.FullPath() + ":" + idemKey
+	ttl := s.cfg.AdminServer.Idempotency.TTL
+	// the marker of the request in progress is set only if the key is absent, so that the concurrent retries never
+	// get through together
+	added, err := service.AddCacheValue(s.APICache, key, idempotentResponse{BodyHash: bodyHash}, ttl)
+	if err != nil {
+		// the request is still processed without the guarantee if the store is unavailable
+		s.log.Warn("failed to set idempotent response", log.Any("key", key), log.Error(err))
+		return
+	}
+	if !added {
+		var res idempotentResponse
+		if err = s.APICache.Get(key, &res); err != nil {
+			// the marker is removed or expired in between, which is taken as the request in progress
+			res = idempotentResponse{BodyHash: bodyHash}
+		}
+		if res.BodyHash != bodyHash {
+			common.PopulateFailedResponse(cc, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "the idempotency key is already used by another request")), true)
+			return
+		}
+		if res.Status == 0 {
+			common.PopulateFailedResponse(cc, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "the request with the same idempotency key is in progress")), true)
+			return
+		}
+		c.Header(HeaderIdempotencyReplayed, "true")
+		c.Data(res.Status, res.ContentType, res.Data)
+		c.Abort()
+		return
+	}
+
+	// only the successful responses are kept, the marker of the request failed or panicked is removed,
+	// so that it can be retried with the same key
+	kept := false
+	defer func() {
+		if kept {
+			return
+		}
+		if err := s.APICache.Delete(key); err != nil && err != persist.ErrCacheMiss {
+			s.log.Warn("failed to delete idempotent response", log.Any("key", key), log.Error(err))
+		}
+	}()
+	writer := &idempotentResponseWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+	c.Next()
+
+	if c.Writer.Status() >= http.StatusBadRequest {
+		return
+	}
+	res := idempotentResponse{
+		BodyHash:    bodyHash,
+		Status:      c.Writer.Status(),
+		ContentType: c.Writer.Header().Get("Content-Type"),
+		Data:        writer.body.Bytes(),
+	}
+	if err = s.APICache.Set(key, res, ttl); err != nil {
+		s.log.Warn("failed to set idempotent response", log.Any("key", key), log.Error(err))
+		return
+	}
+	kept = true
+}
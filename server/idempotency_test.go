@@ -0,0 +1,120 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestIsCreatingRoute(t *testing.T) {
+	assert.True(t, isCreatingRoute(http.MethodPost, "/v1/apps"))
+	assert.True(t, isCreatingRoute(http.MethodPost, "/v2/objects"))
+	assert.False(t, isCreatingRoute(http.MethodPut, "/v1/apps"))
+	assert.False(t, isCreatingRoute(http.MethodPost, "/v1/apps/:name"))
+	assert.False(t, isCreatingRoute(http.MethodPost, "/v1/nodes/:name/drain"))
+	assert.False(t, isCreatingRoute(http.MethodPost, ""))
+}
+
+func TestAdminServer_IdempotencyHandler(t *testing.T) {
+	cfg := &config.CloudConfig{}
+	cfg.AdminServer.Idempotency = config.Idempotency{Enable: true, TTL: time.Minute}
+	s := &AdminServer{
+		cfg:      cfg,
+		APICache: persist.NewInMemoryStore(time.Minute),
+		log:      log.L(),
+	}
+	created := 0
+	router := gin.New()
+	router.Use(func(c *gin.Context) { common.NewContext(c).SetNamespace(c.Query("ns")) })
+	router.Use(s.IdempotencyHandler)
+	router.POST("/v1/apps", func(c *gin.Context) {
+		if c.Query("panic") != "" {
+			panic("unexpected")
+		}
+		if c.Query("fail") != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"code": "error"})
+			return
+		}
+		created++
+		c.JSON(http.StatusOK, gin.H{"name": fmt.Sprintf("app-%d", created)})
+	})
+	router.PUT("/v1/apps/:name", func(c *gin.Context) {
+		created++
+		c.Status(http.StatusOK)
+	})
+
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// the retry gets the original response
+	w := do(http.MethodPost, "/v1/apps?ns=default", "k1", `{"name":"a"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"name":"app-1"}`, w.Body.String())
+	assert.Empty(t, w.Header().Get(HeaderIdempotencyReplayed))
+	w = do(http.MethodPost, "/v1/apps?ns=default", "k1", `{"name":"a"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"name":"app-1"}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(HeaderIdempotencyReplayed))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, 1, created)
+
+	// the key reused with another body is rejected
+	w = do(http.MethodPost, "/v1/apps?ns=default", "k1", `{"name":"b"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "the idempotency key is already used by another request")
+
+	// the keys are scoped by namespace, and the requests without key are not affected
+	w = do(http.MethodPost, "/v1/apps?ns=other", "k1", `{"name":"a"}`)
+	assert.Equal(t, `{"name":"app-2"}`, w.Body.String())
+	w = do(http.MethodPost, "/v1/apps?ns=default", "", `{"name":"a"}`)
+	assert.Equal(t, `{"name":"app-3"}`, w.Body.String())
+
+	// the failed requests are not kept
+	w = do(http.MethodPost, "/v1/apps?ns=default&fail=1", "k2", `{"name":"c"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = do(http.MethodPost, "/v1/apps?ns=default", "k2", `{"name":"c"}`)
+	assert.Equal(t, `{"name":"app-4"}`, w.Body.String())
+
+	// the marker of the request panicked is removed
+	assert.Panics(t, func() { do(http.MethodPost, "/v1/apps?ns=default&panic=1", "k5", `{"name":"d"}`) })
+	w = do(http.MethodPost, "/v1/apps?ns=default", "k5", `{"name":"d"}`)
+	assert.Equal(t, `{"name":"app-5"}`, w.Body.String())
+
+	// the request in progress
+	assert.NoError(t, s.APICache.Set(idempotencyCacheKeyPrefix+"default:/v1/apps:k3", idempotentResponse{BodyHash: ""}, time.Minute))
+	w = do(http.MethodPost, "/v1/apps?ns=default", "k3", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "in progress")
+
+	// the key is too long
+	w = do(http.MethodPost, "/v1/apps?ns=default", strings.Repeat("k", idempotencyKeyMaxLength+1), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// only the creating requests are affected
+	do(http.MethodPut, "/v1/apps/a?ns=default", "k4", "")
+	do(http.MethodPut, "/v1/apps/a?ns=default", "k4", "")
+	assert.Equal(t, 7, created)
+
+	// disabled
+	cfg.AdminServer.Idempotency.Enable = false
+	w = do(http.MethodPost, "/v1/apps?ns=default", "k1", `{"name":"a"}`)
+	assert.Equal(t, `{"name":"app-8"}`, w.Body.String())
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/api"
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 type InitServer struct {
@@ -18,11 +19,20 @@ type InitServer struct {
 	router *gin.Engine
 	server *http.Server
 	api    *api.InitAPI
+	health service.HealthService
 }
 
 // NewInitServer new init server
 func NewInitServer(config *config.CloudConfig) (*InitServer, error) {
-	router := gin.New()
+	health, err := service.NewHealthService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := newRouter(config.InitServer)
+	if err != nil {
+		return nil, err
+	}
 	server := &http.Server{
 		Addr:           config.InitServer.Port,
 		Handler:        router,
@@ -46,6 +56,7 @@ func NewInitServer(config *config.CloudConfig) (*InitServer, error) {
 		cfg:    config,
 		router: router,
 		server: server,
+		health: health,
 	}, nil
 }
 
@@ -80,7 +91,9 @@ func (s *InitServer) GetRoute() *gin.Engine {
 func (s *InitServer) InitRoute() {
 	s.router.NoRoute(NoRouteHandler)
 	s.router.NoMethod(NoMethodHandler)
-	s.router.GET("/health", Health)
+	s.router.GET("/health", HealthCheck(s.health))
+	s.router.GET("/health/live", Health)
+	s.router.GET("/health/ready", Ready(s.health))
 
 	s.router.Use(RequestIDHandler)
 	s.router.Use(LoggerHandler)
@@ -12,6 +12,7 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/api"
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 // MisServer mis server
@@ -20,11 +21,20 @@ type MisServer struct {
 	router *gin.Engine
 	server *http.Server
 	api    *api.API // TODO: define independent api
+	health service.HealthService
 }
 
 // NewMisServer create Mis server
 func NewMisServer(config *config.CloudConfig) (*MisServer, error) {
-	router := gin.New()
+	health, err := service.NewHealthService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := newRouter(config.MisServer.Server)
+	if err != nil {
+		return nil, err
+	}
 	server := &http.Server{
 		Addr:           config.MisServer.Port,
 		Handler:        router,
@@ -36,6 +46,7 @@ func NewMisServer(config *config.CloudConfig) (*MisServer, error) {
 		cfg:    config,
 		router: router,
 		server: server,
+		health: health,
 	}, nil
 }
 
@@ -63,7 +74,9 @@ func (s *MisServer) GetRoute() *gin.Engine {
 func (s *MisServer) InitRoute() {
 	s.router.NoRoute(NoRouteHandler)
 	s.router.NoMethod(NoMethodHandler)
-	s.router.GET("/health", Health)
+	s.router.GET("/health", HealthCheck(s.health))
+	s.router.GET("/health/live", Health)
+	s.router.GET("/health/ready", Ready(s.health))
 
 	s.router.Use(RequestIDHandler)
 	s.router.Use(LoggerHandler)
@@ -97,6 +110,11 @@ func (s *MisServer) InitRoute() {
 		module.DELETE("/:name", common.WrapperMis(s.api.DeleteModules))
 		module.DELETE("/:name/version/:version", common.WrapperMis(s.api.DeleteModules))
 	}
+	{
+		secret := v1.Group("/secrets")
+
+		secret.POST("/encrypt", common.WrapperMis(s.api.EncryptSecrets))
+	}
 }
 
 // auth handler
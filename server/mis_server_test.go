@@ -1,10 +1,12 @@
 package server
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -13,6 +15,7 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
@@ -25,6 +28,15 @@ func initMisServerMock(t *testing.T) (*MisServer, *gomock.Controller) {
 	c.Plugin.Shadow = common.RandString(9)
 	c.Plugin.Index = common.RandString(9)
 	c.Plugin.AppHistory = common.RandString(9)
+	c.Plugin.NodeGroup = common.RandString(9)
+	c.Plugin.AppTpl = common.RandString(9)
+	c.Plugin.EventSink = common.RandString(9)
+	c.Plugin.AuditSink = common.RandString(9)
+	c.Plugin.Webhook = common.RandString(9)
+	c.Plugin.NodeCmd = common.RandString(9)
+	c.Plugin.AppSched = common.RandString(9)
+	c.Plugin.RegRefresh = common.RandString(9)
+	c.Plugin.Job = common.RandString(9)
 	c.Plugin.Objects = []string{common.RandString(9)}
 	c.Plugin.PKI = common.RandString(9)
 	c.Plugin.Functions = []string{common.RandString(9)}
@@ -112,6 +124,48 @@ func initMisServerMock(t *testing.T) (*MisServer, *gomock.Controller) {
 		return mockIndex, nil
 	})
 
+	mockAppHis := mockPlugin.NewMockAppHistory(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppHistory, func() (plugin.Plugin, error) {
+		return mockAppHis, nil
+	})
+	mockNodeGroup := mockPlugin.NewMockNodeGroup(mockCtl)
+	plugin.RegisterFactory(c.Plugin.NodeGroup, func() (plugin.Plugin, error) {
+		return mockNodeGroup, nil
+	})
+	mockAppTemplate := mockPlugin.NewMockAppTemplate(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppTpl, func() (plugin.Plugin, error) {
+		return mockAppTemplate, nil
+	})
+	mockEventSink := mockPlugin.NewMockEventSink(mockCtl)
+	plugin.RegisterFactory(c.Plugin.EventSink, func() (plugin.Plugin, error) {
+		return mockEventSink, nil
+	})
+
+	mockWebhook := mockPlugin.NewMockWebhook(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Webhook, func() (plugin.Plugin, error) {
+		return mockWebhook, nil
+	})
+	mockNodeCmd := mockPlugin.NewMockNodeCommand(mockCtl)
+	plugin.RegisterFactory(c.Plugin.NodeCmd, func() (plugin.Plugin, error) {
+		return mockNodeCmd, nil
+	})
+	mockAppSched := mockPlugin.NewMockAppSchedule(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppSched, func() (plugin.Plugin, error) {
+		return mockAppSched, nil
+	})
+	mockRegRefresh := mockPlugin.NewMockRegistryRefresh(mockCtl)
+	plugin.RegisterFactory(c.Plugin.RegRefresh, func() (plugin.Plugin, error) {
+		return mockRegRefresh, nil
+	})
+	mockJob := mockPlugin.NewMockJob(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Job, func() (plugin.Plugin, error) {
+		return mockJob, nil
+	})
+	mockAuditSink := mockPlugin.NewMockAuditSink(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AuditSink, func() (plugin.Plugin, error) {
+		return mockAuditSink, nil
+	})
+
 	mockTask := mockPlugin.NewMockTask(mockCtl)
 	plugin.RegisterFactory(c.Plugin.Task, func() (plugin.Plugin, error) {
 		return mockTask, nil
@@ -176,6 +230,50 @@ func TestMisServer_Handler(t *testing.T) {
 	w = httptest.NewRecorder()
 	s.GetRoute().ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"healthy"`)
 	go s.Run()
 	defer s.Close()
+
+	for _, path := range []string{"/health/live", "/health/ready"} {
+		req, _ = http.NewRequest(http.MethodGet, path, nil)
+		w = httptest.NewRecorder()
+		s.GetRoute().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	hs := ms.NewMockHealthService(mockCtl)
+	router := gin.New()
+	router.GET("/health", HealthCheck(hs))
+	router.GET("/health/live", Health)
+	router.GET("/health/ready", Ready(hs))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	degraded := &models.Health{Status: models.HealthStatusDegraded, Checks: []models.HealthCheck{
+		{Name: "storage", Critical: true, Status: models.HealthCheckUp},
+		{Name: "object", Status: models.HealthCheckDown, Error: "timeout"},
+	}}
+	hs.EXPECT().Check(gomock.Any()).Return(degraded).Times(2)
+	w := get("/health")
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.Health{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, degraded, res)
+	assert.Equal(t, http.StatusOK, get("/health/ready").Code)
+
+	unhealthy := &models.Health{Status: models.HealthStatusUnhealthy}
+	hs.EXPECT().Check(gomock.Any()).Return(unhealthy).Times(2)
+	assert.Equal(t, http.StatusServiceUnavailable, get("/health").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get("/health/ready").Code)
+	// liveness doesn't depend on the dependencies
+	assert.Equal(t, http.StatusOK, get("/health/live").Code)
 }
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+const (
+	RateLimitTypeMemory = "memory"
+	RateLimitTypeRedis  = "redis"
+
+	redisRateLimitKeyPrefix = "baetyl-cloud:rate-limit:"
+	// rateLimitSweepInterval how often the idle buckets are forgotten by the memory limiter
+	rateLimitSweepInterval = time.Minute
+)
+
+// rateLimiter keeps a token bucket per key
+type rateLimiter interface {
+	// take takes a token from the bucket of the key,
+	// returns false and how long to wait for the next token if the bucket is empty
+	take(key string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+// newRateLimiter creates the limiter of the requests, the buckets are kept in redis to be shared by all replicas
+func newRateLimiter(cfg config.RateLimit) (rateLimiter, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+	if cfg.Rate <= 0 || cfg.Burst <= 0 {
+		return nil, errors.Errorf("rate (%v) and burst (%d) of rate limit should be positive", cfg.Rate, cfg.Burst)
+	}
+	switch cfg.Type {
+	case "", RateLimitTypeMemory:
+		return newMemoryRateLimiter(), nil
+	case RateLimitTypeRedis:
+		return &redisRateLimiter{cli: redis.NewClient(&redis.Options{
+			Addr:     cfg.Address,
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		})}, nil
+	default:
+		return nil, errors.Errorf("unsupported rate limit type (%s)", cfg.Type)
+	}
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// fill adds the tokens generated since the last time, returns whether the bucket is full
+func (b *tokenBucket) fill(now time.Time, rate float64, burst int) bool {
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	return b.tokens >= float64(burst)
+}
+
+type memoryRateLimiter struct {
+	buckets map[string]*tokenBucket
+	swept   time.Time
+	sync.Mutex
+}
+
+func newMemoryRateLimiter() *memoryRateLimiter {
+	return &memoryRateLimiter{buckets: map[string]*tokenBucket{}, swept: time.Now()}
+}
+
+func (l *memoryRateLimiter) take(key string, rate float64, burst int) (bool, time.Duration, error) {
+	l.Lock()
+	defer l.Unlock()
+	now := time.Now()
+	// the full buckets are the same as the missing ones
+	if now.Sub(l.swept) > rateLimitSweepInterval {
+		for k, b := range l.buckets {
+			if b.fill(now, rate, burst) {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+	b.fill(now, rate, burst)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second)), nil
+}
+
+// redisRateLimitScript refills and takes the bucket atomically, the bucket expires once it is full again
+var redisRateLimitScript = redis.NewScript(`
+local rate, burst, now = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local tokens = tonumber(redis.call('HGET', KEYS[1], 'tokens'))
+local last = tonumber(redis.call('HGET', KEYS[1], 'last'))
+if tokens == nil or last == nil then
+	tokens, last = burst, now
+end
+tokens = math.min(burst, tokens + math.max(0, now - last) * rate)
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens, allowed = tokens - 1, 1
+else
+	wait = (1 - tokens) / rate
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, tostring(wait)}
+`)
+
+type redisRateLimiter struct {
+	cli *redis.Client
+}
+
+func (l *redisRateLimiter) take(key string, rate float64, burst int) (bool, time.Duration, error) {
+	now := float64(time.Now().UnixMicro()) / 1e6
+	res, err := redisRateLimitScript.Run(context.TODO(), l.cli, []string{redisRateLimitKeyPrefix + key},
+		strconv.FormatFloat(rate, 'f', -1, 64), burst, strconv.FormatFloat(now, 'f', 6, 64)).Slice()
+	if err != nil {
+		return false, 0, errors.Trace(err)
+	}
+	if len(res) != 2 {
+		return false, 0, errors.Errorf("unexpected result of rate limit script (%v)", res)
+	}
+	if allowed, _ := res[0].(int64); allowed == 1 {
+		return true, 0, nil
+	}
+	s, _ := res[1].(string)
+	wait, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return false, 0, errors.Trace(err)
+	}
+	return false, time.Duration(wait * float64(time.Second)), nil
+}
+
+// RateLimitHandler rejects the requests of the namespace whose bucket is empty,
+// the requests are let through if the limiter fails, so that the api is still available
+func (s *AdminServer) RateLimitHandler(c *gin.Context) {
+	if s.limiter == nil {
+		return
+	}
+	cfg := s.cfg.AdminServer.RateLimit
+	cc := common.NewContext(c)
+	key := cc.GetNamespace()
+	if token := c.GetHeader("Authorization"); cfg.ByToken && token != "" {
+		sum := sha256.Sum256([]byte(token))
+		key += "/" + hex.EncodeToString(sum[:8])
+	}
+	if cfg.ByClientIP {
+		key += "/" + c.ClientIP()
+	}
+	ok, wait, err := s.limiter.take(key, cfg.Rate, cfg.Burst)
+	if err != nil {
+		s.log.Warn("failed to take rate limit token", log.Any("key", key), log.Error(err))
+		return
+	}
+	if ok {
+		return
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(wait.Seconds())))))
+	common.PopulateFailedResponse(cc, common.Error(common.ErrTooManyRequests,
+		common.Field("error", "the rate limit of namespace is exceeded")), true)
+}
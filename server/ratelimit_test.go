@@ -0,0 +1,115 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	l, err := newRateLimiter(config.RateLimit{Rate: 1, Burst: 1})
+	assert.NoError(t, err)
+	assert.Nil(t, l)
+
+	l, err = newRateLimiter(config.RateLimit{Enable: true, Rate: 1, Burst: 1, Type: RateLimitTypeMemory})
+	assert.NoError(t, err)
+	assert.IsType(t, &memoryRateLimiter{}, l)
+
+	l, err = newRateLimiter(config.RateLimit{Enable: true, Rate: 1, Burst: 1, Type: RateLimitTypeRedis, Address: "127.0.0.1:6379", DB: 1})
+	assert.NoError(t, err)
+	assert.IsType(t, &redisRateLimiter{}, l)
+	assert.Equal(t, 1, l.(*redisRateLimiter).cli.Options().DB)
+
+	_, err = newRateLimiter(config.RateLimit{Enable: true, Rate: 0, Burst: 1})
+	assert.Error(t, err)
+	_, err = newRateLimiter(config.RateLimit{Enable: true, Rate: 1, Burst: 1, Type: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestMemoryRateLimiter(t *testing.T) {
+	l := newMemoryRateLimiter()
+	for i := 0; i < 2; i++ {
+		ok, _, err := l.take("default", 0.5, 2)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, wait, err := l.take("default", 0.5, 2)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.InDelta(t, 2*time.Second, wait, float64(100*time.Millisecond))
+
+	// the buckets of other keys are not affected
+	ok, _, _ = l.take("other", 0.5, 2)
+	assert.True(t, ok)
+
+	// the bucket is refilled over time
+	l.buckets["default"].last = l.buckets["default"].last.Add(-2 * time.Second)
+	ok, _, _ = l.take("default", 0.5, 2)
+	assert.True(t, ok)
+
+	// the full buckets are swept
+	l.buckets["other"].last = time.Now().Add(-time.Hour)
+	l.swept = time.Now().Add(-2 * rateLimitSweepInterval)
+	l.take("default", 0.5, 2)
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestAdminServer_RateLimitHandler(t *testing.T) {
+	cfg := &config.CloudConfig{}
+	cfg.AdminServer.RateLimit = config.RateLimit{Enable: true, Rate: 0.1, Burst: 1}
+	s := &AdminServer{
+		cfg:     cfg,
+		limiter: newMemoryRateLimiter(),
+		log:     log.L(),
+	}
+	// no proxy is trusted by default
+	router, err := newRouter(config.Server{})
+	assert.NoError(t, err)
+	router.Use(func(c *gin.Context) { common.NewContext(c).SetNamespace(c.Query("ns")) })
+	router.Use(s.RateLimitHandler)
+	router.GET("/v1/apps", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	remoteAddr := "192.168.1.1:4321"
+	get := func(ns, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/apps?ns="+ns, nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "203.0.113.9")
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, get("default", "a").Code)
+	w := get("default", "b")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, get("other", "").Code)
+
+	// the requests of each token are limited separately
+	cfg.AdminServer.RateLimit.ByToken = true
+	assert.Equal(t, http.StatusOK, get("default", "a").Code)
+	assert.Equal(t, http.StatusOK, get("default", "b").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("default", "b").Code)
+
+	// the requests of each client ip are limited separately, regardless of X-Forwarded-For from the untrusted
+	cfg.AdminServer.RateLimit.ByClientIP = true
+	assert.Equal(t, http.StatusOK, get("default", "b").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("default", "b").Code)
+	remoteAddr = "192.168.1.2:4321"
+	assert.Equal(t, http.StatusOK, get("default", "b").Code)
+
+	// disabled
+	s.limiter = nil
+	assert.Equal(t, http.StatusOK, get("default", "b").Code)
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+const (
+	RBACRoleViewer = "viewer"
+	RBACRoleEditor = "editor"
+	RBACRoleAdmin  = "admin"
+
+	RBACVerbRead   = "read"
+	RBACVerbWrite  = "write"
+	RBACVerbManage = "manage"
+
+	rbacWildcard = "*"
+)
+
+// rbacBuiltinRoles the viewers can read only, the editors can write but can't manage,
+// and the admins can do anything
+var rbacBuiltinRoles = map[string][]string{
+	RBACRoleViewer: {"*:read"},
+	RBACRoleEditor: {"*:read", "*:write"},
+	RBACRoleAdmin:  {"*:*"},
+}
+
+// rbacManagedResources the resources of the whole system or namespace, which are written with the manage verb
+var rbacManagedResources = map[string]bool{
+	"admin":     true,
+	"quotas":    true,
+	"license":   true,
+	"namespace": true,
+	"modules":   true,
+}
+
+// rbacPolicy keeps the permissions of the roles and the ones declared by the routes
+type rbacPolicy struct {
+	defaultRole string
+	roles       map[string][]string
+	routes      map[string]string
+}
+
+func newRBACPolicy(cfg config.RBAC) (*rbacPolicy, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+	roles := map[string][]string{}
+	for k, v := range rbacBuiltinRoles {
+		roles[k] = v
+	}
+	for k, v := range cfg.Roles {
+		for _, p := range v {
+			if _, _, ok := splitPermission(p); !ok {
+				return nil, errors.Errorf("the permission (%s) of the role (%s) should be written as resource:verb", p, k)
+			}
+		}
+		roles[k] = v
+	}
+	if _, ok := roles[cfg.DefaultRole]; cfg.DefaultRole != "" && !ok {
+		return nil, errors.Errorf("the default role (%s) is not defined", cfg.DefaultRole)
+	}
+	return &rbacPolicy{
+		defaultRole: cfg.DefaultRole,
+		roles:       roles,
+		routes:      map[string]string{},
+	}, nil
+}
+
+// permission returns the permission required by the route, which is declared by the route,
+// or derived from the resource of the path and the method
+func (p *rbacPolicy) permission(method, route string) string {
+	if perm, ok := p.routes[method+" "+route]; ok {
+		return perm
+	}
+	resource := routeResource(route)
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return resource + ":" + RBACVerbRead
+	}
+	if rbacManagedResources[resource] {
+		return resource + ":" + RBACVerbManage
+	}
+	return resource + ":" + RBACVerbWrite
+}
+
+// grant returns whether any of the roles is granted the permission, the users without any role known
+// are treated as the default role
+func (p *rbacPolicy) grant(roles []string, permission string) bool {
+	known := false
+	for _, r := range roles {
+		perms, ok := p.roles[r]
+		if !ok {
+			continue
+		}
+		known = true
+		if matchPermissions(perms, permission) {
+			return true
+		}
+	}
+	if !known && p.defaultRole != "" {
+		return matchPermissions(p.roles[p.defaultRole], permission)
+	}
+	return false
+}
+
+func matchPermissions(granted []string, permission string) bool {
+	resource, verb, _ := splitPermission(permission)
+	for _, g := range granted {
+		gr, gv, ok := splitPermission(g)
+		if !ok {
+			continue
+		}
+		if (gr == rbacWildcard || gr == resource) && (gv == rbacWildcard || gv == verb) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitPermission(permission string) (string, string, bool) {
+	parts := strings.Split(permission, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// RequirePermission declares the permission required by the route of the method and the full path,
+// if it is different from the one derived from the resource of the path and the method
+func (s *AdminServer) RequirePermission(method, route, permission string) {
+	if s.rbac == nil {
+		return
+	}
+	s.rbac.routes[method+" "+route] = permission
+}
+
+// RBACHandler rejects the requests with 403 if none of the roles of the user is granted the permission required by the route
+func (s *AdminServer) RBACHandler(c *gin.Context) {
+	if s.rbac == nil || c.FullPath() == "" {
+		return
+	}
+	cc := common.NewContext(c)
+	var roles []string
+	for _, r := range cc.GetUserInfo().Roles {
+		roles = append(roles, r.ID)
+	}
+	permission := s.rbac.permission(c.Request.Method, c.FullPath())
+	if s.rbac.grant(roles, permission) {
+		return
+	}
+	s.log.Warn("request permission denied",
+		log.Any(cc.GetTrace()),
+		log.Any("namespace", cc.GetNamespace()),
+		log.Any("user", cc.GetUserInfo().User.ID),
+		log.Any("roles", roles),
+		log.Any("permission", permission))
+	common.PopulateFailedResponse(cc, common.Error(common.ErrPermissionDenied,
+		common.Field("permission", permission), common.Field("role", strings.Join(roles, ","))), true)
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestAdminServer_RBAC(t *testing.T) {
+	rbac, err := newRBACPolicy(config.RBAC{
+		Enable:      true,
+		DefaultRole: RBACRoleViewer,
+		Roles:       map[string][]string{"operator": {"nodes:*", "apps:read"}},
+	})
+	assert.NoError(t, err)
+	s := &AdminServer{rbac: rbac, log: log.L()}
+	s.RequirePermission(http.MethodPut, "/v1/nodes", "nodes:"+RBACVerbRead)
+
+	var roles []common.Role
+	router := gin.New()
+	v1 := router.Group("v1")
+	v1.Use(func(c *gin.Context) {
+		common.NewContext(c).SetUserInfo(common.UserInfo{Roles: roles})
+	})
+	v1.Use(s.RBACHandler)
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	v1.GET("/apps", ok)
+	v1.POST("/apps", ok)
+	v1.DELETE("/apps/:name", ok)
+	v1.PUT("/nodes", ok)
+	v1.PUT("/nodes/:name", ok)
+	v1.GET("/quotas", ok)
+	v1.POST("/quotas", ok)
+	v1.GET("/license", ok)
+	v1.PUT("/license", ok)
+	v1.PUT("/admin/readonly", ok)
+
+	do := func(method, path string, rs ...string) *httptest.ResponseRecorder {
+		roles = nil
+		for _, r := range rs {
+			roles = append(roles, common.Role{ID: r})
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	// viewers can only read
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/apps", RBACRoleViewer).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/v1/nodes", RBACRoleViewer).Code)
+	w := do(http.MethodPost, "/v1/apps", RBACRoleViewer)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrPermissionDenied")
+	assert.Contains(t, w.Body.String(), "The permission (apps:write) is required, which is not granted to the role (viewer).")
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPut, "/v1/nodes/n1", RBACRoleViewer).Code)
+
+	// editors can't manage the quotas and the license
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/v1/apps", RBACRoleEditor).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodDelete, "/v1/apps/a1", RBACRoleEditor).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/quotas", RBACRoleEditor).Code)
+	w = do(http.MethodPost, "/v1/quotas", RBACRoleEditor)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "quotas:manage")
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPut, "/v1/admin/readonly", RBACRoleEditor).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/license", RBACRoleEditor).Code)
+	w = do(http.MethodPut, "/v1/license", RBACRoleEditor)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "license:manage")
+
+	// admins can do anything
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/v1/quotas", RBACRoleAdmin).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/v1/admin/readonly", "other", RBACRoleAdmin).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/v1/license", RBACRoleAdmin).Code)
+
+	// custom roles
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/v1/nodes/n1", "operator").Code)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/v1/apps", "operator").Code)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, "/v1/quotas", "operator").Code)
+
+	// the users without any role known are viewers
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/apps").Code)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/v1/apps", "unknown").Code)
+
+	// the users without any role known are denied without the default role
+	s.rbac.defaultRole = ""
+	w = do(http.MethodGet, "/v1/apps")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "The permission (apps:read) is required.")
+}
+
+func TestNewRBACPolicy(t *testing.T) {
+	rbac, err := newRBACPolicy(config.RBAC{})
+	assert.NoError(t, err)
+	assert.Nil(t, rbac)
+
+	_, err = newRBACPolicy(config.RBAC{Enable: true, Roles: map[string][]string{"operator": {"nodes"}}})
+	assert.EqualError(t, err, "the permission (nodes) of the role (operator) should be written as resource:verb")
+
+	_, err = newRBACPolicy(config.RBAC{Enable: true, DefaultRole: "operator"})
+	assert.EqualError(t, err, "the default role (operator) is not defined")
+
+	// the built-in roles can be overridden
+	rbac, err = newRBACPolicy(config.RBAC{Enable: true, Roles: map[string][]string{RBACRoleViewer: {"apps:read"}}})
+	assert.NoError(t, err)
+	assert.True(t, rbac.grant([]string{RBACRoleViewer}, "apps:read"))
+	assert.False(t, rbac.grant([]string{RBACRoleViewer}, "nodes:read"))
+	assert.Equal(t, "modules:manage", rbac.permission(http.MethodDelete, "/v1/modules/:name"))
+	assert.Equal(t, "nodes:write", rbac.permission(http.MethodPost, "/v1/nodes/batch/delete"))
+}
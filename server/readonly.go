@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+const (
+	readOnlyRoute    = "/v1/admin/readonly"
+	readOnlyCacheKey = "baetyl-cloud:read-only"
+)
+
+// readOnlyState keeps the read-only switch in the cache store, which is shared by the replicas if it is of redis,
+// so the switch toggled on one replica takes effect on all of them. The mode of the config is used until it is toggled
+type readOnlyState struct {
+	store persist.CacheStore
+	mode  models.ReadOnlyMode
+	log   *log.Logger
+}
+
+func newReadOnlyState(store persist.CacheStore, readOnly bool) *readOnlyState {
+	return &readOnlyState{
+		store: store,
+		mode:  models.ReadOnlyMode{ReadOnly: readOnly},
+		log:   log.L().With(log.Any("server", "readOnly")),
+	}
+}
+
+// get returns the switch toggled, or the mode of the config if it is never toggled or fails to be read
+func (r *readOnlyState) get() models.ReadOnlyMode {
+	var mode models.ReadOnlyMode
+	if err := r.store.Get(readOnlyCacheKey, &mode); err != nil {
+		if err != persist.ErrCacheMiss {
+			r.log.Error("failed to get the read-only switch", log.Error(err))
+		}
+		return r.mode
+	}
+	return mode
+}
+
+func (r *readOnlyState) set(mode models.ReadOnlyMode) error {
+	return service.KeepCacheValue(r.store, readOnlyCacheKey, mode)
+}
+
+// ReadOnlyHandler rejects the requests except GET and the read routes with 503 if the server is read-only,
+// the switch itself can always be toggled
+func (s *AdminServer) ReadOnlyHandler(c *gin.Context) {
+	if s.readOnly == nil {
+		return
+	}
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return
+	}
+	if c.FullPath() == readOnlyRoute || s.isReadRoute(c.Request.Method, c.FullPath()) {
+		return
+	}
+	if mode := s.readOnly.get(); mode.ReadOnly {
+		common.PopulateFailedResponse(common.NewContext(c), common.Error(common.ErrServiceReadOnly,
+			common.Field("message", mode.Message)), true)
+	}
+}
+
+// GetReadOnly get the read-only switch
+func (s *AdminServer) GetReadOnly(_ *common.Context) (interface{}, error) {
+	mode := s.readOnly.get()
+	return &mode, nil
+}
+
+// UpdateReadOnly toggle the read-only switch, only the users with the full control of the system are allowed
+func (s *AdminServer) UpdateReadOnly(c *common.Context) (interface{}, error) {
+	err := s.Auth.Verify(c, &plugin.PermissionRequest{
+		Resource:   plugin.PermissionResourceSystem,
+		Permission: []string{plugin.PermissionFull},
+	})
+	if err != nil {
+		return nil, common.Error(common.ErrRequestAccessDenied, common.Field("error", err.Error()))
+	}
+	mode := new(models.ReadOnlyMode)
+	if err = c.LoadBody(mode); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err = s.readOnly.set(*mode); err != nil {
+		return nil, err
+	}
+	s.log.Info("read-only switch is toggled", log.Any(c.GetTrace()), log.Any("user", c.GetUser().Name),
+		log.Any("readOnly", mode.ReadOnly), log.Any("message", mode.Message))
+	return mode, nil
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestAdminServer_ReadOnly(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	auth := ms.NewMockAuthService(mockCtl)
+	// the api cache expires the responses quickly, but the switch is kept
+	store := persist.NewInMemoryStore(time.Millisecond)
+	s := &AdminServer{
+		Auth:     auth,
+		readOnly: newReadOnlyState(store, false),
+		log:      log.L(),
+	}
+	router := gin.New()
+	v1 := router.Group("v1")
+	v1.Use(s.ReadOnlyHandler)
+	v1.GET("/admin/readonly", common.Wrapper(s.GetReadOnly))
+	v1.PUT("/admin/readonly", common.Wrapper(s.UpdateReadOnly))
+	v1.GET("/apps", func(c *gin.Context) { c.Status(http.StatusOK) })
+	v1.POST("/apps", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/v1/apps", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// only the users with the full control of the system can toggle it
+	auth.EXPECT().Verify(gomock.Any(), gomock.Any()).Return(errors.New("forbidden")).Times(1)
+	w = do(http.MethodPut, "/v1/admin/readonly", `{"readOnly":true}`)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	auth.EXPECT().Verify(gomock.Any(), &plugin.PermissionRequest{
+		Resource:   plugin.PermissionResourceSystem,
+		Permission: []string{plugin.PermissionFull},
+	}).Return(nil).Times(2)
+	w = do(http.MethodPut, "/v1/admin/readonly", `{"readOnly":true,"message":"migrating until 10:00"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"readOnly":true,"message":"migrating until 10:00"}`, w.Body.String())
+
+	w = do(http.MethodGet, "/v1/apps", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = do(http.MethodPost, "/v1/apps", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrServiceReadOnly")
+	assert.Contains(t, w.Body.String(), "migrating until 10:00")
+	w = do(http.MethodGet, "/v1/admin/readonly", "")
+	assert.JSONEq(t, `{"readOnly":true,"message":"migrating until 10:00"}`, w.Body.String())
+
+	// the switch is shared by the replicas using the same store
+	time.Sleep(5 * time.Millisecond)
+	other := newReadOnlyState(store, false)
+	assert.Equal(t, "migrating until 10:00", other.get().Message)
+	assert.True(t, other.get().ReadOnly)
+
+	// the switch can be turned off while read-only
+	w = do(http.MethodPut, "/v1/admin/readonly", `{"readOnly":false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = do(http.MethodPost, "/v1/apps", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReadOnlyStateDefault(t *testing.T) {
+	// the mode of the config is used until the switch is toggled
+	store := persist.NewInMemoryStore(time.Minute)
+	r := newReadOnlyState(store, true)
+	assert.True(t, r.get().ReadOnly)
+	assert.NoError(t, r.set(models.ReadOnlyMode{}))
+	assert.False(t, r.get().ReadOnly)
+	assert.False(t, newReadOnlyState(store, true).get().ReadOnly)
+}
@@ -11,6 +11,12 @@ import (
 
 type HandlerMessage func(msg specV1.Message) (*specV1.Message, error)
 
+// NodeTokenChecker returns the namespace and the name of the node of the node token in the authorization
+type NodeTokenChecker func(authorization string) (string, string, error)
+
+// MsgRouterNodeToken the router of the node token checker, the links accepting the node tokens look it up
+const MsgRouterNodeToken = "nodeToken"
+
 type SyncServer struct {
 	links   map[string]plugin.SyncLink
 	syncAPI api.SyncAPI
@@ -38,6 +44,7 @@ func (s *SyncServer) InitMsgRouter() {
 	for _, v := range s.links {
 		v.AddMsgRouter(string(specV1.MessageReport), HandlerMessage(s.syncAPI.Report))
 		v.AddMsgRouter(string(specV1.MessageDesire), HandlerMessage(s.syncAPI.Desire))
+		v.AddMsgRouter(MsgRouterNodeToken, NodeTokenChecker(s.syncAPI.CheckNodeToken))
 	}
 }
 
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestTracingHandler(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	var child trace.SpanContext
+	router := gin.New()
+	router.Use(RequestIDHandler, TracingHandler)
+	router.GET("/v1/nodes/:name", func(c *gin.Context) {
+		_, span := common.StartSpan(c.Request.Context(), "child")
+		child = span.SpanContext()
+		span.End()
+		c.Status(http.StatusInternalServerError)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/n1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	span := spans[1]
+	assert.Equal(t, "GET /v1/nodes/:name", span.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Equal(t, span.SpanContext().TraceID(), child.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, span.Attributes(), attribute.String("request.id", w.Header().Get(common.GetTraceHeader())))
+	assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", http.StatusInternalServerError))
+	assert.Equal(t, "Error", span.Status().Code.String())
+}
+
+func TestInitTracing(t *testing.T) {
+	shutdown, err := InitTracing(config.Tracing{})
+	assert.NoError(t, err)
+	shutdown()
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// webhookEventType returns the type of the event of the request changing a resource, such as app.updated of PUT /v1/apps/:name,
+// the other requests are not notified
+func webhookEventType(method, route string) (string, bool) {
+	parts := strings.Split(strings.Trim(route, "/"), "/")
+	if len(parts) < 2 {
+		return "", false
+	}
+	kind, ok := models.WebhookResourceKinds[parts[1]]
+	if !ok {
+		return "", false
+	}
+	var action string
+	switch {
+	case len(parts) == 2 && method == http.MethodPost:
+		action = models.WebhookActionCreated
+	case len(parts) == 3 && parts[2] == ":name" && (method == http.MethodPut || method == http.MethodPatch):
+		action = models.WebhookActionUpdated
+	case len(parts) == 4 && parts[2] == ":name" && parts[3] == "labels" && method == http.MethodPatch:
+		action = models.WebhookActionUpdated
+	case len(parts) == 3 && parts[2] == ":name" && method == http.MethodDelete:
+		action = models.WebhookActionDeleted
+	default:
+		return "", false
+	}
+	return kind + "." + action, true
+}
+
+// WebhookHandler notifies the webhooks of the resources changed successfully through the api
+func (s *AdminServer) WebhookHandler(c *gin.Context) {
+	eventType, ok := webhookEventType(c.Request.Method, c.FullPath())
+	if !ok {
+		return
+	}
+	name := c.Param("name")
+	if name == "" && c.Request.Body != nil {
+		// the name of the resource created is read from the body
+		if buf, err := io.ReadAll(c.Request.Body); err == nil {
+			c.Request.Body = io.NopCloser(bytes.NewReader(buf))
+			var body struct {
+				Name string `json:"name"`
+			}
+			if json.Unmarshal(buf, &body) == nil {
+				name = body.Name
+			}
+		}
+	}
+	c.Next()
+
+	if c.Writer.Status() >= http.StatusBadRequest {
+		return
+	}
+	cc := common.NewContext(c)
+	parts := strings.SplitN(eventType, ".", 2)
+	message := fmt.Sprintf("the %s is %s", parts[0], parts[1])
+	if user := cc.GetUser(); user.Name != "" {
+		message += " by " + user.Name
+	}
+	s.api.Webhook.Notify(eventType, &models.Event{
+		Namespace: cc.GetNamespace(),
+		Kind:      parts[0],
+		Name:      name,
+		Message:   message,
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestWebhookEventType(t *testing.T) {
+	cases := []struct {
+		method, route, event string
+	}{
+		{http.MethodPost, "/v1/apps", "app.created"},
+		{http.MethodPut, "/v1/configs/:name", "config.updated"},
+		{http.MethodDelete, "/v1/certificates/:name", "cert.deleted"},
+		{http.MethodPost, "/v1/nodes", "node.created"},
+		{http.MethodPatch, "/v1/apps/:name/labels", "app.updated"},
+	}
+	for _, c := range cases {
+		event, ok := webhookEventType(c.method, c.route)
+		assert.True(t, ok, c.route)
+		assert.Equal(t, c.event, event)
+	}
+	for _, route := range []string{"/v1/apps/:name", "/v1/nodes/:name/drain", "/v1/webhooks", "/v1", ""} {
+		_, ok := webhookEventType(http.MethodPost, route)
+		assert.False(t, ok, route)
+	}
+	_, ok := webhookEventType(http.MethodGet, "/v1/apps/:name")
+	assert.False(t, ok)
+}
+
+func TestAdminServer_WebhookHandler(t *testing.T) {
+	s, _, _, mockCtl := initAdminServerMock(t)
+	defer mockCtl.Finish()
+
+	mWebhook := service.NewMockWebhookService(mockCtl)
+	s.api.Webhook = mWebhook
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		cc := common.NewContext(c)
+		cc.SetNamespace("default")
+		cc.SetUser(common.User{Name: "user1"})
+	}, s.WebhookHandler)
+	r.POST("/v1/apps", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.PUT("/v1/apps/:name", func(c *gin.Context) { c.Status(http.StatusBadRequest) })
+	r.DELETE("/v1/apps/:name", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// the name of the created one is read from the body
+	mWebhook.EXPECT().Notify("app.created", gomock.Any()).Do(func(_ string, e *models.Event) {
+		assert.Equal(t, "default", e.Namespace)
+		assert.Equal(t, "app", e.Kind)
+		assert.Equal(t, "app01", e.Name)
+		assert.Equal(t, "the app is created by user1", e.Message)
+	}).Times(1)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/apps", bytes.NewReader([]byte(`{"name":"app01"}`)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the failed requests are not notified
+	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/app01", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mWebhook.EXPECT().Notify("app.deleted", gomock.Any()).Do(func(_ string, e *models.Event) {
+		assert.Equal(t, "app01", e.Name)
+	}).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apps/app01", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/app_schedule.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppScheduleService
+
+// AppScheduleService keeps the scheduled deploys of the applications, an application has one pending deploy at most
+type AppScheduleService interface {
+	// Create schedules the deploy at its time, or at the next time matching its cron expression in UTC
+	Create(schedule *models.AppSchedule) (*models.AppSchedule, error)
+	// Get gets the latest scheduled deploy of the application
+	Get(namespace, name string) (*models.AppSchedule, error)
+	// Cancel cancels the pending deploy of the application
+	Cancel(namespace, name string) (*models.AppSchedule, error)
+	// ListDue lists the pending deploys which are due
+	ListDue(limit int) ([]models.AppSchedule, error)
+	// Claim marks the pending deploy running, returns false if it has been claimed or canceled
+	Claim(id int64) (bool, error)
+	// Finish records the outcome of the running deploy
+	Finish(id int64, result error) error
+}
+
+type appScheduleService struct {
+	schedule plugin.AppSchedule
+}
+
+// NewAppScheduleService NewAppScheduleService
+func NewAppScheduleService(config *config.CloudConfig) (AppScheduleService, error) {
+	schedule, err := plugin.GetPlugin(config.Plugin.AppSched)
+	if err != nil {
+		return nil, err
+	}
+	return &appScheduleService{schedule: schedule.(plugin.AppSchedule)}, nil
+}
+
+func (s *appScheduleService) Create(schedule *models.AppSchedule) (*models.AppSchedule, error) {
+	now := time.Now().UTC()
+	switch {
+	case schedule.Time != nil && schedule.Cron != "":
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "only one of time and cron can be set"))
+	case schedule.Time != nil:
+		if !schedule.Time.After(now) {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the time should be in the future"))
+		}
+		schedule.DueTime = schedule.Time.UTC()
+	case schedule.Cron != "":
+		due, err := nextCronTime(schedule.Cron, now)
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+		schedule.DueTime = due
+	default:
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "either time or cron is required"))
+	}
+
+	old, err := s.Get(schedule.Namespace, schedule.Name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return nil, err
+		}
+	}
+	if old != nil && (old.Status == models.AppSchedulePending || old.Status == models.AppScheduleRunning) {
+		return nil, common.Error(common.ErrResourceConflict, common.Field("type", "schedule"),
+			common.Field("name", schedule.Name), common.Field("namespace", schedule.Namespace))
+	}
+	schedule.Status = models.AppSchedulePending
+	schedule.Message = ""
+	schedule.CreationTimestamp = now
+	return s.schedule.CreateAppSchedule(nil, schedule)
+}
+
+func (s *appScheduleService) Get(namespace, name string) (*models.AppSchedule, error) {
+	return s.schedule.GetAppSchedule(nil, namespace, name)
+}
+
+func (s *appScheduleService) Cancel(namespace, name string) (*models.AppSchedule, error) {
+	schedule, err := s.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.schedule.UpdateAppScheduleStatus(nil, schedule.ID, models.AppSchedulePending, models.AppScheduleCanceled, "")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "only the pending deploy can be canceled, it is "+s.status(schedule)))
+	}
+	return s.Get(namespace, name)
+}
+
+func (s *appScheduleService) ListDue(limit int) ([]models.AppSchedule, error) {
+	return s.schedule.ListDueAppSchedule(nil, time.Now(), limit)
+}
+
+func (s *appScheduleService) Claim(id int64) (bool, error) {
+	return s.schedule.UpdateAppScheduleStatus(nil, id, models.AppSchedulePending, models.AppScheduleRunning, "")
+}
+
+func (s *appScheduleService) Finish(id int64, result error) error {
+	status, message := models.AppScheduleSucceeded, ""
+	if result != nil {
+		status, message = models.AppScheduleFailed, result.Error()
+		if len(message) > 1024 {
+			message = message[:1024]
+		}
+	}
+	_, err := s.schedule.UpdateAppScheduleStatus(nil, id, models.AppScheduleRunning, status, message)
+	return err
+}
+
+// status returns the current status of the deploy, which may have been changed by another replica
+func (s *appScheduleService) status(schedule *models.AppSchedule) string {
+	if latest, err := s.Get(schedule.Namespace, schedule.Name); err == nil && latest.ID == schedule.ID {
+		return latest.Status
+	}
+	return schedule.Status
+}
+
+// cronField the values allowed by a field of the cron expression
+type cronField map[int]bool
+
+var cronFieldRanges = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// nextCronTime returns the next time after from matching the cron expression of 5 fields,
+// minute hour day-of-month month day-of-week, each field supports *, lists, ranges and steps such as */15 and 1-5
+func nextCronTime(expr string, from time.Time) (time.Time, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return time.Time{}, fmt.Errorf("the cron expression (%s) should have 5 fields", expr)
+	}
+	var fields [5]cronField
+	for i, p := range parts {
+		f, err := parseCronField(p, cronFieldRanges[i][0], cronFieldRanges[i][1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("the cron expression (%s) is invalid: %s", expr, err.Error())
+		}
+		fields[i] = f
+	}
+	// sunday is either 0 or 7
+	if fields[4][7] {
+		fields[4][0] = true
+	}
+	minute, hour, dom, month, dow := fields[0], fields[1], fields[2], fields[3], fields[4]
+	domAny, dowAny := parts[2] == "*", parts[4] == "*"
+	matchDay := func(t time.Time) bool {
+		switch {
+		case domAny && dowAny:
+			return true
+		case domAny:
+			return dow[int(t.Weekday())]
+		case dowAny:
+			return dom[t.Day()]
+		}
+		// either of the day fields matches if both are restricted
+		return dom[t.Day()] || dow[int(t.Weekday())]
+	}
+
+	t := from.Truncate(time.Minute).Add(time.Minute)
+	limit := from.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("the cron expression (%s) doesn't match any time", expr)
+}
+
+func parseCronField(field string, min, max int) (cronField, error) {
+	res := cronField{}
+	for _, item := range strings.Split(field, ",") {
+		rng, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step (%s)", item)
+			}
+			rng = item[:i]
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value (%s)", item)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("invalid value (%s)", item)
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("the value (%s) is out of range %d-%d", item, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			res[v] = true
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAppScheduleService(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mSchedule := mockPlugin.NewMockAppSchedule(mockCtl)
+	s := &appScheduleService{schedule: mSchedule}
+
+	// create
+	at := time.Now().Add(time.Hour)
+	schedule := &models.AppSchedule{Namespace: "default", Name: "app01", Time: &at, Application: &models.ApplicationView{}}
+	mSchedule.EXPECT().GetAppSchedule(nil, "default", "app01").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	mSchedule.EXPECT().CreateAppSchedule(nil, schedule).DoAndReturn(func(_ interface{}, s *models.AppSchedule) (*models.AppSchedule, error) {
+		assert.Equal(t, models.AppSchedulePending, s.Status)
+		assert.Equal(t, at.UTC(), s.DueTime)
+		return s, nil
+	}).Times(1)
+	_, err := s.Create(schedule)
+	assert.NoError(t, err)
+
+	schedule = &models.AppSchedule{Namespace: "default", Name: "app01", Cron: "30 2 * * *", Application: &models.ApplicationView{}}
+	mSchedule.EXPECT().GetAppSchedule(nil, "default", "app01").Return(&models.AppSchedule{ID: 1, Status: models.AppScheduleSucceeded}, nil).Times(1)
+	mSchedule.EXPECT().CreateAppSchedule(nil, schedule).Return(schedule, nil).Times(1)
+	res, err := s.Create(schedule)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.DueTime.Hour())
+	assert.Equal(t, 30, res.DueTime.Minute())
+	assert.True(t, res.DueTime.After(time.Now()))
+
+	// an app has one pending deploy at most
+	mSchedule.EXPECT().GetAppSchedule(nil, "default", "app01").Return(&models.AppSchedule{ID: 1, Status: models.AppSchedulePending}, nil).Times(1)
+	_, err = s.Create(schedule)
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrResourceConflict, e.Code())
+
+	past := time.Now().Add(-time.Minute)
+	_, err = s.Create(&models.AppSchedule{Time: &past})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the time should be in the future")
+	_, err = s.Create(&models.AppSchedule{Time: &at, Cron: "* * * * *"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "only one of time and cron can be set")
+	_, err = s.Create(&models.AppSchedule{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "either time or cron is required")
+	_, err = s.Create(&models.AppSchedule{Cron: "0 25 * * *"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "out of range 0-23")
+
+	// cancel
+	mSchedule.EXPECT().GetAppSchedule(nil, "default", "app01").Return(&models.AppSchedule{ID: 1, Namespace: "default", Name: "app01", Status: models.AppSchedulePending}, nil).Times(1)
+	mSchedule.EXPECT().UpdateAppScheduleStatus(nil, int64(1), models.AppSchedulePending, models.AppScheduleCanceled, "").Return(true, nil).Times(1)
+	mSchedule.EXPECT().GetAppSchedule(nil, "default", "app01").Return(&models.AppSchedule{ID: 1, Status: models.AppScheduleCanceled}, nil).Times(1)
+	res, err = s.Cancel("default", "app01")
+	assert.NoError(t, err)
+	assert.Equal(t, models.AppScheduleCanceled, res.Status)
+
+	mSchedule.EXPECT().GetAppSchedule(nil, "default", "app01").Return(&models.AppSchedule{ID: 1, Namespace: "default", Name: "app01", Status: models.AppSchedulePending}, nil).Times(1)
+	mSchedule.EXPECT().UpdateAppScheduleStatus(nil, int64(1), models.AppSchedulePending, models.AppScheduleCanceled, "").Return(false, nil).Times(1)
+	mSchedule.EXPECT().GetAppSchedule(nil, "default", "app01").Return(&models.AppSchedule{ID: 1, Status: models.AppScheduleRunning}, nil).Times(1)
+	_, err = s.Cancel("default", "app01")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "only the pending deploy can be canceled, it is running")
+
+	// claim and finish
+	mSchedule.EXPECT().UpdateAppScheduleStatus(nil, int64(1), models.AppSchedulePending, models.AppScheduleRunning, "").Return(true, nil).Times(1)
+	ok, err = s.Claim(1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	mSchedule.EXPECT().UpdateAppScheduleStatus(nil, int64(1), models.AppScheduleRunning, models.AppScheduleSucceeded, "").Return(true, nil).Times(1)
+	assert.NoError(t, s.Finish(1, nil))
+	mSchedule.EXPECT().UpdateAppScheduleStatus(nil, int64(2), models.AppScheduleRunning, models.AppScheduleFailed, "app not found").Return(true, nil).Times(1)
+	assert.NoError(t, s.Finish(2, errors.New("app not found")))
+}
+
+func TestNextCronTime(t *testing.T) {
+	// 2026-10-17 is a saturday
+	from := time.Date(2026, 10, 17, 10, 20, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 17, 10, 21, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 17, 10, 30, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC)},
+		{"30 1-3 * * 1-5", time.Date(2026, 10, 19, 1, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 20,25 * *", time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)},
+		// either of the day fields
+		{"0 12 25 * 1", time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		next, err := nextCronTime(tt.expr, from)
+		assert.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.next, next, tt.expr)
+	}
+
+	for _, expr := range []string{"* * * *", "60 * * * *", "a * * * *", "*/0 * * * *", "5-1 * * * *", "0 0 31 2 *"} {
+		_, err := nextCronTime(expr, from)
+		assert.Error(t, err, expr)
+	}
+}
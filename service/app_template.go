@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/app_template.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppTemplateService
+
+// AppTemplateService manages the parameterized applications
+type AppTemplateService interface {
+	Get(namespace, name string) (*models.AppTemplate, error)
+	List(namespace string, listOptions *models.ListOptions) (*models.AppTemplateList, error)
+	Create(template *models.AppTemplate) (*models.AppTemplate, error)
+	Update(template *models.AppTemplate) (*models.AppTemplate, error)
+	Delete(namespace, name string) error
+	// Render renders the template with the values of the parameters
+	Render(template *models.AppTemplate, values map[string]string) ([]byte, error)
+}
+
+var appTemplateParamRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// appTemplateFuncs the functions of the templates, quote renders the value as a double-quoted scalar of yaml,
+// so that the values with the characters special to yaml, such as ": " and "#", are kept as they are
+var appTemplateFuncs = template.FuncMap{
+	"quote": func(v string) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+type appTemplateService struct {
+	appTemplate plugin.AppTemplate
+}
+
+// NewAppTemplateService NewAppTemplateService
+func NewAppTemplateService(config *config.CloudConfig) (AppTemplateService, error) {
+	tpl, err := plugin.GetPlugin(config.Plugin.AppTpl)
+	if err != nil {
+		return nil, err
+	}
+	return &appTemplateService{
+		appTemplate: tpl.(plugin.AppTemplate),
+	}, nil
+}
+
+// Get get an app template
+func (s *appTemplateService) Get(namespace, name string) (*models.AppTemplate, error) {
+	return s.appTemplate.GetAppTemplate(nil, namespace, name)
+}
+
+// List list app templates
+func (s *appTemplateService) List(namespace string, listOptions *models.ListOptions) (*models.AppTemplateList, error) {
+	return s.appTemplate.ListAppTemplate(nil, namespace, listOptions)
+}
+
+// Create create an app template
+func (s *appTemplateService) Create(tpl *models.AppTemplate) (*models.AppTemplate, error) {
+	if err := checkAppTemplate(tpl); err != nil {
+		return nil, err
+	}
+	return s.appTemplate.CreateAppTemplate(nil, tpl)
+}
+
+// Update update an app template
+func (s *appTemplateService) Update(tpl *models.AppTemplate) (*models.AppTemplate, error) {
+	if err := checkAppTemplate(tpl); err != nil {
+		return nil, err
+	}
+	return s.appTemplate.UpdateAppTemplate(nil, tpl)
+}
+
+// Delete delete an app template
+func (s *appTemplateService) Delete(namespace, name string) error {
+	return s.appTemplate.DeleteAppTemplate(nil, namespace, name)
+}
+
+// Render renders the template with the values of the parameters, the default values are used if not given,
+// an empty value of a required parameter is taken as not given.
+// The values with line breaks or other control characters are rejected, since they could add keys to the
+// rendered yaml, the other values are supposed to be rendered by quote unless they are plain words
+func (s *appTemplateService) Render(tpl *models.AppTemplate, values map[string]string) ([]byte, error) {
+	params := map[string]string{}
+	var missing []string
+	for _, p := range tpl.Parameters {
+		v, ok := values[p.Name]
+		if !ok || (p.Required && v == "") {
+			v = p.Default
+			if p.Required && v == "" {
+				missing = append(missing, p.Name)
+			}
+		}
+		if strings.IndexFunc(v, unicode.IsControl) >= 0 {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the value of the parameter (%s) can't contain line breaks or other control characters", p.Name)))
+		}
+		params[p.Name] = v
+	}
+	if len(missing) > 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the required parameters (%s) are missing", strings.Join(missing, ", "))))
+	}
+	var unknown []string
+	for k := range values {
+		if _, ok := params[k]; !ok {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the parameters (%s) are not declared by the template", strings.Join(unknown, ", "))))
+	}
+
+	t, err := parseAppTemplate(tpl)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err = t.Execute(buf, params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("failed to render the template: %s", err.Error())))
+	}
+	return buf.Bytes(), nil
+}
+
+// checkAppTemplate checks that the parameters are declared once with valid names and the template can be parsed
+func checkAppTemplate(tpl *models.AppTemplate) error {
+	declared := map[string]bool{}
+	for _, p := range tpl.Parameters {
+		if !appTemplateParamRegex.MatchString(p.Name) {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the name of the parameter (%s) should consist of letters, digits and underscores, and can't start with a digit", p.Name)))
+		}
+		if declared[p.Name] {
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the parameter (%s) is declared more than once", p.Name)))
+		}
+		declared[p.Name] = true
+	}
+	_, err := parseAppTemplate(tpl)
+	return err
+}
+
+// parseAppTemplate parses the template, the parameters not declared are reported when it is rendered
+func parseAppTemplate(tpl *models.AppTemplate) (*template.Template, error) {
+	t, err := template.New(tpl.Name).Option("missingkey=error").Funcs(appTemplateFuncs).Parse(tpl.Template)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("failed to parse the template: %s", err.Error())))
+	}
+	return t, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAppTemplateService_Render(t *testing.T) {
+	s := &appTemplateService{}
+	tpl := &models.AppTemplate{
+		Name: "tpl01",
+		Parameters: []models.AppTemplateParameter{
+			{Name: "name", Required: true},
+			{Name: "version", Required: true, Default: "latest"},
+			{Name: "port", Default: "80"},
+			{Name: "host"},
+		},
+		Template: "name: {{ .name }}\nimage: nginx:{{ .version }}\nport: {{ .port }}\nhost: '{{ .host }}'",
+	}
+
+	res, err := s.Render(tpl, map[string]string{"name": "web"})
+	assert.NoError(t, err)
+	assert.Equal(t, "name: web\nimage: nginx:latest\nport: 80\nhost: ''", string(res))
+
+	res, err = s.Render(tpl, map[string]string{"name": "web", "version": "1.25", "port": "8080"})
+	assert.NoError(t, err)
+	assert.Equal(t, "name: web\nimage: nginx:1.25\nport: 8080\nhost: ''", string(res))
+
+	_, err = s.Render(tpl, map[string]string{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the required parameters (name) are missing")
+
+	// the empty value of a required parameter is not taken
+	_, err = s.Render(tpl, map[string]string{"name": ""})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the required parameters (name) are missing")
+	res, err = s.Render(tpl, map[string]string{"name": "web", "version": ""})
+	assert.NoError(t, err)
+	assert.Equal(t, "name: web\nimage: nginx:latest\nport: 80\nhost: ''", string(res))
+
+	// the values can't add keys to the rendered yaml
+	_, err = s.Render(tpl, map[string]string{"name": "web\nprivileged: true"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the value of the parameter (name) can't contain line breaks")
+
+	quoted := &models.AppTemplate{
+		Name:       "tpl02",
+		Parameters: []models.AppTemplateParameter{{Name: "cmd"}},
+		Template:   "cmd: {{ quote .cmd }}",
+	}
+	res, err = s.Render(quoted, map[string]string{"cmd": `echo "a: b" # c`})
+	assert.NoError(t, err)
+	assert.Equal(t, `cmd: "echo \"a: b\" # c"`, string(res))
+
+	_, err = s.Render(tpl, map[string]string{"name": "web", "b": "1", "a": "2"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the parameters (a, b) are not declared by the template")
+
+	// the placeholders not declared fail to be rendered
+	tpl.Template = "name: {{ .name }}\nimage: {{ .image }}"
+	_, err = s.Render(tpl, map[string]string{"name": "web"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to render the template")
+}
+
+func TestAppTemplateService_Check(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mTpl := mockPlugin.NewMockAppTemplate(mockCtl)
+	s := &appTemplateService{appTemplate: mTpl}
+
+	tpl := &models.AppTemplate{
+		Name:       "tpl01",
+		Namespace:  "default",
+		Parameters: []models.AppTemplateParameter{{Name: "image_tag"}},
+		Template:   "name: app\nimage: nginx:{{ .image_tag }}",
+	}
+	mTpl.EXPECT().CreateAppTemplate(nil, tpl).Return(tpl, nil).Times(1)
+	_, err := s.Create(tpl)
+	assert.NoError(t, err)
+
+	mTpl.EXPECT().UpdateAppTemplate(nil, tpl).Return(tpl, nil).Times(1)
+	_, err = s.Update(tpl)
+	assert.NoError(t, err)
+
+	tpl.Template = "name: {{ .name"
+	_, err = s.Create(tpl)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse the template")
+
+	tpl.Template = "name: app"
+	tpl.Parameters = []models.AppTemplateParameter{{Name: "image-tag"}}
+	_, err = s.Update(tpl)
+	assert.Error(t, err)
+	tpl.Parameters = []models.AppTemplateParameter{{Name: "a"}, {Name: "a"}}
+	_, err = s.Create(tpl)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the parameter (a) is declared more than once")
+
+	mTpl.EXPECT().GetAppTemplate(nil, "default", "tpl01").Return(tpl, nil).Times(1)
+	_, err = s.Get("default", "tpl01")
+	assert.NoError(t, err)
+	mTpl.EXPECT().ListAppTemplate(nil, "default", gomock.Any()).Return(&models.AppTemplateList{}, nil).Times(1)
+	_, err = s.List("default", &models.ListOptions{})
+	assert.NoError(t, err)
+	mTpl.EXPECT().DeleteAppTemplate(nil, "default", "tpl01").Return(nil).Times(1)
+	assert.NoError(t, s.Delete("default", "tpl01"))
+}
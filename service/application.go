@@ -2,6 +2,7 @@ package service
 
 import (
 	"strings"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
@@ -24,12 +25,29 @@ type ApplicationService interface {
 	List(namespace string, listOptions *models.ListOptions) (*models.ApplicationList, error)
 	ListByNames(ns string, names []string) ([]models.AppItem, error)
 	CreateWithBase(tx interface{}, namespace string, app, base *specV1.Application) (*specV1.Application, error)
+	GetHistory(namespace, name, version string) (*specV1.Application, error)
+
+	// Trash deletes the application and keeps it in the trash until the expire time
+	Trash(tx interface{}, namespace string, app *specV1.Application, expireTime time.Time) error
+	GetTrash(namespace, name string) (*models.AppTrash, error)
+	ListTrash(namespace string) (*models.AppTrashList, error)
+	ListExpiredTrash(before time.Time, limit int) ([]models.AppTrash, error)
+	// DeleteTrash removes the application restored from the trash
+	DeleteTrash(tx interface{}, namespace, name string) error
+	// PurgeTrash removes the application from the trash along with its history
+	PurgeTrash(tx interface{}, namespace, name string) error
+
+	// GetCanary returns the canary of the application update in progress
+	GetCanary(namespace, name string) (*models.AppCanary, error)
+	SaveCanary(tx interface{}, canary *models.AppCanary) (*models.AppCanary, error)
+	DeleteCanary(tx interface{}, namespace, name string) error
 }
 
 type AppServiceImpl struct {
 	Config       plugin.Configuration
 	Secret       plugin.Secret
 	App          plugin.Application
+	AppHis       plugin.AppHistory
 	IndexService IndexService
 }
 
@@ -47,6 +65,10 @@ func NewApplicationService(config *config.CloudConfig) (ApplicationService, erro
 	if err != nil {
 		return nil, err
 	}
+	appHis, err := plugin.GetPlugin(config.Plugin.AppHistory)
+	if err != nil {
+		return nil, err
+	}
 
 	is, err := NewIndexService(config)
 	if err != nil {
@@ -57,6 +79,7 @@ func NewApplicationService(config *config.CloudConfig) (ApplicationService, erro
 		Config:       cfg.(plugin.Configuration),
 		Secret:       secret.(plugin.Secret),
 		App:          app.(plugin.Application),
+		AppHis:       appHis.(plugin.AppHistory),
 	}, nil
 }
 
@@ -67,7 +90,11 @@ func (a *AppServiceImpl) Get(namespace, name, version string) (*specV1.Applicati
 		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "app"),
 			common.Field("name", name))
 	}
-
+	if err == nil && app != nil {
+		if err = guardNamespace(namespace, common.Application, name, app.Namespace); err != nil {
+			return nil, err
+		}
+	}
 	return app, err
 }
 
@@ -90,6 +117,10 @@ func (a *AppServiceImpl) Create(tx interface{}, namespace string, app *specV1.Ap
 		return nil, err
 	}
 
+	if _, err = a.AppHis.CreateApplicationHis(tx, app); err != nil {
+		return nil, err
+	}
+
 	return app, nil
 }
 
@@ -117,11 +148,26 @@ func (a *AppServiceImpl) Update(tx interface{}, namespace string, app *specV1.Ap
 		return nil, err
 	}
 
+	if _, err = a.AppHis.CreateApplicationHis(tx, newApp); err != nil {
+		return nil, err
+	}
+
 	return newApp, nil
 }
 
 // Delete delete application
 func (a *AppServiceImpl) Delete(tx interface{}, namespace, name, version string) error {
+	if err := a.delete(tx, namespace, name); err != nil {
+		return err
+	}
+	if err := a.AppHis.DeleteApplicationHis(tx, namespace, name); err != nil {
+		log.L().Error("Application clean history error", log.Error(err))
+	}
+
+	return nil
+}
+
+func (a *AppServiceImpl) delete(tx interface{}, namespace, name string) error {
 	if err := a.App.DeleteApplication(tx, namespace, name); err != nil {
 		return err
 	}
@@ -133,14 +179,73 @@ func (a *AppServiceImpl) Delete(tx interface{}, namespace, name, version string)
 	if err := a.IndexService.RefreshSecretIndexByApp(tx, namespace, name, []string{}); err != nil {
 		log.L().Error("Application clean secret index error", log.Error(err))
 	}
-
+	if err := a.AppHis.DeleteApplicationCanary(tx, namespace, name); err != nil {
+		log.L().Error("Application clean canary error", log.Error(err))
+	}
 	return nil
 }
 
+// Trash the history of the application is kept until it is purged from the trash
+func (a *AppServiceImpl) Trash(tx interface{}, namespace string, app *specV1.Application, expireTime time.Time) error {
+	if err := a.delete(tx, namespace, app.Name); err != nil {
+		return err
+	}
+	_, err := a.AppHis.CreateApplicationTrash(tx, app, expireTime)
+	return err
+}
+
+func (a *AppServiceImpl) GetTrash(namespace, name string) (*models.AppTrash, error) {
+	return a.AppHis.GetApplicationTrash(nil, namespace, name)
+}
+
+func (a *AppServiceImpl) ListTrash(namespace string) (*models.AppTrashList, error) {
+	items, err := a.AppHis.ListApplicationTrash(nil, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AppTrashList{Total: len(items), Items: items}, nil
+}
+
+func (a *AppServiceImpl) ListExpiredTrash(before time.Time, limit int) ([]models.AppTrash, error) {
+	return a.AppHis.ListExpiredApplicationTrash(nil, before, limit)
+}
+
+func (a *AppServiceImpl) DeleteTrash(tx interface{}, namespace, name string) error {
+	return a.AppHis.DeleteApplicationTrash(tx, namespace, name)
+}
+
+func (a *AppServiceImpl) PurgeTrash(tx interface{}, namespace, name string) error {
+	if err := a.AppHis.DeleteApplicationTrash(tx, namespace, name); err != nil {
+		return err
+	}
+	return a.AppHis.DeleteApplicationHis(tx, namespace, name)
+}
+
+func (a *AppServiceImpl) GetCanary(namespace, name string) (*models.AppCanary, error) {
+	return a.AppHis.GetApplicationCanary(nil, namespace, name)
+}
+
+func (a *AppServiceImpl) SaveCanary(tx interface{}, canary *models.AppCanary) (*models.AppCanary, error) {
+	return a.AppHis.SaveApplicationCanary(tx, canary)
+}
+
+func (a *AppServiceImpl) DeleteCanary(tx interface{}, namespace, name string) error {
+	return a.AppHis.DeleteApplicationCanary(tx, namespace, name)
+}
+
 // List get list config
 func (a *AppServiceImpl) List(namespace string,
 	listOptions *models.ListOptions) (*models.ApplicationList, error) {
-	return a.App.ListApplication(nil, namespace, listOptions)
+	res, err := a.App.ListApplication(nil, namespace, listOptions)
+	if err != nil || res == nil {
+		return res, err
+	}
+	total := len(res.Items)
+	res.Items = guardNamespaceList(namespace, common.Application, res.Items, func(app *models.AppItem) (string, string) {
+		return app.Namespace, app.Name
+	})
+	res.Total -= total - len(res.Items)
+	return res, nil
 }
 
 func (a *AppServiceImpl) ListByNames(ns string, names []string) ([]models.AppItem, error) {
@@ -151,6 +256,16 @@ func (a *AppServiceImpl) ListByNames(ns string, names []string) ([]models.AppIte
 	return res, nil
 }
 
+// GetHistory get the application of the specified historical version
+func (a *AppServiceImpl) GetHistory(namespace, name, version string) (*specV1.Application, error) {
+	app, err := a.AppHis.GetApplicationHis(nil, namespace, name, version)
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "app version"),
+			common.Field("name", name+"@"+version))
+	}
+	return app, err
+}
+
 // CreateWithBase create application with base
 func (a *AppServiceImpl) CreateWithBase(tx interface{}, namespace string, app, base *specV1.Application) (*specV1.Application, error) {
 	if base != nil {
@@ -210,12 +325,14 @@ func (a *AppServiceImpl) getConfigsAndSecrets(tx interface{}, namespace string,
 	var secrets []string
 	for _, vol := range app.Volumes {
 		if vol.Config != nil {
-			// set the lastest config version
+			// set the lastest config version unless the version is pinned
 			config, err := a.Config.GetConfig(tx, namespace, vol.Config.Name, "")
 			if err != nil {
 				return nil, nil, err
 			}
-			vol.Config.Version = config.Version
+			if vol.Config.Version == "" || !IsConfigPinned(app, vol.Config.Name) {
+				vol.Config.Version = config.Version
+			}
 			configs = append(configs, vol.Config.Name)
 		}
 		if vol.Secret != nil {
@@ -5,11 +5,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/json"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
@@ -126,6 +128,7 @@ func TestDefaultApplicationService_Delete(t *testing.T) {
 	as := AppServiceImpl{
 		IndexService: mockIndexService,
 		App:          mockObject.app,
+		AppHis:       mockObject.appHis,
 	}
 	newApp, _ := genAppTestCase()
 
@@ -134,6 +137,8 @@ func TestDefaultApplicationService_Delete(t *testing.T) {
 	assert.NotNil(t, err)
 
 	mockObject.app.EXPECT().DeleteApplication(nil, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockObject.appHis.EXPECT().DeleteApplicationCanary(nil, newApp.Namespace, newApp.Name).Return(fmt.Errorf("error")).Times(1)
+	mockObject.appHis.EXPECT().DeleteApplicationHis(nil, newApp.Namespace, newApp.Name).Return(fmt.Errorf("error")).Times(1)
 	mockIndexService.EXPECT().RefreshConfigIndexByApp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("error"))
 	mockIndexService.EXPECT().RefreshSecretIndexByApp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("error"))
 	err = as.Delete(nil, newApp.Namespace, newApp.Name, "")
@@ -141,11 +146,76 @@ func TestDefaultApplicationService_Delete(t *testing.T) {
 
 	mockIndexService.EXPECT().RefreshConfigIndexByApp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 	mockIndexService.EXPECT().RefreshSecretIndexByApp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mockObject.appHis.EXPECT().DeleteApplicationCanary(nil, newApp.Namespace, newApp.Name).Return(nil).Times(1)
+	mockObject.appHis.EXPECT().DeleteApplicationHis(nil, newApp.Namespace, newApp.Name).Return(nil).Times(1)
 
 	err = as.Delete(nil, newApp.Namespace, newApp.Name, "")
 	assert.NoError(t, err)
 }
 
+func TestDefaultApplicationService_Trash(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	mockIndexService := ms.NewMockIndexService(mockObject.ctl)
+	as := AppServiceImpl{
+		IndexService: mockIndexService,
+		App:          mockObject.app,
+		AppHis:       mockObject.appHis,
+	}
+	newApp, _ := genAppTestCase()
+	expire := time.Now().Add(time.Hour)
+	trash := models.AppTrash{Name: newApp.Name, Namespace: newApp.Namespace, Application: newApp}
+
+	// the history is kept
+	mockObject.app.EXPECT().DeleteApplication(nil, newApp.Namespace, newApp.Name).Return(nil).Times(1)
+	mockIndexService.EXPECT().RefreshConfigIndexByApp(nil, newApp.Namespace, newApp.Name, []string{}).Return(nil)
+	mockIndexService.EXPECT().RefreshSecretIndexByApp(nil, newApp.Namespace, newApp.Name, []string{}).Return(nil)
+	mockObject.appHis.EXPECT().DeleteApplicationCanary(nil, newApp.Namespace, newApp.Name).Return(nil)
+	mockObject.appHis.EXPECT().CreateApplicationTrash(nil, newApp, expire).Return(&trash, nil).Times(1)
+	err := as.Trash(nil, newApp.Namespace, newApp, expire)
+	assert.NoError(t, err)
+
+	mockObject.appHis.EXPECT().ListApplicationTrash(nil, newApp.Namespace).Return([]models.AppTrash{trash}, nil).Times(1)
+	list, err := as.ListTrash(newApp.Namespace)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.AppTrashList{Total: 1, Items: []models.AppTrash{trash}}, list)
+
+	mockObject.appHis.EXPECT().DeleteApplicationTrash(nil, newApp.Namespace, newApp.Name).Return(nil).Times(1)
+	err = as.DeleteTrash(nil, newApp.Namespace, newApp.Name)
+	assert.NoError(t, err)
+
+	mockObject.appHis.EXPECT().DeleteApplicationTrash(nil, newApp.Namespace, newApp.Name).Return(fmt.Errorf("error")).Times(1)
+	err = as.PurgeTrash(nil, newApp.Namespace, newApp.Name)
+	assert.Error(t, err)
+
+	mockObject.appHis.EXPECT().DeleteApplicationTrash(nil, newApp.Namespace, newApp.Name).Return(nil).Times(1)
+	mockObject.appHis.EXPECT().DeleteApplicationHis(nil, newApp.Namespace, newApp.Name).Return(nil).Times(1)
+	err = as.PurgeTrash(nil, newApp.Namespace, newApp.Name)
+	assert.NoError(t, err)
+}
+
+func TestDefaultApplicationService_Canary(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	as := AppServiceImpl{AppHis: mockObject.appHis}
+	canary := &models.AppCanary{Name: "abc", Namespace: "default", Version: "2", StableVersion: "1", Percent: 10, Nodes: []string{"n1"}}
+
+	mockObject.appHis.EXPECT().SaveApplicationCanary(nil, canary).Return(canary, nil).Times(1)
+	res, err := as.SaveCanary(nil, canary)
+	assert.NoError(t, err)
+	assert.Equal(t, canary, res)
+
+	mockObject.appHis.EXPECT().GetApplicationCanary(nil, "default", "abc").Return(canary, nil).Times(1)
+	res, err = as.GetCanary("default", "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, canary, res)
+
+	mockObject.appHis.EXPECT().DeleteApplicationCanary(nil, "default", "abc").Return(nil).Times(1)
+	assert.NoError(t, as.DeleteCanary(nil, "default", "abc"))
+}
+
 func TestDefaultApplicationService_CreateWithBase(t *testing.T) {
 	mockObject := InitMockEnvironment(t)
 	defer mockObject.Close()
@@ -157,6 +227,7 @@ func TestDefaultApplicationService_CreateWithBase(t *testing.T) {
 		Config:       mockObject.configuration,
 		Secret:       mockObject.secret,
 		App:          mockObject.app,
+		AppHis:       mockObject.appHis,
 	}
 	config := &specV1.Configuration{Name: "agent-conf", Version: "123"}
 	secret2 := &specV1.Secret{Name: "test-secret-02", Version: "123"}
@@ -165,6 +236,7 @@ func TestDefaultApplicationService_CreateWithBase(t *testing.T) {
 	mockIndexService.EXPECT().RefreshConfigIndexByApp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	mockIndexService.EXPECT().RefreshSecretIndexByApp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	mockObject.app.EXPECT().CreateApplication(gomock.Any(), gomock.Any(), gomock.Any()).Return(newApp, nil).Times(1)
+	mockObject.appHis.EXPECT().CreateApplicationHis(gomock.Any(), newApp).Return(newApp, nil).AnyTimes()
 	mockObject.configuration.EXPECT().GetConfig(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(config, nil).Times(2)
 	mockObject.secret.EXPECT().GetSecret(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(secret2, nil)
 	_, err := as.CreateWithBase(nil, newApp.Namespace, newApp, baseApp)
@@ -225,6 +297,7 @@ func TestDefaultApplicationService_Update(t *testing.T) {
 		Config:       mockObject.configuration,
 		Secret:       mockObject.secret,
 		App:          mockObject.app,
+		AppHis:       mockObject.appHis,
 	}
 
 	newApp, oldApp := genAppTestCase()
@@ -238,6 +311,7 @@ func TestDefaultApplicationService_Update(t *testing.T) {
 	mockIndexService.EXPECT().RefreshConfigIndexByApp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	mockIndexService.EXPECT().RefreshSecretIndexByApp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	mockObject.app.EXPECT().UpdateApplication(nil, newApp.Namespace, newApp).Return(oldApp, nil)
+	mockObject.appHis.EXPECT().CreateApplicationHis(nil, oldApp).Return(oldApp, nil).Times(1)
 	mockObject.configuration.EXPECT().GetConfig(gomock.Any(), gomock.Any(), gomock.Any(), "").Return(&specV1.Configuration{Version: "1"}, nil).AnyTimes()
 	mockObject.secret.EXPECT().GetSecret(gomock.Any(), gomock.Any(), secret1.Name, gomock.Any()).Return(secret1, nil).AnyTimes()
 	mockObject.secret.EXPECT().GetSecret(gomock.Any(), gomock.Any(), secret2.Name, gomock.Any()).Return(secret2, nil).AnyTimes()
@@ -257,6 +331,27 @@ func TestDefaultApplicationService_Update(t *testing.T) {
 
 }
 
+func TestDefaultApplicationService_GetHistory(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	app := &specV1.Application{Name: "app", Namespace: "default", Version: "1"}
+	mockObject.appHis.EXPECT().GetApplicationHis(nil, "default", "app", "1").Return(app, nil).Times(1)
+	mockObject.appHis.EXPECT().GetApplicationHis(nil, "default", "app", "2").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	cs, err := NewApplicationService(mockObject.conf)
+	assert.NoError(t, err)
+
+	res, err := cs.GetHistory("default", "app", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, app, res)
+
+	_, err = cs.GetHistory("default", "app", "2")
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrResourceNotFound, e.Code())
+}
+
 func TestDefaultApplicationService_constuctConfig(t *testing.T) {
 	mockObject := InitMockEnvironment(t)
 	defer mockObject.Close()
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/audit.go -package=service github.com/baetyl/baetyl-cloud/v2/service AuditService
+
+// AuditService records and queries the audit trail of the mutating api calls
+type AuditService interface {
+	Create(audit *models.Audit) error
+	List(namespace string, params *models.AuditListOptions) (*models.AuditList, error)
+}
+
+type auditService struct {
+	sink plugin.AuditSink
+}
+
+// NewAuditService NewAuditService
+func NewAuditService(config *config.CloudConfig) (AuditService, error) {
+	sink, err := plugin.GetPlugin(config.Plugin.AuditSink)
+	if err != nil {
+		return nil, err
+	}
+	return &auditService{
+		sink: sink.(plugin.AuditSink),
+	}, nil
+}
+
+// Create create an audit record
+func (s *auditService) Create(audit *models.Audit) error {
+	return s.sink.CreateAudit(nil, audit)
+}
+
+// List list the audit records of the namespace
+func (s *auditService) List(namespace string, params *models.AuditListOptions) (*models.AuditList, error) {
+	return s.sink.ListAudit(nil, namespace, params)
+}
@@ -1,18 +1,49 @@
 package service
 
 import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
 //go:generate mockgen -destination=../mock/service/auth.go -package=service github.com/baetyl/baetyl-cloud/v2/service AuthService
 
+const (
+	authCacheKeyPrefix   = "baetyl-cloud:auth:"
+	authRevokedKeyPrefix = "baetyl-cloud:auth-revoked:"
+)
+
+func init() {
+	// the values set by the auth plugins are kept in the cache store encoded by gob
+	gob.Register(common.User{})
+	gob.Register(common.UserInfo{})
+}
+
 type AuthService interface {
 	plugin.Auth
+	// InvalidateToken forgets the cached auth result of the token, it is supposed to be called once the token is revoked
+	InvalidateToken(token string)
 }
 
 type authService struct {
 	plugin.Auth
+	// store keeps the auth results and the revoked tokens, which is shared by the replicas if it is of redis
+	store persist.CacheStore
+	ttl   time.Duration
+	log   *log.Logger
+}
+
+// authCacheEntry the values set by the auth plugin for a token
+type authCacheEntry struct {
+	Values map[string]interface{}
 }
 
 func NewAuthService(config *config.CloudConfig) (AuthService, error) {
@@ -20,5 +51,101 @@ func NewAuthService(config *config.CloudConfig) (AuthService, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &authService{auth.(plugin.Auth)}, nil
+	as := &authService{
+		Auth: auth.(plugin.Auth),
+		ttl:  config.AuthCache.TTL,
+		log:  log.With(log.Any("service", "auth")),
+	}
+	// only the results of the plugins depending on the token only are cached
+	if _, ok := as.Auth.(plugin.CacheableAuth); ok && as.ttl > 0 {
+		as.store, err = newAuthCacheStore(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return as, nil
+}
+
+// newAuthCacheStore creates the store of the auth results, the one in the memory keeps AuthCache.MaxEntries results
+// at most, and the one of redis is bounded by the eviction policy of redis
+func newAuthCacheStore(config *config.CloudConfig) (persist.CacheStore, error) {
+	switch config.AdminServer.Cache.Type {
+	case "", CacheTypeMemory:
+		if config.AuthCache.MaxEntries > 0 {
+			return NewBoundedMemoryStore(config.AuthCache.MaxEntries), nil
+		}
+	}
+	return NewCacheStore(config.AdminServer.Cache, config.AuthCache.TTL)
+}
+
+// Authenticate replays the values set by the auth plugin for the token authenticated within the ttl, which is
+// capped at the expiry of the token. Only the successful results are cached and they are never refreshed by the hits
+func (a *authService) Authenticate(c *common.Context) error {
+	if a.store == nil || c.Context == nil {
+		return a.Auth.Authenticate(c)
+	}
+	token := c.GetHeader("Authorization")
+	if token == "" {
+		return a.Auth.Authenticate(c)
+	}
+	hash := hashToken(token)
+	var entry authCacheEntry
+	if err := a.store.Get(authCacheKeyPrefix+hash, &entry); err == nil {
+		for k, v := range entry.Values {
+			c.Set(k, v)
+		}
+		return nil
+	} else if err != persist.ErrCacheMiss {
+		a.log.Warn("failed to get auth cache", log.Error(err))
+	}
+
+	before := map[string]bool{}
+	for k := range c.Keys {
+		before[k] = true
+	}
+	if err := a.Auth.Authenticate(c); err != nil {
+		return err
+	}
+	ttl := a.ttl
+	if exp := a.Auth.(plugin.CacheableAuth).TokenExpiry(c); !exp.IsZero() {
+		if d := time.Until(exp); d < ttl {
+			ttl = d
+		}
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	// the token revoked during the authentication is not cached
+	var revoked bool
+	if err := a.store.Get(authRevokedKeyPrefix+hash, &revoked); err != persist.ErrCacheMiss {
+		return nil
+	}
+	entry = authCacheEntry{Values: map[string]interface{}{}}
+	for k, v := range c.Keys {
+		if !before[k] {
+			entry.Values[k] = v
+		}
+	}
+	if err := a.store.Set(authCacheKeyPrefix+hash, entry, ttl); err != nil {
+		a.log.Warn("failed to set auth cache", log.Error(err))
+	}
+	return nil
+}
+
+func (a *authService) InvalidateToken(token string) {
+	if a.store == nil {
+		return
+	}
+	hash := hashToken(token)
+	if err := a.store.Set(authRevokedKeyPrefix+hash, true, a.ttl); err != nil {
+		a.log.Warn("failed to revoke auth cache", log.Error(err))
+	}
+	if err := a.store.Delete(authCacheKeyPrefix + hash); err != nil && err != persist.ErrCacheMiss {
+		a.log.Warn("failed to delete auth cache", log.Error(err))
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -1,11 +1,20 @@
 package service
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/auth"
 )
 
@@ -20,3 +29,89 @@ func TestAuthService_Authenticate(t *testing.T) {
 	err = as.Authenticate(c)
 	assert.Nil(t, err)
 }
+
+// cacheableAuth the auth plugin of which the results only depend on the token
+type cacheableAuth struct {
+	plugin.Auth
+	expiry time.Time
+}
+
+func (a *cacheableAuth) TokenExpiry(_ *common.Context) time.Time {
+	return a.expiry
+}
+
+func TestAuthService_AuthenticateCache(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	mockObject.conf.AuthCache.TTL = time.Hour
+	as, err := NewAuthService(mockObject.conf)
+	assert.NoError(t, err)
+	// the results of the plugins not opting in are never cached
+	assert.Nil(t, as.(*authService).store)
+
+	plugin := &cacheableAuth{Auth: mockObject.auth}
+	store := persist.NewInMemoryStore(time.Minute)
+	cached := &authService{Auth: plugin, store: store, ttl: time.Hour, log: log.L()}
+	// the replicas share the results and the revocations by the store
+	other := &authService{Auth: plugin, store: store, ttl: time.Hour, log: log.L()}
+
+	newContext := func(token string) *common.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/v1/apps", nil)
+		if token != "" {
+			c.Request.Header.Set("Authorization", token)
+		}
+		return common.NewContext(c)
+	}
+	authenticate := func(c *common.Context) error {
+		c.SetNamespace("ns-" + c.GetHeader("Authorization"))
+		c.SetUserInfo(common.UserInfo{User: common.User{ID: c.GetHeader("Authorization")}})
+		return nil
+	}
+
+	// the results are replayed within the ttl
+	mockObject.auth.EXPECT().Authenticate(gomock.Any()).DoAndReturn(authenticate).Times(1)
+	for _, s := range []*authService{cached, cached, other} {
+		c := newContext("a")
+		assert.NoError(t, s.Authenticate(c))
+		assert.Equal(t, "ns-a", c.GetNamespace())
+		assert.Equal(t, "a", c.GetUserInfo().User.ID)
+	}
+
+	// the failures and the requests without token are never cached
+	mockObject.auth.EXPECT().Authenticate(gomock.Any()).Return(errors.New("denied")).Times(2)
+	assert.Error(t, cached.Authenticate(newContext("b")))
+	assert.Error(t, cached.Authenticate(newContext("b")))
+	mockObject.auth.EXPECT().Authenticate(gomock.Any()).DoAndReturn(authenticate).Times(2)
+	assert.NoError(t, cached.Authenticate(newContext("")))
+	assert.NoError(t, cached.Authenticate(newContext("")))
+
+	// the token revoked by a replica is authenticated again by the others
+	cached.InvalidateToken("a")
+	mockObject.auth.EXPECT().Authenticate(gomock.Any()).Return(errors.New("revoked")).Times(1)
+	assert.Error(t, other.Authenticate(newContext("a")))
+
+	// the results are kept until the token expires at most
+	plugin.expiry = time.Now().Add(time.Millisecond * 50)
+	mockObject.auth.EXPECT().Authenticate(gomock.Any()).DoAndReturn(authenticate).Times(1)
+	assert.NoError(t, cached.Authenticate(newContext("c")))
+	assert.NoError(t, cached.Authenticate(newContext("c")))
+	time.Sleep(time.Millisecond * 100)
+	mockObject.auth.EXPECT().Authenticate(gomock.Any()).Return(errors.New("expired")).Times(1)
+	assert.Error(t, cached.Authenticate(newContext("c")))
+
+	// the expired tokens are never cached
+	mockObject.auth.EXPECT().Authenticate(gomock.Any()).DoAndReturn(authenticate).Times(2)
+	assert.NoError(t, cached.Authenticate(newContext("d")))
+	assert.NoError(t, cached.Authenticate(newContext("d")))
+
+	// the values are encoded by gob in the redis store
+	data, err := persist.Serialize(authCacheEntry{Values: map[string]interface{}{
+		"namespace": "default",
+		"userInfo":  common.UserInfo{User: common.User{ID: "a"}},
+	}})
+	assert.NoError(t, err)
+	var entry authCacheEntry
+	assert.NoError(t, persist.Deserialize(data, &entry))
+	assert.Equal(t, common.UserInfo{User: common.User{ID: "a"}}, entry.Values["userInfo"])
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/go-redis/redis/v8"
+	gocache "github.com/robfig/go-cache"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+const (
+	CacheTypeMemory = "memory"
+	CacheTypeRedis  = "redis"
+)
+
+// NewCacheStore creates the store of the cached data, which is shared by the replicas if it is of redis,
+// or kept in the memory of the process otherwise
+func NewCacheStore(cfg config.APICache, defaultExpiration time.Duration) (persist.CacheStore, error) {
+	switch cfg.Type {
+	case "", CacheTypeMemory:
+		return persist.NewInMemoryStore(defaultExpiration), nil
+	case CacheTypeRedis:
+		cli := redis.NewClient(&redis.Options{
+			Addr:     cfg.Address,
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		})
+		return persist.NewRedisStore(cli), nil
+	default:
+		return nil, errors.Errorf("unsupported cache type (%s)", cfg.Type)
+	}
+}
+
+// AddCacheValue sets the value only if the key is absent or expired, and returns false if the key already exists.
+// It is atomic for the stores of memory and redis, and falls back to a get followed by a set for the others
+func AddCacheValue(store persist.CacheStore, key string, value interface{}, expire time.Duration) (bool, error) {
+	switch s := store.(type) {
+	case *persist.InMemoryStore:
+		if err := s.Add(key, value, expire); err == gocache.ErrKeyExists {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		return true, nil
+	case *persist.RedisStore:
+		payload, err := persist.Serialize(value)
+		if err != nil {
+			return false, err
+		}
+		return s.RedisClient.SetNX(context.TODO(), key, payload, expire).Result()
+	default:
+		var v interface{}
+		if err := store.Get(key, &v); err == nil {
+			return false, nil
+		} else if err != persist.ErrCacheMiss {
+			return false, err
+		}
+		return true, store.Set(key, value, expire)
+	}
+}
+
+// KeepCacheValue sets the value never expiring, which is kept until it is set again or the store is flushed
+func KeepCacheValue(store persist.CacheStore, key string, value interface{}) error {
+	if _, ok := store.(*persist.InMemoryStore); ok {
+		// the values set with -1 never expire in the memory
+		return store.Set(key, value, -1)
+	}
+	// the values set with no expiration never expire in redis
+	return store.Set(key, value, 0)
+}
+
+// boundedMemoryStore the store kept in the memory of the process with a bound of the number of the values,
+// the expired values are dropped once the bound is reached, or the earliest expiring one if none is expired
+type boundedMemoryStore struct {
+	max     int
+	entries map[string]boundedMemoryEntry
+	lock    sync.Mutex
+}
+
+type boundedMemoryEntry struct {
+	value   interface{}
+	expired time.Time
+}
+
+// NewBoundedMemoryStore creates the store kept in the memory of the process, which keeps max values at most
+func NewBoundedMemoryStore(max int) persist.CacheStore {
+	return &boundedMemoryStore{max: max, entries: map[string]boundedMemoryEntry{}}
+}
+
+func (s *boundedMemoryStore) Get(key string, value interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return persist.ErrCacheMiss
+	}
+	if !time.Now().Before(e.expired) {
+		delete(s.entries, key)
+		return persist.ErrCacheMiss
+	}
+	v := reflect.ValueOf(value)
+	if v.Type().Kind() == reflect.Ptr && v.Elem().CanSet() {
+		v.Elem().Set(reflect.ValueOf(e.value))
+		return nil
+	}
+	return persist.ErrNotStored
+}
+
+// Set sets the value expiring after expire, which should be positive
+func (s *boundedMemoryStore) Set(key string, value interface{}, expire time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := time.Now()
+	if _, ok := s.entries[key]; !ok && len(s.entries) >= s.max {
+		s.evict(now)
+	}
+	s.entries[key] = boundedMemoryEntry{value: value, expired: now.Add(expire)}
+	return nil
+}
+
+func (s *boundedMemoryStore) Delete(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.entries[key]; !ok {
+		return persist.ErrCacheMiss
+	}
+	delete(s.entries, key)
+	return nil
+}
+
+// evict drops the expired values, or the earliest expiring one if none is expired
+func (s *boundedMemoryStore) evict(now time.Time) {
+	earliest, first := "", time.Time{}
+	for k, e := range s.entries {
+		if !now.Before(e.expired) {
+			delete(s.entries, k)
+			continue
+		}
+		if earliest == "" || e.expired.Before(first) {
+			earliest, first = k, e.expired
+		}
+	}
+	if len(s.entries) >= s.max && earliest != "" {
+		delete(s.entries, earliest)
+	}
+}
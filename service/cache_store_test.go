@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddCacheValue(t *testing.T) {
+	store := persist.NewInMemoryStore(time.Minute)
+	added, err := AddCacheValue(store, "k", "v1", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, added)
+	added, err = AddCacheValue(store, "k", "v2", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, added)
+	var v string
+	assert.NoError(t, store.Get("k", &v))
+	assert.Equal(t, "v1", v)
+
+	assert.NoError(t, store.Delete("k"))
+	added, err = AddCacheValue(store, "k", "v2", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, added)
+}
+
+func TestBoundedMemoryStore(t *testing.T) {
+	store := NewBoundedMemoryStore(2)
+	assert.NoError(t, store.Set("a", "va", time.Minute))
+	assert.NoError(t, store.Set("b", "vb", time.Hour))
+	// the earliest expiring one is evicted
+	assert.NoError(t, store.Set("c", "vc", time.Hour))
+	var v string
+	assert.Equal(t, persist.ErrCacheMiss, store.Get("a", &v))
+	assert.NoError(t, store.Get("b", &v))
+	assert.Equal(t, "vb", v)
+	// the existing one is updated without eviction
+	assert.NoError(t, store.Set("c", "vc2", time.Hour))
+	assert.NoError(t, store.Get("b", &v))
+	assert.NoError(t, store.Get("c", &v))
+	assert.Equal(t, "vc2", v)
+
+	// the expired ones are dropped first
+	assert.NoError(t, store.Set("b", "vb", time.Millisecond))
+	time.Sleep(2 * time.Millisecond)
+	assert.NoError(t, store.Set("d", "vd", time.Minute))
+	assert.Equal(t, persist.ErrCacheMiss, store.Get("b", &v))
+	assert.NoError(t, store.Get("c", &v))
+	assert.NoError(t, store.Get("d", &v))
+
+	assert.NoError(t, store.Delete("c"))
+	assert.Equal(t, persist.ErrCacheMiss, store.Get("c", &v))
+	assert.Equal(t, persist.ErrCacheMiss, store.Delete("c"))
+}
@@ -22,10 +22,15 @@ type ConfigService interface {
 	Update(tx interface{}, namespace string, config *specV1.Configuration) (*specV1.Configuration, error)
 	Upsert(tx interface{}, namespace string, config *specV1.Configuration) (*specV1.Configuration, error)
 	Delete(tx interface{}, namespace, name string) error
+	// GetVersion gets the current or a kept version of the config
+	GetVersion(namespace, name, version string) (*specV1.Configuration, error)
+	// ListVersions lists the kept versions of the config without the data, the latest first
+	ListVersions(namespace, name string) (*models.ResourceVersionList, error)
 }
 
 type configService struct {
-	config plugin.Configuration
+	config   plugin.Configuration
+	versions *resourceVersions
 }
 
 // NewConfigService NewConfigService
@@ -34,8 +39,13 @@ func NewConfigService(config *config.CloudConfig) (ConfigService, error) {
 	if err != nil {
 		return nil, err
 	}
+	versions, err := newResourceVersions(config)
+	if err != nil {
+		return nil, err
+	}
 	return &configService{
-		config: cfg.(plugin.Configuration),
+		config:   cfg.(plugin.Configuration),
+		versions: versions,
 	}, nil
 }
 
@@ -46,29 +56,57 @@ func (s *configService) Get(tx interface{}, namespace, name, version string) (*s
 		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "config"),
 			common.Field("name", name))
 	}
+	if err == nil && res != nil {
+		if err = guardNamespace(namespace, common.Config, name, res.Namespace); err != nil {
+			return nil, err
+		}
+	}
 	return res, err
 }
 
 // List get list config
 func (s *configService) List(namespace string, listOptions *models.ListOptions) (*models.ConfigurationList, error) {
-	return s.config.ListConfig(namespace, listOptions)
+	res, err := s.config.ListConfig(namespace, listOptions)
+	if err != nil || res == nil {
+		return res, err
+	}
+	total := len(res.Items)
+	res.Items = guardNamespaceList(namespace, common.Config, res.Items, func(c *specV1.Configuration) (string, string) {
+		return c.Namespace, c.Name
+	})
+	res.Total -= total - len(res.Items)
+	return res, nil
 }
 
 // Create Create a config
 func (s *configService) Create(tx interface{}, namespace string, config *specV1.Configuration) (*specV1.Configuration, error) {
-	return s.config.CreateConfig(tx, namespace, config)
+	res, err := s.config.CreateConfig(tx, namespace, config)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.versions.record(tx, models.ResourceVersionConfig, namespace, res.Name, res.Version, res); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 // Update update a config
 func (s *configService) Update(tx interface{}, namespace string, config *specV1.Configuration) (*specV1.Configuration, error) {
-	return s.config.UpdateConfig(tx, namespace, config)
+	res, err := s.config.UpdateConfig(tx, namespace, config)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.versions.record(tx, models.ResourceVersionConfig, namespace, res.Name, res.Version, res); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 // Upsert update a config or create a config if not exist
 func (s *configService) Upsert(tx interface{}, namespace string, config *specV1.Configuration) (*specV1.Configuration, error) {
 	res, err := s.config.GetConfig(tx, namespace, config.Name, "")
 	if err != nil {
-		return s.config.CreateConfig(tx, namespace, config)
+		return s.Create(tx, namespace, config)
 	}
 
 	if models.EqualConfig(res, config) {
@@ -77,10 +115,58 @@ func (s *configService) Upsert(tx interface{}, namespace string, config *specV1.
 
 	config.Version = res.Version
 	config.UpdateTimestamp = time.Now()
-	return s.config.UpdateConfig(tx, namespace, config)
+	return s.Update(tx, namespace, config)
 }
 
 // Delete Delete a config
 func (s *configService) Delete(tx interface{}, namespace, name string) error {
-	return s.config.DeleteConfig(tx, namespace, name)
+	if err := s.config.DeleteConfig(tx, namespace, name); err != nil {
+		return err
+	}
+	return s.versions.clear(tx, models.ResourceVersionConfig, namespace, name)
+}
+
+// GetVersion get the current or a kept version of a config
+func (s *configService) GetVersion(namespace, name, version string) (*specV1.Configuration, error) {
+	res, err := s.Get(nil, namespace, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if res.Version == version {
+		return res, nil
+	}
+	his := &specV1.Configuration{}
+	if err = s.versions.get(models.ResourceVersionConfig, namespace, name, version, his); err != nil {
+		return nil, err
+	}
+	return his, nil
+}
+
+// ListVersions list the kept versions of a config
+func (s *configService) ListVersions(namespace, name string) (*models.ResourceVersionList, error) {
+	res, err := s.Get(nil, namespace, name, "")
+	if err != nil {
+		return nil, err
+	}
+	return s.versions.list(models.ResourceVersionConfig, &models.ResourceVersion{
+		Namespace:  namespace,
+		Kind:       models.ResourceVersionConfig,
+		Name:       name,
+		Version:    res.Version,
+		CreateTime: res.UpdateTimestamp,
+	})
+}
+
+// IsConfigPinned returns whether the app pins the version of the config referenced by its volumes
+func IsConfigPinned(app *specV1.Application, name string) bool {
+	pinned, ok := app.Labels[common.LabelPinnedConfigs]
+	if !ok {
+		return false
+	}
+	for _, n := range strings.Split(pinned, "_") {
+		if n == name {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// IsConfigTemplate returns whether the kv items of the config are templates rendered for each node
+func IsConfigTemplate(cfg *specV1.Configuration) bool {
+	_, ok := cfg.Labels[common.LabelConfigTemplate]
+	return ok
+}
+
+// CheckConfigTemplate checks the syntax of the kv items of the config template
+func CheckConfigTemplate(cfg *specV1.Configuration) error {
+	for k, v := range cfg.Data {
+		if strings.HasPrefix(k, common.ConfigObjectPrefix) {
+			continue
+		}
+		if _, err := newConfigTemplate(k, v); err != nil {
+			return fmt.Errorf("the template of the item (%s) is invalid: %s", k, err.Error())
+		}
+	}
+	return nil
+}
+
+// RenderConfigTemplate renders the kv items of the config template with the variables of the node, such as
+// {{ .node.name }}, {{ .node.labels.x }} and {{ .node.properties.x }}, the desired value of a property is preferred
+// to the reported one, and the variables missing are rendered as empty
+func RenderConfigTemplate(cfg *specV1.Configuration, node *specV1.Node) error {
+	vars := map[string]interface{}{
+		"node": map[string]interface{}{
+			"name":       node.Name,
+			"namespace":  node.Namespace,
+			"labels":     toStringMap(node.Labels),
+			"properties": getNodePropertyValues(node),
+		},
+	}
+	for k, v := range cfg.Data {
+		if strings.HasPrefix(k, common.ConfigObjectPrefix) {
+			continue
+		}
+		tpl, err := newConfigTemplate(k, v)
+		if err != nil {
+			return err
+		}
+		buf := new(bytes.Buffer)
+		if err = tpl.Execute(buf, vars); err != nil {
+			return err
+		}
+		cfg.Data[k] = buf.String()
+	}
+	return nil
+}
+
+func newConfigTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=zero").Parse(text)
+}
+
+func getNodePropertyValues(node *specV1.Node) map[string]string {
+	res := map[string]string{}
+	for _, props := range []interface{}{node.Report[common.NodeProps], node.Desire[common.NodeProps]} {
+		m, ok := props.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for k, v := range m {
+			if s, ok := v.(string); ok {
+				res[k] = s
+			} else if data, err := json.Marshal(v); err == nil {
+				res[k] = string(data)
+			}
+		}
+	}
+	return res
+}
+
+func toStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
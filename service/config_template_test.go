@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestConfigTemplate(t *testing.T) {
+	cfg := &specV1.Configuration{
+		Name:   "cfg",
+		Labels: map[string]string{common.LabelConfigTemplate: "true"},
+		Data: map[string]string{
+			"conf.yml":                      "name: {{ .node.name }}\nzone: {{ .node.labels.zone }}\nport: {{ .node.properties.port }}\nmode: {{ .node.properties.mode }}\nmissing: '{{ .node.properties.x }}'",
+			common.ConfigObjectPrefix + "o": `{"url":"{{ not a template"}`,
+		},
+	}
+	assert.True(t, IsConfigTemplate(cfg))
+	assert.False(t, IsConfigTemplate(&specV1.Configuration{}))
+	assert.NoError(t, CheckConfigTemplate(cfg))
+
+	node := &specV1.Node{
+		Name:      "node01",
+		Namespace: "default",
+		Labels:    map[string]string{"zone": "east"},
+		Report:    specV1.Report{common.NodeProps: map[string]interface{}{"port": float64(8080), "mode": "debug"}},
+		Desire:    specV1.Desire{common.NodeProps: map[string]interface{}{"mode": "release"}},
+	}
+	assert.NoError(t, RenderConfigTemplate(cfg, node))
+	assert.Equal(t, "name: node01\nzone: east\nport: 8080\nmode: release\nmissing: ''", cfg.Data["conf.yml"])
+	assert.Equal(t, `{"url":"{{ not a template"}`, cfg.Data[common.ConfigObjectPrefix+"o"])
+
+	// the variables missing are rendered as empty
+	cfg.Data = map[string]string{"a": "{{ .node.labels.zone }}-{{ .node.properties.port }}"}
+	assert.NoError(t, RenderConfigTemplate(cfg, &specV1.Node{Name: "node02"}))
+	assert.Equal(t, "-", cfg.Data["a"])
+
+	cfg.Data = map[string]string{"a": "{{ .node.name "}
+	err := CheckConfigTemplate(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the template of the item (a) is invalid")
+}
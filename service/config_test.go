@@ -139,3 +139,74 @@ func TestDefaultConfigService_Delete(t *testing.T) {
 	err = cs.Delete(nil, namespace, name)
 	assert.NoError(t, err)
 }
+
+func TestDefaultConfigService_Versions(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	mockObject.conf.ResourceVersion.Retention = 2
+
+	namespace := "default"
+	name := "cfg"
+	cs, err := NewConfigService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mConf := &specV1.Configuration{Name: name, Version: "2", Data: map[string]string{"a": "2"}}
+	mockObject.configuration.EXPECT().UpdateConfig(nil, namespace, mConf).Return(mConf, nil).Times(1)
+	mockObject.appHis.EXPECT().CreateResourceVersion(nil, &models.ResourceVersion{
+		Namespace: namespace, Kind: models.ResourceVersionConfig, Name: name, Version: "2",
+		Content: `{"name":"cfg","data":{"a":"2"},"createTime":"0001-01-01T00:00:00Z","updateTime":"0001-01-01T00:00:00Z","version":"2"}`,
+	}).Return(nil).Times(1)
+	mockObject.appHis.EXPECT().DeleteResourceVersion(nil, namespace, models.ResourceVersionConfig, name, 2).Return(nil).Times(1)
+	res, err := cs.Update(nil, namespace, mConf)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", res.Version)
+
+	mockObject.configuration.EXPECT().GetConfig(nil, namespace, name, "").Return(mConf, nil).AnyTimes()
+	res, err = cs.GetVersion(namespace, name, "2")
+	assert.NoError(t, err)
+	assert.Equal(t, mConf, res)
+
+	mockObject.appHis.EXPECT().GetResourceVersion(nil, namespace, models.ResourceVersionConfig, name, "1").
+		Return(&models.ResourceVersion{Version: "1", Content: `{"name":"cfg","version":"1","data":{"a":"1"}}`}, nil).Times(1)
+	res, err = cs.GetVersion(namespace, name, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", res.Version)
+	assert.Equal(t, "1", res.Data["a"])
+
+	mockObject.appHis.EXPECT().GetResourceVersion(nil, namespace, models.ResourceVersionConfig, name, "0").
+		Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	_, err = cs.GetVersion(namespace, name, "0")
+	assert.Error(t, err)
+
+	// the current version updated before the versions are kept is still listed
+	mockObject.appHis.EXPECT().ListResourceVersion(nil, namespace, models.ResourceVersionConfig, name).
+		Return([]models.ResourceVersion{{Name: name, Version: "1"}}, nil).Times(1)
+	list, err := cs.ListVersions(namespace, name)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Equal(t, "2", list.Items[0].Version)
+	assert.Equal(t, "1", list.Items[1].Version)
+
+	mockObject.configuration.EXPECT().DeleteConfig(nil, namespace, name).Return(nil).Times(1)
+	mockObject.appHis.EXPECT().DeleteResourceVersion(nil, namespace, models.ResourceVersionConfig, name, 0).Return(nil).Times(1)
+	assert.NoError(t, cs.Delete(nil, namespace, name))
+}
+
+func TestDefaultConfigService_VersionsDisabled(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	namespace := "default"
+	name := "cfg"
+	cs, err := NewConfigService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mConf := &specV1.Configuration{Name: name, Version: "2"}
+	mockObject.configuration.EXPECT().GetConfig(nil, namespace, name, "").Return(mConf, nil).AnyTimes()
+	_, err = cs.GetVersion(namespace, name, "1")
+	assert.Error(t, err)
+	list, err := cs.ListVersions(namespace, name)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "2", list.Items[0].Version)
+}
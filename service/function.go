@@ -1,7 +1,15 @@
 package service
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
 
@@ -19,11 +27,18 @@ type FunctionService interface {
 	ListSources() []models.FunctionSource
 	ListRuntimes() (map[string]string, error)
 	GetFunction(userID, name, version, source string) (*models.Function, error)
+	// Invoke invokes the function with the json payload read from the reader to test it
+	Invoke(userID, name, version, source string, payload io.Reader) (*models.FunctionInvocation, error)
+	// Upload stores the zip archive of the code read from the reader as the version of the function
+	Upload(userID, source string, function *models.Function, archive io.Reader) (*models.Function, error)
 }
 
 type functionService struct {
-	module    ModuleService
-	functions map[string]plugin.Function
+	module        ModuleService
+	functions     map[string]plugin.Function
+	invokeTimeout time.Duration
+	invokeMaxSize int
+	uploadMaxSize int
 }
 
 // NewFunctionService NewFunctionService
@@ -41,8 +56,11 @@ func NewFunctionService(cfg *config.CloudConfig) (FunctionService, error) {
 		functions[v] = cs.(plugin.Function)
 	}
 	return &functionService{
-		module:    sModule,
-		functions: functions,
+		module:        sModule,
+		functions:     functions,
+		invokeTimeout: cfg.Function.InvokeTimeout,
+		invokeMaxSize: cfg.Function.InvokeMaxSize,
+		uploadMaxSize: cfg.Function.UploadMaxSize,
 	}, nil
 }
 
@@ -96,3 +114,94 @@ func (c *functionService) GetFunction(userID, name, version, source string) (*mo
 
 	return functionPlugin.Get(userID, name, version)
 }
+
+func (c *functionService) Invoke(userID, name, version, source string, payload io.Reader) (*models.FunctionInvocation, error) {
+	functionPlugin, ok := c.functions[source]
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) is not supported", source)))
+	}
+	invoker, ok := functionPlugin.(plugin.FunctionInvoker)
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) does not support invoking functions", source)))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(payload, int64(c.invokeMaxSize)+1))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(data) > c.invokeMaxSize {
+		return nil, common.Error(common.ErrDataTooLarge, common.Field("name", name),
+			common.Field("size", len(data)), common.Field("max", c.invokeMaxSize))
+	}
+	if !json.Valid(data) {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the payload should be json"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.invokeTimeout)
+	defer cancel()
+	type result struct {
+		res *models.FunctionInvocation
+		err error
+	}
+	// the invocation is abandoned if the plugin does not return in time
+	done := make(chan result, 1)
+	go func() {
+		res, err := invoker.Invoke(ctx, userID, name, version, data)
+		done <- result{res: res, err: err}
+	}()
+	select {
+	case r := <-done:
+		return r.res, r.err
+	case <-ctx.Done():
+		return nil, common.Error(common.ErrGatewayTimeout, common.Field("type", "function"),
+			common.Field("name", name), common.Field("timeout", c.invokeTimeout))
+	}
+}
+
+func (c *functionService) Upload(userID, source string, function *models.Function, archive io.Reader) (*models.Function, error) {
+	functionPlugin, ok := c.functions[source]
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) is not supported", source)))
+	}
+	uploader, ok := functionPlugin.(plugin.FunctionUploader)
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) does not support uploading functions", source)))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(archive, int64(c.uploadMaxSize)+1))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(data) > c.uploadMaxSize {
+		return nil, common.Error(common.ErrDataTooLarge, common.Field("name", function.Name),
+			common.Field("size", len(data)), common.Field("max", c.uploadMaxSize))
+	}
+	if err = checkFunctionArchive(data, function.Handler); err != nil {
+		return nil, err
+	}
+	return uploader.Upload(userID, function, data)
+}
+
+// checkFunctionArchive checks the archive is a zip containing the entrypoint of the handler, which is the module
+// before the last dot of the handler with any extension, e.g. index.py or index.js of index.handler
+func checkFunctionArchive(data []byte, handler string) error {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "the archive should be a zip"))
+	}
+	entry := handler
+	if i := strings.LastIndex(handler, "."); i > 0 {
+		entry = handler[:i]
+	}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean(f.Name), "/")
+		if strings.TrimSuffix(name, path.Ext(name)) == entry {
+			return nil
+		}
+	}
+	return common.Error(common.ErrRequestParamInvalid,
+		common.Field("error", fmt.Sprintf("the entrypoint (%s) of the handler (%s) is not found in the archive", entry, handler)))
+}
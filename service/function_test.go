@@ -1,16 +1,23 @@
 package service
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	errors2 "github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
-
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
 func TestDefaultFunctionService_List(t *testing.T) {
@@ -167,3 +174,124 @@ func TestDefaultFunctionService_GetFunction(t *testing.T) {
 	assert.Error(t, err2)
 	assert.Equal(t, err2.Error(), "err")
 }
+
+type mockFunctionInvoker struct {
+	*mockPlugin.MockFunction
+	*mockPlugin.MockFunctionInvoker
+}
+
+func TestDefaultFunctionService_Invoke(t *testing.T) {
+	mock := gomock.NewController(t)
+	defer mock.Finish()
+
+	invoker := &mockFunctionInvoker{mockPlugin.NewMockFunction(mock), mockPlugin.NewMockFunctionInvoker(mock)}
+	fs := &functionService{
+		functions: map[string]plugin.Function{
+			"invoker": invoker,
+			"other":   mockPlugin.NewMockFunction(mock),
+		},
+		invokeTimeout: time.Millisecond * 50,
+		invokeMaxSize: 16,
+	}
+
+	res := &models.FunctionInvocation{Result: `{"b":1}`, Logs: "START\nEND"}
+	invoker.MockFunctionInvoker.EXPECT().Invoke(gomock.Any(), "default", "f", "1", []byte(`{"a":1}`)).Return(res, nil).Times(1)
+	out, err := fs.Invoke("default", "f", "1", "invoker", strings.NewReader(`{"a":1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, res, out)
+
+	invoker.MockFunctionInvoker.EXPECT().Invoke(gomock.Any(), "default", "f", "1", gomock.Any()).Return(nil, errors.New("err")).Times(1)
+	_, err = fs.Invoke("default", "f", "1", "invoker", strings.NewReader(`{}`))
+	assert.EqualError(t, err, "err")
+
+	// timeout
+	invoker.MockFunctionInvoker.EXPECT().Invoke(gomock.Any(), "default", "f", "1", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _, _, _ string, _ []byte) (*models.FunctionInvocation, error) {
+			time.Sleep(time.Millisecond * 200)
+			return res, nil
+		}).Times(1)
+	_, err = fs.Invoke("default", "f", "1", "invoker", strings.NewReader(`{}`))
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrGatewayTimeout, err.(errors2.Coder).Code())
+	assert.Contains(t, err.Error(), "The function (f) did not respond within 50ms.")
+
+	// invalid payload
+	_, err = fs.Invoke("default", "f", "1", "invoker", strings.NewReader(`{"a":"0123456789abcdef"}`))
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrDataTooLarge, err.(errors2.Coder).Code())
+	_, err = fs.Invoke("default", "f", "1", "invoker", strings.NewReader(`{"a"`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the payload should be json")
+
+	// unsupported source
+	_, err = fs.Invoke("default", "f", "1", "other", strings.NewReader(`{}`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support invoking functions")
+	_, err = fs.Invoke("default", "f", "1", "unknown", strings.NewReader(`{}`))
+	assert.Error(t, err)
+}
+
+type mockFunctionUploader struct {
+	*mockPlugin.MockFunction
+	*mockPlugin.MockFunctionUploader
+}
+
+func TestDefaultFunctionService_Upload(t *testing.T) {
+	mock := gomock.NewController(t)
+	defer mock.Finish()
+
+	uploader := &mockFunctionUploader{mockPlugin.NewMockFunction(mock), mockPlugin.NewMockFunctionUploader(mock)}
+	fs := &functionService{
+		functions: map[string]plugin.Function{
+			"local": uploader,
+			"other": mockPlugin.NewMockFunction(mock),
+		},
+		uploadMaxSize: 1024,
+	}
+	newArchive := func(names ...string) []byte {
+		buf := new(bytes.Buffer)
+		w := zip.NewWriter(buf)
+		for _, name := range names {
+			f, err := w.Create(name)
+			assert.NoError(t, err)
+			if strings.HasSuffix(name, "/") {
+				continue
+			}
+			_, err = f.Write([]byte("code"))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	function := &models.Function{Name: "f", Version: "1", Runtime: "python3", Handler: "src/index.handler"}
+	archive := newArchive("src/", "./src/index.py", "requirements.txt")
+	res := &models.Function{Name: "f", Version: "1", Runtime: "python3", Handler: "src/index.handler",
+		Code: models.FunctionCode{Size: int32(len(archive))}}
+	uploader.MockFunctionUploader.EXPECT().Upload("default", function, archive).Return(res, nil).Times(1)
+	out, err := fs.Upload("default", "local", function, bytes.NewReader(archive))
+	assert.NoError(t, err)
+	assert.Equal(t, res, out)
+
+	// the entrypoint is not found
+	_, err = fs.Upload("default", "local", function, bytes.NewReader(newArchive("index.py")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the entrypoint (src/index) of the handler (src/index.handler) is not found")
+
+	// not a zip
+	_, err = fs.Upload("default", "local", function, strings.NewReader("code"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the archive should be a zip")
+
+	// too large
+	_, err = fs.Upload("default", "local", function, bytes.NewReader(make([]byte, 1025)))
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrDataTooLarge, err.(errors2.Coder).Code())
+
+	// unsupported source
+	_, err = fs.Upload("default", "other", function, bytes.NewReader(archive))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support uploading functions")
+	_, err = fs.Upload("default", "unknown", function, bytes.NewReader(archive))
+	assert.Error(t, err)
+}
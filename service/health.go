@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/health.go -package=service github.com/baetyl/baetyl-cloud/v2/service HealthService
+
+// HealthService checks the dependencies of the server
+type HealthService interface {
+	// AddCheck adds a dependency to check, the server is unhealthy if a critical one is down
+	AddCheck(name string, critical bool, check func(ctx context.Context) error)
+	Check(ctx context.Context) *models.Health
+}
+
+const DefaultHealthCheckTimeout = time.Second * 3
+
+type healthCheck struct {
+	name     string
+	plugin   string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+type HealthServiceImpl struct {
+	timeout time.Duration
+	checks  []healthCheck
+}
+
+// NewHealthService checks the storage and auth plugins as critical dependencies,
+// the object, function and cache plugins as non-critical ones
+func NewHealthService(config *config.CloudConfig) (HealthService, error) {
+	h := &HealthServiceImpl{timeout: config.Health.Timeout}
+	if h.timeout <= 0 {
+		h.timeout = DefaultHealthCheckTimeout
+	}
+	h.addPluginCheck("storage", config.Plugin.Resource, true)
+	h.addPluginCheck("auth", config.Plugin.Auth, true)
+	for _, name := range config.Plugin.Objects {
+		h.addPluginCheck("object", name, false)
+	}
+	for _, name := range config.Plugin.Functions {
+		h.addPluginCheck("function", name, false)
+	}
+	h.addPluginCheck("cache", config.Plugin.Cache, false)
+	return h, nil
+}
+
+func (h *HealthServiceImpl) addPluginCheck(name, pluginName string, critical bool) {
+	h.checks = append(h.checks, healthCheck{
+		name:     name,
+		plugin:   pluginName,
+		critical: critical,
+		check: func(ctx context.Context) error {
+			p, err := plugin.GetPlugin(pluginName)
+			if err != nil {
+				return err
+			}
+			// the plugins unable to check their backend are up once created
+			if checker, ok := p.(plugin.HealthChecker); ok {
+				return checker.Health(ctx)
+			}
+			return nil
+		},
+	})
+}
+
+func (h *HealthServiceImpl) AddCheck(name string, critical bool, check func(ctx context.Context) error) {
+	h.checks = append(h.checks, healthCheck{name: name, critical: critical, check: check})
+}
+
+// Check runs all checks concurrently, each one is down if it doesn't finish in time
+func (h *HealthServiceImpl) Check(ctx context.Context) *models.Health {
+	res := &models.Health{Status: models.HealthStatusHealthy, Checks: make([]models.HealthCheck, len(h.checks))}
+	done := make(chan struct{}, len(h.checks))
+	for i := range h.checks {
+		go func(i int) {
+			res.Checks[i] = h.runCheck(ctx, h.checks[i])
+			done <- struct{}{}
+		}(i)
+	}
+	for range h.checks {
+		<-done
+	}
+
+	for _, c := range res.Checks {
+		if c.Status == models.HealthCheckUp {
+			continue
+		}
+		if c.Critical {
+			res.Status = models.HealthStatusUnhealthy
+		} else if res.Status == models.HealthStatusHealthy {
+			res.Status = models.HealthStatusDegraded
+		}
+	}
+	return res
+}
+
+func (h *HealthServiceImpl) runCheck(ctx context.Context, c healthCheck) models.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = errors.Errorf("health check timed out after %s", h.timeout)
+	}
+
+	res := models.HealthCheck{
+		Name:     c.name,
+		Plugin:   c.plugin,
+		Critical: c.critical,
+		Status:   models.HealthCheckUp,
+		Latency:  time.Since(start).String(),
+	}
+	if err != nil {
+		res.Status = models.HealthCheckDown
+		res.Error = err.Error()
+	}
+	return res
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+type mockHealthPlugin struct {
+	err error
+}
+
+func (p *mockHealthPlugin) Health(_ context.Context) error {
+	return p.err
+}
+
+func (p *mockHealthPlugin) Close() error {
+	return nil
+}
+
+func TestHealthService_Check(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	conf := *mockObject.conf
+	conf.Health.Timeout = time.Millisecond * 100
+
+	hs, err := NewHealthService(&conf)
+	assert.NoError(t, err)
+	res := hs.Check(context.Background())
+	assert.Equal(t, models.HealthStatusHealthy, res.Status)
+	assert.Len(t, res.Checks, 3+len(conf.Plugin.Objects)+len(conf.Plugin.Functions))
+	assert.Equal(t, models.HealthCheck{Name: "storage", Plugin: conf.Plugin.Resource, Critical: true,
+		Status: models.HealthCheckUp, Latency: res.Checks[0].Latency}, res.Checks[0])
+
+	// the cache plugin checks its backend
+	conf.Plugin.Cache = common.RandString(9)
+	cache := &mockHealthPlugin{err: fmt.Errorf("connection refused")}
+	plugin.RegisterFactory(conf.Plugin.Cache, func() (plugin.Plugin, error) {
+		return cache, nil
+	})
+	hs, err = NewHealthService(&conf)
+	assert.NoError(t, err)
+	res = hs.Check(context.Background())
+	assert.Equal(t, models.HealthStatusDegraded, res.Status)
+	last := res.Checks[len(res.Checks)-1]
+	assert.Equal(t, "cache", last.Name)
+	assert.Equal(t, models.HealthCheckDown, last.Status)
+	assert.Equal(t, "connection refused", last.Error)
+
+	cache.err = nil
+	res = hs.Check(context.Background())
+	assert.Equal(t, models.HealthStatusHealthy, res.Status)
+
+	// slow checks time out
+	hs.AddCheck("slow", false, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	res = hs.Check(context.Background())
+	assert.Equal(t, models.HealthStatusDegraded, res.Status)
+	assert.Contains(t, res.Checks[len(res.Checks)-1].Error, "timed out")
+
+	// critical plugins failing to be created
+	conf.Plugin.Auth = common.RandString(9)
+	hs, err = NewHealthService(&conf)
+	assert.NoError(t, err)
+	res = hs.Check(context.Background())
+	assert.Equal(t, models.HealthStatusUnhealthy, res.Status)
+	assert.Equal(t, models.HealthCheckDown, res.Checks[1].Status)
+}
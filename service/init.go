@@ -22,6 +22,18 @@ const (
 	InfoName      = "n"
 	InfoNamespace = "ns"
 	InfoExpiry    = "e"
+	InfoOneTime   = "o"
+	// InfoNodeToken the id of the node token, which is checked against the latest one of the node
+	InfoNodeToken = "t"
+)
+
+const (
+	// the attributes of a node keeping the state of its latest one-time init token
+	AttrInitTokenID       = "BaetylInitTokenID"
+	AttrInitTokenExpiry   = "BaetylInitTokenExpiry"
+	AttrInitTokenConsumed = "BaetylInitTokenConsumed"
+
+	initTokenIDLength = 16
 )
 
 const (
@@ -35,6 +47,8 @@ const (
 	TemplateBaetylInitCommand  = "baetyl-init-command"
 	TemplateInitCommandWget    = "baetyl-init-command-wget"
 	TemplateInitCommandWindows = "baetyl-init-command-windows"
+	// TemplateInitManifest the kubernetes manifest of the init, which is applied directly instead of by an init command
+	TemplateInitManifest = "baetyl-init-manifest"
 
 	NamePopulateExtParams = "populateExtParams"
 
@@ -54,6 +68,7 @@ type PopulateExtParams func(ns string, node *specV1.Node, app *specV1.Applicatio
 // InitService
 type InitService interface {
 	GetResource(ns, nodeName, resourceName string, params map[string]interface{}) (interface{}, error)
+	GetInitTokenStatus(ns, nodeName string) (*models.NodeInitTokenStatus, error)
 }
 
 type InitServiceImpl struct {
@@ -118,6 +133,7 @@ func NewInitService(config *config.CloudConfig) (InitService, error) {
 		log:                log.L().With(log.Any("service", "init")),
 	}
 	initService.ResourceMapFunc[templateInitDeploymentYaml] = initService.getInitDeploymentYaml
+	initService.ResourceMapFunc[TemplateInitManifest] = initService.getInitDeploymentYaml
 	initService.ResourceMapFunc[TemplateBaetylInitCommand] = initService.GetInitCommand
 	initService.ResourceMapFunc[TemplateCoreConfYaml] = initService.getCoreConfig
 	initService.ResourceMapFunc[templateBaetylInstallShell] = initService.getInstallShell
@@ -133,6 +149,11 @@ func (s *InitServiceImpl) GetResource(ns, nodeName, resourceName string, params
 		if params == nil {
 			params = map[string]interface{}{}
 		}
+		if tokenID, _ := params["InitTokenID"].(string); tokenID != "" {
+			if err := s.checkInitToken(ns, nodeName, tokenID); err != nil {
+				return nil, err
+			}
+		}
 		return handler(ns, nodeName, params)
 	}
 	return nil, common.Error(
@@ -186,6 +207,10 @@ func (s *InitServiceImpl) getInitDeploymentYaml(ns, nodeName string, params map[
 	params["QPSStats"] = node.NodeMode == context.RunModeKube
 	params["AgentPort"] = common.DefaultAgentPort
 	params["NodeMode"] = node.NodeMode
+	// the node token issued along with the init command, which is kept with the node certificate
+	nodeToken, _ := params["NodeToken"].(string)
+	params["NodeToken"] = nodeToken
+	params["NodeTokenData"] = base64.StdEncoding.EncodeToString([]byte(nodeToken))
 
 	registryAuth, err := s.GetRegistryAuth()
 	if err != nil {
@@ -199,7 +224,15 @@ func (s *InitServiceImpl) getInitDeploymentYaml(ns, nodeName string, params map[
 		}
 	}
 
-	return s.TemplateService.ParseTemplate(templateInitDeploymentYaml, params)
+	data, err := s.TemplateService.ParseTemplate(templateInitDeploymentYaml, params)
+	if err != nil {
+		return nil, err
+	}
+	// the one-time init token is consumed once the node certificate is delivered, whatever the resource name or mode
+	if err = s.consumeInitToken(ns, nodeName, params); err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 // GetRegistryAuth add system registry auth if property exist
@@ -254,16 +287,37 @@ func (s *InitServiceImpl) getInstallShell(ns, nodeName string, params map[string
 	return data, nil
 }
 
+// GetInitCommand generates the init command with a token expiring after the ttl in params, or after CmdExpirationInSeconds by default.
+// The token is invalidated after the node is registered if oneTime is set in params, and only the latest one-time token of a node is valid
 func (s *InitServiceImpl) GetInitCommand(ns, nodeName string, params map[string]interface{}) ([]byte, error) {
+	expiry := time.Now().Add(time.Duration(CmdExpirationInSeconds) * time.Second)
+	if ttl, ok := params["ttl"].(time.Duration); ok && ttl > 0 {
+		expiry = time.Now().Add(ttl)
+	}
 	info := map[string]interface{}{
 		InfoNamespace: ns,
 		InfoName:      nodeName,
-		InfoExpiry:    time.Now().Unix() + CmdExpirationInSeconds,
+		InfoExpiry:    expiry.Unix(),
 	}
-	initCommand, err := s.Property.GetPropertyValue(params["template"].(string))
+	initCommand, err := s.getInitCommandTemplate(params)
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := params["NodeToken"]; !ok {
+		params["NodeToken"] = ""
+	}
+	if oneTime, _ := params["oneTime"].(bool); oneTime {
+		id := common.RandString(initTokenIDLength)
+		_, err = s.NodeService.UpdateNodeAttributes(ns, nodeName, map[string]interface{}{
+			AttrInitTokenID:       id,
+			AttrInitTokenExpiry:   time.Unix(expiry.Unix(), 0).UTC().Format(time.RFC3339),
+			AttrInitTokenConsumed: nil,
+		})
+		if err != nil {
+			return nil, err
+		}
+		info[InfoOneTime] = id
+	}
 	token, err := s.SignService.GenToken(info)
 	if err != nil {
 		return nil, err
@@ -276,6 +330,90 @@ func (s *InitServiceImpl) GetInitCommand(ns, nodeName string, params map[string]
 	return data, nil
 }
 
+// getInitCommandTemplate returns the property of the template in params,
+// or the property of the fallbackTemplate in params if the former is not set, e.g. the template of a platform
+func (s *InitServiceImpl) getInitCommandTemplate(params map[string]interface{}) (string, error) {
+	initCommand, err := s.Property.GetPropertyValue(params["template"].(string))
+	if err == nil {
+		return initCommand, nil
+	}
+	fallback, ok := params["fallbackTemplate"].(string)
+	if e, isCoder := err.(errors.Coder); !ok || !isCoder || e.Code() != common.ErrResourceNotFound {
+		return "", err
+	}
+	return s.Property.GetPropertyValue(fallback)
+}
+
+// GetInitTokenStatus returns the status of the latest one-time init token of the node
+func (s *InitServiceImpl) GetInitTokenStatus(ns, nodeName string) (*models.NodeInitTokenStatus, error) {
+	node, err := s.NodeService.Get(nil, ns, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	status := &models.NodeInitTokenStatus{}
+	if id, _ := node.Attributes[AttrInitTokenID].(string); id == "" {
+		return status, nil
+	}
+	status.OneTime = true
+	if t, ok := parseInitTokenTime(node.Attributes[AttrInitTokenExpiry]); ok {
+		status.ExpireTime = &t
+		status.Expired = t.Before(time.Now())
+	}
+	if t, ok := parseInitTokenTime(node.Attributes[AttrInitTokenConsumed]); ok {
+		status.ConsumeTime = &t
+		status.Consumed = true
+	}
+	return status, nil
+}
+
+// checkInitToken checks whether the one-time init token is the latest one of the node and not consumed yet
+func (s *InitServiceImpl) checkInitToken(ns, nodeName, tokenID string) error {
+	node, err := s.NodeService.Get(nil, ns, nodeName)
+	if err != nil {
+		return err
+	}
+	return s.validInitToken(node, tokenID)
+}
+
+// consumeInitToken marks the one-time init token in params as consumed,
+// which fails if the token has been consumed or replaced, even by a concurrent request
+func (s *InitServiceImpl) consumeInitToken(ns, nodeName string, params map[string]interface{}) error {
+	tokenID, _ := params["InitTokenID"].(string)
+	if tokenID == "" {
+		return nil
+	}
+	_, err := s.NodeService.UpdateNodeAttributesIf(ns, nodeName, func(node *specV1.Node) error {
+		return s.validInitToken(node, tokenID)
+	}, map[string]interface{}{
+		AttrInitTokenConsumed: time.Now().UTC().Format(time.RFC3339),
+	})
+	return err
+}
+
+func (s *InitServiceImpl) validInitToken(node *specV1.Node, tokenID string) error {
+	if id, _ := node.Attributes[AttrInitTokenID].(string); id != tokenID {
+		s.log.Info("one-time init token is replaced", log.Any("namespace", node.Namespace), log.Any("node", node.Name))
+		return common.Error(common.ErrInvalidToken)
+	}
+	if _, ok := parseInitTokenTime(node.Attributes[AttrInitTokenConsumed]); ok {
+		s.log.Info("one-time init token is consumed", log.Any("namespace", node.Namespace), log.Any("node", node.Name))
+		return common.Error(common.ErrInvalidToken)
+	}
+	return nil
+}
+
+func parseInitTokenTime(v interface{}) (time.Time, bool) {
+	str, ok := v.(string)
+	if !ok || str == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
 func (s *InitServiceImpl) GetAppFromDesire(ns, nodeName, moduleName string, isSys bool) (*specV1.Application, error) {
 	shadowDesire, err := s.NodeService.GetDesire(ns, nodeName)
 	if err != nil {
@@ -1,6 +1,10 @@
 package service
 
 import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +16,7 @@ import (
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 func TestInitService_GetResource(t *testing.T) {
@@ -190,6 +195,118 @@ func TestInitService_GenCmd(t *testing.T) {
 	assert.Equal(t, string(res), expect)
 }
 
+// the node token issued along with the init command is passed to the install script and then to the init deployment
+func TestInitService_GenCmdWithNodeToken(t *testing.T) {
+	mocks := InitMockEnvironment(t)
+	defer mocks.Close()
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	sTemplate, err := NewTemplateService(mocks.conf, map[string]interface{}{
+		"GetProperty":    func(in string) string { return "https://1.2.3.4:30003" },
+		"GetModuleImage": func(in string) string { return in },
+	})
+	assert.NoError(t, err)
+	sSign := service.NewMockSignService(mockCtl)
+	sProp := service.NewMockPropertyService(mockCtl)
+	as := InitServiceImpl{
+		SignService:     sSign,
+		TemplateService: sTemplate,
+		Property:        sProp,
+	}
+	sSign.EXPECT().GenToken(gomock.Any()).Return("tokenexpect", nil).AnyTimes()
+
+	data, err := ioutil.ReadFile("../scripts/sql/data.sql")
+	assert.NoError(t, err)
+	for _, name := range []string{TemplateBaetylInitCommand, TemplateInitCommandWget, TemplateInitCommandWindows} {
+		m := regexp.MustCompile(`\('` + name + `', '(.*)'\),`).FindSubmatch(data)
+		assert.Len(t, m, 2, name)
+		cmd := strings.NewReplacer(`\'`, `'`, `\"`, `"`).Replace(string(m[1]))
+		sProp.EXPECT().GetPropertyValue(name).Return(cmd, nil).Times(2)
+
+		res, err := as.GetInitCommand("ns", "name", map[string]interface{}{
+			"template":      name,
+			"mode":          "kube",
+			"InitApplyYaml": "baetyl-init-deployment.yml",
+			"NodeToken":     "baetyl-node-abc",
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, string(res), "token=tokenexpect&mode=kube&initApplyYaml=baetyl-init-deployment.yml&nodeToken=baetyl-node-abc", name)
+
+		res, err = as.GetInitCommand("ns", "name", map[string]interface{}{
+			"template":      name,
+			"mode":          "kube",
+			"InitApplyYaml": "baetyl-init-deployment.yml",
+		})
+		assert.NoError(t, err)
+		assert.NotContains(t, string(res), "nodeToken", name)
+	}
+
+	shellParams := map[string]interface{}{
+		"InitApplyYaml": "baetyl-init-deployment.yml",
+		"Token":         "tokenexpect",
+		"Mode":          "kube",
+		"NodeToken":     "baetyl-node-abc",
+	}
+	res, err := as.getInstallShell("ns", "name", shellParams)
+	assert.NoError(t, err)
+	assert.Contains(t, string(res), `NODE_TOKEN="baetyl-node-abc"`)
+	assert.Contains(t, string(res), `$DEPLOYYML?$QUERY`)
+	res, err = as.getWindowsInstallShell("ns", "name", shellParams)
+	assert.NoError(t, err)
+	assert.Contains(t, string(res), `$NodeToken="baetyl-node-abc"`)
+
+	// the node token is kept with the node certificate
+	deployParams := map[string]interface{}{}
+	for k, v := range params {
+		deployParams[k] = v
+	}
+	deployParams["NodeToken"] = "baetyl-node-abc"
+	deployParams["NodeTokenData"] = "YmFldHlsLW5vZGUtYWJj"
+	res, err = sTemplate.ParseTemplate(templateInitDeploymentYaml, deployParams)
+	assert.NoError(t, err)
+	assert.Contains(t, string(res), "  ca.pem: '---node cert ca---'\n  token: 'YmFldHlsLW5vZGUtYWJj'\n")
+}
+
+func TestInitService_GenCmdOfPlatform(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	sSign := service.NewMockSignService(mockCtl)
+	sTemplate := service.NewMockTemplateService(mockCtl)
+	sProp := service.NewMockPropertyService(mockCtl)
+	as := InitServiceImpl{}
+	as.SignService = sSign
+	as.TemplateService = sTemplate
+	as.Property = sProp
+	template := TemplateBaetylInitCommand + "-linux-arm64"
+	params := map[string]interface{}{
+		"template":         template,
+		"fallbackTemplate": TemplateInitCommandWget,
+	}
+	sSign.EXPECT().GenToken(gomock.Any()).Return("tokenexpect", nil).Times(2)
+
+	// the template of the platform is used if set
+	sProp.EXPECT().GetPropertyValue(template).Return("arm64", nil).Times(1)
+	sTemplate.EXPECT().Execute("setup-command", "arm64", gomock.Any()).Return([]byte("arm64"), nil).Times(1)
+	res, err := as.GetInitCommand("ns", "name", params)
+	assert.NoError(t, err)
+	assert.Equal(t, "arm64", string(res))
+
+	// the fallback template is used if not
+	sProp.EXPECT().GetPropertyValue(template).Return("", common.Error(common.ErrResourceNotFound, common.Field("name", template))).Times(1)
+	sProp.EXPECT().GetPropertyValue(TemplateInitCommandWget).Return("wget", nil).Times(1)
+	sTemplate.EXPECT().Execute("setup-command", "wget", gomock.Any()).Return([]byte("wget"), nil).Times(1)
+	res, err = as.GetInitCommand("ns", "name", params)
+	assert.NoError(t, err)
+	assert.Equal(t, "wget", string(res))
+
+	// other errors are returned
+	sProp.EXPECT().GetPropertyValue(template).Return("", fmt.Errorf("error")).Times(1)
+	_, err = as.GetInitCommand("ns", "name", params)
+	assert.Error(t, err)
+}
+
 func TestInitService_getDesireAppInfo(t *testing.T) {
 	as := InitServiceImpl{}
 	mockCtl := gomock.NewController(t)
@@ -218,3 +335,122 @@ func TestInitService_getDesireAppInfo(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, res, app1)
 }
+
+func TestInitService_OneTimeToken(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	sSign := service.NewMockSignService(mockCtl)
+	sTemplate := service.NewMockTemplateService(mockCtl)
+	sProp := service.NewMockPropertyService(mockCtl)
+	sNode := service.NewMockNodeService(mockCtl)
+	as := InitServiceImpl{
+		SignService:     sSign,
+		TemplateService: sTemplate,
+		Property:        sProp,
+		NodeService:     sNode,
+		ResourceMapFunc: map[string]GetInitResource{},
+		log:             log.L(),
+	}
+	as.ResourceMapFunc[TemplateBaetylInitCommand] = as.GetInitCommand
+	as.ResourceMapFunc[templateBaetylInstallShell] = func(_, _ string, _ map[string]interface{}) ([]byte, error) {
+		return []byte("shell"), nil
+	}
+	// the resources carrying the node certificate consume the token
+	as.ResourceMapFunc[templateInitDeploymentYaml] = func(ns, name string, params map[string]interface{}) ([]byte, error) {
+		if err := as.consumeInitToken(ns, name, params); err != nil {
+			return nil, err
+		}
+		return []byte("init"), nil
+	}
+	as.ResourceMapFunc[TemplateInitManifest] = as.ResourceMapFunc[templateInitDeploymentYaml]
+
+	// generate
+	attrs := map[string]interface{}{}
+	sNode.EXPECT().UpdateNodeAttributes("default", "abc", gomock.Any()).DoAndReturn(func(_, _ string, update map[string]interface{}) (*specV1.Node, error) {
+		for k, v := range update {
+			if v == nil {
+				delete(attrs, k)
+			} else {
+				attrs[k] = v
+			}
+		}
+		return &specV1.Node{Attributes: attrs}, nil
+	}).AnyTimes()
+	sNode.EXPECT().Get(nil, "default", "abc").DoAndReturn(func(_ interface{}, _, _ string) (*specV1.Node, error) {
+		return &specV1.Node{Attributes: attrs}, nil
+	}).AnyTimes()
+	sNode.EXPECT().UpdateNodeAttributesIf("default", "abc", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_, _ string, check func(*specV1.Node) error, update map[string]interface{}) (*specV1.Node, error) {
+			if err := check(&specV1.Node{Attributes: attrs}); err != nil {
+				return nil, err
+			}
+			for k, v := range update {
+				attrs[k] = v
+			}
+			return &specV1.Node{Attributes: attrs}, nil
+		}).AnyTimes()
+
+	var info map[string]interface{}
+	sSign.EXPECT().GenToken(gomock.Any()).DoAndReturn(func(i map[string]interface{}) (string, error) {
+		info = i
+		return "token", nil
+	}).Times(1)
+	sProp.EXPECT().GetPropertyValue(TemplateBaetylInitCommand).Return(TemplateBaetylInitCommand, nil)
+	sTemplate.EXPECT().Execute("setup-command", TemplateBaetylInitCommand, gomock.Any()).Return([]byte("cmd"), nil).Times(1)
+	now := time.Now()
+	_, err := as.GetResource("default", "abc", TemplateBaetylInitCommand, map[string]interface{}{
+		"template": TemplateBaetylInitCommand,
+		"ttl":      10 * time.Minute,
+		"oneTime":  true,
+	})
+	assert.NoError(t, err)
+	id := info[InfoOneTime].(string)
+	assert.Len(t, id, initTokenIDLength)
+	assert.Equal(t, id, attrs[AttrInitTokenID])
+	assert.InDelta(t, now.Add(10*time.Minute).Unix(), info[InfoExpiry], 1)
+
+	status, err := as.GetInitTokenStatus("default", "abc")
+	assert.NoError(t, err)
+	assert.True(t, status.OneTime)
+	assert.False(t, status.Consumed)
+	assert.False(t, status.Expired)
+	assert.NotNil(t, status.ExpireTime)
+
+	// the token is valid until the node certificate is delivered
+	res, err := as.GetResource("default", "abc", templateBaetylInstallShell, map[string]interface{}{"InitTokenID": id})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("shell"), res)
+	res, err = as.GetResource("default", "abc", templateInitDeploymentYaml, map[string]interface{}{"InitTokenID": id})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("init"), res)
+
+	status, err = as.GetInitTokenStatus("default", "abc")
+	assert.NoError(t, err)
+	assert.True(t, status.Consumed)
+	assert.NotNil(t, status.ConsumeTime)
+
+	_, err = as.GetResource("default", "abc", templateBaetylInstallShell, map[string]interface{}{"InitTokenID": id})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The token is invalid")
+	_, err = as.GetResource("default", "abc", TemplateInitManifest, map[string]interface{}{"InitTokenID": id})
+	assert.Error(t, err)
+
+	// the token consumed after the check of the request is not consumed again
+	delete(attrs, AttrInitTokenConsumed)
+	assert.NoError(t, as.checkInitToken("default", "abc", id))
+	assert.NoError(t, as.consumeInitToken("default", "abc", map[string]interface{}{"InitTokenID": id}))
+	err = as.consumeInitToken("default", "abc", map[string]interface{}{"InitTokenID": id})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The token is invalid")
+
+	// the replaced token is invalid
+	_, err = as.GetResource("default", "abc", templateBaetylInstallShell, map[string]interface{}{"InitTokenID": "other"})
+	assert.Error(t, err)
+
+	// no one-time token
+	attrs = map[string]interface{}{}
+	status, err = as.GetInitTokenStatus("default", "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.NodeInitTokenStatus{}, status)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/job.go -package=service github.com/baetyl/baetyl-cloud/v2/service JobService
+
+// JobService keeps the status, progress and result of the jobs running in background
+type JobService interface {
+	// Create creates a pending job of the type in the namespace
+	Create(namespace, typ string) (*models.Job, error)
+	Get(namespace string, id int64) (*models.Job, error)
+	// Start marks the pending job running, returns false if it has been canceled
+	Start(job *models.Job) (bool, error)
+	// Progress updates the progress of the running job, returns false if it has been canceled
+	Progress(job *models.Job, progress int) (bool, error)
+	// Finish records the result or the error of the running job
+	Finish(job *models.Job, result interface{}, err error) error
+	// Cancel cancels the pending or running job, the running job stops at its next step
+	Cancel(namespace string, id int64) (*models.Job, error)
+}
+
+type jobService struct {
+	job plugin.Job
+}
+
+// NewJobService NewJobService
+func NewJobService(config *config.CloudConfig) (JobService, error) {
+	job, err := plugin.GetPlugin(config.Plugin.Job)
+	if err != nil {
+		return nil, err
+	}
+	return &jobService{job: job.(plugin.Job)}, nil
+}
+
+func (s *jobService) Create(namespace, typ string) (*models.Job, error) {
+	return s.job.CreateJob(nil, &models.Job{
+		Namespace: namespace,
+		Type:      typ,
+		Status:    models.JobPending,
+	})
+}
+
+func (s *jobService) Get(namespace string, id int64) (*models.Job, error) {
+	return s.job.GetJob(nil, namespace, id)
+}
+
+func (s *jobService) Start(job *models.Job) (bool, error) {
+	job.Status = models.JobRunning
+	return s.job.UpdateJob(nil, job, models.JobPending)
+}
+
+func (s *jobService) Progress(job *models.Job, progress int) (bool, error) {
+	if progress > 100 {
+		progress = 100
+	}
+	job.Progress = progress
+	return s.job.UpdateJob(nil, job, models.JobRunning)
+}
+
+func (s *jobService) Finish(job *models.Job, result interface{}, err error) error {
+	job.Status, job.Result, job.Message = models.JobSucceeded, result, ""
+	if err != nil {
+		job.Status, job.Message = models.JobFailed, err.Error()
+		if len(job.Message) > 1024 {
+			job.Message = job.Message[:1024]
+		}
+	} else {
+		job.Progress = 100
+	}
+	_, err = s.job.UpdateJob(nil, job, models.JobRunning)
+	return errors.Trace(err)
+}
+
+func (s *jobService) Cancel(namespace string, id int64) (*models.Job, error) {
+	job, err := s.Get(namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	if models.IsJobDone(job.Status) {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the job is already "+job.Status))
+	}
+	oldStatus := job.Status
+	job.Status = models.JobCanceled
+	ok, err := s.job.UpdateJob(nil, job, oldStatus)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// the job has been started or finished meanwhile
+		return s.Cancel(namespace, id)
+	}
+	return s.Get(namespace, id)
+}
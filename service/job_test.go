@@ -0,0 +1,69 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestJobService(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mJob := mockPlugin.NewMockJob(mockCtl)
+	s := &jobService{job: mJob}
+
+	mJob.EXPECT().CreateJob(nil, gomock.Any()).DoAndReturn(func(_ interface{}, job *models.Job) (*models.Job, error) {
+		assert.Equal(t, models.JobPending, job.Status)
+		assert.Equal(t, models.JobNamespaceImport, job.Type)
+		job.ID = 1
+		return job, nil
+	}).Times(1)
+	job, err := s.Create("default", models.JobNamespaceImport)
+	assert.NoError(t, err)
+
+	mJob.EXPECT().UpdateJob(nil, job, models.JobPending).Return(true, nil).Times(1)
+	ok, err := s.Start(job)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, models.JobRunning, job.Status)
+
+	mJob.EXPECT().UpdateJob(nil, job, models.JobRunning).Return(true, nil).Times(1)
+	ok, err = s.Progress(job, 120)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 100, job.Progress)
+
+	mJob.EXPECT().UpdateJob(nil, job, models.JobRunning).Return(true, nil).Times(1)
+	assert.NoError(t, s.Finish(job, nil, fmt.Errorf("import failed")))
+	assert.Equal(t, models.JobFailed, job.Status)
+	assert.Equal(t, "import failed", job.Message)
+
+	job = &models.Job{ID: 2, Namespace: "default", Status: models.JobRunning, Progress: 40}
+	mJob.EXPECT().UpdateJob(nil, job, models.JobRunning).Return(true, nil).Times(1)
+	assert.NoError(t, s.Finish(job, map[string]int{"total": 1}, nil))
+	assert.Equal(t, models.JobSucceeded, job.Status)
+	assert.Equal(t, 100, job.Progress)
+
+	// cancel
+	mJob.EXPECT().GetJob(nil, "default", int64(3)).Return(&models.Job{ID: 3, Namespace: "default", Status: models.JobPending}, nil).Times(1)
+	mJob.EXPECT().UpdateJob(nil, gomock.Any(), models.JobPending).Return(false, nil).Times(1)
+	mJob.EXPECT().GetJob(nil, "default", int64(3)).Return(&models.Job{ID: 3, Namespace: "default", Status: models.JobRunning}, nil).Times(1)
+	mJob.EXPECT().UpdateJob(nil, gomock.Any(), models.JobRunning).DoAndReturn(func(_ interface{}, job *models.Job, _ string) (bool, error) {
+		assert.Equal(t, models.JobCanceled, job.Status)
+		return true, nil
+	}).Times(1)
+	mJob.EXPECT().GetJob(nil, "default", int64(3)).Return(&models.Job{ID: 3, Namespace: "default", Status: models.JobCanceled}, nil).Times(1)
+	job, err = s.Cancel("default", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobCanceled, job.Status)
+
+	mJob.EXPECT().GetJob(nil, "default", int64(3)).Return(&models.Job{ID: 3, Namespace: "default", Status: models.JobCanceled}, nil).Times(1)
+	_, err = s.Cancel("default", 3)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the job is already canceled")
+}
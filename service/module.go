@@ -1,6 +1,11 @@
 package service
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	"github.com/baetyl/baetyl-cloud/v2/models"
@@ -22,6 +27,17 @@ type ModuleService interface {
 
 	GetLatestModuleImage(name string) (string, error)
 	GetLatestModuleProgram(name, platform string) (string, error)
+
+	// CheckDependencies checks that the module versions depended on exist and there is no cyclic dependency
+	CheckDependencies(module *models.Module) error
+	// GetDependencyTree resolves the dependencies of the module version recursively
+	GetDependencyTree(name, version string) (*models.ModuleDependencyTree, error)
+	// ListDependents lists the modules depending on the module, on any version of it if the version is empty
+	ListDependents(name, version string) ([]models.Module, error)
+}
+
+type moduleService struct {
+	plugin.Module
 }
 
 // NewModuleService
@@ -30,5 +46,70 @@ func NewModuleService(config *config.CloudConfig) (ModuleService, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ds.(plugin.Module), nil
+	return &moduleService{Module: ds.(plugin.Module)}, nil
+}
+
+func (s *moduleService) CheckDependencies(module *models.Module) error {
+	_, err := s.resolveDependencies(module, nil)
+	return err
+}
+
+func (s *moduleService) GetDependencyTree(name, version string) (*models.ModuleDependencyTree, error) {
+	module, err := s.GetModuleByVersion(name, version)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolveDependencies(module, nil)
+}
+
+func (s *moduleService) ListDependents(name, version string) ([]models.Module, error) {
+	modules, err := s.ListModules(&models.Filter{}, "")
+	if err != nil {
+		return nil, err
+	}
+	var res []models.Module
+	for _, m := range modules {
+		for _, dep := range m.Dependencies {
+			if dep.Name == name && (version == "" || dep.Version == version) {
+				res = append(res, m)
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// resolveDependencies resolves the dependencies depth first, the path keeps the module versions being resolved
+// to detect cycles, and the module itself is used as is since its dependencies may not be saved yet
+func (s *moduleService) resolveDependencies(module *models.Module, path []string) (*models.ModuleDependencyTree, error) {
+	key := moduleVersionKey(module.Name, module.Version)
+	path = append(path, key)
+	tree := &models.ModuleDependencyTree{Name: module.Name, Version: module.Version}
+	for _, dep := range module.Dependencies {
+		depKey := moduleVersionKey(dep.Name, dep.Version)
+		for i, p := range path {
+			if p == depKey {
+				return nil, common.Error(common.ErrRequestParamInvalid,
+					common.Field("error", fmt.Sprintf("a cyclic dependency is found (%s)", strings.Join(append(path[i:len(path):len(path)], depKey), " -> "))))
+			}
+		}
+		m, err := s.GetModuleByVersion(dep.Name, dep.Version)
+		if err != nil {
+			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				return nil, common.Error(common.ErrRequestParamInvalid,
+					common.Field("error", fmt.Sprintf("the dependency (%s) of the module (%s) does not exist", depKey, key)))
+			}
+			return nil, err
+		}
+		sub, err := s.resolveDependencies(m, path)
+		if err != nil {
+			return nil, err
+		}
+		tree.Dependencies = append(tree.Dependencies, *sub)
+	}
+	return tree, nil
+}
+
+func moduleVersionKey(name, version string) string {
+	return name + ":" + version
 }
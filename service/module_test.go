@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestModuleService_Dependencies(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ms := &moduleService{Module: mockObject.module}
+
+	a := &models.Module{Name: "a", Version: "v1", Dependencies: []models.ModuleDependency{{Name: "b", Version: "v1"}, {Name: "c", Version: "v1"}}}
+	b := &models.Module{Name: "b", Version: "v1", Dependencies: []models.ModuleDependency{{Name: "c", Version: "v1"}}}
+	c := &models.Module{Name: "c", Version: "v1"}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "module"), common.Field("name", "d"))
+
+	mockObject.module.EXPECT().GetModuleByVersion("a", "v1").Return(a, nil)
+	mockObject.module.EXPECT().GetModuleByVersion("b", "v1").Return(b, nil)
+	mockObject.module.EXPECT().GetModuleByVersion("c", "v1").Return(c, nil).Times(2)
+	tree, err := ms.GetDependencyTree("a", "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ModuleDependencyTree{Name: "a", Version: "v1", Dependencies: []models.ModuleDependencyTree{
+		{Name: "b", Version: "v1", Dependencies: []models.ModuleDependencyTree{{Name: "c", Version: "v1"}}},
+		{Name: "c", Version: "v1"},
+	}}, tree)
+
+	// the module being checked is used as is
+	c2 := &models.Module{Name: "c", Version: "v1", Dependencies: []models.ModuleDependency{{Name: "a", Version: "v1"}}}
+	mockObject.module.EXPECT().GetModuleByVersion("a", "v1").Return(a, nil)
+	mockObject.module.EXPECT().GetModuleByVersion("b", "v1").Return(b, nil)
+	err = ms.CheckDependencies(c2)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "a cyclic dependency is found (c:v1 -> a:v1 -> b:v1 -> c:v1)")
+
+	d := &models.Module{Name: "d", Version: "v1", Dependencies: []models.ModuleDependency{{Name: "e", Version: "v1"}}}
+	mockObject.module.EXPECT().GetModuleByVersion("e", "v1").Return(nil, notFound)
+	err = ms.CheckDependencies(d)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the dependency (e:v1) of the module (d:v1) does not exist")
+
+	mockObject.module.EXPECT().GetModuleByVersion("e", "v1").Return(nil, fmt.Errorf("error"))
+	err = ms.CheckDependencies(d)
+	assert.EqualError(t, err, "error")
+
+	assert.NoError(t, ms.CheckDependencies(c))
+}
+
+func TestModuleService_ListDependents(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ms := &moduleService{Module: mockObject.module}
+
+	modules := []models.Module{
+		{Name: "a", Version: "v1", Dependencies: []models.ModuleDependency{{Name: "c", Version: "v1"}}},
+		{Name: "b", Version: "v1", Dependencies: []models.ModuleDependency{{Name: "c", Version: "v2"}}},
+		{Name: "c", Version: "v1"},
+	}
+	mockObject.module.EXPECT().ListModules(&models.Filter{}, common.ModuleType("")).Return(modules, nil).Times(2)
+	res, err := ms.ListDependents("c", "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, modules[:1], res)
+	res, err = ms.ListDependents("c", "")
+	assert.NoError(t, err)
+	assert.Equal(t, modules[:2], res)
+
+	mockObject.module.EXPECT().ListModules(&models.Filter{}, common.ModuleType("")).Return(nil, fmt.Errorf("error"))
+	_, err = ms.ListDependents("c", "")
+	assert.Error(t, err)
+}
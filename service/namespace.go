@@ -2,8 +2,11 @@ package service
 
 import (
 	"strings"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
@@ -19,16 +22,31 @@ const KeyDeleteExtraNamespaceResources = "deleteExtraNamespaceResources"
 type CreateExtraNamespaceResourcesFunc func(namespace string) error
 type DeleteExtraNamespaceResourcesFunc func(namespace string) error
 
+const (
+	// NamespaceSettingsConfig the invisible config keeping the settings of the namespace
+	NamespaceSettingsConfig = "baetyl-namespace-settings"
+	namespaceSettingsKey    = "settings"
+)
+
 // NamespaceService NamespaceService
 type NamespaceService interface {
 	Get(namespace string) (*models.Namespace, error)
 	Create(namespace *models.Namespace) (*models.Namespace, error)
 	List(listOptions *models.ListOptions) (*models.NamespaceList, error)
 	Delete(namespace *models.Namespace) error
+	// GetSettings returns the settings of the namespace, which are empty if never set
+	GetSettings(namespace string) (*models.NamespaceSettings, error)
+	UpdateSettings(namespace string, settings *models.NamespaceSettings) (*models.NamespaceSettings, error)
+	// GetFeatures returns the feature flags in effect in the namespace, the global defaults overridden by the settings
+	GetFeatures(namespace string) (map[string]bool, error)
+	// IsFeatureEnabled returns whether the feature is enabled in the namespace, the unknown features are disabled
+	IsFeatureEnabled(namespace, flag string) (bool, error)
 }
 
 type NamespaceServiceImpl struct {
 	namespace plugin.Namespace
+	config    plugin.Configuration
+	features  map[string]bool
 	Hooks     map[string]interface{}
 }
 
@@ -40,6 +58,8 @@ func NewNamespaceService(config *config.CloudConfig) (NamespaceService, error) {
 	}
 	return &NamespaceServiceImpl{
 		namespace: ms.(plugin.Namespace),
+		config:    ms.(plugin.Configuration),
+		features:  config.Features,
 		Hooks:     make(map[string]interface{}),
 	}, nil
 }
@@ -78,3 +98,87 @@ func (s *NamespaceServiceImpl) Delete(namespace *models.Namespace) error {
 	}
 	return s.namespace.DeleteNamespace(namespace)
 }
+
+// GetSettings get the settings of the namespace
+func (s *NamespaceServiceImpl) GetSettings(namespace string) (*models.NamespaceSettings, error) {
+	settings := &models.NamespaceSettings{}
+	cfg, err := s.config.GetConfig(nil, namespace, NamespaceSettingsConfig, "")
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return settings, nil
+		}
+		return nil, err
+	}
+	if data, ok := cfg.Data[namespaceSettingsKey]; ok {
+		if err = json.Unmarshal([]byte(data), settings); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return settings, nil
+}
+
+// UpdateSettings replace the settings of the namespace
+func (s *NamespaceServiceImpl) UpdateSettings(namespace string, settings *models.NamespaceSettings) (*models.NamespaceSettings, error) {
+	for flag := range settings.Features {
+		if _, ok := s.features[flag]; !ok {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the feature ("+flag+") is unknown"))
+		}
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg := &specV1.Configuration{
+		Name:      NamespaceSettingsConfig,
+		Namespace: namespace,
+		Labels: map[string]string{
+			common.LabelSystem:       "true",
+			common.ResourceInvisible: "true",
+		},
+		Data: map[string]string{namespaceSettingsKey: string(data)},
+	}
+	old, err := s.config.GetConfig(nil, namespace, NamespaceSettingsConfig, "")
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		if _, err = s.config.CreateConfig(nil, namespace, cfg); err != nil {
+			return nil, err
+		}
+		return settings, nil
+	}
+	cfg.Version = old.Version
+	cfg.CreationTimestamp = old.CreationTimestamp
+	cfg.UpdateTimestamp = time.Now()
+	if _, err = s.config.UpdateConfig(nil, namespace, cfg); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetFeatures get the feature flags in effect in the namespace
+func (s *NamespaceServiceImpl) GetFeatures(namespace string) (map[string]bool, error) {
+	settings, err := s.GetSettings(namespace)
+	if err != nil {
+		return nil, err
+	}
+	features := make(map[string]bool, len(s.features))
+	for flag, enabled := range s.features {
+		features[flag] = enabled
+	}
+	for flag, enabled := range settings.Features {
+		if _, ok := features[flag]; ok {
+			features[flag] = enabled
+		}
+	}
+	return features, nil
+}
+
+// IsFeatureEnabled check the feature flag in effect in the namespace
+func (s *NamespaceServiceImpl) IsFeatureEnabled(namespace, flag string) (bool, error) {
+	features, err := s.GetFeatures(namespace)
+	if err != nil {
+		return false, err
+	}
+	return features[flag], nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"sync/atomic"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// blockedNamespaceReads the number of the reads blocked by the namespace guard since the start
+var blockedNamespaceReads int64
+
+// BlockedNamespaceReads returns the number of the reads blocked by the namespace guard since the start,
+// which is always 0 unless a storage returns the resources of other namespaces
+func BlockedNamespaceReads() int64 {
+	return atomic.LoadInt64(&blockedNamespaceReads)
+}
+
+// guardNamespace guards the read of the storage, the resource read should belong to the namespace queried,
+// otherwise the read is blocked as if the resource is not found. The resource without namespace is let through
+func guardNamespace(namespace string, resource common.Resource, name, owner string) error {
+	if owner == "" || owner == namespace {
+		return nil
+	}
+	atomic.AddInt64(&blockedNamespaceReads, 1)
+	log.L().Error("the read of the resource of another namespace is blocked",
+		log.Any("namespace", namespace), log.Any("type", resource), log.Any("name", name), log.Any("owner", owner))
+	return common.Error(common.ErrResourceNotFound, common.Field("type", resource), common.Field("name", name))
+}
+
+// guardNamespaceList drops the items of other namespaces from the list read, meta returns the namespace and the name of the item
+func guardNamespaceList[T any](namespace string, resource common.Resource, items []T, meta func(*T) (string, string)) []T {
+	res := items[:0]
+	for i := range items {
+		owner, name := meta(&items[i])
+		if guardNamespace(namespace, resource, name, owner) == nil {
+			res = append(res, items[i])
+		}
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestNamespaceGuard(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs, err := NewConfigService(mockObject.conf)
+	assert.NoError(t, err)
+
+	before := BlockedNamespaceReads()
+	// the resources without namespace are let through
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", "c0", "").Return(&specV1.Configuration{Name: "c0"}, nil)
+	res, err := cs.Get(nil, "default", "c0", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "c0", res.Name)
+
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", "c1", "").Return(&specV1.Configuration{Namespace: "other", Name: "c1"}, nil)
+	_, err = cs.Get(nil, "default", "c1", "")
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+	assert.Equal(t, before+1, BlockedNamespaceReads())
+
+	list := &models.ConfigurationList{Total: 3, Items: []specV1.Configuration{
+		{Namespace: "default", Name: "c0"},
+		{Namespace: "other", Name: "c1"},
+		{Namespace: "default", Name: "c2"},
+	}}
+	mockObject.configuration.EXPECT().ListConfig("default", &models.ListOptions{}).Return(list, nil)
+	resList, err := cs.List("default", &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resList.Total)
+	assert.Equal(t, []specV1.Configuration{{Namespace: "default", Name: "c0"}, {Namespace: "default", Name: "c2"}}, resList.Items)
+	assert.Equal(t, before+2, BlockedNamespaceReads())
+
+	// the secrets of other namespaces are blocked before they are decrypted, including their versions
+	ss, err := NewSecretService(mockObject.conf)
+	assert.NoError(t, err)
+	mockObject.secret.EXPECT().GetSecret(nil, "default", "s1", "").Return(&specV1.Secret{Namespace: "other", Name: "s1"}, nil).Times(3)
+	_, err = ss.Get("default", "s1", "")
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+	_, err = ss.GetVersion("default", "s1", "1")
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+	_, err = ss.ListVersions("default", "s1")
+	assert.Error(t, err)
+	assert.Equal(t, before+5, BlockedNamespaceReads())
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"testing"
 
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
@@ -80,3 +83,89 @@ func TestNamespaceService_Delete(t *testing.T) {
 	err = cs.Delete(ns)
 	assert.NoError(t, err)
 }
+
+func TestNamespaceService_Settings(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	cs, err := NewNamespaceService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", NamespaceSettingsConfig, "").Return(nil, fmt.Errorf("configs \"baetyl-namespace-settings\" not found"))
+	res, err := cs.GetSettings("default")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.NamespaceSettings{}, res)
+
+	settings := &models.NamespaceSettings{NodeLabels: map[string]string{"tenant": "acme"}}
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", NamespaceSettingsConfig, "").Return(nil, fmt.Errorf("configs \"baetyl-namespace-settings\" not found"))
+	mockObject.configuration.EXPECT().CreateConfig(nil, "default", gomock.Any()).DoAndReturn(func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Equal(t, "true", cfg.Labels[common.ResourceInvisible])
+		assert.Equal(t, `{"nodeLabels":{"tenant":"acme"}}`, cfg.Data["settings"])
+		return cfg, nil
+	})
+	res, err = cs.UpdateSettings("default", settings)
+	assert.NoError(t, err)
+	assert.Equal(t, settings, res)
+
+	old := &specV1.Configuration{Name: NamespaceSettingsConfig, Namespace: "default", Version: "12",
+		Data: map[string]string{"settings": `{"nodeLabels":{"tenant":"acme"}}`}}
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", NamespaceSettingsConfig, "").Return(old, nil)
+	res, err = cs.GetSettings("default")
+	assert.NoError(t, err)
+	assert.Equal(t, settings, res)
+
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", NamespaceSettingsConfig, "").Return(old, nil)
+	mockObject.configuration.EXPECT().UpdateConfig(nil, "default", gomock.Any()).DoAndReturn(func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Equal(t, "12", cfg.Version)
+		assert.Equal(t, "{}", cfg.Data["settings"])
+		return cfg, nil
+	})
+	res, err = cs.UpdateSettings("default", &models.NamespaceSettings{})
+	assert.NoError(t, err)
+	assert.Equal(t, &models.NamespaceSettings{}, res)
+
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", NamespaceSettingsConfig, "").Return(nil, fmt.Errorf("error"))
+	_, err = cs.GetSettings("default")
+	assert.Error(t, err)
+}
+
+func TestNamespaceService_Features(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	mockObject.conf.Features = map[string]bool{models.FeatureFunctions: true, models.FeatureCanary: false}
+	cs, err := NewNamespaceService(mockObject.conf)
+	assert.NoError(t, err)
+
+	// the global defaults
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", NamespaceSettingsConfig, "").Return(nil, fmt.Errorf("configs \"baetyl-namespace-settings\" not found"))
+	features, err := cs.GetFeatures("default")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{models.FeatureFunctions: true, models.FeatureCanary: false}, features)
+
+	// overridden by the namespace
+	settings := &specV1.Configuration{Name: NamespaceSettingsConfig, Namespace: "default",
+		Data: map[string]string{"settings": `{"features":{"functions":false,"canary":true,"unknown":true}}`}}
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", NamespaceSettingsConfig, "").Return(settings, nil).Times(4)
+	features, err = cs.GetFeatures("default")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{models.FeatureFunctions: false, models.FeatureCanary: true}, features)
+	enabled, err := cs.IsFeatureEnabled("default", models.FeatureCanary)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+	enabled, err = cs.IsFeatureEnabled("default", models.FeatureFunctions)
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+	enabled, err = cs.IsFeatureEnabled("default", "unknown")
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", NamespaceSettingsConfig, "").Return(nil, fmt.Errorf("error"))
+	_, err = cs.IsFeatureEnabled("default", models.FeatureCanary)
+	assert.Error(t, err)
+
+	// the unknown features can't be set
+	_, err = cs.UpdateSettings("default", &models.NamespaceSettings{Features: map[string]bool{"unknown": true}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the feature (unknown) is unknown")
+}
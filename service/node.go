@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -48,16 +49,38 @@ type NodeService interface {
 
 	UpdateReport(namespace, name string, report specV1.Report) (*models.Shadow, error)
 	UpdateInitReport(namespace, name string, report specV1.Report) (*models.Shadow, error)
+	// UpdateDesire updates the apps desired by the nodes and records the deploys of the nodes changed
 	UpdateDesire(tx interface{}, namespace string, names []string, app *specV1.Application, f func(*models.Shadow, *specV1.Application)) error
+	// ListDeployHistory lists the deploy records of the node without the desired specs, the latest first
+	ListDeployHistory(namespace, name string, filter *models.Filter) (*models.NodeDeployRecordList, error)
+	// GetDeployRecord returns the deploy record of the node with the desired spec after the deploy
+	GetDeployRecord(namespace, name string, id int64) (*models.NodeDeployRecord, error)
 
 	GetDesire(namespace, name string) (*specV1.Desire, error)
+	// ListReportTime returns the time of the last reports of the nodes, which is zero if the node never reported
+	ListReportTime(namespace string) (map[string]time.Time, error)
+	// ListStatsHistory returns the resource usage of the node reported within [from, to), averaged by step if it is positive
+	ListStatsHistory(namespace, name string, from, to time.Time, step time.Duration) ([]models.NodeStatsPoint, error)
+	// ListLatestStats returns the latest resource usage of the nodes reported since the time, keyed by the node names,
+	// the nodes without stats reported since then are absent
+	ListLatestStats(namespace string, names []string, since time.Time) (map[string]*models.NodeStatsPoint, error)
 
 	UpdateNodeAppVersion(tx interface{}, namespace string, app *specV1.Application) ([]string, error)
 	DeleteNodeAppVersion(tx interface{}, namespace string, app *specV1.Application) ([]string, error)
 
 	GetNodeProperties(ns, name string) (*models.NodeProperties, error)
-	UpdateNodeProperties(ns, name string, props *models.NodeProperties) (*models.NodeProperties, error)
+	// UpdateNodeProperties updates the desired properties of the node and records the changes made by the actor
+	UpdateNodeProperties(ns, name, actor string, props *models.NodeProperties) (*models.NodeProperties, error)
+	// ListPropertyHistory lists the changes of the desired and reported properties of the node, the latest first
+	ListPropertyHistory(ns, name string, params *models.NodePropertyRecordListOptions) (*models.NodePropertyRecordList, error)
 	UpdateNodeMode(ns, name, mode string) error
+	// UpdateNodeTags replaces the tags of the node, the apps are not rescheduled since the labels are unchanged
+	UpdateNodeTags(ns, name string, tags map[string]string) (map[string]string, error)
+	// UpdateNodeAttributes merges the attributes into the node and removes the ones with nil value, the apps are not rescheduled
+	UpdateNodeAttributes(ns, name string, attrs map[string]interface{}) (*specV1.Node, error)
+	// UpdateNodeAttributesIf merges the attributes into the node atomically if the check of the node passes,
+	// the check is made again against the latest node if the node is changed concurrently
+	UpdateNodeAttributesIf(ns, name string, check func(node *specV1.Node) error, attrs map[string]interface{}) (*specV1.Node, error)
 }
 
 type NodeServiceImpl struct {
@@ -65,10 +88,16 @@ type NodeServiceImpl struct {
 	App           plugin.Application
 	Node          plugin.Node
 	Shadow        plugin.Shadow
+	AppHistory    plugin.AppHistory
 	Cache         plugin.DataCache
 	SysAppService SystemAppService
 	Hooks         map[string]interface{}
-	logger        *log.Logger
+	Offline       config.NodeOffline
+	// MaintenanceExcludeQuota the nodes under maintenance are not counted in the node quota if true
+	MaintenanceExcludeQuota bool
+	// Metrics records the resource usage of the nodes reported, which is optional
+	Metrics plugin.MetricsStore
+	logger  *log.Logger
 }
 
 // NewNodeService NewNodeService
@@ -92,6 +121,18 @@ func NewNodeService(config *config.CloudConfig) (NodeService, error) {
 	if err != nil {
 		return nil, err
 	}
+	appHis, err := plugin.GetPlugin(config.Plugin.AppHistory)
+	if err != nil {
+		return nil, err
+	}
+	var metrics plugin.MetricsStore
+	if config.Plugin.MetricsStore != "" {
+		m, err := plugin.GetPlugin(config.Plugin.MetricsStore)
+		if err != nil {
+			return nil, err
+		}
+		metrics = m.(plugin.MetricsStore)
+	}
 	system, err := NewSystemAppService(config)
 	if err != nil {
 		return nil, err
@@ -124,10 +165,15 @@ func NewNodeService(config *config.CloudConfig) (NodeService, error) {
 		SysAppService: system,
 		Node:          node.(plugin.Node),
 		Shadow:        shadow.(plugin.Shadow),
+		AppHistory:    appHis.(plugin.AppHistory),
 		App:           app.(plugin.Application),
 		Hooks:         make(map[string]interface{}),
 		Cache:         cache.(plugin.DataCache),
+		Offline:       config.NodeOffline,
 		logger:        log.With(log.Any("service", "node")),
+
+		MaintenanceExcludeQuota: config.NodeMaintenance.ExcludeQuota,
+		Metrics:                 metrics,
 	}, nil
 }
 
@@ -141,6 +187,11 @@ func (n *NodeServiceImpl) Get(tx interface{}, namespace, name string) (*specV1.N
 		n.logger.Error("get node failed", log.Error(err))
 		return nil, err
 	}
+	if node != nil {
+		if err = guardNamespace(namespace, common.Node, name, node.Namespace); err != nil {
+			return nil, err
+		}
+	}
 
 	shadow, err := n.Shadow.Get(tx, namespace, name)
 	if err != nil {
@@ -204,6 +255,11 @@ func (n *NodeServiceImpl) List(namespace string, listOptions *models.ListOptions
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	total := len(list.Items)
+	list.Items = guardNamespaceList(namespace, common.Node, list.Items, func(node *specV1.Node) (string, string) {
+		return node.Namespace, node.Name
+	})
+	list.Total -= total - len(list.Items)
 	if len(list.Items) == 0 {
 		return list, nil
 	}
@@ -214,7 +270,10 @@ func (n *NodeServiceImpl) List(namespace string, listOptions *models.ListOptions
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	if listOptions.CreateSort != "" || listOptions.Ready != "" || listOptions.Cluster != "" {
+	if listOptions.IsCursorPaging() || (listOptions.SortBy != "" && listOptions.Ready == "" && listOptions.Status == "" && listOptions.Cluster == "") {
+		// the page fetched by cursor or sorted explicitly keeps the order of storage
+		resNode = list.Items
+	} else if listOptions.CreateSort != "" || listOptions.Ready != "" || listOptions.Status != "" || listOptions.Cluster != "" {
 		// filter sort
 		resNode, err = n.filterListNode(list, namespace, listOptions, shadowReportTimeMap)
 		list.Total = len(resNode)
@@ -225,8 +284,11 @@ func (n *NodeServiceImpl) List(namespace string, listOptions *models.ListOptions
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	start, end := models.GetPagingParam(listOptions, list.Total)
-	list.Items = resNode[start:end]
+	if !listOptions.IsCursorPaging() {
+		start, end := models.GetPagingParam(listOptions, list.Total)
+		resNode = resNode[start:end]
+	}
+	list.Items = resNode
 
 	var names []string
 	for i := range list.Items {
@@ -262,7 +324,8 @@ func Reverse(s []interface{}) {
 
 func (n *NodeServiceImpl) filterListNode(list *models.NodeList, namespace string, listOptions *models.ListOptions, shadowReportTimeMap map[string]string) ([]specV1.Node, error) {
 	var resNode []specV1.Node
-	if listOptions.Ready != "" || listOptions.Cluster != "" {
+	if listOptions.Ready != "" || listOptions.Status != "" || listOptions.Cluster != "" {
+		now := time.Now()
 		for i := range list.Items {
 			node := list.Items[i]
 			if listOptions.Cluster != "" {
@@ -270,37 +333,14 @@ func (n *NodeServiceImpl) filterListNode(list *models.NodeList, namespace string
 					continue
 				}
 			}
-			if listOptions.Ready != "" {
-				reportTime := shadowReportTimeMap[node.Name]
-				after, err := n.GetNodeAfterTime(node, namespace)
-				if err != nil {
-					return nil, err
-				}
-				t, _ := time.Parse(time.RFC3339Nano, reportTime)
-				switch listOptions.Ready {
-				case models.ReadyTypeOnline:
-					if time.Now().UTC().After(t.Add(after)) {
-						continue
-					}
-				case models.ReadyTypOffline:
-					noTime, err := time.Parse("2006-01-02 03:04:05", "0001-01-01 00:00:00")
-					if err != nil {
-						return nil, err
-					}
-					if t == noTime || time.Now().UTC().Before(t.Add(after)) {
-						continue
-					}
-				case models.ReadyTypeUninstall:
-					noTime, err := time.Parse("2006-01-02 03:04:05", "0001-01-01 00:00:00")
-					if err != nil {
-						return nil, err
-					}
-					if t != noTime {
-						continue
-					}
-				default:
-
-				}
+			// the status and the ready filters share the offline threshold of the namespace, as GetNode reports
+			t, _ := time.Parse(time.RFC3339Nano, shadowReportTimeMap[node.Name])
+			status := nodeStatus(n.Offline, namespace, t, now)
+			if listOptions.Status != "" && status != listOptions.Status {
+				continue
+			}
+			if listOptions.Ready != "" && status != listOptions.Ready {
+				continue
 			}
 			resNode = append(resNode, node)
 		}
@@ -370,6 +410,19 @@ func (n *NodeServiceImpl) GetNodeAfterTime(node specV1.Node, namespace string) (
 	return after, err
 }
 
+func (n *NodeServiceImpl) ListReportTime(namespace string) (map[string]time.Time, error) {
+	reportTimeMap, err := n.GetAllShadowReportTime(namespace, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]time.Time, len(reportTimeMap))
+	for name, v := range reportTimeMap {
+		t, _ := time.Parse(time.RFC3339Nano, v)
+		res[name] = t
+	}
+	return res, nil
+}
+
 // GetAllShadowReportTime get node report time from cache
 func (n *NodeServiceImpl) GetAllShadowReportTime(namespace string, nodes []specV1.Node) (reportTimeMap map[string]string, err error) {
 
@@ -540,8 +593,16 @@ func (n *NodeServiceImpl) Count(namespace string) (map[string]int, error) {
 	if err != nil {
 		return nil, err
 	}
+	count := len(list.Items)
+	if n.MaintenanceExcludeQuota {
+		for i := range list.Items {
+			if models.GetNodeMaintenance(&list.Items[i]) != nil {
+				count--
+			}
+		}
+	}
 	return map[string]int{
-		plugin.QuotaNode: len(list.Items),
+		plugin.QuotaNode: count,
 	}, nil
 }
 
@@ -630,7 +691,12 @@ func (n *NodeServiceImpl) UpdateReport(namespace, name string, report specV1.Rep
 	if err = n.updateReportNodeProperties(namespace, name, report, shadow); err != nil {
 		return nil, err
 	}
-	return n.Shadow.UpdateReport(shadow)
+	res, err := n.Shadow.UpdateReport(shadow)
+	if err != nil {
+		return nil, err
+	}
+	n.recordStats(namespace, name, report)
+	return res, nil
 }
 
 func (n *NodeServiceImpl) UpdateInitReport(namespace, name string, report specV1.Report) (*models.Shadow, error) {
@@ -687,6 +753,7 @@ func (n *NodeServiceImpl) updateReportNodeProperties(ns, name string, report spe
 	if _, err := n.Node.UpdateNode(nil, ns, []*specV1.Node{node}); err != nil {
 		return err
 	}
+	n.recordNodeProperties(ns, name, models.NodePropertyReport, models.NodePropertyActorNode, diff, now)
 	// since merge won't delete exist key-val, node props should override
 	if len(newProps) == 0 {
 		delete(shad.Report, common.NodeProps)
@@ -703,11 +770,69 @@ func (n *NodeServiceImpl) UpdateDesire(tx interface{}, namespace string, names [
 	if err != nil {
 		return err
 	}
+	records := make([]*models.NodeDeployRecord, 0, len(shadows))
 	for _, shadow := range shadows {
+		prev := desiredAppVersion(shadow.Desire, app)
 		// Refresh desire in Shadow by app
 		f(shadow, app)
+		if record := newNodeDeployRecord(shadow, app, prev); record != nil {
+			records = append(records, record)
+		}
+	}
+	if err = n.Shadow.UpdateDesires(tx, shadows); err != nil {
+		return err
+	}
+	return n.AppHistory.CreateNodeDeployRecords(tx, records)
+}
+
+// ListDeployHistory list the deploy records of the node
+func (n *NodeServiceImpl) ListDeployHistory(namespace, name string, filter *models.Filter) (*models.NodeDeployRecordList, error) {
+	return n.AppHistory.ListNodeDeployRecord(nil, namespace, name, filter)
+}
+
+// GetDeployRecord get a deploy record of the node
+func (n *NodeServiceImpl) GetDeployRecord(namespace, name string, id int64) (*models.NodeDeployRecord, error) {
+	return n.AppHistory.GetNodeDeployRecord(nil, namespace, name, id)
+}
+
+// desiredAppVersion returns the version of the app desired by the node, or empty if not desired
+func desiredAppVersion(desire specV1.Desire, app *specV1.Application) string {
+	if desire == nil {
+		return ""
+	}
+	for _, a := range desire.AppInfos(app.System) {
+		if a.Name == app.Name {
+			return a.Version
+		}
+	}
+	return ""
+}
+
+// newNodeDeployRecord returns the record of the change of the app desired by the node, or nil if unchanged
+func newNodeDeployRecord(shadow *models.Shadow, app *specV1.Application, prev string) *models.NodeDeployRecord {
+	version := desiredAppVersion(shadow.Desire, app)
+	if version == prev {
+		return nil
+	}
+	action := models.NodeDeployActionDeploy
+	if version == "" {
+		action = models.NodeDeployActionUndeploy
+	}
+	return &models.NodeDeployRecord{
+		Namespace: shadow.Namespace,
+		Node:      shadow.Name,
+		Action:    action,
+		App: models.NodeDeployApp{
+			Name:        app.Name,
+			System:      app.System,
+			Version:     version,
+			PrevVersion: prev,
+		},
+		Apps:    shadow.Desire.AppInfos(false),
+		SysApps: shadow.Desire.AppInfos(true),
+		Desire:  shadow.Desire,
+		Time:    time.Now(),
 	}
-	return n.Shadow.UpdateDesires(tx, shadows)
 }
 
 func (n *NodeServiceImpl) updateDesire(tx interface{}, shadow *models.Shadow, desire specV1.Desire) error {
@@ -742,7 +867,7 @@ func (n *NodeServiceImpl) InsertOrUpdateNodeAndAppIndex(tx interface{}, namespac
 		return err
 	}
 
-	desire, appNames := n.rematchApplicationsForNode(apps, node.Labels)
+	desire, appNames := n.rematchApplicationsForNode(apps, node.Labels, models.IsNodeUnschedulable(node))
 
 	node.Desire = desire
 
@@ -768,15 +893,16 @@ func (n *NodeServiceImpl) InsertOrUpdateNodeAndAppIndex(tx interface{}, namespac
 // rematchApplicationsForNode rematch applications for node
 //   - param apps: all applications for the namespace
 //   - param nodeLabels: the labels of node
+//   - param unschedulable: only the system applications are matched for the drained node
 //   - return desire: the node's desire
 //   - return appNames: matched application names
-func (n *NodeServiceImpl) rematchApplicationsForNode(apps *models.ApplicationList, labels map[string]string) (specV1.Desire, []string) {
+func (n *NodeServiceImpl) rematchApplicationsForNode(apps *models.ApplicationList, labels map[string]string, unschedulable bool) (specV1.Desire, []string) {
 	desireApps := make([]specV1.AppInfo, 0)
 	sysApps := make([]specV1.AppInfo, 0)
 
 	appNames := make([]string, 0)
 	for _, app := range apps.Items {
-		if app.Selector == "" {
+		if app.Selector == "" || (unschedulable && !app.System) {
 			continue
 		}
 
@@ -814,10 +940,13 @@ func (n *NodeServiceImpl) UpdateNodeAppVersion(tx interface{}, namespace string,
 	if err != nil {
 		return nil, err
 	}
-	// update nodes
+	// update nodes, the drained nodes only get the system apps
 	var nodes []string
 	for idx := range nodeList.Items {
 		node := &nodeList.Items[idx]
+		if !app.System && models.IsNodeUnschedulable(node) {
+			continue
+		}
 		nodes = append(nodes, node.Name)
 	}
 	err = n.UpdateDesire(tx, namespace, nodes, app, RefreshNodeDesireByApp)
@@ -962,7 +1091,7 @@ func (n *NodeServiceImpl) GetNodeProperties(namespace, name string) (*models.Nod
 
 // UpdateNodeProperties update desire of node properties
 // and can not update report of node properties
-func (n *NodeServiceImpl) UpdateNodeProperties(namespace, name string, props *models.NodeProperties) (*models.NodeProperties, error) {
+func (n *NodeServiceImpl) UpdateNodeProperties(namespace, name, actor string, props *models.NodeProperties) (*models.NodeProperties, error) {
 	node, err := n.Node.GetNode(nil, namespace, name)
 	if err != nil && strings.Contains(err.Error(), "not found") {
 		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "node"),
@@ -1011,9 +1140,43 @@ func (n *NodeServiceImpl) UpdateNodeProperties(namespace, name string, props *mo
 	if _, err := n.Node.UpdateNode(nil, namespace, []*specV1.Node{node}); err != nil {
 		return nil, err
 	}
+	n.recordNodeProperties(namespace, name, models.NodePropertyDesire, actor, diff, now)
 	return props, nil
 }
 
+// ListPropertyHistory list the property records of the node
+func (n *NodeServiceImpl) ListPropertyHistory(ns, name string, params *models.NodePropertyRecordListOptions) (*models.NodePropertyRecordList, error) {
+	return n.AppHistory.ListNodePropertyRecord(nil, ns, name, params)
+}
+
+// recordNodeProperties records the changed properties, the failure is logged only since the properties have been updated
+func (n *NodeServiceImpl) recordNodeProperties(ns, name, typ, actor string, diff map[string]interface{}, now time.Time) {
+	if len(diff) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(diff))
+	for key := range diff {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	records := make([]*models.NodePropertyRecord, 0, len(keys))
+	for _, key := range keys {
+		records = append(records, &models.NodePropertyRecord{
+			Namespace: ns,
+			Node:      name,
+			Property:  key,
+			Type:      typ,
+			Value:     diff[key],
+			Actor:     actor,
+			Time:      now,
+		})
+	}
+	if err := n.AppHistory.CreateNodePropertyRecords(nil, records); err != nil {
+		n.logger.Error("failed to record node properties", log.Any("namespace", ns), log.Any("name", name),
+			log.Any("type", typ), log.Error(err))
+	}
+}
+
 func (n *NodeServiceImpl) UpdateNodeMode(ns, name, mode string) error {
 	node, err := n.Node.GetNode(nil, ns, name)
 	if err != nil && strings.Contains(err.Error(), "not found") {
@@ -1034,6 +1197,80 @@ func (n *NodeServiceImpl) UpdateNodeMode(ns, name, mode string) error {
 	return nil
 }
 
+func (n *NodeServiceImpl) UpdateNodeTags(ns, name string, tags map[string]string) (map[string]string, error) {
+	node, err := n.Node.GetNode(nil, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	node.Annotations = common.SetNodeTags(node.Annotations, tags)
+	if _, err = n.Node.UpdateNode(nil, ns, []*specV1.Node{node}); err != nil {
+		return nil, err
+	}
+	return common.GetNodeTags(node.Annotations), nil
+}
+
+func (n *NodeServiceImpl) UpdateNodeAttributes(ns, name string, attrs map[string]interface{}) (*specV1.Node, error) {
+	node, err := n.Node.GetNode(nil, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	if node.Attributes == nil {
+		node.Attributes = map[string]interface{}{}
+	}
+	for k, v := range attrs {
+		if v == nil {
+			delete(node.Attributes, k)
+		} else {
+			node.Attributes[k] = v
+		}
+	}
+	list, err := n.Node.UpdateNode(nil, ns, []*specV1.Node{node})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) > 0 {
+		return list[0], nil
+	}
+	return node, nil
+}
+
+func (n *NodeServiceImpl) UpdateNodeAttributesIf(ns, name string, check func(node *specV1.Node) error, attrs map[string]interface{}) (*specV1.Node, error) {
+	var version string
+	for i := 0; i < casRetryTimes; i++ {
+		node, err := n.Node.GetNode(nil, ns, name)
+		if err != nil {
+			return nil, err
+		}
+		if err = check(node); err != nil {
+			return nil, err
+		}
+		version = node.Version
+		if node.Attributes == nil {
+			node.Attributes = map[string]interface{}{}
+		}
+		for k, v := range attrs {
+			if v == nil {
+				delete(node.Attributes, k)
+			} else {
+				node.Attributes[k] = v
+			}
+		}
+		ok, err := n.Node.UpdateNodeIfVersion(nil, ns, node, version)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return node, nil
+		}
+	}
+	current := ""
+	if node, err := n.Node.GetNode(nil, ns, name); err == nil {
+		current = node.Version
+	}
+	return nil, common.Error(common.ErrVersionConflict, common.Field("type", "node"), common.Field("name", name),
+		common.Field("current", current), common.Field("version", version))
+}
+
 func getNodePropertiesMeta(node *specV1.Node) *models.NodePropertiesMetadata {
 	propsMeta := &models.NodePropertiesMetadata{
 		ReportMeta: make(map[string]interface{}),
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/node_command.go -package=service github.com/baetyl/baetyl-cloud/v2/service NodeCommandService
+
+// NodeCommandService queues the commands for the nodes, which pick them up on their next sync
+type NodeCommandService interface {
+	// Dispatch queues the command for the node, it fails with ErrTooManyRequests if the node has
+	// been dispatched too many commands within the window
+	Dispatch(command *models.NodeCommand) (*models.NodeCommand, error)
+	// List lists the commands of the node, the pending ones expired are listed as expired
+	List(namespace, node string, filter *models.Filter) (*models.NodeCommandList, error)
+	// Pick returns the pending commands of the node and marks them as dispatched
+	Pick(namespace, node string) ([]models.NodeCommand, error)
+	// Report updates the status of the commands by the results reported by the node, keyed by the ids
+	Report(namespace, node string, results map[string]models.NodeCommandResult) error
+}
+
+type nodeCommandService struct {
+	cfg     config.NodeCommand
+	command plugin.NodeCommand
+}
+
+// NewNodeCommandService NewNodeCommandService
+func NewNodeCommandService(config *config.CloudConfig) (NodeCommandService, error) {
+	command, err := plugin.GetPlugin(config.Plugin.NodeCmd)
+	if err != nil {
+		return nil, err
+	}
+	return &nodeCommandService{
+		cfg:     config.NodeCommand,
+		command: command.(plugin.NodeCommand),
+	}, nil
+}
+
+func (s *nodeCommandService) Dispatch(command *models.NodeCommand) (*models.NodeCommand, error) {
+	if s.cfg.Limit > 0 {
+		count, err := s.command.CountNodeCommand(nil, command.Namespace, command.Node, time.Now().Add(-s.cfg.Window))
+		if err != nil {
+			return nil, err
+		}
+		if count >= s.cfg.Limit {
+			return nil, common.Error(common.ErrTooManyRequests, common.Field("error",
+				fmt.Sprintf("at most %d commands can be dispatched to the node within %s", s.cfg.Limit, s.cfg.Window)))
+		}
+	}
+	command.Status = models.NodeCommandPending
+	command.Message = ""
+	command.CreationTimestamp = time.Now()
+	return s.command.CreateNodeCommand(nil, command)
+}
+
+func (s *nodeCommandService) List(namespace, node string, filter *models.Filter) (*models.NodeCommandList, error) {
+	list, err := s.command.ListNodeCommand(nil, namespace, node, filter)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(-s.cfg.Expiration)
+	for i := range list.Items {
+		if list.Items[i].Status == models.NodeCommandPending && list.Items[i].CreationTimestamp.Before(deadline) {
+			list.Items[i].Status = models.NodeCommandExpired
+		}
+	}
+	return list, nil
+}
+
+func (s *nodeCommandService) Pick(namespace, node string) ([]models.NodeCommand, error) {
+	commands, err := s.command.ListPendingNodeCommand(nil, namespace, node, time.Now().Add(-s.cfg.Expiration))
+	if err != nil {
+		return nil, err
+	}
+	for i := range commands {
+		err = s.command.UpdateNodeCommandStatus(nil, namespace, node, commands[i].ID, models.NodeCommandDispatched, "")
+		if err != nil {
+			return nil, err
+		}
+		commands[i].Status = models.NodeCommandDispatched
+	}
+	return commands, nil
+}
+
+func (s *nodeCommandService) Report(namespace, node string, results map[string]models.NodeCommandResult) error {
+	for k, v := range results {
+		id, err := strconv.ParseInt(k, 10, 64)
+		if err != nil {
+			log.L().Warn("ignore the result of an unknown command", log.Any("namespace", namespace),
+				log.Any("node", node), log.Any("id", k))
+			continue
+		}
+		switch v.Status {
+		case models.NodeCommandSucceeded, models.NodeCommandFailed:
+		default:
+			continue
+		}
+		if err = s.command.UpdateNodeCommandStatus(nil, namespace, node, id, v.Status, v.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
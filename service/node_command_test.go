@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestNodeCommandService(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mCommand := mockPlugin.NewMockNodeCommand(mockCtl)
+	s := &nodeCommandService{
+		cfg:     config.NodeCommand{Limit: 2, Window: time.Minute, Expiration: time.Hour},
+		command: mCommand,
+	}
+
+	// dispatch
+	command := &models.NodeCommand{Namespace: "default", Node: "node01", Type: models.NodeCommandReboot, Status: models.NodeCommandFailed}
+	mCommand.EXPECT().CountNodeCommand(nil, "default", "node01", gomock.Any()).Return(1, nil).Times(1)
+	mCommand.EXPECT().CreateNodeCommand(nil, command).DoAndReturn(func(_ interface{}, c *models.NodeCommand) (*models.NodeCommand, error) {
+		assert.Equal(t, models.NodeCommandPending, c.Status)
+		assert.False(t, c.CreationTimestamp.IsZero())
+		return c, nil
+	}).Times(1)
+	res, err := s.Dispatch(command)
+	assert.NoError(t, err)
+	assert.Equal(t, models.NodeCommandPending, res.Status)
+
+	mCommand.EXPECT().CountNodeCommand(nil, "default", "node01", gomock.Any()).Return(2, nil).Times(1)
+	_, err = s.Dispatch(command)
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrTooManyRequests, e.Code())
+
+	// list
+	now := time.Now()
+	mCommand.EXPECT().ListNodeCommand(nil, "default", "node01", &models.Filter{}).Return(&models.NodeCommandList{
+		Total: 2,
+		Items: []models.NodeCommand{
+			{ID: 2, Status: models.NodeCommandPending, CreationTimestamp: now},
+			{ID: 1, Status: models.NodeCommandPending, CreationTimestamp: now.Add(-2 * time.Hour)},
+		},
+	}, nil).Times(1)
+	list, err := s.List("default", "node01", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.NodeCommandPending, list.Items[0].Status)
+	assert.Equal(t, models.NodeCommandExpired, list.Items[1].Status)
+
+	// pick
+	mCommand.EXPECT().ListPendingNodeCommand(nil, "default", "node01", gomock.Any()).Return([]models.NodeCommand{{ID: 2, Status: models.NodeCommandPending}}, nil).Times(1)
+	mCommand.EXPECT().UpdateNodeCommandStatus(nil, "default", "node01", int64(2), models.NodeCommandDispatched, "").Return(nil).Times(1)
+	commands, err := s.Pick("default", "node01")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.NodeCommand{{ID: 2, Status: models.NodeCommandDispatched}}, commands)
+
+	// report
+	mCommand.EXPECT().UpdateNodeCommandStatus(nil, "default", "node01", int64(2), models.NodeCommandFailed, "timeout").Return(nil).Times(1)
+	err = s.Report("default", "node01", map[string]models.NodeCommandResult{
+		"2":   {Status: models.NodeCommandFailed, Message: "timeout"},
+		"3":   {Status: models.NodeCommandPending},
+		"abc": {Status: models.NodeCommandSucceeded},
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/node_group.go -package=service github.com/baetyl/baetyl-cloud/v2/service NodeGroupService
+
+// NodeGroupService manages the node groups defined by label selector
+type NodeGroupService interface {
+	Get(namespace, name string) (*models.NodeGroup, error)
+	List(namespace string, listOptions *models.ListOptions) (*models.NodeGroupList, error)
+	Create(group *models.NodeGroup) (*models.NodeGroup, error)
+	Update(group *models.NodeGroup) (*models.NodeGroup, error)
+	Delete(namespace, name string) error
+}
+
+type nodeGroupService struct {
+	nodeGroup plugin.NodeGroup
+}
+
+// NewNodeGroupService NewNodeGroupService
+func NewNodeGroupService(config *config.CloudConfig) (NodeGroupService, error) {
+	ng, err := plugin.GetPlugin(config.Plugin.NodeGroup)
+	if err != nil {
+		return nil, err
+	}
+	return &nodeGroupService{
+		nodeGroup: ng.(plugin.NodeGroup),
+	}, nil
+}
+
+// Get get a node group
+func (s *nodeGroupService) Get(namespace, name string) (*models.NodeGroup, error) {
+	return s.nodeGroup.GetNodeGroup(nil, namespace, name)
+}
+
+// List list node groups
+func (s *nodeGroupService) List(namespace string, listOptions *models.ListOptions) (*models.NodeGroupList, error) {
+	return s.nodeGroup.ListNodeGroup(nil, namespace, listOptions)
+}
+
+// Create create a node group
+func (s *nodeGroupService) Create(group *models.NodeGroup) (*models.NodeGroup, error) {
+	return s.nodeGroup.CreateNodeGroup(nil, group)
+}
+
+// Update update a node group
+func (s *nodeGroupService) Update(group *models.NodeGroup) (*models.NodeGroup, error) {
+	return s.nodeGroup.UpdateNodeGroup(nil, group)
+}
+
+// Delete delete a node group
+func (s *nodeGroupService) Delete(namespace, name string) error {
+	return s.nodeGroup.DeleteNodeGroup(nil, namespace, name)
+}
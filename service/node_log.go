@@ -0,0 +1,34 @@
+package service
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/node_log.go -package=service github.com/baetyl/baetyl-cloud/v2/service NodeLogService
+
+// NodeLogService streams the logs of the apps from the nodes through the agent connection
+type NodeLogService interface {
+	// Stream opens the log stream of the app on the node, the stream is closed by the caller
+	Stream(namespace, node, app string, options *models.AppLogOptions) (io.ReadCloser, error)
+}
+
+type nodeLogService struct {
+	log plugin.NodeLog
+}
+
+// NewNodeLogService NewNodeLogService
+func NewNodeLogService(config *config.CloudConfig) (NodeLogService, error) {
+	l, err := plugin.GetPlugin(config.Plugin.NodeLog)
+	if err != nil {
+		return nil, err
+	}
+	return &nodeLogService{log: l.(plugin.NodeLog)}, nil
+}
+
+func (s *nodeLogService) Stream(namespace, node, app string, options *models.AppLogOptions) (io.ReadCloser, error) {
+	return s.log.StreamAppLogs(namespace, node, app, options)
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const (
+	nodeOfflineClaimKeyPrefix = "baetyl-cloud:node-offline-claim:"
+	nodeOfflineKeyPrefix      = "baetyl-cloud:node-offline:"
+	// nodeOfflineKeptChecks the number of the checks the offline nodes of a namespace are kept for,
+	// so that the ones of the deleted namespaces expire
+	nodeOfflineKeptChecks = 10
+)
+
+//go:generate mockgen -destination=../mock/service/node_offline.go -package=service github.com/baetyl/baetyl-cloud/v2/service NodeOfflineService
+
+// NodeOfflineService tells whether the nodes are offline by the time of their last reports,
+// and emits the events when the nodes go offline or come back, except the nodes under maintenance
+type NodeOfflineService interface {
+	// Status returns the status of the node, online, offline, or uninstall if it never reported
+	Status(namespace string, reportTime time.Time) string
+	// Threshold returns how long the nodes of the namespace are considered online after their last reports
+	Threshold(namespace string) time.Duration
+	// Run checks the nodes of all namespaces periodically until stopped, each check is claimed by one of the replicas
+	Run(stop <-chan struct{})
+	// Close waits for the events being delivered to the webhooks
+	Close()
+}
+
+type nodeOfflineService struct {
+	cfg       config.NodeOffline
+	node      NodeService
+	namespace plugin.Namespace
+	sink      plugin.EventSink
+	webhook   WebhookService
+	// store keeps the claims of the checks and the offline nodes of each namespace found by the last check,
+	// which is shared by the replicas if it is of redis
+	store persist.CacheStore
+	log   *log.Logger
+}
+
+// NewNodeOfflineService NewNodeOfflineService
+func NewNodeOfflineService(config *config.CloudConfig) (NodeOfflineService, error) {
+	node, err := NewNodeService(config)
+	if err != nil {
+		return nil, err
+	}
+	ns, err := plugin.GetPlugin(config.Plugin.Resource)
+	if err != nil {
+		return nil, err
+	}
+	sink, err := plugin.GetPlugin(config.Plugin.EventSink)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := NewWebhookService(config)
+	if err != nil {
+		return nil, err
+	}
+	store, err := NewCacheStore(config.AdminServer.Cache, config.NodeOffline.Interval)
+	if err != nil {
+		return nil, err
+	}
+	return &nodeOfflineService{
+		cfg:       config.NodeOffline,
+		node:      node,
+		namespace: ns.(plugin.Namespace),
+		sink:      sink.(plugin.EventSink),
+		webhook:   webhook,
+		store:     store,
+		log:       log.With(log.Any("service", "nodeOffline")),
+	}, nil
+}
+
+func (s *nodeOfflineService) Status(namespace string, reportTime time.Time) string {
+	return nodeStatus(s.cfg, namespace, reportTime, time.Now())
+}
+
+func (s *nodeOfflineService) Threshold(namespace string) time.Duration {
+	return offlineThreshold(s.cfg, namespace)
+}
+
+func (s *nodeOfflineService) Run(stop <-chan struct{}) {
+	if !s.cfg.Enable || s.cfg.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if s.claim(now) {
+				s.check(now)
+			}
+		}
+	}
+}
+
+func (s *nodeOfflineService) Close() {
+	if s.webhook != nil {
+		s.webhook.Close()
+	}
+}
+
+// claim returns whether the check of the interval is claimed by this replica, the check is skipped if it fails
+// to be claimed, since it is done by the others or by the next interval
+func (s *nodeOfflineService) claim(now time.Time) bool {
+	slot := now.Truncate(s.cfg.Interval).UnixNano()
+	ok, err := AddCacheValue(s.store, fmt.Sprintf("%s%d", nodeOfflineClaimKeyPrefix, slot), true, s.cfg.Interval)
+	if err != nil {
+		s.log.Error("failed to claim the check of offline nodes", log.Error(err))
+		return false
+	}
+	return ok
+}
+
+// check compares the status of the nodes with the last check and emits the events of the changes.
+// The report times of a namespace are read from the cache at once, so a check costs a cache read per namespace.
+func (s *nodeOfflineService) check(now time.Time) {
+	nss, err := s.namespace.ListNamespace(&models.ListOptions{})
+	if err != nil {
+		s.log.Error("failed to list namespaces", log.Error(err))
+		return
+	}
+	for _, ns := range nss.Items {
+		times, err := s.node.ListReportTime(ns.Name)
+		if err != nil {
+			// the nodes of the namespace keep their last status, and are checked again next time
+			s.log.Error("failed to list report time of nodes", log.Any("namespace", ns.Name), log.Error(err))
+			continue
+		}
+		offline := map[string]bool{}
+		for name, t := range times {
+			if nodeStatus(s.cfg, ns.Name, t, now) == models.ReadyTypOffline {
+				offline[name] = true
+			}
+		}
+		var last map[string]bool
+		err = s.store.Get(nodeOfflineKeyPrefix+ns.Name, &last)
+		if err != nil && err != persist.ErrCacheMiss {
+			s.log.Error("failed to get offline nodes", log.Any("namespace", ns.Name), log.Error(err))
+			continue
+		}
+		first := err == persist.ErrCacheMiss
+		if err = s.store.Set(nodeOfflineKeyPrefix+ns.Name, offline, nodeOfflineKeptChecks*s.cfg.Interval); err != nil {
+			s.log.Error("failed to keep offline nodes", log.Any("namespace", ns.Name), log.Error(err))
+			continue
+		}
+		// the nodes already offline are not reported again when the namespace is checked for the first time
+		if first {
+			continue
+		}
+		for name := range offline {
+			if !last[name] && !s.inMaintenance(ns.Name, name) {
+				s.emit(ns.Name, name, models.EventNodeOffline,
+					fmt.Sprintf("the node has not reported since %s", times[name].UTC().Format(time.RFC3339)))
+			}
+		}
+		for name := range last {
+			// the deleted nodes are not reported
+			if _, exist := times[name]; exist && !offline[name] && !s.inMaintenance(ns.Name, name) {
+				s.emit(ns.Name, name, models.EventNodeOnline, "the node reported again")
+			}
+		}
+	}
+}
+
+// inMaintenance returns whether the alerts of the node are suppressed for maintenance, the node is read only when
+// its status changes, and it is alerted as usual if it fails to be read
+func (s *nodeOfflineService) inMaintenance(namespace, name string) bool {
+	node, err := s.node.Get(nil, namespace, name)
+	if err != nil {
+		s.log.Warn("failed to get node", log.Any("namespace", namespace), log.Any("name", name), log.Error(err))
+		return false
+	}
+	return models.GetNodeMaintenance(node) != nil
+}
+
+// nodeWebhookEvents the webhook events of the node events
+var nodeWebhookEvents = map[string]string{
+	models.EventNodeOffline: models.WebhookEventNodeOffline,
+	models.EventNodeOnline:  models.WebhookEventNodeOnline,
+}
+
+func (s *nodeOfflineService) emit(namespace, name, typ, message string) {
+	event := &models.Event{
+		Namespace: namespace,
+		Kind:      models.EventKindNode,
+		Name:      name,
+		Type:      typ,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+	if err := s.sink.SendEvent(event); err != nil {
+		s.log.Error("failed to send event", log.Any("namespace", namespace), log.Any("name", name),
+			log.Any("type", typ), log.Error(err))
+	}
+	if s.webhook != nil {
+		s.webhook.Notify(nodeWebhookEvents[typ], event)
+	}
+}
+
+// nodeStatus returns the status of the node by the time of its last report and the threshold of the namespace
+func nodeStatus(cfg config.NodeOffline, namespace string, reportTime, now time.Time) string {
+	if reportTime.IsZero() {
+		return models.ReadyTypeUninstall
+	}
+	if now.Sub(reportTime) > offlineThreshold(cfg, namespace) {
+		return models.ReadyTypOffline
+	}
+	return models.ReadyTypeOnline
+}
+
+// offlineThreshold returns the threshold of the namespace, or the global one if not set
+func offlineThreshold(cfg config.NodeOffline, namespace string) time.Duration {
+	if t, ok := cfg.Namespaces[namespace]; ok && t > 0 {
+		return t
+	}
+	return cfg.Threshold
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestNodeStatus(t *testing.T) {
+	cfg := config.NodeOffline{
+		Threshold:  time.Minute,
+		Namespaces: map[string]time.Duration{"slow": time.Hour, "zero": 0},
+	}
+	now := time.Now()
+	assert.Equal(t, models.ReadyTypeUninstall, nodeStatus(cfg, "default", time.Time{}, now))
+	assert.Equal(t, models.ReadyTypeOnline, nodeStatus(cfg, "default", now.Add(-30*time.Second), now))
+	assert.Equal(t, models.ReadyTypOffline, nodeStatus(cfg, "default", now.Add(-2*time.Minute), now))
+	assert.Equal(t, models.ReadyTypeOnline, nodeStatus(cfg, "slow", now.Add(-2*time.Minute), now))
+	assert.Equal(t, models.ReadyTypOffline, nodeStatus(cfg, "zero", now.Add(-2*time.Minute), now))
+
+	s := &nodeOfflineService{cfg: cfg}
+	assert.Equal(t, time.Hour, s.Threshold("slow"))
+	assert.Equal(t, time.Minute, s.Threshold("zero"))
+}
+
+func TestNodeOfflineCheck(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	sNode := ms.NewMockNodeService(mockCtl)
+	mNamespace := mockPlugin.NewMockResource(mockCtl)
+	mSink := mockPlugin.NewMockEventSink(mockCtl)
+	sWebhook := ms.NewMockWebhookService(mockCtl)
+	s := &nodeOfflineService{
+		cfg:       config.NodeOffline{Enable: true, Threshold: time.Minute, Interval: time.Second},
+		node:      sNode,
+		namespace: mNamespace,
+		sink:      mSink,
+		webhook:   sWebhook,
+		store:     persist.NewInMemoryStore(time.Minute),
+		log:       log.With(log.Any("service", "nodeOffline")),
+	}
+	offlineOf := func(ns string) map[string]bool {
+		var offline map[string]bool
+		assert.NoError(t, s.store.Get(nodeOfflineKeyPrefix+ns, &offline))
+		return offline
+	}
+	ns := "default"
+	nsList := &models.NamespaceList{Items: []models.Namespace{{Name: ns}}}
+	now := time.Now()
+	recent, stale := now.Add(-10*time.Second), now.Add(-10*time.Minute)
+	maintenance := map[string]bool{}
+	sNode.EXPECT().Get(nil, ns, gomock.Any()).DoAndReturn(func(_ interface{}, _, name string) (*specV1.Node, error) {
+		node := &specV1.Node{Name: name}
+		if maintenance[name] {
+			node.Attributes = map[string]interface{}{common.AttributeNodeMaintenance: &models.NodeMaintenance{Maintenance: true}}
+		}
+		return node, nil
+	}).AnyTimes()
+
+	// the first check seeds the status silently
+	mNamespace.EXPECT().ListNamespace(gomock.Any()).Return(nsList, nil).Times(1)
+	sNode.EXPECT().ListReportTime(ns).Return(map[string]time.Time{"n1": recent, "n2": stale, "n3": stale}, nil).Times(1)
+	s.check(now)
+	assert.Equal(t, map[string]bool{"n2": true, "n3": true}, offlineOf(ns))
+
+	// n1 goes offline, n2 comes back, n3 is deleted
+	var events []*models.Event
+	mNamespace.EXPECT().ListNamespace(gomock.Any()).Return(nsList, nil).Times(1)
+	sNode.EXPECT().ListReportTime(ns).Return(map[string]time.Time{"n1": stale, "n2": recent}, nil).Times(1)
+	mSink.EXPECT().SendEvent(gomock.Any()).DoAndReturn(func(e *models.Event) error {
+		events = append(events, e)
+		return nil
+	}).Times(2)
+	sWebhook.EXPECT().Notify(models.WebhookEventNodeOffline, gomock.Any()).Times(1)
+	sWebhook.EXPECT().Notify(models.WebhookEventNodeOnline, gomock.Any()).Times(1)
+	s.check(now)
+	assert.Len(t, events, 2)
+	for _, e := range events {
+		assert.Equal(t, ns, e.Namespace)
+		assert.Equal(t, models.EventKindNode, e.Kind)
+		switch e.Name {
+		case "n1":
+			assert.Equal(t, models.EventNodeOffline, e.Type)
+		case "n2":
+			assert.Equal(t, models.EventNodeOnline, e.Type)
+		default:
+			t.Errorf("unexpected event of node %s", e.Name)
+		}
+	}
+
+	// the status is kept if the report times fail to be read, and the errors of the sink are ignored
+	mNamespace.EXPECT().ListNamespace(gomock.Any()).Return(nsList, nil).Times(1)
+	sNode.EXPECT().ListReportTime(ns).Return(nil, fmt.Errorf("error")).Times(1)
+	s.check(now)
+	assert.Equal(t, map[string]bool{"n1": true}, offlineOf(ns))
+
+	mNamespace.EXPECT().ListNamespace(gomock.Any()).Return(nsList, nil).Times(1)
+	sNode.EXPECT().ListReportTime(ns).Return(map[string]time.Time{"n1": recent}, nil).Times(1)
+	mSink.EXPECT().SendEvent(gomock.Any()).Return(fmt.Errorf("error")).Times(1)
+	sWebhook.EXPECT().Notify(models.WebhookEventNodeOnline, gomock.Any()).Times(1)
+	s.check(now)
+	assert.Empty(t, offlineOf(ns))
+
+	// the nodes under maintenance are not alerted
+	maintenance["n1"] = true
+	mNamespace.EXPECT().ListNamespace(gomock.Any()).Return(nsList, nil).Times(1)
+	sNode.EXPECT().ListReportTime(ns).Return(map[string]time.Time{"n1": stale}, nil).Times(1)
+	s.check(now)
+	assert.Equal(t, map[string]bool{"n1": true}, offlineOf(ns))
+	mNamespace.EXPECT().ListNamespace(gomock.Any()).Return(nsList, nil).Times(1)
+	sNode.EXPECT().ListReportTime(ns).Return(map[string]time.Time{"n1": recent}, nil).Times(1)
+	s.check(now)
+	assert.Empty(t, offlineOf(ns))
+
+	// the replicas sharing the store check the nodes from the status kept by the others
+	other := *s
+	mNamespace.EXPECT().ListNamespace(gomock.Any()).Return(nsList, nil).Times(1)
+	sNode.EXPECT().ListReportTime(ns).Return(map[string]time.Time{"n2": stale}, nil).Times(1)
+	mSink.EXPECT().SendEvent(gomock.Any()).Return(nil).Times(1)
+	sWebhook.EXPECT().Notify(models.WebhookEventNodeOffline, gomock.Any()).Times(1)
+	other.check(now)
+	assert.Equal(t, map[string]bool{"n2": true}, offlineOf(ns))
+
+	mNamespace.EXPECT().ListNamespace(gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	s.check(now)
+}
+
+func TestNodeOfflineClaim(t *testing.T) {
+	store := persist.NewInMemoryStore(time.Minute)
+	cfg := config.NodeOffline{Enable: true, Threshold: time.Minute, Interval: time.Minute}
+	s := &nodeOfflineService{cfg: cfg, store: store, log: log.With(log.Any("service", "nodeOffline"))}
+	other := &nodeOfflineService{cfg: cfg, store: store, log: log.With(log.Any("service", "nodeOffline"))}
+	now := time.Now().Truncate(time.Minute)
+	// a check is claimed by one of the replicas sharing the store
+	assert.True(t, s.claim(now))
+	assert.False(t, other.claim(now.Add(time.Second)))
+	assert.False(t, s.claim(now.Add(time.Second)))
+	// and the next one is claimed again
+	assert.True(t, other.claim(now.Add(time.Minute)))
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// the resources recorded in the history of the node stats
+const (
+	statsCPU    = "cpu"
+	statsMemory = "memory"
+	statsDisk   = specV1.ResourceDisk
+)
+
+// ListStatsHistory ListStatsHistory
+func (n *NodeServiceImpl) ListStatsHistory(namespace, name string, from, to time.Time, step time.Duration) ([]models.NodeStatsPoint, error) {
+	if n.Metrics == nil {
+		return nil, common.Error(common.ErrNotSupported, common.Field("operation", "node stats history"))
+	}
+	points, err := n.Metrics.ListNodeStats(namespace, name, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if step <= 0 {
+		return points, nil
+	}
+	return downsampleStats(points, from, step), nil
+}
+
+// ListLatestStats ListLatestStats
+func (n *NodeServiceImpl) ListLatestStats(namespace string, names []string, since time.Time) (map[string]*models.NodeStatsPoint, error) {
+	if n.Metrics == nil {
+		return nil, common.Error(common.ErrNotSupported, common.Field("operation", "node stats"))
+	}
+	now := time.Now()
+	res := map[string]*models.NodeStatsPoint{}
+	for _, name := range names {
+		points, err := n.Metrics.ListNodeStats(namespace, name, since, now.Add(time.Second))
+		if err != nil {
+			return nil, err
+		}
+		if len(points) > 0 {
+			res[name] = &points[len(points)-1]
+		}
+	}
+	return res, nil
+}
+
+// recordStats records the resource usage in the report, the failures are only logged since the report is saved
+func (n *NodeServiceImpl) recordStats(namespace, name string, report specV1.Report) {
+	if n.Metrics == nil || report == nil {
+		return
+	}
+	point := nodeStatsPoint(report)
+	if point == nil {
+		return
+	}
+	if err := n.Metrics.WriteNodeStats(namespace, name, point); err != nil {
+		n.logger.Warn("failed to record the node stats", log.Any("namespace", namespace), log.Any("name", name), log.Error(err))
+	}
+}
+
+// nodeStatsPoint sums up the stats of the hosts in the report, it returns nil if the report has no stats
+func nodeStatsPoint(report specV1.Report) *models.NodeStatsPoint {
+	raw, ok := report[common.NodeStats]
+	if !ok || raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	fields := map[string]json.RawMessage{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	hosts := map[string]*specV1.NodeStats{}
+	_, usage := fields["usage"]
+	_, capacity := fields["capacity"]
+	if usage || capacity {
+		// the stats of the single host reported by the old cores
+		single := &specV1.NodeStats{}
+		if err = json.Unmarshal(data, single); err != nil {
+			return nil
+		}
+		hosts[""] = single
+	} else if err = json.Unmarshal(data, &hosts); err != nil {
+		return nil
+	}
+	point := &models.NodeStatsPoint{
+		Time:     time.Now().UTC(),
+		Usage:    map[string]float64{},
+		Capacity: map[string]float64{},
+		Percent:  map[string]float64{},
+	}
+	if t, ok := report[ReportTimeKey].(time.Time); ok {
+		point.Time = t
+	}
+	for _, s := range hosts {
+		if s == nil {
+			continue
+		}
+		addQuantity(point.Usage, statsCPU, s.Usage, true)
+		addQuantity(point.Capacity, statsCPU, s.Capacity, true)
+		addQuantity(point.Usage, statsMemory, s.Usage, false)
+		addQuantity(point.Capacity, statsMemory, s.Capacity, false)
+		if ext, ok := s.Extension.(map[string]interface{}); ok {
+			if v, ok := ext[specV1.KeyDiskUsed].(float64); ok {
+				point.Usage[statsDisk] += v
+			}
+			if v, ok := ext[specV1.KeyDiskTotal].(float64); ok {
+				point.Capacity[statsDisk] += v
+			}
+		}
+	}
+	for k, c := range point.Capacity {
+		if c > 0 {
+			point.Percent[k] = point.Usage[k] / c * 100
+		}
+	}
+	return point
+}
+
+func addQuantity(res map[string]float64, key string, values map[string]string, milli bool) {
+	q, err := resource.ParseQuantity(values[key])
+	if err != nil {
+		return
+	}
+	if milli {
+		res[key] += float64(q.MilliValue()) / 1000
+	} else {
+		res[key] += float64(q.Value())
+	}
+}
+
+// downsampleStats averages the points in each step since from, the point of a step is at its start
+func downsampleStats(points []models.NodeStatsPoint, from time.Time, step time.Duration) []models.NodeStatsPoint {
+	res := make([]models.NodeStatsPoint, 0)
+	for i := 0; i < len(points); {
+		start := from.Add(points[i].Time.Sub(from) / step * step)
+		j := i + 1
+		for j < len(points) && points[j].Time.Before(start.Add(step)) {
+			j++
+		}
+		res = append(res, models.NodeStatsPoint{
+			Time:     start,
+			Usage:    averageStats(points[i:j], func(p *models.NodeStatsPoint) map[string]float64 { return p.Usage }),
+			Capacity: averageStats(points[i:j], func(p *models.NodeStatsPoint) map[string]float64 { return p.Capacity }),
+			Percent:  averageStats(points[i:j], func(p *models.NodeStatsPoint) map[string]float64 { return p.Percent }),
+		})
+		i = j
+	}
+	return res
+}
+
+func averageStats(points []models.NodeStatsPoint, values func(*models.NodeStatsPoint) map[string]float64) map[string]float64 {
+	sums, counts := map[string]float64{}, map[string]int{}
+	for i := range points {
+		for k, v := range values(&points[i]) {
+			sums[k] += v
+			counts[k]++
+		}
+	}
+	for k := range sums {
+		sums[k] /= float64(counts[k])
+	}
+	return sums
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestNodeStatsPoint(t *testing.T) {
+	now := time.Now().UTC()
+	report := specV1.Report{
+		ReportTimeKey: now,
+		common.NodeStats: map[string]interface{}{
+			"host1": map[string]interface{}{
+				"usage":     map[string]interface{}{"cpu": "500m", "memory": "1Gi"},
+				"capacity":  map[string]interface{}{"cpu": "2", "memory": "4Gi"},
+				"extension": map[string]interface{}{specV1.KeyDiskUsed: float64(30), specV1.KeyDiskTotal: float64(100)},
+			},
+			"host2": map[string]interface{}{
+				"usage":    map[string]interface{}{"cpu": "1500m", "memory": "1Gi"},
+				"capacity": map[string]interface{}{"cpu": "2", "memory": "4Gi"},
+			},
+		},
+	}
+	point := nodeStatsPoint(report)
+	assert.Equal(t, now, point.Time)
+	assert.Equal(t, map[string]float64{"cpu": 2, "memory": 2 << 30, "disk": 30}, point.Usage)
+	assert.Equal(t, map[string]float64{"cpu": 4, "memory": 8 << 30, "disk": 100}, point.Capacity)
+	assert.Equal(t, map[string]float64{"cpu": 50, "memory": 25, "disk": 30}, point.Percent)
+
+	// the single host of the old cores
+	point = nodeStatsPoint(specV1.Report{common.NodeStats: map[string]interface{}{
+		"usage":    map[string]interface{}{"cpu": "1"},
+		"capacity": map[string]interface{}{"cpu": "4"},
+	}})
+	assert.Equal(t, map[string]float64{"cpu": 25}, point.Percent)
+
+	assert.Nil(t, nodeStatsPoint(specV1.Report{}))
+}
+
+func TestListStatsHistory(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	metrics := mockPlugin.NewMockMetricsStore(mockCtl)
+	ns := &NodeServiceImpl{Metrics: metrics, logger: log.L()}
+
+	from := time.Unix(1700000000, 0).UTC()
+	to := from.Add(time.Hour)
+	point := func(d time.Duration, cpu float64) models.NodeStatsPoint {
+		return models.NodeStatsPoint{Time: from.Add(d), Usage: map[string]float64{"cpu": cpu}, Capacity: map[string]float64{}, Percent: map[string]float64{"cpu": cpu * 50}}
+	}
+	points := []models.NodeStatsPoint{point(time.Minute, 1), point(4*time.Minute, 2), point(6*time.Minute, 0.5), point(20*time.Minute, 1)}
+	metrics.EXPECT().ListNodeStats("default", "node01", from, to).Return(points, nil).Times(2)
+
+	res, err := ns.ListStatsHistory("default", "node01", from, to, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, points, res)
+
+	res, err = ns.ListStatsHistory("default", "node01", from, to, 5*time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, res, 3)
+	assert.Equal(t, from, res[0].Time)
+	assert.Equal(t, 1.5, res[0].Usage["cpu"])
+	assert.Equal(t, 75.0, res[0].Percent["cpu"])
+	assert.Equal(t, from.Add(5*time.Minute), res[1].Time)
+	assert.Equal(t, 0.5, res[1].Usage["cpu"])
+	assert.Equal(t, from.Add(20*time.Minute), res[2].Time)
+
+	metrics.EXPECT().ListNodeStats("default", "node01", from, to).Return(nil, errors.New("error")).Times(1)
+	_, err = ns.ListStatsHistory("default", "node01", from, to, 0)
+	assert.Error(t, err)
+
+	// recorded on report
+	metrics.EXPECT().WriteNodeStats("default", "node01", gomock.Any()).Return(errors.New("error")).Times(1)
+	ns.recordStats("default", "node01", specV1.Report{common.NodeStats: map[string]interface{}{}})
+	ns.recordStats("default", "node01", specV1.Report{})
+
+	ns.Metrics = nil
+	_, err = ns.ListStatsHistory("default", "node01", from, to, 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The operation (node stats history) is not supported.")
+}
+
+func TestListLatestStats(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	metrics := mockPlugin.NewMockMetricsStore(mockCtl)
+	ns := &NodeServiceImpl{Metrics: metrics, logger: log.L()}
+
+	since := time.Now().Add(-5 * time.Minute)
+	points := []models.NodeStatsPoint{
+		{Time: since.Add(time.Minute), Percent: map[string]float64{"cpu": 10}},
+		{Time: since.Add(2 * time.Minute), Percent: map[string]float64{"cpu": 20}},
+	}
+	metrics.EXPECT().ListNodeStats("default", "node01", since, gomock.Any()).Return(points, nil).Times(1)
+	metrics.EXPECT().ListNodeStats("default", "node02", since, gomock.Any()).Return(nil, nil).Times(1)
+	res, err := ns.ListLatestStats("default", []string{"node01", "node02"}, since)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*models.NodeStatsPoint{"node01": &points[1]}, res)
+
+	metrics.EXPECT().ListNodeStats("default", "node01", since, gomock.Any()).Return(nil, errors.New("error")).Times(1)
+	_, err = ns.ListLatestStats("default", []string{"node01"}, since)
+	assert.Error(t, err)
+
+	ns.Metrics = nil
+	_, err = ns.ListLatestStats("default", []string{"node01"}, since)
+	assert.Error(t, err)
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/baetyl/baetyl-cloud/v2/cachemsg"
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
@@ -130,10 +131,11 @@ func TestFilterNodeService_List(t *testing.T) {
 	list := genNodeList(t, ns)
 
 	nsvc := NodeServiceImpl{
-		Shadow: mockObject.shadow,
-		Node:   mockObject.node,
-		Cache:  mockObject.cache,
-		logger: log.With(log.Any("service", "node")),
+		Shadow:  mockObject.shadow,
+		Node:    mockObject.node,
+		Cache:   mockObject.cache,
+		Offline: config.NodeOffline{Threshold: 30 * time.Second},
+		logger:  log.With(log.Any("service", "node")),
 	}
 
 	mockObject.node.EXPECT().ListNode(nil, ns, s).Return(nil, fmt.Errorf("error"))
@@ -187,6 +189,51 @@ func TestFilterNodeService_List(t *testing.T) {
 	assert.Equal(t, 2, len(res.Items))
 	assert.Equal(t, "node02", res.Items[0].Name)
 
+	// filter by the offline threshold of the namespace
+	nsvc.Offline = config.NodeOffline{Threshold: time.Minute}
+	s = &models.ListOptions{NodeOptions: models.NodeOptions{Status: models.ReadyTypOffline}}
+	res, err = nsvc.List(ns, s)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(res.Items))
+	assert.Equal(t, "node02", res.Items[0].Name)
+
+	nsvc.Offline.Namespaces = map[string]time.Duration{ns: 5 * time.Minute}
+	res, err = nsvc.List(ns, s)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(res.Items))
+
+	// the ready filter agrees with the status filter
+	for _, threshold := range []time.Duration{time.Minute, 5 * time.Minute} {
+		nsvc.Offline.Namespaces = map[string]time.Duration{ns: threshold}
+		for _, status := range []string{models.ReadyTypeOnline, models.ReadyTypOffline, models.ReadyTypeUninstall} {
+			list = genNodeList(t, ns)
+			byStatus, err := nsvc.List(ns, &models.ListOptions{NodeOptions: models.NodeOptions{Status: status}})
+			assert.NoError(t, err)
+			list = genNodeList(t, ns)
+			byReady, err := nsvc.List(ns, &models.ListOptions{NodeOptions: models.NodeOptions{Ready: status}})
+			assert.NoError(t, err)
+			assert.Equal(t, byStatus.Items, byReady.Items, status)
+		}
+	}
+	nsvc.Offline.Namespaces = nil
+	list = genNodeList(t, ns)
+	res, err = nsvc.List(ns, &models.ListOptions{NodeOptions: models.NodeOptions{Ready: models.ReadyTypOffline}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(res.Items))
+	assert.Equal(t, "node02", res.Items[0].Name)
+
+	// the nodes sorted explicitly keep the order of storage, rather than ranking the online ones first
+	list = genNodeList(t, ns)
+	list.Items[0], list.Items[1] = list.Items[1], list.Items[0]
+	res, err = nsvc.List(ns, &models.ListOptions{SortBy: models.SortByName, Order: models.SortOrderDesc})
+	assert.NoError(t, err)
+	assert.Equal(t, "node02", res.Items[0].Name)
+
+	list = genNodeList(t, ns)
+	list.Items[0], list.Items[1] = list.Items[1], list.Items[0]
+	res, err = nsvc.List(ns, &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "node01", res.Items[0].Name)
 }
 
 func genNodeList(t *testing.T, ns string) models.NodeList {
@@ -378,6 +425,7 @@ func TestUpdateNodeAppVersion(t *testing.T) {
 	ss := NodeServiceImpl{
 		IndexService: mockIndexService,
 		Shadow:       mockObject.shadow,
+		AppHistory:   mockObject.appHis,
 		Node:         mockObject.node,
 		App:          mockObject.app,
 	}
@@ -495,6 +543,7 @@ func TestUpdateNodeAppVersion(t *testing.T) {
 	assert.NotNil(t, err)
 
 	mockObject.shadow.EXPECT().UpdateDesires(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockObject.appHis.EXPECT().CreateNodeDeployRecords(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	app.Labels = map[string]string{
 		common.LabelSystem: app.Name,
 	}
@@ -509,6 +558,7 @@ func TestDeleteNodeAppVersion(t *testing.T) {
 	ss := NodeServiceImpl{
 		IndexService: mockIndexService,
 		Shadow:       mockObject.shadow,
+		AppHistory:   mockObject.appHis,
 		Node:         mockObject.node,
 		App:          mockObject.app,
 	}
@@ -630,6 +680,7 @@ func TestDeleteNodeAppVersion(t *testing.T) {
 	}
 	mockObject.node.EXPECT().ListNode(nil, node.Namespace, gomock.Any()).Return(nodeList, nil).AnyTimes()
 	mockObject.shadow.EXPECT().UpdateDesires(gomock.Any(), gomock.Any()).Return(nil)
+	mockObject.appHis.EXPECT().CreateNodeDeployRecords(gomock.Any(), gomock.Any()).Return(nil)
 	_, err = ss.DeleteNodeAppVersion(nil, node.Namespace, app)
 	assert.NoError(t, err)
 }
@@ -836,9 +887,10 @@ func TestUpdateDesired(t *testing.T) {
 	defer mockObject.Close()
 
 	ns := NodeServiceImpl{
-		Shadow: mockObject.shadow,
-		Node:   mockObject.node,
-		App:    mockObject.app,
+		Shadow:     mockObject.shadow,
+		AppHistory: mockObject.appHis,
+		Node:       mockObject.node,
+		App:        mockObject.app,
 	}
 
 	namespace := "test"
@@ -857,9 +909,63 @@ func TestUpdateDesired(t *testing.T) {
 
 	mockObject.shadow.EXPECT().ListShadowByNames(gomock.Any(), namespace, names).Return(shadows, nil)
 	mockObject.shadow.EXPECT().UpdateDesires(gomock.Any(), shadows).Return(nil)
+	var records []*models.NodeDeployRecord
+	mockObject.appHis.EXPECT().CreateNodeDeployRecords(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, r []*models.NodeDeployRecord) error {
+		records = r
+		return nil
+	})
 
 	err = ns.UpdateDesire(nil, namespace, names, app, RefreshNodeDesireByApp)
 	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, namespace, records[0].Namespace)
+	assert.Equal(t, names[0], records[0].Node)
+	assert.Equal(t, models.NodeDeployActionDeploy, records[0].Action)
+	assert.Equal(t, app.Name, records[0].App.Name)
+	assert.Equal(t, app.Version, records[0].App.Version)
+	assert.Contains(t, records[0].Apps, specV1.AppInfo{Name: app.Name, Version: app.Version})
+	assert.NotNil(t, records[0].Desire)
+	prev := app.Version
+
+	// unchanged nodes are not recorded
+	mockObject.shadow.EXPECT().ListShadowByNames(gomock.Any(), namespace, names).Return(shadows, nil)
+	mockObject.shadow.EXPECT().UpdateDesires(gomock.Any(), shadows).Return(nil)
+	mockObject.appHis.EXPECT().CreateNodeDeployRecords(gomock.Any(), gomock.Len(0)).Return(nil)
+	err = ns.UpdateDesire(nil, namespace, names, app, RefreshNodeDesireByApp)
+	assert.NoError(t, err)
+
+	// undeploy
+	mockObject.shadow.EXPECT().ListShadowByNames(gomock.Any(), namespace, names).Return(shadows, nil)
+	mockObject.shadow.EXPECT().UpdateDesires(gomock.Any(), shadows).Return(nil)
+	mockObject.appHis.EXPECT().CreateNodeDeployRecords(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, r []*models.NodeDeployRecord) error {
+		records = r
+		return nil
+	})
+	err = ns.UpdateDesire(nil, namespace, names, app, DeleteNodeDesireByApp)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, models.NodeDeployActionUndeploy, records[0].Action)
+	assert.Equal(t, prev, records[0].App.PrevVersion)
+	assert.Empty(t, records[0].App.Version)
+}
+
+func TestNodeDeployHistory(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns := NodeServiceImpl{AppHistory: mockObject.appHis}
+	filter := &models.Filter{PageNo: 1, PageSize: 10}
+	list := &models.NodeDeployRecordList{Total: 1, Items: []models.NodeDeployRecord{{ID: 1}}}
+	mockObject.appHis.EXPECT().ListNodeDeployRecord(nil, "default", "node01", filter).Return(list, nil)
+	res, err := ns.ListDeployHistory("default", "node01", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, list, res)
+
+	record := &models.NodeDeployRecord{ID: 1, Desire: specV1.Desire{}}
+	mockObject.appHis.EXPECT().GetNodeDeployRecord(nil, "default", "node01", int64(1)).Return(record, nil)
+	rec, err := ns.GetDeployRecord("default", "node01", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, record, rec)
 }
 
 func TestRematchApplicationForNode(t *testing.T) {
@@ -915,10 +1021,16 @@ func TestRematchApplicationForNode(t *testing.T) {
 	names := []string{"app02", "app03"}
 	//mockObject.matcher.EXPECT().IsLabelMatch("env=dev", labels).Return(true, nil).Times(2)
 	//mockObject.matcher.EXPECT().IsLabelMatch("env=test", labels).Return(false, nil)
-	desire, appNames := ns.rematchApplicationsForNode(apps, labels)
+	desire, appNames := ns.rematchApplicationsForNode(apps, labels, false)
 	assert.Equal(t, expect, desire)
 	assert.Equal(t, names, appNames)
 
+	// the drained node only matches the system apps
+	desire, appNames = ns.rematchApplicationsForNode(apps, labels, true)
+	assert.Equal(t, expect[common.DesiredSysApplications], desire[common.DesiredSysApplications])
+	assert.Empty(t, desire[common.DesiredApplications])
+	assert.Equal(t, []string{"app02"}, appNames)
+
 }
 
 func TestGetNodeProperties(t *testing.T) {
@@ -978,9 +1090,10 @@ func TestUpdateNodeProperties(t *testing.T) {
 	defer mockObject.Close()
 
 	ns := NodeServiceImpl{
-		Node:   mockObject.node,
-		Shadow: mockObject.shadow,
-		logger: log.With(log.Any("service", "node")),
+		Node:       mockObject.node,
+		Shadow:     mockObject.shadow,
+		AppHistory: mockObject.appHis,
+		logger:     log.With(log.Any("service", "node")),
 	}
 
 	node := &v1.Node{Attributes: map[string]interface{}{
@@ -1012,7 +1125,16 @@ func TestUpdateNodeProperties(t *testing.T) {
 	mockObject.shadow.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(shadow, nil)
 	mockObject.shadow.EXPECT().UpdateDesire(gomock.Any(), gomock.Any()).Return(nil)
 	mockObject.node.EXPECT().UpdateNode(nil, gomock.Any(), gomock.Any()).Return(nil, nil)
-	res, err := ns.UpdateNodeProperties("default", "abc", nodeProps)
+	mockObject.appHis.EXPECT().CreateNodePropertyRecords(nil, gomock.Any()).DoAndReturn(
+		func(_ interface{}, records []*models.NodePropertyRecord) error {
+			assert.Len(t, records, 1)
+			assert.Equal(t, "a", records[0].Property)
+			assert.Equal(t, "2", records[0].Value)
+			assert.Equal(t, models.NodePropertyDesire, records[0].Type)
+			assert.Equal(t, "user01", records[0].Actor)
+			return nil
+		})
+	res, err := ns.UpdateNodeProperties("default", "abc", "user01", nodeProps)
 	assert.NoError(t, err)
 	expect := &models.NodeProperties{
 		State: models.NodePropertiesState{
@@ -1043,7 +1165,15 @@ func TestUpdateNodeProperties(t *testing.T) {
 	mockObject.shadow.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(shadow, nil)
 	mockObject.shadow.EXPECT().UpdateDesire(gomock.Any(), gomock.Any()).Return(nil)
 	mockObject.node.EXPECT().UpdateNode(nil, gomock.Any(), gomock.Any()).Return(nil, nil)
-	res, err = ns.UpdateNodeProperties("default", "abc", nodeProps)
+	// the deleted property is recorded with a nil value, and the failure to record doesn't fail the update
+	mockObject.appHis.EXPECT().CreateNodePropertyRecords(nil, gomock.Any()).DoAndReturn(
+		func(_ interface{}, records []*models.NodePropertyRecord) error {
+			assert.Len(t, records, 1)
+			assert.Equal(t, "abc", records[0].Node)
+			assert.Nil(t, records[0].Value)
+			return errors.New("failed to record")
+		})
+	res, err = ns.UpdateNodeProperties("default", "abc", "user01", nodeProps)
 	assert.NoError(t, err)
 	expect = &models.NodeProperties{
 		State: models.NodePropertiesState{
@@ -1062,29 +1192,82 @@ func TestUpdateNodeProperties(t *testing.T) {
 	assert.Equal(t, expect, res)
 
 	mockObject.node.EXPECT().GetNode(nil, gomock.Any(), gomock.Any()).Return(nil, errors.New("failed to get node"))
-	_, err = ns.UpdateNodeProperties("default", "abc", nodeProps)
+	_, err = ns.UpdateNodeProperties("default", "abc", "user01", nodeProps)
 	assert.Error(t, err)
 
 	mockObject.node.EXPECT().GetNode(nil, gomock.Any(), gomock.Any()).Return(node, nil)
 	mockObject.shadow.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("failed to get Shadow"))
-	_, err = ns.UpdateNodeProperties("default", "abc", nodeProps)
+	_, err = ns.UpdateNodeProperties("default", "abc", "user01", nodeProps)
 	assert.Error(t, err)
 
 	mockObject.node.EXPECT().GetNode(nil, gomock.Any(), gomock.Any()).Return(node, nil)
 	mockObject.shadow.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(shadow, nil)
 	mockObject.shadow.EXPECT().UpdateDesire(gomock.Any(), gomock.Any()).Return(errors.New("failed to update desire"))
 	//mockObject.node.EXPECT().UpdateNode(nil, gomock.Any(), gomock.Any()).Return(nil, nil)
-	_, err = ns.UpdateNodeProperties("default", "abc", nodeProps)
+	_, err = ns.UpdateNodeProperties("default", "abc", "user01", nodeProps)
 	assert.Error(t, err)
 
 	mockObject.node.EXPECT().GetNode(nil, gomock.Any(), gomock.Any()).Return(node, nil)
 	mockObject.shadow.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(shadow, nil)
 	mockObject.shadow.EXPECT().UpdateDesire(gomock.Any(), gomock.Any()).Return(nil)
 	mockObject.node.EXPECT().UpdateNode(nil, gomock.Any(), gomock.Any()).Return(nil, errors.New("failed to update node"))
-	_, err = ns.UpdateNodeProperties("default", "abc", nodeProps)
+	_, err = ns.UpdateNodeProperties("default", "abc", "user01", nodeProps)
 	assert.Error(t, err)
 }
 
+func TestUpdateReportNodeProperties(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns := NodeServiceImpl{
+		Node:       mockObject.node,
+		Shadow:     mockObject.shadow,
+		AppHistory: mockObject.appHis,
+		logger:     log.With(log.Any("service", "node")),
+	}
+	node := &v1.Node{Attributes: map[string]interface{}{}}
+	shadow := &models.Shadow{
+		Report: map[string]interface{}{
+			common.NodeProps: map[string]interface{}{"a": "1", "b": "1"},
+		},
+	}
+	report := v1.Report{common.NodeProps: map[string]interface{}{"a": "2", "b": "1", "c": "1"}}
+
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(node, nil)
+	mockObject.node.EXPECT().UpdateNode(nil, "default", gomock.Any()).Return(nil, nil)
+	mockObject.appHis.EXPECT().CreateNodePropertyRecords(nil, gomock.Any()).DoAndReturn(
+		func(_ interface{}, records []*models.NodePropertyRecord) error {
+			assert.Len(t, records, 2)
+			assert.Equal(t, "a", records[0].Property)
+			assert.Equal(t, "2", records[0].Value)
+			assert.Equal(t, "c", records[1].Property)
+			assert.Equal(t, models.NodePropertyReport, records[1].Type)
+			assert.Equal(t, models.NodePropertyActorNode, records[1].Actor)
+			return nil
+		})
+	assert.NoError(t, ns.updateReportNodeProperties("default", "abc", report, shadow))
+	assert.Equal(t, report[common.NodeProps], shadow.Report[common.NodeProps])
+
+	// the unchanged properties are not recorded
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(node, nil)
+	mockObject.node.EXPECT().UpdateNode(nil, "default", gomock.Any()).Return(nil, nil)
+	assert.NoError(t, ns.updateReportNodeProperties("default", "abc", report, shadow))
+}
+
+func TestListPropertyHistory(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns := NodeServiceImpl{AppHistory: mockObject.appHis}
+	params := &models.NodePropertyRecordListOptions{Property: "a"}
+	list := &models.NodePropertyRecordList{Total: 1, NodePropertyRecordListOptions: params,
+		Items: []models.NodePropertyRecord{{Property: "a", Value: "1"}}}
+	mockObject.appHis.EXPECT().ListNodePropertyRecord(nil, "default", "abc", params).Return(list, nil)
+	res, err := ns.ListPropertyHistory("default", "abc", params)
+	assert.NoError(t, err)
+	assert.Equal(t, list, res)
+}
+
 func TestUpdateNodeMode(t *testing.T) {
 	mockObject := InitMockEnvironment(t)
 	defer mockObject.Close()
@@ -1110,6 +1293,39 @@ func TestUpdateNodeMode(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestUpdateNodeTags(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns := NodeServiceImpl{
+		Node:   mockObject.node,
+		logger: log.With(log.Any("service", "node")),
+	}
+	node := &v1.Node{
+		Name:        "abc",
+		Labels:      map[string]string{"a": "b"},
+		Annotations: map[string]string{"a": "b", common.AnnotationNodeTagPrefix + "old": "x"},
+	}
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(node, nil)
+	mockObject.node.EXPECT().UpdateNode(nil, "default", gomock.Any()).DoAndReturn(func(_ interface{}, _ string, nodes []*v1.Node) ([]*v1.Node, error) {
+		assert.Equal(t, map[string]string{"a": "b", common.AnnotationNodeTagPrefix + "owner": "alice"}, nodes[0].Annotations)
+		assert.Equal(t, map[string]string{"a": "b"}, nodes[0].Labels)
+		return nodes, nil
+	})
+	tags, err := ns.UpdateNodeTags("default", "abc", map[string]string{"owner": "alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "alice"}, tags)
+
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(nil, errors.New("failed to get node"))
+	_, err = ns.UpdateNodeTags("default", "abc", nil)
+	assert.Error(t, err)
+
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(node, nil)
+	mockObject.node.EXPECT().UpdateNode(nil, "default", gomock.Any()).Return(nil, errors.New("failed to update node"))
+	_, err = ns.UpdateNodeTags("default", "abc", nil)
+	assert.Error(t, err)
+}
+
 func copyDesire(src *v1.Desire, dst *v1.Desire) {
 	var apps []specV1.AppInfo
 	dstApps := src.AppInfos(false)
@@ -1229,3 +1445,67 @@ func TestFilterNodeListByNodeSelector(t *testing.T) {
 	res := filterNodeListByNodeSelector(list)
 	assert.EqualValues(t, expect, res)
 }
+
+func TestUpdateNodeAttributes(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns := NodeServiceImpl{
+		Node:   mockObject.node,
+		logger: log.With(log.Any("service", "node")),
+	}
+	node := &v1.Node{
+		Name:       "abc",
+		Attributes: map[string]interface{}{"a": "b", "c": "d"},
+	}
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(node, nil)
+	mockObject.node.EXPECT().UpdateNode(nil, "default", gomock.Any()).DoAndReturn(func(_ interface{}, _ string, nodes []*v1.Node) ([]*v1.Node, error) {
+		return nodes, nil
+	})
+	res, err := ns.UpdateNodeAttributes("default", "abc", map[string]interface{}{"a": "x", "c": nil, "e": "f"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "x", "e": "f"}, res.Attributes)
+
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(nil, errors.New("failed to get node"))
+	_, err = ns.UpdateNodeAttributes("default", "abc", nil)
+	assert.Error(t, err)
+}
+
+func TestUpdateNodeAttributesIf(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns := NodeServiceImpl{
+		Node:   mockObject.node,
+		logger: log.With(log.Any("service", "node")),
+	}
+	check := func(node *v1.Node) error {
+		if node.Attributes["a"] != "b" {
+			return errors.New("changed")
+		}
+		return nil
+	}
+
+	// the update succeeds after a concurrent change not affecting the check
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(&v1.Node{Name: "abc", Version: "1", Attributes: map[string]interface{}{"a": "b"}}, nil)
+	mockObject.node.EXPECT().UpdateNodeIfVersion(nil, "default", gomock.Any(), "1").Return(false, nil)
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(&v1.Node{Name: "abc", Version: "2", Attributes: map[string]interface{}{"a": "b"}}, nil)
+	mockObject.node.EXPECT().UpdateNodeIfVersion(nil, "default", gomock.Any(), "2").Return(true, nil)
+	res, err := ns.UpdateNodeAttributesIf("default", "abc", check, map[string]interface{}{"c": "d"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "b", "c": "d"}, res.Attributes)
+
+	// the check fails against the node changed concurrently
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(&v1.Node{Name: "abc", Version: "1", Attributes: map[string]interface{}{"a": "b"}}, nil)
+	mockObject.node.EXPECT().UpdateNodeIfVersion(nil, "default", gomock.Any(), "1").Return(false, nil)
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(&v1.Node{Name: "abc", Version: "2", Attributes: map[string]interface{}{"a": "x"}}, nil)
+	_, err = ns.UpdateNodeAttributesIf("default", "abc", check, map[string]interface{}{"c": "d"})
+	assert.EqualError(t, err, "changed")
+
+	// too many concurrent changes
+	mockObject.node.EXPECT().GetNode(nil, "default", "abc").Return(&v1.Node{Name: "abc", Version: "1", Attributes: map[string]interface{}{"a": "b"}}, nil).Times(casRetryTimes + 1)
+	mockObject.node.EXPECT().UpdateNodeIfVersion(nil, "default", gomock.Any(), "1").Return(false, nil).Times(casRetryTimes)
+	_, err = ns.UpdateNodeAttributesIf("default", "abc", check, map[string]interface{}{"c": "d"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has been modified")
+}
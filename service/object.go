@@ -20,12 +20,25 @@ type ObjectService interface {
 	PutInternalObjectFromURLIfNotExist(userID, bucket, object, url, source string) error
 	GenInternalObjectURL(userID string, bucket, object, source string) (*models.ObjectURL, error)
 	GenInternalObjectPutURL(userID string, bucket, object, source string) (*models.ObjectURL, error)
+	// PresignInternalObjectURL and PresignInternalObjectPutURL sign the urls with options, which fail if not supported by the source
+	PresignInternalObjectURL(userID string, bucket, object, source string, opts *models.ObjectURLOptions) (*models.ObjectURL, error)
+	PresignInternalObjectPutURL(userID string, bucket, object, source string, opts *models.ObjectURLOptions) (*models.ObjectURL, error)
 	PutInternalObject(userID, bucket, name, source string, b []byte) error
+	// GetInternalBucketLifecycle and PutInternalBucketLifecycle fail with ErrNotSupported if the source has no lifecycle support
+	GetInternalBucketLifecycle(userID, bucket, source string) (*models.BucketLifecycle, error)
+	PutInternalBucketLifecycle(userID, bucket, source string, lifecycle *models.BucketLifecycle) error
 	HeadInternalObject(userID, bucket, name, source string) (*models.ObjectMeta, error)
+	// CheckObjectUpload is called before the put url is signed, and CompleteObjectUpload after the object is uploaded,
+	// which removes or quarantines the object if the upload hook rejects it
+	CheckObjectUpload(upload *models.ObjectUpload) error
+	CompleteObjectUpload(upload *models.ObjectUpload) (*models.ObjectUploadVerdict, error)
 
 	ListExternalBuckets(info models.ExternalObjectInfo, source string) ([]models.Bucket, error)
 	ListExternalBucketObjects(info models.ExternalObjectInfo, bucket, source string) (*models.ListObjectsResult, error)
 	GenExternalObjectURL(info models.ExternalObjectInfo, bucket, object, source string) (*models.ObjectURL, error)
+	PresignExternalObjectURL(info models.ExternalObjectInfo, bucket, object, source string, opts *models.ObjectURLOptions) (*models.ObjectURL, error)
+	GetExternalBucketLifecycle(info models.ExternalObjectInfo, bucket, source string) (*models.BucketLifecycle, error)
+	PutExternalBucketLifecycle(info models.ExternalObjectInfo, bucket, source string, lifecycle *models.BucketLifecycle) error
 	CreateExternalBucket(info models.ExternalObjectInfo, bucket, permission, source string) error
 	PutExternalObject(info models.ExternalObjectInfo, bucket, name, source string, b []byte) error
 	PutExternalObjectFromURL(info models.ExternalObjectInfo, bucket, name, url, source string) error
@@ -36,6 +49,9 @@ type ObjectService interface {
 
 type objectService struct {
 	objects map[string]plugin.Object
+	// hook is optional, the uploads are always accepted if nil
+	hook             plugin.ObjectUploadHook
+	quarantinePrefix string
 }
 
 // NewObjectService NewObjectService
@@ -48,8 +64,18 @@ func NewObjectService(config *config.CloudConfig) (ObjectService, error) {
 		}
 		objects[v] = cs.(plugin.Object)
 	}
+	var hook plugin.ObjectUploadHook
+	if config.Plugin.ObjectUploadHook != "" {
+		h, err := plugin.GetPlugin(config.Plugin.ObjectUploadHook)
+		if err != nil {
+			return nil, err
+		}
+		hook = h.(plugin.ObjectUploadHook)
+	}
 	return &objectService{
-		objects: objects,
+		objects:          objects,
+		hook:             hook,
+		quarantinePrefix: config.ObjectUpload.QuarantinePrefix,
 	}, nil
 }
 
@@ -131,6 +157,94 @@ func (c *objectService) GenInternalObjectPutURL(userID string, bucket, object, s
 	return objectPlugin.GenInternalPutObjectURL(userID, bucket, object)
 }
 
+// PresignInternalObjectURL PresignInternalObjectURL
+func (c *objectService) PresignInternalObjectURL(userID string, bucket, object, source string, opts *models.ObjectURLOptions) (*models.ObjectURL, error) {
+	presigner, err := c.getPresigner(source)
+	if err != nil {
+		return nil, err
+	}
+	return presigner.PresignInternalObjectURL(userID, bucket, object, opts)
+}
+
+// PresignInternalObjectPutURL PresignInternalObjectPutURL
+func (c *objectService) PresignInternalObjectPutURL(userID string, bucket, object, source string, opts *models.ObjectURLOptions) (*models.ObjectURL, error) {
+	presigner, err := c.getPresigner(source)
+	if err != nil {
+		return nil, err
+	}
+	return presigner.PresignInternalPutObjectURL(userID, bucket, object, opts)
+}
+
+// PresignExternalObjectURL PresignExternalObjectURL
+func (c *objectService) PresignExternalObjectURL(info models.ExternalObjectInfo, bucket, object, source string, opts *models.ObjectURLOptions) (*models.ObjectURL, error) {
+	presigner, err := c.getPresigner(source)
+	if err != nil {
+		return nil, err
+	}
+	return presigner.PresignExternalObjectURL(info, bucket, object, opts)
+}
+
+func (c *objectService) getPresigner(source string) (plugin.ObjectPresigner, error) {
+	objectPlugin, ok := c.objects[source]
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) is not supported", source)))
+	}
+	presigner, ok := objectPlugin.(plugin.ObjectPresigner)
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the source (%s) does not support signing the urls with the expiry or constraints", source)))
+	}
+	return presigner, nil
+}
+
+// GetInternalBucketLifecycle GetInternalBucketLifecycle
+func (c *objectService) GetInternalBucketLifecycle(userID, bucket, source string) (*models.BucketLifecycle, error) {
+	lifecycle, err := c.getLifecycle(source)
+	if err != nil {
+		return nil, err
+	}
+	return lifecycle.GetInternalBucketLifecycle(userID, bucket)
+}
+
+// PutInternalBucketLifecycle PutInternalBucketLifecycle
+func (c *objectService) PutInternalBucketLifecycle(userID, bucket, source string, lifecycle *models.BucketLifecycle) error {
+	l, err := c.getLifecycle(source)
+	if err != nil {
+		return err
+	}
+	return l.PutInternalBucketLifecycle(userID, bucket, lifecycle)
+}
+
+// GetExternalBucketLifecycle GetExternalBucketLifecycle
+func (c *objectService) GetExternalBucketLifecycle(info models.ExternalObjectInfo, bucket, source string) (*models.BucketLifecycle, error) {
+	lifecycle, err := c.getLifecycle(source)
+	if err != nil {
+		return nil, err
+	}
+	return lifecycle.GetExternalBucketLifecycle(info, bucket)
+}
+
+// PutExternalBucketLifecycle PutExternalBucketLifecycle
+func (c *objectService) PutExternalBucketLifecycle(info models.ExternalObjectInfo, bucket, source string, lifecycle *models.BucketLifecycle) error {
+	l, err := c.getLifecycle(source)
+	if err != nil {
+		return err
+	}
+	return l.PutExternalBucketLifecycle(info, bucket, lifecycle)
+}
+
+func (c *objectService) getLifecycle(source string) (plugin.ObjectLifecycle, error) {
+	objectPlugin, ok := c.objects[source]
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) is not supported", source)))
+	}
+	lifecycle, ok := objectPlugin.(plugin.ObjectLifecycle)
+	if !ok {
+		return nil, common.Error(common.ErrNotSupported, common.Field("operation", "bucket lifecycle"), common.Field("source", source))
+	}
+	return lifecycle, nil
+}
+
 // ListExternalBuckets ListExternalBuckets
 func (c *objectService) ListExternalBuckets(info models.ExternalObjectInfo, source string) ([]models.Bucket, error) {
 	objectPlugin, ok := c.objects[source]
@@ -174,6 +288,63 @@ func (c *objectService) HeadInternalObject(userID, bucket, name, source string)
 	return objectPlugin.HeadInternalObject(userID, bucket, name)
 }
 
+// CheckObjectUpload CheckObjectUpload
+func (c *objectService) CheckObjectUpload(upload *models.ObjectUpload) error {
+	if _, ok := c.objects[upload.Source]; !ok {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) is not supported", upload.Source)))
+	}
+	if c.hook == nil {
+		return nil
+	}
+	if err := c.hook.BeforeUpload(upload); err != nil {
+		return common.Error(common.ErrObjectRejected, common.Field("name", upload.Object), common.Field("reason", err.Error()))
+	}
+	return nil
+}
+
+// CompleteObjectUpload CompleteObjectUpload
+func (c *objectService) CompleteObjectUpload(upload *models.ObjectUpload) (*models.ObjectUploadVerdict, error) {
+	objectPlugin, ok := c.objects[upload.Source]
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) is not supported", upload.Source)))
+	}
+	meta, err := objectPlugin.HeadInternalObject(upload.UserID, upload.Bucket, upload.Object)
+	if err != nil {
+		return nil, err
+	}
+	if c.hook == nil {
+		return &models.ObjectUploadVerdict{Action: models.ObjectUploadAccept}, nil
+	}
+	verdict, err := c.hook.AfterUpload(upload, meta)
+	if err != nil {
+		return nil, err
+	}
+	switch verdict.Action {
+	case models.ObjectUploadAccept:
+	case models.ObjectUploadReject:
+		if err = objectPlugin.DeleteInternalObject(upload.UserID, upload.Bucket, upload.Object); err != nil {
+			return nil, err
+		}
+	case models.ObjectUploadQuarantine:
+		location := c.quarantinePrefix + upload.Object
+		u, err := objectPlugin.GenInternalObjectURL(upload.UserID, upload.Bucket, upload.Object)
+		if err != nil {
+			return nil, err
+		}
+		if err = objectPlugin.PutInternalObjectFromURL(upload.UserID, upload.Bucket, location, u.URL); err != nil {
+			return nil, err
+		}
+		if err = objectPlugin.DeleteInternalObject(upload.UserID, upload.Bucket, upload.Object); err != nil {
+			return nil, err
+		}
+		verdict.Location = location
+	default:
+		return nil, common.Error(common.ErrObjectOperationException, common.Field("source", upload.Source),
+			common.Field("error", fmt.Sprintf("the verdict (%s) of the upload hook is unknown", verdict.Action)))
+	}
+	return verdict, nil
+}
+
 func (c *objectService) CreateExternalBucket(info models.ExternalObjectInfo, bucket, permission, source string) error {
 	objectPlugin, ok := c.objects[source]
 	if !ok {
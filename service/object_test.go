@@ -9,7 +9,9 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
 func Test_NewObjectService(t *testing.T) {
@@ -160,6 +162,134 @@ func TestObjectService_GenInternalObjectURL(t *testing.T) {
 	assert.Contains(t, err.Error(), "The request parameter is invalid. (the source (unknown) is not supported)")
 }
 
+type mockPresignedObject struct {
+	*mockPlugin.MockObject
+	*mockPlugin.MockObjectPresigner
+}
+
+func TestObjectService_PresignObjectURL(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	presigner := mockPlugin.NewMockObjectPresigner(mockObject.ctl)
+	cs := &objectService{objects: map[string]plugin.Object{
+		"s3":  &mockPresignedObject{MockObject: mockObject.objectStorage, MockObjectPresigner: presigner},
+		"bos": mockObject.objectStorage,
+	}}
+
+	urlObj := &models.ObjectURL{URL: "url1"}
+	info := models.ExternalObjectInfo{Endpoint: "x"}
+	opts := &models.ObjectURLOptions{Expire: 60, ContentDisposition: "attachment"}
+	presigner.EXPECT().PresignInternalObjectURL("user", "bucket1", "object1", opts).Return(urlObj, nil)
+	res, err := cs.PresignInternalObjectURL("user", "bucket1", "object1", "s3", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, urlObj, res)
+
+	presigner.EXPECT().PresignExternalObjectURL(info, "bucket1", "object1", opts).Return(urlObj, nil)
+	res, err = cs.PresignExternalObjectURL(info, "bucket1", "object1", "s3", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, urlObj, res)
+
+	putOpts := &models.ObjectURLOptions{ContentType: "application/zip", MaxSize: 1024}
+	presigner.EXPECT().PresignInternalPutObjectURL("user", "bucket1", "object1", putOpts).Return(urlObj, nil)
+	res, err = cs.PresignInternalObjectPutURL("user", "bucket1", "object1", "s3", putOpts)
+	assert.NoError(t, err)
+	assert.Equal(t, urlObj, res)
+
+	_, err = cs.PresignInternalObjectURL("user", "bucket1", "object1", "bos", opts)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the source (bos) does not support signing the urls with the expiry or constraints")
+
+	_, err = cs.PresignInternalObjectPutURL("user", "bucket1", "object1", "unknown", putOpts)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the source (unknown) is not supported")
+}
+
+type mockLifecycleObject struct {
+	*mockPlugin.MockObject
+	*mockPlugin.MockObjectLifecycle
+}
+
+func TestObjectService_BucketLifecycle(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	lifecycle := mockPlugin.NewMockObjectLifecycle(mockObject.ctl)
+	cs := &objectService{objects: map[string]plugin.Object{
+		"s3":  &mockLifecycleObject{MockObject: mockObject.objectStorage, MockObjectLifecycle: lifecycle},
+		"bos": mockObject.objectStorage,
+	}}
+
+	rules := &models.BucketLifecycle{Rules: []models.BucketLifecycleRule{{ID: "r1", Prefix: "uploads/", Enabled: true, ExpirationDays: 30}}}
+	info := models.ExternalObjectInfo{Endpoint: "x"}
+	lifecycle.EXPECT().GetInternalBucketLifecycle("user", "bucket1").Return(rules, nil)
+	res, err := cs.GetInternalBucketLifecycle("user", "bucket1", "s3")
+	assert.NoError(t, err)
+	assert.Equal(t, rules, res)
+
+	lifecycle.EXPECT().PutInternalBucketLifecycle("user", "bucket1", rules).Return(nil)
+	assert.NoError(t, cs.PutInternalBucketLifecycle("user", "bucket1", "s3", rules))
+
+	lifecycle.EXPECT().GetExternalBucketLifecycle(info, "bucket1").Return(rules, nil)
+	res, err = cs.GetExternalBucketLifecycle(info, "bucket1", "s3")
+	assert.NoError(t, err)
+	assert.Equal(t, rules, res)
+
+	lifecycle.EXPECT().PutExternalBucketLifecycle(info, "bucket1", rules).Return(nil)
+	assert.NoError(t, cs.PutExternalBucketLifecycle(info, "bucket1", "s3", rules))
+
+	_, err = cs.GetInternalBucketLifecycle("user", "bucket1", "bos")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The operation (bucket lifecycle) is not supported by the source (bos).")
+
+	err = cs.PutExternalBucketLifecycle(info, "bucket1", "unknown", rules)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the source (unknown) is not supported")
+}
+
+func TestObjectService_ObjectUpload(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	hook := mockPlugin.NewMockObjectUploadHook(mockObject.ctl)
+	cs := &objectService{objects: map[string]plugin.Object{"bos": mockObject.objectStorage}, hook: hook, quarantinePrefix: "quarantine/"}
+
+	upload := &models.ObjectUpload{UserID: "user", Source: "bos", Bucket: "b1", Object: "a.zip", ContentType: "application/zip"}
+	hook.EXPECT().BeforeUpload(upload).Return(nil)
+	assert.NoError(t, cs.CheckObjectUpload(upload))
+	hook.EXPECT().BeforeUpload(upload).Return(errors.New("too large"))
+	err := cs.CheckObjectUpload(upload)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The object (a.zip) is rejected. (too large)")
+	assert.Error(t, cs.CheckObjectUpload(&models.ObjectUpload{Source: "unknown"}))
+
+	meta := &models.ObjectMeta{ContentLength: 10}
+	mockObject.objectStorage.EXPECT().HeadInternalObject("user", "b1", "a.zip").Return(meta, nil).Times(4)
+
+	hook.EXPECT().AfterUpload(upload, meta).Return(&models.ObjectUploadVerdict{Action: models.ObjectUploadAccept}, nil)
+	res, err := cs.CompleteObjectUpload(upload)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ObjectUploadAccept, res.Action)
+
+	hook.EXPECT().AfterUpload(upload, meta).Return(&models.ObjectUploadVerdict{Action: models.ObjectUploadReject, Reason: "virus"}, nil)
+	mockObject.objectStorage.EXPECT().DeleteInternalObject("user", "b1", "a.zip").Return(nil)
+	res, err = cs.CompleteObjectUpload(upload)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ObjectUploadVerdict{Action: models.ObjectUploadReject, Reason: "virus"}, res)
+
+	hook.EXPECT().AfterUpload(upload, meta).Return(&models.ObjectUploadVerdict{Action: models.ObjectUploadQuarantine}, nil)
+	mockObject.objectStorage.EXPECT().GenInternalObjectURL("user", "b1", "a.zip").Return(&models.ObjectURL{URL: "http://x/a.zip"}, nil)
+	mockObject.objectStorage.EXPECT().PutInternalObjectFromURL("user", "b1", "quarantine/a.zip", "http://x/a.zip").Return(nil)
+	mockObject.objectStorage.EXPECT().DeleteInternalObject("user", "b1", "a.zip").Return(nil)
+	res, err = cs.CompleteObjectUpload(upload)
+	assert.NoError(t, err)
+	assert.Equal(t, "quarantine/a.zip", res.Location)
+
+	hook.EXPECT().AfterUpload(upload, meta).Return(&models.ObjectUploadVerdict{Action: "unknown"}, nil)
+	_, err = cs.CompleteObjectUpload(upload)
+	assert.Error(t, err)
+
+	cs.hook = nil
+	assert.NoError(t, cs.CheckObjectUpload(upload))
+}
+
 func TestObjectService_CreateInternalBucketIfNotExist(t *testing.T) {
 	mockObject := InitMockEnvironment(t)
 	defer mockObject.Close()
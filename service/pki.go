@@ -4,9 +4,11 @@ import (
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/pki"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
@@ -21,6 +23,8 @@ type PKIService interface {
 	SignServerCertificate(cn string, altNames models.AltNames) (*models.PEMCredential, error)
 	// SignNodeCertificate sign a certificate which can be used to connect to cloud
 	SignClientCertificate(cn string, altNames models.AltNames) (*models.PEMCredential, error)
+	// SignCertificateRequest sign the client certificate of the csr with the validity, the key is kept by the client
+	SignCertificateRequest(csr []byte, validity time.Duration) (*models.PEMCredential, error)
 	// DeleteServerCertificate delete a server certificate by certId
 	DeleteServerCertificate(certId string) error
 	// DeleteClientCertificate delete a server certificate by certId
@@ -70,6 +74,26 @@ func (p *pkiService) SignClientCertificate(cn string, altNames models.AltNames)
 	return p.signCertificate(cn, altNames, p.pki.CreateClientCert, p.pki.GetClientCert)
 }
 
+// SignCertificateRequest fails with ErrNotSupported if the pki can't sign the certificates with the validity
+func (p *pkiService) SignCertificateRequest(csr []byte, validity time.Duration) (*models.PEMCredential, error) {
+	pv, ok := p.pki.(plugin.PKIValidity)
+	if !ok {
+		return nil, common.Error(common.ErrNotSupported, common.Field("operation", "signing csr"), common.Field("source", "pki"))
+	}
+	certId, err := pv.CreateClientCertWithValidity(csr, p.pki.GetRootCertID(), validity)
+	if err != nil {
+		return nil, err
+	}
+	certPem, err := p.pki.GetClientCert(certId)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PEMCredential{
+		CertPEM: certPem,
+		CertId:  certId,
+	}, nil
+}
+
 func (p *pkiService) signCertificate(cn string, altNames models.AltNames, create func(csr []byte, rootId string) (string, error), get func(certId string) ([]byte, error)) (*models.PEMCredential, error) {
 	csrInfo := p.genDefaultCSR(cn)
 	csrInfo.DNSNames = altNames.DNSNames
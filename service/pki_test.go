@@ -3,11 +3,13 @@ package service
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
@@ -104,3 +106,31 @@ func TestPkiService_DeleteCertificate(t *testing.T) {
 	err = ps.DeleteServerCertificate(certId)
 	assert.NoError(t, err)
 }
+
+func TestPkiService_SignCertificateRequest(t *testing.T) {
+	mc := InitMockEnvironment(t)
+	defer mc.Close()
+
+	// the pki can't sign the certificates with the validity
+	ps, err := NewPKIService(mc.conf)
+	assert.NoError(t, err)
+	_, err = ps.SignCertificateRequest([]byte("csr"), time.Hour)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The operation (signing csr) is not supported")
+
+	pv := mockPlugin.NewMockPKIValidity(mc.ctl)
+	ps = &pkiService{pki: &struct {
+		*mockPlugin.MockPKI
+		*mockPlugin.MockPKIValidity
+	}{mc.pki, pv}}
+	mc.pki.EXPECT().GetRootCertId().Return("root").Times(2)
+	pv.EXPECT().CreateClientCertWithValidity([]byte("csr"), "root", time.Hour).Return("c0", nil).Times(1)
+	mc.pki.EXPECT().GetClientCert("c0").Return([]byte("pem"), nil).Times(1)
+	res, err := ps.SignCertificateRequest([]byte("csr"), time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.PEMCredential{CertPEM: []byte("pem"), CertId: "c0"}, res)
+
+	pv.EXPECT().CreateClientCertWithValidity([]byte("csr"), "root", time.Hour).Return("", os.ErrNotExist).Times(1)
+	_, err = ps.SignCertificateRequest([]byte("csr"), time.Hour)
+	assert.Error(t, err)
+}
@@ -9,7 +9,8 @@ import (
 //go:generate mockgen -destination=../mock/service/quota.go -package=service github.com/baetyl/baetyl-cloud/v2/service QuotaService
 type QuotaService interface {
 	plugin.Quota
-	CheckQuota(namespace string, collector plugin.QuotaCollector) error
+	CheckQuota(namespace string, collectors ...plugin.QuotaCollector) error
+	CollectUsage(namespace string, collectors ...plugin.QuotaCollector) (map[string]int, error)
 }
 
 type QuotaServiceImpl struct {
@@ -27,13 +28,14 @@ func NewQuotaService(config *config.CloudConfig) (QuotaService, error) {
 	}, nil
 }
 
-func (l *QuotaServiceImpl) CheckQuota(namespace string, collector plugin.QuotaCollector) error {
+// CheckQuota checks the usages collected by all the collectors against the limits of the namespace
+func (l *QuotaServiceImpl) CheckQuota(namespace string, collectors ...plugin.QuotaCollector) error {
 	limits, err := l.GetQuota(namespace)
 	if err != nil {
 		return err
 	}
 
-	counts, err := collector(namespace)
+	counts, err := l.CollectUsage(namespace, collectors...)
 	if err != nil {
 		return err
 	}
@@ -44,11 +46,29 @@ func (l *QuotaServiceImpl) CheckQuota(namespace string, collector plugin.QuotaCo
 
 	for k, v := range counts {
 		if limits[k] != 0 && v >= limits[k] {
-			return common.Error(
-				common.ErrLicenseQuota,
-				common.Field("name", k),
-				common.Field("limit", limits[k]))
+			return &common.QuotaError{Name: k, Limit: limits[k], Used: v}
 		}
 	}
 	return nil
 }
+
+// CollectUsage merges the usages of the namespace collected by the collectors
+func (l *QuotaServiceImpl) CollectUsage(namespace string, collectors ...plugin.QuotaCollector) (map[string]int, error) {
+	var usages map[string]int
+	for _, collector := range collectors {
+		counts, err := collector(namespace)
+		if err != nil {
+			return nil, err
+		}
+		if counts == nil {
+			continue
+		}
+		if usages == nil {
+			usages = map[string]int{}
+		}
+		for k, v := range counts {
+			usages[k] = v
+		}
+	}
+	return usages, nil
+}
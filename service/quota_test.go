@@ -56,3 +56,42 @@ func TestLicenseService_CheckQuota(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+func TestQuotaService_CheckQuotaWithCollectors(t *testing.T) {
+	namespace := "default"
+	services := InitMockEnvironment(t)
+	ls, err := NewQuotaService(services.conf)
+	assert.NoError(t, err)
+	quotas := map[string]int{
+		plugin.QuotaNode:   10,
+		plugin.QuotaApp:    2,
+		plugin.QuotaConfig: 0,
+	}
+	nodeCollector := func(namespace string) (map[string]int, error) {
+		return map[string]int{plugin.QuotaNode: 1}, nil
+	}
+	appCollector := func(namespace string) (map[string]int, error) {
+		return map[string]int{plugin.QuotaApp: 2}, nil
+	}
+	configCollector := func(namespace string) (map[string]int, error) {
+		return map[string]int{plugin.QuotaConfig: 100}, nil
+	}
+
+	services.quota.EXPECT().GetQuota(namespace).Return(quotas, nil)
+	err = ls.CheckQuota(namespace, nodeCollector, configCollector)
+	assert.NoError(t, err)
+
+	services.quota.EXPECT().GetQuota(namespace).Return(quotas, nil)
+	err = ls.CheckQuota(namespace, nodeCollector, appCollector)
+	assert.Error(t, err)
+
+	usages, err := ls.CollectUsage(namespace, nodeCollector, appCollector, configCollector)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{plugin.QuotaNode: 1, plugin.QuotaApp: 2, plugin.QuotaConfig: 100}, usages)
+
+	errCollect := fmt.Errorf("collect error")
+	_, err = ls.CollectUsage(namespace, nodeCollector, func(namespace string) (map[string]int, error) {
+		return nil, errCollect
+	})
+	assert.Equal(t, errCollect, err)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/registry.go -package=service github.com/baetyl/baetyl-cloud/v2/service RegistryService
+
+// RegistryService keeps the result of the last refresh of the password of each registry
+type RegistryService interface {
+	GetRefresh(namespace, name string) (*models.RegistryRefresh, error)
+	SaveRefresh(refresh *models.RegistryRefresh) error
+	DeleteRefresh(namespace, name string) error
+}
+
+type registryService struct {
+	refresh plugin.RegistryRefresh
+}
+
+// NewRegistryService NewRegistryService
+func NewRegistryService(config *config.CloudConfig) (RegistryService, error) {
+	refresh, err := plugin.GetPlugin(config.Plugin.RegRefresh)
+	if err != nil {
+		return nil, err
+	}
+	return &registryService{refresh: refresh.(plugin.RegistryRefresh)}, nil
+}
+
+func (s *registryService) GetRefresh(namespace, name string) (*models.RegistryRefresh, error) {
+	return s.refresh.GetRegistryRefresh(nil, namespace, name)
+}
+
+func (s *registryService) SaveRefresh(refresh *models.RegistryRefresh) error {
+	if len(refresh.Message) > 1024 {
+		refresh.Message = refresh.Message[:1024]
+	}
+	return s.refresh.SaveRegistryRefresh(nil, refresh)
+}
+
+func (s *registryService) DeleteRefresh(namespace, name string) error {
+	return s.refresh.DeleteRegistryRefresh(nil, namespace, name)
+}
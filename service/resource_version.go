@@ -0,0 +1,94 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+// resourceVersions keeps the versions of the configs and secrets up to the retention,
+// a nil one keeps nothing so that only the current versions are available
+type resourceVersions struct {
+	history   plugin.AppHistory
+	retention int
+}
+
+func newResourceVersions(cfg *config.CloudConfig) (*resourceVersions, error) {
+	if cfg.ResourceVersion.Retention <= 0 {
+		return nil, nil
+	}
+	history, err := plugin.GetPlugin(cfg.Plugin.AppHistory)
+	if err != nil {
+		return nil, err
+	}
+	return &resourceVersions{
+		history:   history.(plugin.AppHistory),
+		retention: cfg.ResourceVersion.Retention,
+	}, nil
+}
+
+// record keeps the version of the resource and deletes the oldest ones out of the retention
+func (r *resourceVersions) record(tx interface{}, kind, namespace, name, version string, resource interface{}) error {
+	if r == nil {
+		return nil
+	}
+	content, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	err = r.history.CreateResourceVersion(tx, &models.ResourceVersion{
+		Namespace: namespace,
+		Kind:      kind,
+		Name:      name,
+		Version:   version,
+		Content:   string(content),
+	})
+	if err != nil {
+		return err
+	}
+	return r.history.DeleteResourceVersion(tx, namespace, kind, name, r.retention)
+}
+
+// get reads the kept version of the resource into out
+func (r *resourceVersions) get(kind, namespace, name, version string, out interface{}) error {
+	if r == nil {
+		return common.Error(common.ErrResourceNotFound, common.Field("type", kind+" version"),
+			common.Field("name", name+"@"+version), common.Field("namespace", namespace))
+	}
+	res, err := r.history.GetResourceVersion(nil, namespace, kind, name, version)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(res.Content), out)
+}
+
+// list lists the kept versions of the resource, the latest first,
+// the current version is always listed even if it was updated before the versions are kept
+func (r *resourceVersions) list(kind string, current *models.ResourceVersion) (*models.ResourceVersionList, error) {
+	var items []models.ResourceVersion
+	if r != nil {
+		versions, err := r.history.ListResourceVersion(nil, current.Namespace, kind, current.Name)
+		if err != nil {
+			return nil, err
+		}
+		items = versions
+	}
+	if len(items) == 0 || items[0].Version != current.Version {
+		items = append([]models.ResourceVersion{*current}, items...)
+	}
+	return &models.ResourceVersionList{
+		Total: len(items),
+		Items: items,
+	}, nil
+}
+
+// clear deletes all the kept versions of the resource
+func (r *resourceVersions) clear(tx interface{}, kind, namespace, name string) error {
+	if r == nil {
+		return nil
+	}
+	return r.history.DeleteResourceVersion(tx, namespace, kind, name, 0)
+}
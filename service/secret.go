@@ -19,12 +19,22 @@ type SecretService interface {
 	GetTx(tx interface{}, namespace, name, version string) (*specV1.Secret, error)
 	List(namespace string, listOptions *models.ListOptions) (*models.SecretList, error)
 	Create(tx interface{}, namespace string, secret *specV1.Secret) (*specV1.Secret, error)
-	Update(namespace string, secret *specV1.Secret) (*specV1.Secret, error)
+	Update(tx interface{}, namespace string, secret *specV1.Secret) (*specV1.Secret, error)
 	Delete(tx interface{}, namespace, name string) error
+	// Encrypt encrypts the plaintext secrets of the namespace stored before the kms is configured, returns the number of them
+	Encrypt(namespace string) (int, error)
+	// GetVersion gets the current or a kept version of the secret
+	GetVersion(namespace, name, version string) (*specV1.Secret, error)
+	// ListVersions lists the kept versions of the secret without the data, the latest first
+	ListVersions(namespace, name string) (*models.ResourceVersionList, error)
 }
 
 type secretService struct {
 	secret plugin.Secret
+	// kms the secrets are stored as plaintext if it is nil
+	kms plugin.KMS
+	// versions the secrets are kept as stored, so the versions are encrypted if the kms is configured
+	versions *resourceVersions
 }
 
 // NewSecretService NewSecretService
@@ -33,9 +43,22 @@ func NewSecretService(config *config.CloudConfig) (SecretService, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &secretService{
-		secret: secret.(plugin.Secret),
-	}, nil
+	versions, err := newResourceVersions(config)
+	if err != nil {
+		return nil, err
+	}
+	s := &secretService{
+		secret:   secret.(plugin.Secret),
+		versions: versions,
+	}
+	if config.Plugin.KMS != "" {
+		kms, err := plugin.GetPlugin(config.Plugin.KMS)
+		if err != nil {
+			return nil, err
+		}
+		s.kms = kms.(plugin.KMS)
+	}
+	return s, nil
 }
 
 // Get get a Secret
@@ -44,7 +67,15 @@ func (s *secretService) GetTx(tx interface{}, namespace, name, version string) (
 	if err != nil && strings.Contains(err.Error(), "not found") {
 		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "secret"), common.Field("name", name))
 	}
-	return res, err
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		if err = guardNamespace(namespace, common.Secret, name, res.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	return s.decrypt(res)
 }
 
 // Get get a Secret
@@ -54,26 +85,145 @@ func (s *secretService) Get(namespace, name, version string) (*specV1.Secret, er
 		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "secret"),
 			common.Field("name", name))
 	}
-	return res, err
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		if err = guardNamespace(namespace, common.Secret, name, res.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	return s.decrypt(res)
 }
 
 // List get list Secret
 func (s *secretService) List(namespace string, listOptions *models.ListOptions) (*models.SecretList, error) {
-	return s.secret.ListSecret(namespace, listOptions)
+	res, err := s.secret.ListSecret(namespace, listOptions)
+	if err != nil || res == nil {
+		return res, err
+	}
+	total := len(res.Items)
+	res.Items = guardNamespaceList(namespace, common.Secret, res.Items, func(s *specV1.Secret) (string, string) {
+		return s.Namespace, s.Name
+	})
+	res.Total -= total - len(res.Items)
+	for i := range res.Items {
+		item, err := s.decrypt(&res.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		res.Items[i] = *item
+	}
+	return res, nil
 }
 
 // Create Create a Secret
 func (s *secretService) Create(tx interface{}, namespace string, secret *specV1.Secret) (*specV1.Secret, error) {
-	return s.secret.CreateSecret(tx, namespace, secret)
-
+	secret, err := s.encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.secret.CreateSecret(tx, namespace, secret)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.versions.record(tx, models.ResourceVersionSecret, namespace, res.Name, res.Version, res); err != nil {
+		return nil, err
+	}
+	return s.decrypt(res)
 }
 
 // Update update a Secret
-func (s *secretService) Update(namespace string, secret *specV1.Secret) (*specV1.Secret, error) {
-	return s.secret.UpdateSecret(namespace, secret)
+func (s *secretService) Update(tx interface{}, namespace string, secret *specV1.Secret) (*specV1.Secret, error) {
+	secret, err := s.encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.secret.UpdateSecret(tx, namespace, secret)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.versions.record(tx, models.ResourceVersionSecret, namespace, res.Name, res.Version, res); err != nil {
+		return nil, err
+	}
+	return s.decrypt(res)
 }
 
 // Delete Delete a Secret
 func (s *secretService) Delete(tx interface{}, namespace, name string) error {
-	return s.secret.DeleteSecret(tx, namespace, name)
+	if err := s.secret.DeleteSecret(tx, namespace, name); err != nil {
+		return err
+	}
+	return s.versions.clear(tx, models.ResourceVersionSecret, namespace, name)
+}
+
+// GetVersion get the current or a kept version of a secret
+func (s *secretService) GetVersion(namespace, name, version string) (*specV1.Secret, error) {
+	res, err := s.Get(namespace, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if res.Version == version {
+		return res, nil
+	}
+	his := &specV1.Secret{}
+	if err = s.versions.get(models.ResourceVersionSecret, namespace, name, version, his); err != nil {
+		return nil, err
+	}
+	return s.decrypt(his)
+}
+
+// ListVersions list the kept versions of a secret
+func (s *secretService) ListVersions(namespace, name string) (*models.ResourceVersionList, error) {
+	res, err := s.secret.GetSecret(nil, namespace, name, "")
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "secret"), common.Field("name", name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = guardNamespace(namespace, common.Secret, name, res.Namespace); err != nil {
+		return nil, err
+	}
+	return s.versions.list(models.ResourceVersionSecret, &models.ResourceVersion{
+		Namespace:  namespace,
+		Kind:       models.ResourceVersionSecret,
+		Name:       name,
+		Version:    res.Version,
+		CreateTime: res.UpdateTimestamp,
+	})
+}
+
+// Encrypt encrypts the plaintext secrets of the namespace in place, the encrypted ones are skipped
+func (s *secretService) Encrypt(namespace string) (int, error) {
+	if s.kms == nil {
+		return 0, common.Error(common.ErrRequestParamInvalid, common.Field("error", "no kms is configured to encrypt the secrets"))
+	}
+	list, err := s.secret.ListSecret(namespace, &models.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := range list.Items {
+		if isEncryptedSecret(&list.Items[i]) || len(list.Items[i].Data) == 0 {
+			continue
+		}
+		secret, err := s.encrypt(&list.Items[i])
+		if err != nil {
+			return count, err
+		}
+		res, err := s.secret.UpdateSecret(nil, namespace, secret)
+		if err != nil {
+			return count, err
+		}
+		// the kept versions in plaintext are replaced by the encrypted one
+		if err = s.versions.clear(nil, models.ResourceVersionSecret, namespace, res.Name); err != nil {
+			return count, err
+		}
+		if err = s.versions.record(nil, models.ResourceVersionSecret, namespace, res.Name, res.Version, res); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
 }
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const (
+	// secretEncryptedPrefix the prefix of the encrypted values of the secrets,
+	// which are formatted as <prefix><base64 data key encrypted by kms>:<base64 value encrypted by data key>
+	secretEncryptedPrefix = "baetyl-kms:v1:"
+	// secretEncryptionVersion the value of the label marking the encrypted secrets
+	secretEncryptionVersion = "v1"
+	secretDataKeySize       = 32
+)
+
+// encrypt returns a copy of the secret whose values are all encrypted by a new data key, which is encrypted by the kms
+// in turn. The secret is marked encrypted by the label, the values given by the users are always encrypted whatever
+// they look like, and the label given by the users is dropped
+func (s *secretService) encrypt(secret *specV1.Secret) (*specV1.Secret, error) {
+	if secret == nil {
+		return secret, nil
+	}
+	res := *secret
+	res.Labels = make(map[string]string, len(secret.Labels)+1)
+	for k, v := range secret.Labels {
+		if k != common.LabelSecretEncrypted {
+			res.Labels[k] = v
+		}
+	}
+	if s.kms == nil || len(secret.Data) == 0 {
+		return &res, nil
+	}
+	key := make([]byte, secretDataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	encKey, err := s.kms.Encrypt(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	prefix := secretEncryptedPrefix + base64.StdEncoding.EncodeToString(encKey) + ":"
+
+	res.Data = make(map[string][]byte, len(secret.Data))
+	for k, v := range secret.Data {
+		enc, err := util.SealGCM(v, key)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		res.Data[k] = []byte(prefix + base64.StdEncoding.EncodeToString(enc))
+	}
+	res.Labels[common.LabelSecretEncrypted] = secretEncryptionVersion
+	return &res, nil
+}
+
+// decrypt decrypts the values of the secret marked encrypted in place and removes the label,
+// the secrets stored in plaintext before the kms is configured are kept as they are
+func (s *secretService) decrypt(secret *specV1.Secret) (*specV1.Secret, error) {
+	if !isEncryptedSecret(secret) {
+		return secret, nil
+	}
+	if s.kms == nil {
+		return nil, errors.Errorf("the secret (%s) is encrypted but no kms is configured", secret.Name)
+	}
+	// the values encrypted at once share the same data key
+	keys := map[string][]byte{}
+	for k, v := range secret.Data {
+		if !isEncrypted(v) {
+			return nil, errors.Errorf("the encrypted value (%s) of the secret (%s) is malformed", k, secret.Name)
+		}
+		parts := strings.SplitN(string(v[len(secretEncryptedPrefix):]), ":", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("the encrypted value (%s) of the secret (%s) is malformed", k, secret.Name)
+		}
+		key, ok := keys[parts[0]]
+		if !ok {
+			encKey, err := base64.StdEncoding.DecodeString(parts[0])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if key, err = s.kms.Decrypt(encKey); err != nil {
+				return nil, errors.Trace(err)
+			}
+			keys[parts[0]] = key
+		}
+		enc, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if secret.Data[k], err = util.OpenGCM(enc, key); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	delete(secret.Labels, common.LabelSecretEncrypted)
+	return secret, nil
+}
+
+func isEncrypted(value []byte) bool {
+	return bytes.HasPrefix(value, []byte(secretEncryptedPrefix))
+}
+
+func isEncryptedSecret(secret *specV1.Secret) bool {
+	return secret != nil && secret.Labels[common.LabelSecretEncrypted] != ""
+}
+
+// encryptValue encrypts the value by a new data key in the same format as the values of the secrets,
+// which is used for the single values stored out of the secrets, such as the signing secrets of the webhooks
+func encryptValue(kms plugin.KMS, value []byte) ([]byte, error) {
+	key := make([]byte, secretDataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	encKey, err := kms.Encrypt(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	enc, err := util.SealGCM(value, key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []byte(secretEncryptedPrefix + base64.StdEncoding.EncodeToString(encKey) + ":" +
+		base64.StdEncoding.EncodeToString(enc)), nil
+}
+
+// decryptValue decrypts the value encrypted by encryptValue, the values stored in plaintext are returned as they are
+func decryptValue(kms plugin.KMS, value []byte) ([]byte, error) {
+	if !isEncrypted(value) {
+		return value, nil
+	}
+	if kms == nil {
+		return nil, errors.New("the value is encrypted but no kms is configured")
+	}
+	parts := strings.SplitN(string(value[len(secretEncryptedPrefix):]), ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("the encrypted value is malformed")
+	}
+	encKey, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	key, err := kms.Decrypt(encKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	enc, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res, err := util.OpenGCM(enc, key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return res, nil
+}
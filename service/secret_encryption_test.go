@@ -0,0 +1,155 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// mockKMS wraps the data keys by reversing them
+func mockKMS(mockCtl *gomock.Controller) *mockPlugin.MockKMS {
+	reverse := func(in []byte) ([]byte, error) {
+		out := make([]byte, len(in))
+		for i := range in {
+			out[len(in)-1-i] = in[i]
+		}
+		return out, nil
+	}
+	mKMS := mockPlugin.NewMockKMS(mockCtl)
+	mKMS.EXPECT().Encrypt(gomock.Any()).DoAndReturn(reverse).AnyTimes()
+	mKMS.EXPECT().Decrypt(gomock.Any()).DoAndReturn(reverse).AnyTimes()
+	return mKMS
+}
+
+func TestSecretEncryption(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs := &secretService{secret: mockObject.secret, kms: mockKMS(mockObject.ctl)}
+	ns := "default"
+
+	var stored *specV1.Secret
+	mockObject.secret.EXPECT().CreateSecret(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, secret *specV1.Secret) (*specV1.Secret, error) {
+			stored = secret
+			res := *secret
+			res.Labels = map[string]string{}
+			for k, v := range secret.Labels {
+				res.Labels[k] = v
+			}
+			res.Data = map[string][]byte{}
+			for k, v := range secret.Data {
+				res.Data[k] = v
+			}
+			return &res, nil
+		}).Times(1)
+	secret := &specV1.Secret{Namespace: ns, Name: "abc", Data: map[string][]byte{"password": []byte("123456"), "key": []byte("-----BEGIN")}}
+	res, err := cs.Create(nil, ns, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, secret.Data, res.Data)
+	// the secret of the caller is not modified
+	assert.Equal(t, []byte("123456"), secret.Data["password"])
+	for k, v := range stored.Data {
+		assert.True(t, isEncrypted(v), k)
+		assert.False(t, bytes.Contains(v, secret.Data[k]), k)
+	}
+
+	assert.Equal(t, secretEncryptionVersion, stored.Labels[common.LabelSecretEncrypted])
+	assert.Empty(t, res.Labels[common.LabelSecretEncrypted])
+
+	// the values looking encrypted are encrypted as well, and the label given is dropped
+	mockObject.secret.EXPECT().UpdateSecret(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, secret *specV1.Secret) (*specV1.Secret, error) {
+			stored = secret
+			res := *secret
+			res.Labels = map[string]string{}
+			for k, v := range secret.Labels {
+				res.Labels[k] = v
+			}
+			res.Data = map[string][]byte{}
+			for k, v := range secret.Data {
+				res.Data[k] = v
+			}
+			return &res, nil
+		}).Times(1)
+	fake := &specV1.Secret{Namespace: ns, Name: "abc", Labels: map[string]string{common.LabelSecretEncrypted: "v1"},
+		Data: map[string][]byte{"password": []byte(secretEncryptedPrefix + "abc:def")}}
+	res, err = cs.Update(nil, ns, fake)
+	assert.NoError(t, err)
+	assert.NotEqual(t, fake.Data["password"], stored.Data["password"])
+	assert.Equal(t, fake.Data, res.Data)
+	assert.Empty(t, res.Labels[common.LabelSecretEncrypted])
+
+	// the secrets without the label are read as plaintext, even if the values look encrypted
+	plainStored := &specV1.Secret{Namespace: ns, Name: "abc", Data: map[string][]byte{"password": []byte(secretEncryptedPrefix + "abc:def")}}
+	mockObject.secret.EXPECT().GetSecret(nil, ns, "abc", "").Return(plainStored, nil).Times(1)
+	res, err = cs.Get(ns, "abc", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(secretEncryptedPrefix+"abc:def"), res.Data["password"])
+
+	list := &models.SecretList{Items: []specV1.Secret{{Name: "abc", Labels: map[string]string{common.LabelSecretEncrypted: "v1"},
+		Data: map[string][]byte{"password": stored.Data["password"]}}}}
+	mockObject.secret.EXPECT().ListSecret(ns, gomock.Any()).Return(list, nil).Times(1)
+	resList, err := cs.List(ns, &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, fake.Data["password"], resList.Items[0].Data["password"])
+	assert.Empty(t, resList.Items[0].Labels)
+
+	// malformed values fail to be decrypted
+	encryptedLabels := map[string]string{common.LabelSecretEncrypted: "v1"}
+	mockObject.secret.EXPECT().GetSecret(nil, ns, "abc", "").Return(&specV1.Secret{Name: "abc", Labels: encryptedLabels,
+		Data: map[string][]byte{"key": []byte(secretEncryptedPrefix + "abc")}}, nil).Times(1)
+	_, err = cs.GetTx(nil, ns, "abc", "")
+	assert.Error(t, err)
+	mockObject.secret.EXPECT().GetSecret(nil, ns, "abc", "").Return(&specV1.Secret{Name: "abc", Labels: encryptedLabels,
+		Data: map[string][]byte{"key": []byte("plain")}}, nil).Times(1)
+	_, err = cs.GetTx(nil, ns, "abc", "")
+	assert.Error(t, err)
+
+	// the encrypted values can't be read without kms
+	plain := &secretService{secret: mockObject.secret}
+	mockObject.secret.EXPECT().GetSecret(nil, ns, "abc", "").Return(&specV1.Secret{Name: "abc", Labels: encryptedLabels,
+		Data: map[string][]byte{"key": stored.Data["password"]}}, nil).Times(1)
+	_, err = plain.Get(ns, "abc", "")
+	assert.Error(t, err)
+	_, err = plain.Encrypt(ns)
+	assert.Error(t, err)
+}
+
+func TestSecretService_Encrypt(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs := &secretService{secret: mockObject.secret, kms: mockKMS(mockObject.ctl)}
+	ns := "default"
+
+	encrypted, err := cs.encrypt(&specV1.Secret{Name: "b", Data: map[string][]byte{"k": []byte("v")}})
+	assert.NoError(t, err)
+	list := &models.SecretList{Items: []specV1.Secret{
+		{Name: "a", Data: map[string][]byte{"k": []byte("v")}},
+		*encrypted,
+		{Name: "c"},
+	}}
+	mockObject.secret.EXPECT().ListSecret(ns, gomock.Any()).Return(list, nil).Times(1)
+	mockObject.secret.EXPECT().UpdateSecret(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, secret *specV1.Secret) (*specV1.Secret, error) {
+			assert.Equal(t, "a", secret.Name)
+			assert.True(t, isEncrypted(secret.Data["k"]))
+			assert.True(t, isEncryptedSecret(secret))
+			return secret, nil
+		}).Times(1)
+	count, err := cs.Encrypt(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	mockObject.secret.EXPECT().ListSecret(ns, gomock.Any()).Return(list, nil).Times(1)
+	mockObject.secret.EXPECT().UpdateSecret(nil, ns, gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	_, err = cs.Encrypt(ns)
+	assert.Error(t, err)
+}
@@ -90,7 +90,50 @@ func TestDefaultRegistryService_Update(t *testing.T) {
 	cs, err := NewSecretService(mockObject.conf)
 	assert.NoError(t, err)
 	registry := genSecretTestCase()
-	mockObject.secret.EXPECT().UpdateSecret(gomock.Any(), gomock.Any()).Return(genSecretTestCase(), nil)
-	_, err = cs.Update(registry.Namespace, registry)
+	mockObject.secret.EXPECT().UpdateSecret(nil, gomock.Any(), gomock.Any()).Return(genSecretTestCase(), nil)
+	_, err = cs.Update(nil, registry.Namespace, registry)
 	assert.NoError(t, err)
 }
+
+func TestDefaultSecretService_Versions(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs := &secretService{
+		secret:   mockObject.secret,
+		kms:      mockKMS(mockObject.ctl),
+		versions: &resourceVersions{history: mockObject.appHis, retention: 3},
+	}
+	ns := "default"
+
+	// the versions are kept as stored, so they are encrypted
+	var recorded *models.ResourceVersion
+	mockObject.secret.EXPECT().UpdateSecret(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, secret *specV1.Secret) (*specV1.Secret, error) {
+			res := *secret
+			res.Version = "2"
+			return &res, nil
+		}).Times(1)
+	mockObject.appHis.EXPECT().CreateResourceVersion(nil, gomock.Any()).DoAndReturn(
+		func(_ interface{}, version *models.ResourceVersion) error {
+			recorded = version
+			return nil
+		}).Times(1)
+	mockObject.appHis.EXPECT().DeleteResourceVersion(nil, ns, models.ResourceVersionSecret, "abc", 3).Return(nil).Times(1)
+	res, err := cs.Update(nil, ns, &specV1.Secret{Namespace: ns, Name: "abc", Data: map[string][]byte{"password": []byte("123456")}})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", res.Version)
+	assert.Equal(t, "2", recorded.Version)
+	assert.NotContains(t, recorded.Content, "123456")
+
+	mockObject.secret.EXPECT().GetSecret(nil, ns, "abc", "").Return(&specV1.Secret{Name: "abc", Version: "3"}, nil).AnyTimes()
+	mockObject.appHis.EXPECT().GetResourceVersion(nil, ns, models.ResourceVersionSecret, "abc", "2").Return(recorded, nil).Times(1)
+	res, err = cs.GetVersion(ns, "abc", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("123456"), res.Data["password"])
+
+	mockObject.appHis.EXPECT().ListResourceVersion(nil, ns, models.ResourceVersionSecret, "abc").
+		Return([]models.ResourceVersion{{Name: "abc", Version: "3"}, {Name: "abc", Version: "2"}}, nil).Times(1)
+	list, err := cs.ListVersions(ns, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+}
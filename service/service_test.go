@@ -21,6 +21,7 @@ type MockServices struct {
 	secret         *mockPlugin.MockResource
 	app            *mockPlugin.MockResource
 	index          *mockPlugin.MockIndex
+	appHis         *mockPlugin.MockAppHistory
 	objectStorage  *mockPlugin.MockObject
 	functionPlugin *mockPlugin.MockFunction
 	pki            *mockPlugin.MockPKI
@@ -110,6 +111,13 @@ func mockResource(mock plugin.Resource) plugin.Factory {
 	return factory
 }
 
+func mockAppHistory(mock plugin.AppHistory) plugin.Factory {
+	factory := func() (plugin.Plugin, error) {
+		return mock, nil
+	}
+	return factory
+}
+
 func mockIndex(mock plugin.Index) plugin.Factory {
 	factory := func() (plugin.Plugin, error) {
 		return mock, nil
@@ -146,6 +154,7 @@ func mockTestConfig() *config.CloudConfig {
 	conf.Plugin.Property = common.RandString(9)
 	conf.Plugin.Task = common.RandString(9)
 	conf.Plugin.Cache = common.RandString(9)
+	conf.Plugin.NodeCmd = common.RandString(9)
 	conf.Template.Path = "../scripts/native/templates"
 	return conf
 }
@@ -195,12 +204,20 @@ func InitMockEnvironment(t *testing.T) *MockServices {
 	mIndex := mockPlugin.NewMockIndex(mockCtl)
 	plugin.RegisterFactory(conf.Plugin.Index, mockIndex(mIndex))
 
+	mAppHis := mockPlugin.NewMockAppHistory(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.AppHistory, mockAppHistory(mAppHis))
+
 	mTask := mockPlugin.NewMockTask(mockCtl)
 	plugin.RegisterFactory(conf.Plugin.Task, mockTask(mTask))
 
 	mCache := mockPlugin.NewMockDataCache(mockCtl)
 	plugin.RegisterFactory(conf.Plugin.Cache, mockCache(mCache))
 
+	mNodeCmd := mockPlugin.NewMockNodeCommand(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.NodeCmd, func() (plugin.Plugin, error) {
+		return mNodeCmd, nil
+	})
+
 	_, err := NewSyncService(conf)
 	assert.Nil(t, err)
 
@@ -214,6 +231,7 @@ func InitMockEnvironment(t *testing.T) *MockServices {
 		secret:         mResource,
 		app:            mResource,
 		index:          mIndex,
+		appHis:         mAppHis,
 		objectStorage:  mockObjectStorage,
 		functionPlugin: mockFunctionPlugin,
 		pki:            mPKI,
@@ -33,6 +33,7 @@ type SyncServiceImpl struct {
 	AppService    ApplicationService
 	SecretService SecretService
 	ObjectService ObjectService
+	CmdService    NodeCommandService
 	Hooks         map[string]interface{}
 }
 
@@ -62,6 +63,10 @@ func NewSyncService(config *config.CloudConfig) (SyncService, error) {
 	if err != nil {
 		return nil, err
 	}
+	es.CmdService, err = NewNodeCommandService(config)
+	if err != nil {
+		return nil, err
+	}
 	es.Hooks[HookNamePopulateConfig] = HandlerPopulateConfig(es.PopulateConfig)
 	return es, nil
 }
@@ -69,6 +74,11 @@ func NewSyncService(config *config.CloudConfig) (SyncService, error) {
 func (t *SyncServiceImpl) Report(namespace, name, source string, report specV1.Report) (specV1.Delta, error) {
 	var err error
 	var shadow *models.Shadow
+	if results, ok := report[common.NodeCommands]; ok {
+		// the results of the commands aren't kept in the shadow
+		delete(report, common.NodeCommands)
+		t.reportCommands(namespace, name, results)
+	}
 	if source == specV1.BaetylInit {
 		shadow, err = t.NodeService.UpdateInitReport(namespace, name, report)
 		if err != nil {
@@ -128,9 +138,46 @@ func (t *SyncServiceImpl) Report(namespace, name, source string, report specV1.R
 		delta[common.NodeProps] = shadow.Desire[common.NodeProps]
 	}
 
+	commands, err := t.CmdService.Pick(namespace, name)
+	if err != nil {
+		log.L().Error("failed to pick node commands",
+			log.Any(common.KeyContextNamespace, namespace),
+			log.Any("name", name),
+			log.Error(err))
+		return nil, err
+	}
+	if len(commands) > 0 {
+		if delta == nil {
+			delta = specV1.Delta{}
+		}
+		delta[common.NodeCommands] = commands
+	}
+
 	return delta, nil
 }
 
+// reportCommands updates the status of the commands by the results reported, the malformed results are ignored
+func (t *SyncServiceImpl) reportCommands(namespace, name string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	results := map[string]models.NodeCommandResult{}
+	if err = json.Unmarshal(data, &results); err != nil {
+		log.L().Warn("ignore the malformed results of node commands",
+			log.Any(common.KeyContextNamespace, namespace),
+			log.Any("name", name),
+			log.Error(err))
+		return
+	}
+	if err = t.CmdService.Report(namespace, name, results); err != nil {
+		log.L().Error("failed to update the status of node commands",
+			log.Any(common.KeyContextNamespace, namespace),
+			log.Any("name", name),
+			log.Error(err))
+	}
+}
+
 func extractComparingReport(report specV1.Report) specV1.Report {
 	res := map[string]interface{}{}
 	if apps, ok := report["apps"]; ok {
@@ -147,6 +194,8 @@ func extractComparingReport(report specV1.Report) specV1.Report {
 
 func (t *SyncServiceImpl) Desire(namespace string, crdInfos []specV1.ResourceInfo, metadata map[string]string) ([]specV1.ResourceValue, error) {
 	var crdDatas []specV1.ResourceValue
+	// the node is got once for the config templates
+	var node *specV1.Node
 	for _, info := range crdInfos {
 		crdData := specV1.ResourceValue{
 			ResourceInfo: info,
@@ -159,6 +208,16 @@ func (t *SyncServiceImpl) Desire(namespace string, crdInfos []specV1.ResourceInf
 				log.L().Error("failed to get application", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
 				return nil, err
 			}
+			// the nodes out of the canary of an application keep running its stable version
+			if info.Version != "" && app.Version != info.Version {
+				if his, err := t.AppService.GetHistory(namespace, info.Name, info.Version); err == nil {
+					app = his
+				}
+			}
+			if app, err = t.resolveEnvSources(namespace, app); err != nil {
+				log.L().Error("failed to resolve the environment variables of application", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name), log.Error(err))
+				return nil, err
+			}
 			crdData.Value.Value = app
 		case specV1.KindConfiguration, specV1.KindConfig:
 			cfg, err := t.ConfigService.Get(nil, namespace, info.Name, info.Version)
@@ -166,6 +225,24 @@ func (t *SyncServiceImpl) Desire(namespace string, crdInfos []specV1.ResourceInf
 				log.L().Error("failed to get config", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
 				return nil, err
 			}
+			// the apps pinning a version of the config keep running it
+			if info.Version != "" && cfg.Version != info.Version {
+				if his, err := t.ConfigService.GetVersion(namespace, info.Name, info.Version); err == nil {
+					cfg = his
+				}
+			}
+			if IsConfigTemplate(cfg) {
+				if node == nil {
+					if node, err = t.NodeService.Get(nil, namespace, metadata["name"]); err != nil {
+						log.L().Error("failed to get node", log.Any(common.KeyContextNamespace, namespace), log.Any("name", metadata["name"]))
+						return nil, err
+					}
+				}
+				if err = RenderConfigTemplate(cfg, node); err != nil {
+					log.L().Error("failed to render config template", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name), log.Error(err))
+					return nil, err
+				}
+			}
 			if err = t.Hooks[HookNamePopulateConfig].(HandlerPopulateConfig)(cfg, metadata); err != nil {
 				log.L().Error("failed to populate config", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
 				return nil, err
@@ -177,6 +254,11 @@ func (t *SyncServiceImpl) Desire(namespace string, crdInfos []specV1.ResourceInf
 				log.L().Error("failed to get secret", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
 				return nil, err
 			}
+			if info.Version != "" && secret.Version != info.Version {
+				if his, err := t.SecretService.GetVersion(namespace, info.Name, info.Version); err == nil {
+					secret = his
+				}
+			}
 			crdData.Value.Value = secret
 		default:
 			return nil, fmt.Errorf("unsupported request type")
@@ -186,6 +268,106 @@ func (t *SyncServiceImpl) Desire(namespace string, crdInfos []specV1.ResourceInf
 	return crdDatas, nil
 }
 
+// resolveEnvSources resolves the environment variables referencing the secrets and configs into their values,
+// by the versions of the volumes added for the references, and removes the volumes and the mounts from the app synced
+func (t *SyncServiceImpl) resolveEnvSources(namespace string, app *specV1.Application) (*specV1.Application, error) {
+	sources := map[string]map[string]string{}
+	var volumes []specV1.Volume
+	for _, v := range app.Volumes {
+		if !models.IsEnvSourceVolume(v.Name) {
+			volumes = append(volumes, v)
+			continue
+		}
+		data, err := t.getEnvSourceData(namespace, &v)
+		if err != nil {
+			return nil, err
+		}
+		sources[v.Name] = data
+	}
+	if len(sources) == 0 {
+		return app, nil
+	}
+	resolve := func(services []specV1.Service) ([]specV1.Service, error) {
+		if services == nil {
+			return nil, nil
+		}
+		res := make([]specV1.Service, 0, len(services))
+		for _, s := range services {
+			values := map[string]string{}
+			var mounts []specV1.VolumeMount
+			for _, vm := range s.VolumeMounts {
+				data, ok := sources[vm.Name]
+				if !ok {
+					mounts = append(mounts, vm)
+					continue
+				}
+				v, ok := data[vm.SubPath]
+				if !ok {
+					return nil, fmt.Errorf("the key (%s) referenced by the environment variable (%s) of the service (%s) does not exist", vm.SubPath, vm.MountPath, s.Name)
+				}
+				values[vm.MountPath] = v
+			}
+			env := make([]specV1.Environment, 0, len(s.Env))
+			for _, e := range s.Env {
+				if v, ok := values[e.Name]; ok {
+					e.Value = v
+				}
+				env = append(env, e)
+			}
+			s.Env, s.VolumeMounts = env, mounts
+			res = append(res, s)
+		}
+		return res, nil
+	}
+	// the app got may be shared, so the resolved one is a copy
+	res := *app
+	res.Volumes = volumes
+	var err error
+	if res.InitServices, err = resolve(app.InitServices); err != nil {
+		return nil, err
+	}
+	if res.Services, err = resolve(app.Services); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// getEnvSourceData returns the data of the secret or config at the version referenced by the volume,
+// the latest version is taken if the version is not available any more like the volumes synced
+func (t *SyncServiceImpl) getEnvSourceData(namespace string, v *specV1.Volume) (map[string]string, error) {
+	res := map[string]string{}
+	if v.Secret != nil {
+		secret, err := t.SecretService.Get(namespace, v.Secret.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		if v.Secret.Version != "" && secret.Version != v.Secret.Version {
+			if his, err := t.SecretService.GetVersion(namespace, v.Secret.Name, v.Secret.Version); err == nil {
+				secret = his
+			}
+		}
+		for k, val := range secret.Data {
+			res[k] = string(val)
+		}
+		return res, nil
+	}
+	if v.Config != nil {
+		cfg, err := t.ConfigService.Get(nil, namespace, v.Config.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		if v.Config.Version != "" && cfg.Version != v.Config.Version {
+			if his, err := t.ConfigService.GetVersion(namespace, v.Config.Name, v.Config.Version); err == nil {
+				cfg = his
+			}
+		}
+		for k, val := range cfg.Data {
+			res[k] = val
+		}
+	}
+	return res, nil
+}
+
 func (t *SyncServiceImpl) PopulateConfig(cfg *specV1.Configuration, metadata map[string]string) error {
 	for k, v := range cfg.Data {
 		if strings.HasPrefix(k, common.ConfigObjectPrefix) {
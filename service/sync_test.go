@@ -25,9 +25,12 @@ func TestReport(t *testing.T) {
 
 	ns.EXPECT().UpdateReport(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
 	ns.EXPECT().Get(nil, gomock.Any(), gomock.Any()).Return(&specV1.Node{}, nil)
+	cs := ms.NewMockNodeCommandService(mockObject.ctl)
+	cs.EXPECT().Pick(namespace, name).Return(nil, nil)
 
 	sync := SyncServiceImpl{
 		NodeService: ns,
+		CmdService:  cs,
 	}
 	info := specV1.Report{}
 	response, err := sync.Report(namespace, name, specV1.BaetylCore, info)
@@ -66,6 +69,90 @@ func TestReport(t *testing.T) {
 	assert.NotNil(t, response)
 }
 
+func TestReportNodeCommands(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ns := ms.NewMockNodeService(mockObject.ctl)
+	cs := ms.NewMockNodeCommandService(mockObject.ctl)
+	sync := SyncServiceImpl{
+		NodeService: ns,
+		CmdService:  cs,
+	}
+
+	namespace, name := "ns01", "node01"
+	shadow := &models.Shadow{
+		Desire: specV1.Desire{
+			common.DesiredSysApplications: []specV1.AppInfo{{Name: "sysapp01", Version: "v1"}},
+		},
+		Report: specV1.Report{
+			common.DesiredSysApplications: []specV1.AppInfo{{Name: "sysapp01", Version: "v1"}},
+		},
+	}
+	report := specV1.Report{
+		common.NodeCommands: map[string]interface{}{
+			"1": map[string]interface{}{"status": models.NodeCommandSucceeded},
+		},
+	}
+	commands := []models.NodeCommand{{ID: 2, Type: models.NodeCommandReboot, Status: models.NodeCommandDispatched}}
+
+	cs.EXPECT().Report(namespace, name, map[string]models.NodeCommandResult{
+		"1": {Status: models.NodeCommandSucceeded},
+	}).Return(nil).Times(1)
+	ns.EXPECT().UpdateReport(namespace, name, gomock.Any()).DoAndReturn(func(_, _ string, r specV1.Report) (*models.Shadow, error) {
+		_, ok := r[common.NodeCommands]
+		assert.False(t, ok)
+		return shadow, nil
+	}).Times(1)
+	ns.EXPECT().Get(nil, namespace, name).Return(&specV1.Node{}, nil).Times(1)
+	cs.EXPECT().Pick(namespace, name).Return(commands, nil).Times(1)
+	delta, err := sync.Report(namespace, name, specV1.BaetylCore, report)
+	assert.NoError(t, err)
+	assert.Equal(t, commands, delta[common.NodeCommands])
+
+	cs.EXPECT().Report(namespace, name, gomock.Any()).Times(0)
+	ns.EXPECT().UpdateReport(namespace, name, gomock.Any()).Return(shadow, nil).Times(1)
+	ns.EXPECT().Get(nil, namespace, name).Return(&specV1.Node{}, nil).Times(1)
+	cs.EXPECT().Pick(namespace, name).Return(nil, fmt.Errorf("error")).Times(1)
+	_, err = sync.Report(namespace, name, specV1.BaetylCore, specV1.Report{common.NodeCommands: "malformed"})
+	assert.Error(t, err)
+}
+
+func TestSyncDesireConfigTemplate(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs := ms.NewMockConfigService(mockObject.ctl)
+	ns := ms.NewMockNodeService(mockObject.ctl)
+	sync := SyncServiceImpl{
+		ConfigService: cs,
+		NodeService:   ns,
+		Hooks:         map[string]interface{}{},
+	}
+	sync.Hooks[HookNamePopulateConfig] = HandlerPopulateConfig(sync.PopulateConfig)
+
+	reqs := []specV1.ResourceInfo{
+		{Kind: specV1.KindConfiguration, Name: "tpl1", Version: "v1"},
+		{Kind: specV1.KindConfiguration, Name: "tpl2", Version: "v1"},
+		{Kind: specV1.KindConfiguration, Name: "plain", Version: "v1"},
+	}
+	labels := map[string]string{common.LabelConfigTemplate: "true"}
+	cs.EXPECT().Get(nil, "default", "tpl1", "v1").Return(&specV1.Configuration{Name: "tpl1", Version: "v1", Labels: labels, Data: map[string]string{"a": "{{ .node.name }}"}}, nil).Times(1)
+	cs.EXPECT().Get(nil, "default", "tpl2", "v1").Return(&specV1.Configuration{Name: "tpl2", Version: "v1", Labels: labels, Data: map[string]string{"b": "{{ .node.labels.zone }}"}}, nil).Times(1)
+	cs.EXPECT().Get(nil, "default", "plain", "v1").Return(&specV1.Configuration{Name: "plain", Version: "v1", Data: map[string]string{"c": "{{ .node.name }}"}}, nil).Times(1)
+	ns.EXPECT().Get(nil, "default", "node01").Return(&specV1.Node{Name: "node01", Labels: map[string]string{"zone": "east"}}, nil).Times(1)
+
+	res, err := sync.Desire("default", reqs, map[string]string{"namespace": "default", "name": "node01"})
+	assert.NoError(t, err)
+	assert.Len(t, res, 3)
+	assert.Equal(t, "node01", res[0].Value.Value.(*specV1.Configuration).Data["a"])
+	assert.Equal(t, "east", res[1].Value.Value.(*specV1.Configuration).Data["b"])
+	assert.Equal(t, "{{ .node.name }}", res[2].Value.Value.(*specV1.Configuration).Data["c"])
+
+	cs.EXPECT().Get(nil, "default", "tpl1", "v1").Return(&specV1.Configuration{Name: "tpl1", Version: "v1", Labels: labels, Data: map[string]string{"a": "{{ .node.name }}"}}, nil).Times(1)
+	ns.EXPECT().Get(nil, "default", "node01").Return(nil, fmt.Errorf("error")).Times(1)
+	_, err = sync.Desire("default", reqs[:1], map[string]string{"namespace": "default", "name": "node01"})
+	assert.Error(t, err)
+}
+
 func TestSyncDesire(t *testing.T) {
 	mockObject := InitMockEnvironment(t)
 	defer mockObject.Close()
@@ -157,6 +244,79 @@ func TestSyncDesire(t *testing.T) {
 	assert.Equal(t, resObj2.MD5, obj2.MD5)
 	assert.Equal(t, resObj2.URL, obj2.URL)
 	assert.Empty(t, resObj2.Token)
+
+	// the stable version of the app in canary is got from the history
+	stable := &specV1.Application{Name: "app", Version: "v0"}
+	as.EXPECT().Get(namespace, "app", "v0").Return(app, nil).Times(1)
+	as.EXPECT().GetHistory(namespace, "app", "v0").Return(stable, nil).Times(1)
+	res, err = sync.Desire(namespace, []specV1.ResourceInfo{{Kind: specV1.KindApplication, Name: "app", Version: "v0"}}, map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, stable, res[0].Value.Value)
+}
+
+func TestSyncDesireEnvSources(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs := ms.NewMockConfigService(mockObject.ctl)
+	as := ms.NewMockApplicationService(mockObject.ctl)
+	ss := ms.NewMockSecretService(mockObject.ctl)
+	sync := SyncServiceImpl{
+		ConfigService: cs,
+		AppService:    as,
+		SecretService: ss,
+	}
+	app := &specV1.Application{
+		Name:    "app",
+		Version: "v1",
+		Services: []specV1.Service{{
+			Name: "s0",
+			Env: []specV1.Environment{
+				{Name: "LOG_LEVEL", Value: "debug"},
+				{Name: "PASSWORD"},
+				{Name: "HOST"},
+				{Name: "PLAIN", Value: "${secret:sec0/password}"},
+			},
+			VolumeMounts: []specV1.VolumeMount{
+				{Name: "cfg", MountPath: "/etc/cfg"},
+				{Name: "baetyl-env-secret-sec0", MountPath: "PASSWORD", SubPath: "password", ReadOnly: true},
+				{Name: "baetyl-env-config-cfg0", MountPath: "HOST", SubPath: "host", ReadOnly: true},
+			},
+		}},
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg0", Version: "c2"}}},
+			{Name: "baetyl-env-secret-sec0", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "sec0", Version: "s1"}}},
+			{Name: "baetyl-env-config-cfg0", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg0", Version: "c2"}}},
+		},
+	}
+	reqs := []specV1.ResourceInfo{{Kind: specV1.KindApplication, Name: "app", Version: "v1"}}
+
+	// the references are resolved by the versions of the volumes
+	as.EXPECT().Get("default", "app", "v1").Return(app, nil).Times(1)
+	ss.EXPECT().Get("default", "sec0", "").Return(&specV1.Secret{Name: "sec0", Version: "s2", Data: map[string][]byte{"password": []byte("new")}}, nil).Times(1)
+	ss.EXPECT().GetVersion("default", "sec0", "s1").Return(&specV1.Secret{Name: "sec0", Version: "s1", Data: map[string][]byte{"password": []byte("old")}}, nil).Times(1)
+	cs.EXPECT().Get(nil, "default", "cfg0", "").Return(&specV1.Configuration{Name: "cfg0", Version: "c2", Data: map[string]string{"host": "localhost"}}, nil).Times(1)
+	res, err := sync.Desire("default", reqs, map[string]string{})
+	assert.NoError(t, err)
+	synced := res[0].Value.Value.(*specV1.Application)
+	assert.Equal(t, []specV1.Environment{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "PASSWORD", Value: "old"},
+		{Name: "HOST", Value: "localhost"},
+		{Name: "PLAIN", Value: "${secret:sec0/password}"},
+	}, synced.Services[0].Env)
+	assert.Equal(t, []specV1.VolumeMount{{Name: "cfg", MountPath: "/etc/cfg"}}, synced.Services[0].VolumeMounts)
+	assert.Equal(t, app.Volumes[:1], synced.Volumes)
+	// the app got is kept as it is
+	assert.Len(t, app.Services[0].VolumeMounts, 3)
+	assert.Equal(t, "", app.Services[0].Env[1].Value)
+
+	// the key removed from the secret
+	as.EXPECT().Get("default", "app", "v1").Return(app, nil).Times(1)
+	ss.EXPECT().Get("default", "sec0", "").Return(&specV1.Secret{Name: "sec0", Version: "s1", Data: map[string][]byte{}}, nil).Times(1)
+	cs.EXPECT().Get(nil, "default", "cfg0", "").Return(&specV1.Configuration{Name: "cfg0", Version: "c2", Data: map[string]string{"host": "localhost"}}, nil).Times(1)
+	_, err = sync.Desire("default", reqs, map[string]string{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the key (password) referenced by the environment variable (PASSWORD) of the service (s0) does not exist")
 }
 
 func TestSyncService_Report(t *testing.T) {
@@ -31,6 +31,8 @@ var params = map[string]interface{}{
 	"NodeCertPem":                "---node cert pem---",
 	"NodeCertKey":                "---node cert key---",
 	"NodeCertCa":                 "---node cert ca---",
+	"NodeToken":                  "",
+	"NodeTokenData":              "",
 	"CoreFrequency":              "20s",
 	"CoreAPIPort":                30050,
 	"BaetylCoreByteUint":         "KB",
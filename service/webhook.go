@@ -0,0 +1,521 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/webhook.go -package=service github.com/baetyl/baetyl-cloud/v2/service WebhookService
+
+const (
+	HeaderWebhookEvent     = "X-Baetyl-Event"
+	HeaderWebhookDelivery  = "X-Baetyl-Delivery"
+	HeaderWebhookSignature = "X-Baetyl-Signature"
+
+	webhookResponseMaxSize = 64 * 1024
+
+	certCheckClaimKeyPrefix = "baetyl-cloud:cert-check-claim:"
+	certExpiringKeyPrefix   = "baetyl-cloud:cert-expiring:"
+	// certExpiringKeptChecks the number of the checks the notified certificates of a namespace are kept for,
+	// so that the ones of the deleted namespaces expire
+	certExpiringKeptChecks = 10
+)
+
+// WebhookService manages the webhook subscriptions and delivers the events to them
+type WebhookService interface {
+	Get(namespace, name string) (*models.Webhook, error)
+	List(namespace string, listOptions *models.ListOptions) (*models.WebhookList, error)
+	Create(webhook *models.Webhook) (*models.Webhook, error)
+	Update(webhook *models.Webhook) (*models.Webhook, error)
+	Delete(namespace, name string) error
+	ListDeadLetters(namespace, name string, filter *models.Filter) (*models.WebhookDeadLetterList, error)
+	// Notify delivers the event to the webhooks of its namespace subscribing to the type asynchronously,
+	// the payload is retried with backoff and kept as a dead letter if all attempts fail
+	Notify(eventType string, event *models.Event)
+	// Test posts a sample event to the webhook once and returns the result
+	Test(webhook *models.Webhook) *models.WebhookDelivery
+	// Run checks the expiring certificates periodically until stopped, each check is claimed by one of the replicas
+	Run(stop <-chan struct{})
+	// Close stops retrying the deliveries in progress, which are kept as dead letters, and waits for them
+	Close()
+}
+
+type webhookService struct {
+	cfg       config.Webhook
+	webhook   plugin.Webhook
+	namespace plugin.Namespace
+	secret    SecretService
+	// kms the signing secrets of the webhooks are stored as plaintext if it is nil
+	kms    plugin.KMS
+	client *http.Client
+	// sem limits the payloads posted at the same time, which is not held during the backoff
+	sem chan struct{}
+	// pending limits the payloads being delivered, including the ones waiting for retries
+	pending chan struct{}
+	// store keeps the claims of the checks and the expiration of the certificates of each namespace notified
+	// by the last check, so that a certificate is notified once until it is renewed, which is shared by the
+	// replicas if it is of redis
+	store  persist.CacheStore
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+	log    *log.Logger
+}
+
+// NewWebhookService NewWebhookService
+func NewWebhookService(config *config.CloudConfig) (WebhookService, error) {
+	webhook, err := plugin.GetPlugin(config.Plugin.Webhook)
+	if err != nil {
+		return nil, err
+	}
+	ns, err := plugin.GetPlugin(config.Plugin.Resource)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := NewSecretService(config)
+	if err != nil {
+		return nil, err
+	}
+	store, err := NewCacheStore(config.AdminServer.Cache, config.Webhook.CertCheckInterval)
+	if err != nil {
+		return nil, err
+	}
+	s := newWebhookService(config.Webhook, webhook.(plugin.Webhook), ns.(plugin.Namespace), secret, store)
+	if config.Plugin.KMS != "" {
+		kms, err := plugin.GetPlugin(config.Plugin.KMS)
+		if err != nil {
+			return nil, err
+		}
+		s.kms = kms.(plugin.KMS)
+	}
+	return s, nil
+}
+
+func newWebhookService(cfg config.Webhook, webhook plugin.Webhook, ns plugin.Namespace, secret SecretService, store persist.CacheStore) *webhookService {
+	concurrency := cfg.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	queueSize := cfg.QueueSize
+	if queueSize < concurrency {
+		queueSize = concurrency
+	}
+	return &webhookService{
+		cfg:       cfg,
+		webhook:   webhook,
+		namespace: ns,
+		secret:    secret,
+		client:    common.NewGuardedHTTPClient(cfg.Timeout, cfg.AllowPrivateNetwork),
+		sem:       make(chan struct{}, concurrency),
+		pending:   make(chan struct{}, queueSize),
+		store:     store,
+		done:      make(chan struct{}),
+		log:       log.With(log.Any("service", "webhook")),
+	}
+}
+
+// Get get a webhook
+func (s *webhookService) Get(namespace, name string) (*models.Webhook, error) {
+	res, err := s.webhook.GetWebhook(nil, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(res)
+}
+
+// List list webhooks
+func (s *webhookService) List(namespace string, listOptions *models.ListOptions) (*models.WebhookList, error) {
+	res, err := s.webhook.ListWebhook(nil, namespace, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res.Items {
+		if _, err = s.decrypt(&res.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Create create a webhook
+func (s *webhookService) Create(webhook *models.Webhook) (*models.Webhook, error) {
+	webhook, err := s.encrypt(webhook)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.webhook.CreateWebhook(nil, webhook)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(res)
+}
+
+// Update update a webhook
+func (s *webhookService) Update(webhook *models.Webhook) (*models.Webhook, error) {
+	webhook, err := s.encrypt(webhook)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.webhook.UpdateWebhook(nil, webhook)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(res)
+}
+
+// encrypt returns a copy of the webhook whose signing secret is encrypted if the kms is configured
+func (s *webhookService) encrypt(webhook *models.Webhook) (*models.Webhook, error) {
+	if s.kms == nil || webhook.Secret == "" {
+		return webhook, nil
+	}
+	enc, err := encryptValue(s.kms, []byte(webhook.Secret))
+	if err != nil {
+		return nil, err
+	}
+	res := *webhook
+	res.Secret = string(enc)
+	return &res, nil
+}
+
+// decrypt decrypts the signing secret of the webhook in place,
+// the ones stored in plaintext before the kms is configured are kept as they are
+func (s *webhookService) decrypt(webhook *models.Webhook) (*models.Webhook, error) {
+	secret, err := decryptValue(s.kms, []byte(webhook.Secret))
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = string(secret)
+	return webhook, nil
+}
+
+// Delete delete a webhook, its dead letters are kept
+func (s *webhookService) Delete(namespace, name string) error {
+	return s.webhook.DeleteWebhook(nil, namespace, name)
+}
+
+// ListDeadLetters list the payloads failed to be delivered to the webhook, the latest first
+func (s *webhookService) ListDeadLetters(namespace, name string, filter *models.Filter) (*models.WebhookDeadLetterList, error) {
+	return s.webhook.ListWebhookDeadLetter(nil, namespace, name, filter)
+}
+
+func (s *webhookService) Notify(eventType string, event *models.Event) {
+	// the webhooks are listed out of the request, the payloads are kept as dead letters at once after shutdown
+	s.mu.Lock()
+	closed := s.closed
+	if !closed {
+		s.wg.Add(1)
+	}
+	s.mu.Unlock()
+	if closed {
+		s.notify(eventType, event)
+		return
+	}
+	go func() {
+		defer s.wg.Done()
+		s.notify(eventType, event)
+	}()
+}
+
+// notify delivers the payload to each webhook subscribing to the type in its own goroutine
+func (s *webhookService) notify(eventType string, event *models.Event) {
+	list, err := s.webhook.ListWebhook(nil, event.Namespace, &models.ListOptions{})
+	if err != nil {
+		s.log.Error("failed to list webhooks", log.Any("namespace", event.Namespace), log.Error(err))
+		return
+	}
+	payload := newWebhookPayload(eventType, event)
+	for i := range list.Items {
+		webhook := list.Items[i]
+		if !webhook.Subscribes(eventType) {
+			continue
+		}
+		if _, err = s.decrypt(&webhook); err != nil {
+			s.keepDeadLetter(&webhook, payload, 0, err)
+			continue
+		}
+		if err = s.enqueue(); err != nil {
+			s.keepDeadLetter(&webhook, payload, 0, err)
+			continue
+		}
+		go func() {
+			defer s.dequeue()
+			s.deliver(&webhook, payload)
+		}()
+	}
+}
+
+func (s *webhookService) enqueue() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("the webhook service is closed")
+	}
+	select {
+	case s.pending <- struct{}{}:
+		s.wg.Add(1)
+		return nil
+	default:
+		return fmt.Errorf("too many payloads are being delivered")
+	}
+}
+
+func (s *webhookService) dequeue() {
+	<-s.pending
+	s.wg.Done()
+}
+
+func (s *webhookService) Test(webhook *models.Webhook) *models.WebhookDelivery {
+	payload := newWebhookPayload(models.WebhookEventTest, &models.Event{
+		Namespace: webhook.Namespace,
+		Kind:      string(common.Webhook),
+		Name:      webhook.Name,
+		Message:   "this is a test event",
+		Timestamp: time.Now(),
+	})
+	res := &models.WebhookDelivery{Payload: payload}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.StatusCode, err = s.post(webhook, payload, body)
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
+
+func (s *webhookService) Run(stop <-chan struct{}) {
+	if s.cfg.CertCheckInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.cfg.CertCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if s.claimCertificateCheck(now) {
+				s.checkCertificates(now)
+			}
+		}
+	}
+}
+
+func (s *webhookService) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// deliver posts the payload until it succeeds or the attempts are exhausted, then it is kept as a dead letter
+func (s *webhookService) deliver(webhook *models.Webhook, payload *models.WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		s.log.Error("failed to marshal webhook payload", log.Error(err))
+		return
+	}
+	attempts := s.cfg.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := s.cfg.Backoff
+	i := 1
+	for ; ; i++ {
+		if _, err = s.postLimited(webhook, payload, body); err == nil {
+			return
+		}
+		s.log.Warn("failed to post webhook", log.Any("namespace", webhook.Namespace), log.Any("webhook", webhook.Name),
+			log.Any("event", payload.Event), log.Any("attempt", i), log.Error(err))
+		if i == attempts {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-s.done:
+			s.keepDeadLetter(webhook, payload, i, fmt.Errorf("%s, and the retries are stopped on shutdown", err.Error()))
+			return
+		}
+		if backoff *= 2; s.cfg.MaxBackoff > 0 && backoff > s.cfg.MaxBackoff {
+			backoff = s.cfg.MaxBackoff
+		}
+	}
+	s.keepDeadLetter(webhook, payload, i, err)
+}
+
+// keepDeadLetter keeps the payload failed to be delivered after the attempts
+func (s *webhookService) keepDeadLetter(webhook *models.Webhook, payload *models.WebhookPayload, attempts int, cause error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		s.log.Error("failed to marshal webhook payload", log.Error(err))
+		return
+	}
+	letter := &models.WebhookDeadLetter{
+		Namespace: webhook.Namespace,
+		Webhook:   webhook.Name,
+		Event:     payload.Event,
+		Payload:   string(body),
+		Attempts:  attempts,
+		Error:     cause.Error(),
+		Timestamp: time.Now(),
+	}
+	if err = s.webhook.CreateWebhookDeadLetter(nil, letter); err != nil {
+		s.log.Error("failed to keep webhook dead letter", log.Any("namespace", webhook.Namespace),
+			log.Any("webhook", webhook.Name), log.Any("payload", letter.Payload), log.Error(err))
+	}
+}
+
+// postLimited posts the payload once the number of the payloads being posted is under the concurrency
+func (s *webhookService) postLimited(webhook *models.Webhook, payload *models.WebhookPayload, body []byte) (int, error) {
+	s.sem <- struct{}{}
+	defer func() { <-s.sem }()
+	return s.post(webhook, payload, body)
+}
+
+// post posts the payload to the webhook, the responses out of 2xx are treated as failures
+func (s *webhookService) post(webhook *models.Webhook, payload *models.WebhookPayload, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookEvent, payload.Event)
+	req.Header.Set(HeaderWebhookDelivery, payload.ID)
+	if webhook.Secret != "" {
+		req.Header.Set(HeaderWebhookSignature, "sha256="+SignWebhookPayload(webhook.Secret, body))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, webhookResponseMaxSize))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("the webhook responded with status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// claimCertificateCheck returns whether the check of the interval is claimed by this replica, the check is skipped
+// if it fails to be claimed, since it is done by the others or by the next interval
+func (s *webhookService) claimCertificateCheck(now time.Time) bool {
+	slot := now.Truncate(s.cfg.CertCheckInterval).UnixNano()
+	ok, err := AddCacheValue(s.store, fmt.Sprintf("%s%d", certCheckClaimKeyPrefix, slot), true, s.cfg.CertCheckInterval)
+	if err != nil {
+		s.log.Error("failed to claim the check of certificates", log.Error(err))
+		return false
+	}
+	return ok
+}
+
+// checkCertificates notifies the certificates expiring soon to the namespaces subscribing to cert.expiring,
+// a certificate is notified once until its expiration is changed
+func (s *webhookService) checkCertificates(now time.Time) {
+	nss, err := s.namespace.ListNamespace(&models.ListOptions{})
+	if err != nil {
+		s.log.Error("failed to list namespaces", log.Error(err))
+		return
+	}
+	for _, ns := range nss.Items {
+		list, err := s.webhook.ListWebhook(nil, ns.Name, &models.ListOptions{})
+		if err != nil {
+			s.log.Error("failed to list webhooks", log.Any("namespace", ns.Name), log.Error(err))
+			continue
+		}
+		subscribed := false
+		for i := range list.Items {
+			if list.Items[i].Subscribes(models.WebhookEventCertExpiring) {
+				subscribed = true
+				break
+			}
+		}
+		if !subscribed {
+			if err = s.store.Delete(certExpiringKeyPrefix + ns.Name); err != nil && err != persist.ErrCacheMiss {
+				s.log.Error("failed to delete notified certificates", log.Any("namespace", ns.Name), log.Error(err))
+			}
+			continue
+		}
+		secrets, err := s.secret.List(ns.Name, &models.ListOptions{
+			LabelSelector: fmt.Sprintf("!%s,%s=%s", common.LabelSystem, specV1.SecretLabel, specV1.SecretCertificate),
+		})
+		if err != nil {
+			s.log.Error("failed to list certificates", log.Any("namespace", ns.Name), log.Error(err))
+			continue
+		}
+		var last map[string]time.Time
+		if err = s.store.Get(certExpiringKeyPrefix+ns.Name, &last); err != nil && err != persist.ErrCacheMiss {
+			s.log.Error("failed to get notified certificates", log.Any("namespace", ns.Name), log.Error(err))
+			continue
+		}
+		expiring := map[string]time.Time{}
+		deadline := now.Add(s.cfg.CertExpiringWithin)
+		for i := range secrets.Items {
+			cert := models.FromSecretToCertificate(&secrets.Items[i], false)
+			notAfter, err := cert.ParseNotAfter()
+			if err != nil || notAfter.After(deadline) {
+				continue
+			}
+			expiring[cert.Name] = notAfter
+			if t, ok := last[cert.Name]; ok && t.Equal(notAfter) {
+				continue
+			}
+			s.Notify(models.WebhookEventCertExpiring, &models.Event{
+				Namespace: ns.Name,
+				Kind:      "Certificate",
+				Name:      cert.Name,
+				Message:   fmt.Sprintf("the certificate expires at %s", notAfter.UTC().Format(time.RFC3339)),
+				Timestamp: now,
+			})
+		}
+		if err = s.store.Set(certExpiringKeyPrefix+ns.Name, expiring, certExpiringKeptChecks*s.cfg.CertCheckInterval); err != nil {
+			s.log.Error("failed to keep notified certificates", log.Any("namespace", ns.Name), log.Error(err))
+		}
+	}
+}
+
+func newWebhookPayload(eventType string, event *models.Event) *models.WebhookPayload {
+	ts := event.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	return &models.WebhookPayload{
+		ID:        common.UUIDPrune(),
+		Event:     eventType,
+		Namespace: event.Namespace,
+		Kind:      event.Kind,
+		Name:      event.Name,
+		Message:   event.Message,
+		Timestamp: ts.UTC(),
+	}
+}
+
+// SignWebhookPayload returns the hex encoded HMAC-SHA256 of the payload, which is sent as sha256=<signature>
+// in the X-Baetyl-Signature header for the subscribers to verify
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
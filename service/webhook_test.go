@@ -0,0 +1,371 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/cache/persist"
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// webhookReceiver records the requests posted to the webhook, and fails the first ones as configured
+type webhookReceiver struct {
+	sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.Lock()
+	defer r.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestWebhookService_Notify(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	mWebhook := mockPlugin.NewMockWebhook(mockCtl)
+	s := newWebhookService(config.Webhook{Timeout: time.Second, MaxAttempts: 3, Concurrency: 2}, mWebhook, nil, nil, nil)
+	s.client = server.Client()
+
+	list := &models.WebhookList{Items: []models.Webhook{
+		{Name: "signed", Namespace: "default", URL: server.URL, Secret: "s3cret", Events: []string{"app.updated"}},
+		{Name: "all", Namespace: "default", URL: server.URL, Events: []string{models.WebhookEventAll}},
+		{Name: "other", Namespace: "default", URL: server.URL, Events: []string{models.WebhookEventNodeOffline}},
+	}}
+	mWebhook.EXPECT().ListWebhook(nil, "default", gomock.Any()).Return(list, nil).Times(1)
+	s.Notify("app.updated", &models.Event{Namespace: "default", Kind: "app", Name: "app01", Message: "the app is updated"})
+	s.wg.Wait()
+
+	// only the subscribers are notified, and the payloads are signed if the secret is set
+	assert.Len(t, receiver.requests, 2)
+	signed := 0
+	for i, req := range receiver.requests {
+		assert.Equal(t, "app.updated", req.Header.Get(HeaderWebhookEvent))
+		assert.NotEmpty(t, req.Header.Get(HeaderWebhookDelivery))
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		var payload models.WebhookPayload
+		assert.NoError(t, json.Unmarshal(receiver.bodies[i], &payload))
+		assert.Equal(t, "app.updated", payload.Event)
+		assert.Equal(t, "app01", payload.Name)
+		assert.Equal(t, req.Header.Get(HeaderWebhookDelivery), payload.ID)
+		if sig := req.Header.Get(HeaderWebhookSignature); sig != "" {
+			assert.Equal(t, "sha256="+SignWebhookPayload("s3cret", receiver.bodies[i]), sig)
+			signed++
+		}
+	}
+	assert.Equal(t, 1, signed)
+
+	mWebhook.EXPECT().ListWebhook(nil, "default", gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	s.Notify("app.updated", &models.Event{Namespace: "default"})
+	s.wg.Wait()
+	assert.Len(t, receiver.requests, 2)
+}
+
+func TestWebhookService_Encryption(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	mWebhook := mockPlugin.NewMockWebhook(mockCtl)
+	s := newWebhookService(config.Webhook{Timeout: time.Second, MaxAttempts: 1}, mWebhook, nil, nil, nil)
+	s.client = server.Client()
+	s.kms = mockKMS(mockCtl)
+
+	// the signing secret is stored encrypted, and returned decrypted
+	var stored *models.Webhook
+	mWebhook.EXPECT().CreateWebhook(nil, gomock.Any()).DoAndReturn(func(_ interface{}, w *models.Webhook) (*models.Webhook, error) {
+		stored = w
+		res := *w
+		return &res, nil
+	}).Times(1)
+	webhook := &models.Webhook{Name: "hook", Namespace: "default", URL: server.URL, Secret: "s3cret", Events: []string{"*"}}
+	res, err := s.Create(webhook)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", res.Secret)
+	assert.Equal(t, "s3cret", webhook.Secret)
+	assert.True(t, strings.HasPrefix(stored.Secret, secretEncryptedPrefix))
+	assert.NotContains(t, stored.Secret, "s3cret")
+
+	mWebhook.EXPECT().GetWebhook(nil, "default", "hook").DoAndReturn(func(_ interface{}, _, _ string) (*models.Webhook, error) {
+		res := *stored
+		return &res, nil
+	}).Times(1)
+	res, err = s.Get("default", "hook")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", res.Secret)
+
+	// the secrets stored in plaintext before the kms is configured are kept
+	mWebhook.EXPECT().ListWebhook(nil, "default", gomock.Any()).DoAndReturn(func(_ interface{}, _ string, _ *models.ListOptions) (*models.WebhookList, error) {
+		return &models.WebhookList{Items: []models.Webhook{*stored, {Name: "plain", Namespace: "default", Secret: "plain"}}}, nil
+	}).Times(1)
+	list, err := s.List("default", &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", list.Items[0].Secret)
+	assert.Equal(t, "plain", list.Items[1].Secret)
+
+	// the payloads are signed by the decrypted secret
+	mWebhook.EXPECT().ListWebhook(nil, "default", gomock.Any()).Return(&models.WebhookList{Items: []models.Webhook{*stored}}, nil).Times(1)
+	s.Notify("app.updated", &models.Event{Namespace: "default"})
+	s.wg.Wait()
+	assert.Len(t, receiver.requests, 1)
+	assert.Equal(t, "sha256="+SignWebhookPayload("s3cret", receiver.bodies[0]), receiver.requests[0].Header.Get(HeaderWebhookSignature))
+
+	// the encrypted secrets can't be read without the kms
+	s.kms = nil
+	mWebhook.EXPECT().GetWebhook(nil, "default", "hook").Return(stored, nil).Times(1)
+	_, err = s.Get("default", "hook")
+	assert.Error(t, err)
+}
+
+func TestWebhookService_Retry(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	receiver := &webhookReceiver{failures: 2}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	mWebhook := mockPlugin.NewMockWebhook(mockCtl)
+	s := newWebhookService(config.Webhook{Timeout: time.Second, MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond}, mWebhook, nil, nil, nil)
+	s.client = server.Client()
+	webhook := &models.Webhook{Name: "hook", Namespace: "default", URL: server.URL, Events: []string{"*"}}
+
+	// succeeds at the last attempt
+	s.deliver(webhook, newWebhookPayload("node.offline", &models.Event{Namespace: "default"}))
+	assert.Len(t, receiver.requests, 3)
+
+	// kept as a dead letter after all attempts fail
+	receiver.failures = 3
+	var letter *models.WebhookDeadLetter
+	mWebhook.EXPECT().CreateWebhookDeadLetter(nil, gomock.Any()).DoAndReturn(func(_ interface{}, l *models.WebhookDeadLetter) error {
+		letter = l
+		return nil
+	}).Times(1)
+	payload := newWebhookPayload("node.offline", &models.Event{Namespace: "default", Name: "node01"})
+	s.deliver(webhook, payload)
+	assert.Len(t, receiver.requests, 6)
+	assert.NotNil(t, letter)
+	assert.Equal(t, "default", letter.Namespace)
+	assert.Equal(t, "hook", letter.Webhook)
+	assert.Equal(t, "node.offline", letter.Event)
+	assert.Equal(t, 3, letter.Attempts)
+	assert.Contains(t, letter.Error, "500")
+	assert.Contains(t, letter.Payload, payload.ID)
+}
+
+func TestWebhookService_Test(t *testing.T) {
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	s := newWebhookService(config.Webhook{Timeout: time.Second}, nil, nil, nil, nil)
+	s.client = server.Client()
+	res := s.Test(&models.Webhook{Name: "hook", Namespace: "default", URL: server.URL})
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Empty(t, res.Error)
+	assert.Equal(t, models.WebhookEventTest, res.Payload.Event)
+	assert.Len(t, receiver.requests, 1)
+
+	// the test is not retried
+	receiver.failures = 1
+	res = s.Test(&models.Webhook{Name: "hook", Namespace: "default", URL: server.URL})
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	assert.NotEmpty(t, res.Error)
+	assert.Len(t, receiver.requests, 2)
+
+	res = s.Test(&models.Webhook{Name: "hook", Namespace: "default", URL: "http://127.0.0.1:0"})
+	assert.NotEmpty(t, res.Error)
+
+	// the loopback, link-local and metadata service addresses are never posted
+	s = newWebhookService(config.Webhook{Timeout: time.Second, AllowPrivateNetwork: true}, nil, nil, nil, nil)
+	for _, url := range []string{server.URL, "http://169.254.169.254/latest/meta-data", "http://[::1]:80"} {
+		res = s.Test(&models.Webhook{Name: "hook", Namespace: "default", URL: url})
+		assert.Contains(t, res.Error, "is forbidden", url)
+	}
+	assert.Len(t, receiver.requests, 2)
+}
+
+func TestWebhookService_Limits(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	receiver := &webhookReceiver{failures: 100}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	mWebhook := mockPlugin.NewMockWebhook(mockCtl)
+	s := newWebhookService(config.Webhook{Timeout: time.Second, MaxAttempts: 3, Backoff: time.Hour, Concurrency: 1, QueueSize: 2}, mWebhook, nil, nil, nil)
+	s.client = server.Client()
+
+	list := &models.WebhookList{Items: []models.Webhook{
+		{Name: "a", Namespace: "default", URL: server.URL, Events: []string{"*"}},
+		{Name: "b", Namespace: "default", URL: server.URL, Events: []string{"*"}},
+		{Name: "c", Namespace: "default", URL: server.URL, Events: []string{"*"}},
+	}}
+	var lock sync.Mutex
+	letters := map[string]*models.WebhookDeadLetter{}
+	mWebhook.EXPECT().ListWebhook(nil, "default", gomock.Any()).Return(list, nil).Times(2)
+	mWebhook.EXPECT().CreateWebhookDeadLetter(nil, gomock.Any()).DoAndReturn(func(_ interface{}, l *models.WebhookDeadLetter) error {
+		lock.Lock()
+		defer lock.Unlock()
+		letters[l.Webhook] = l
+		return nil
+	}).Times(4)
+
+	// the payloads beyond the queue are kept as dead letters at once
+	s.Notify("app.updated", &models.Event{Namespace: "default"})
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(letters) == 1 && letters["c"] != nil && letters["c"].Attempts == 0
+	}, time.Second*5, time.Millisecond*10)
+
+	// the payloads waiting for retries don't hold the concurrency
+	assert.Eventually(t, func() bool {
+		receiver.Lock()
+		defer receiver.Unlock()
+		return len(receiver.requests) == 2
+	}, time.Second*5, time.Millisecond*10)
+
+	// the retries are stopped on shutdown, and the payloads are kept as dead letters
+	s.Close()
+	assert.Len(t, letters, 3)
+	assert.Equal(t, 1, letters["a"].Attempts)
+	assert.Contains(t, letters["a"].Error, "shutdown")
+
+	// the payloads are not delivered after shutdown
+	letters = map[string]*models.WebhookDeadLetter{}
+	mWebhook.EXPECT().CreateWebhookDeadLetter(nil, gomock.Any()).Return(nil).Times(2)
+	s.Notify("app.updated", &models.Event{Namespace: "default"})
+	assert.Len(t, receiver.requests, 2)
+}
+
+func TestWebhookService_CheckCertificates(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mWebhook := mockPlugin.NewMockWebhook(mockCtl)
+	mNamespace := mockPlugin.NewMockResource(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	store := persist.NewInMemoryStore(time.Minute)
+	s := newWebhookService(config.Webhook{CertExpiringWithin: 24 * time.Hour}, mWebhook, mNamespace, sSecret, store)
+
+	now := time.Now()
+	genSecret := func(name string, notAfter time.Time) specV1.Secret {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		tpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "test"},
+			NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+		assert.NoError(t, err)
+		cert := &models.Certificate{
+			Name:      name,
+			Namespace: "default",
+			Data:      models.CertificateDataItem{Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))},
+		}
+		return *cert.ToSecret()
+	}
+
+	mNamespace.EXPECT().ListNamespace(gomock.Any()).Return(&models.NamespaceList{
+		Items: []models.Namespace{{Name: "default"}, {Name: "quiet"}},
+	}, nil).Times(1)
+	// the certificates are only checked for the namespaces subscribing to cert.expiring
+	subscribers := &models.WebhookList{Items: []models.Webhook{{Name: "hook", Namespace: "default", Events: []string{models.WebhookEventCertExpiring}}}}
+	mWebhook.EXPECT().ListWebhook(nil, "quiet", gomock.Any()).Return(&models.WebhookList{}, nil).Times(1)
+	mWebhook.EXPECT().ListWebhook(nil, "default", gomock.Any()).Return(subscribers, nil).Times(2)
+	sSecret.EXPECT().List("default", gomock.Any()).Return(&models.SecretList{Items: []specV1.Secret{
+		genSecret("expiring", now.Add(time.Hour)),
+		genSecret("valid", now.Add(30*24*time.Hour)),
+	}}, nil).Times(1)
+	// only the expiring one is delivered, it becomes a dead letter since the subscriber is unreachable
+	var letter *models.WebhookDeadLetter
+	mWebhook.EXPECT().CreateWebhookDeadLetter(nil, gomock.Any()).DoAndReturn(func(_ interface{}, l *models.WebhookDeadLetter) error {
+		letter = l
+		return nil
+	}).Times(1)
+	s.checkCertificates(now)
+	s.wg.Wait()
+	assert.NotNil(t, letter)
+	assert.Equal(t, models.WebhookEventCertExpiring, letter.Event)
+	var payload models.WebhookPayload
+	assert.NoError(t, json.Unmarshal([]byte(letter.Payload), &payload))
+	assert.Equal(t, "expiring", payload.Name)
+
+	// the notified certificates are not notified again until renewed, even by the other replicas sharing the store
+	expiring := genSecret("expiring", now.Add(time.Hour))
+	mNamespace.EXPECT().ListNamespace(gomock.Any()).Return(&models.NamespaceList{Items: []models.Namespace{{Name: "default"}}}, nil).Times(2)
+	mWebhook.EXPECT().ListWebhook(nil, "default", gomock.Any()).Return(subscribers, nil).Times(1)
+	sSecret.EXPECT().List("default", gomock.Any()).Return(&models.SecretList{Items: []specV1.Secret{expiring}}, nil).Times(1)
+	other := newWebhookService(config.Webhook{CertExpiringWithin: 24 * time.Hour}, mWebhook, mNamespace, sSecret, store)
+	other.checkCertificates(now.Add(time.Minute))
+	other.wg.Wait()
+
+	letter = nil
+	mWebhook.EXPECT().ListWebhook(nil, "default", gomock.Any()).Return(subscribers, nil).Times(2)
+	sSecret.EXPECT().List("default", gomock.Any()).Return(&models.SecretList{Items: []specV1.Secret{
+		genSecret("expiring", now.Add(2*time.Hour)),
+	}}, nil).Times(1)
+	mWebhook.EXPECT().CreateWebhookDeadLetter(nil, gomock.Any()).DoAndReturn(func(_ interface{}, l *models.WebhookDeadLetter) error {
+		letter = l
+		return nil
+	}).Times(1)
+	s.checkCertificates(now.Add(2 * time.Minute))
+	s.wg.Wait()
+	assert.NotNil(t, letter)
+}
+
+func TestWebhookService_ClaimCertificateCheck(t *testing.T) {
+	store := persist.NewInMemoryStore(time.Minute)
+	cfg := config.Webhook{CertCheckInterval: time.Hour}
+	s := newWebhookService(cfg, nil, nil, nil, store)
+	other := newWebhookService(cfg, nil, nil, nil, store)
+	now := time.Now().Truncate(time.Hour)
+	// a check is claimed by one of the replicas sharing the store
+	assert.True(t, s.claimCertificateCheck(now))
+	assert.False(t, other.claimCertificateCheck(now.Add(time.Minute)))
+	// and the next one is claimed again
+	assert.True(t, other.claimCertificateCheck(now.Add(time.Hour)))
+}